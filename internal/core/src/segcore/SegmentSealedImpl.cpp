@@ -493,6 +493,22 @@ SegmentSealedImpl::LoadDeletedRecord(const LoadDeletedRecordInfo& info) {
     deleted_record_.push(pks, timestamps);
 }
 
+void
+SegmentSealedImpl::CopyDeletedRecord(const SegmentSealedImpl& source) {
+    auto& record = source.get_deleted_record();
+    auto size = record.size();
+    if (size == 0) {
+        return;
+    }
+    std::vector<PkType> pks(size);
+    std::vector<Timestamp> timestamps(size);
+    for (int64_t i = 0; i < size; ++i) {
+        pks[i] = record.pks()[i];
+        timestamps[i] = record.timestamps()[i];
+    }
+    deleted_record_.push(pks, timestamps.data());
+}
+
 void
 SegmentSealedImpl::AddFieldDataInfoForSealed(
     const LoadFieldDataInfo& field_data_info) {
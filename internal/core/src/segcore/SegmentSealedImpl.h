@@ -46,6 +46,9 @@ class SegmentSealedImpl : public SegmentSealed {
     LoadFieldData(const LoadFieldDataInfo& info) override;
     void
     LoadDeletedRecord(const LoadDeletedRecordInfo& info) override;
+    // copy all the deleted records of source segment
+    void
+    CopyDeletedRecord(const SegmentSealedImpl& source);
     void
     LoadSegmentMeta(
         const milvus::proto::segcore::LoadSegmentMeta& segment_meta) override;
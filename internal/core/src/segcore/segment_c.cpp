@@ -303,6 +303,22 @@ LoadDeletedRecord(CSegmentInterface c_segment,
     }
 }
 
+CStatus
+CopyDeletedRecord(CSegmentInterface c_source, CSegmentInterface c_target) {
+    try {
+        auto source = dynamic_cast<milvus::segcore::SegmentSealedImpl*>(
+            reinterpret_cast<milvus::segcore::SegmentInterface*>(c_source));
+        auto target = dynamic_cast<milvus::segcore::SegmentSealedImpl*>(
+            reinterpret_cast<milvus::segcore::SegmentInterface*>(c_target));
+        AssertInfo(source != nullptr && target != nullptr,
+                   "segment conversion failed");
+        target->CopyDeletedRecord(*source);
+        return milvus::SuccessCStatus();
+    } catch (std::exception& e) {
+        return milvus::FailureCStatus(&e);
+    }
+}
+
 CStatus
 UpdateSealedSegmentIndex(CSegmentInterface c_segment,
                          CLoadIndexInfo c_load_index_info) {
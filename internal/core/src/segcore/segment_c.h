@@ -99,6 +99,9 @@ CStatus
 LoadDeletedRecord(CSegmentInterface c_segment,
                   CLoadDeletedRecordInfo deleted_record_info);
 
+CStatus
+CopyDeletedRecord(CSegmentInterface c_source, CSegmentInterface c_target);
+
 CStatus
 UpdateSealedSegmentIndex(CSegmentInterface c_segment,
                          CLoadIndexInfo c_load_index_info);
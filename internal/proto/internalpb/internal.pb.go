@@ -320,6 +320,7 @@ type GetStatisticsRequest struct {
 	TravelTimestamp      uint64   `protobuf:"varint,5,opt,name=travel_timestamp,json=travelTimestamp,proto3" json:"travel_timestamp,omitempty"`
 	GuaranteeTimestamp   uint64   `protobuf:"varint,6,opt,name=guarantee_timestamp,json=guaranteeTimestamp,proto3" json:"guarantee_timestamp,omitempty"`
 	TimeoutTimestamp     uint64   `protobuf:"varint,7,opt,name=timeout_timestamp,json=timeoutTimestamp,proto3" json:"timeout_timestamp,omitempty"`
+	PartitionKeyFieldID  int64    `protobuf:"varint,8,opt,name=partition_key_fieldID,json=partitionKeyFieldID,proto3" json:"partition_key_fieldID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -399,6 +400,13 @@ func (m *GetStatisticsRequest) GetTimeoutTimestamp() uint64 {
 	return 0
 }
 
+func (m *GetStatisticsRequest) GetPartitionKeyFieldID() int64 {
+	if m != nil {
+		return m.PartitionKeyFieldID
+	}
+	return 0
+}
+
 type GetStatisticsResponse struct {
 	Base *commonpb.MsgBase `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
 	// Contain error_code and reason
@@ -740,20 +748,48 @@ type SearchRequest struct {
 	PartitionIDs []int64           `protobuf:"varint,5,rep,packed,name=partitionIDs,proto3" json:"partitionIDs,omitempty"`
 	Dsl          string            `protobuf:"bytes,6,opt,name=dsl,proto3" json:"dsl,omitempty"`
 	// serialized `PlaceholderGroup`
-	PlaceholderGroup     []byte           `protobuf:"bytes,7,opt,name=placeholder_group,json=placeholderGroup,proto3" json:"placeholder_group,omitempty"`
-	DslType              commonpb.DslType `protobuf:"varint,8,opt,name=dsl_type,json=dslType,proto3,enum=milvus.proto.common.DslType" json:"dsl_type,omitempty"`
-	SerializedExprPlan   []byte           `protobuf:"bytes,9,opt,name=serialized_expr_plan,json=serializedExprPlan,proto3" json:"serialized_expr_plan,omitempty"`
-	OutputFieldsId       []int64          `protobuf:"varint,10,rep,packed,name=output_fields_id,json=outputFieldsId,proto3" json:"output_fields_id,omitempty"`
-	GuaranteeTimestamp   uint64           `protobuf:"varint,12,opt,name=guarantee_timestamp,json=guaranteeTimestamp,proto3" json:"guarantee_timestamp,omitempty"`
-	TimeoutTimestamp     uint64           `protobuf:"varint,13,opt,name=timeout_timestamp,json=timeoutTimestamp,proto3" json:"timeout_timestamp,omitempty"`
-	Nq                   int64            `protobuf:"varint,14,opt,name=nq,proto3" json:"nq,omitempty"`
-	Topk                 int64            `protobuf:"varint,15,opt,name=topk,proto3" json:"topk,omitempty"`
-	MetricType           string           `protobuf:"bytes,16,opt,name=metricType,proto3" json:"metricType,omitempty"`
-	IgnoreGrowing        bool             `protobuf:"varint,17,opt,name=ignoreGrowing,proto3" json:"ignoreGrowing,omitempty"`
-	Username             string           `protobuf:"bytes,18,opt,name=username,proto3" json:"username,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	PlaceholderGroup        []byte                    `protobuf:"bytes,7,opt,name=placeholder_group,json=placeholderGroup,proto3" json:"placeholder_group,omitempty"`
+	DslType                 commonpb.DslType          `protobuf:"varint,8,opt,name=dsl_type,json=dslType,proto3,enum=milvus.proto.common.DslType" json:"dsl_type,omitempty"`
+	SerializedExprPlan      []byte                    `protobuf:"bytes,9,opt,name=serialized_expr_plan,json=serializedExprPlan,proto3" json:"serialized_expr_plan,omitempty"`
+	OutputFieldsId          []int64                   `protobuf:"varint,10,rep,packed,name=output_fields_id,json=outputFieldsId,proto3" json:"output_fields_id,omitempty"`
+	GuaranteeTimestamp      uint64                    `protobuf:"varint,12,opt,name=guarantee_timestamp,json=guaranteeTimestamp,proto3" json:"guarantee_timestamp,omitempty"`
+	TimeoutTimestamp        uint64                    `protobuf:"varint,13,opt,name=timeout_timestamp,json=timeoutTimestamp,proto3" json:"timeout_timestamp,omitempty"`
+	Nq                      int64                     `protobuf:"varint,14,opt,name=nq,proto3" json:"nq,omitempty"`
+	Topk                    int64                     `protobuf:"varint,15,opt,name=topk,proto3" json:"topk,omitempty"`
+	MetricType              string                    `protobuf:"bytes,16,opt,name=metricType,proto3" json:"metricType,omitempty"`
+	IgnoreGrowing           bool                      `protobuf:"varint,17,opt,name=ignoreGrowing,proto3" json:"ignoreGrowing,omitempty"`
+	Username                string                    `protobuf:"bytes,18,opt,name=username,proto3" json:"username,omitempty"`
+	PreferCached            bool                      `protobuf:"varint,19,opt,name=prefer_cached,json=preferCached,proto3" json:"prefer_cached,omitempty"`
+	ReturnSegmentId         bool                      `protobuf:"varint,20,opt,name=return_segment_id,json=returnSegmentId,proto3" json:"return_segment_id,omitempty"`
+	Explain                 bool                      `protobuf:"varint,21,opt,name=explain,proto3" json:"explain,omitempty"`
+	EnableScoreThreshold    bool                      `protobuf:"varint,22,opt,name=enable_score_threshold,json=enableScoreThreshold,proto3" json:"enable_score_threshold,omitempty"`
+	ScoreThreshold          float32                   `protobuf:"fixed32,23,opt,name=score_threshold,json=scoreThreshold,proto3" json:"score_threshold,omitempty"`
+	ReplicaID               int64                     `protobuf:"varint,24,opt,name=replicaID,proto3" json:"replicaID,omitempty"`
+	IteratorToken           []byte                    `protobuf:"bytes,25,opt,name=iterator_token,json=iteratorToken,proto3" json:"iterator_token,omitempty"`
+	IsIterator              bool                      `protobuf:"varint,26,opt,name=is_iterator,json=isIterator,proto3" json:"is_iterator,omitempty"`
+	ExcludePks              *schemapb.IDs             `protobuf:"bytes,27,opt,name=exclude_pks,json=excludePks,proto3" json:"exclude_pks,omitempty"`
+	SkipHook                bool                      `protobuf:"varint,28,opt,name=skip_hook,json=skipHook,proto3" json:"skip_hook,omitempty"`
+	FilterStrategy          string                    `protobuf:"bytes,29,opt,name=filter_strategy,json=filterStrategy,proto3" json:"filter_strategy,omitempty"`
+	ConsistencyLevel        commonpb.ConsistencyLevel `protobuf:"varint,30,opt,name=consistency_level,json=consistencyLevel,proto3,enum=milvus.proto.common.ConsistencyLevel" json:"consistency_level,omitempty"`
+	EnforceConsistencyLevel bool                      `protobuf:"varint,31,opt,name=enforce_consistency_level,json=enforceConsistencyLevel,proto3" json:"enforce_consistency_level,omitempty"`
+	ReduceAlgorithm         string                    `protobuf:"bytes,32,opt,name=reduce_algorithm,json=reduceAlgorithm,proto3" json:"reduce_algorithm,omitempty"`
+	RefineFactor            int64                     `protobuf:"varint,33,opt,name=refine_factor,json=refineFactor,proto3" json:"refine_factor,omitempty"`
+	ReturnRankScore         bool                      `protobuf:"varint,34,opt,name=return_rank_score,json=returnRankScore,proto3" json:"return_rank_score,omitempty"`
+	RerankFieldId           int64                     `protobuf:"varint,35,opt,name=rerank_field_id,json=rerankFieldId,proto3" json:"rerank_field_id,omitempty"`
+	RerankMetricType        string                    `protobuf:"bytes,36,opt,name=rerank_metric_type,json=rerankMetricType,proto3" json:"rerank_metric_type,omitempty"`
+	RerankPlaceholderGroup  []byte                    `protobuf:"bytes,37,opt,name=rerank_placeholder_group,json=rerankPlaceholderGroup,proto3" json:"rerank_placeholder_group,omitempty"`
+	ExactSearch             bool                      `protobuf:"varint,38,opt,name=exact_search,json=exactSearch,proto3" json:"exact_search,omitempty"`
+	ReturnFingerprint       bool                      `protobuf:"varint,39,opt,name=return_fingerprint,json=returnFingerprint,proto3" json:"return_fingerprint,omitempty"`
+	Facet                   *FacetSpec                `protobuf:"bytes,40,opt,name=facet,proto3" json:"facet,omitempty"`
+	PerPartitionTopk        bool                      `protobuf:"varint,41,opt,name=per_partition_topk,json=perPartitionTopk,proto3" json:"per_partition_topk,omitempty"`
+	ScoreStatsOnly          bool                      `protobuf:"varint,42,opt,name=score_stats_only,json=scoreStatsOnly,proto3" json:"score_stats_only,omitempty"`
+	ExplainPk               *schemapb.IDs             `protobuf:"bytes,43,opt,name=explain_pk,json=explainPk,proto3" json:"explain_pk,omitempty"`
+	Overfetch               int64                     `protobuf:"varint,44,opt,name=overfetch,proto3" json:"overfetch,omitempty"`
+	InsertionOrderTiebreak  bool                      `protobuf:"varint,45,opt,name=insertion_order_tiebreak,json=insertionOrderTiebreak,proto3" json:"insertion_order_tiebreak,omitempty"`
+	ReturnSegmentFlushTs    bool                      `protobuf:"varint,46,opt,name=return_segment_flush_ts,json=returnSegmentFlushTs,proto3" json:"return_segment_flush_ts,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}                  `json:"-"`
+	XXX_unrecognized        []byte                    `json:"-"`
+	XXX_sizecache           int32                     `json:"-"`
 }
 
 func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
@@ -900,6 +936,202 @@ func (m *SearchRequest) GetUsername() string {
 	return ""
 }
 
+func (m *SearchRequest) GetPreferCached() bool {
+	if m != nil {
+		return m.PreferCached
+	}
+	return false
+}
+
+func (m *SearchRequest) GetReturnSegmentId() bool {
+	if m != nil {
+		return m.ReturnSegmentId
+	}
+	return false
+}
+
+func (m *SearchRequest) GetExplain() bool {
+	if m != nil {
+		return m.Explain
+	}
+	return false
+}
+
+func (m *SearchRequest) GetEnableScoreThreshold() bool {
+	if m != nil {
+		return m.EnableScoreThreshold
+	}
+	return false
+}
+
+func (m *SearchRequest) GetScoreThreshold() float32 {
+	if m != nil {
+		return m.ScoreThreshold
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetReplicaID() int64 {
+	if m != nil {
+		return m.ReplicaID
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetIteratorToken() []byte {
+	if m != nil {
+		return m.IteratorToken
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetIsIterator() bool {
+	if m != nil {
+		return m.IsIterator
+	}
+	return false
+}
+
+func (m *SearchRequest) GetExcludePks() *schemapb.IDs {
+	if m != nil {
+		return m.ExcludePks
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetSkipHook() bool {
+	if m != nil {
+		return m.SkipHook
+	}
+	return false
+}
+
+func (m *SearchRequest) GetFilterStrategy() string {
+	if m != nil {
+		return m.FilterStrategy
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetConsistencyLevel() commonpb.ConsistencyLevel {
+	if m != nil {
+		return m.ConsistencyLevel
+	}
+	return commonpb.ConsistencyLevel_Strong
+}
+
+func (m *SearchRequest) GetEnforceConsistencyLevel() bool {
+	if m != nil {
+		return m.EnforceConsistencyLevel
+	}
+	return false
+}
+
+func (m *SearchRequest) GetReduceAlgorithm() string {
+	if m != nil {
+		return m.ReduceAlgorithm
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetRefineFactor() int64 {
+	if m != nil {
+		return m.RefineFactor
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetReturnRankScore() bool {
+	if m != nil {
+		return m.ReturnRankScore
+	}
+	return false
+}
+
+func (m *SearchRequest) GetRerankFieldId() int64 {
+	if m != nil {
+		return m.RerankFieldId
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetRerankMetricType() string {
+	if m != nil {
+		return m.RerankMetricType
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetRerankPlaceholderGroup() []byte {
+	if m != nil {
+		return m.RerankPlaceholderGroup
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetExactSearch() bool {
+	if m != nil {
+		return m.ExactSearch
+	}
+	return false
+}
+
+func (m *SearchRequest) GetReturnFingerprint() bool {
+	if m != nil {
+		return m.ReturnFingerprint
+	}
+	return false
+}
+
+func (m *SearchRequest) GetFacet() *FacetSpec {
+	if m != nil {
+		return m.Facet
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetPerPartitionTopk() bool {
+	if m != nil {
+		return m.PerPartitionTopk
+	}
+	return false
+}
+
+func (m *SearchRequest) GetScoreStatsOnly() bool {
+	if m != nil {
+		return m.ScoreStatsOnly
+	}
+	return false
+}
+
+func (m *SearchRequest) GetExplainPk() *schemapb.IDs {
+	if m != nil {
+		return m.ExplainPk
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetOverfetch() int64 {
+	if m != nil {
+		return m.Overfetch
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetInsertionOrderTiebreak() bool {
+	if m != nil {
+		return m.InsertionOrderTiebreak
+	}
+	return false
+}
+
+func (m *SearchRequest) GetReturnSegmentFlushTs() bool {
+	if m != nil {
+		return m.ReturnSegmentFlushTs
+	}
+	return false
+}
+
 type SearchResults struct {
 	Base                     *commonpb.MsgBase `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
 	Status                   *commonpb.Status  `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
@@ -915,10 +1147,29 @@ type SearchResults struct {
 	SlicedNumCount int64  `protobuf:"varint,11,opt,name=sliced_num_count,json=slicedNumCount,proto3" json:"sliced_num_count,omitempty"`
 	SlicedOffset   int64  `protobuf:"varint,12,opt,name=sliced_offset,json=slicedOffset,proto3" json:"sliced_offset,omitempty"`
 	// search request cost
-	CostAggregation      *CostAggregation `protobuf:"bytes,13,opt,name=costAggregation,proto3" json:"costAggregation,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	CostAggregation         *CostAggregation          `protobuf:"bytes,13,opt,name=costAggregation,proto3" json:"costAggregation,omitempty"`
+	IsPartial               bool                      `protobuf:"varint,14,opt,name=is_partial,json=isPartial,proto3" json:"is_partial,omitempty"`
+	IsTopkCapped            bool                      `protobuf:"varint,15,opt,name=is_topk_capped,json=isTopkCapped,proto3" json:"is_topk_capped,omitempty"`
+	ScanDecisions           []*SegmentScanDecision    `protobuf:"bytes,16,rep,name=scan_decisions,json=scanDecisions,proto3" json:"scan_decisions,omitempty"`
+	TimedOutNodes           []int64                   `protobuf:"varint,17,rep,packed,name=timed_out_nodes,json=timedOutNodes,proto3" json:"timed_out_nodes,omitempty"`
+	Topks                   []int64                   `protobuf:"varint,18,rep,packed,name=topks,proto3" json:"topks,omitempty"`
+	Truncated               []bool                    `protobuf:"varint,19,rep,packed,name=truncated,proto3" json:"truncated,omitempty"`
+	NextIteratorToken       []byte                    `protobuf:"bytes,20,opt,name=next_iterator_token,json=nextIteratorToken,proto3" json:"next_iterator_token,omitempty"`
+	SegmentHitDistributions []*SegmentHitDistribution `protobuf:"bytes,21,rep,name=segment_hit_distributions,json=segmentHitDistributions,proto3" json:"segment_hit_distributions,omitempty"`
+	FilterStrategyDecisions []*FilterStrategyDecision `protobuf:"bytes,22,rep,name=filter_strategy_decisions,json=filterStrategyDecisions,proto3" json:"filter_strategy_decisions,omitempty"`
+	ScannedSegments         int64                     `protobuf:"varint,23,opt,name=scanned_segments,json=scannedSegments,proto3" json:"scanned_segments,omitempty"`
+	ScannedRows             int64                     `protobuf:"varint,24,opt,name=scanned_rows,json=scannedRows,proto3" json:"scanned_rows,omitempty"`
+	ScanEstimates           []*SearchScanEstimate     `protobuf:"bytes,25,rep,name=scan_estimates,json=scanEstimates,proto3" json:"scan_estimates,omitempty"`
+	RankScores              []float32                 `protobuf:"fixed32,26,rep,packed,name=rank_scores,json=rankScores,proto3" json:"rank_scores,omitempty"`
+	QueryFingerprint        string                    `protobuf:"bytes,27,opt,name=query_fingerprint,json=queryFingerprint,proto3" json:"query_fingerprint,omitempty"`
+	FacetBuckets            []*FacetBucket            `protobuf:"bytes,28,rep,name=facet_buckets,json=facetBuckets,proto3" json:"facet_buckets,omitempty"`
+	PartitionTopks          []*PartitionTopks         `protobuf:"bytes,29,rep,name=partition_topks,json=partitionTopks,proto3" json:"partition_topks,omitempty"`
+	ScoreStats              []*ScoreStats             `protobuf:"bytes,30,rep,name=score_stats,json=scoreStats,proto3" json:"score_stats,omitempty"`
+	HitExplanations         []*HitExplanation         `protobuf:"bytes,31,rep,name=hit_explanations,json=hitExplanations,proto3" json:"hit_explanations,omitempty"`
+	PrimaryTopks            []int64                   `protobuf:"varint,32,rep,packed,name=primary_topks,json=primaryTopks,proto3" json:"primary_topks,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}                  `json:"-"`
+	XXX_unrecognized        []byte                    `json:"-"`
+	XXX_sizecache           int32                     `json:"-"`
 }
 
 func (m *SearchResults) Reset()         { *m = SearchResults{} }
@@ -1037,6 +1288,139 @@ func (m *SearchResults) GetCostAggregation() *CostAggregation {
 	return nil
 }
 
+func (m *SearchResults) GetIsPartial() bool {
+	if m != nil {
+		return m.IsPartial
+	}
+	return false
+}
+
+func (m *SearchResults) GetIsTopkCapped() bool {
+	if m != nil {
+		return m.IsTopkCapped
+	}
+	return false
+}
+
+func (m *SearchResults) GetScanDecisions() []*SegmentScanDecision {
+	if m != nil {
+		return m.ScanDecisions
+	}
+	return nil
+}
+
+func (m *SearchResults) GetTimedOutNodes() []int64 {
+	if m != nil {
+		return m.TimedOutNodes
+	}
+	return nil
+}
+
+func (m *SearchResults) GetTopks() []int64 {
+	if m != nil {
+		return m.Topks
+	}
+	return nil
+}
+
+func (m *SearchResults) GetTruncated() []bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return nil
+}
+
+func (m *SearchResults) GetNextIteratorToken() []byte {
+	if m != nil {
+		return m.NextIteratorToken
+	}
+	return nil
+}
+
+func (m *SearchResults) GetSegmentHitDistributions() []*SegmentHitDistribution {
+	if m != nil {
+		return m.SegmentHitDistributions
+	}
+	return nil
+}
+
+func (m *SearchResults) GetFilterStrategyDecisions() []*FilterStrategyDecision {
+	if m != nil {
+		return m.FilterStrategyDecisions
+	}
+	return nil
+}
+
+func (m *SearchResults) GetScannedSegments() int64 {
+	if m != nil {
+		return m.ScannedSegments
+	}
+	return 0
+}
+
+func (m *SearchResults) GetScannedRows() int64 {
+	if m != nil {
+		return m.ScannedRows
+	}
+	return 0
+}
+
+func (m *SearchResults) GetScanEstimates() []*SearchScanEstimate {
+	if m != nil {
+		return m.ScanEstimates
+	}
+	return nil
+}
+
+func (m *SearchResults) GetRankScores() []float32 {
+	if m != nil {
+		return m.RankScores
+	}
+	return nil
+}
+
+func (m *SearchResults) GetQueryFingerprint() string {
+	if m != nil {
+		return m.QueryFingerprint
+	}
+	return ""
+}
+
+func (m *SearchResults) GetFacetBuckets() []*FacetBucket {
+	if m != nil {
+		return m.FacetBuckets
+	}
+	return nil
+}
+
+func (m *SearchResults) GetPartitionTopks() []*PartitionTopks {
+	if m != nil {
+		return m.PartitionTopks
+	}
+	return nil
+}
+
+func (m *SearchResults) GetScoreStats() []*ScoreStats {
+	if m != nil {
+		return m.ScoreStats
+	}
+	return nil
+}
+
+func (m *SearchResults) GetHitExplanations() []*HitExplanation {
+	if m != nil {
+		return m.HitExplanations
+	}
+	return nil
+}
+
+func (m *SearchResults) GetPrimaryTopks() []int64 {
+	if m != nil {
+		return m.PrimaryTopks
+	}
+	return nil
+}
+
 type CostAggregation struct {
 	ResponseTime         int64    `protobuf:"varint,1,opt,name=responseTime,proto3" json:"responseTime,omitempty"`
 	ServiceTime          int64    `protobuf:"varint,2,opt,name=serviceTime,proto3" json:"serviceTime,omitempty"`
@@ -1093,25 +1477,39 @@ func (m *CostAggregation) GetTotalNQ() int64 {
 }
 
 type RetrieveRequest struct {
-	Base                         *commonpb.MsgBase `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
-	ReqID                        int64             `protobuf:"varint,2,opt,name=reqID,proto3" json:"reqID,omitempty"`
-	DbID                         int64             `protobuf:"varint,3,opt,name=dbID,proto3" json:"dbID,omitempty"`
-	CollectionID                 int64             `protobuf:"varint,4,opt,name=collectionID,proto3" json:"collectionID,omitempty"`
-	PartitionIDs                 []int64           `protobuf:"varint,5,rep,packed,name=partitionIDs,proto3" json:"partitionIDs,omitempty"`
-	SerializedExprPlan           []byte            `protobuf:"bytes,6,opt,name=serialized_expr_plan,json=serializedExprPlan,proto3" json:"serialized_expr_plan,omitempty"`
-	OutputFieldsId               []int64           `protobuf:"varint,7,rep,packed,name=output_fields_id,json=outputFieldsId,proto3" json:"output_fields_id,omitempty"`
-	MvccTimestamp                uint64            `protobuf:"varint,8,opt,name=mvcc_timestamp,json=mvccTimestamp,proto3" json:"mvcc_timestamp,omitempty"`
-	GuaranteeTimestamp           uint64            `protobuf:"varint,9,opt,name=guarantee_timestamp,json=guaranteeTimestamp,proto3" json:"guarantee_timestamp,omitempty"`
-	TimeoutTimestamp             uint64            `protobuf:"varint,10,opt,name=timeout_timestamp,json=timeoutTimestamp,proto3" json:"timeout_timestamp,omitempty"`
-	Limit                        int64             `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
-	IgnoreGrowing                bool              `protobuf:"varint,12,opt,name=ignoreGrowing,proto3" json:"ignoreGrowing,omitempty"`
-	IsCount                      bool              `protobuf:"varint,13,opt,name=is_count,json=isCount,proto3" json:"is_count,omitempty"`
-	IterationExtensionReduceRate int64             `protobuf:"varint,14,opt,name=iteration_extension_reduce_rate,json=iterationExtensionReduceRate,proto3" json:"iteration_extension_reduce_rate,omitempty"`
-	Username                     string            `protobuf:"bytes,15,opt,name=username,proto3" json:"username,omitempty"`
-	ReduceStopForBest            bool              `protobuf:"varint,16,opt,name=reduce_stop_for_best,json=reduceStopForBest,proto3" json:"reduce_stop_for_best,omitempty"`
-	XXX_NoUnkeyedLiteral         struct{}          `json:"-"`
-	XXX_unrecognized             []byte            `json:"-"`
-	XXX_sizecache                int32             `json:"-"`
+	Base                         *commonpb.MsgBase         `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	ReqID                        int64                     `protobuf:"varint,2,opt,name=reqID,proto3" json:"reqID,omitempty"`
+	DbID                         int64                     `protobuf:"varint,3,opt,name=dbID,proto3" json:"dbID,omitempty"`
+	CollectionID                 int64                     `protobuf:"varint,4,opt,name=collectionID,proto3" json:"collectionID,omitempty"`
+	PartitionIDs                 []int64                   `protobuf:"varint,5,rep,packed,name=partitionIDs,proto3" json:"partitionIDs,omitempty"`
+	SerializedExprPlan           []byte                    `protobuf:"bytes,6,opt,name=serialized_expr_plan,json=serializedExprPlan,proto3" json:"serialized_expr_plan,omitempty"`
+	OutputFieldsId               []int64                   `protobuf:"varint,7,rep,packed,name=output_fields_id,json=outputFieldsId,proto3" json:"output_fields_id,omitempty"`
+	MvccTimestamp                uint64                    `protobuf:"varint,8,opt,name=mvcc_timestamp,json=mvccTimestamp,proto3" json:"mvcc_timestamp,omitempty"`
+	GuaranteeTimestamp           uint64                    `protobuf:"varint,9,opt,name=guarantee_timestamp,json=guaranteeTimestamp,proto3" json:"guarantee_timestamp,omitempty"`
+	TimeoutTimestamp             uint64                    `protobuf:"varint,10,opt,name=timeout_timestamp,json=timeoutTimestamp,proto3" json:"timeout_timestamp,omitempty"`
+	Limit                        int64                     `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	IgnoreGrowing                bool                      `protobuf:"varint,12,opt,name=ignoreGrowing,proto3" json:"ignoreGrowing,omitempty"`
+	IsCount                      bool                      `protobuf:"varint,13,opt,name=is_count,json=isCount,proto3" json:"is_count,omitempty"`
+	IterationExtensionReduceRate int64                     `protobuf:"varint,14,opt,name=iteration_extension_reduce_rate,json=iterationExtensionReduceRate,proto3" json:"iteration_extension_reduce_rate,omitempty"`
+	Username                     string                    `protobuf:"bytes,15,opt,name=username,proto3" json:"username,omitempty"`
+	ReduceStopForBest            bool                      `protobuf:"varint,16,opt,name=reduce_stop_for_best,json=reduceStopForBest,proto3" json:"reduce_stop_for_best,omitempty"`
+	SampleSize                   int64                     `protobuf:"varint,17,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	SampleSeed                   int64                     `protobuf:"varint,18,opt,name=sample_seed,json=sampleSeed,proto3" json:"sample_seed,omitempty"`
+	DistinctCountFieldID         int64                     `protobuf:"varint,19,opt,name=distinct_count_fieldID,json=distinctCountFieldID,proto3" json:"distinct_count_fieldID,omitempty"`
+	Explain                      bool                      `protobuf:"varint,20,opt,name=explain,proto3" json:"explain,omitempty"`
+	CompressStream               bool                      `protobuf:"varint,21,opt,name=compress_stream,json=compressStream,proto3" json:"compress_stream,omitempty"`
+	SortKeys                     []*SortKey                `protobuf:"bytes,22,rep,name=sort_keys,json=sortKeys,proto3" json:"sort_keys,omitempty"`
+	ReplicaID                    int64                     `protobuf:"varint,23,opt,name=replicaID,proto3" json:"replicaID,omitempty"`
+	LenientOutputFields          bool                      `protobuf:"varint,24,opt,name=lenient_output_fields,json=lenientOutputFields,proto3" json:"lenient_output_fields,omitempty"`
+	MaxStreamBytes               int64                     `protobuf:"varint,25,opt,name=max_stream_bytes,json=maxStreamBytes,proto3" json:"max_stream_bytes,omitempty"`
+	ConsistencyLevel             commonpb.ConsistencyLevel `protobuf:"varint,26,opt,name=consistency_level,json=consistencyLevel,proto3,enum=milvus.proto.common.ConsistencyLevel" json:"consistency_level,omitempty"`
+	EnforceConsistencyLevel      bool                      `protobuf:"varint,27,opt,name=enforce_consistency_level,json=enforceConsistencyLevel,proto3" json:"enforce_consistency_level,omitempty"`
+	ResumableStream              bool                      `protobuf:"varint,28,opt,name=resumable_stream,json=resumableStream,proto3" json:"resumable_stream,omitempty"`
+	StreamCursor                 string                    `protobuf:"bytes,29,opt,name=stream_cursor,json=streamCursor,proto3" json:"stream_cursor,omitempty"`
+	ReturnFieldFormats           bool                      `protobuf:"varint,30,opt,name=return_field_formats,json=returnFieldFormats,proto3" json:"return_field_formats,omitempty"`
+	XXX_NoUnkeyedLiteral         struct{}                  `json:"-"`
+	XXX_unrecognized             []byte                    `json:"-"`
+	XXX_sizecache                int32                     `json:"-"`
 }
 
 func (m *RetrieveRequest) Reset()         { *m = RetrieveRequest{} }
@@ -1251,20 +1649,130 @@ func (m *RetrieveRequest) GetReduceStopForBest() bool {
 	return false
 }
 
-type RetrieveResults struct {
-	Base                      *commonpb.MsgBase     `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
-	Status                    *commonpb.Status      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	ReqID                     int64                 `protobuf:"varint,3,opt,name=reqID,proto3" json:"reqID,omitempty"`
-	Ids                       *schemapb.IDs         `protobuf:"bytes,4,opt,name=ids,proto3" json:"ids,omitempty"`
-	FieldsData                []*schemapb.FieldData `protobuf:"bytes,5,rep,name=fields_data,json=fieldsData,proto3" json:"fields_data,omitempty"`
-	SealedSegmentIDsRetrieved []int64               `protobuf:"varint,6,rep,packed,name=sealed_segmentIDs_retrieved,json=sealedSegmentIDsRetrieved,proto3" json:"sealed_segmentIDs_retrieved,omitempty"`
-	ChannelIDsRetrieved       []string              `protobuf:"bytes,7,rep,name=channelIDs_retrieved,json=channelIDsRetrieved,proto3" json:"channelIDs_retrieved,omitempty"`
-	GlobalSealedSegmentIDs    []int64               `protobuf:"varint,8,rep,packed,name=global_sealed_segmentIDs,json=globalSealedSegmentIDs,proto3" json:"global_sealed_segmentIDs,omitempty"`
-	// query request cost
-	CostAggregation      *CostAggregation `protobuf:"bytes,13,opt,name=costAggregation,proto3" json:"costAggregation,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+func (m *RetrieveRequest) GetSampleSize() int64 {
+	if m != nil {
+		return m.SampleSize
+	}
+	return 0
+}
+
+func (m *RetrieveRequest) GetSampleSeed() int64 {
+	if m != nil {
+		return m.SampleSeed
+	}
+	return 0
+}
+
+func (m *RetrieveRequest) GetDistinctCountFieldID() int64 {
+	if m != nil {
+		return m.DistinctCountFieldID
+	}
+	return 0
+}
+
+func (m *RetrieveRequest) GetExplain() bool {
+	if m != nil {
+		return m.Explain
+	}
+	return false
+}
+
+func (m *RetrieveRequest) GetCompressStream() bool {
+	if m != nil {
+		return m.CompressStream
+	}
+	return false
+}
+
+func (m *RetrieveRequest) GetSortKeys() []*SortKey {
+	if m != nil {
+		return m.SortKeys
+	}
+	return nil
+}
+
+func (m *RetrieveRequest) GetReplicaID() int64 {
+	if m != nil {
+		return m.ReplicaID
+	}
+	return 0
+}
+
+func (m *RetrieveRequest) GetLenientOutputFields() bool {
+	if m != nil {
+		return m.LenientOutputFields
+	}
+	return false
+}
+
+func (m *RetrieveRequest) GetMaxStreamBytes() int64 {
+	if m != nil {
+		return m.MaxStreamBytes
+	}
+	return 0
+}
+
+func (m *RetrieveRequest) GetConsistencyLevel() commonpb.ConsistencyLevel {
+	if m != nil {
+		return m.ConsistencyLevel
+	}
+	return commonpb.ConsistencyLevel_Strong
+}
+
+func (m *RetrieveRequest) GetEnforceConsistencyLevel() bool {
+	if m != nil {
+		return m.EnforceConsistencyLevel
+	}
+	return false
+}
+
+func (m *RetrieveRequest) GetResumableStream() bool {
+	if m != nil {
+		return m.ResumableStream
+	}
+	return false
+}
+
+func (m *RetrieveRequest) GetStreamCursor() string {
+	if m != nil {
+		return m.StreamCursor
+	}
+	return ""
+}
+
+func (m *RetrieveRequest) GetReturnFieldFormats() bool {
+	if m != nil {
+		return m.ReturnFieldFormats
+	}
+	return false
+}
+
+type RetrieveResults struct {
+	Base                      *commonpb.MsgBase     `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Status                    *commonpb.Status      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	ReqID                     int64                 `protobuf:"varint,3,opt,name=reqID,proto3" json:"reqID,omitempty"`
+	Ids                       *schemapb.IDs         `protobuf:"bytes,4,opt,name=ids,proto3" json:"ids,omitempty"`
+	FieldsData                []*schemapb.FieldData `protobuf:"bytes,5,rep,name=fields_data,json=fieldsData,proto3" json:"fields_data,omitempty"`
+	SealedSegmentIDsRetrieved []int64               `protobuf:"varint,6,rep,packed,name=sealed_segmentIDs_retrieved,json=sealedSegmentIDsRetrieved,proto3" json:"sealed_segmentIDs_retrieved,omitempty"`
+	ChannelIDsRetrieved       []string              `protobuf:"bytes,7,rep,name=channelIDs_retrieved,json=channelIDsRetrieved,proto3" json:"channelIDs_retrieved,omitempty"`
+	GlobalSealedSegmentIDs    []int64               `protobuf:"varint,8,rep,packed,name=global_sealed_segmentIDs,json=globalSealedSegmentIDs,proto3" json:"global_sealed_segmentIDs,omitempty"`
+	// query request cost
+	CostAggregation       *CostAggregation       `protobuf:"bytes,13,opt,name=costAggregation,proto3" json:"costAggregation,omitempty"`
+	SamplePopulation      int64                  `protobuf:"varint,14,opt,name=sample_population,json=samplePopulation,proto3" json:"sample_population,omitempty"`
+	DistinctSketch        []byte                 `protobuf:"bytes,15,opt,name=distinct_sketch,json=distinctSketch,proto3" json:"distinct_sketch,omitempty"`
+	DistinctCount         int64                  `protobuf:"varint,16,opt,name=distinct_count,json=distinctCount,proto3" json:"distinct_count,omitempty"`
+	DistinctCountError    float64                `protobuf:"fixed64,17,opt,name=distinct_count_error,json=distinctCountError,proto3" json:"distinct_count_error,omitempty"`
+	ScanDecisions         []*SegmentScanDecision `protobuf:"bytes,18,rep,name=scan_decisions,json=scanDecisions,proto3" json:"scan_decisions,omitempty"`
+	CompressedFieldsData  []byte                 `protobuf:"bytes,19,opt,name=compressed_fields_data,json=compressedFieldsData,proto3" json:"compressed_fields_data,omitempty"`
+	CompressType          string                 `protobuf:"bytes,20,opt,name=compress_type,json=compressType,proto3" json:"compress_type,omitempty"`
+	DroppedOutputFieldIDs []int64                `protobuf:"varint,21,rep,packed,name=dropped_output_fieldIDs,json=droppedOutputFieldIDs,proto3" json:"dropped_output_fieldIDs,omitempty"`
+	Warning               string                 `protobuf:"bytes,22,opt,name=warning,proto3" json:"warning,omitempty"`
+	Truncated             bool                   `protobuf:"varint,23,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	StreamCursor          string                 `protobuf:"bytes,24,opt,name=stream_cursor,json=streamCursor,proto3" json:"stream_cursor,omitempty"`
+	FieldFormats          []*FieldFormat         `protobuf:"bytes,25,rep,name=field_formats,json=fieldFormats,proto3" json:"field_formats,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}               `json:"-"`
+	XXX_unrecognized      []byte                 `json:"-"`
+	XXX_sizecache         int32                  `json:"-"`
 }
 
 func (m *RetrieveResults) Reset()         { *m = RetrieveResults{} }
@@ -1355,6 +1863,90 @@ func (m *RetrieveResults) GetCostAggregation() *CostAggregation {
 	return nil
 }
 
+func (m *RetrieveResults) GetSamplePopulation() int64 {
+	if m != nil {
+		return m.SamplePopulation
+	}
+	return 0
+}
+
+func (m *RetrieveResults) GetDistinctSketch() []byte {
+	if m != nil {
+		return m.DistinctSketch
+	}
+	return nil
+}
+
+func (m *RetrieveResults) GetDistinctCount() int64 {
+	if m != nil {
+		return m.DistinctCount
+	}
+	return 0
+}
+
+func (m *RetrieveResults) GetDistinctCountError() float64 {
+	if m != nil {
+		return m.DistinctCountError
+	}
+	return 0
+}
+
+func (m *RetrieveResults) GetScanDecisions() []*SegmentScanDecision {
+	if m != nil {
+		return m.ScanDecisions
+	}
+	return nil
+}
+
+func (m *RetrieveResults) GetCompressedFieldsData() []byte {
+	if m != nil {
+		return m.CompressedFieldsData
+	}
+	return nil
+}
+
+func (m *RetrieveResults) GetCompressType() string {
+	if m != nil {
+		return m.CompressType
+	}
+	return ""
+}
+
+func (m *RetrieveResults) GetDroppedOutputFieldIDs() []int64 {
+	if m != nil {
+		return m.DroppedOutputFieldIDs
+	}
+	return nil
+}
+
+func (m *RetrieveResults) GetWarning() string {
+	if m != nil {
+		return m.Warning
+	}
+	return ""
+}
+
+func (m *RetrieveResults) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
+func (m *RetrieveResults) GetStreamCursor() string {
+	if m != nil {
+		return m.StreamCursor
+	}
+	return ""
+}
+
+func (m *RetrieveResults) GetFieldFormats() []*FieldFormat {
+	if m != nil {
+		return m.FieldFormats
+	}
+	return nil
+}
+
 type LoadIndex struct {
 	Base                 *commonpb.MsgBase        `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
 	SegmentID            int64                    `protobuf:"varint,2,opt,name=segmentID,proto3" json:"segmentID,omitempty"`
@@ -1972,6 +2564,818 @@ func (m *Rate) GetR() float64 {
 	return 0
 }
 
+type SegmentScanDecision struct {
+	SegmentID            int64    `protobuf:"varint,1,opt,name=segmentID,proto3" json:"segmentID,omitempty"`
+	PartitionID          int64    `protobuf:"varint,2,opt,name=partitionID,proto3" json:"partitionID,omitempty"`
+	Pruned               bool     `protobuf:"varint,3,opt,name=pruned,proto3" json:"pruned,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentScanDecision) Reset()         { *m = SegmentScanDecision{} }
+func (m *SegmentScanDecision) String() string { return proto.CompactTextString(m) }
+func (*SegmentScanDecision) ProtoMessage()    {}
+func (*SegmentScanDecision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{28}
+}
+
+func (m *SegmentScanDecision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentScanDecision.Unmarshal(m, b)
+}
+func (m *SegmentScanDecision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentScanDecision.Marshal(b, m, deterministic)
+}
+func (m *SegmentScanDecision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentScanDecision.Merge(m, src)
+}
+func (m *SegmentScanDecision) XXX_Size() int {
+	return xxx_messageInfo_SegmentScanDecision.Size(m)
+}
+func (m *SegmentScanDecision) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentScanDecision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentScanDecision proto.InternalMessageInfo
+
+func (m *SegmentScanDecision) GetSegmentID() int64 {
+	if m != nil {
+		return m.SegmentID
+	}
+	return 0
+}
+
+func (m *SegmentScanDecision) GetPartitionID() int64 {
+	if m != nil {
+		return m.PartitionID
+	}
+	return 0
+}
+
+func (m *SegmentScanDecision) GetPruned() bool {
+	if m != nil {
+		return m.Pruned
+	}
+	return false
+}
+
+func (m *SegmentScanDecision) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type SortKey struct {
+	FieldID              int64    `protobuf:"varint,1,opt,name=fieldID,proto3" json:"fieldID,omitempty"`
+	Descending           bool     `protobuf:"varint,2,opt,name=descending,proto3" json:"descending,omitempty"`
+	NullsFirst           bool     `protobuf:"varint,3,opt,name=nulls_first,json=nullsFirst,proto3" json:"nulls_first,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SortKey) Reset()         { *m = SortKey{} }
+func (m *SortKey) String() string { return proto.CompactTextString(m) }
+func (*SortKey) ProtoMessage()    {}
+func (*SortKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{29}
+}
+
+func (m *SortKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SortKey.Unmarshal(m, b)
+}
+func (m *SortKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SortKey.Marshal(b, m, deterministic)
+}
+func (m *SortKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SortKey.Merge(m, src)
+}
+func (m *SortKey) XXX_Size() int {
+	return xxx_messageInfo_SortKey.Size(m)
+}
+func (m *SortKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_SortKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SortKey proto.InternalMessageInfo
+
+func (m *SortKey) GetFieldID() int64 {
+	if m != nil {
+		return m.FieldID
+	}
+	return 0
+}
+
+func (m *SortKey) GetDescending() bool {
+	if m != nil {
+		return m.Descending
+	}
+	return false
+}
+
+func (m *SortKey) GetNullsFirst() bool {
+	if m != nil {
+		return m.NullsFirst
+	}
+	return false
+}
+
+type SegmentHits struct {
+	SegmentID            int64    `protobuf:"varint,1,opt,name=segmentID,proto3" json:"segmentID,omitempty"`
+	Hits                 int64    `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	FlushTs              uint64   `protobuf:"varint,3,opt,name=flush_ts,json=flushTs,proto3" json:"flush_ts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentHits) Reset()         { *m = SegmentHits{} }
+func (m *SegmentHits) String() string { return proto.CompactTextString(m) }
+func (*SegmentHits) ProtoMessage()    {}
+func (*SegmentHits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{30}
+}
+
+func (m *SegmentHits) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentHits.Unmarshal(m, b)
+}
+func (m *SegmentHits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentHits.Marshal(b, m, deterministic)
+}
+func (m *SegmentHits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentHits.Merge(m, src)
+}
+func (m *SegmentHits) XXX_Size() int {
+	return xxx_messageInfo_SegmentHits.Size(m)
+}
+func (m *SegmentHits) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentHits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentHits proto.InternalMessageInfo
+
+func (m *SegmentHits) GetSegmentID() int64 {
+	if m != nil {
+		return m.SegmentID
+	}
+	return 0
+}
+
+func (m *SegmentHits) GetHits() int64 {
+	if m != nil {
+		return m.Hits
+	}
+	return 0
+}
+
+func (m *SegmentHits) GetFlushTs() uint64 {
+	if m != nil {
+		return m.FlushTs
+	}
+	return 0
+}
+
+type SegmentHitDistribution struct {
+	Segments             []*SegmentHits `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SegmentHitDistribution) Reset()         { *m = SegmentHitDistribution{} }
+func (m *SegmentHitDistribution) String() string { return proto.CompactTextString(m) }
+func (*SegmentHitDistribution) ProtoMessage()    {}
+func (*SegmentHitDistribution) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{31}
+}
+
+func (m *SegmentHitDistribution) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentHitDistribution.Unmarshal(m, b)
+}
+func (m *SegmentHitDistribution) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentHitDistribution.Marshal(b, m, deterministic)
+}
+func (m *SegmentHitDistribution) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentHitDistribution.Merge(m, src)
+}
+func (m *SegmentHitDistribution) XXX_Size() int {
+	return xxx_messageInfo_SegmentHitDistribution.Size(m)
+}
+func (m *SegmentHitDistribution) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentHitDistribution.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentHitDistribution proto.InternalMessageInfo
+
+func (m *SegmentHitDistribution) GetSegments() []*SegmentHits {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+type FilterStrategyDecision struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Strategy             string   `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	EstimatedCardinality int64    `protobuf:"varint,3,opt,name=estimated_cardinality,json=estimatedCardinality,proto3" json:"estimated_cardinality,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	RecallFallback       bool     `protobuf:"varint,5,opt,name=recall_fallback,json=recallFallback,proto3" json:"recall_fallback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilterStrategyDecision) Reset()         { *m = FilterStrategyDecision{} }
+func (m *FilterStrategyDecision) String() string { return proto.CompactTextString(m) }
+func (*FilterStrategyDecision) ProtoMessage()    {}
+func (*FilterStrategyDecision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{32}
+}
+
+func (m *FilterStrategyDecision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterStrategyDecision.Unmarshal(m, b)
+}
+func (m *FilterStrategyDecision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterStrategyDecision.Marshal(b, m, deterministic)
+}
+func (m *FilterStrategyDecision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterStrategyDecision.Merge(m, src)
+}
+func (m *FilterStrategyDecision) XXX_Size() int {
+	return xxx_messageInfo_FilterStrategyDecision.Size(m)
+}
+func (m *FilterStrategyDecision) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterStrategyDecision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterStrategyDecision proto.InternalMessageInfo
+
+func (m *FilterStrategyDecision) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *FilterStrategyDecision) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *FilterStrategyDecision) GetEstimatedCardinality() int64 {
+	if m != nil {
+		return m.EstimatedCardinality
+	}
+	return 0
+}
+
+func (m *FilterStrategyDecision) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *FilterStrategyDecision) GetRecallFallback() bool {
+	if m != nil {
+		return m.RecallFallback
+	}
+	return false
+}
+
+type SearchScanEstimate struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	EstimatedSegmentNum  int64    `protobuf:"varint,2,opt,name=estimated_segment_num,json=estimatedSegmentNum,proto3" json:"estimated_segment_num,omitempty"`
+	ScannedSegments      int64    `protobuf:"varint,3,opt,name=scanned_segments,json=scannedSegments,proto3" json:"scanned_segments,omitempty"`
+	ScannedRows          int64    `protobuf:"varint,4,opt,name=scanned_rows,json=scannedRows,proto3" json:"scanned_rows,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchScanEstimate) Reset()         { *m = SearchScanEstimate{} }
+func (m *SearchScanEstimate) String() string { return proto.CompactTextString(m) }
+func (*SearchScanEstimate) ProtoMessage()    {}
+func (*SearchScanEstimate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{33}
+}
+
+func (m *SearchScanEstimate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchScanEstimate.Unmarshal(m, b)
+}
+func (m *SearchScanEstimate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchScanEstimate.Marshal(b, m, deterministic)
+}
+func (m *SearchScanEstimate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchScanEstimate.Merge(m, src)
+}
+func (m *SearchScanEstimate) XXX_Size() int {
+	return xxx_messageInfo_SearchScanEstimate.Size(m)
+}
+func (m *SearchScanEstimate) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchScanEstimate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchScanEstimate proto.InternalMessageInfo
+
+func (m *SearchScanEstimate) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SearchScanEstimate) GetEstimatedSegmentNum() int64 {
+	if m != nil {
+		return m.EstimatedSegmentNum
+	}
+	return 0
+}
+
+func (m *SearchScanEstimate) GetScannedSegments() int64 {
+	if m != nil {
+		return m.ScannedSegments
+	}
+	return 0
+}
+
+func (m *SearchScanEstimate) GetScannedRows() int64 {
+	if m != nil {
+		return m.ScannedRows
+	}
+	return 0
+}
+
+type FacetSpec struct {
+	FieldID              int64     `protobuf:"varint,1,opt,name=fieldID,proto3" json:"fieldID,omitempty"`
+	Boundaries           []float64 `protobuf:"fixed64,2,rep,packed,name=boundaries,proto3" json:"boundaries,omitempty"`
+	TopHits              int64     `protobuf:"varint,3,opt,name=top_hits,json=topHits,proto3" json:"top_hits,omitempty"`
+	CandidateFactor      int64     `protobuf:"varint,4,opt,name=candidate_factor,json=candidateFactor,proto3" json:"candidate_factor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *FacetSpec) Reset()         { *m = FacetSpec{} }
+func (m *FacetSpec) String() string { return proto.CompactTextString(m) }
+func (*FacetSpec) ProtoMessage()    {}
+func (*FacetSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{34}
+}
+
+func (m *FacetSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetSpec.Unmarshal(m, b)
+}
+func (m *FacetSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetSpec.Marshal(b, m, deterministic)
+}
+func (m *FacetSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetSpec.Merge(m, src)
+}
+func (m *FacetSpec) XXX_Size() int {
+	return xxx_messageInfo_FacetSpec.Size(m)
+}
+func (m *FacetSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetSpec proto.InternalMessageInfo
+
+func (m *FacetSpec) GetFieldID() int64 {
+	if m != nil {
+		return m.FieldID
+	}
+	return 0
+}
+
+func (m *FacetSpec) GetBoundaries() []float64 {
+	if m != nil {
+		return m.Boundaries
+	}
+	return nil
+}
+
+func (m *FacetSpec) GetTopHits() int64 {
+	if m != nil {
+		return m.TopHits
+	}
+	return 0
+}
+
+func (m *FacetSpec) GetCandidateFactor() int64 {
+	if m != nil {
+		return m.CandidateFactor
+	}
+	return 0
+}
+
+type FacetBucket struct {
+	QueryIndex           int64         `protobuf:"varint,1,opt,name=query_index,json=queryIndex,proto3" json:"query_index,omitempty"`
+	Lower                float64       `protobuf:"fixed64,2,opt,name=lower,proto3" json:"lower,omitempty"`
+	Upper                float64       `protobuf:"fixed64,3,opt,name=upper,proto3" json:"upper,omitempty"`
+	Count                int64         `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	TopHitIds            *schemapb.IDs `protobuf:"bytes,5,opt,name=top_hit_ids,json=topHitIds,proto3" json:"top_hit_ids,omitempty"`
+	TopHitScores         []float32     `protobuf:"fixed32,6,rep,packed,name=top_hit_scores,json=topHitScores,proto3" json:"top_hit_scores,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *FacetBucket) Reset()         { *m = FacetBucket{} }
+func (m *FacetBucket) String() string { return proto.CompactTextString(m) }
+func (*FacetBucket) ProtoMessage()    {}
+func (*FacetBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{35}
+}
+
+func (m *FacetBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetBucket.Unmarshal(m, b)
+}
+func (m *FacetBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetBucket.Marshal(b, m, deterministic)
+}
+func (m *FacetBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetBucket.Merge(m, src)
+}
+func (m *FacetBucket) XXX_Size() int {
+	return xxx_messageInfo_FacetBucket.Size(m)
+}
+func (m *FacetBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetBucket proto.InternalMessageInfo
+
+func (m *FacetBucket) GetQueryIndex() int64 {
+	if m != nil {
+		return m.QueryIndex
+	}
+	return 0
+}
+
+func (m *FacetBucket) GetLower() float64 {
+	if m != nil {
+		return m.Lower
+	}
+	return 0
+}
+
+func (m *FacetBucket) GetUpper() float64 {
+	if m != nil {
+		return m.Upper
+	}
+	return 0
+}
+
+func (m *FacetBucket) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *FacetBucket) GetTopHitIds() *schemapb.IDs {
+	if m != nil {
+		return m.TopHitIds
+	}
+	return nil
+}
+
+func (m *FacetBucket) GetTopHitScores() []float32 {
+	if m != nil {
+		return m.TopHitScores
+	}
+	return nil
+}
+
+type PartitionTopks struct {
+	PartitionID          int64    `protobuf:"varint,1,opt,name=partitionID,proto3" json:"partitionID,omitempty"`
+	Topks                []int64  `protobuf:"varint,2,rep,packed,name=topks,proto3" json:"topks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartitionTopks) Reset()         { *m = PartitionTopks{} }
+func (m *PartitionTopks) String() string { return proto.CompactTextString(m) }
+func (*PartitionTopks) ProtoMessage()    {}
+func (*PartitionTopks) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{36}
+}
+
+func (m *PartitionTopks) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartitionTopks.Unmarshal(m, b)
+}
+func (m *PartitionTopks) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartitionTopks.Marshal(b, m, deterministic)
+}
+func (m *PartitionTopks) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartitionTopks.Merge(m, src)
+}
+func (m *PartitionTopks) XXX_Size() int {
+	return xxx_messageInfo_PartitionTopks.Size(m)
+}
+func (m *PartitionTopks) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartitionTopks.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartitionTopks proto.InternalMessageInfo
+
+func (m *PartitionTopks) GetPartitionID() int64 {
+	if m != nil {
+		return m.PartitionID
+	}
+	return 0
+}
+
+func (m *PartitionTopks) GetTopks() []int64 {
+	if m != nil {
+		return m.Topks
+	}
+	return nil
+}
+
+type ScoreStats struct {
+	Count                int64    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Min                  float32  `protobuf:"fixed32,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  float32  `protobuf:"fixed32,3,opt,name=max,proto3" json:"max,omitempty"`
+	Mean                 float32  `protobuf:"fixed32,4,opt,name=mean,proto3" json:"mean,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScoreStats) Reset()         { *m = ScoreStats{} }
+func (m *ScoreStats) String() string { return proto.CompactTextString(m) }
+func (*ScoreStats) ProtoMessage()    {}
+func (*ScoreStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{37}
+}
+
+func (m *ScoreStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScoreStats.Unmarshal(m, b)
+}
+func (m *ScoreStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScoreStats.Marshal(b, m, deterministic)
+}
+func (m *ScoreStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScoreStats.Merge(m, src)
+}
+func (m *ScoreStats) XXX_Size() int {
+	return xxx_messageInfo_ScoreStats.Size(m)
+}
+func (m *ScoreStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScoreStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScoreStats proto.InternalMessageInfo
+
+func (m *ScoreStats) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ScoreStats) GetMin() float32 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *ScoreStats) GetMax() float32 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *ScoreStats) GetMean() float32 {
+	if m != nil {
+		return m.Mean
+	}
+	return 0
+}
+
+type HitExplanation struct {
+	QueryIndex           int64    `protobuf:"varint,1,opt,name=query_index,json=queryIndex,proto3" json:"query_index,omitempty"`
+	Exists               bool     `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	MatchesFilter        bool     `protobuf:"varint,3,opt,name=matches_filter,json=matchesFilter,proto3" json:"matches_filter,omitempty"`
+	ExactScore           float32  `protobuf:"fixed32,4,opt,name=exact_score,json=exactScore,proto3" json:"exact_score,omitempty"`
+	IndexScore           float32  `protobuf:"fixed32,5,opt,name=index_score,json=indexScore,proto3" json:"index_score,omitempty"`
+	SegmentID            int64    `protobuf:"varint,6,opt,name=segmentID,proto3" json:"segmentID,omitempty"`
+	SearchMode           string   `protobuf:"bytes,7,opt,name=search_mode,json=searchMode,proto3" json:"search_mode,omitempty"`
+	InTopk               bool     `protobuf:"varint,8,opt,name=in_topk,json=inTopk,proto3" json:"in_topk,omitempty"`
+	Rank                 int64    `protobuf:"varint,9,opt,name=rank,proto3" json:"rank,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HitExplanation) Reset()         { *m = HitExplanation{} }
+func (m *HitExplanation) String() string { return proto.CompactTextString(m) }
+func (*HitExplanation) ProtoMessage()    {}
+func (*HitExplanation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{38}
+}
+
+func (m *HitExplanation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HitExplanation.Unmarshal(m, b)
+}
+func (m *HitExplanation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HitExplanation.Marshal(b, m, deterministic)
+}
+func (m *HitExplanation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HitExplanation.Merge(m, src)
+}
+func (m *HitExplanation) XXX_Size() int {
+	return xxx_messageInfo_HitExplanation.Size(m)
+}
+func (m *HitExplanation) XXX_DiscardUnknown() {
+	xxx_messageInfo_HitExplanation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HitExplanation proto.InternalMessageInfo
+
+func (m *HitExplanation) GetQueryIndex() int64 {
+	if m != nil {
+		return m.QueryIndex
+	}
+	return 0
+}
+
+func (m *HitExplanation) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+func (m *HitExplanation) GetMatchesFilter() bool {
+	if m != nil {
+		return m.MatchesFilter
+	}
+	return false
+}
+
+func (m *HitExplanation) GetExactScore() float32 {
+	if m != nil {
+		return m.ExactScore
+	}
+	return 0
+}
+
+func (m *HitExplanation) GetIndexScore() float32 {
+	if m != nil {
+		return m.IndexScore
+	}
+	return 0
+}
+
+func (m *HitExplanation) GetSegmentID() int64 {
+	if m != nil {
+		return m.SegmentID
+	}
+	return 0
+}
+
+func (m *HitExplanation) GetSearchMode() string {
+	if m != nil {
+		return m.SearchMode
+	}
+	return ""
+}
+
+func (m *HitExplanation) GetInTopk() bool {
+	if m != nil {
+		return m.InTopk
+	}
+	return false
+}
+
+func (m *HitExplanation) GetRank() int64 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+type FieldFormat struct {
+	FieldID              int64             `protobuf:"varint,1,opt,name=fieldID,proto3" json:"fieldID,omitempty"`
+	Name                 string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DataType             schemapb.DataType `protobuf:"varint,3,opt,name=data_type,json=dataType,proto3,enum=milvus.proto.schema.DataType" json:"data_type,omitempty"`
+	Dim                  int64             `protobuf:"varint,4,opt,name=dim,proto3" json:"dim,omitempty"`
+	ElementType          schemapb.DataType `protobuf:"varint,5,opt,name=element_type,json=elementType,proto3,enum=milvus.proto.schema.DataType" json:"element_type,omitempty"`
+	MaxLength            int64             `protobuf:"varint,6,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`
+	MaxCapacity          int64             `protobuf:"varint,7,opt,name=max_capacity,json=maxCapacity,proto3" json:"max_capacity,omitempty"`
+	Nullable             bool              `protobuf:"varint,8,opt,name=nullable,proto3" json:"nullable,omitempty"`
+	IsDynamic            bool              `protobuf:"varint,9,opt,name=is_dynamic,json=isDynamic,proto3" json:"is_dynamic,omitempty"`
+	IsPrimaryKey         bool              `protobuf:"varint,10,opt,name=is_primary_key,json=isPrimaryKey,proto3" json:"is_primary_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *FieldFormat) Reset()         { *m = FieldFormat{} }
+func (m *FieldFormat) String() string { return proto.CompactTextString(m) }
+func (*FieldFormat) ProtoMessage()    {}
+func (*FieldFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{39}
+}
+
+func (m *FieldFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FieldFormat.Unmarshal(m, b)
+}
+func (m *FieldFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FieldFormat.Marshal(b, m, deterministic)
+}
+func (m *FieldFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldFormat.Merge(m, src)
+}
+func (m *FieldFormat) XXX_Size() int {
+	return xxx_messageInfo_FieldFormat.Size(m)
+}
+func (m *FieldFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldFormat proto.InternalMessageInfo
+
+func (m *FieldFormat) GetFieldID() int64 {
+	if m != nil {
+		return m.FieldID
+	}
+	return 0
+}
+
+func (m *FieldFormat) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FieldFormat) GetDataType() schemapb.DataType {
+	if m != nil {
+		return m.DataType
+	}
+	return schemapb.DataType_None
+}
+
+func (m *FieldFormat) GetDim() int64 {
+	if m != nil {
+		return m.Dim
+	}
+	return 0
+}
+
+func (m *FieldFormat) GetElementType() schemapb.DataType {
+	if m != nil {
+		return m.ElementType
+	}
+	return schemapb.DataType_None
+}
+
+func (m *FieldFormat) GetMaxLength() int64 {
+	if m != nil {
+		return m.MaxLength
+	}
+	return 0
+}
+
+func (m *FieldFormat) GetMaxCapacity() int64 {
+	if m != nil {
+		return m.MaxCapacity
+	}
+	return 0
+}
+
+func (m *FieldFormat) GetNullable() bool {
+	if m != nil {
+		return m.Nullable
+	}
+	return false
+}
+
+func (m *FieldFormat) GetIsDynamic() bool {
+	if m != nil {
+		return m.IsDynamic
+	}
+	return false
+}
+
+func (m *FieldFormat) GetIsPrimaryKey() bool {
+	if m != nil {
+		return m.IsPrimaryKey
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("milvus.proto.internal.RateType", RateType_name, RateType_value)
 	proto.RegisterType((*GetTimeTickChannelRequest)(nil), "milvus.proto.internal.GetTimeTickChannelRequest")
@@ -2002,133 +3406,271 @@ func init() {
 	proto.RegisterType((*ShowConfigurationsRequest)(nil), "milvus.proto.internal.ShowConfigurationsRequest")
 	proto.RegisterType((*ShowConfigurationsResponse)(nil), "milvus.proto.internal.ShowConfigurationsResponse")
 	proto.RegisterType((*Rate)(nil), "milvus.proto.internal.Rate")
+	proto.RegisterType((*SegmentScanDecision)(nil), "milvus.proto.internal.SegmentScanDecision")
+	proto.RegisterType((*SortKey)(nil), "milvus.proto.internal.SortKey")
+	proto.RegisterType((*SegmentHits)(nil), "milvus.proto.internal.SegmentHits")
+	proto.RegisterType((*SegmentHitDistribution)(nil), "milvus.proto.internal.SegmentHitDistribution")
+	proto.RegisterType((*FilterStrategyDecision)(nil), "milvus.proto.internal.FilterStrategyDecision")
+	proto.RegisterType((*SearchScanEstimate)(nil), "milvus.proto.internal.SearchScanEstimate")
+	proto.RegisterType((*FacetSpec)(nil), "milvus.proto.internal.FacetSpec")
+	proto.RegisterType((*FacetBucket)(nil), "milvus.proto.internal.FacetBucket")
+	proto.RegisterType((*PartitionTopks)(nil), "milvus.proto.internal.PartitionTopks")
+	proto.RegisterType((*ScoreStats)(nil), "milvus.proto.internal.ScoreStats")
+	proto.RegisterType((*HitExplanation)(nil), "milvus.proto.internal.HitExplanation")
+	proto.RegisterType((*FieldFormat)(nil), "milvus.proto.internal.FieldFormat")
 }
 
 func init() { proto.RegisterFile("internal.proto", fileDescriptor_41f4a519b878ee3b) }
 
 var fileDescriptor_41f4a519b878ee3b = []byte{
-	// 1966 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x58, 0xcb, 0x6e, 0x1c, 0xb9,
-	0x15, 0x4d, 0xf5, 0xbb, 0x6f, 0xb7, 0x5a, 0x2d, 0x5a, 0x76, 0xca, 0x8f, 0x19, 0x6b, 0x2a, 0x2f,
-	0x65, 0x82, 0xb1, 0x26, 0x1a, 0xcc, 0x38, 0x8b, 0x20, 0x81, 0xa5, 0xb2, 0x85, 0xc6, 0xb4, 0x1d,
-	0xb9, 0xda, 0x19, 0x20, 0xd9, 0x14, 0xd8, 0x55, 0x54, 0x8b, 0x71, 0x55, 0xb1, 0x44, 0xb2, 0x64,
-	0xcb, 0xbb, 0x00, 0xd9, 0x05, 0xc8, 0x2e, 0x9b, 0x00, 0xc9, 0x1f, 0x64, 0x3d, 0xc8, 0x2a, 0x7f,
-	0x90, 0x1f, 0xc9, 0x1f, 0xcc, 0x2a, 0xe0, 0xa3, 0xfa, 0xa5, 0x96, 0x22, 0xcb, 0x79, 0xcc, 0xec,
-	0x8a, 0xe7, 0x1e, 0x5e, 0x92, 0x97, 0x97, 0x87, 0x97, 0x05, 0x3d, 0x9a, 0x49, 0xc2, 0x33, 0x9c,
-	0x3c, 0xc8, 0x39, 0x93, 0x0c, 0xdd, 0x4c, 0x69, 0x72, 0x5a, 0x08, 0xd3, 0x7a, 0x50, 0x1a, 0xef,
-	0x74, 0x23, 0x96, 0xa6, 0x2c, 0x33, 0xf0, 0x9d, 0xae, 0x88, 0x8e, 0x49, 0x8a, 0x4d, 0xcb, 0xbb,
-	0x0b, 0xb7, 0x0f, 0x88, 0x7c, 0x41, 0x53, 0xf2, 0x82, 0x46, 0x2f, 0xf7, 0x8f, 0x71, 0x96, 0x91,
-	0x24, 0x20, 0x27, 0x05, 0x11, 0xd2, 0x7b, 0x0f, 0xee, 0x1e, 0x10, 0x39, 0x92, 0x58, 0x52, 0x21,
-	0x69, 0x24, 0x96, 0xcc, 0x37, 0xe1, 0xc6, 0x01, 0x91, 0x7e, 0xbc, 0x04, 0x7f, 0x01, 0xad, 0x67,
-	0x2c, 0x26, 0x83, 0xec, 0x88, 0xa1, 0xcf, 0xa0, 0x89, 0xe3, 0x98, 0x13, 0x21, 0x5c, 0x67, 0xcb,
-	0xd9, 0xee, 0xec, 0xde, 0x7b, 0xb0, 0x30, 0x47, 0x3b, 0xb3, 0x47, 0x86, 0x13, 0x94, 0x64, 0x84,
-	0xa0, 0xc6, 0x59, 0x42, 0xdc, 0xca, 0x96, 0xb3, 0xdd, 0x0e, 0xf4, 0xb7, 0xf7, 0x1b, 0x80, 0x41,
-	0x46, 0xe5, 0x21, 0xe6, 0x38, 0x15, 0xe8, 0x16, 0x34, 0x32, 0x35, 0x8a, 0xaf, 0x1d, 0x57, 0x03,
-	0xdb, 0x42, 0x3e, 0x74, 0x85, 0xc4, 0x5c, 0x86, 0xb9, 0xe6, 0xb9, 0x95, 0xad, 0xea, 0x76, 0x67,
-	0xf7, 0x83, 0x95, 0xc3, 0x7e, 0x4e, 0xce, 0xbe, 0xc0, 0x49, 0x41, 0x0e, 0x31, 0xe5, 0x41, 0x47,
-	0x77, 0x33, 0xde, 0xbd, 0x5f, 0x01, 0x8c, 0x24, 0xa7, 0xd9, 0x64, 0x48, 0x85, 0x54, 0x63, 0x9d,
-	0x2a, 0x9e, 0x5a, 0x44, 0x75, 0xbb, 0x1d, 0xd8, 0x16, 0xfa, 0x04, 0x1a, 0x42, 0x62, 0x59, 0x08,
-	0x3d, 0xcf, 0xce, 0xee, 0xdd, 0x95, 0xa3, 0x8c, 0x34, 0x25, 0xb0, 0x54, 0xef, 0xaf, 0x15, 0xd8,
-	0x5c, 0x88, 0xaa, 0x8d, 0x1b, 0xfa, 0x18, 0x6a, 0x63, 0x2c, 0xc8, 0xa5, 0x81, 0x7a, 0x2a, 0x26,
-	0x7b, 0x58, 0x90, 0x40, 0x33, 0x55, 0x94, 0xe2, 0xf1, 0xc0, 0xd7, 0xa3, 0x57, 0x03, 0xfd, 0x8d,
-	0x3c, 0xe8, 0x46, 0x2c, 0x49, 0x48, 0x24, 0x29, 0xcb, 0x06, 0xbe, 0x5b, 0xd5, 0xb6, 0x05, 0x4c,
-	0x71, 0x72, 0xcc, 0x25, 0x35, 0x4d, 0xe1, 0xd6, 0xb6, 0xaa, 0x8a, 0x33, 0x8f, 0xa1, 0x1f, 0x42,
-	0x5f, 0x72, 0x7c, 0x4a, 0x92, 0x50, 0xd2, 0x94, 0x08, 0x89, 0xd3, 0xdc, 0xad, 0x6f, 0x39, 0xdb,
-	0xb5, 0x60, 0xdd, 0xe0, 0x2f, 0x4a, 0x18, 0xed, 0xc0, 0x8d, 0x49, 0x81, 0x39, 0xce, 0x24, 0x21,
-	0x73, 0xec, 0x86, 0x66, 0xa3, 0xa9, 0x69, 0xd6, 0xe1, 0x47, 0xb0, 0xa1, 0x68, 0xac, 0x90, 0x73,
-	0xf4, 0xa6, 0xa6, 0xf7, 0xad, 0x61, 0x4a, 0xf6, 0xbe, 0x74, 0xe0, 0xe6, 0x52, 0xbc, 0x44, 0xce,
-	0x32, 0x41, 0xae, 0x11, 0xb0, 0xeb, 0x6c, 0x18, 0x7a, 0x08, 0x75, 0xf5, 0x25, 0xdc, 0xea, 0x55,
-	0x53, 0xc9, 0xf0, 0xbd, 0xbf, 0x38, 0x80, 0xf6, 0x39, 0xc1, 0x92, 0x3c, 0x4a, 0x28, 0x7e, 0x87,
-	0x7d, 0xfe, 0x36, 0x34, 0xe3, 0x71, 0x98, 0xe1, 0xb4, 0x3c, 0x10, 0x8d, 0x78, 0xfc, 0x0c, 0xa7,
-	0x04, 0xfd, 0x00, 0xd6, 0x67, 0x1b, 0x6b, 0x08, 0x55, 0x4d, 0xe8, 0xcd, 0x60, 0x4d, 0xdc, 0x84,
-	0x3a, 0x56, 0x73, 0x70, 0x6b, 0xda, 0x6c, 0x1a, 0x9e, 0x80, 0xbe, 0xcf, 0x59, 0xfe, 0xdf, 0x9a,
-	0xdd, 0x74, 0xd0, 0xea, 0xfc, 0xa0, 0x7f, 0x76, 0x60, 0xe3, 0x51, 0x22, 0x09, 0xff, 0x9a, 0x06,
-	0xe5, 0xef, 0x95, 0x72, 0xd7, 0x06, 0x59, 0x4c, 0x5e, 0xff, 0x3f, 0x27, 0xf8, 0x1e, 0xc0, 0x11,
-	0x25, 0x49, 0x6c, 0x38, 0x66, 0x96, 0x6d, 0x8d, 0x68, 0x73, 0x79, 0xfc, 0xeb, 0x97, 0x1c, 0xff,
-	0xc6, 0x8a, 0xe3, 0xef, 0x42, 0x53, 0x3b, 0x19, 0xf8, 0xfa, 0xd0, 0x55, 0x83, 0xb2, 0xa9, 0xc4,
-	0x93, 0xbc, 0x96, 0x1c, 0x97, 0xe2, 0xd9, 0xba, 0xb2, 0x78, 0xea, 0x6e, 0x56, 0x3c, 0xff, 0x59,
-	0x83, 0xb5, 0x11, 0xc1, 0x3c, 0x3a, 0xbe, 0x7e, 0xf0, 0x36, 0xa1, 0xce, 0xc9, 0xc9, 0x54, 0xdb,
-	0x4c, 0x63, 0xba, 0xe2, 0xea, 0x25, 0x2b, 0xae, 0x5d, 0x41, 0xf0, 0xea, 0x2b, 0x04, 0xaf, 0x0f,
-	0xd5, 0x58, 0x24, 0x3a, 0x60, 0xed, 0x40, 0x7d, 0x2a, 0x99, 0xca, 0x13, 0x1c, 0x91, 0x63, 0x96,
-	0xc4, 0x84, 0x87, 0x13, 0xce, 0x0a, 0x23, 0x53, 0xdd, 0xa0, 0x3f, 0x67, 0x38, 0x50, 0x38, 0x7a,
-	0x08, 0xad, 0x58, 0x24, 0xa1, 0x3c, 0xcb, 0x89, 0xdb, 0xda, 0x72, 0xb6, 0x7b, 0x17, 0x2c, 0xd3,
-	0x17, 0xc9, 0x8b, 0xb3, 0x9c, 0x04, 0xcd, 0xd8, 0x7c, 0xa0, 0x8f, 0x61, 0x53, 0x10, 0x4e, 0x71,
-	0x42, 0xdf, 0x90, 0x38, 0x24, 0xaf, 0x73, 0x1e, 0xe6, 0x09, 0xce, 0xdc, 0xb6, 0x1e, 0x08, 0xcd,
-	0x6c, 0x8f, 0x5f, 0xe7, 0xfc, 0x30, 0xc1, 0x19, 0xda, 0x86, 0x3e, 0x2b, 0x64, 0x5e, 0xc8, 0x50,
-	0xef, 0x9b, 0x08, 0x69, 0xec, 0x82, 0x5e, 0x51, 0xcf, 0xe0, 0x4f, 0x34, 0x3c, 0x88, 0x2f, 0x52,
-	0xe6, 0xee, 0xdb, 0x29, 0xf3, 0xda, 0x6a, 0x65, 0x46, 0x3d, 0xa8, 0x64, 0x27, 0x6e, 0x4f, 0xc7,
-	0xbb, 0x92, 0x9d, 0xa8, 0xdd, 0x91, 0x2c, 0x7f, 0xe9, 0xae, 0x9b, 0xdd, 0x51, 0xdf, 0xe8, 0x7d,
-	0x80, 0x94, 0x48, 0x4e, 0x23, 0xb5, 0x56, 0xb7, 0xaf, 0x83, 0x3b, 0x87, 0xa0, 0xef, 0xc2, 0x1a,
-	0x9d, 0x64, 0x8c, 0x93, 0x03, 0xce, 0x5e, 0xd1, 0x6c, 0xe2, 0x6e, 0x6c, 0x39, 0xdb, 0xad, 0x60,
-	0x11, 0x44, 0x77, 0xa0, 0x55, 0x08, 0x55, 0xcc, 0xa4, 0xc4, 0x45, 0xda, 0xc7, 0xb4, 0xed, 0xfd,
-	0x63, 0x2e, 0xdb, 0x44, 0x91, 0x48, 0xf1, 0xbf, 0xba, 0x17, 0xa6, 0x29, 0x5a, 0x9d, 0x4f, 0xd1,
-	0xfb, 0xd0, 0x31, 0xcb, 0x33, 0xa9, 0x50, 0x3b, 0xb7, 0xe2, 0xfb, 0xd0, 0xc9, 0x8a, 0x34, 0x3c,
-	0x29, 0x08, 0xa7, 0x44, 0xd8, 0xc3, 0x0b, 0x59, 0x91, 0x3e, 0x37, 0x08, 0xba, 0x01, 0x75, 0xc9,
-	0xf2, 0xf0, 0xa5, 0x3d, 0xbb, 0x2a, 0x8e, 0x9f, 0xa3, 0x9f, 0xc2, 0x1d, 0x41, 0x70, 0x42, 0xe2,
-	0x50, 0x90, 0x49, 0x4a, 0x32, 0x39, 0xf0, 0x45, 0x28, 0xf4, 0xb2, 0x49, 0xec, 0x36, 0xf5, 0xee,
-	0xbb, 0x86, 0x31, 0x9a, 0x12, 0x46, 0xd6, 0xae, 0xf2, 0x20, 0x32, 0x45, 0xda, 0x42, 0xb7, 0x96,
-	0xae, 0x66, 0xd0, 0xcc, 0x34, 0xed, 0xf0, 0x13, 0x70, 0x27, 0x09, 0x1b, 0xe3, 0x24, 0x3c, 0x37,
-	0xaa, 0xdb, 0xd6, 0x83, 0xdd, 0x32, 0xf6, 0xd1, 0xd2, 0x90, 0x6a, 0x79, 0x22, 0xa1, 0x11, 0x89,
-	0xc3, 0x71, 0xc2, 0xc6, 0x2e, 0xe8, 0x2c, 0x06, 0x03, 0xed, 0x25, 0x6c, 0xac, 0xb2, 0xd7, 0x12,
-	0x54, 0x18, 0x22, 0x56, 0x64, 0xd2, 0xed, 0xe8, 0x95, 0xf6, 0x0c, 0xfe, 0xac, 0x48, 0xf7, 0x15,
-	0x8a, 0xbe, 0x03, 0x6b, 0x96, 0xc9, 0x8e, 0x8e, 0x04, 0x91, 0x3a, 0x6f, 0xab, 0x41, 0xd7, 0x80,
-	0xbf, 0xd0, 0x18, 0x3a, 0x54, 0x62, 0x2a, 0xe4, 0xa3, 0xc9, 0x84, 0x93, 0x09, 0x56, 0x87, 0x59,
-	0xe7, 0x6b, 0x67, 0xf7, 0xfb, 0x0f, 0x56, 0x56, 0xc3, 0x0f, 0xf6, 0x17, 0xd9, 0xc1, 0x72, 0x77,
-	0xef, 0x04, 0xd6, 0x97, 0x38, 0x4a, 0x3f, 0xb8, 0xad, 0x3a, 0x54, 0xfa, 0xdb, 0x92, 0x73, 0x01,
-	0x43, 0x5b, 0xd0, 0x11, 0x84, 0x9f, 0xd2, 0xc8, 0x50, 0x8c, 0x6e, 0xcd, 0x43, 0x4a, 0x77, 0x25,
-	0x93, 0x38, 0x79, 0xf6, 0xdc, 0xa6, 0x4c, 0xd9, 0xf4, 0x7e, 0x5b, 0x87, 0xf5, 0x40, 0xa5, 0x08,
-	0x39, 0x25, 0xdf, 0x24, 0xcd, 0xbc, 0x48, 0xbb, 0x1a, 0x6f, 0xa5, 0x5d, 0xcd, 0x95, 0xda, 0xf5,
-	0x3d, 0xe8, 0xa5, 0xa7, 0x51, 0x34, 0xa7, 0x43, 0x2d, 0xad, 0x43, 0x6b, 0x0a, 0xfd, 0xb7, 0xc5,
-	0x67, 0xfb, 0xed, 0x24, 0x0e, 0x2e, 0x90, 0xb8, 0x4d, 0xa8, 0x27, 0x34, 0xa5, 0x65, 0x86, 0x9a,
-	0xc6, 0x79, 0xd1, 0xea, 0xae, 0x12, 0xad, 0xdb, 0xd0, 0xa2, 0xc2, 0x26, 0xf8, 0x9a, 0x26, 0x34,
-	0xa9, 0x30, 0x99, 0xfd, 0x18, 0xee, 0x53, 0x49, 0xb8, 0x4e, 0xae, 0x90, 0xbc, 0x96, 0x24, 0x13,
-	0xea, 0x8b, 0x93, 0xb8, 0x88, 0x48, 0xc8, 0xb1, 0x24, 0x56, 0x56, 0xef, 0x4d, 0x69, 0x8f, 0x4b,
-	0x56, 0xa0, 0x49, 0x01, 0x96, 0x64, 0x41, 0x16, 0xd7, 0x17, 0x65, 0x11, 0xed, 0xc0, 0xa6, 0x75,
-	0x27, 0x94, 0x9a, 0x1c, 0x31, 0x1e, 0x8e, 0x89, 0x90, 0x5a, 0x82, 0x5b, 0xc1, 0x86, 0xb1, 0x8d,
-	0x24, 0xcb, 0x9f, 0x30, 0xbe, 0xa7, 0x9e, 0x6d, 0x5f, 0x55, 0xe7, 0x73, 0xf0, 0x6b, 0xa0, 0xa4,
-	0x1f, 0x42, 0x95, 0xc6, 0xa6, 0x38, 0xeb, 0xec, 0xba, 0x8b, 0x7e, 0xec, 0x1b, 0x76, 0xe0, 0x8b,
-	0x40, 0x91, 0xd0, 0xcf, 0xa1, 0x63, 0xf3, 0x29, 0xc6, 0x12, 0xeb, 0x5c, 0xed, 0xec, 0xbe, 0xbf,
-	0xb2, 0x8f, 0x4e, 0x30, 0x1f, 0x4b, 0x1c, 0x98, 0xe2, 0x4a, 0xa8, 0x6f, 0xf4, 0x33, 0xb8, 0x7b,
-	0x5e, 0x5f, 0xb9, 0x0d, 0x47, 0xec, 0x36, 0x74, 0x8a, 0xde, 0x5e, 0x16, 0xd8, 0x32, 0x5e, 0x31,
-	0xfa, 0x31, 0x6c, 0xce, 0x29, 0xec, 0xac, 0x63, 0x53, 0x4b, 0xec, 0x9c, 0xfa, 0xce, 0xba, 0x5c,
-	0xa6, 0xb1, 0xad, 0x4b, 0x35, 0xf6, 0x3f, 0xaf, 0x79, 0x5f, 0x39, 0xd0, 0x1e, 0x32, 0x1c, 0xeb,
-	0x92, 0xf7, 0x1a, 0xdb, 0x7e, 0x0f, 0xda, 0xd3, 0xd9, 0x5b, 0xf9, 0x99, 0x01, 0xca, 0x3a, 0xad,
-	0x5a, 0x6d, 0xa9, 0x3b, 0x57, 0xc6, 0xce, 0x95, 0xa3, 0xb5, 0xc5, 0x72, 0xf4, 0x3e, 0x74, 0xa8,
-	0x9a, 0x50, 0x98, 0x63, 0x79, 0x6c, 0x14, 0xa8, 0x1d, 0x80, 0x86, 0x0e, 0x15, 0xa2, 0xea, 0xd5,
-	0x92, 0xa0, 0xeb, 0xd5, 0xc6, 0x95, 0xeb, 0x55, 0xeb, 0x44, 0xd7, 0xab, 0xbf, 0x73, 0x00, 0xf4,
-	0xc2, 0x55, 0x5a, 0x9e, 0x77, 0xea, 0x5c, 0xc7, 0xa9, 0x92, 0x46, 0x75, 0xbf, 0x71, 0x92, 0x60,
-	0x39, 0xdb, 0x5b, 0x61, 0x83, 0x83, 0xb2, 0x22, 0x0d, 0x8c, 0xc9, 0xee, 0xab, 0xf0, 0xfe, 0xe0,
-	0x00, 0xe8, 0xe4, 0x34, 0xd3, 0x58, 0xd6, 0x68, 0xe7, 0xf2, 0x4a, 0xbe, 0xb2, 0x18, 0xba, 0xbd,
-	0x32, 0x74, 0x97, 0x3c, 0x5d, 0xa7, 0xe9, 0x31, 0x5b, 0xbc, 0x8d, 0xae, 0xfe, 0xf6, 0xfe, 0xe8,
-	0x40, 0xd7, 0xce, 0xce, 0x4c, 0x69, 0x61, 0x97, 0x9d, 0xe5, 0x5d, 0xd6, 0x95, 0x4f, 0xca, 0xf8,
-	0x59, 0x28, 0xe8, 0x9b, 0xf2, 0x02, 0x04, 0x03, 0x8d, 0xe8, 0x1b, 0xa2, 0x04, 0x51, 0x87, 0x84,
-	0xbd, 0x12, 0xe5, 0x05, 0xa8, 0xc2, 0xc0, 0x5e, 0x09, 0x25, 0xca, 0x9c, 0x44, 0x24, 0x93, 0xc9,
-	0x59, 0x98, 0xb2, 0x98, 0x1e, 0x51, 0x12, 0xeb, 0x6c, 0x68, 0x05, 0xfd, 0xd2, 0xf0, 0xd4, 0xe2,
-	0xde, 0x97, 0xea, 0x5d, 0x6d, 0x0e, 0x54, 0xf9, 0xe3, 0xea, 0xa9, 0x98, 0x5c, 0x23, 0x6b, 0x55,
-	0x88, 0x8d, 0x1f, 0x95, 0x88, 0xe6, 0x5f, 0x51, 0x3b, 0x58, 0xc0, 0x54, 0x01, 0x3b, 0xbd, 0x26,
-	0x4c, 0x1c, 0x6b, 0xc1, 0x1c, 0xa2, 0x66, 0x1e, 0x93, 0x23, 0x5c, 0x24, 0xf3, 0xd7, 0x49, 0xcd,
-	0x5c, 0x27, 0xd6, 0xb0, 0xf0, 0x2f, 0xa3, 0xb7, 0xcf, 0x49, 0x4c, 0x32, 0x49, 0x71, 0xa2, 0xff,
-	0x90, 0xcd, 0x6b, 0xb8, 0xb3, 0xa4, 0xe1, 0x1f, 0x01, 0x22, 0x59, 0xc4, 0xcf, 0x72, 0x95, 0x41,
-	0x39, 0x16, 0xe2, 0x15, 0xe3, 0xb1, 0x7d, 0x4c, 0x6e, 0x4c, 0x2d, 0x87, 0xd6, 0x80, 0x6e, 0x41,
-	0x43, 0x92, 0x0c, 0x67, 0xd2, 0x9e, 0x31, 0xdb, 0xb2, 0x17, 0x91, 0x28, 0x72, 0xc2, 0x6d, 0x4c,
-	0x9b, 0x54, 0x8c, 0x54, 0x53, 0x3d, 0x45, 0xc5, 0x31, 0xde, 0xfd, 0xf4, 0xb3, 0x99, 0xfb, 0xba,
-	0x79, 0x8a, 0x1a, 0xb8, 0xf4, 0xed, 0x3d, 0x86, 0x8d, 0x21, 0x15, 0xf2, 0x90, 0x25, 0x34, 0x3a,
-	0xbb, 0x76, 0x89, 0xe2, 0xfd, 0xde, 0x01, 0x34, 0xef, 0xc7, 0xfe, 0xc9, 0x99, 0xdd, 0x1a, 0xce,
-	0xd5, 0x6f, 0x8d, 0x0f, 0xa0, 0x9b, 0x6b, 0x37, 0x21, 0xcd, 0x8e, 0x58, 0xb9, 0x7b, 0x1d, 0x83,
-	0xa9, 0xd8, 0x0a, 0xf5, 0x80, 0x56, 0xc1, 0x0c, 0x39, 0x4b, 0x88, 0xd9, 0xbc, 0x76, 0xd0, 0x56,
-	0x48, 0xa0, 0x00, 0x6f, 0x02, 0xb7, 0x47, 0xc7, 0xec, 0xd5, 0x3e, 0xcb, 0x8e, 0xe8, 0xa4, 0x30,
-	0xf7, 0xec, 0x3b, 0xfc, 0x91, 0x70, 0xa1, 0x99, 0x63, 0xa9, 0xce, 0x94, 0xdd, 0xa3, 0xb2, 0xe9,
-	0xfd, 0xc9, 0x81, 0x3b, 0xab, 0x46, 0x7a, 0x97, 0xe5, 0x1f, 0xc0, 0x5a, 0x64, 0xdc, 0x19, 0x6f,
-	0x57, 0xff, 0xd3, 0xb9, 0xd8, 0xcf, 0x7b, 0x0c, 0x35, 0x5d, 0x4d, 0xec, 0x40, 0x85, 0x4b, 0x3d,
-	0x83, 0xde, 0xee, 0xfd, 0x0b, 0x94, 0x42, 0x11, 0xf5, 0xf3, 0xb5, 0xc2, 0x25, 0xea, 0x82, 0xc3,
-	0xf5, 0x4a, 0x9d, 0xc0, 0xe1, 0x1f, 0xfe, 0xcd, 0x81, 0x56, 0x69, 0x46, 0x1b, 0xb0, 0xe6, 0xfb,
-	0xc3, 0xfd, 0xa9, 0x56, 0xf5, 0xbf, 0x85, 0xfa, 0xd0, 0xf5, 0xfd, 0xe1, 0x61, 0x59, 0x3e, 0xf6,
-	0x1d, 0xd4, 0x85, 0x96, 0xef, 0x0f, 0xb5, 0xf8, 0xf4, 0x2b, 0xb6, 0xf5, 0x24, 0x29, 0xc4, 0x71,
-	0xbf, 0x3a, 0x75, 0x90, 0xe6, 0xd8, 0x38, 0xa8, 0xa1, 0x35, 0x68, 0xfb, 0x4f, 0x87, 0x83, 0x4c,
-	0x10, 0x2e, 0xfb, 0x75, 0xdb, 0xf4, 0x49, 0x42, 0x24, 0xe9, 0x37, 0xd0, 0x3a, 0x74, 0xfc, 0xa7,
-	0xc3, 0xbd, 0x22, 0x79, 0xa9, 0xee, 0xb1, 0x7e, 0x53, 0xdb, 0x9f, 0x0f, 0xcd, 0x8b, 0xa6, 0xdf,
-	0xd2, 0xee, 0x9f, 0x0f, 0xd5, 0x1b, 0xeb, 0xac, 0xdf, 0xb6, 0x9d, 0x7f, 0x99, 0x6b, 0x5f, 0xb0,
-	0xf7, 0xf0, 0xd7, 0x9f, 0x4e, 0xa8, 0x3c, 0x2e, 0xc6, 0x2a, 0x5e, 0x3b, 0x66, 0xe9, 0x1f, 0x51,
-	0x66, 0xbf, 0x76, 0xca, 0xe5, 0xef, 0xe8, 0x68, 0x4c, 0x9b, 0xf9, 0x78, 0xdc, 0xd0, 0xc8, 0x27,
-	0xff, 0x0a, 0x00, 0x00, 0xff, 0xff, 0x39, 0x3a, 0x8f, 0x34, 0x8a, 0x17, 0x00, 0x00,
+	// 3969 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd5, 0x5a, 0x5b, 0x73, 0x1b, 0x49,
+	0x15, 0x46, 0x96, 0x2f, 0x72, 0xcb, 0x96, 0xe5, 0xf1, 0x6d, 0x1c, 0x67, 0x37, 0x89, 0x76, 0xb3,
+	0x97, 0xec, 0x26, 0x01, 0x2f, 0xbb, 0x0b, 0x0b, 0x05, 0xc4, 0x76, 0x9c, 0x4d, 0x6d, 0x2e, 0xce,
+	0xc8, 0x6c, 0xc1, 0xbe, 0x4c, 0x8d, 0x34, 0x6d, 0x69, 0xd0, 0x68, 0x46, 0x99, 0x1e, 0x25, 0x11,
+	0xcf, 0x50, 0x54, 0x41, 0x15, 0x6f, 0xbc, 0x50, 0x05, 0xbf, 0x81, 0x17, 0x8a, 0xe2, 0x89, 0x17,
+	0x7e, 0x00, 0x7f, 0x01, 0x7e, 0x00, 0x3f, 0x80, 0x27, 0xce, 0xa5, 0xe7, 0x26, 0xcb, 0x8a, 0x93,
+	0xb0, 0xb0, 0xbc, 0xa8, 0xa6, 0xbf, 0x73, 0xa6, 0xa7, 0xfb, 0xf4, 0xe9, 0xef, 0x9c, 0xd3, 0x2d,
+	0x51, 0xf3, 0x82, 0x58, 0x46, 0x81, 0xe3, 0xdf, 0x18, 0x44, 0x61, 0x1c, 0x1a, 0x1b, 0x7d, 0xcf,
+	0x7f, 0x32, 0x54, 0xdc, 0xba, 0x91, 0x08, 0x2f, 0x2c, 0xb5, 0xc3, 0x7e, 0x3f, 0x0c, 0x18, 0xbe,
+	0xb0, 0xa4, 0xda, 0x5d, 0xd9, 0x77, 0xb8, 0xd5, 0xd8, 0x11, 0xdb, 0x77, 0x64, 0x7c, 0xec, 0xf5,
+	0xe5, 0xb1, 0xd7, 0xee, 0xed, 0x77, 0x9d, 0x20, 0x90, 0xbe, 0x25, 0x1f, 0x0f, 0xa5, 0x8a, 0x1b,
+	0xaf, 0x89, 0x1d, 0x10, 0x36, 0x63, 0x27, 0xf6, 0x54, 0xec, 0xb5, 0xd5, 0x98, 0x78, 0x43, 0xac,
+	0x81, 0xf8, 0xc0, 0x1d, 0x83, 0x3f, 0x17, 0x95, 0x07, 0xa1, 0x2b, 0xef, 0x06, 0x27, 0xa1, 0xf1,
+	0x91, 0x58, 0x70, 0x5c, 0x37, 0x92, 0x4a, 0x99, 0xa5, 0xcb, 0xa5, 0x77, 0xaa, 0xbb, 0x17, 0x6f,
+	0x14, 0xc6, 0xa8, 0x47, 0x76, 0x8b, 0x75, 0xac, 0x44, 0xd9, 0x30, 0xc4, 0x6c, 0x14, 0xfa, 0xd2,
+	0x9c, 0x81, 0x97, 0x16, 0x2d, 0x7a, 0x6e, 0xfc, 0x44, 0x88, 0xbb, 0x81, 0x17, 0x1f, 0x39, 0x91,
+	0xd3, 0x57, 0xc6, 0xa6, 0x98, 0x0f, 0xf0, 0x2b, 0x07, 0xd4, 0x71, 0xd9, 0xd2, 0x2d, 0xe3, 0x40,
+	0x2c, 0xa9, 0xd8, 0x89, 0x62, 0x7b, 0x40, 0x7a, 0xd0, 0x43, 0x19, 0x3e, 0x7b, 0x65, 0xe2, 0x67,
+	0x3f, 0x93, 0xa3, 0xcf, 0x1d, 0x7f, 0x28, 0x8f, 0x1c, 0x2f, 0xb2, 0xaa, 0xf4, 0x1a, 0xf7, 0xde,
+	0xf8, 0xb1, 0x10, 0xcd, 0x38, 0xf2, 0x82, 0xce, 0x3d, 0x98, 0x39, 0x7e, 0xeb, 0x09, 0xea, 0xe1,
+	0x24, 0xca, 0x30, 0x1e, 0xdd, 0x32, 0x3e, 0x10, 0xf3, 0xf0, 0x52, 0x3c, 0x54, 0x34, 0xce, 0xea,
+	0xee, 0xce, 0xc4, 0xaf, 0x34, 0x49, 0xc5, 0xd2, 0xaa, 0x8d, 0xbf, 0xcf, 0x88, 0xf5, 0x82, 0x55,
+	0xb5, 0xdd, 0x8c, 0xaf, 0x8b, 0xd9, 0x96, 0xa3, 0xe4, 0x54, 0x43, 0xdd, 0x57, 0x9d, 0x3d, 0xd0,
+	0xb1, 0x48, 0x13, 0xad, 0xe4, 0xb6, 0xc0, 0x02, 0x33, 0x64, 0x01, 0x7a, 0x36, 0x1a, 0x02, 0x96,
+	0xdb, 0xf7, 0x65, 0x3b, 0xf6, 0xc2, 0x00, 0x64, 0x65, 0x92, 0x15, 0x30, 0xd4, 0x01, 0xeb, 0xc4,
+	0x1e, 0x37, 0x95, 0x39, 0x0b, 0xb3, 0x02, 0x9d, 0x3c, 0x66, 0xbc, 0x2b, 0xea, 0x71, 0xe4, 0x3c,
+	0x91, 0xbe, 0x1d, 0x83, 0x73, 0xc0, 0xd8, 0xfb, 0x03, 0x73, 0x0e, 0xfa, 0x9a, 0xb5, 0x56, 0x18,
+	0x3f, 0x4e, 0x60, 0xe3, 0xa6, 0x58, 0xeb, 0x0c, 0xc1, 0x6e, 0xe0, 0x6f, 0x32, 0xa7, 0x3d, 0x4f,
+	0xda, 0x46, 0x2a, 0xca, 0x5e, 0x78, 0x4f, 0xac, 0xa2, 0x5a, 0x38, 0x8c, 0x73, 0xea, 0x0b, 0xa4,
+	0x5e, 0xd7, 0x82, 0x4c, 0x79, 0x57, 0x6c, 0xa4, 0x03, 0xb3, 0x7b, 0x72, 0x64, 0x9f, 0x78, 0xd2,
+	0x77, 0x61, 0x66, 0x15, 0x9a, 0xd9, 0x5a, 0x2a, 0x84, 0xd5, 0x3c, 0x64, 0x51, 0xe3, 0x4f, 0x25,
+	0xb1, 0x31, 0x66, 0x63, 0x35, 0x08, 0x03, 0x30, 0xd9, 0x8b, 0x1b, 0xf9, 0x65, 0x16, 0xd9, 0xf8,
+	0x58, 0xcc, 0xe1, 0x93, 0x02, 0xf3, 0x9f, 0xd3, 0xfd, 0x58, 0xbf, 0xf1, 0xfb, 0x92, 0x30, 0xf6,
+	0x23, 0xe9, 0xc4, 0xf2, 0x96, 0xef, 0x39, 0xaf, 0xe0, 0x1b, 0x5b, 0x62, 0xc1, 0x6d, 0xd9, 0x81,
+	0xd3, 0x4f, 0x36, 0xd1, 0xbc, 0xdb, 0x7a, 0x00, 0x2d, 0xe3, 0x6d, 0xb1, 0x92, 0x39, 0x03, 0x2b,
+	0x94, 0x49, 0xa1, 0x96, 0xc1, 0xa4, 0xb8, 0x2e, 0xe6, 0x1c, 0x1c, 0x03, 0xb8, 0x07, 0x8a, 0xb9,
+	0xd1, 0x50, 0xa2, 0x7e, 0x10, 0x85, 0x83, 0x2f, 0x6b, 0x74, 0xe9, 0x47, 0xcb, 0xf9, 0x8f, 0xfe,
+	0xae, 0x24, 0x56, 0x6f, 0xf9, 0x40, 0x67, 0x5f, 0x51, 0xa3, 0xfc, 0x65, 0x26, 0x59, 0xb5, 0xbb,
+	0x81, 0x2b, 0x9f, 0xfd, 0x2f, 0x07, 0xf8, 0x9a, 0x10, 0xb4, 0x41, 0x58, 0x87, 0x47, 0xb9, 0x48,
+	0x08, 0x89, 0x13, 0xca, 0x98, 0x9b, 0x42, 0x19, 0xf3, 0x13, 0x28, 0xc3, 0x14, 0x0b, 0xc9, 0xbe,
+	0x5b, 0x20, 0x71, 0xd2, 0x44, 0xc2, 0x95, 0xcf, 0x80, 0x12, 0x12, 0xc2, 0xad, 0x9c, 0x9b, 0x70,
+	0xe9, 0x35, 0x4d, 0xb8, 0xff, 0xa8, 0x89, 0xe5, 0xa6, 0x74, 0xa2, 0x76, 0xf7, 0xe5, 0x8d, 0x07,
+	0x6b, 0x13, 0xc9, 0xc7, 0x29, 0x1f, 0x72, 0x23, 0x9d, 0x71, 0x79, 0xca, 0x8c, 0x67, 0xcf, 0x41,
+	0x92, 0x73, 0x13, 0x48, 0xb2, 0x2e, 0xca, 0xae, 0xf2, 0xc9, 0x60, 0x8b, 0x16, 0x3e, 0x22, 0xb5,
+	0x0d, 0x7c, 0xa7, 0x2d, 0xbb, 0xa1, 0xef, 0xca, 0xc8, 0xee, 0x44, 0xe1, 0x90, 0xa9, 0x6d, 0xc9,
+	0xaa, 0xe7, 0x04, 0x77, 0x10, 0x07, 0x96, 0xa8, 0xc0, 0x3b, 0x76, 0x3c, 0x1a, 0x48, 0x62, 0xb3,
+	0xda, 0x19, 0xd3, 0x3c, 0x50, 0xfe, 0x31, 0xe8, 0x58, 0x0b, 0x2e, 0x3f, 0x80, 0x6d, 0xd6, 0x95,
+	0x8c, 0x3c, 0x70, 0xbe, 0x9f, 0x4a, 0xd7, 0x96, 0xcf, 0x06, 0x91, 0x0d, 0x9d, 0x07, 0xe6, 0x22,
+	0x7d, 0xc8, 0xc8, 0x64, 0xb7, 0x41, 0x74, 0x04, 0x12, 0xe3, 0x1d, 0x51, 0x07, 0x56, 0x1d, 0x00,
+	0xe3, 0xd2, 0xba, 0x29, 0xdb, 0x73, 0x4d, 0x41, 0x33, 0xaa, 0x31, 0x4e, 0xd4, 0xa9, 0xee, 0xba,
+	0x67, 0xb1, 0xf9, 0xd2, 0x8b, 0xb1, 0xf9, 0xf2, 0x19, 0x6c, 0x5e, 0x13, 0x33, 0xc1, 0x63, 0xb3,
+	0x46, 0xf6, 0x86, 0x27, 0x5c, 0x9d, 0x38, 0x1c, 0xf4, 0xcc, 0x15, 0x5e, 0x1d, 0x7c, 0x36, 0x5e,
+	0x17, 0xa2, 0x2f, 0x21, 0xfa, 0xb6, 0x71, 0xae, 0x66, 0x9d, 0x8c, 0x9b, 0x43, 0x8c, 0x37, 0xc5,
+	0xb2, 0xd7, 0x09, 0xc2, 0x48, 0x82, 0x15, 0x9f, 0x42, 0x8c, 0x36, 0x57, 0x41, 0xa5, 0x62, 0x15,
+	0x41, 0xe3, 0x82, 0xa8, 0x0c, 0x15, 0x26, 0x40, 0xb0, 0x0d, 0x0c, 0xea, 0x23, 0x6d, 0x1b, 0x6f,
+	0x88, 0xe5, 0x41, 0x24, 0x4f, 0x60, 0x81, 0xda, 0x0e, 0x64, 0x43, 0xae, 0xb9, 0x46, 0x3d, 0x2c,
+	0x31, 0xb8, 0x4f, 0x98, 0x71, 0x4d, 0xac, 0x46, 0x32, 0x1e, 0x46, 0x81, 0xad, 0x64, 0xa7, 0x2f,
+	0x83, 0x18, 0x6d, 0xb6, 0x4e, 0x8a, 0x2b, 0x2c, 0x68, 0x32, 0x0e, 0x46, 0x83, 0xed, 0x01, 0xab,
+	0xe0, 0x3b, 0x5e, 0x60, 0x6e, 0x90, 0x46, 0xd2, 0x34, 0xbe, 0x29, 0x36, 0x65, 0xe0, 0xb4, 0x7c,
+	0x69, 0xab, 0x36, 0x8c, 0xce, 0x8e, 0xbb, 0x90, 0xe0, 0xa0, 0x13, 0x98, 0x9b, 0xa4, 0xb8, 0xce,
+	0xd2, 0x26, 0x0a, 0x8f, 0x13, 0x19, 0x6e, 0xf7, 0x71, 0xf5, 0x2d, 0x50, 0x9f, 0xb1, 0x6a, 0xaa,
+	0xa8, 0x78, 0x51, 0x2c, 0x46, 0x72, 0xe0, 0x7b, 0x6d, 0x07, 0xdc, 0xd8, 0x24, 0x23, 0x66, 0x80,
+	0x71, 0x55, 0xd4, 0x3c, 0x60, 0x4d, 0x27, 0x0e, 0x23, 0x3b, 0x0e, 0x7b, 0x32, 0x30, 0xb7, 0xc9,
+	0x43, 0x96, 0x13, 0xf4, 0x18, 0x41, 0xe3, 0x92, 0xa8, 0x7a, 0xe0, 0x11, 0x1a, 0x33, 0x2f, 0xd0,
+	0xc0, 0x84, 0xa7, 0xee, 0x6a, 0xc4, 0xf8, 0xb6, 0x80, 0xcd, 0xda, 0xf6, 0x87, 0xae, 0xb4, 0x07,
+	0x3d, 0x65, 0xee, 0xd0, 0x96, 0x34, 0x8b, 0xbe, 0xaa, 0xd3, 0x4a, 0xd8, 0x16, 0x96, 0xd0, 0xca,
+	0x47, 0x3d, 0x65, 0xec, 0x88, 0x45, 0xd5, 0xf3, 0x06, 0x76, 0x37, 0x0c, 0x7b, 0xe6, 0x45, 0xea,
+	0xb9, 0x82, 0xc0, 0xa7, 0xd0, 0xc6, 0x69, 0x9e, 0x78, 0xc8, 0xeb, 0xb6, 0x02, 0x2a, 0x88, 0x65,
+	0x67, 0x64, 0xbe, 0xc6, 0xac, 0xc6, 0x70, 0x53, 0xa3, 0x86, 0x25, 0x56, 0xdb, 0x10, 0xbf, 0x21,
+	0x98, 0xcb, 0xa0, 0x3d, 0xb2, 0x7d, 0x09, 0x09, 0x88, 0xf9, 0x3a, 0x6d, 0x99, 0xab, 0x13, 0xb7,
+	0xcc, 0x7e, 0xa6, 0x7d, 0x0f, 0x95, 0xad, 0x7a, 0x7b, 0x0c, 0x31, 0x3e, 0x11, 0xdb, 0x12, 0x72,
+	0xd4, 0xa8, 0x2d, 0xed, 0xd3, 0x7d, 0x5f, 0xa2, 0x91, 0x6e, 0x69, 0x85, 0xf1, 0xde, 0x30, 0x3b,
+	0x8a, 0xa4, 0x3b, 0x84, 0x57, 0x1d, 0xbf, 0x13, 0x46, 0x5e, 0xdc, 0xed, 0x9b, 0x97, 0x69, 0xe4,
+	0x2b, 0x8c, 0xdf, 0x4a, 0x60, 0xf4, 0x35, 0xf0, 0x2a, 0x2f, 0x90, 0xf6, 0x89, 0xd3, 0x46, 0xf3,
+	0x5e, 0x61, 0xb2, 0x61, 0xf0, 0x90, 0xb0, 0x9c, 0xaf, 0xc1, 0xee, 0xea, 0xb1, 0xab, 0x98, 0x8d,
+	0xbc, 0xaf, 0x59, 0x80, 0x93, 0x93, 0x18, 0x6f, 0x09, 0x80, 0x48, 0x8d, 0x89, 0x1e, 0xbc, 0xf2,
+	0x0d, 0xea, 0x72, 0x99, 0x61, 0x4e, 0x82, 0x5c, 0xe3, 0x7d, 0x61, 0x68, 0x3d, 0xde, 0x3b, 0xcc,
+	0x33, 0x6f, 0xd2, 0x28, 0xeb, 0x2c, 0xb9, 0x9f, 0x6d, 0xaa, 0x6f, 0x09, 0x53, 0x6b, 0x9f, 0xe6,
+	0xaf, 0xab, 0xe4, 0x34, 0x9b, 0x2c, 0x3f, 0x1a, 0x67, 0xb1, 0x2b, 0x18, 0x00, 0x60, 0x1a, 0xb0,
+	0x4d, 0x90, 0xbf, 0xcd, 0xb7, 0x68, 0xd8, 0x55, 0xc2, 0x98, 0xd2, 0x8d, 0xeb, 0x38, 0x14, 0x9a,
+	0x1e, 0xcc, 0xb9, 0x23, 0xa3, 0x01, 0xa4, 0xd6, 0xb1, 0xf9, 0x36, 0x29, 0xea, 0x89, 0x1f, 0x66,
+	0x02, 0xa8, 0x1a, 0xe6, 0xc0, 0x56, 0x32, 0x36, 0xdf, 0x21, 0x47, 0xbb, 0x7c, 0x63, 0x62, 0x5d,
+	0x73, 0xe3, 0x10, 0x75, 0x9a, 0x03, 0xd9, 0xb6, 0x58, 0x1d, 0x67, 0x3c, 0x80, 0x41, 0x67, 0xe9,
+	0x22, 0x51, 0xcb, 0xbb, 0xf4, 0x99, 0x3a, 0x48, 0x8e, 0x12, 0xc1, 0x31, 0xd2, 0x0c, 0x50, 0x22,
+	0xef, 0x31, 0xca, 0xbc, 0xec, 0x30, 0xf0, 0x47, 0xe6, 0x35, 0xd2, 0xe5, 0x4d, 0x86, 0x29, 0x9d,
+	0x7a, 0x08, 0x28, 0xf0, 0xb4, 0xd0, 0xdb, 0x19, 0xdc, 0xdf, 0x7c, 0xef, 0x39, 0xde, 0xbf, 0xa8,
+	0x75, 0x8f, 0x7a, 0xb8, 0x3b, 0xc3, 0x27, 0x32, 0x3a, 0x91, 0x31, 0xd8, 0xe5, 0x7d, 0xde, 0x9d,
+	0x29, 0x80, 0x26, 0xf7, 0x20, 0x27, 0x8d, 0x68, 0xa8, 0x61, 0x84, 0xf6, 0x8e, 0x3d, 0xd9, 0x82,
+	0x3c, 0xa2, 0x67, 0x5e, 0xa7, 0x81, 0x6c, 0xa6, 0xf2, 0x87, 0x28, 0x3e, 0xd6, 0x52, 0xe3, 0x43,
+	0xb1, 0x35, 0x46, 0x4d, 0x27, 0xfe, 0x50, 0x75, 0x6d, 0x48, 0x38, 0x6f, 0x30, 0xab, 0x14, 0x08,
+	0xea, 0x10, 0x85, 0xc7, 0xaa, 0xf1, 0xcb, 0xe5, 0x2c, 0xc8, 0xaa, 0xa1, 0x1f, 0xab, 0xff, 0x56,
+	0x3a, 0x9c, 0x46, 0xe6, 0x72, 0x3e, 0x32, 0x03, 0xed, 0xe4, 0x3d, 0x73, 0xf6, 0x14, 0xd1, 0x83,
+	0x42, 0x30, 0xec, 0xdb, 0x90, 0x0f, 0x44, 0x9e, 0x54, 0x3a, 0x67, 0x11, 0x00, 0x3d, 0x62, 0xc4,
+	0x58, 0x13, 0x73, 0xb0, 0xc4, 0x76, 0x4f, 0xa7, 0x2c, 0x18, 0x3e, 0x3e, 0x33, 0xbe, 0x2b, 0x2e,
+	0x80, 0x27, 0xfa, 0x10, 0x18, 0xb5, 0x71, 0x60, 0x51, 0xb4, 0x6f, 0x02, 0xd3, 0x2f, 0x50, 0xd0,
+	0x33, 0x59, 0xa3, 0x99, 0x2a, 0x34, 0xb5, 0x1c, 0xc3, 0x5f, 0x9b, 0xeb, 0xd9, 0xc2, 0x6b, 0x15,
+	0x2a, 0xfc, 0x8c, 0x4c, 0x94, 0xbe, 0x00, 0xab, 0xd8, 0xf1, 0xc3, 0x96, 0xe3, 0xdb, 0xa7, 0xbe,
+	0x0a, 0xf1, 0x18, 0x3f, 0xb6, 0xc9, 0xf2, 0xe6, 0xd8, 0x27, 0x71, 0x7a, 0x0a, 0x88, 0x1a, 0x5e,
+	0x69, 0x81, 0x02, 0x84, 0x63, 0xdc, 0x65, 0x82, 0xa1, 0x3d, 0x40, 0xc8, 0x43, 0x59, 0x01, 0xcd,
+	0xd0, 0x0e, 0x87, 0xb0, 0x69, 0xaa, 0x34, 0xd3, 0x1a, 0xe3, 0x0f, 0x86, 0xfd, 0x7d, 0x44, 0x91,
+	0x64, 0xb4, 0x66, 0x78, 0x72, 0xa2, 0x60, 0xe7, 0x2c, 0x31, 0xc9, 0x30, 0xf8, 0x90, 0x30, 0xe3,
+	0x08, 0x73, 0x48, 0x15, 0xdf, 0xea, 0x74, 0x22, 0xd9, 0x71, 0xd0, 0xab, 0x28, 0x4c, 0x57, 0x77,
+	0xdf, 0x3a, 0x63, 0x83, 0xed, 0x17, 0xb5, 0xad, 0xf1, 0xd7, 0x31, 0xd9, 0x84, 0xc0, 0x41, 0xfb,
+	0xcd, 0xf1, 0x29, 0xaa, 0x57, 0xac, 0x45, 0x4f, 0x1d, 0x31, 0x00, 0x81, 0xba, 0x06, 0x62, 0xdc,
+	0x84, 0x10, 0x67, 0x07, 0x03, 0x30, 0xe3, 0x0a, 0xc7, 0x59, 0x4f, 0xe1, 0x0e, 0xdc, 0x27, 0xcc,
+	0x78, 0x24, 0x60, 0xbf, 0x39, 0x81, 0xed, 0xca, 0xb6, 0xa7, 0xa0, 0x57, 0x05, 0x21, 0x1f, 0x53,
+	0xc8, 0x6b, 0x67, 0x8c, 0x4a, 0x5b, 0xb0, 0x09, 0xef, 0x1c, 0xe8, 0x57, 0xac, 0x65, 0x95, 0x6b,
+	0x29, 0xa4, 0x48, 0xcc, 0x3c, 0xc0, 0x1a, 0x90, 0x94, 0xe0, 0xc1, 0x80, 0x82, 0x1c, 0x01, 0x97,
+	0x62, 0x99, 0xe0, 0x87, 0xc3, 0x18, 0x4f, 0x28, 0xc8, 0x2f, 0x71, 0x74, 0x0a, 0x12, 0x04, 0x94,
+	0x72, 0x03, 0x77, 0x6d, 0x1c, 0x0d, 0x83, 0x36, 0x84, 0x1e, 0xcc, 0x0c, 0xca, 0x38, 0xa9, 0x14,
+	0x30, 0x6e, 0x88, 0xb5, 0x00, 0x32, 0x57, 0x7b, 0x2c, 0xb0, 0xae, 0xd3, 0xea, 0xad, 0xa2, 0xe8,
+	0x6e, 0x21, 0xb8, 0x7a, 0x62, 0x3b, 0xd9, 0xa4, 0x5d, 0x2f, 0xb6, 0x5d, 0x88, 0x23, 0x91, 0xd7,
+	0x1a, 0xc6, 0x34, 0xd3, 0x0d, 0x9a, 0xe9, 0xf5, 0xe9, 0x33, 0xfd, 0xd4, 0x8b, 0x0f, 0x72, 0x6f,
+	0x59, 0x5b, 0x6a, 0x22, 0xae, 0xf0, 0x53, 0x63, 0xe1, 0x34, 0x67, 0xd4, 0xcd, 0xa9, 0x9f, 0x3a,
+	0x2c, 0xc4, 0xdb, 0xd4, 0xae, 0x5b, 0x27, 0x13, 0x71, 0x3a, 0x1e, 0x40, 0x93, 0x07, 0x99, 0xbf,
+	0x2b, 0xca, 0x50, 0xca, 0xd6, 0x8a, 0xc6, 0xf5, 0xe0, 0x15, 0xc6, 0x87, 0x44, 0x15, 0x52, 0x33,
+	0xa5, 0xb3, 0x94, 0xaa, 0xc6, 0x2c, 0x80, 0xc0, 0x33, 0xd9, 0x05, 0x20, 0x49, 0xf4, 0xfa, 0xf0,
+	0x25, 0x05, 0x79, 0x0a, 0x8e, 0xf6, 0xdd, 0x33, 0x0d, 0x83, 0x9b, 0x0f, 0x3d, 0xe0, 0xb6, 0x7e,
+	0x83, 0x3d, 0x20, 0x69, 0xd1, 0xde, 0xca, 0x22, 0xa9, 0x82, 0x94, 0xa6, 0x0c, 0xc9, 0x93, 0x88,
+	0x92, 0x20, 0xaa, 0x30, 0x6b, 0x45, 0x5e, 0x19, 0x15, 0x22, 0xd2, 0x0e, 0x07, 0x47, 0x12, 0xe4,
+	0x03, 0xd2, 0x1d, 0xb1, 0x4c, 0x11, 0xc6, 0x6e, 0x0d, 0xdb, 0x3d, 0x09, 0x53, 0xbd, 0x48, 0xc3,
+	0x6b, 0x4c, 0x0b, 0x4c, 0x7b, 0xa4, 0x6a, 0x2d, 0x9d, 0x64, 0x0d, 0x65, 0x3c, 0x10, 0x2b, 0xc5,
+	0xe8, 0xa4, 0x20, 0xe1, 0xc1, 0xae, 0xae, 0x9e, 0xd1, 0x55, 0x21, 0x64, 0x29, 0xab, 0x36, 0x28,
+	0xb4, 0x8d, 0x3d, 0xa0, 0x90, 0x2c, 0x86, 0x41, 0x46, 0x34, 0xa1, 0xf6, 0xca, 0xac, 0x96, 0x46,
+	0x35, 0x60, 0x99, 0xf4, 0x19, 0x8c, 0x5f, 0x47, 0xc7, 0xa4, 0xa8, 0x15, 0x38, 0xec, 0x97, 0x97,
+	0xa6, 0x0e, 0x0a, 0x1c, 0xef, 0x76, 0xa6, 0x6d, 0xad, 0x74, 0x0b, 0x6d, 0xc5, 0xe9, 0x35, 0x2c,
+	0x04, 0x58, 0x97, 0xe7, 0x78, 0x59, 0xd7, 0x4e, 0x0c, 0xd2, 0xd0, 0x1b, 0x8f, 0xc5, 0xca, 0x18,
+	0xbf, 0x60, 0xc9, 0x15, 0xe9, 0x83, 0x1a, 0xac, 0x18, 0xf4, 0xc9, 0x5e, 0x01, 0x33, 0x2e, 0xc3,
+	0x8c, 0x65, 0xf4, 0x04, 0x68, 0x8d, 0x54, 0x66, 0xb4, 0x33, 0x65, 0x10, 0xe6, 0xe2, 0x71, 0x18,
+	0x3b, 0xfe, 0x83, 0x47, 0x3a, 0xdc, 0x24, 0xcd, 0xc6, 0x2f, 0x84, 0x58, 0xb1, 0x30, 0xbc, 0x40,
+	0x0e, 0xf7, 0xff, 0x54, 0x66, 0x9e, 0x55, 0xee, 0xcd, 0xbf, 0x50, 0xb9, 0xb7, 0x30, 0xb1, 0xdc,
+	0x83, 0x12, 0xa1, 0xff, 0xa4, 0xdd, 0xce, 0x95, 0x6e, 0x15, 0x2a, 0xdd, 0x96, 0x11, 0x7d, 0xee,
+	0x19, 0xdf, 0xe2, 0x8b, 0x55, 0x85, 0xe2, 0x8c, 0xaa, 0x10, 0x4c, 0xea, 0x7b, 0x7d, 0x2f, 0x89,
+	0x6e, 0xdc, 0x38, 0x5d, 0xe7, 0x2d, 0x4d, 0xaa, 0xf3, 0xb6, 0x45, 0x05, 0x82, 0x0c, 0x07, 0xc7,
+	0x65, 0xae, 0xbd, 0x3c, 0xc5, 0x51, 0xf1, 0xb6, 0xb8, 0xc4, 0x2c, 0x8d, 0xbb, 0x0d, 0x88, 0x59,
+	0x06, 0x48, 0x5e, 0xb6, 0xce, 0xdc, 0x91, 0xd2, 0x74, 0x25, 0x7a, 0x31, 0x55, 0xbb, 0x9d, 0x68,
+	0x59, 0xa4, 0x64, 0x81, 0x4e, 0xa1, 0x92, 0x5c, 0x19, 0xab, 0x24, 0x6f, 0x8a, 0x75, 0xdd, 0x9d,
+	0xc2, 0x4c, 0x04, 0xaa, 0x05, 0xbb, 0x05, 0x93, 0xa2, 0xaa, 0x95, 0x72, 0x5b, 0x94, 0x35, 0x41,
+	0x74, 0x18, 0x46, 0x7b, 0xe8, 0x6f, 0x18, 0xf4, 0x61, 0xca, 0x58, 0x0f, 0xc2, 0x8a, 0x51, 0xe9,
+	0x0a, 0x39, 0x0d, 0x43, 0x4d, 0x40, 0xf2, 0x0a, 0x12, 0xe2, 0x8f, 0x51, 0x50, 0x00, 0x04, 0x2b,
+	0x4a, 0x0c, 0x22, 0x5e, 0x00, 0x29, 0x37, 0x4d, 0x3b, 0x3d, 0x11, 0x5d, 0x23, 0xdd, 0xf5, 0x44,
+	0x4a, 0x46, 0xd0, 0x47, 0xa2, 0xf9, 0x0a, 0x75, 0xbd, 0x58, 0xa1, 0xd2, 0xd1, 0x52, 0x7f, 0x80,
+	0xe7, 0xee, 0x18, 0x37, 0xa4, 0xd3, 0xd7, 0x35, 0x6c, 0x2d, 0x81, 0x9b, 0x84, 0x1a, 0xdf, 0x81,
+	0x52, 0x2e, 0x8c, 0x62, 0x3c, 0x84, 0x4d, 0xc2, 0xc9, 0xeb, 0x67, 0x51, 0x0d, 0xe8, 0x7d, 0x26,
+	0x47, 0x50, 0xea, 0xf1, 0x83, 0x2a, 0x16, 0xaa, 0x5b, 0xe3, 0x85, 0xea, 0xae, 0xd8, 0xf0, 0x65,
+	0xe0, 0x61, 0x90, 0x2c, 0xf8, 0x2d, 0x05, 0x8b, 0x8a, 0xb5, 0xa6, 0x85, 0x0f, 0x73, 0xbe, 0x8b,
+	0x3e, 0xde, 0x77, 0x9e, 0xe9, 0x21, 0xdb, 0xad, 0x11, 0x87, 0x0d, 0xca, 0x8e, 0x00, 0xe7, 0x31,
+	0xef, 0x21, 0x3a, 0xb9, 0x7a, 0xbc, 0xf0, 0x25, 0x56, 0x8f, 0x3b, 0xe7, 0xa8, 0x1e, 0xd5, 0xb0,
+	0xcf, 0xc7, 0x02, 0x6c, 0xf2, 0x8b, 0x49, 0xb1, 0xa7, 0x71, 0x6d, 0x73, 0x4c, 0xec, 0x78, 0x82,
+	0xed, 0x61, 0x04, 0xc6, 0xd4, 0xf5, 0xf1, 0x12, 0x83, 0xfb, 0x84, 0x21, 0x3f, 0xa4, 0xe5, 0x15,
+	0x56, 0x84, 0xf0, 0xd5, 0x3e, 0x87, 0x03, 0xec, 0xd3, 0x48, 0x0a, 0x2c, 0x10, 0x1d, 0xb2, 0xa4,
+	0xf1, 0xc7, 0x4a, 0x9e, 0x09, 0xbf, 0x02, 0xb5, 0xc0, 0x35, 0x51, 0xf6, 0x5c, 0x3e, 0x55, 0x9d,
+	0x56, 0x5b, 0xa1, 0x92, 0xf1, 0x7d, 0x51, 0xd5, 0xac, 0xe6, 0x3a, 0xb1, 0x43, 0x8c, 0x79, 0xca,
+	0x13, 0xf5, 0x3b, 0x34, 0xe9, 0x03, 0xd0, 0xb2, 0xf8, 0x54, 0x54, 0xe1, 0xb3, 0xf1, 0x3d, 0xb1,
+	0x73, 0xba, 0x42, 0x88, 0xb4, 0x39, 0x5c, 0xa0, 0x55, 0x24, 0xca, 0xed, 0xf1, 0x12, 0x21, 0xb1,
+	0x97, 0x6b, 0x7c, 0x43, 0xac, 0xe7, 0x6a, 0x84, 0xec, 0xc5, 0x05, 0x2a, 0x12, 0x72, 0xf5, 0x43,
+	0xf6, 0xca, 0xb4, 0x2a, 0xa1, 0x32, 0xb5, 0x4a, 0xf8, 0xcf, 0x67, 0xed, 0x40, 0xcd, 0x9a, 0x61,
+	0x06, 0xe1, 0x60, 0xe8, 0x73, 0x9f, 0x4c, 0x84, 0x75, 0x16, 0x1c, 0xa5, 0x38, 0xb2, 0x43, 0xca,
+	0x36, 0xaa, 0x47, 0x85, 0xec, 0x0a, 0x85, 0x9d, 0x5a, 0x02, 0x37, 0x09, 0xc5, 0x40, 0x52, 0xa4,
+	0x25, 0xe2, 0x40, 0x48, 0xb9, 0x0b, 0x74, 0x84, 0xbe, 0x3a, 0xc6, 0x5e, 0x32, 0x8a, 0xc0, 0xaf,
+	0x91, 0x08, 0x4b, 0x96, 0x51, 0x50, 0xbe, 0x8d, 0x92, 0x09, 0xf5, 0x81, 0xf1, 0xaa, 0xf5, 0x01,
+	0x50, 0x68, 0xc2, 0x6d, 0xb0, 0x14, 0x79, 0x67, 0x5a, 0xa3, 0xb9, 0xad, 0x67, 0xd2, 0xc3, 0xcc,
+	0x6d, 0x60, 0x2f, 0xa6, 0x44, 0x49, 0x15, 0xeb, 0x3a, 0xef, 0xc5, 0x04, 0xa4, 0x9a, 0xf5, 0x23,
+	0xb1, 0xe5, 0x46, 0x21, 0x16, 0x36, 0x05, 0x26, 0xc3, 0x75, 0xde, 0xa0, 0x75, 0xde, 0xd0, 0xe2,
+	0x1c, 0x97, 0xe1, 0x32, 0x03, 0x3f, 0x3f, 0x75, 0xa2, 0x00, 0xc3, 0xdc, 0x26, 0x75, 0x9b, 0x34,
+	0x8b, 0xe5, 0xc8, 0x16, 0xd7, 0x58, 0x59, 0x39, 0x72, 0x8a, 0x20, 0xcc, 0x09, 0x04, 0x81, 0xf9,
+	0x6b, 0x81, 0x19, 0xb6, 0xa7, 0xe7, 0xaf, 0x19, 0x55, 0x40, 0xfe, 0x9a, 0xe7, 0x8d, 0x7f, 0x95,
+	0xc4, 0xe2, 0xbd, 0xd0, 0x71, 0xe9, 0x9a, 0xe3, 0x25, 0x18, 0x03, 0xe6, 0x92, 0x3a, 0xbe, 0xce,
+	0x9f, 0x32, 0x00, 0xa5, 0xe9, 0x4d, 0x85, 0xbe, 0xde, 0xc8, 0x5d, 0x5d, 0xe4, 0xae, 0x20, 0x66,
+	0x8b, 0x57, 0x10, 0x78, 0x7e, 0x89, 0x03, 0x82, 0x4a, 0x34, 0xee, 0x72, 0x0a, 0xb5, 0x68, 0x09,
+	0x82, 0x8e, 0x10, 0xc1, 0x3b, 0x8a, 0x44, 0x81, 0xee, 0x28, 0xe6, 0xcf, 0x7d, 0x47, 0xa1, 0x3b,
+	0xa1, 0x3b, 0x8a, 0x9f, 0x95, 0xf0, 0x06, 0x1a, 0xda, 0x9c, 0x37, 0x8f, 0x77, 0x5a, 0x7a, 0x99,
+	0x4e, 0x71, 0x3f, 0x60, 0x71, 0x1f, 0x49, 0x1f, 0x97, 0x33, 0x2b, 0xa6, 0xd8, 0x38, 0x06, 0xc8,
+	0x2c, 0x16, 0x25, 0xf5, 0x54, 0xe3, 0xd7, 0x30, 0x0c, 0x5a, 0x21, 0x1e, 0xc6, 0x78, 0x92, 0x59,
+	0x9a, 0x7e, 0x7b, 0x33, 0x53, 0x34, 0xdd, 0x5e, 0x62, 0xba, 0x29, 0xd7, 0x95, 0xa9, 0x5f, 0x64,
+	0x93, 0xd7, 0xd6, 0xa5, 0xe7, 0xc6, 0x6f, 0x4a, 0x62, 0x29, 0xd9, 0x74, 0x34, 0xa4, 0xc2, 0x2a,
+	0x97, 0xc6, 0x57, 0x99, 0x8e, 0x7d, 0xfa, 0x21, 0x14, 0x07, 0x94, 0x01, 0xf1, 0x80, 0x04, 0x43,
+	0x94, 0x01, 0x41, 0x46, 0x47, 0x26, 0xc1, 0x62, 0x51, 0x67, 0xf0, 0x68, 0x06, 0x2c, 0x14, 0xdf,
+	0xc3, 0x73, 0xd2, 0x36, 0xf4, 0xe3, 0x8f, 0xec, 0x7e, 0xe8, 0x7a, 0x30, 0x0d, 0x97, 0xbc, 0xa1,
+	0x82, 0x47, 0x9a, 0x2c, 0xb8, 0xaf, 0x71, 0xbc, 0x05, 0x36, 0xf4, 0x7f, 0x13, 0x92, 0x3f, 0x38,
+	0x80, 0x37, 0xbe, 0x84, 0xd7, 0xa2, 0x89, 0xb9, 0x1f, 0x74, 0x44, 0xfe, 0x4f, 0x01, 0xee, 0xfb,
+	0x1c, 0x86, 0x97, 0x16, 0x69, 0x9e, 0xcb, 0x76, 0x9c, 0xb5, 0x72, 0x08, 0x8e, 0xdc, 0x95, 0x27,
+	0x0e, 0x44, 0xda, 0x5c, 0x3e, 0x3c, 0xcb, 0xf9, 0xb0, 0x16, 0xa4, 0xf9, 0x30, 0x8e, 0xbc, 0xb6,
+	0x0f, 0xb9, 0x23, 0xcc, 0x07, 0x32, 0x7b, 0xfa, 0x27, 0x45, 0x3e, 0x09, 0x2d, 0x8d, 0x25, 0xa1,
+	0xd7, 0x85, 0x01, 0xc9, 0x45, 0x34, 0x1a, 0xa0, 0x07, 0x0d, 0x1c, 0xa5, 0x9e, 0x86, 0x91, 0xab,
+	0x2f, 0x10, 0x57, 0x53, 0xc9, 0x91, 0x16, 0xe0, 0xdf, 0x19, 0x20, 0x19, 0x81, 0x7c, 0x5d, 0xef,
+	0x31, 0xdd, 0xd2, 0x99, 0xb4, 0x1a, 0x0e, 0x64, 0xa4, 0x6d, 0x0a, 0x99, 0x74, 0x13, 0x9b, 0x74,
+	0x1f, 0xd1, 0x75, 0x76, 0x3f, 0xfc, 0x28, 0xeb, 0x7e, 0x8e, 0x0f, 0xea, 0x19, 0x4e, 0xfa, 0x6e,
+	0xdc, 0x16, 0xab, 0xf8, 0x97, 0x89, 0xa3, 0x10, 0x12, 0xbb, 0xd1, 0x4b, 0xd7, 0x58, 0x8d, 0x5f,
+	0xc1, 0xd2, 0xe5, 0xfb, 0xd1, 0xb7, 0xf7, 0x59, 0xc2, 0x51, 0x3a, 0x7f, 0xc2, 0x71, 0x05, 0x2a,
+	0x2c, 0xea, 0xc6, 0xf6, 0xc0, 0x90, 0xc9, 0xea, 0x55, 0x19, 0x43, 0xdb, 0x2a, 0x3c, 0xc7, 0x42,
+	0x63, 0xda, 0xf8, 0x3f, 0x13, 0x5e, 0x3c, 0x60, 0x1e, 0x44, 0x2c, 0x04, 0x1a, 0x1d, 0xb1, 0xdd,
+	0xec, 0x86, 0x4f, 0x21, 0x8f, 0x3b, 0xf1, 0x3a, 0x43, 0x2e, 0x14, 0x5e, 0xe1, 0x16, 0x1a, 0x76,
+	0x23, 0x10, 0x15, 0xee, 0x29, 0xbd, 0x46, 0x49, 0xb3, 0xf1, 0xdb, 0x92, 0xb8, 0x30, 0xe9, 0x4b,
+	0xaf, 0x32, 0xfd, 0x3b, 0x18, 0xb5, 0xa8, 0x3b, 0x5d, 0xdb, 0x9f, 0xfb, 0x1f, 0x31, 0xc5, 0xf7,
+	0x60, 0x69, 0x67, 0xa9, 0x1c, 0xba, 0x29, 0x66, 0xa2, 0x98, 0x46, 0x50, 0xdb, 0xbd, 0x74, 0x06,
+	0x53, 0xa0, 0x22, 0x5d, 0x59, 0x82, 0xaa, 0xb1, 0x24, 0x4a, 0x11, 0xcd, 0xb4, 0x64, 0x95, 0xa2,
+	0xc6, 0xcf, 0x4b, 0x62, 0x6d, 0x42, 0x88, 0x7e, 0x0e, 0x69, 0x40, 0xd9, 0x9f, 0x2b, 0x89, 0x93,
+	0xb2, 0x3f, 0x07, 0xa1, 0x57, 0x0f, 0x20, 0x2a, 0x02, 0x1f, 0x94, 0xc9, 0x77, 0x75, 0x0b, 0x71,
+	0x88, 0x84, 0x0a, 0x52, 0x1c, 0x3e, 0x60, 0xd6, 0xad, 0x86, 0x2b, 0x16, 0x74, 0x95, 0x92, 0xa7,
+	0xc7, 0x52, 0x91, 0x1e, 0x61, 0x57, 0xbb, 0x52, 0x01, 0xaf, 0xb8, 0x18, 0x98, 0x67, 0xf8, 0x62,
+	0x2c, 0x43, 0xf8, 0x84, 0xda, 0xf7, 0x15, 0x04, 0xf9, 0x48, 0xc5, 0xfa, 0xcb, 0x82, 0xa0, 0x43,
+	0x44, 0x1a, 0x5f, 0x88, 0x6a, 0x76, 0x8a, 0xf7, 0x3c, 0x66, 0x34, 0xc4, 0x6c, 0xd7, 0x4b, 0xb9,
+	0x9f, 0x9e, 0x71, 0x53, 0xa6, 0x67, 0xfb, 0x65, 0xa2, 0x8b, 0x85, 0x13, 0x7d, 0x9c, 0xff, 0x23,
+	0xb1, 0x39, 0xf9, 0x84, 0x10, 0x12, 0xdc, 0x4a, 0x1a, 0x48, 0x4a, 0x53, 0x43, 0x7d, 0x6e, 0x70,
+	0x56, 0xfa, 0x4e, 0xe3, 0xaf, 0x25, 0xb1, 0x39, 0xf9, 0x44, 0x10, 0x6d, 0xa5, 0x79, 0x4f, 0xd3,
+	0x50, 0xd2, 0x44, 0x86, 0x4a, 0x6f, 0xf1, 0xd8, 0xaf, 0xd3, 0x36, 0x78, 0xee, 0x46, 0x72, 0xb6,
+	0xe7, 0xda, 0x6d, 0x27, 0x02, 0xe3, 0x39, 0xbe, 0x17, 0x8f, 0x34, 0xbf, 0xaf, 0xa7, 0xc2, 0xfd,
+	0x4c, 0x76, 0xd6, 0xca, 0x21, 0x19, 0x01, 0xd7, 0x3b, 0xbe, 0x6f, 0x9f, 0xc0, 0x4f, 0xcb, 0x69,
+	0xf7, 0x88, 0x8c, 0xa0, 0x60, 0x65, 0xf8, 0x50, 0xa3, 0x8d, 0x3f, 0x00, 0x8b, 0x9c, 0x3e, 0x2a,
+	0x9c, 0x32, 0x85, 0xdd, 0xfc, 0x30, 0x93, 0x53, 0x5b, 0x88, 0x3d, 0x7a, 0x45, 0xd6, 0x52, 0xa1,
+	0x36, 0xdb, 0x83, 0x61, 0x7f, 0xe2, 0x49, 0x68, 0xf9, 0x7c, 0x27, 0xa1, 0xb3, 0xa7, 0x4e, 0x42,
+	0x91, 0xf8, 0x16, 0xd3, 0x7b, 0xad, 0xe9, 0x8e, 0xd9, 0x82, 0x14, 0xd9, 0x75, 0xe8, 0x66, 0x04,
+	0xb7, 0x74, 0xc9, 0xca, 0x21, 0xe8, 0x36, 0x78, 0x1e, 0x41, 0xee, 0x94, 0x9e, 0x82, 0x0d, 0xc8,
+	0x07, 0x61, 0xc0, 0xf0, 0x41, 0xd7, 0x83, 0x7c, 0x37, 0xbd, 0x93, 0xe4, 0x91, 0xac, 0xa4, 0x38,
+	0x5f, 0x4b, 0x36, 0xfe, 0x56, 0x12, 0xd5, 0xdc, 0x61, 0x26, 0xba, 0x3b, 0x1f, 0x9a, 0x52, 0xf4,
+	0xd7, 0x63, 0x12, 0x04, 0x71, 0x46, 0x88, 0x07, 0x39, 0xe1, 0x53, 0x99, 0x6c, 0x77, 0x6e, 0x20,
+	0x3a, 0x1c, 0x60, 0x54, 0x29, 0x33, 0x4a, 0x0d, 0x44, 0xb9, 0x4e, 0xe0, 0x8f, 0x73, 0x03, 0x0a,
+	0xa5, 0xaa, 0x1e, 0xb8, 0x8d, 0x05, 0xe1, 0xdc, 0xf3, 0x2e, 0xdb, 0x78, 0x56, 0x77, 0xa1, 0x2c,
+	0x7c, 0x53, 0xd4, 0x92, 0x37, 0xf5, 0xa9, 0xef, 0x3c, 0x9d, 0xfa, 0x2e, 0xb1, 0x0a, 0x9f, 0xfb,
+	0x36, 0x3e, 0x15, 0xb5, 0xe2, 0x99, 0xea, 0x38, 0xb5, 0x94, 0x4e, 0x53, 0x4b, 0x7a, 0x4d, 0x30,
+	0x93, 0xbb, 0x26, 0x80, 0xad, 0x2d, 0xb2, 0x13, 0xd5, 0x6c, 0x36, 0xa5, 0xfc, 0x6c, 0xea, 0xa2,
+	0xdc, 0xf7, 0x98, 0xe6, 0x67, 0x2c, 0x7c, 0x24, 0xc4, 0x79, 0x46, 0x96, 0x40, 0xc4, 0x79, 0x86,
+	0xbb, 0xbe, 0x2f, 0x1d, 0x76, 0xf2, 0x19, 0x8b, 0x9e, 0x1b, 0xbf, 0x9f, 0x11, 0xb5, 0xe2, 0x29,
+	0xeb, 0xf3, 0x6d, 0x0f, 0xdb, 0x45, 0x3e, 0x03, 0x16, 0x50, 0x9a, 0xa7, 0x74, 0x8b, 0x4e, 0xf8,
+	0x1c, 0xa8, 0xd0, 0x24, 0xb2, 0x14, 0xee, 0x69, 0x4d, 0x53, 0xcb, 0x1a, 0xe5, 0x8d, 0x8e, 0xfd,
+	0xeb, 0x6b, 0x5c, 0xba, 0x7c, 0xe6, 0xd1, 0x08, 0xbe, 0xc5, 0xa5, 0x7b, 0xe7, 0x34, 0xcb, 0x66,
+	0x85, 0x39, 0x56, 0xe0, 0x3c, 0x90, 0x14, 0x0a, 0xe4, 0x36, 0x3f, 0x21, 0xed, 0xe3, 0xdb, 0x34,
+	0x4c, 0xdc, 0x24, 0xfd, 0x27, 0x06, 0x92, 0x74, 0x86, 0x20, 0x65, 0xa3, 0xff, 0x3e, 0x79, 0xfa,
+	0xca, 0xb6, 0xc2, 0x13, 0xf0, 0xf8, 0xa2, 0x16, 0xff, 0x0c, 0xea, 0x04, 0x3d, 0x3a, 0x6c, 0x04,
+	0x5a, 0xc4, 0xe7, 0xc6, 0x3f, 0x67, 0xc0, 0x33, 0xb3, 0xca, 0x64, 0xca, 0x4e, 0x81, 0xb7, 0x73,
+	0xff, 0xa7, 0xa2, 0x67, 0xe3, 0x13, 0xb1, 0x88, 0xd5, 0x1e, 0x57, 0x71, 0x65, 0x8a, 0x64, 0xaf,
+	0x4d, 0x74, 0x31, 0xac, 0xfb, 0x28, 0x8e, 0x55, 0x5c, 0xfd, 0x44, 0xff, 0xf9, 0xf1, 0xfa, 0xda,
+	0x69, 0xf1, 0xd1, 0xf8, 0x81, 0x58, 0x92, 0xbe, 0x24, 0xae, 0xa0, 0x0e, 0xe7, 0xce, 0xd3, 0x61,
+	0x55, 0xbf, 0x42, 0x7d, 0x42, 0xfe, 0x81, 0x47, 0x59, 0xbe, 0x0c, 0x3a, 0x71, 0x37, 0x31, 0x1d,
+	0x20, 0xf7, 0x08, 0x40, 0xde, 0x40, 0x71, 0xdb, 0x19, 0x38, 0x6d, 0x24, 0x4d, 0xfe, 0x07, 0x56,
+	0x15, 0xb0, 0x7d, 0x0d, 0x21, 0xf9, 0x62, 0xd4, 0xc1, 0x93, 0x23, 0x6d, 0xbd, 0xb4, 0xad, 0x6f,
+	0xe9, 0xdc, 0x11, 0x4c, 0xdd, 0x6b, 0x93, 0x15, 0xe9, 0x96, 0xee, 0x80, 0x01, 0x7d, 0x4b, 0x97,
+	0x1c, 0xd8, 0xf7, 0xe4, 0x88, 0x8e, 0x69, 0xe9, 0x96, 0xee, 0x88, 0x41, 0x88, 0x91, 0xd7, 0xfe,
+	0x5c, 0x12, 0x95, 0x24, 0xaa, 0x1b, 0xab, 0x62, 0xf9, 0xe0, 0xe0, 0xde, 0x7e, 0x5a, 0x62, 0xd4,
+	0xbf, 0x06, 0x66, 0x59, 0x02, 0x28, 0xdd, 0x5a, 0xf5, 0x12, 0x84, 0xfd, 0x0a, 0x20, 0xe4, 0x9b,
+	0xf5, 0x19, 0xdd, 0xa2, 0x9b, 0xe8, 0x7a, 0x39, 0xed, 0xa0, 0x0f, 0xc3, 0x27, 0xf5, 0x59, 0x63,
+	0x59, 0x2c, 0x1e, 0xdc, 0x07, 0x75, 0xbc, 0xf0, 0xae, 0xcf, 0xe9, 0xe6, 0x01, 0x58, 0x29, 0x96,
+	0xf5, 0x79, 0x63, 0x45, 0x54, 0xa1, 0xb9, 0x37, 0xf4, 0x7b, 0x58, 0x7e, 0xd6, 0x17, 0x48, 0xfe,
+	0xe8, 0x1e, 0xb3, 0x7b, 0xbd, 0x42, 0xdd, 0x3f, 0xba, 0x87, 0xf7, 0xc2, 0xa3, 0xfa, 0xa2, 0x7e,
+	0xf9, 0x87, 0x03, 0xea, 0x4b, 0xec, 0x7d, 0xfc, 0xc5, 0x87, 0x1d, 0x2f, 0xee, 0x0e, 0x5b, 0x98,
+	0xe6, 0xdc, 0xe4, 0x65, 0xb9, 0xee, 0x85, 0xfa, 0xe9, 0x66, 0x12, 0x0c, 0x6f, 0xd2, 0x4a, 0xa5,
+	0xcd, 0x41, 0xab, 0x35, 0x4f, 0xc8, 0x07, 0xff, 0x06, 0xe9, 0x45, 0x7a, 0x0b, 0x69, 0x2d, 0x00,
+	0x00,
 }
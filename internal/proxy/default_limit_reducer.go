@@ -6,6 +6,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -29,6 +30,11 @@ func (r *defaultLimitReducer) Reduce(results []*internalpb.RetrieveResults) (*mi
 		return nil, err
 	}
 
+	// rows are selected by pk when merging the results of shards, order them again
+	if err := typeutil2.SortQueryResults(res, r.req.GetSortKeys()); err != nil {
+		return nil, err
+	}
+
 	return res, nil
 }
 
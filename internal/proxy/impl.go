@@ -30,6 +30,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/federpb"
@@ -2489,6 +2491,12 @@ func (node *Proxy) Search(ctx context.Context, request *milvuspb.SearchRequest)
 		metrics.ProxyReadReqSendBytes.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10)).Add(float64(sentSize))
 		rateCol.Add(metricsinfo.ReadResultThroughput, float64(sentSize))
 	}
+	if qt.nextIteratorToken != "" {
+		// search results carry no token, return it by header of the response
+		if err := grpc.SetHeader(ctx, metadata.Pairs(IteratorTokenHeader, qt.nextIteratorToken)); err != nil {
+			log.Warn("failed to set search iterator token header", zap.Error(err))
+		}
+	}
 	return qt.result, nil
 }
 
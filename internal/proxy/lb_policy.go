@@ -17,9 +17,12 @@ package proxy
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
@@ -27,13 +30,36 @@ import (
 	"github.com/milvus-io/milvus/internal/querycoordv2/params"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
 	"github.com/milvus-io/milvus/pkg/util/retry"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 type executeFunc func(context.Context, UniqueID, types.QueryNodeClient, ...string) error
 
+// errHedgeLost is returned by the execution losing the race of hedged read, whose result is dropped.
+var errHedgeLost = errors.New("hedged read lost the race")
+
+type hedgeClaimKey struct{}
+
+// withHedgeClaim marks the executions under ctx racing for a hedged read,
+// only the first one claiming the result shall deliver it.
+func withHedgeClaim(ctx context.Context) context.Context {
+	return context.WithValue(ctx, hedgeClaimKey{}, atomic.NewBool(false))
+}
+
+// claimHedgedResult returns whether the execution shall deliver its result, always true if not racing for a hedged read.
+func claimHedgedResult(ctx context.Context) bool {
+	claimed, ok := ctx.Value(hedgeClaimKey{}).(*atomic.Bool)
+	if !ok {
+		return true
+	}
+	return claimed.CompareAndSwap(false, true)
+}
+
 type ChannelWorkload struct {
 	db             string
 	collectionName string
@@ -43,6 +69,8 @@ type ChannelWorkload struct {
 	nq             int64
 	exec           executeFunc
 	retryTimes     uint
+	// hedged races the workload on two replicas, see executeHedged
+	hedged bool
 }
 
 type CollectionWorkLoad struct {
@@ -51,6 +79,7 @@ type CollectionWorkLoad struct {
 	collectionID   int64
 	nq             int64
 	exec           executeFunc
+	hedged         bool
 }
 
 type LBPolicy interface {
@@ -62,8 +91,9 @@ type LBPolicy interface {
 }
 
 type LBPolicyImpl struct {
-	balancer  LBBalancer
-	clientMgr shardClientMgr
+	balancer     LBBalancer
+	clientMgr    shardClientMgr
+	hedgeLimiter *ratelimitutil.Limiter
 }
 
 func NewLBPolicyImpl(clientMgr shardClientMgr) *LBPolicyImpl {
@@ -79,9 +109,11 @@ func NewLBPolicyImpl(clientMgr shardClientMgr) *LBPolicyImpl {
 		balancer = NewLookAsideBalancer(clientMgr)
 	}
 
+	maxHedgeRate := ratelimitutil.Limit(params.Params.ProxyCfg.HedgedReadMaxRate.GetAsFloat())
 	return &LBPolicyImpl{
-		balancer:  balancer,
-		clientMgr: clientMgr,
+		balancer:     balancer,
+		clientMgr:    clientMgr,
+		hedgeLimiter: ratelimitutil.NewLimiter(maxHedgeRate, float64(maxHedgeRate)),
 	}
 }
 
@@ -159,34 +191,127 @@ func (lb *LBPolicyImpl) ExecuteWithRetry(ctx context.Context, workload ChannelWo
 			return err
 		}
 
-		client, err := lb.clientMgr.GetClient(ctx, targetNode)
+		if workload.hedged && len(lo.Filter(workload.shardLeaders, func(node int64, _ int) bool {
+			return node != targetNode && !excludeNodes.Contain(node)
+		})) > 0 {
+			return lb.executeHedged(ctx, workload, targetNode, excludeNodes)
+		}
+
+		err = lb.executeOn(ctx, workload, targetNode)
 		if err != nil {
-			log.Warn("search/query channel failed, node not available",
-				zap.Int64("nodeID", targetNode),
-				zap.Error(err))
 			excludeNodes.Insert(targetNode)
+		}
+		return err
+	}, retry.Attempts(workload.retryTimes))
+
+	return err
+}
+
+// executeOn executes the workload on the node, the workload assigned to the node is cancelled after done.
+func (lb *LBPolicyImpl) executeOn(ctx context.Context, workload ChannelWorkload, targetNode int64) error {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", workload.collectionID),
+		zap.String("collectionName", workload.collectionName),
+		zap.String("channelName", workload.channel),
+		zap.Int64("nodeID", targetNode),
+	)
+	// cancel work load which assign to the target node
+	defer lb.balancer.CancelWorkload(targetNode, workload.nq)
+
+	client, err := lb.clientMgr.GetClient(ctx, targetNode)
+	if err != nil {
+		log.Warn("search/query channel failed, node not available", zap.Error(err))
+		return errors.Wrapf(err, "failed to get delegator %d for channel %s", targetNode, workload.channel)
+	}
 
-			// cancel work load which assign to the target node
-			lb.balancer.CancelWorkload(targetNode, workload.nq)
-			return errors.Wrapf(err, "failed to get delegator %d for channel %s", targetNode, workload.channel)
+	err = workload.exec(ctx, targetNode, client, workload.channel)
+	if err != nil {
+		if !errors.Is(err, errHedgeLost) {
+			log.Warn("search/query channel failed", zap.Error(err))
 		}
+		return errors.Wrapf(err, "failed to search/query delegator %d for channel %s", targetNode, workload.channel)
+	}
+	return nil
+}
 
-		err = workload.exec(ctx, targetNode, client, workload.channel)
-		if err != nil {
-			log.Warn("search/query channel failed",
-				zap.Int64("nodeID", targetNode),
-				zap.Error(err))
-			excludeNodes.Insert(targetNode)
-			lb.balancer.CancelWorkload(targetNode, workload.nq)
+// allowHedge returns whether another hedged read is allowed within the max rate.
+func (lb *LBPolicyImpl) allowHedge() bool {
+	maxRate := Params.ProxyCfg.HedgedReadMaxRate.GetAsFloat()
+	if maxRate <= 0 {
+		return false
+	}
+	if lb.hedgeLimiter.Limit() != ratelimitutil.Limit(maxRate) {
+		lb.hedgeLimiter.SetLimit(ratelimitutil.Limit(maxRate))
+	}
+	return lb.hedgeLimiter.AllowN(time.Now(), 1)
+}
+
+type hedgeOutcome struct {
+	node  int64
+	hedge bool
+	err   error
+}
+
+// executeHedged races the workload on the primary node and another replica, the result of the first one responding
+// successfully is taken, and the slower one is cancelled. The hedge is sent only if the primary doesn't respond
+// within the hedge delay, and the hedged reads are bounded by the max rate, so that hedging doesn't double the load.
+func (lb *LBPolicyImpl) executeHedged(ctx context.Context, workload ChannelWorkload, primary int64, excludeNodes typeutil.UniqueSet) error {
+	raceCtx, cancel := context.WithCancel(withHedgeClaim(ctx))
+	defer cancel()
+
+	// buffered for the loser not to block after the race done
+	outcomes := make(chan hedgeOutcome, 2)
+	race := func(node int64, hedge bool) {
+		go func() {
+			outcomes <- hedgeOutcome{node: node, hedge: hedge, err: lb.executeOn(raceCtx, workload, node)}
+		}()
+	}
+	race(primary, false)
+
+	delay := time.NewTimer(Params.ProxyCfg.HedgedReadDelay.GetAsDuration(time.Millisecond))
+	defer delay.Stop()
+	racers := 1
+	var outcome hedgeOutcome
+	select {
+	case outcome = <-outcomes:
+		// primary responded before the hedge sent
+		if outcome.err != nil {
+			excludeNodes.Insert(primary)
+		}
+		return outcome.err
+	case <-delay.C:
+	}
 
-			return errors.Wrapf(err, "failed to search/query delegator %d for channel %s", targetNode, workload.channel)
+	if lb.allowHedge() {
+		candidates := lo.Filter(workload.shardLeaders, func(node int64, _ int) bool {
+			return node != primary && !excludeNodes.Contain(node)
+		})
+		if hedgeNode, err := lb.balancer.SelectNode(ctx, candidates, workload.nq); err == nil {
+			race(hedgeNode, true)
+			racers++
 		}
+	}
 
-		lb.balancer.CancelWorkload(targetNode, workload.nq)
+	var errs []error
+	for i := 0; i < racers; i++ {
+		outcome = <-outcomes
+		if outcome.err == nil {
+			break
+		}
+		excludeNodes.Insert(outcome.node)
+		errs = append(errs, outcome.err)
+	}
+	if racers > 1 {
+		winner := metrics.FailLabel
+		if outcome.err == nil {
+			winner = lo.Ternary(outcome.hedge, metrics.HedgeLabel, metrics.HedgePrimaryLabel)
+		}
+		metrics.ProxyHedgedReadCount.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), winner).Inc()
+	}
+	if outcome.err == nil {
 		return nil
-	}, retry.Attempts(workload.retryTimes))
-
-	return err
+	}
+	return merr.Combine(errs...)
 }
 
 // Execute will execute collection workload in parallel
@@ -212,6 +337,7 @@ func (lb *LBPolicyImpl) Execute(ctx context.Context, workload CollectionWorkLoad
 				nq:             workload.nq,
 				exec:           workload.exec,
 				retryTimes:     uint(len(nodes) * retryOnReplica),
+				hedged:         workload.hedged,
 			})
 			return err
 		})
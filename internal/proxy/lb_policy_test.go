@@ -19,6 +19,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
@@ -358,6 +359,68 @@ func (s *LBPolicySuite) TestExecuteWithRetry() {
 	s.True(merr.IsCanceledOrTimeout(err))
 }
 
+func (s *LBPolicySuite) TestExecuteHedged() {
+	ctx := context.Background()
+	s.mgr.EXPECT().GetClient(mock.Anything, mock.Anything).Return(s.qn, nil)
+	s.lbBalancer.ExpectedCalls = nil
+	s.lbBalancer.EXPECT().SelectNode(mock.Anything, mock.Anything, mock.Anything).Return(1, nil).Once()
+	s.lbBalancer.EXPECT().SelectNode(mock.Anything, mock.Anything, mock.Anything).Return(2, nil).Once()
+	cancelled := atomic.NewInt32(0)
+	s.lbBalancer.EXPECT().CancelWorkload(mock.Anything, mock.Anything).Run(func(node int64, nq int64) {
+		cancelled.Inc()
+	})
+
+	// the primary node 1 is slow, the result of hedged node 2 is taken
+	delivered := atomic.NewInt32(0)
+	executed := typeutil.NewConcurrentSet[int64]()
+	exec := func(ctx context.Context, node UniqueID, qn types.QueryNodeClient, s ...string) error {
+		executed.Insert(node)
+		if node == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		if !claimHedgedResult(ctx) {
+			return errHedgeLost
+		}
+		delivered.Inc()
+		return nil
+	}
+	workload := ChannelWorkload{
+		db:             dbName,
+		collectionName: s.collectionName,
+		collectionID:   s.collectionID,
+		channel:        s.channels[0],
+		shardLeaders:   s.nodes,
+		nq:             1,
+		exec:           exec,
+		retryTimes:     1,
+		hedged:         true,
+	}
+	err := s.lbPolicy.ExecuteWithRetry(ctx, workload)
+	s.NoError(err)
+	s.EqualValues(1, delivered.Load())
+	s.True(executed.Contain(2))
+	// the slower primary is cancelled
+	s.Eventually(func() bool {
+		return cancelled.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// hedged read disabled, executed on the primary only
+	paramtable.Get().Save(Params.ProxyCfg.HedgedReadMaxRate.Key, "0")
+	defer paramtable.Get().Reset(Params.ProxyCfg.HedgedReadMaxRate.Key)
+	s.lbBalancer.ExpectedCalls = nil
+	s.lbBalancer.EXPECT().SelectNode(mock.Anything, mock.Anything, mock.Anything).Return(3, nil).Once()
+	s.lbBalancer.EXPECT().CancelWorkload(mock.Anything, mock.Anything)
+	executed = typeutil.NewConcurrentSet[int64]()
+	workload.exec = func(ctx context.Context, node UniqueID, qn types.QueryNodeClient, s ...string) error {
+		executed.Insert(node)
+		return nil
+	}
+	err = s.lbPolicy.ExecuteWithRetry(ctx, workload)
+	s.NoError(err)
+	s.Equal([]int64{3}, executed.Collect())
+}
+
 func (s *LBPolicySuite) TestExecute() {
 	ctx := context.Background()
 	mockErr := errors.New("mock error")
@@ -6,7 +6,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 func Test_createMilvusReducer(t *testing.T) {
@@ -29,3 +33,39 @@ func Test_createMilvusReducer(t *testing.T) {
 	_, ok = r.(*cntReducer)
 	assert.True(t, ok)
 }
+
+func Test_defaultLimitReducer_SortKeys(t *testing.T) {
+	const (
+		pkFieldID  = common.StartOfUserFieldID
+		ageFieldID = common.StartOfUserFieldID + 1
+	)
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: pkFieldID, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: ageFieldID, Name: "age", DataType: schemapb.DataType_Int64},
+		},
+	}
+	genResult := func(pks []int64, ages []int64) *internalpb.RetrieveResults {
+		return &internalpb.RetrieveResults{
+			Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{
+				getFieldData("pk", pkFieldID, schemapb.DataType_Int64, pks, 1),
+				getFieldData("age", ageFieldID, schemapb.DataType_Int64, ages, 1),
+			},
+		}
+	}
+	req := &internalpb.RetrieveRequest{
+		OutputFieldsId: []int64{pkFieldID, ageFieldID},
+		SortKeys:       []*internalpb.SortKey{{FieldID: ageFieldID}},
+	}
+
+	// each shard is ordered by age, the merge by pk breaks the order
+	r := newDefaultLimitReducer(context.Background(), &queryParams{limit: typeutil.Unlimited}, req, schema, "test")
+	res, err := r.Reduce([]*internalpb.RetrieveResults{
+		genResult([]int64{3, 1}, []int64{10, 30}),
+		genResult([]int64{2, 4}, []int64{20, 40}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2, 1, 4}, res.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	assert.Equal(t, []int64{10, 20, 30, 40}, res.GetFieldsData()[1].GetScalars().GetLongData().GetData())
+}
@@ -43,6 +43,11 @@ import (
 
 const (
 	IgnoreGrowingKey     = "ignore_growing"
+	HedgedReadKey        = "hedged_read"
+	IteratorKey          = "iterator"
+	IteratorTokenKey     = "iterator_token"
+	IteratorTokenHeader  = "iterator-token"
+	PerPartitionTopKKey  = "per_partition_topk"
 	ReduceStopForBestKey = "reduce_stop_for_best"
 	AnnsFieldKey         = "anns_field"
 	TopKKey              = "topk"
@@ -52,6 +57,7 @@ const (
 	RoundDecimalKey      = "round_decimal"
 	OffsetKey            = "offset"
 	LimitKey             = "limit"
+	OrderByKey           = "order_by"
 
 	InsertTaskName                = "InsertTask"
 	CreateCollectionTaskName      = "CreateCollectionTask"
@@ -174,6 +174,45 @@ func parseQueryParams(queryParamsPair []*commonpb.KeyValuePair) (*queryParams, e
 	}, nil
 }
 
+// parseSortKeys gets the sort keys from order_by of queryParamsPair, which is optional.
+// The keys are separated by comma, each in the form of field[:asc|:desc][:nulls_first|:nulls_last].
+func parseSortKeys(queryParamsPair []*commonpb.KeyValuePair, schema *schemapb.CollectionSchema) ([]*internalpb.SortKey, error) {
+	orderByStr, err := funcutil.GetAttrByKeyFromRepeatedKV(OrderByKey, queryParamsPair)
+	// if order_by is not provided
+	if err != nil || strings.TrimSpace(orderByStr) == "" {
+		return nil, nil
+	}
+
+	sortKeys := make([]*internalpb.SortKey, 0)
+	for _, keyStr := range strings.Split(orderByStr, ",") {
+		parts := strings.Split(strings.TrimSpace(keyStr), ":")
+		fieldName := strings.TrimSpace(parts[0])
+		field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+			return field.GetName() == fieldName
+		})
+		if !ok {
+			return nil, merr.WrapErrParameterInvalidMsg("%s [%s] is invalid, field %s not exist", OrderByKey, orderByStr, fieldName)
+		}
+		key := &internalpb.SortKey{FieldID: field.GetFieldID()}
+		for _, option := range parts[1:] {
+			switch strings.ToLower(strings.TrimSpace(option)) {
+			case "asc":
+				key.Descending = false
+			case "desc":
+				key.Descending = true
+			case "nulls_first":
+				key.NullsFirst = true
+			case "nulls_last":
+				key.NullsFirst = false
+			default:
+				return nil, merr.WrapErrParameterInvalidMsg("%s [%s] is invalid, unknown option %s", OrderByKey, orderByStr, option)
+			}
+		}
+		sortKeys = append(sortKeys, key)
+	}
+	return sortKeys, nil
+}
+
 func matchCountRule(outputs []string) bool {
 	return len(outputs) == 1 && strings.ToLower(strings.TrimSpace(outputs[0])) == "count(*)"
 }
@@ -228,6 +267,14 @@ func (t *queryTask) createPlan(ctx context.Context) error {
 	t.RetrieveRequest.OutputFieldsId = outputFieldIDs
 	plan.OutputFieldIds = outputFieldIDs
 	t.plan = plan
+
+	t.RetrieveRequest.SortKeys, err = parseSortKeys(t.request.GetQueryParams(), schema)
+	if err != nil {
+		return err
+	}
+	if err := typeutil2.CheckSortKeys(schema, outputFieldIDs, t.RetrieveRequest.GetSortKeys()); err != nil {
+		return err
+	}
 	log.Ctx(ctx).Debug("translate output fields to field ids",
 		zap.Int64s("OutputFieldsID", t.OutputFieldsId),
 		zap.String("requestType", "query"))
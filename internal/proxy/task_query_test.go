@@ -432,6 +432,34 @@ func TestTaskQuery_functions(t *testing.T) {
 		}
 	})
 
+	t.Run("test parseSortKeys", func(t *testing.T) {
+		schema := &schemapb.CollectionSchema{
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: common.StartOfUserFieldID, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+				{FieldID: common.StartOfUserFieldID + 1, Name: "age", DataType: schemapb.DataType_Int64},
+				{FieldID: common.StartOfUserFieldID + 2, Name: "meta", DataType: schemapb.DataType_JSON},
+			},
+		}
+
+		keys, err := parseSortKeys(nil, schema)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+
+		keys, err = parseSortKeys([]*commonpb.KeyValuePair{{Key: OrderByKey, Value: "age:desc, meta:nulls_first,pk"}}, schema)
+		assert.NoError(t, err)
+		assert.Equal(t, []*internalpb.SortKey{
+			{FieldID: common.StartOfUserFieldID + 1, Descending: true},
+			{FieldID: common.StartOfUserFieldID + 2, NullsFirst: true},
+			{FieldID: common.StartOfUserFieldID},
+		}, keys)
+
+		_, err = parseSortKeys([]*commonpb.KeyValuePair{{Key: OrderByKey, Value: "unknown"}}, schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+		_, err = parseSortKeys([]*commonpb.KeyValuePair{{Key: OrderByKey, Value: "age:up"}}, schema)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
 	t.Run("test reduceRetrieveResults", func(t *testing.T) {
 		const (
 			Dim                  = 8
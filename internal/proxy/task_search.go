@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"math"
 	"regexp"
@@ -21,6 +22,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/planpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/types"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
@@ -59,9 +61,14 @@ type searchTask struct {
 	requery        bool
 
 	userOutputFields []string
+	// scoreFieldRequested is whether the score pseudo field is in the output fields
+	scoreFieldRequested bool
 
-	offset    int64
-	resultBuf *typeutil.ConcurrentSet[*internalpb.SearchResults]
+	offset     int64
+	hedgedRead bool
+	resultBuf  *typeutil.ConcurrentSet[*internalpb.SearchResults]
+	// nextIteratorToken is the base64 encoded token to resume search iterator after this page
+	nextIteratorToken string
 
 	qc   types.QueryCoordClient
 	node types.ProxyComponent
@@ -172,6 +179,11 @@ func parseSearchInfo(searchParamsPair []*commonpb.KeyValuePair) (*planpb.QueryIn
 func getOutputFieldIDs(schema *schemapb.CollectionSchema, outputFields []string) (outputFieldIDs []UniqueID, err error) {
 	outputFieldIDs = make([]UniqueID, 0, len(outputFields))
 	for _, name := range outputFields {
+		// the score pseudo field is filled by query node with the reserved field id
+		if name == common.ScoreFieldName {
+			outputFieldIDs = append(outputFieldIDs, common.ScoreField)
+			continue
+		}
 		hitField := false
 		for _, field := range schema.GetFields() {
 			if field.Name == name {
@@ -236,11 +248,16 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 		return errors.New("not support manually specifying the partition names if partition key mode is used")
 	}
 
-	t.request.OutputFields, t.userOutputFields, err = translateOutputFields(t.request.OutputFields, t.schema, false)
+	t.scoreFieldRequested = lo.Contains(t.request.GetOutputFields(), common.ScoreFieldName)
+	t.request.OutputFields, t.userOutputFields, err = translateOutputFields(lo.Without(t.request.GetOutputFields(), common.ScoreFieldName), t.schema, false)
 	if err != nil {
 		log.Warn("translate output fields failed", zap.Error(err))
 		return err
 	}
+	if t.scoreFieldRequested {
+		t.request.OutputFields = append(t.request.OutputFields, common.ScoreFieldName)
+		t.userOutputFields = append(t.userOutputFields, common.ScoreFieldName)
+	}
 	log.Debug("translate output fields",
 		zap.Strings("output fields", t.request.GetOutputFields()))
 
@@ -258,6 +275,35 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 	}
 	t.SearchRequest.IgnoreGrowing = ignoreGrowing
 
+	// fetch hedged_read from search param
+	for i, kv := range t.request.GetSearchParams() {
+		if kv.GetKey() == HedgedReadKey {
+			t.hedgedRead, err = strconv.ParseBool(kv.GetValue())
+			if err != nil {
+				return errors.New("parse hedged read failed")
+			}
+			t.request.SearchParams = append(t.request.GetSearchParams()[:i], t.request.GetSearchParams()[i+1:]...)
+			break
+		}
+	}
+
+	// fetch search iterator params from search param
+	if err := t.parseIteratorParams(); err != nil {
+		return err
+	}
+
+	// fetch per_partition_topk from search param
+	for i, kv := range t.request.GetSearchParams() {
+		if kv.GetKey() == PerPartitionTopKKey {
+			t.SearchRequest.PerPartitionTopk, err = strconv.ParseBool(kv.GetValue())
+			if err != nil {
+				return merr.WrapErrParameterInvalid("true or false", kv.GetValue(), "value for per_partition_topk is invalid")
+			}
+			t.request.SearchParams = append(t.request.GetSearchParams()[:i], t.request.GetSearchParams()[i+1:]...)
+			break
+		}
+	}
+
 	// Manually update nq if not set.
 	nq, err := getNq(t.request)
 	if err != nil {
@@ -324,7 +370,8 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 			partitionNames = append(partitionNames, hashedPartitionNames...)
 		}
 
-		plan.OutputFieldIds = outputFieldIDs
+		// segcore knows nothing about the score pseudo field
+		plan.OutputFieldIds = lo.Without(outputFieldIDs, common.ScoreField)
 
 		t.SearchRequest.Topk = queryInfo.GetTopk()
 		t.SearchRequest.MetricType = queryInfo.GetMetricType()
@@ -338,6 +385,8 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 		if estimateSize >= requeryThreshold {
 			t.requery = true
 			plan.OutputFieldIds = nil
+			// the score field is filled after requery
+			t.SearchRequest.OutputFieldsId = lo.Without(t.SearchRequest.GetOutputFieldsId(), common.ScoreField)
 		}
 
 		t.SearchRequest.SerializedExprPlan, err = proto.Marshal(plan)
@@ -350,6 +399,25 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 			zap.String("plan", plan.String())) // may be very large if large term passed.
 	}
 
+	if t.SearchRequest.GetIsIterator() || len(t.SearchRequest.GetIteratorToken()) > 0 {
+		if nq != 1 {
+			return merr.WrapErrParameterInvalid(int64(1), nq, "search iterator supports nq 1 only")
+		}
+		if t.offset != 0 {
+			return merr.WrapErrParameterInvalidMsg("search iterator does not support offset")
+		}
+	}
+	if t.SearchRequest.GetPerPartitionTopk() {
+		switch {
+		case len(t.request.GetPartitionNames()) == 0:
+			return merr.WrapErrParameterInvalidMsg("per partition topK requires the partition names to search")
+		case t.SearchRequest.GetIsIterator() || len(t.SearchRequest.GetIteratorToken()) > 0:
+			return merr.WrapErrParameterInvalidMsg("per partition topK could not be combined with search iterator")
+		case t.offset != 0:
+			return merr.WrapErrParameterInvalidMsg("per partition topK does not support offset")
+		}
+	}
+
 	// translate partition name to partition ids. Use regex-pattern to match partition name.
 	t.SearchRequest.PartitionIDs, err = getPartitionIDs(ctx, t.request.GetDbName(), collectionName, partitionNames)
 	if err != nil {
@@ -417,6 +485,7 @@ func (t *searchTask) Execute(ctx context.Context) error {
 		collectionName: t.collectionName,
 		nq:             t.Nq,
 		exec:           t.searchShard,
+		hedged:         t.hedgedRead,
 	})
 	if err != nil {
 		log.Warn("search execute failed", zap.Error(err))
@@ -481,7 +550,11 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 		return err
 	}
 
-	t.result, err = reduceSearchResultData(ctx, validSearchResults, Nq, Topk, MetricType, primaryFieldSchema.DataType, t.offset)
+	if t.SearchRequest.GetPerPartitionTopk() {
+		t.result, err = reducePerPartitionSearchResultData(ctx, validSearchResults, lo.Uniq(t.SearchRequest.GetPartitionIDs()), Nq, Topk, MetricType, primaryFieldSchema.DataType)
+	} else {
+		t.result, err = reduceSearchResultData(ctx, validSearchResults, Nq, Topk, MetricType, primaryFieldSchema.DataType, t.offset)
+	}
 	if err != nil {
 		log.Warn("failed to reduce search results", zap.Error(err))
 		return err
@@ -492,6 +565,13 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 	t.result.CollectionName = t.collectionName
 	t.fillInFieldInfo()
 
+	if t.SearchRequest.GetIsIterator() {
+		if err := t.fillNextIteratorToken(MetricType); err != nil {
+			log.Warn("failed to fill next search iterator token", zap.Error(err))
+			return err
+		}
+	}
+
 	if t.requery {
 		err = t.Requery()
 		if err != nil {
@@ -499,7 +579,13 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 			return err
 		}
 	}
+	if t.scoreFieldRequested {
+		t.fillInScoreField()
+	}
 	t.result.Results.OutputFields = t.userOutputFields
+	if t.SearchRequest.GetPerPartitionTopk() {
+		t.result.Results.OutputFields = append(t.result.Results.OutputFields, common.PartitionIDFieldName)
+	}
 
 	log.Debug("Search post execute done",
 		zap.Int64("collection", t.GetCollectionID()),
@@ -507,6 +593,55 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 	return nil
 }
 
+// parseIteratorParams fetches iterator and iterator_token from search params,
+// the token is the base64 encoded one returned with the previous page.
+func (t *searchTask) parseIteratorParams() error {
+	params := make([]*commonpb.KeyValuePair, 0, len(t.request.GetSearchParams()))
+	for _, kv := range t.request.GetSearchParams() {
+		switch kv.GetKey() {
+		case IteratorKey:
+			isIterator, err := strconv.ParseBool(kv.GetValue())
+			if err != nil {
+				return merr.WrapErrParameterInvalid("true or false", kv.GetValue(), "value for iterator is invalid")
+			}
+			t.SearchRequest.IsIterator = isIterator
+		case IteratorTokenKey:
+			token, err := base64.StdEncoding.DecodeString(kv.GetValue())
+			if err != nil {
+				return merr.WrapErrParameterInvalid("base64 encoded token", kv.GetValue(), "value for iterator_token is invalid")
+			}
+			t.SearchRequest.IteratorToken = token
+		default:
+			params = append(params, kv)
+		}
+	}
+	t.request.SearchParams = params
+	return nil
+}
+
+// fillNextIteratorToken sets the token to resume after the last hit of the reduced page,
+// the tokens of shards are not used since each of them only knows the hits of its own shard.
+func (t *searchTask) fillNextIteratorToken(metricType string) error {
+	prev, err := typeutil2.ParseIteratorToken(t.SearchRequest.GetIteratorToken())
+	if err != nil {
+		return err
+	}
+	// tokens keep distances negated as query nodes do, while reduce restores them
+	scores := t.result.GetResults().GetScores()
+	if !metric.PositivelyRelated(metricType) {
+		scores = lo.Map(scores, func(score float32, _ int) float32 { return -score })
+	}
+	token, err := typeutil2.NextIteratorToken(t.result.GetResults().GetIds(), scores, prev, metricType)
+	if err != nil {
+		return err
+	}
+	t.nextIteratorToken = ""
+	if len(token) > 0 {
+		t.nextIteratorToken = base64.StdEncoding.EncodeToString(token)
+	}
+	return nil
+}
+
 func (t *searchTask) searchShard(ctx context.Context, nodeID int64, qn types.QueryNodeClient, channelIDs ...string) error {
 	searchReq := typeutil.Clone(t.SearchRequest)
 	searchReq.GetBase().TargetID = nodeID
@@ -539,8 +674,11 @@ func (t *searchTask) searchShard(ctx context.Context, nodeID int64, qn types.Que
 			zap.String("reason", result.GetStatus().GetReason()))
 		return fmt.Errorf("fail to Search, QueryNode ID=%d, reason=%s", nodeID, result.GetStatus().GetReason())
 	}
-	t.resultBuf.Insert(result)
 	t.lb.UpdateCostMetrics(nodeID, result.CostAggregation)
+	if !claimHedgedResult(ctx) {
+		return errHedgeLost
+	}
+	t.resultBuf.Insert(result)
 
 	return nil
 }
@@ -582,7 +720,7 @@ func (t *searchTask) Requery() error {
 		DbName:             t.request.GetDbName(),
 		CollectionName:     t.request.GetCollectionName(),
 		Expr:               expr,
-		OutputFields:       t.request.GetOutputFields(),
+		OutputFields:       lo.Without(t.request.GetOutputFields(), common.ScoreFieldName),
 		PartitionNames:     t.request.GetPartitionNames(),
 		GuaranteeTimestamp: t.request.GetGuaranteeTimestamp(),
 		QueryParams:        t.request.GetSearchParams(),
@@ -619,6 +757,9 @@ func (t *searchTask) Requery() error {
 		offsets[pk] = i
 	}
 
+	partitionIDFields := lo.Filter(t.result.GetResults().GetFieldsData(), func(fieldData *schemapb.FieldData, _ int) bool {
+		return fieldData.GetFieldId() == common.PartitionIDField
+	})
 	t.result.Results.FieldsData = make([]*schemapb.FieldData, len(queryResult.GetFieldsData()))
 	for i := 0; i < typeutil.GetSizeOfIDs(ids); i++ {
 		id := typeutil.GetPK(ids, int64(i))
@@ -633,6 +774,8 @@ func (t *searchTask) Requery() error {
 	t.result.Results.FieldsData = lo.Filter(t.result.Results.FieldsData, func(fieldData *schemapb.FieldData, i int) bool {
 		return lo.Contains(t.request.GetOutputFields(), fieldData.GetFieldName())
 	})
+	// the partition id pseudo field of per partition topK search could not be retrieved, keep the searched one
+	t.result.Results.FieldsData = append(t.result.Results.FieldsData, partitionIDFields...)
 
 	return nil
 }
@@ -663,6 +806,31 @@ func (t *searchTask) fillInFieldInfo() {
 	}
 }
 
+// fillInScoreField sets the score pseudo field by the reduced scores,
+// the one filled by query nodes is replaced since the scores of distance metrics are negated there,
+// and it is missing after requery.
+func (t *searchTask) fillInScoreField() {
+	scores := make([]float32, len(t.result.GetResults().GetScores()))
+	copy(scores, t.result.GetResults().GetScores())
+	fieldsData := lo.Filter(t.result.GetResults().GetFieldsData(), func(fieldData *schemapb.FieldData, _ int) bool {
+		return fieldData.GetFieldId() != common.ScoreField
+	})
+	t.result.Results.FieldsData = append(fieldsData, &schemapb.FieldData{
+		Type:      schemapb.DataType_Float,
+		FieldName: common.ScoreFieldName,
+		FieldId:   common.ScoreField,
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_FloatData{
+					FloatData: &schemapb.FloatArray{
+						Data: scores,
+					},
+				},
+			},
+		},
+	})
+}
+
 func (t *searchTask) collectSearchResults(ctx context.Context) ([]*internalpb.SearchResults, error) {
 	select {
 	case <-t.TraceCtx().Done():
@@ -752,6 +920,36 @@ func selectHighestScoreIndex(subSearchResultData []*schemapb.SearchResultData, s
 	return subSearchIdx, resultDataIdx
 }
 
+// reducePerPartitionSearchResultData reduces the hits of each partition separately to keep topk hits for each of them,
+// hits of each query are grouped by partition in the order of partitionIDs, the partition id pseudo field returned by query nodes tells the group.
+func reducePerPartitionSearchResultData(ctx context.Context, subSearchResultData []*schemapb.SearchResultData, partitionIDs []int64, nq int64, topk int64, metricType string, pkType schemapb.DataType) (*milvuspb.SearchResults, error) {
+	partitionDatas := make(map[int64][]*schemapb.SearchResultData)
+	for _, data := range subSearchResultData {
+		split, err := typeutil2.SplitSearchResultDataByPartition(data)
+		if err != nil {
+			return nil, err
+		}
+		for partitionID, partitionData := range split {
+			// query nodes return topk hits of each partition, in total topk * partitions
+			partitionData.TopK = topk
+			partitionDatas[partitionID] = append(partitionDatas[partitionID], partitionData)
+		}
+	}
+
+	reduced := make(map[int64]*schemapb.SearchResultData, len(partitionDatas))
+	for partitionID, datas := range partitionDatas {
+		ret, err := reduceSearchResultData(ctx, datas, nq, topk, metricType, pkType, 0)
+		if err != nil {
+			return nil, err
+		}
+		reduced[partitionID] = ret.GetResults()
+	}
+	return &milvuspb.SearchResults{
+		Status:  merr.Success(),
+		Results: typeutil2.ConcatPartitionSearchResultData(partitionIDs, reduced, nq, topk*int64(len(partitionIDs))),
+	}, nil
+}
+
 func reduceSearchResultData(ctx context.Context, subSearchResultData []*schemapb.SearchResultData, nq int64, topk int64, metricType string, pkType schemapb.DataType, offset int64) (*milvuspb.SearchResults, error) {
 	tr := timerecord.NewTimeRecorder("reduceSearchResultData")
 	defer func() {
@@ -17,6 +17,7 @@ package proxy
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strconv"
 	"strings"
@@ -36,6 +37,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/types"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -2178,3 +2180,134 @@ func TestSearchTask_Requery(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestSearchTask_ScoreField(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector},
+		},
+	}
+
+	outputFieldIDs, err := getOutputFieldIDs(schema, []string{"pk", common.ScoreFieldName})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{100, common.ScoreField}, outputFieldIDs)
+
+	qt := &searchTask{
+		result: &milvuspb.SearchResults{
+			Results: &schemapb.SearchResultData{
+				Scores: []float32{0.5, 0.2},
+				FieldsData: []*schemapb.FieldData{
+					{FieldId: common.ScoreField, FieldName: common.ScoreFieldName},
+					{FieldId: 100, FieldName: "pk"},
+				},
+			},
+		},
+	}
+	qt.fillInScoreField()
+	fieldsData := qt.result.GetResults().GetFieldsData()
+	assert.Len(t, fieldsData, 2)
+	assert.EqualValues(t, 100, fieldsData[0].GetFieldId())
+	assert.EqualValues(t, common.ScoreField, fieldsData[1].GetFieldId())
+	assert.Equal(t, []float32{0.5, 0.2}, fieldsData[1].GetScalars().GetFloatData().GetData())
+}
+
+func TestSearchTask_IteratorToken(t *testing.T) {
+	t.Run("parse params", func(t *testing.T) {
+		prev := []byte(`{"metric_type":"L2","last_score":-1,"last_int_pk":3,"tied_count":1}`)
+		qt := &searchTask{
+			SearchRequest: &internalpb.SearchRequest{},
+			request: &milvuspb.SearchRequest{
+				SearchParams: []*commonpb.KeyValuePair{
+					{Key: IteratorKey, Value: "true"},
+					{Key: IteratorTokenKey, Value: base64.StdEncoding.EncodeToString(prev)},
+					{Key: TopKKey, Value: "2"},
+				},
+			},
+		}
+		assert.NoError(t, qt.parseIteratorParams())
+		assert.True(t, qt.SearchRequest.GetIsIterator())
+		assert.Equal(t, prev, qt.SearchRequest.GetIteratorToken())
+		assert.Equal(t, []*commonpb.KeyValuePair{{Key: TopKKey, Value: "2"}}, qt.request.GetSearchParams())
+
+		qt.request.SearchParams = []*commonpb.KeyValuePair{{Key: IteratorTokenKey, Value: "!"}}
+		assert.ErrorIs(t, qt.parseIteratorParams(), merr.ErrParameterInvalid)
+	})
+
+	t.Run("merge shards", func(t *testing.T) {
+		// each shard returns its own hits, the merged top 3 ends with pk 4 of the second shard
+		shard1 := &schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       3,
+			Scores:     []float32{-1, -4},
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 3}}}},
+			Topks:      []int64{2},
+		}
+		shard2 := &schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       3,
+			Scores:     []float32{-2, -3},
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{2, 4}}}},
+			Topks:      []int64{2},
+		}
+		result, err := reduceSearchResultData(context.TODO(), []*schemapb.SearchResultData{shard1, shard2}, 1, 3, metric.L2, schemapb.DataType_Int64, 0)
+		require.NoError(t, err)
+
+		qt := &searchTask{
+			SearchRequest: &internalpb.SearchRequest{IsIterator: true},
+			result:        result,
+		}
+		require.NoError(t, qt.fillNextIteratorToken(metric.L2))
+		bs, err := base64.StdEncoding.DecodeString(qt.nextIteratorToken)
+		require.NoError(t, err)
+		token, err := typeutil2.ParseIteratorToken(bs)
+		require.NoError(t, err)
+		assert.Equal(t, &typeutil2.IteratorToken{MetricType: metric.L2, LastScore: -3, LastIntPK: 4, TiedCount: 1}, token)
+
+		qt.result = &milvuspb.SearchResults{Results: &schemapb.SearchResultData{}}
+		require.NoError(t, qt.fillNextIteratorToken(metric.L2))
+		assert.Empty(t, qt.nextIteratorToken)
+	})
+}
+
+func TestSearchTask_PerPartitionTopK(t *testing.T) {
+	partitionIDField := func(partitionIDs ...int64) []*schemapb.FieldData {
+		return []*schemapb.FieldData{{
+			Type:      schemapb.DataType_Int64,
+			FieldName: common.PartitionIDFieldName,
+			FieldId:   common.PartitionIDField,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: partitionIDs}},
+			}},
+		}}
+	}
+	// hits of each shard are grouped by partition, topk 2 for each of 2 partitions
+	shard1 := &schemapb.SearchResultData{
+		NumQueries: 1,
+		TopK:       4,
+		Scores:     []float32{0.9, 0.5, 0.8},
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 3, 2}}}},
+		Topks:      []int64{3},
+		FieldsData: partitionIDField(10, 10, 20),
+	}
+	shard2 := &schemapb.SearchResultData{
+		NumQueries: 1,
+		TopK:       4,
+		Scores:     []float32{0.7, 0.6, 0.4},
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{4, 5, 6}}}},
+		Topks:      []int64{3},
+		FieldsData: partitionIDField(10, 20, 20),
+	}
+
+	result, err := reducePerPartitionSearchResultData(context.TODO(), []*schemapb.SearchResultData{shard1, shard2}, []int64{20, 10}, 1, 2, metric.IP, schemapb.DataType_Int64)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 5, 1, 4}, result.GetResults().GetIds().GetIntId().GetData())
+	assert.Equal(t, []float32{0.8, 0.6, 0.9, 0.7}, result.GetResults().GetScores())
+	assert.Equal(t, []int64{4}, result.GetResults().GetTopks())
+	assert.Equal(t, []int64{20, 20, 10, 10}, result.GetResults().GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+	// partition ids of hits not returned
+	shard1.FieldsData = nil
+	_, err = reducePerPartitionSearchResultData(context.TODO(), []*schemapb.SearchResultData{shard1, shard2}, []int64{20, 10}, 1, 2, metric.IP, schemapb.DataType_Int64)
+	assert.ErrorIs(t, err, merr.ErrServiceInternal)
+}
@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// SegmentIndexLoadStatus is the index loading result of a segment in a batch load index request.
+type SegmentIndexLoadStatus struct {
+	SegmentID int64
+	// Skipped is true if the segment is not a sealed segment loaded on the node,
+	// same as loadIndex, it is not regarded as failure
+	Skipped bool
+	Status  *commonpb.Status
+}
+
+// CollectionIndexLoadStatus is the index loading result of a collection in a batch load index request.
+type CollectionIndexLoadStatus struct {
+	CollectionID int64
+	Segments     []*SegmentIndexLoadStatus
+}
+
+// BatchLoadIndex loads indexes of segments of multiple collections in one request, each spec is the
+// load index request of a collection. Segments of all collections share one worker pool capped by
+// paramtable queryNode.batchLoadIndex.maxConcurrency, so a batch never loads more indexes concurrently
+// than the cap no matter how many collections it spans. Failure of a segment doesn't stop the others,
+// the status of each segment is returned in the order of specs and infos.
+func (node *QueryNode) BatchLoadIndex(ctx context.Context, specs []*querypb.LoadSegmentsRequest) ([]*CollectionIndexLoadStatus, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	log := log.Ctx(ctx).With(zap.Int("collectionNum", len(specs)))
+	concurrency := paramtable.Get().QueryNodeCfg.BatchLoadIndexMaxConcurrency.GetAsInt()
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	log.Info("start to batch load index", zap.Int("maxConcurrency", concurrency))
+
+	results := make([]*CollectionIndexLoadStatus, len(specs))
+	group := &errgroup.Group{}
+	group.SetLimit(concurrency)
+	for i, spec := range specs {
+		result := &CollectionIndexLoadStatus{
+			CollectionID: spec.GetCollectionID(),
+			Segments:     make([]*SegmentIndexLoadStatus, len(spec.GetInfos())),
+		}
+		results[i] = result
+
+		segmentIDs := lo.Map(spec.GetInfos(), func(info *querypb.SegmentLoadInfo, _ int) int64 { return info.GetSegmentID() })
+		// segments stay searchable with partially loaded indexes, nothing to roll back
+		loadCtx, load := node.loads.register(ctx, spec.GetCollectionID(), LoadKindIndex, segmentIDs)
+		// the load of collection finishes once its last segment is done,
+		// so releasing a collection doesn't wait for the other collections in the batch
+		remaining := atomic.NewInt32(int32(len(spec.GetInfos())) + 1)
+		done := func() {
+			if remaining.Dec() == 0 {
+				node.loads.finish(load, nil)
+			}
+		}
+
+		for j, info := range spec.GetInfos() {
+			status := &SegmentIndexLoadStatus{SegmentID: info.GetSegmentID()}
+			result.Segments[j] = status
+			if _, ok := node.manager.Segment.GetSealed(info.GetSegmentID()).(*segments.LocalSegment); !ok {
+				status.Skipped = true
+				status.Status = merr.Success()
+				done()
+				continue
+			}
+
+			req := &querypb.LoadSegmentsRequest{
+				Base:         spec.GetBase(),
+				CollectionID: spec.GetCollectionID(),
+				Infos:        []*querypb.SegmentLoadInfo{info},
+				Version:      spec.GetVersion(),
+				LoadScope:    querypb.LoadScope_Index,
+			}
+			group.Go(func() error {
+				defer done()
+				if err := loadCtx.Err(); err != nil {
+					status.Status = merr.Status(err)
+					return nil
+				}
+				status.Status = node.loadIndex(loadCtx, req)
+				return nil
+			})
+		}
+		done()
+	}
+	// tasks never fail, errors are reported by segment status
+	_ = group.Wait()
+
+	failed := 0
+	for _, result := range results {
+		failed += lo.CountBy(result.Segments, func(status *SegmentIndexLoadStatus) bool { return !merr.Ok(status.Status) })
+	}
+	log.Info("batch load index done", zap.Int("failedSegmentNum", failed))
+	return results, nil
+}
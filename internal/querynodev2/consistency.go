@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+// consistencyRequest is the part of search and retrieve requests about consistency.
+type consistencyRequest interface {
+	GetEnforceConsistencyLevel() bool
+	GetConsistencyLevel() commonpb.ConsistencyLevel
+	GetGuaranteeTimestamp() uint64
+}
+
+// enforcedGuaranteeTs computes the guarantee timestamp of request by its consistency level,
+// returns false if the request does not enforce the consistency level at node.
+func (node *QueryNode) enforcedGuaranteeTs(ctx context.Context, req consistencyRequest, channels []string) (uint64, bool, error) {
+	if !req.GetEnforceConsistencyLevel() {
+		return 0, false, nil
+	}
+	log := log.Ctx(ctx).With(zap.String("consistencyLevel", req.GetConsistencyLevel().String()))
+
+	var checkpoint uint64
+	if req.GetConsistencyLevel() == commonpb.ConsistencyLevel_Strong {
+		var err error
+		checkpoint, err = node.latestCheckpoint(channels)
+		if err != nil {
+			log.Warn("failed to get checkpoint of channels", zap.Error(err))
+			return 0, false, err
+		}
+	}
+	guaranteeTs, err := guaranteeTsOfConsistency(req.GetConsistencyLevel(), req.GetGuaranteeTimestamp(), checkpoint, time.Now())
+	if err != nil {
+		log.Warn("invalid consistency level", zap.Error(err))
+		return 0, false, err
+	}
+	log.Debug("guarantee timestamp computed by consistency level", zap.Uint64("guaranteeTimestamp", guaranteeTs))
+	return guaranteeTs, true, nil
+}
+
+// latestCheckpoint returns the latest tsafe among the delegators of channels,
+// channels behind it wait until they catch up.
+func (node *QueryNode) latestCheckpoint(channels []string) (uint64, error) {
+	var latest uint64
+	for _, channel := range channels {
+		sd, ok := node.delegators.Get(channel)
+		if !ok {
+			return 0, merr.WrapErrChannelNotFound(channel, "failed to get shard delegator")
+		}
+		if tsafe := sd.GetTSafe(); tsafe > latest {
+			latest = tsafe
+		}
+	}
+	return latest, nil
+}
+
+// guaranteeTsOfConsistency computes the guarantee timestamp of the consistency level at node:
+// Strong waits for the latest checkpoint of the channels, which comes from the timestamp oracle unlike the clock of node,
+// Bounded for the clock of node staled by common.gracefulTime, Eventually never waits,
+// Session and Customized use the requested guarantee timestamp, which must be set.
+func guaranteeTsOfConsistency(level commonpb.ConsistencyLevel, requested uint64, checkpoint uint64, now time.Time) (uint64, error) {
+	switch level {
+	case commonpb.ConsistencyLevel_Strong:
+		return checkpoint, nil
+	case commonpb.ConsistencyLevel_Bounded:
+		staleness := paramtable.Get().CommonCfg.GracefulTime.GetAsDuration(time.Millisecond)
+		return tsoutil.ComposeTSByTime(now.Add(-staleness), 0), nil
+	case commonpb.ConsistencyLevel_Eventually:
+		return 1, nil
+	case commonpb.ConsistencyLevel_Session, commonpb.ConsistencyLevel_Customized:
+		if requested == 0 {
+			return 0, merr.WrapErrParameterInvalid("guarantee timestamp", "0",
+				fmt.Sprintf("guarantee timestamp is required by consistency level %s", level.String()))
+		}
+		return requested, nil
+	default:
+		return 0, merr.WrapErrParameterInvalid("valid consistency level", level.String(), "invalid consistency level")
+	}
+}
@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestGuaranteeTsOfConsistency(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.CommonCfg.GracefulTime.Key, "3000")
+	defer params.Reset(params.CommonCfg.GracefulTime.Key)
+
+	now := time.Now()
+	requested := tsoutil.ComposeTSByTime(now.Add(-time.Minute), 0)
+
+	checkpoint := tsoutil.ComposeTSByTime(now.Add(-time.Second), 0)
+	ts, err := guaranteeTsOfConsistency(commonpb.ConsistencyLevel_Strong, requested, checkpoint, now)
+	assert.NoError(t, err)
+	assert.Equal(t, checkpoint, ts)
+
+	ts, err = guaranteeTsOfConsistency(commonpb.ConsistencyLevel_Bounded, requested, checkpoint, now)
+	assert.NoError(t, err)
+	assert.Equal(t, tsoutil.ComposeTSByTime(now.Add(-3*time.Second), 0), ts)
+
+	ts, err = guaranteeTsOfConsistency(commonpb.ConsistencyLevel_Eventually, requested, checkpoint, now)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, ts)
+
+	// requested guarantee timestamp used
+	for _, level := range []commonpb.ConsistencyLevel{commonpb.ConsistencyLevel_Session, commonpb.ConsistencyLevel_Customized} {
+		ts, err = guaranteeTsOfConsistency(level, requested, checkpoint, now)
+		assert.NoError(t, err)
+		assert.Equal(t, requested, ts)
+
+		_, err = guaranteeTsOfConsistency(level, 0, checkpoint, now)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	}
+
+	_, err = guaranteeTsOfConsistency(commonpb.ConsistencyLevel(100), requested, checkpoint, now)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestEnforcedGuaranteeTs(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+
+	node := &QueryNode{delegators: typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()}
+	for channel, tsafe := range map[string]uint64{"ch1": 100, "ch2": 200} {
+		sd := delegator.NewMockShardDelegator(t)
+		sd.EXPECT().GetTSafe().Return(tsafe).Maybe()
+		node.delegators.Insert(channel, sd)
+	}
+
+	// not enforced
+	req := &internalpb.SearchRequest{ConsistencyLevel: commonpb.ConsistencyLevel_Strong, GuaranteeTimestamp: 50}
+	_, enforced, err := node.enforcedGuaranteeTs(ctx, req, []string{"ch1", "ch2"})
+	assert.NoError(t, err)
+	assert.False(t, enforced)
+
+	// strong waits for the latest checkpoint of channels
+	req.EnforceConsistencyLevel = true
+	ts, enforced, err := node.enforcedGuaranteeTs(ctx, req, []string{"ch1", "ch2"})
+	assert.NoError(t, err)
+	assert.True(t, enforced)
+	assert.EqualValues(t, 200, ts)
+	assert.EqualValues(t, 50, req.GetGuaranteeTimestamp())
+
+	_, _, err = node.enforcedGuaranteeTs(ctx, req, []string{"ch1", "ch3"})
+	assert.ErrorIs(t, err, merr.ErrChannelNotFound)
+
+	// delegator not required by other levels
+	retrieveReq := &internalpb.RetrieveRequest{
+		EnforceConsistencyLevel: true,
+		ConsistencyLevel:        commonpb.ConsistencyLevel_Session,
+		GuaranteeTimestamp:      50,
+	}
+	ts, enforced, err = node.enforcedGuaranteeTs(ctx, retrieveReq, []string{"ch3"})
+	assert.NoError(t, err)
+	assert.True(t, enforced)
+	assert.EqualValues(t, 50, ts)
+}
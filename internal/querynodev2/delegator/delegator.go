@@ -37,6 +37,7 @@ import (
 	"github.com/milvus-io/milvus/internal/querynodev2/pkoracle"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
 	"github.com/milvus-io/milvus/internal/querynodev2/tsafe"
+	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
@@ -53,9 +54,11 @@ import (
 // ShardDelegator is the interface definition.
 type ShardDelegator interface {
 	Collection() int64
+	Replica() int64
 	Version() int64
 	GetSegmentInfo(readable bool) (sealed []SnapshotItem, growing []SegmentEntry)
 	SyncDistribution(ctx context.Context, entries ...SegmentEntry)
+	// Search and Query append a result carrying scan decisions of segments at last if explain requested.
 	Search(ctx context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error)
 	Query(ctx context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error)
 	QueryStream(ctx context.Context, req *querypb.QueryRequest, srv streamrpc.QueryStreamServer) error
@@ -64,11 +67,19 @@ type ShardDelegator interface {
 	// data
 	ProcessInsert(insertRecords map[int64]*InsertData)
 	ProcessDelete(deleteData []*DeleteData, ts uint64)
-	LoadGrowing(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) error
+	LoadGrowing(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) ([]int64, error)
 	LoadSegments(ctx context.Context, req *querypb.LoadSegmentsRequest) error
 	ReleaseSegments(ctx context.Context, req *querypb.ReleaseSegmentsRequest, force bool) error
 	SyncTargetVersion(newVersion int64, growingInTarget []int64, sealedInTarget []int64, droppedInTarget []int64)
 	GetTargetVersion() int64
+	// GetTargetSegments returns the sealed and growing segments in the latest synced target.
+	GetTargetSegments() (sealed []int64, growing []int64)
+	CompactDeleteBuffer(ctx context.Context) (before DeleteBufferStats, after DeleteBufferStats)
+	GetDeleteStats() DeleteStats
+	// GetPKDeleteTrace returns the segments which may contain the pk and the buffered deletes of it.
+	GetPKDeleteTrace(pk storage.PrimaryKey) PKDeleteTrace
+	// GetTSafe returns the timestamp up to which the channel is consumed and serviceable.
+	GetTSafe() uint64
 
 	// control
 	Serviceable() bool
@@ -136,6 +147,11 @@ func (sd *shardDelegator) Collection() int64 {
 	return sd.collectionID
 }
 
+// Replica returns the id of replica which delegator belongs to.
+func (sd *shardDelegator) Replica() int64 {
+	return sd.replicaID
+}
+
 // Version returns delegator version.
 func (sd *shardDelegator) Version() int64 {
 	return sd.version
@@ -216,6 +232,14 @@ func (sd *shardDelegator) Search(ctx context.Context, req *querypb.SearchRequest
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	// streaming scope only searches the growing segments
+	if req.GetScope() == querypb.DataScope_Streaming {
+		sealed = []SnapshotItem{}
+	}
+	var scanDecisions []*internalpb.SegmentScanDecision
+	if req.GetReq().GetExplain() {
+		scanDecisions = sd.explainScan(sealed, growing, req.GetReq().GetPartitionIDs(), req.GetReq().GetIgnoreGrowing(), req.GetScope())
+	}
 
 	sealedNum := lo.SumBy(sealed, func(item SnapshotItem) int { return len(item.Segments) })
 	log.Debug("search segments...",
@@ -228,16 +252,33 @@ func (sd *shardDelegator) Search(ctx context.Context, req *querypb.SearchRequest
 		return nil, err
 	}
 
-	results, err := executeSubTasks(ctx, tasks, func(ctx context.Context, req *querypb.SearchRequest, worker cluster.Worker) (*internalpb.SearchResults, error) {
+	timeout := paramtable.Get().QueryNodeCfg.WorkerSearchTimeout.GetAsDuration(time.Millisecond)
+	results, timedOut, err := executeSubTasksWithTimeout(ctx, tasks, func(ctx context.Context, req *querypb.SearchRequest, worker cluster.Worker) (*internalpb.SearchResults, error) {
 		return worker.SearchSegments(ctx, req)
-	}, "Search", log)
+	}, timeout, "Search", log)
 	if err != nil {
 		log.Warn("Delegator search failed", zap.Error(err))
 		return nil, err
 	}
+	if len(timedOut) > 0 {
+		log.Warn("Delegator search skipped timed out workers", zap.Int64s("nodeIDs", timedOut))
+		// empty result keeps the partial flag and timed out workers through reduce
+		results = append(results, &internalpb.SearchResults{
+			Status:        merr.Success(),
+			IsPartial:     true,
+			TimedOutNodes: timedOut,
+		})
+	}
 
 	log.Debug("Delegator search done")
 
+	if req.GetReq().GetExplain() {
+		// scan decisions are returned as the last result
+		results = append(results, &internalpb.SearchResults{
+			Status:        merr.Success(),
+			ScanDecisions: scanDecisions,
+		})
+	}
 	return results, nil
 }
 
@@ -279,6 +320,10 @@ func (sd *shardDelegator) QueryStream(ctx context.Context, req *querypb.QueryReq
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	// streaming scope only queries the growing segments
+	if req.GetScope() == querypb.DataScope_Streaming {
+		sealed = []SnapshotItem{}
+	}
 
 	log.Info("query segments...",
 		zap.Int("sealedNum", len(sealed)),
@@ -343,6 +388,14 @@ func (sd *shardDelegator) Query(ctx context.Context, req *querypb.QueryRequest)
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	// streaming scope only queries the growing segments
+	if req.GetScope() == querypb.DataScope_Streaming {
+		sealed = []SnapshotItem{}
+	}
+	var scanDecisions []*internalpb.SegmentScanDecision
+	if req.GetReq().GetExplain() {
+		scanDecisions = sd.explainScan(sealed, growing, req.GetReq().GetPartitionIDs(), req.GetReq().GetIgnoreGrowing(), req.GetScope())
+	}
 
 	sealedNum := lo.SumBy(sealed, func(item SnapshotItem) int { return len(item.Segments) })
 	log.Debug("query segments...",
@@ -365,6 +418,13 @@ func (sd *shardDelegator) Query(ctx context.Context, req *querypb.QueryRequest)
 
 	log.Debug("Delegator Query done")
 
+	if req.GetReq().GetExplain() {
+		// scan decisions are returned as the last result
+		results = append(results, &internalpb.RetrieveResults{
+			Status:        merr.Success(),
+			ScanDecisions: scanDecisions,
+		})
+	}
 	return results, nil
 }
 
@@ -469,6 +529,16 @@ func organizeSubTask[T any](ctx context.Context, req T, sealed []SnapshotItem, g
 func executeSubTasks[T any, R interface {
 	GetStatus() *commonpb.Status
 }](ctx context.Context, tasks []subTask[T], execute func(context.Context, T, cluster.Worker) (R, error), taskType string, log *log.MLogger) ([]R, error) {
+	results, _, err := executeSubTasksWithTimeout(ctx, tasks, execute, 0, taskType, log)
+	return results, err
+}
+
+// executeSubTasksWithTimeout executes sub tasks with a deadline for each target,
+// targets exceeding the timeout are skipped and returned instead of failing all tasks.
+// timeout <= 0 means no deadline for targets.
+func executeSubTasksWithTimeout[T any, R interface {
+	GetStatus() *commonpb.Status
+}](ctx context.Context, tasks []subTask[T], execute func(context.Context, T, cluster.Worker) (R, error), timeout time.Duration, taskType string, log *log.MLogger) ([]R, []int64, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -476,14 +546,31 @@ func executeSubTasks[T any, R interface {
 	wg.Add(len(tasks))
 
 	resultCh := make(chan R, len(tasks))
+	timeoutCh := make(chan int64, len(tasks))
 	errCh := make(chan error, 1)
 	for _, task := range tasks {
 		go func(task subTask[T]) {
 			defer wg.Done()
-			result, err := execute(ctx, task.req, task.worker)
+			taskCtx := ctx
+			if timeout > 0 {
+				var taskCancel context.CancelFunc
+				taskCtx, taskCancel = context.WithTimeout(ctx, timeout)
+				defer taskCancel()
+			}
+			result, err := execute(taskCtx, task.req, task.worker)
 			if result.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
 				err = fmt.Errorf("worker(%d) query failed: %s", task.targetID, result.GetStatus().GetReason())
 			}
+			if err != nil && ctx.Err() == nil && errors.Is(taskCtx.Err(), context.DeadlineExceeded) {
+				log.Warn("sub task exceeds worker timeout, skip its result",
+					zap.String("taskType", taskType),
+					zap.Int64("nodeID", task.targetID),
+					zap.Duration("timeout", timeout),
+					zap.Error(err),
+				)
+				timeoutCh <- task.targetID
+				return
+			}
 			if err != nil {
 				log.Warn("failed to execute sub task",
 					zap.String("taskType", taskType),
@@ -503,13 +590,14 @@ func executeSubTasks[T any, R interface {
 
 	wg.Wait()
 	close(resultCh)
+	close(timeoutCh)
 	select {
 	case err := <-errCh:
 		log.Warn("Delegator execute subTask failed",
 			zap.String("taskType", taskType),
 			zap.Error(err),
 		)
-		return nil, err
+		return nil, nil, err
 	default:
 	}
 
@@ -517,7 +605,11 @@ func executeSubTasks[T any, R interface {
 	for result := range resultCh {
 		results = append(results, result)
 	}
-	return results, nil
+	timedOut := make([]int64, 0)
+	for targetID := range timeoutCh {
+		timedOut = append(timedOut, targetID)
+	}
+	return results, lo.Uniq(timedOut), nil
 }
 
 // waitTSafe returns when tsafe listener notifies a timestamp which meet the guarantee ts.
@@ -571,6 +663,11 @@ func (sd *shardDelegator) waitTSafe(ctx context.Context, ts uint64) error {
 	}
 }
 
+// GetTSafe returns the latest tsafe of the channel, data before it is consumed and searchable.
+func (sd *shardDelegator) GetTSafe() uint64 {
+	return sd.latestTsafe.Load()
+}
+
 // watchTSafe is the worker function to update serviceable timestamp.
 func (sd *shardDelegator) watchTSafe() {
 	defer sd.lifetime.Done()
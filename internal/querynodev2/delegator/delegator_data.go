@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
@@ -297,8 +298,8 @@ func (sd *shardDelegator) addGrowing(entries ...SegmentEntry) {
 	sd.distribution.AddGrowing(entries...)
 }
 
-// LoadGrowing load growing segments locally.
-func (sd *shardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) error {
+// LoadGrowing load growing segments locally, returns the ids of segments loaded.
+func (sd *shardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) ([]int64, error) {
 	log := sd.getLogger(ctx)
 
 	segmentIDs := lo.Map(infos, func(info *querypb.SegmentLoadInfo, _ int) int64 { return info.GetSegmentID() })
@@ -306,7 +307,8 @@ func (sd *shardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.Segm
 	loaded, err := sd.loader.Load(ctx, sd.collectionID, segments.SegmentTypeGrowing, version, infos...)
 	if err != nil {
 		log.Warn("failed to load growing segment", zap.Error(err))
-		return err
+		// the segments loaded before the failure are returned for the caller to roll back
+		return lo.Map(loaded, func(segment segments.Segment, _ int) int64 { return segment.ID() }), err
 	}
 
 	segmentIDs = lo.Map(loaded, func(segment segments.Segment, _ int) int64 { return segment.ID() })
@@ -324,7 +326,7 @@ func (sd *shardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.Segm
 			TargetVersion: sd.distribution.getTargetVersion(),
 		}
 	})...)
-	return nil
+	return segmentIDs, nil
 }
 
 // LoadSegments load segments local or remotely depends on the target node.
@@ -698,3 +700,134 @@ func (sd *shardDelegator) SyncTargetVersion(newVersion int64, growingInTarget []
 func (sd *shardDelegator) GetTargetVersion() int64 {
 	return sd.distribution.getTargetVersion()
 }
+
+// GetTargetSegments returns the segments in the latest target synced from coordinator.
+func (sd *shardDelegator) GetTargetSegments() ([]int64, []int64) {
+	return sd.distribution.GetTargetSegments()
+}
+
+// DeleteBufferStats is the size summary of delete buffer in delegator.
+type DeleteBufferStats struct {
+	DeleteBufferSize int64
+	// number of per-partition delete data fragments in buffer, which compaction merges
+	DeleteBufferFragments int
+}
+
+func (sd *shardDelegator) deleteBufferStats() DeleteBufferStats {
+	stats := DeleteBufferStats{
+		DeleteBufferSize: sd.deleteBuffer.Size(),
+	}
+	for _, entry := range sd.deleteBuffer.ListAfter(0) {
+		stats.DeleteBufferFragments += len(entry.Data)
+	}
+	return stats
+}
+
+// DeleteStats is the summary of deletes buffered in delegator,
+// which are the deletes applied to segments loaded since the safe timestamp of delete buffer.
+type DeleteStats struct {
+	DeleteBufferSize    int64
+	DeleteBufferEntries int
+	// number of buffered delete records, duplicated pks counted repeatedly
+	BufferedDeletes    int64
+	DistinctDeletedPKs int
+	// timestamp range of buffered deletes, zero if no delete buffered
+	MinDeleteTs uint64
+	MaxDeleteTs uint64
+}
+
+// GetDeleteStats returns the summary of buffered deletes,
+// the buffer is only locked to take a snapshot, since buffered items are immutable.
+func (sd *shardDelegator) GetDeleteStats() DeleteStats {
+	sd.deleteMut.Lock()
+	size := sd.deleteBuffer.Size()
+	entries := sd.deleteBuffer.ListAfter(0)
+	sd.deleteMut.Unlock()
+
+	stats := DeleteStats{
+		DeleteBufferSize:    size,
+		DeleteBufferEntries: len(entries),
+	}
+	distinctPKs := make(map[any]struct{})
+	for _, entry := range entries {
+		for _, data := range entry.Data {
+			stats.BufferedDeletes += int64(len(data.DeleteData.Pks))
+			for _, pk := range data.DeleteData.Pks {
+				distinctPKs[pk.GetValue()] = struct{}{}
+			}
+			for _, ts := range data.DeleteData.Tss {
+				if stats.MinDeleteTs == 0 || ts < stats.MinDeleteTs {
+					stats.MinDeleteTs = ts
+				}
+				if ts > stats.MaxDeleteTs {
+					stats.MaxDeleteTs = ts
+				}
+			}
+		}
+	}
+	stats.DistinctDeletedPKs = len(distinctPKs)
+	return stats
+}
+
+// PKDeleteTrace is the delete state of a pk in delegator.
+type PKDeleteTrace struct {
+	// CandidateSegments may contain the pk by their bloom filters, false positive possible
+	CandidateSegments []int64
+	// DeleteTss are the timestamps of buffered deletes of the pk in ascending order,
+	// the deletes before DeleteBufferSafeTs are evicted from buffer and not reported
+	DeleteTss          []uint64
+	DeleteBufferSafeTs uint64
+}
+
+// GetPKDeleteTrace returns the segments which may contain the pk and the buffered deletes of it.
+func (sd *shardDelegator) GetPKDeleteTrace(pk storage.PrimaryKey) PKDeleteTrace {
+	sd.deleteMut.Lock()
+	safeTs := sd.deleteBuffer.SafeTs()
+	entries := sd.deleteBuffer.ListAfter(0)
+	sd.deleteMut.Unlock()
+
+	// pk oracle never fails to get candidates
+	candidates, _ := sd.pkOracle.Get(pk)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	trace := PKDeleteTrace{
+		CandidateSegments:  candidates,
+		DeleteTss:          make([]uint64, 0),
+		DeleteBufferSafeTs: safeTs,
+	}
+	for _, entry := range entries {
+		for _, data := range entry.Data {
+			for i, deletedPK := range data.DeleteData.Pks {
+				if deletedPK.EQ(pk) {
+					trace.DeleteTss = append(trace.DeleteTss, data.DeleteData.Tss[i])
+				}
+			}
+		}
+	}
+	sort.Slice(trace.DeleteTss, func(i, j int) bool { return trace.DeleteTss[i] < trace.DeleteTss[j] })
+	return trace
+}
+
+// CompactDeleteBuffer merges the delete data of the same partition in each buffered entry into tight slices.
+// A new buffer is built first and then swapped in, so in-flight requests are not affected.
+func (sd *shardDelegator) CompactDeleteBuffer(ctx context.Context) (DeleteBufferStats, DeleteBufferStats) {
+	log := sd.getLogger(ctx)
+
+	sd.deleteMut.Lock()
+	defer sd.deleteMut.Unlock()
+
+	before := sd.deleteBufferStats()
+
+	maxSegmentDeleteBuffer := paramtable.Get().QueryNodeCfg.MaxSegmentDeleteBuffer.GetAsInt64()
+	buffer := deletebuffer.NewDoubleCacheDeleteBuffer[*deletebuffer.Item](sd.deleteBuffer.SafeTs(), maxSegmentDeleteBuffer)
+	for _, entry := range sd.deleteBuffer.ListAfter(0) {
+		buffer.Put(entry.Compact())
+	}
+	sd.deleteBuffer = buffer
+
+	after := sd.deleteBufferStats()
+	log.Info("compact delete buffer done",
+		zap.Any("before", before),
+		zap.Any("after", after),
+	)
+	return before, after
+}
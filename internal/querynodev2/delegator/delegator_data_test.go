@@ -268,7 +268,7 @@ func (s *DelegatorDataSuite) TestProcessDelete() {
 	// load growing
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	err := s.delegator.LoadGrowing(ctx, []*querypb.SegmentLoadInfo{
+	_, err := s.delegator.LoadGrowing(ctx, []*querypb.SegmentLoadInfo{
 		{
 			SegmentID:    1001,
 			CollectionID: s.collectionID,
@@ -703,7 +703,7 @@ func (s *DelegatorDataSuite) TestReleaseSegment() {
 	// load growing
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	err := s.delegator.LoadGrowing(ctx, []*querypb.SegmentLoadInfo{
+	_, err := s.delegator.LoadGrowing(ctx, []*querypb.SegmentLoadInfo{
 		{
 			SegmentID:    1001,
 			CollectionID: s.collectionID,
@@ -811,6 +811,95 @@ func (s *DelegatorDataSuite) TestSyncTargetVersion() {
 	s.Equal(int64(5), s.delegator.GetTargetVersion())
 }
 
+func (s *DelegatorDataSuite) TestCompactDeleteBuffer() {
+	s.delegator.ProcessDelete([]*DeleteData{
+		{
+			PartitionID: 500,
+			PrimaryKeys: []storage.PrimaryKey{storage.NewInt64PrimaryKey(10)},
+			Timestamps:  []uint64{10},
+			RowCount:    1,
+		},
+		{
+			PartitionID: 500,
+			PrimaryKeys: []storage.PrimaryKey{storage.NewInt64PrimaryKey(20)},
+			Timestamps:  []uint64{10},
+			RowCount:    1,
+		},
+	}, 10)
+
+	before, after := s.delegator.CompactDeleteBuffer(context.Background())
+	s.Equal(2, before.DeleteBufferFragments)
+	s.Equal(1, after.DeleteBufferFragments)
+	s.Less(after.DeleteBufferSize, before.DeleteBufferSize)
+
+	records := s.delegator.deleteBuffer.ListAfter(0)
+	s.Require().Equal(1, len(records))
+	s.Require().Equal(1, len(records[0].Data))
+	s.Equal(2, len(records[0].Data[0].DeleteData.Pks))
+}
+
+func (s *DelegatorDataSuite) TestGetDeleteStats() {
+	stats := s.delegator.GetDeleteStats()
+	s.Zero(stats.DeleteBufferEntries)
+	s.Zero(stats.BufferedDeletes)
+	s.Zero(stats.MinDeleteTs)
+	s.Zero(stats.MaxDeleteTs)
+
+	s.delegator.ProcessDelete([]*DeleteData{
+		{
+			PartitionID: 500,
+			PrimaryKeys: []storage.PrimaryKey{storage.NewInt64PrimaryKey(10), storage.NewInt64PrimaryKey(20)},
+			Timestamps:  []uint64{10, 12},
+			RowCount:    2,
+		},
+	}, 12)
+	s.delegator.ProcessDelete([]*DeleteData{
+		{
+			PartitionID: 500,
+			PrimaryKeys: []storage.PrimaryKey{storage.NewInt64PrimaryKey(10)},
+			Timestamps:  []uint64{15},
+			RowCount:    1,
+		},
+	}, 15)
+
+	stats = s.delegator.GetDeleteStats()
+	s.Equal(2, stats.DeleteBufferEntries)
+	s.Positive(stats.DeleteBufferSize)
+	s.EqualValues(3, stats.BufferedDeletes)
+	s.Equal(2, stats.DistinctDeletedPKs)
+	s.EqualValues(10, stats.MinDeleteTs)
+	s.EqualValues(15, stats.MaxDeleteTs)
+}
+
+func (s *DelegatorDataSuite) TestGetPKDeleteTrace() {
+	trace := s.delegator.GetPKDeleteTrace(storage.NewInt64PrimaryKey(10))
+	s.Empty(trace.DeleteTss)
+
+	s.delegator.ProcessDelete([]*DeleteData{
+		{
+			PartitionID: 500,
+			PrimaryKeys: []storage.PrimaryKey{storage.NewInt64PrimaryKey(10), storage.NewInt64PrimaryKey(20)},
+			Timestamps:  []uint64{10, 12},
+			RowCount:    2,
+		},
+	}, 12)
+	s.delegator.ProcessDelete([]*DeleteData{
+		{
+			PartitionID: 500,
+			PrimaryKeys: []storage.PrimaryKey{storage.NewInt64PrimaryKey(10)},
+			Timestamps:  []uint64{15},
+			RowCount:    1,
+		},
+	}, 15)
+
+	trace = s.delegator.GetPKDeleteTrace(storage.NewInt64PrimaryKey(10))
+	s.Equal([]uint64{10, 15}, trace.DeleteTss)
+	trace = s.delegator.GetPKDeleteTrace(storage.NewInt64PrimaryKey(20))
+	s.Equal([]uint64{12}, trace.DeleteTss)
+	trace = s.delegator.GetPKDeleteTrace(storage.NewInt64PrimaryKey(30))
+	s.Empty(trace.DeleteTss)
+}
+
 func TestDelegatorDataSuite(t *testing.T) {
 	suite.Run(t, new(DelegatorDataSuite))
 }
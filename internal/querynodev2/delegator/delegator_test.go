@@ -298,6 +298,105 @@ func (s *DelegatorSuite) TestSearch() {
 		s.Equal(3, len(results))
 	})
 
+	s.Run("streaming_only", func() {
+		defer func() {
+			s.workerManager.ExpectedCalls = nil
+		}()
+		worker1 := &cluster.MockWorker{}
+
+		worker1.EXPECT().SearchSegments(mock.Anything, mock.AnythingOfType("*querypb.SearchRequest")).
+			Run(func(_ context.Context, req *querypb.SearchRequest) {
+				s.EqualValues(1, req.Req.GetBase().GetTargetID())
+				s.Equal(querypb.DataScope_Streaming, req.GetScope())
+				s.ElementsMatch([]int64{1004}, req.GetSegmentIDs())
+			}).Return(&internalpb.SearchResults{}, nil)
+
+		s.workerManager.EXPECT().GetWorker(mock.Anything, int64(1)).Return(worker1, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		results, err := s.delegator.Search(ctx, &querypb.SearchRequest{
+			Req:         &internalpb.SearchRequest{Base: commonpbutil.NewMsgBase()},
+			DmlChannels: []string{s.vchannelName},
+			Scope:       querypb.DataScope_Streaming,
+		})
+
+		s.NoError(err)
+		s.Equal(1, len(results))
+	})
+
+	s.Run("explain", func() {
+		defer func() {
+			s.workerManager.ExpectedCalls = nil
+		}()
+		worker1 := &cluster.MockWorker{}
+
+		worker1.EXPECT().SearchSegments(mock.Anything, mock.AnythingOfType("*querypb.SearchRequest")).
+			Return(&internalpb.SearchResults{}, nil)
+
+		s.workerManager.EXPECT().GetWorker(mock.Anything, int64(1)).Return(worker1, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		results, err := s.delegator.Search(ctx, &querypb.SearchRequest{
+			Req:         &internalpb.SearchRequest{Base: commonpbutil.NewMsgBase(), Explain: true},
+			DmlChannels: []string{s.vchannelName},
+			Scope:       querypb.DataScope_Streaming,
+		})
+
+		s.NoError(err)
+		s.Equal(2, len(results))
+		decisions := results[len(results)-1].GetScanDecisions()
+		s.Len(decisions, 5)
+		for _, decision := range decisions {
+			if decision.GetSegmentID() == 1004 {
+				s.False(decision.GetPruned())
+				continue
+			}
+			s.True(decision.GetPruned())
+			s.Equal(ScanPrunedByScope, decision.GetReason())
+		}
+	})
+
+	s.Run("worker_timeout", func() {
+		paramtable.Get().Save(paramtable.Get().QueryNodeCfg.WorkerSearchTimeout.Key, "100")
+		defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.WorkerSearchTimeout.Key)
+		defer func() {
+			s.workerManager.ExpectedCalls = nil
+		}()
+		workers := make(map[int64]*cluster.MockWorker)
+		worker1 := &cluster.MockWorker{}
+		worker2 := &cluster.MockWorker{}
+
+		workers[1] = worker1
+		workers[2] = worker2
+
+		worker1.EXPECT().SearchSegments(mock.Anything, mock.AnythingOfType("*querypb.SearchRequest")).
+			Return(&internalpb.SearchResults{}, nil)
+		worker2.EXPECT().SearchSegments(mock.Anything, mock.AnythingOfType("*querypb.SearchRequest")).
+			RunAndReturn(func(ctx context.Context, _ *querypb.SearchRequest) (*internalpb.SearchResults, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+
+		s.workerManager.EXPECT().GetWorker(mock.Anything, mock.AnythingOfType("int64")).Call.Return(func(_ context.Context, nodeID int64) cluster.Worker {
+			return workers[nodeID]
+		}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		results, err := s.delegator.Search(ctx, &querypb.SearchRequest{
+			Req:         &internalpb.SearchRequest{Base: commonpbutil.NewMsgBase()},
+			DmlChannels: []string{s.vchannelName},
+		})
+
+		s.NoError(err)
+		s.Equal(3, len(results))
+		partial := results[len(results)-1]
+		s.True(partial.GetIsPartial())
+		s.EqualValues([]int64{2}, partial.GetTimedOutNodes())
+	})
+
 	s.Run("partition_not_loaded", func() {
 		defer func() {
 			s.workerManager.ExpectedCalls = nil
@@ -501,6 +600,33 @@ func (s *DelegatorSuite) TestQuery() {
 		s.Equal(3, len(results))
 	})
 
+	s.Run("streaming_only", func() {
+		defer func() {
+			s.workerManager.ExpectedCalls = nil
+		}()
+		worker1 := &cluster.MockWorker{}
+
+		worker1.EXPECT().QuerySegments(mock.Anything, mock.AnythingOfType("*querypb.QueryRequest")).
+			Run(func(_ context.Context, req *querypb.QueryRequest) {
+				s.EqualValues(1, req.Req.GetBase().GetTargetID())
+				s.Equal(querypb.DataScope_Streaming, req.GetScope())
+				s.ElementsMatch([]int64{1004}, req.GetSegmentIDs())
+			}).Return(&internalpb.RetrieveResults{}, nil)
+
+		s.workerManager.EXPECT().GetWorker(mock.Anything, int64(1)).Return(worker1, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		results, err := s.delegator.Query(ctx, &querypb.QueryRequest{
+			Req:         &internalpb.RetrieveRequest{Base: commonpbutil.NewMsgBase()},
+			DmlChannels: []string{s.vchannelName},
+			Scope:       querypb.DataScope_Streaming,
+		})
+
+		s.NoError(err)
+		s.Equal(1, len(results))
+	})
+
 	s.Run("partition_not_loaded", func() {
 		defer func() {
 			s.workerManager.ExpectedCalls = nil
@@ -35,6 +35,8 @@ type DeleteBuffer[T timed] interface {
 	Put(T)
 	ListAfter(uint64) []T
 	SafeTs() uint64
+	// Size returns the total size of buffered entries.
+	Size() int64
 }
 
 func NewDoubleCacheDeleteBuffer[T timed](startTs uint64, maxSize int64) DeleteBuffer[T] {
@@ -83,6 +85,20 @@ func (c *doubleCacheBuffer[T]) ListAfter(ts uint64) []T {
 	return result
 }
 
+// Size implements DeleteBuffer.
+func (c *doubleCacheBuffer[T]) Size() int64 {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	var size int64
+	if c.tail != nil {
+		size += c.tail.Size()
+	}
+	if c.head != nil {
+		size += c.head.Size()
+	}
+	return size
+}
+
 // evict sets head as tail and evicts tail.
 func (c *doubleCacheBuffer[T]) evict(newTs uint64) {
 	c.tail = c.head
@@ -121,6 +137,13 @@ func (c *doubleCacheItem[T]) Put(entry T) error {
 	return nil
 }
 
+// Size returns the total size of entries in cache item.
+func (c *doubleCacheItem[T]) Size() int64 {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.size
+}
+
 // ListAfter returns entries of which ts after provided value.
 func (c *doubleCacheItem[T]) ListAfter(ts uint64) []T {
 	c.mut.RLock()
@@ -24,6 +24,41 @@ func (item *Item) Size() int64 {
 	}, int64(0))
 }
 
+// Compact returns a new item with delete data of same partition merged.
+// The original item is not modified, so it could be used by concurrent readers.
+func (item *Item) Compact() *Item {
+	merged := make(map[int64]*BufferItem)
+	var partitions []int64
+	for _, data := range item.Data {
+		bufferItem, ok := merged[data.PartitionID]
+		if !ok {
+			bufferItem = &BufferItem{PartitionID: data.PartitionID}
+			merged[data.PartitionID] = bufferItem
+			partitions = append(partitions, data.PartitionID)
+		}
+		bufferItem.DeleteData.Pks = append(bufferItem.DeleteData.Pks, data.DeleteData.Pks...)
+		bufferItem.DeleteData.Tss = append(bufferItem.DeleteData.Tss, data.DeleteData.Tss...)
+		bufferItem.DeleteData.RowCount += data.DeleteData.RowCount
+	}
+
+	result := &Item{
+		Ts:   item.Ts,
+		Data: make([]BufferItem, 0, len(partitions)),
+	}
+	for _, partitionID := range partitions {
+		bufferItem := merged[partitionID]
+		// copy into tight slices to release unused capacity
+		pks := make([]storage.PrimaryKey, len(bufferItem.DeleteData.Pks))
+		copy(pks, bufferItem.DeleteData.Pks)
+		tss := make([]uint64, len(bufferItem.DeleteData.Tss))
+		copy(tss, bufferItem.DeleteData.Tss)
+		bufferItem.DeleteData.Pks = pks
+		bufferItem.DeleteData.Tss = tss
+		result.Data = append(result.Data, *bufferItem)
+	}
+	return result
+}
+
 type BufferItem struct {
 	PartitionID int64
 	DeleteData  storage.DeleteData
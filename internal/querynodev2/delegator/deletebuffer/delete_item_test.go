@@ -21,3 +21,46 @@ func TestDeleteBufferItem(t *testing.T) {
 	}
 	item.DeleteData.Tss = []uint64{2000}
 }
+
+func TestDeleteItemCompact(t *testing.T) {
+	item := &Item{
+		Ts: 100,
+		Data: []BufferItem{
+			{
+				PartitionID: 100,
+				DeleteData: storage.DeleteData{
+					Pks:      []storage.PrimaryKey{storage.NewInt64PrimaryKey(1)},
+					Tss:      []uint64{100},
+					RowCount: 1,
+				},
+			},
+			{
+				PartitionID: 200,
+				DeleteData: storage.DeleteData{
+					Pks:      []storage.PrimaryKey{storage.NewInt64PrimaryKey(2)},
+					Tss:      []uint64{100},
+					RowCount: 1,
+				},
+			},
+			{
+				PartitionID: 100,
+				DeleteData: storage.DeleteData{
+					Pks:      []storage.PrimaryKey{storage.NewInt64PrimaryKey(3)},
+					Tss:      []uint64{100},
+					RowCount: 1,
+				},
+			},
+		},
+	}
+
+	compacted := item.Compact()
+	assert.EqualValues(t, 100, compacted.Timestamp())
+	assert.Equal(t, 2, len(compacted.Data))
+	assert.EqualValues(t, 100, compacted.Data[0].PartitionID)
+	assert.EqualValues(t, 2, compacted.Data[0].DeleteData.RowCount)
+	assert.Equal(t, 2, len(compacted.Data[0].DeleteData.Pks))
+	assert.EqualValues(t, 200, compacted.Data[1].PartitionID)
+	assert.Less(t, compacted.Size(), item.Size())
+	// original item shall not be modified
+	assert.Equal(t, 3, len(item.Data))
+}
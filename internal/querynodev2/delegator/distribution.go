@@ -67,6 +67,9 @@ type distribution struct {
 	// quick flag for current snapshot is serviceable
 	serviceable *atomic.Bool
 	offlines    typeutil.Set[int64]
+	// segments in the latest target synced from coordinator
+	sealedInTarget  []int64
+	growingInTarget []int64
 
 	snapshots *typeutil.ConcurrentMap[int64, *snapshot]
 	// current is the snapshot for quick usage for search/query
@@ -150,6 +153,14 @@ func (d *distribution) PeekSegments(readable bool, partitions ...int64) (sealed
 	return
 }
 
+// GetTargetSegments returns the sealed and growing segments in the latest target synced from coordinator.
+func (d *distribution) GetTargetSegments() (sealed []int64, growing []int64) {
+	d.mut.RLock()
+	defer d.mut.RUnlock()
+
+	return append([]int64(nil), d.sealedInTarget...), append([]int64(nil), d.growingInTarget...)
+}
+
 // FinishUsage notifies snapshot one reference is released.
 func (d *distribution) FinishUsage(version int64) {
 	snapshot, ok := d.snapshots.Get(version)
@@ -269,6 +280,9 @@ func (d *distribution) SyncTargetVersion(newVersion int64, growingInTarget []int
 		d.sealedSegments[segmentID] = entry
 	}
 
+	d.sealedInTarget = append([]int64(nil), sealedInTarget...)
+	d.growingInTarget = append([]int64(nil), growingInTarget...)
+
 	oldValue := d.targetVersion.Load()
 	d.targetVersion.Store(newVersion)
 	d.genSnapshot()
@@ -676,6 +676,10 @@ func (s *DistributionSuite) Test_SyncTargetVersion() {
 	s.Len(s1[0].Segments, 3)
 	s.Len(s2, 3)
 
+	targetSealed, targetGrowing := s.dist.GetTargetSegments()
+	s.ElementsMatch([]int64{6}, targetSealed)
+	s.ElementsMatch([]int64{2, 3}, targetGrowing)
+
 	s.dist.serviceable.Store(true)
 	s.dist.SyncTargetVersion(2, []int64{222}, []int64{}, []int64{})
 	s.True(s.dist.Serviceable())
@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// reasons of segments pruned by delegator.
+// pk range and scalar index pruning happen inside segcore per segment,
+// so such segments are reported as scanned.
+const (
+	ScanPrunedByPartition     = "partition"
+	ScanPrunedByTarget        = "not_readable"
+	ScanPrunedByIgnoreGrowing = "ignore_growing"
+	ScanPrunedByScope         = "scope"
+	// segment is changed after the snapshot of request taken
+	ScanPrunedBySnapshot = "not_in_snapshot"
+)
+
+// explainScan reports the scan decisions of all segments in distribution,
+// the selected sealed and growing segments are scanned, others are pruned.
+func (sd *shardDelegator) explainScan(sealed []SnapshotItem, growing []SegmentEntry,
+	partitions []int64, ignoreGrowing bool, scope querypb.DataScope,
+) []*internalpb.SegmentScanDecision {
+	scanned := typeutil.NewUniqueSet()
+	for _, item := range sealed {
+		for _, segment := range item.Segments {
+			scanned.Insert(segment.SegmentID)
+		}
+	}
+	for _, segment := range growing {
+		scanned.Insert(segment.SegmentID)
+	}
+
+	readable := typeutil.NewUniqueSet()
+	readableSealed, readableGrowing := sd.distribution.PeekSegments(true)
+	for _, item := range readableSealed {
+		for _, segment := range item.Segments {
+			readable.Insert(segment.SegmentID)
+		}
+	}
+	for _, segment := range readableGrowing {
+		readable.Insert(segment.SegmentID)
+	}
+
+	requested := typeutil.NewUniqueSet(partitions...)
+	loaded := typeutil.NewUniqueSet(sd.collection.GetPartitions()...)
+	decide := func(segment SegmentEntry, isGrowing bool) *internalpb.SegmentScanDecision {
+		decision := &internalpb.SegmentScanDecision{
+			SegmentID:   segment.SegmentID,
+			PartitionID: segment.PartitionID,
+			Pruned:      true,
+		}
+		switch {
+		case scanned.Contain(segment.SegmentID):
+			decision.Pruned = false
+		case len(partitions) > 0 && !requested.Contain(segment.PartitionID),
+			isGrowing && !loaded.Contain(segment.PartitionID):
+			decision.Reason = ScanPrunedByPartition
+		case !readable.Contain(segment.SegmentID):
+			decision.Reason = ScanPrunedByTarget
+		case isGrowing && ignoreGrowing:
+			decision.Reason = ScanPrunedByIgnoreGrowing
+		case !isGrowing && scope == querypb.DataScope_Streaming:
+			decision.Reason = ScanPrunedByScope
+		default:
+			decision.Reason = ScanPrunedBySnapshot
+		}
+		return decision
+	}
+
+	allSealed, allGrowing := sd.distribution.PeekSegments(false)
+	decisions := make([]*internalpb.SegmentScanDecision, 0)
+	for _, item := range allSealed {
+		for _, segment := range item.Segments {
+			decisions = append(decisions, decide(segment, false))
+		}
+	}
+	for _, segment := range allGrowing {
+		decisions = append(decisions, decide(segment, true))
+	}
+	return decisions
+}
@@ -10,6 +10,8 @@ import (
 
 	querypb "github.com/milvus-io/milvus/internal/proto/querypb"
 
+	storage "github.com/milvus-io/milvus/internal/storage"
+
 	streamrpc "github.com/milvus-io/milvus/internal/util/streamrpc"
 )
 
@@ -99,6 +101,141 @@ func (_c *MockShardDelegator_Collection_Call) RunAndReturn(run func() int64) *Mo
 	return _c
 }
 
+// CompactDeleteBuffer provides a mock function with given fields: ctx
+func (_m *MockShardDelegator) CompactDeleteBuffer(ctx context.Context) (DeleteBufferStats, DeleteBufferStats) {
+	ret := _m.Called(ctx)
+
+	var r0 DeleteBufferStats
+	var r1 DeleteBufferStats
+	if rf, ok := ret.Get(0).(func(context.Context) (DeleteBufferStats, DeleteBufferStats)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) DeleteBufferStats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(DeleteBufferStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) DeleteBufferStats); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(DeleteBufferStats)
+	}
+
+	return r0, r1
+}
+
+// MockShardDelegator_CompactDeleteBuffer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompactDeleteBuffer'
+type MockShardDelegator_CompactDeleteBuffer_Call struct {
+	*mock.Call
+}
+
+// CompactDeleteBuffer is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockShardDelegator_Expecter) CompactDeleteBuffer(ctx interface{}) *MockShardDelegator_CompactDeleteBuffer_Call {
+	return &MockShardDelegator_CompactDeleteBuffer_Call{Call: _e.mock.On("CompactDeleteBuffer", ctx)}
+}
+
+func (_c *MockShardDelegator_CompactDeleteBuffer_Call) Run(run func(ctx context.Context)) *MockShardDelegator_CompactDeleteBuffer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_CompactDeleteBuffer_Call) Return(before DeleteBufferStats, after DeleteBufferStats) *MockShardDelegator_CompactDeleteBuffer_Call {
+	_c.Call.Return(before, after)
+	return _c
+}
+
+func (_c *MockShardDelegator_CompactDeleteBuffer_Call) RunAndReturn(run func(context.Context) (DeleteBufferStats, DeleteBufferStats)) *MockShardDelegator_CompactDeleteBuffer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeleteStats provides a mock function with given fields:
+func (_m *MockShardDelegator) GetDeleteStats() DeleteStats {
+	ret := _m.Called()
+
+	var r0 DeleteStats
+	if rf, ok := ret.Get(0).(func() DeleteStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(DeleteStats)
+	}
+
+	return r0
+}
+
+// MockShardDelegator_GetDeleteStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeleteStats'
+type MockShardDelegator_GetDeleteStats_Call struct {
+	*mock.Call
+}
+
+// GetDeleteStats is a helper method to define mock.On call
+func (_e *MockShardDelegator_Expecter) GetDeleteStats() *MockShardDelegator_GetDeleteStats_Call {
+	return &MockShardDelegator_GetDeleteStats_Call{Call: _e.mock.On("GetDeleteStats")}
+}
+
+func (_c *MockShardDelegator_GetDeleteStats_Call) Run(run func()) *MockShardDelegator_GetDeleteStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_GetDeleteStats_Call) Return(_a0 DeleteStats) *MockShardDelegator_GetDeleteStats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShardDelegator_GetDeleteStats_Call) RunAndReturn(run func() DeleteStats) *MockShardDelegator_GetDeleteStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPKDeleteTrace provides a mock function with given fields: pk
+func (_m *MockShardDelegator) GetPKDeleteTrace(pk storage.PrimaryKey) PKDeleteTrace {
+	ret := _m.Called(pk)
+
+	var r0 PKDeleteTrace
+	if rf, ok := ret.Get(0).(func(storage.PrimaryKey) PKDeleteTrace); ok {
+		r0 = rf(pk)
+	} else {
+		r0 = ret.Get(0).(PKDeleteTrace)
+	}
+
+	return r0
+}
+
+// MockShardDelegator_GetPKDeleteTrace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPKDeleteTrace'
+type MockShardDelegator_GetPKDeleteTrace_Call struct {
+	*mock.Call
+}
+
+// GetPKDeleteTrace is a helper method to define mock.On call
+//   - pk storage.PrimaryKey
+func (_e *MockShardDelegator_Expecter) GetPKDeleteTrace(pk interface{}) *MockShardDelegator_GetPKDeleteTrace_Call {
+	return &MockShardDelegator_GetPKDeleteTrace_Call{Call: _e.mock.On("GetPKDeleteTrace", pk)}
+}
+
+func (_c *MockShardDelegator_GetPKDeleteTrace_Call) Run(run func(pk storage.PrimaryKey)) *MockShardDelegator_GetPKDeleteTrace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(storage.PrimaryKey))
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_GetPKDeleteTrace_Call) Return(_a0 PKDeleteTrace) *MockShardDelegator_GetPKDeleteTrace_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShardDelegator_GetPKDeleteTrace_Call) RunAndReturn(run func(storage.PrimaryKey) PKDeleteTrace) *MockShardDelegator_GetPKDeleteTrace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSegmentInfo provides a mock function with given fields: readable
 func (_m *MockShardDelegator) GetSegmentInfo(readable bool) ([]SnapshotItem, []SegmentEntry) {
 	ret := _m.Called(readable)
@@ -210,6 +347,102 @@ func (_c *MockShardDelegator_GetStatistics_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// GetTSafe provides a mock function with given fields:
+func (_m *MockShardDelegator) GetTSafe() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// MockShardDelegator_GetTSafe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTSafe'
+type MockShardDelegator_GetTSafe_Call struct {
+	*mock.Call
+}
+
+// GetTSafe is a helper method to define mock.On call
+func (_e *MockShardDelegator_Expecter) GetTSafe() *MockShardDelegator_GetTSafe_Call {
+	return &MockShardDelegator_GetTSafe_Call{Call: _e.mock.On("GetTSafe")}
+}
+
+func (_c *MockShardDelegator_GetTSafe_Call) Run(run func()) *MockShardDelegator_GetTSafe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_GetTSafe_Call) Return(_a0 uint64) *MockShardDelegator_GetTSafe_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShardDelegator_GetTSafe_Call) RunAndReturn(run func() uint64) *MockShardDelegator_GetTSafe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTargetSegments provides a mock function with given fields:
+func (_m *MockShardDelegator) GetTargetSegments() ([]int64, []int64) {
+	ret := _m.Called()
+
+	var r0 []int64
+	var r1 []int64
+	if rf, ok := ret.Get(0).(func() ([]int64, []int64)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []int64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() []int64); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]int64)
+		}
+	}
+
+	return r0, r1
+}
+
+// MockShardDelegator_GetTargetSegments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTargetSegments'
+type MockShardDelegator_GetTargetSegments_Call struct {
+	*mock.Call
+}
+
+// GetTargetSegments is a helper method to define mock.On call
+func (_e *MockShardDelegator_Expecter) GetTargetSegments() *MockShardDelegator_GetTargetSegments_Call {
+	return &MockShardDelegator_GetTargetSegments_Call{Call: _e.mock.On("GetTargetSegments")}
+}
+
+func (_c *MockShardDelegator_GetTargetSegments_Call) Run(run func()) *MockShardDelegator_GetTargetSegments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_GetTargetSegments_Call) Return(sealed []int64, growing []int64) *MockShardDelegator_GetTargetSegments_Call {
+	_c.Call.Return(sealed, growing)
+	return _c
+}
+
+func (_c *MockShardDelegator_GetTargetSegments_Call) RunAndReturn(run func() ([]int64, []int64)) *MockShardDelegator_GetTargetSegments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTargetVersion provides a mock function with given fields:
 func (_m *MockShardDelegator) GetTargetVersion() int64 {
 	ret := _m.Called()
@@ -252,17 +485,29 @@ func (_c *MockShardDelegator_GetTargetVersion_Call) RunAndReturn(run func() int6
 }
 
 // LoadGrowing provides a mock function with given fields: ctx, infos, version
-func (_m *MockShardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) error {
+func (_m *MockShardDelegator) LoadGrowing(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) ([]int64, error) {
 	ret := _m.Called(ctx, infos, version)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, []*querypb.SegmentLoadInfo, int64) error); ok {
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*querypb.SegmentLoadInfo, int64) ([]int64, error)); ok {
+		return rf(ctx, infos, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*querypb.SegmentLoadInfo, int64) []int64); ok {
 		r0 = rf(ctx, infos, version)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, []*querypb.SegmentLoadInfo, int64) error); ok {
+		r1 = rf(ctx, infos, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // MockShardDelegator_LoadGrowing_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadGrowing'
@@ -285,12 +530,12 @@ func (_c *MockShardDelegator_LoadGrowing_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockShardDelegator_LoadGrowing_Call) Return(_a0 error) *MockShardDelegator_LoadGrowing_Call {
-	_c.Call.Return(_a0)
+func (_c *MockShardDelegator_LoadGrowing_Call) Return(_a0 []int64, _a1 error) *MockShardDelegator_LoadGrowing_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockShardDelegator_LoadGrowing_Call) RunAndReturn(run func(context.Context, []*querypb.SegmentLoadInfo, int64) error) *MockShardDelegator_LoadGrowing_Call {
+func (_c *MockShardDelegator_LoadGrowing_Call) RunAndReturn(run func(context.Context, []*querypb.SegmentLoadInfo, int64) ([]int64, error)) *MockShardDelegator_LoadGrowing_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -548,6 +793,47 @@ func (_c *MockShardDelegator_ReleaseSegments_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// Replica provides a mock function with given fields:
+func (_m *MockShardDelegator) Replica() int64 {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// MockShardDelegator_Replica_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replica'
+type MockShardDelegator_Replica_Call struct {
+	*mock.Call
+}
+
+// Replica is a helper method to define mock.On call
+func (_e *MockShardDelegator_Expecter) Replica() *MockShardDelegator_Replica_Call {
+	return &MockShardDelegator_Replica_Call{Call: _e.mock.On("Replica")}
+}
+
+func (_c *MockShardDelegator_Replica_Call) Run(run func()) *MockShardDelegator_Replica_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_Replica_Call) Return(_a0 int64) *MockShardDelegator_Replica_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShardDelegator_Replica_Call) RunAndReturn(run func() int64) *MockShardDelegator_Replica_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Search provides a mock function with given fields: ctx, req
 func (_m *MockShardDelegator) Search(ctx context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
 	ret := _m.Called(ctx, req)
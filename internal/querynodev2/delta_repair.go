@@ -0,0 +1,166 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// DeltaWatermark is the timestamp of the latest delete applied to a sealed segment,
+// either by loading deltalogs or by consuming the delete stream, along with the deltalogs loaded.
+type DeltaWatermark struct {
+	SegmentID int64
+	Timestamp uint64
+	// AppliedDeltaLogs are the paths of the deltalogs loaded into the segment
+	AppliedDeltaLogs []string
+}
+
+// DeltaRepair is the delete divergence of a sealed segment from a peer replica.
+type DeltaRepair struct {
+	SegmentID      int64
+	LocalWatermark uint64
+	PeerWatermark  uint64
+	// Missing are the deltalogs the peer has applied but the node hasn't
+	Missing  []*datapb.FieldBinlog
+	Repaired bool
+	Status   *commonpb.Status
+}
+
+// missingDeltaLogs returns the deltalogs the peer has applied while the node hasn't, by the applied log paths of them.
+// The watermarks are not compared, a log ending before the watermark is skipped while loading
+// even if its deletes were never applied.
+func missingDeltaLogs(deltaLogs []*datapb.FieldBinlog, local, peer typeutil.Set[string]) []*datapb.FieldBinlog {
+	missing := make([]*datapb.FieldBinlog, 0)
+	for _, deltaLog := range deltaLogs {
+		binlogs := lo.Filter(deltaLog.GetBinlogs(), func(binlog *datapb.Binlog, _ int) bool {
+			return peer.Contain(binlog.GetLogPath()) && !local.Contain(binlog.GetLogPath())
+		})
+		if len(binlogs) > 0 {
+			missing = append(missing, &datapb.FieldBinlog{
+				FieldID: deltaLog.GetFieldID(),
+				Binlogs: binlogs,
+			})
+		}
+	}
+	return missing
+}
+
+// GetDeltaWatermarks returns the applied delete watermarks of the sealed segments of collection loaded on node,
+// all of them if segmentIDs is empty. The watermarks are to compare with the ones of peer replicas.
+func (node *QueryNode) GetDeltaWatermarks(ctx context.Context, collectionID int64, segmentIDs []int64) ([]*DeltaWatermark, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if node.manager.Collection.Get(collectionID) == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	filters := []segments.SegmentFilter{segments.WithCollection(collectionID), segments.WithType(segments.SegmentTypeSealed)}
+	if len(segmentIDs) > 0 {
+		ids := typeutil.NewSet(segmentIDs...)
+		filters = append(filters, segments.SegmentFilter(func(segment segments.Segment) bool {
+			return ids.Contain(segment.ID())
+		}))
+	}
+	sealed := node.manager.Segment.GetBy(filters...)
+	watermarks := make([]*DeltaWatermark, 0, len(sealed))
+	for _, segment := range sealed {
+		watermark := &DeltaWatermark{
+			SegmentID: segment.ID(),
+			Timestamp: segment.LastDeltaTimestamp(),
+		}
+		if local, ok := segment.(*segments.LocalSegment); ok {
+			watermark.AppliedDeltaLogs = local.AppliedDeltaLogs()
+			sort.Strings(watermark.AppliedDeltaLogs)
+		}
+		watermarks = append(watermarks, watermark)
+	}
+	sort.Slice(watermarks, func(i, j int) bool {
+		return watermarks[i].SegmentID < watermarks[j].SegmentID
+	})
+	return watermarks, nil
+}
+
+// RepairDeltaDivergence compares the deltalogs applied to the segments in req with the ones of a peer replica,
+// and reports the deltalogs of req infos the node is missing. With repair, the missing deltalogs are loaded to catch up
+// regardless of the delete watermark, segments are repaired independently so that a failed one doesn't block others.
+// Segments not loaded on node or without peer watermark are skipped.
+func (node *QueryNode) RepairDeltaDivergence(ctx context.Context, req *querypb.LoadSegmentsRequest, peer []*DeltaWatermark, repair bool) ([]*DeltaRepair, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", req.GetCollectionID()))
+	peerWatermarks := lo.SliceToMap(peer, func(watermark *DeltaWatermark) (int64, *DeltaWatermark) {
+		return watermark.SegmentID, watermark
+	})
+
+	repairs := make([]*DeltaRepair, 0)
+	for _, info := range req.GetInfos() {
+		peerWatermark, ok := peerWatermarks[info.GetSegmentID()]
+		if !ok {
+			continue
+		}
+		segment, ok := node.manager.Segment.GetSealed(info.GetSegmentID()).(*segments.LocalSegment)
+		if !ok {
+			continue
+		}
+		result := &DeltaRepair{
+			SegmentID:      info.GetSegmentID(),
+			LocalWatermark: segment.LastDeltaTimestamp(),
+			PeerWatermark:  peerWatermark.Timestamp,
+			Status:         merr.Success(),
+		}
+		result.Missing = missingDeltaLogs(info.GetDeltalogs(),
+			typeutil.NewSet(segment.AppliedDeltaLogs()...), typeutil.NewSet(peerWatermark.AppliedDeltaLogs...))
+		repairs = append(repairs, result)
+		if len(result.Missing) == 0 {
+			continue
+		}
+
+		log.Warn("segment misses delta logs applied by peer replica",
+			zap.Int64("segmentID", result.SegmentID),
+			zap.Uint64("localWatermark", result.LocalWatermark),
+			zap.Uint64("peerWatermark", result.PeerWatermark),
+			zap.Int("missingLogNum", lo.SumBy(result.Missing, func(deltaLog *datapb.FieldBinlog) int { return len(deltaLog.GetBinlogs()) })),
+		)
+		if !repair {
+			continue
+		}
+		if err := node.loader.ReloadDeltaLogs(ctx, segment, result.Missing); err != nil {
+			log.Warn("failed to repair delta logs of segment", zap.Int64("segmentID", result.SegmentID), zap.Error(err))
+			result.Status = merr.Status(err)
+			continue
+		}
+		result.Repaired = true
+	}
+	return repairs, nil
+}
@@ -0,0 +1,53 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestMissingDeltaLogs(t *testing.T) {
+	deltaLogs := []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{
+		{LogPath: "1", TimestampFrom: 1, TimestampTo: 100},
+		{LogPath: "2", TimestampFrom: 101, TimestampTo: 200},
+		{LogPath: "3", TimestampFrom: 201, TimestampTo: 300},
+		// legacy log without timestamp range
+		{LogPath: "4"},
+	}}}
+	paths := func(missing []*datapb.FieldBinlog) []string {
+		result := make([]string, 0)
+		for _, deltaLog := range missing {
+			for _, binlog := range deltaLog.GetBinlogs() {
+				result = append(result, binlog.GetLogPath())
+			}
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"2"}, paths(missingDeltaLogs(deltaLogs, typeutil.NewSet("1"), typeutil.NewSet("1", "2"))))
+	assert.Equal(t, []string{"2", "4"}, paths(missingDeltaLogs(deltaLogs, typeutil.NewSet("1", "3"), typeutil.NewSet("1", "2", "3", "4"))))
+	// a log skipped by the node is missing even if the node has applied later ones
+	assert.Equal(t, []string{"1"}, paths(missingDeltaLogs(deltaLogs, typeutil.NewSet("2", "3"), typeutil.NewSet("1", "2", "3"))))
+	// the node has applied all the logs of peer
+	assert.Empty(t, missingDeltaLogs(deltaLogs, typeutil.NewSet("1", "2"), typeutil.NewSet("1", "2")))
+	assert.Empty(t, missingDeltaLogs(deltaLogs, typeutil.NewSet("1", "2", "3"), typeutil.NewSet("2")))
+}
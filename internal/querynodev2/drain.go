@@ -0,0 +1,115 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// drainCheckInterval is the interval to check whether the in-flight requests of a draining node are finished.
+const drainCheckInterval = 100 * time.Millisecond
+
+// acceptsNewRequest checks the node is healthy and not draining before accepting new searches and queries of channels,
+// the proxy retries the rejected ones on other replicas since service not ready is retriable.
+func (node *QueryNode) acceptsNewRequest(stateCode commonpb.StateCode) error {
+	if err := merr.IsHealthy(stateCode); err != nil {
+		return err
+	}
+	if node.draining.Load() {
+		return merr.WrapErrServiceNotReady(paramtable.GetRole(), paramtable.GetNodeID(), "Draining")
+	}
+	return nil
+}
+
+// DrainStatus is the drain state and in-flight requests of the node.
+type DrainStatus struct {
+	Draining         bool
+	InflightSearches int64
+	InflightQueries  int64
+	// Idle is true if the node is draining and all in-flight searches and queries finished
+	Idle bool
+}
+
+// SetDraining makes the node stop accepting new searches and queries of channels, or accept them again,
+// for graceful decommissioning. The in-flight ones and the sub-requests of other delegators are still served.
+func (node *QueryNode) SetDraining(ctx context.Context, draining bool) error {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
+	previous := node.draining.Swap(draining)
+	log.Ctx(ctx).Info("drain state of query node set",
+		zap.Bool("draining", draining),
+		zap.Bool("previous", previous),
+	)
+	return nil
+}
+
+// GetDrainStatus returns whether the node is draining and its in-flight searches and queries.
+func (node *QueryNode) GetDrainStatus(ctx context.Context) (*DrainStatus, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.drainStatus(), nil
+}
+
+func (node *QueryNode) drainStatus() *DrainStatus {
+	status := &DrainStatus{
+		Draining:         node.draining.Load(),
+		InflightSearches: node.inflightSearches.Load(),
+		InflightQueries:  node.inflightQueries.Load(),
+	}
+	status.Idle = status.Draining && status.InflightSearches == 0 && status.InflightQueries == 0
+	return status
+}
+
+// WaitDrained blocks until the draining node is idle, fails if the node is not draining or stops draining,
+// or the context is done.
+func (node *QueryNode) WaitDrained(ctx context.Context) (*DrainStatus, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for {
+		status := node.drainStatus()
+		if !status.Draining {
+			return nil, merr.WrapErrServiceUnavailable("query node is not draining", "drain canceled")
+		}
+		if status.Idle {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// validateFacet checks the facet spec of search request against the collection schema,
+// only numeric scalar fields could be bucketed by value ranges.
+func validateFacet(spec *internalpb.FacetSpec, schema *schemapb.CollectionSchema) error {
+	field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == spec.GetFieldID()
+	})
+	if !ok {
+		return merr.WrapErrParameterInvalidMsg("facet field %d not found in collection", spec.GetFieldID())
+	}
+	if !typeutil.IsIntegerType(field.GetDataType()) && !typeutil.IsFloatingType(field.GetDataType()) {
+		return merr.WrapErrParameterInvalid("numeric scalar field", field.GetDataType().String(),
+			fmt.Sprintf("facet field %d could not be bucketed by value ranges", spec.GetFieldID()))
+	}
+
+	boundaries := spec.GetBoundaries()
+	if len(boundaries) == 0 {
+		return merr.WrapErrParameterInvalidMsg("facet requires at least one bucket boundary")
+	}
+	if maxBuckets := paramtable.Get().QueryNodeCfg.FacetMaxBuckets.GetAsInt(); len(boundaries)+1 > maxBuckets {
+		return merr.WrapErrParameterInvalid(maxBuckets, len(boundaries)+1, "too many facet buckets")
+	}
+	for i, boundary := range boundaries {
+		if math.IsNaN(boundary) || math.IsInf(boundary, 0) {
+			return merr.WrapErrParameterInvalidMsg("facet bucket boundary %v is not a finite number", boundary)
+		}
+		if i > 0 && boundary <= boundaries[i-1] {
+			return merr.WrapErrParameterInvalidMsg("facet bucket boundaries must be strictly ascending, got %v after %v", boundary, boundaries[i-1])
+		}
+	}
+
+	if spec.GetTopHits() < 0 {
+		return merr.WrapErrParameterInvalid(">= 0", spec.GetTopHits(), "invalid facet top hits")
+	}
+	maxFactor := paramtable.Get().QueryNodeCfg.FacetMaxCandidateFactor.GetAsInt64()
+	if spec.GetCandidateFactor() < 0 || spec.GetCandidateFactor() > maxFactor {
+		return merr.WrapErrParameterInvalid(fmt.Sprintf("[0, %d]", maxFactor), spec.GetCandidateFactor(), "invalid facet candidate factor")
+	}
+	return nil
+}
+
+// facetBucketOf returns the index of the bucket the value falls into,
+// bucket i covers [boundaries[i-1], boundaries[i]).
+func facetBucketOf(boundaries []float64, value float64) int {
+	return sort.Search(len(boundaries), func(i int) bool {
+		return boundaries[i] > value
+	})
+}
+
+// newFacetBuckets returns the empty buckets of each query.
+func newFacetBuckets(spec *internalpb.FacetSpec, nq int64) []*internalpb.FacetBucket {
+	boundaries := spec.GetBoundaries()
+	buckets := make([]*internalpb.FacetBucket, 0, int(nq)*(len(boundaries)+1))
+	for i := int64(0); i < nq; i++ {
+		for j := 0; j <= len(boundaries); j++ {
+			bucket := &internalpb.FacetBucket{
+				QueryIndex: i,
+				Lower:      math.Inf(-1),
+				Upper:      math.Inf(1),
+			}
+			if j > 0 {
+				bucket.Lower = boundaries[j-1]
+			}
+			if j < len(boundaries) {
+				bucket.Upper = boundaries[j]
+			}
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// facetSearch buckets the hits of each query by the value of facet field,
+// the final hits are bucketed unless a larger candidate set is requested by candidate factor,
+// whose candidates are searched over the index again regardless of how the final hits are searched.
+func (node *QueryNode) facetSearch(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string, resp *internalpb.SearchResults) ([]*internalpb.FacetBucket, error) {
+	spec := req.GetReq().GetFacet()
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.String("channel", channel),
+		zap.Int64("facetFieldID", spec.GetFieldID()),
+		zap.Int64("candidateFactor", spec.GetCandidateFactor()),
+	)
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := resp
+	if spec.GetCandidateFactor() > 1 {
+		candidateReq, err := facetCandidateRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		if maxNQ := paramtable.Get().QueryNodeCfg.MaxNQPerBatch.GetAsInt64(); maxNQ > 0 && req.GetReq().GetNq() > maxNQ {
+			candidates, _, err = node.searchDelegatorInNQBatches(ctx, sd, candidateReq, maxNQ)
+		} else {
+			candidates, _, err = node.searchDelegator(ctx, sd, candidateReq)
+		}
+		if err != nil {
+			log.Warn("failed to search facet candidates", zap.Error(err))
+			return nil, err
+		}
+		if req.GetReq().GetEnableScoreThreshold() {
+			if err := segments.FilterSearchResultsByScore(candidates, req.GetReq().GetScoreThreshold()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	decoded, err := segments.DecodeSearchResults([]*internalpb.SearchResults{candidates})
+	if err != nil {
+		return nil, err
+	}
+	buckets := newFacetBuckets(spec, req.GetReq().GetNq())
+	if len(decoded) == 0 {
+		return buckets, nil
+	}
+	data := decoded[0]
+
+	// retrieve facet field values of hits
+	pks := &schemapb.IDs{}
+	seen := make(map[any]struct{})
+	for i := 0; i < typeutil.GetSizeOfIDs(data.GetIds()); i++ {
+		pk := typeutil.GetPK(data.GetIds(), int64(i))
+		if _, ok := seen[pk]; !ok {
+			seen[pk] = struct{}{}
+			typeutil.AppendPKs(pks, pk)
+		}
+	}
+	values := make(map[any]float64, len(seen))
+	if len(seen) > 0 {
+		plan, err := pkTermPlan(pkField, pks)
+		if err != nil {
+			return nil, err
+		}
+		plan.OutputFieldIds = []int64{pkField.GetFieldID(), spec.GetFieldID()}
+		queryReq, err := filterQueryRequest(req, plan, channel)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := node.queryDelegatorForFilter(ctx, sd, queryReq, collection.Schema())
+		if err != nil {
+			log.Warn("failed to retrieve facet field values of hits", zap.Error(err))
+			return nil, err
+		}
+		if fieldData, ok := lo.Find(rows.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+			return fieldData.GetFieldId() == spec.GetFieldID()
+		}); ok {
+			for i, value := range numericFieldValues(fieldData) {
+				values[typeutil.GetPK(rows.GetIds(), int64(i))] = value
+			}
+		}
+	}
+
+	// hits are ordered by score in each query, the first ones of each bucket are its top hits
+	bucketNum := len(spec.GetBoundaries()) + 1
+	var offset int64
+	for i, topk := range data.GetTopks() {
+		for j := offset; j < offset+topk; j++ {
+			pk := typeutil.GetPK(data.GetIds(), j)
+			value, ok := values[pk]
+			if !ok {
+				// deleted after searched
+				continue
+			}
+			bucket := buckets[i*bucketNum+facetBucketOf(spec.GetBoundaries(), value)]
+			bucket.Count++
+			if int64(len(bucket.GetTopHitScores())) < spec.GetTopHits() {
+				if bucket.TopHitIds == nil {
+					bucket.TopHitIds = &schemapb.IDs{}
+				}
+				typeutil.AppendPKs(bucket.TopHitIds, pk)
+				bucket.TopHitScores = append(bucket.TopHitScores, data.GetScores()[j])
+			}
+		}
+		offset += topk
+	}
+	log.Debug("search hits bucketed by facet field", zap.Int("hitNum", len(seen)))
+	return buckets, nil
+}
+
+// facetCandidateRequest widens the topK of search request by the facet candidate factor.
+func facetCandidateRequest(req *querypb.SearchRequest) (*querypb.SearchRequest, error) {
+	factor := req.GetReq().GetFacet().GetCandidateFactor()
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	queryInfo := plan.GetVectorAnns().GetQueryInfo()
+	if queryInfo == nil {
+		return nil, merr.WrapErrParameterInvalidMsg("facet candidates require vector search plan")
+	}
+	queryInfo.Topk *= factor
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.Topk *= factor
+	return cloned, nil
+}
+
+// numericFieldValues returns the values of numeric scalar field data as float64.
+func numericFieldValues(fieldData *schemapb.FieldData) []float64 {
+	scalars := fieldData.GetScalars()
+	switch fieldData.GetType() {
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		return lo.Map(scalars.GetIntData().GetData(), func(v int32, _ int) float64 { return float64(v) })
+	case schemapb.DataType_Int64:
+		return lo.Map(scalars.GetLongData().GetData(), func(v int64, _ int) float64 { return float64(v) })
+	case schemapb.DataType_Float:
+		return lo.Map(scalars.GetFloatData().GetData(), func(v float32, _ int) float64 { return float64(v) })
+	case schemapb.DataType_Double:
+		return scalars.GetDoubleData().GetData()
+	default:
+		return nil
+	}
+}
+
+// mergeFacetBuckets merges the facet buckets of channels, counts are summed and top hits are selected again.
+// Channels without any segment return no bucket.
+func mergeFacetBuckets(results []*internalpb.SearchResults, topHits int64) []*internalpb.FacetBucket {
+	type bucketKey struct {
+		queryIndex int64
+		lower      float64
+	}
+	type hit struct {
+		pk    any
+		score float32
+	}
+	merged := make(map[bucketKey]*internalpb.FacetBucket)
+	hits := make(map[bucketKey][]hit)
+	for _, result := range results {
+		for _, bucket := range result.GetFacetBuckets() {
+			key := bucketKey{queryIndex: bucket.GetQueryIndex(), lower: bucket.GetLower()}
+			if _, ok := merged[key]; !ok {
+				merged[key] = &internalpb.FacetBucket{
+					QueryIndex: bucket.GetQueryIndex(),
+					Lower:      bucket.GetLower(),
+					Upper:      bucket.GetUpper(),
+				}
+			}
+			merged[key].Count += bucket.GetCount()
+			for i, score := range bucket.GetTopHitScores() {
+				hits[key] = append(hits[key], hit{pk: typeutil.GetPK(bucket.GetTopHitIds(), int64(i)), score: score})
+			}
+		}
+	}
+
+	buckets := lo.Values(merged)
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].GetQueryIndex() != buckets[j].GetQueryIndex() {
+			return buckets[i].GetQueryIndex() < buckets[j].GetQueryIndex()
+		}
+		return buckets[i].GetLower() < buckets[j].GetLower()
+	})
+	for _, bucket := range buckets {
+		key := bucketKey{queryIndex: bucket.GetQueryIndex(), lower: bucket.GetLower()}
+		bucketHits := hits[key]
+		// ordered as segcore does, score descending and pk ascending for tied scores
+		sort.SliceStable(bucketHits, func(a, b int) bool {
+			if bucketHits[a].score != bucketHits[b].score {
+				return bucketHits[a].score > bucketHits[b].score
+			}
+			return typeutil.ComparePK(bucketHits[a].pk, bucketHits[b].pk)
+		})
+		if int64(len(bucketHits)) > topHits {
+			bucketHits = bucketHits[:topHits]
+		}
+		for _, hit := range bucketHits {
+			if bucket.TopHitIds == nil {
+				bucket.TopHitIds = &schemapb.IDs{}
+			}
+			typeutil.AppendPKs(bucket.TopHitIds, hit.pk)
+			bucket.TopHitScores = append(bucket.TopHitScores, hit.score)
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,65 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+)
+
+func TestFacetBucketOf(t *testing.T) {
+	boundaries := []float64{10, 20}
+	assert.Equal(t, 0, facetBucketOf(boundaries, -1))
+	assert.Equal(t, 1, facetBucketOf(boundaries, 10))
+	assert.Equal(t, 1, facetBucketOf(boundaries, 19.9))
+	assert.Equal(t, 2, facetBucketOf(boundaries, 20))
+	assert.Equal(t, 2, facetBucketOf(boundaries, 100))
+}
+
+func TestMergeFacetBuckets(t *testing.T) {
+	spec := &internalpb.FacetSpec{Boundaries: []float64{10}, TopHits: 2}
+	bucketsOf := func(counts []int64, pks [][]int64, scores [][]float32) []*internalpb.FacetBucket {
+		buckets := newFacetBuckets(spec, 1)
+		for i, bucket := range buckets {
+			bucket.Count = counts[i]
+			bucket.TopHitIds = &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks[i]}}}
+			bucket.TopHitScores = scores[i]
+		}
+		return buckets
+	}
+
+	results := []*internalpb.SearchResults{
+		{FacetBuckets: bucketsOf([]int64{3, 1}, [][]int64{{1, 2}, {3}}, [][]float32{{0.9, 0.5}, {0.8}})},
+		{FacetBuckets: bucketsOf([]int64{2, 0}, [][]int64{{4, 5}, {}}, [][]float32{{0.7, 0.6}, {}})},
+		// channel without segments
+		{},
+	}
+	merged := mergeFacetBuckets(results, spec.GetTopHits())
+	assert.Len(t, merged, 2)
+	assert.True(t, math.IsInf(merged[0].GetLower(), -1))
+	assert.EqualValues(t, 5, merged[0].GetCount())
+	assert.Equal(t, []int64{1, 4}, merged[0].GetTopHitIds().GetIntId().GetData())
+	assert.Equal(t, []float32{0.9, 0.7}, merged[0].GetTopHitScores())
+	assert.EqualValues(t, 10, merged[1].GetLower())
+	assert.EqualValues(t, 1, merged[1].GetCount())
+	assert.Equal(t, []int64{3}, merged[1].GetTopHitIds().GetIntId().GetData())
+}
@@ -0,0 +1,177 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// FieldDenylist is the fields of collection never returned by retrieve to the callers not allowed, e.g. PII fields.
+type FieldDenylist struct {
+	CollectionID int64
+	FieldIDs     []int64
+	// AllowedUsers could still retrieve the denied fields
+	AllowedUsers []string
+	UpdatedAt    time.Time
+}
+
+// fieldDenylistRegistry keeps the field denylists of collections.
+type fieldDenylistRegistry struct {
+	denylists *typeutil.ConcurrentMap[int64, *FieldDenylist]
+}
+
+func newFieldDenylistRegistry() *fieldDenylistRegistry {
+	return &fieldDenylistRegistry{
+		denylists: typeutil.NewConcurrentMap[int64, *FieldDenylist](),
+	}
+}
+
+// set replaces the field denylist of collection, empty fields remove the denylist.
+func (r *fieldDenylistRegistry) set(collectionID int64, fieldIDs []int64, allowedUsers []string) {
+	if len(fieldIDs) == 0 {
+		r.denylists.Remove(collectionID)
+		return
+	}
+	r.denylists.Insert(collectionID, &FieldDenylist{
+		CollectionID: collectionID,
+		FieldIDs:     lo.Uniq(fieldIDs),
+		AllowedUsers: lo.Uniq(allowedUsers),
+		UpdatedAt:    time.Now(),
+	})
+}
+
+func (r *fieldDenylistRegistry) list() []*FieldDenylist {
+	result := make([]*FieldDenylist, 0, r.denylists.Len())
+	r.denylists.Range(func(_ int64, denylist *FieldDenylist) bool {
+		result = append(result, denylist)
+		return true
+	})
+	return result
+}
+
+// deniedFields returns the fields of collection denied to the user, the unknown caller is never allowed.
+func (r *fieldDenylistRegistry) deniedFields(collectionID int64, user string) []int64 {
+	denylist, ok := r.denylists.Get(collectionID)
+	if !ok {
+		return nil
+	}
+	if user != "" && lo.Contains(denylist.AllowedUsers, user) {
+		return nil
+	}
+	return denylist.FieldIDs
+}
+
+// callerOfRequest returns the caller of the request for the field denylists, empty if unknown.
+// The username of request is set by proxy from the authorization of the client,
+// which is verified only if authorization is enabled, so it's not trusted otherwise.
+func callerOfRequest(username string) string {
+	if !paramtable.Get().CommonCfg.AuthorizationEnabled.GetAsBool() {
+		return ""
+	}
+	return username
+}
+
+// omitDeniedFields removes the denied fields from the retrieve result even if requested,
+// and reports the omitted ones as warning of the result.
+func omitDeniedFields(result *internalpb.RetrieveResults, denied []int64) {
+	if len(denied) == 0 || result == nil {
+		return
+	}
+	omitted := make([]int64, 0)
+	result.FieldsData = lo.Filter(result.GetFieldsData(), func(field *schemapb.FieldData, _ int) bool {
+		if lo.Contains(denied, field.GetFieldId()) {
+			omitted = append(omitted, field.GetFieldId())
+			return false
+		}
+		return true
+	})
+	if len(omitted) == 0 {
+		return
+	}
+	warning := fmt.Sprintf("output fields %v denied to the caller, omitted", omitted)
+	if result.GetWarning() != "" {
+		warning = result.GetWarning() + "; " + warning
+	}
+	result.Warning = warning
+}
+
+// omitDeniedSearchFields removes the denied output fields from the hits of search result even if requested,
+// returns the omitted ones.
+func omitDeniedSearchFields(result *internalpb.SearchResults, denied []int64) ([]int64, error) {
+	if len(denied) == 0 || result.GetSlicedBlob() == nil {
+		return nil, nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return nil, err
+	}
+	omitted := make([]int64, 0)
+	omittedNames := make([]string, 0)
+	data.FieldsData = lo.Filter(data.GetFieldsData(), func(field *schemapb.FieldData, _ int) bool {
+		if lo.Contains(denied, field.GetFieldId()) {
+			omitted = append(omitted, field.GetFieldId())
+			omittedNames = append(omittedNames, field.GetFieldName())
+			return false
+		}
+		return true
+	})
+	if len(omitted) == 0 {
+		return nil, nil
+	}
+	data.OutputFields = lo.Without(data.GetOutputFields(), omittedNames...)
+	slicedBlob, err := proto.Marshal(&data)
+	if err != nil {
+		return nil, err
+	}
+	result.SlicedBlob = slicedBlob
+	return omitted, nil
+}
+
+var _ streamrpc.QueryStreamServer = (*deniedFieldsQueryStreamServer)(nil)
+
+// deniedFieldsQueryStreamServer omits the denied fields from each streamed result.
+type deniedFieldsQueryStreamServer struct {
+	server streamrpc.QueryStreamServer
+	denied []int64
+}
+
+func newDeniedFieldsQueryStreamServer(srv streamrpc.QueryStreamServer, denied []int64) *deniedFieldsQueryStreamServer {
+	return &deniedFieldsQueryStreamServer{
+		server: srv,
+		denied: denied,
+	}
+}
+
+func (s *deniedFieldsQueryStreamServer) Send(result *internalpb.RetrieveResults) error {
+	omitDeniedFields(result, s.denied)
+	return s.server.Send(result)
+}
+
+func (s *deniedFieldsQueryStreamServer) Context() context.Context {
+	return s.server.Context()
+}
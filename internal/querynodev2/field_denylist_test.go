@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestCallerOfRequest(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.CommonCfg.AuthorizationEnabled.Key, "false")
+	defer params.Reset(params.CommonCfg.AuthorizationEnabled.Key)
+	// username not verified by proxy is unknown
+	assert.Empty(t, callerOfRequest("alice"))
+
+	params.Save(params.CommonCfg.AuthorizationEnabled.Key, "true")
+	assert.Equal(t, "alice", callerOfRequest("alice"))
+	assert.Empty(t, callerOfRequest(""))
+}
+
+func TestFieldDenylistRegistry(t *testing.T) {
+	registry := newFieldDenylistRegistry()
+	assert.Empty(t, registry.deniedFields(1, "alice"))
+
+	registry.set(1, []int64{101, 102, 101}, []string{"admin"})
+	assert.ElementsMatch(t, []int64{101, 102}, registry.deniedFields(1, "alice"))
+	assert.ElementsMatch(t, []int64{101, 102}, registry.deniedFields(1, ""))
+	assert.Empty(t, registry.deniedFields(1, "admin"))
+	assert.Empty(t, registry.deniedFields(2, "alice"))
+	assert.Len(t, registry.list(), 1)
+
+	// empty fields remove the denylist
+	registry.set(1, nil, []string{"admin"})
+	assert.Empty(t, registry.deniedFields(1, "alice"))
+	assert.Empty(t, registry.list())
+}
+
+func TestOmitDeniedFields(t *testing.T) {
+	result := &internalpb.RetrieveResults{
+		FieldsData: []*schemapb.FieldData{{FieldId: 100}, {FieldId: 101}, {FieldId: 102}},
+		Warning:    "output fields [999] not found in collection schema, dropped",
+	}
+	omitDeniedFields(result, nil)
+	assert.Len(t, result.GetFieldsData(), 3)
+
+	omitDeniedFields(result, []int64{101, 103})
+	assert.Len(t, result.GetFieldsData(), 2)
+	assert.EqualValues(t, 100, result.GetFieldsData()[0].GetFieldId())
+	assert.EqualValues(t, 102, result.GetFieldsData()[1].GetFieldId())
+	assert.Contains(t, result.GetWarning(), "dropped")
+	assert.Contains(t, result.GetWarning(), "[101] denied")
+
+	// no warning if denied fields not requested
+	result = &internalpb.RetrieveResults{FieldsData: []*schemapb.FieldData{{FieldId: 100}}}
+	omitDeniedFields(result, []int64{101})
+	assert.Len(t, result.GetFieldsData(), 1)
+	assert.Empty(t, result.GetWarning())
+}
+
+func TestOmitDeniedSearchFields(t *testing.T) {
+	omitted, err := omitDeniedSearchFields(&internalpb.SearchResults{}, []int64{101})
+	assert.NoError(t, err)
+	assert.Empty(t, omitted)
+
+	data := &schemapb.SearchResultData{
+		NumQueries:   1,
+		TopK:         1,
+		Scores:       []float32{1},
+		Ids:          &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+		FieldsData:   []*schemapb.FieldData{{FieldId: 100, FieldName: "a"}, {FieldId: 101, FieldName: "b"}},
+		OutputFields: []string{"a", "b"},
+	}
+	slicedBlob, err := proto.Marshal(data)
+	require.NoError(t, err)
+	result := &internalpb.SearchResults{SlicedBlob: slicedBlob}
+
+	omitted, err = omitDeniedSearchFields(result, []int64{101, 103})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{101}, omitted)
+	var got schemapb.SearchResultData
+	require.NoError(t, proto.Unmarshal(result.GetSlicedBlob(), &got))
+	assert.Len(t, got.GetFieldsData(), 1)
+	assert.EqualValues(t, 100, got.GetFieldsData()[0].GetFieldId())
+	assert.Equal(t, []string{"a"}, got.GetOutputFields())
+
+	_, err = omitDeniedSearchFields(&internalpb.SearchResults{SlicedBlob: []byte{1}}, []int64{101})
+	assert.Error(t, err)
+}
+
+func TestDeniedFieldsQueryStreamServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := streamrpc.NewLocalQueryClient(ctx)
+	srv := newDeniedFieldsQueryStreamServer(client.CreateServer(), []int64{100})
+	assert.Equal(t, ctx, srv.Context())
+
+	go func() {
+		srv.Send(genLongFieldResult(10))
+	}()
+	result, err := client.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, result.GetFieldsData())
+	assert.NotEmpty(t, result.GetWarning())
+}
@@ -0,0 +1,501 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/internal/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	// search the index and drop the hits not matching the filter, the default one
+	filterStrategyANN = "ann_then_filter"
+	// retrieve the rows matching the filter and compute their distances exactly,
+	// which is faster than ANN search when the filter matches very few rows
+	filterStrategyBruteForce = "filter_then_bruteforce"
+)
+
+// chooseFilterStrategy decides how the filtered search is executed on the channel,
+// the strategy specified by request is respected, otherwise the brute force is chosen
+// if the estimated filter cardinality is not greater than paramtable queryNode.filterBruteForceThreshold.
+func (node *QueryNode) chooseFilterStrategy(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string) (*internalpb.FilterStrategyDecision, error) {
+	decision := &internalpb.FilterStrategyDecision{
+		Channel:              channel,
+		Strategy:             filterStrategyANN,
+		EstimatedCardinality: -1,
+	}
+	specified := req.GetReq().GetFilterStrategy()
+	threshold := paramtable.Get().QueryNodeCfg.FilterBruteForceThreshold.GetAsInt64()
+	switch {
+	case req.GetReq().GetExactSearch():
+		decision.Strategy = filterStrategyBruteForce
+		decision.Reason = "exact search requested"
+		return decision, nil
+	case specified == filterStrategyANN:
+		decision.Reason = "specified by request"
+		return decision, nil
+	case specified != "" && specified != filterStrategyBruteForce:
+		return nil, merr.WrapErrParameterInvalid(filterStrategyANN+" or "+filterStrategyBruteForce, specified, "invalid filter strategy")
+	case specified == "" && threshold <= 0:
+		decision.Reason = "brute force disabled"
+		return decision, nil
+	}
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	if reason := bruteForceUnsupported(req, &plan, collection.Schema()); reason != "" {
+		if specified == filterStrategyBruteForce {
+			return nil, merr.WrapErrParameterInvalidMsg("filter strategy %s is not supported: %s", filterStrategyBruteForce, reason)
+		}
+		decision.Reason = reason
+		return decision, nil
+	}
+	if specified == filterStrategyBruteForce {
+		decision.Strategy = filterStrategyBruteForce
+		decision.Reason = "specified by request"
+		return decision, nil
+	}
+
+	cardinality, err := node.estimateFilterCardinality(ctx, sd, req, plan.GetVectorAnns().GetPredicates(), collection.Schema(), channel)
+	if err != nil {
+		return nil, err
+	}
+	decision.EstimatedCardinality = cardinality
+	if cardinality <= threshold {
+		decision.Strategy = filterStrategyBruteForce
+		decision.Reason = fmt.Sprintf("estimated cardinality %d not greater than threshold %d", cardinality, threshold)
+	} else {
+		decision.Reason = fmt.Sprintf("estimated cardinality %d greater than threshold %d", cardinality, threshold)
+	}
+	return decision, nil
+}
+
+// bruteForceUnsupported returns why the search could not be brute forced, empty if it could.
+func bruteForceUnsupported(req *querypb.SearchRequest, plan *planpb.PlanNode, schema *schemapb.CollectionSchema) string {
+	vectorAnns := plan.GetVectorAnns()
+	switch {
+	case vectorAnns == nil:
+		return "not a vector search plan"
+	case vectorAnns.GetPredicates() == nil && !req.GetReq().GetExactSearch():
+		return "no filter"
+	case len(req.GetReq().GetOutputFieldsId()) > 0:
+		return "output fields requested"
+	case req.GetReq().GetIsIterator() || len(req.GetReq().GetIteratorToken()) > 0:
+		return "search iterator"
+	case typeutil.GetSizeOfIDs(req.GetReq().GetExcludePks()) > 0:
+		return "excluded pks"
+	case req.GetReq().GetReturnSegmentId():
+		return "segment id requested"
+	}
+	metricType := strings.ToUpper(req.GetReq().GetMetricType())
+	if metricType != metric.L2 && metricType != metric.IP && metricType != metric.COSINE {
+		return fmt.Sprintf("metric type %s", req.GetReq().GetMetricType())
+	}
+	field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == vectorAnns.GetFieldId()
+	})
+	if !ok || field.GetDataType() != schemapb.DataType_FloatVector {
+		return "not a float vector field"
+	}
+	if searchParams := vectorAnns.GetQueryInfo().GetSearchParams(); len(searchParams) > 0 {
+		params := make(map[string]any)
+		if err := json.Unmarshal([]byte(searchParams), &params); err == nil {
+			if _, ok := params[radiusKey]; ok {
+				return "range search"
+			}
+		}
+	}
+	return ""
+}
+
+// estimateFilterCardinality estimates the number of rows matching the filter on the channel,
+// the number of values bounds the primary key term filter, otherwise the rows are counted by the delegator.
+func (node *QueryNode) estimateFilterCardinality(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, predicates *planpb.Expr, schema *schemapb.CollectionSchema, channel string) (int64, error) {
+	if termExpr := predicates.GetTermExpr(); termExpr != nil && termExpr.GetColumnInfo().GetIsPrimaryKey() {
+		return int64(len(termExpr.GetValues())), nil
+	}
+
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: predicates,
+				IsCount:    true,
+			},
+		},
+	}
+	queryReq, err := filterQueryRequest(req, plan, channel)
+	if err != nil {
+		return 0, err
+	}
+	queryReq.Req.IsCount = true
+	result, err := node.queryDelegatorForFilter(ctx, sd, queryReq, schema)
+	if err != nil {
+		return 0, err
+	}
+	return funcutil.CntOfInternalResult(result)
+}
+
+// filterQueryRequest builds the retrieve request with the filter of search request.
+func filterQueryRequest(req *querypb.SearchRequest, plan *planpb.PlanNode, channel string) (*querypb.QueryRequest, error) {
+	serializedPlan, err := proto.Marshal(plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable retrieve plan", "plan with marshal error", err.Error())
+	}
+	base := &commonpb.MsgBase{}
+	if req.GetReq().GetBase() != nil {
+		base = proto.Clone(req.GetReq().GetBase()).(*commonpb.MsgBase)
+	}
+	return &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			Base:               base,
+			ReqID:              req.GetReq().GetReqID(),
+			CollectionID:       req.GetReq().GetCollectionID(),
+			PartitionIDs:       req.GetReq().GetPartitionIDs(),
+			SerializedExprPlan: serializedPlan,
+			OutputFieldsId:     plan.GetOutputFieldIds(),
+			// search is not bounded by mvcc timestamp, neither are the rows it retrieves
+			MvccTimestamp:      typeutil.MaxTimestamp,
+			GuaranteeTimestamp: req.GetReq().GetGuaranteeTimestamp(),
+			TimeoutTimestamp:   req.GetReq().GetTimeoutTimestamp(),
+			Limit:              typeutil.Unlimited,
+		},
+		DmlChannels: []string{channel},
+		SegmentIDs:  req.GetSegmentIDs(),
+		Scope:       req.GetScope(),
+	}, nil
+}
+
+func (node *QueryNode) queryDelegatorForFilter(ctx context.Context, sd delegator.ShardDelegator, req *querypb.QueryRequest, schema *schemapb.CollectionSchema) (*internalpb.RetrieveResults, error) {
+	results, err := sd.Query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return segments.CreateInternalReducer(req, schema).Reduce(ctx, results)
+}
+
+// bruteForceSearch retrieves the vectors of rows matching the filter from the delegator,
+// and computes their scores with the query vectors exactly.
+func (node *QueryNode) bruteForceSearch(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string) (*internalpb.SearchResults, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.String("channel", channel),
+	)
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+	vectorAnns, queries, err := searchQueries(req)
+	if err != nil {
+		return nil, err
+	}
+
+	predicates := vectorAnns.GetPredicates()
+	if predicates == nil {
+		// exact search without filter scans all rows
+		predicates = &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}}
+	}
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: predicates,
+			},
+		},
+		OutputFieldIds: []int64{pkField.GetFieldID(), vectorAnns.GetFieldId()},
+	}
+	queryReq, err := filterQueryRequest(req, plan, channel)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := node.queryDelegatorForFilter(ctx, sd, queryReq, collection.Schema())
+	if err != nil {
+		log.Warn("failed to retrieve rows matching filter", zap.Error(err))
+		return nil, err
+	}
+	var vectors []float32
+	var dim int
+	if fieldData, ok := lo.Find(rows.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldId() == vectorAnns.GetFieldId()
+	}); ok {
+		vectors = fieldData.GetVectors().GetFloatVector().GetData()
+		dim = int(fieldData.GetVectors().GetDim())
+	}
+	log.Debug("brute force search over rows matching filter", zap.Int("rowNum", typeutil.GetSizeOfIDs(rows.GetIds())))
+
+	topK := req.GetReq().GetTopk()
+	metricType := req.GetReq().GetMetricType()
+	data := &schemapb.SearchResultData{
+		NumQueries: req.GetReq().GetNq(),
+		TopK:       topK,
+		Ids:        &schemapb.IDs{},
+		Topks:      make([]int64, 0, len(queries)),
+	}
+	for _, query := range queries {
+		type hit struct {
+			offset int
+			score  float32
+		}
+		hits := make([]hit, 0)
+		for offset := 0; dim > 0 && (offset+1)*dim <= len(vectors); offset++ {
+			score := bruteForceScore(metricType, query, vectors[offset*dim:(offset+1)*dim])
+			hits = append(hits, hit{offset: offset, score: roundScore(score, vectorAnns.GetQueryInfo().GetRoundDecimal())})
+		}
+		// ordered as segcore does, score descending and pk ascending for tied scores
+		sort.SliceStable(hits, func(i, j int) bool {
+			if hits[i].score != hits[j].score {
+				return hits[i].score > hits[j].score
+			}
+			return typeutil.ComparePK(typeutil.GetPK(rows.GetIds(), int64(hits[i].offset)), typeutil.GetPK(rows.GetIds(), int64(hits[j].offset)))
+		})
+		if int64(len(hits)) > topK {
+			hits = hits[:topK]
+		}
+		for _, hit := range hits {
+			typeutil.AppendPKs(data.Ids, typeutil.GetPK(rows.GetIds(), int64(hit.offset)))
+			data.Scores = append(data.Scores, hit.score)
+		}
+		data.Topks = append(data.Topks, int64(len(hits)))
+	}
+	result, err := segments.EncodeSearchResultData(data, req.GetReq().GetNq(), topK, metricType)
+	if err != nil {
+		return nil, err
+	}
+	// segments are scanned by the filter query, only the matched rows are brute forced
+	result.ScannedRows = int64(typeutil.GetSizeOfIDs(rows.GetIds()))
+	return result, nil
+}
+
+// requiredRecallHits returns the least hits each query of the filtered search is supposed to get,
+// the ratio of topK bounded by the rows matching the filter.
+func requiredRecallHits(topK int64, ratio float64, cardinality int64) int64 {
+	return lo.Min([]int64{int64(math.Ceil(ratio * float64(topK))), topK, cardinality})
+}
+
+// recallFallbackSearch brute forces the rows matching the filter if the filtered ANN search got fewer hits
+// than paramtable queryNode.filteredSearch.minRecallRatio of topK for any query while more rows match the filter,
+// e.g. the filter is so selective that few candidates generated by the index match it.
+// Widening the index search params is not tried since the recall gained depends on the index and data,
+// while brute force over the matched rows is exact. The fallback is bounded by
+// paramtable queryNode.filteredSearch.fallbackMaxRows, and reported by the filter strategy decision.
+func (node *QueryNode) recallFallbackSearch(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string,
+	decision *internalpb.FilterStrategyDecision, resp *internalpb.SearchResults,
+) (*internalpb.SearchResults, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.String("channel", channel),
+	)
+
+	ratio := paramtable.Get().QueryNodeCfg.FilteredSearchMinRecallRatio.GetAsFloat()
+	topK := req.GetReq().GetTopk()
+	if ratio <= 0 || topK <= 0 || req.GetReq().GetNq() <= 0 || decision.GetStrategy() != filterStrategyANN {
+		return resp, nil
+	}
+	var resultData schemapb.SearchResultData
+	if resp.GetSlicedBlob() != nil {
+		if err := proto.Unmarshal(resp.GetSlicedBlob(), &resultData); err != nil {
+			return nil, err
+		}
+	}
+	topks := make([]int64, req.GetReq().GetNq())
+	copy(topks, resultData.GetTopks())
+	fewest := lo.Min(topks)
+	if fewest >= requiredRecallHits(topK, ratio, topK) {
+		return resp, nil
+	}
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	if reason := bruteForceUnsupported(req, &plan, collection.Schema()); reason != "" {
+		log.Debug("filtered search got too few hits but could not be brute forced", zap.String("reason", reason))
+		return resp, nil
+	}
+	cardinality, err := node.estimateFilterCardinality(ctx, sd, req, plan.GetVectorAnns().GetPredicates(), collection.Schema(), channel)
+	if err != nil {
+		return nil, err
+	}
+	required := requiredRecallHits(topK, ratio, cardinality)
+	if fewest >= required {
+		return resp, nil
+	}
+	if maxRows := paramtable.Get().QueryNodeCfg.FilteredSearchFallbackMaxRows.GetAsInt64(); cardinality > maxRows {
+		log.Info("filtered search got too few hits but too many rows match filter to brute force",
+			zap.Int64("hits", fewest),
+			zap.Int64("required", required),
+			zap.Int64("cardinality", cardinality),
+			zap.Int64("maxRows", maxRows))
+		return resp, nil
+	}
+
+	fallback, err := node.bruteForceSearch(ctx, sd, req, channel)
+	if err != nil {
+		log.Warn("failed to brute force filtered search for recall", zap.Error(err))
+		return nil, err
+	}
+	// the ANN search is done already, its cost is counted as well
+	fallback.ScannedSegments += resp.GetScannedSegments()
+	fallback.ScannedRows += resp.GetScannedRows()
+	decision.Strategy = filterStrategyBruteForce
+	decision.EstimatedCardinality = cardinality
+	decision.RecallFallback = true
+	decision.Reason = fmt.Sprintf("ann search got %d hits fewer than %d required, %d rows match filter", fewest, required, cardinality)
+	log.Info("filtered search fell back to brute force for recall",
+		zap.Int64("hits", fewest),
+		zap.Int64("required", required),
+		zap.Int64("cardinality", cardinality))
+	return fallback, nil
+}
+
+// exactSearch bypasses the index and computes the distances of all rows not deleted in the matched segments,
+// e.g. to generate the ground truth for recall evaluation. As every row is scanned,
+// the concurrency is bounded by paramtable queryNode.exactSearch.maxConcurrency,
+// and the rows retrieved into memory by each of them are bounded by queryNode.exactSearch.maxRows.
+func (node *QueryNode) exactSearch(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string) (*internalpb.SearchResults, error) {
+	maxConcurrency := paramtable.Get().QueryNodeCfg.ExactSearchMaxConcurrency.GetAsInt64()
+	if maxConcurrency <= 0 {
+		return nil, merr.WrapErrServiceUnavailable("exact search disabled")
+	}
+	if node.exactSearches.Inc() > maxConcurrency {
+		node.exactSearches.Dec()
+		return nil, merr.WrapErrServiceRateLimit(float64(maxConcurrency))
+	}
+	defer node.exactSearches.Dec()
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	reason := bruteForceUnsupported(req, &plan, collection.Schema())
+	if reason == "" && (req.GetReq().GetRefineFactor() > 0 || req.GetReq().GetRerankFieldId() > 0) {
+		reason = "refined search"
+	}
+	if reason != "" {
+		return nil, merr.WrapErrParameterInvalidMsg("exact search is not supported: %s", reason)
+	}
+	predicates := plan.GetVectorAnns().GetPredicates()
+	if predicates == nil {
+		predicates = &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}}
+	}
+	cardinality, err := node.estimateFilterCardinality(ctx, sd, req, predicates, collection.Schema(), channel)
+	if err != nil {
+		return nil, err
+	}
+	if maxRows := paramtable.Get().QueryNodeCfg.ExactSearchMaxRows.GetAsInt64(); cardinality > maxRows {
+		return nil, merr.WrapErrParameterInvalid(fmt.Sprintf("rows <= %d", maxRows), fmt.Sprintf("rows = %d", cardinality),
+			"too many rows to search exactly, narrow down the filter")
+	}
+	return node.bruteForceSearch(ctx, sd, req, channel)
+}
+
+// searchQueries returns the vector anns of search plan and the float query vectors.
+func searchQueries(req *querypb.SearchRequest) (*planpb.VectorANNS, [][]float32, error) {
+	searchPlan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &searchPlan); err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetReq().GetPlaceholderGroup(), placeholderGroup); err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("valid placeholder group", "no unmarshalable one", err.Error())
+	}
+	queries := make([][]float32, 0, req.GetReq().GetNq())
+	for _, value := range placeholderGroup.GetPlaceholders()[0].GetValues() {
+		queries = append(queries, bytesToFloats(value))
+	}
+	return searchPlan.GetVectorAnns(), queries, nil
+}
+
+// bruteForceScore computes the score of vector as search results, distances are negated.
+func bruteForceScore(metricType string, query, vector []float32) float32 {
+	var dot, queryNorm, vectorNorm, distance float64
+	for i := range query {
+		q, v := float64(query[i]), float64(vector[i])
+		dot += q * v
+		queryNorm += q * q
+		vectorNorm += v * v
+		distance += (q - v) * (q - v)
+	}
+	switch strings.ToUpper(metricType) {
+	case metric.IP:
+		return float32(dot)
+	case metric.COSINE:
+		if queryNorm == 0 || vectorNorm == 0 {
+			return 0
+		}
+		return float32(dot / math.Sqrt(queryNorm*vectorNorm))
+	default:
+		// squared euclidean distance as L2 index does
+		return float32(-distance)
+	}
+}
+
+func roundScore(score float32, roundDecimal int64) float32 {
+	if roundDecimal < 0 {
+		return score
+	}
+	multiplier := math.Pow(10, float64(roundDecimal))
+	return float32(math.Round(float64(score)*multiplier) / multiplier)
+}
+
+func bytesToFloats(data []byte) []float32 {
+	floats := make([]float32, len(data)/4)
+	for i := range floats {
+		floats[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return floats
+}
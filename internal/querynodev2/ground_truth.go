@@ -0,0 +1,217 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// GroundTruth is the exact topK results of a named query set on a channel.
+type GroundTruth struct {
+	Name          string
+	Channel       string
+	TargetVersion int64
+	// Cached is true if the results are returned from cache instead of computed by the call
+	Cached     bool
+	ComputedAt time.Time
+	Results    *internalpb.SearchResults
+}
+
+type groundTruthKey struct {
+	name    string
+	channel string
+}
+
+type groundTruthEntry struct {
+	truth *GroundTruth
+	// fingerprint of the query set, including the target version
+	fingerprint string
+	// signature of the readable segment distribution the truth computed on
+	distribution string
+}
+
+// groundTruthCache caches the exact ground truth of named query sets, keyed by name and channel.
+type groundTruthCache struct {
+	mu      sync.Mutex
+	entries map[groundTruthKey]*groundTruthEntry
+}
+
+func newGroundTruthCache() *groundTruthCache {
+	return &groundTruthCache{
+		entries: make(map[groundTruthKey]*groundTruthEntry),
+	}
+}
+
+// get returns the cached ground truth if it's computed for the same query set on the same target version
+// and segment distribution, the stale one is dropped.
+func (c *groundTruthCache) get(key groundTruthKey, fingerprint string, distribution string) (*GroundTruth, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.fingerprint != fingerprint || entry.distribution != distribution {
+		delete(c.entries, key)
+		return nil, false
+	}
+	truth := *entry.truth
+	truth.Cached = true
+	return &truth, true
+}
+
+// put caches the ground truth, the least recently computed one of channel is evicted once exceeding capacity.
+func (c *groundTruthCache) put(key groundTruthKey, entry *groundTruthEntry) {
+	capacity := paramtable.Get().QueryNodeCfg.GroundTruthCacheCapacity.GetAsInt()
+	if capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	for {
+		var (
+			oldest *groundTruthKey
+			num    int
+		)
+		for k, e := range c.entries {
+			if k.channel != key.channel {
+				continue
+			}
+			num++
+			if oldest == nil || e.truth.ComputedAt.Before(c.entries[*oldest].truth.ComputedAt) {
+				k := k
+				oldest = &k
+			}
+		}
+		if num <= capacity {
+			return
+		}
+		delete(c.entries, *oldest)
+	}
+}
+
+// invalidate drops the cached ground truth of channel.
+func (c *groundTruthCache) invalidate(channel string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.channel == channel {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// distributionSignature returns the signature of the readable segment distribution of delegator,
+// which changes once segments are loaded, released or handed off.
+func distributionSignature(sd delegator.ShardDelegator) string {
+	sealed, growing := sd.GetSegmentInfo(true)
+	entries := make([]string, 0, len(growing))
+	for _, item := range sealed {
+		for _, segment := range item.Segments {
+			entries = append(entries, fmt.Sprintf("s%d@%d:%d", segment.SegmentID, item.NodeID, segment.Version))
+		}
+	}
+	for _, segment := range growing {
+		entries = append(entries, fmt.Sprintf("g%d@%d:%d", segment.SegmentID, segment.NodeID, segment.Version))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// GetGroundTruth returns the exact topK results of the named query set described by req on its channel,
+// computed by brute force once and cached until the target version or the segment distribution of channel changes,
+// or the query set of the name changes. The guarantee timestamp doesn't take part in the cache key, so the rows
+// inserted into growing segments since computed are not reflected until the distribution changes.
+func (node *QueryNode) GetGroundTruth(ctx context.Context, name string, req *querypb.SearchRequest) (*GroundTruth, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if name == "" {
+		return nil, merr.WrapErrParameterInvalidMsg("name of query set is required")
+	}
+	if len(req.GetDmlChannels()) != 1 {
+		return nil, merr.WrapErrParameterInvalid(1, len(req.GetDmlChannels()), "ground truth is computed on one channel")
+	}
+	channel := req.GetDmlChannels()[0]
+	log := log.Ctx(ctx).With(
+		zap.String("name", name),
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.String("channel", channel),
+	)
+	sd, ok := node.delegators.Get(channel)
+	if !ok || sd.Collection() != req.GetReq().GetCollectionID() {
+		return nil, merr.WrapErrChannelNotFound(channel)
+	}
+
+	exactReq := proto.Clone(req).(*querypb.SearchRequest)
+	exactReq.Req.ExactSearch = true
+	normalized := proto.Clone(exactReq).(*querypb.SearchRequest)
+	normalized.Req.GuaranteeTimestamp = 0
+	targetVersion := sd.GetTargetVersion()
+	fingerprint, err := queryFingerprint(normalized, channel, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	key := groundTruthKey{name: name, channel: channel}
+	distribution := distributionSignature(sd)
+	if truth, ok := node.groundTruths.get(key, fingerprint, distribution); ok {
+		log.Debug("ground truth cache hit", zap.Int64("targetVersion", targetVersion))
+		return truth, nil
+	}
+
+	results, err := node.exactSearch(ctx, sd, exactReq, channel)
+	if err != nil {
+		log.Warn("failed to compute ground truth", zap.Error(err))
+		return nil, err
+	}
+	truth := &GroundTruth{
+		Name:          name,
+		Channel:       channel,
+		TargetVersion: targetVersion,
+		ComputedAt:    time.Now(),
+		Results:       results,
+	}
+	// the distribution may change while computing, the truth is not cached then
+	if targetVersion == sd.GetTargetVersion() && distribution == distributionSignature(sd) {
+		node.groundTruths.put(key, &groundTruthEntry{
+			truth:        truth,
+			fingerprint:  fingerprint,
+			distribution: distribution,
+		})
+	}
+	log.Info("ground truth computed", zap.Int64("targetVersion", targetVersion))
+	return truth, nil
+}
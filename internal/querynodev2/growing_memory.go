@@ -0,0 +1,174 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// ChannelGrowingMemory is the memory of growing segments of a channel delegated by the node.
+type ChannelGrowingMemory struct {
+	Channel      string
+	CollectionID int64
+	Segments     int
+	Rows         int64
+	MemSize      int64
+	// Share is the proportion of the growing memory of the node taken by the channel
+	Share float64
+}
+
+// GrowingMemoryDistribution is the growing memory of channels delegated by the node, heaviest first.
+type GrowingMemoryDistribution struct {
+	Channels []*ChannelGrowingMemory
+	TotalMem int64
+	// SealCandidate is the heaviest channel whose growing segments should be sealed early to relieve memory,
+	// empty if the node delegates less than two channels or holds no growing data.
+	SealCandidate string
+	Reason        string
+}
+
+// GetGrowingMemoryDistribution reports the growing memory of each channel delegated by the node,
+// and picks the heaviest channel as seal candidate if the node hosts multiple delegators.
+// Growing segments on query node mirror the DML stream and are sealed by data coordinator only,
+// so the node could not seal them itself, the caller shall flush the candidate through the coordinator.
+// Segments are measured under their own lock, released segments count as empty, so the report is safe
+// against concurrent ingestion and search, though it is only a snapshot.
+func (node *QueryNode) GetGrowingMemoryDistribution(ctx context.Context) (*GrowingMemoryDistribution, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	result := &GrowingMemoryDistribution{
+		Channels: make([]*ChannelGrowingMemory, 0),
+	}
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		usage := &ChannelGrowingMemory{
+			Channel:      channel,
+			CollectionID: sd.Collection(),
+		}
+		for _, segment := range node.manager.Segment.GetBy(segments.WithChannel(channel), segments.WithType(segments.SegmentTypeGrowing)) {
+			usage.Segments++
+			usage.Rows += segment.RowNum()
+			usage.MemSize += segment.MemSize()
+		}
+		result.TotalMem += usage.MemSize
+		result.Channels = append(result.Channels, usage)
+		return true
+	})
+
+	for _, usage := range result.Channels {
+		if result.TotalMem > 0 {
+			usage.Share = float64(usage.MemSize) / float64(result.TotalMem)
+		}
+	}
+	sort.Slice(result.Channels, func(i, j int) bool {
+		if result.Channels[i].MemSize != result.Channels[j].MemSize {
+			return result.Channels[i].MemSize > result.Channels[j].MemSize
+		}
+		return result.Channels[i].Channel < result.Channels[j].Channel
+	})
+
+	switch {
+	case len(result.Channels) < 2:
+		result.Reason = "node delegates less than two channels"
+	case result.TotalMem == 0:
+		result.Reason = "no growing data"
+	default:
+		heaviest := result.Channels[0]
+		result.SealCandidate = heaviest.Channel
+		result.Reason = fmt.Sprintf("heaviest channel takes %.2f of growing memory", heaviest.Share)
+		log.Ctx(ctx).Info("growing memory seal candidate picked",
+			zap.String("channel", heaviest.Channel),
+			zap.Int64("collectionID", heaviest.CollectionID),
+			zap.Int64("memSize", heaviest.MemSize),
+			zap.Int64("totalMem", result.TotalMem),
+		)
+	}
+	return result, nil
+}
+
+// ChannelGrowingSegments is the growing segment count of a channel delegated by the node.
+type ChannelGrowingSegments struct {
+	Channel      string
+	CollectionID int64
+	Segments     int
+	Rows         int64
+	// Exceeded is true if the channel holds more growing segments than the cap,
+	// it shall be flushed through the coordinator to seal them early
+	Exceeded bool
+}
+
+// GrowingSegmentCounts is the growing segment count of channels delegated by the node, most segments first.
+type GrowingSegmentCounts struct {
+	Channels []*ChannelGrowingSegments
+	// MaxPerChannel is paramtable queryNode.growingSegment.maxPerChannel, 0 means no limit
+	MaxPerChannel int
+}
+
+// GetGrowingSegmentCounts reports the growing segment count of each channel delegated by the node,
+// and the channels exceeding paramtable queryNode.growingSegment.maxPerChannel. Loading growing segments
+// beyond the cap is rejected, while the ones created by ingestion could only be sealed by data coordinator.
+func (node *QueryNode) GetGrowingSegmentCounts(ctx context.Context) (*GrowingSegmentCounts, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	result := &GrowingSegmentCounts{
+		Channels:      make([]*ChannelGrowingSegments, 0),
+		MaxPerChannel: paramtable.Get().QueryNodeCfg.GrowingSegmentMaxPerChannel.GetAsInt(),
+	}
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		count := &ChannelGrowingSegments{
+			Channel:      channel,
+			CollectionID: sd.Collection(),
+		}
+		for _, segment := range node.manager.Segment.GetBy(segments.WithChannel(channel), segments.WithType(segments.SegmentTypeGrowing)) {
+			count.Segments++
+			count.Rows += segment.RowNum()
+		}
+		count.Exceeded = result.MaxPerChannel > 0 && count.Segments > result.MaxPerChannel
+		if count.Exceeded {
+			log.Ctx(ctx).Warn("channel holds growing segments more than the cap",
+				zap.String("channel", channel),
+				zap.Int64("collectionID", count.CollectionID),
+				zap.Int("segments", count.Segments),
+				zap.Int("maxPerChannel", result.MaxPerChannel),
+			)
+		}
+		result.Channels = append(result.Channels, count)
+		return true
+	})
+	sort.Slice(result.Channels, func(i, j int) bool {
+		if result.Channels[i].Segments != result.Channels[j].Segments {
+			return result.Channels[i].Segments > result.Channels[j].Segments
+		}
+		return result.Channels[i].Channel < result.Channels[j].Channel
+	})
+	return result, nil
+}
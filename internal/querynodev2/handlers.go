@@ -18,32 +18,56 @@ package querynodev2
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/planpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/optimizers"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
 	"github.com/milvus-io/milvus/internal/querynodev2/tasks"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/retry"
 	"github.com/milvus-io/milvus/pkg/util/timerecord"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
-func loadGrowingSegments(ctx context.Context, delegator delegator.ShardDelegator, req *querypb.WatchDmChannelsRequest) error {
+// policies of unflushed segments with empty binlog, see queryNode.emptyGrowingBinlogPolicy
+const (
+	emptyBinlogPolicySkip           = "skip"
+	emptyBinlogPolicySkipWithMetric = "skipWithMetric"
+	emptyBinlogPolicyError          = "error"
+)
+
+func loadGrowingSegments(ctx context.Context, delegator delegator.ShardDelegator, req *querypb.WatchDmChannelsRequest) ([]int64, error) {
 	// load growing segments
 	growingSegments := make([]*querypb.SegmentLoadInfo, 0, len(req.Infos))
 	for _, info := range req.Infos {
@@ -66,14 +90,46 @@ func loadGrowingSegments(ctx context.Context, delegator delegator.ShardDelegator
 					InsertChannel: segmentInfo.InsertChannel,
 				})
 			} else {
-				log.Info("skip segment which binlog is empty", zap.Int64("segmentID", segmentInfo.ID))
+				switch paramtable.Get().QueryNodeCfg.EmptyGrowingBinlogPolicy.GetValue() {
+				case emptyBinlogPolicyError:
+					// an unflushed segment without binlog may indicate a bug of flushing, surface it to coordinator
+					err := merr.WrapErrSegmentLack(segmentInfo.ID, "binlog of unflushed segment is empty")
+					log.Warn("failed to load growing segment", zap.Error(err))
+					return nil, err
+				case emptyBinlogPolicySkipWithMetric:
+					metrics.QueryNodeEmptyBinlogGrowingSegmentCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), fmt.Sprint(segmentInfo.CollectionID)).Inc()
+					log.Warn("skip segment which binlog is empty", zap.Int64("segmentID", segmentInfo.ID))
+				default:
+					// emptyBinlogPolicySkip, unknown policies are treated as skip as well
+					log.Warn("skip segment which binlog is empty", zap.Int64("segmentID", segmentInfo.ID))
+				}
 			}
 		}
 	}
 
+	channels := lo.Map(req.GetInfos(), func(info *datapb.VchannelInfo, _ int) string { return info.GetChannelName() })
+	if err := checkGrowingSegmentCap(delegator, channels, len(growingSegments)); err != nil {
+		log.Warn("failed to load growing segments", zap.Error(err))
+		return nil, err
+	}
 	return delegator.LoadGrowing(ctx, growingSegments, req.GetVersion())
 }
 
+// checkGrowingSegmentCap rejects loading the growing segments if the channel would hold more growing segments
+// than paramtable queryNode.growingSegment.maxPerChannel, since the per segment overhead slows search.
+func checkGrowingSegmentCap(sd delegator.ShardDelegator, channels []string, loading int) error {
+	maxSegments := paramtable.Get().QueryNodeCfg.GrowingSegmentMaxPerChannel.GetAsInt()
+	if maxSegments <= 0 || loading == 0 {
+		return nil
+	}
+	_, growing := sd.GetSegmentInfo(false)
+	if total := len(growing) + loading; total > maxSegments {
+		return merr.WrapErrServiceRequestLimitExceeded(int32(maxSegments),
+			fmt.Sprintf("channel %v would hold %d growing segments, flush the channel to seal them", channels, total))
+	}
+	return nil
+}
+
 func (node *QueryNode) loadDeltaLogs(ctx context.Context, req *querypb.LoadSegmentsRequest) *commonpb.Status {
 	log := log.Ctx(ctx).With(
 		zap.Int64("collectionID", req.GetCollectionID()),
@@ -147,11 +203,19 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 		zap.String("scope", req.GetScope().String()),
 	)
 
+	if err := node.lifetime.Add(node.acceptsNewRequest); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	// req may be replaced while serving, the collection is recorded beforehand
+	collectionID := req.GetReq().GetCollectionID()
 	var err error
 	metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.QueryLabel, metrics.TotalLabel, metrics.Leader).Inc()
 	defer func() {
 		if err != nil {
 			metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.QueryLabel, metrics.FailLabel, metrics.Leader).Inc()
+			node.requestCounters.record(collectionID, metrics.QueryLabel, false)
 		}
 	}()
 
@@ -159,8 +223,8 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 		zap.Bool("fromShardLeader", req.GetFromShardLeader()),
 		zap.Int64s("segmentIDs", req.GetSegmentIDs()),
 	)
-	// add cancel when error occurs
-	queryCtx, cancel := context.WithCancel(ctx)
+	// add cancel when error occurs, the deadline is capped by the query timeout of collection
+	queryCtx, cancel := withQueryTimeout(ctx, node.queryTimeoutOverrides.timeout(collectionID))
 	defer cancel()
 
 	// From Proxy
@@ -172,13 +236,38 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 		log.Warn("Query failed, failed to get shard delegator for query", zap.Error(err))
 		return nil, err
 	}
+	if err := checkReplica(sd, channel, req.GetReq().GetReplicaID()); err != nil {
+		log.Warn("Query failed, requested replica not served here", zap.Error(err))
+		return nil, err
+	}
+
+	collection := node.manager.Collection.Get(req.Req.GetCollectionID())
+	if collection == nil {
+		err := merr.WrapErrCollectionNotFound(req.Req.GetCollectionID())
+		log.Warn("Query failed, failed to get collection", zap.Error(err))
+		return nil, err
+	}
+	req, droppedOutputFields, err := applyLenientOutputFields(req, collection.Schema())
+	if err != nil {
+		log.Warn("Query failed, invalid retrieve plan", zap.Error(err))
+		return nil, err
+	}
+	if len(droppedOutputFields) > 0 {
+		log.Warn("output fields not in schema are dropped", zap.Int64s("fieldIDs", droppedOutputFields))
+	}
 
 	// do query
 	results, err := sd.Query(queryCtx, req)
 	if err != nil {
+		err = tagServerTimeout(ctx, queryCtx, err)
 		log.Warn("failed to query on delegator", zap.Error(err))
 		return nil, err
 	}
+	var scanDecisions []*internalpb.SegmentScanDecision
+	if req.GetReq().GetExplain() && len(results) > 0 {
+		scanDecisions = results[len(results)-1].GetScanDecisions()
+		results = results[:len(results)-1]
+	}
 
 	// reduce result
 	tr.CtxElapse(ctx, fmt.Sprintf("start reduce query result, traceID = %s, fromSharedLeader = %t, vChannel = %s, segmentIDs = %v",
@@ -188,19 +277,29 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 		req.GetSegmentIDs(),
 	))
 
-	collection := node.manager.Collection.Get(req.Req.GetCollectionID())
-	if collection == nil {
-		err := merr.WrapErrCollectionNotFound(req.Req.GetCollectionID())
-		log.Warn("Query failed, failed to get collection", zap.Error(err))
+	// account the memory materialized while reducing, to fail the request instead of oom
+	account := segments.NewRequestReduceMemoryAccount()
+	defer account.Free()
+	err = account.Grow(int64(lo.SumBy(results, func(result *internalpb.RetrieveResults) int { return proto.Size(result) })))
+	if err != nil {
+		log.Warn("query results exceed reduce memory limit", zap.Error(err))
 		return nil, err
 	}
-
 	reducer := segments.CreateInternalReducer(req, collection.Schema())
 
-	resp, err := reducer.Reduce(ctx, results)
+	resp, err := reducer.Reduce(segments.WithReduceMemoryAccount(ctx, account), results)
+	if err != nil {
+		log.Warn("failed to reduce query results", zap.Error(err))
+		return nil, err
+	}
+	// order the rows after limit applied by reduce
+	err = typeutil2.SortRetrieveResults(resp, req.GetReq().GetSortKeys())
 	if err != nil {
+		log.Warn("failed to sort query results", zap.Error(err))
 		return nil, err
 	}
+	resp.ScanDecisions = scanDecisions
+	fillDroppedOutputFields(resp, droppedOutputFields)
 
 	tr.CtxElapse(ctx, fmt.Sprintf("do query with channel done , vChannel = %s, segmentIDs = %v",
 		channel,
@@ -210,10 +309,16 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 	latency := tr.ElapseSpan()
 	metrics.QueryNodeSQReqLatency.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.QueryLabel, metrics.Leader).Observe(float64(latency.Milliseconds()))
 	metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.QueryLabel, metrics.SuccessLabel, metrics.Leader).Inc()
+	node.requestCounters.record(collectionID, metrics.QueryLabel, true)
 	return resp, nil
 }
 
 func (node *QueryNode) queryChannelStream(ctx context.Context, req *querypb.QueryRequest, channel string, srv streamrpc.QueryStreamServer) error {
+	if err := node.lifetime.Add(node.acceptsNewRequest); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
 	metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.QueryLabel, metrics.TotalLabel, metrics.Leader).Inc()
 	msgID := req.Req.Base.GetMsgID()
 	log := log.Ctx(ctx).With(
@@ -235,8 +340,8 @@ func (node *QueryNode) queryChannelStream(ctx context.Context, req *querypb.Quer
 		zap.Int64s("segmentIDs", req.GetSegmentIDs()),
 	)
 
-	// add cancel when error occurs
-	queryCtx, cancel := context.WithCancel(ctx)
+	// add cancel when error occurs, the deadline is capped by the query timeout of collection
+	queryCtx, cancel := withQueryTimeout(ctx, node.queryTimeoutOverrides.timeout(req.GetReq().GetCollectionID()))
 	defer cancel()
 
 	// From Proxy
@@ -248,10 +353,15 @@ func (node *QueryNode) queryChannelStream(ctx context.Context, req *querypb.Quer
 		log.Warn("Query failed, failed to get query shard delegator", zap.Error(err))
 		return err
 	}
+	if err := checkReplica(sd, channel, req.GetReq().GetReplicaID()); err != nil {
+		log.Warn("Query failed, requested replica not served here", zap.Error(err))
+		return err
+	}
 
 	// do query
 	err = sd.QueryStream(queryCtx, req, srv)
 	if err != nil {
+		err = tagServerTimeout(ctx, queryCtx, err)
 		return err
 	}
 
@@ -285,6 +395,16 @@ func (node *QueryNode) queryStreamSegments(ctx context.Context, req *querypb.Que
 	return nil
 }
 
+// estimateSegmentNum estimates the total segment number searched among channels for the query hook,
+// growing ones are ignored for now since they will always be brute force.
+func estimateSegmentNum(sealed []delegator.SnapshotItem, channelNum int64) int {
+	sealedNum := lo.Reduce(sealed, func(sum int, item delegator.SnapshotItem, _ int) int {
+		return sum + len(item.Segments)
+	}, 0)
+	// use shardNum * segments num in shard to estimate total segment number
+	return sealedNum * int(channelNum)
+}
+
 func (node *QueryNode) optimizeSearchParams(ctx context.Context, req *querypb.SearchRequest, deleg delegator.ShardDelegator) (*querypb.SearchRequest, error) {
 	// no hook applied, just return
 	if node.queryHook == nil {
@@ -315,24 +435,36 @@ func (node *QueryNode) optimizeSearchParams(ctx context.Context, req *querypb.Se
 
 	switch plan.GetNode().(type) {
 	case *planpb.PlanNode_VectorAnns:
-		// ignore growing ones for now since they will always be brute force
 		sealed, _ := deleg.GetSegmentInfo(true)
-		sealedNum := lo.Reduce(sealed, func(sum int, item delegator.SnapshotItem, _ int) int {
-			return sum + len(item.Segments)
-		}, 0)
-		// use shardNum * segments num in shard to estimate total segment number
-		estSegmentNum := sealedNum * int(channelNum)
+		estSegmentNum := estimateSegmentNum(sealed, channelNum)
 		withFilter := (plan.GetVectorAnns().GetPredicates() != nil)
 		queryInfo := plan.GetVectorAnns().GetQueryInfo()
-		params := map[string]any{
-			common.TopKKey:        queryInfo.GetTopk(),
-			common.SearchParamKey: queryInfo.GetSearchParams(),
-			common.SegmentNumKey:  estSegmentNum,
-			common.WithFilterKey:  withFilter,
-			common.CollectionKey:  req.GetReq().GetCollectionID(),
-		}
-		err := node.queryHook.Run(params)
+		attempts := paramtable.Get().QueryNodeCfg.QueryHookRetryAttempts.GetAsUint()
+		if attempts == 0 {
+			attempts = 1
+		}
+		var params map[string]any
+		var hookErr error
+		err := retry.Do(ctx, func() error {
+			// hook may modify params before failing, rebuild them for each attempt
+			params = map[string]any{
+				common.TopKKey:        queryInfo.GetTopk(),
+				common.SearchParamKey: queryInfo.GetSearchParams(),
+				common.SegmentNumKey:  estSegmentNum,
+				common.WithFilterKey:  withFilter,
+				common.CollectionKey:  req.GetReq().GetCollectionID(),
+			}
+			hookErr = node.queryHook.Run(params)
+			if hookErr != nil && !optimizers.IsTransientError(hookErr) {
+				return retry.Unrecoverable(hookErr)
+			}
+			return hookErr
+		}, retry.Attempts(attempts), retry.Sleep(paramtable.Get().QueryNodeCfg.QueryHookRetryInterval.GetAsDuration(time.Millisecond)))
 		if err != nil {
+			if optimizers.IsTransientError(hookErr) {
+				log.Warn("queryHook keeps failing with transient error, search with params not optimized", zap.Error(err))
+				return req, nil
+			}
 			log.Warn("failed to execute queryHook", zap.Error(err))
 			return nil, merr.WrapErrServiceUnavailable(err.Error(), "queryHook execution failed")
 		}
@@ -351,6 +483,91 @@ func (node *QueryNode) optimizeSearchParams(ctx context.Context, req *querypb.Se
 	return req, nil
 }
 
+// capSearchTopK applies the adaptive topK cap of collection to search request.
+func (node *QueryNode) capSearchTopK(req *querypb.SearchRequest) (*querypb.SearchRequest, bool, error) {
+	effective, capped := node.adaptiveTopK.Cap(req.GetReq().GetCollectionID(), req.GetReq().GetTopk())
+	if !capped {
+		return req, false, nil
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, false, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	if plan.GetVectorAnns() == nil {
+		return req, false, nil
+	}
+	plan.GetVectorAnns().GetQueryInfo().Topk = effective
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, false, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+	// request is shared by the searches of channels, keep it intact
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.Topk = effective
+	return cloned, true, nil
+}
+
+// validateSearchRequest checks nq and vector dimension of placeholder group before searching.
+func (node *QueryNode) validateSearchRequest(req *querypb.SearchRequest) error {
+	nq := req.GetReq().GetNq()
+	if nq < 1 {
+		return merr.WrapErrParameterInvalid("nq >= 1", fmt.Sprintf("nq = %d", nq), "invalid nq")
+	}
+
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetReq().GetPlaceholderGroup(), placeholderGroup); err != nil {
+		return merr.WrapErrParameterInvalid("valid placeholder group", "no unmarshalable one", err.Error())
+	}
+	if len(placeholderGroup.GetPlaceholders()) == 0 {
+		return merr.WrapErrParameterInvalid("non-empty placeholder group", "empty placeholder group")
+	}
+
+	// dimension check requires the search field, which is checked while optimizing search params if plan is invalid
+	var dim int64
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err == nil && plan.GetVectorAnns() != nil {
+		collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+		if collection != nil {
+			field, ok := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+				return field.GetFieldID() == plan.GetVectorAnns().GetFieldId()
+			})
+			if ok {
+				dim, _ = typeutil.GetDim(field)
+			}
+		}
+	}
+
+	for _, placeholder := range placeholderGroup.GetPlaceholders() {
+		if int64(len(placeholder.GetValues())) != nq {
+			return merr.WrapErrParameterInvalid(nq, int64(len(placeholder.GetValues())), "nq mismatches the number of vectors in placeholder group")
+		}
+		if dim <= 0 {
+			continue
+		}
+
+		var expectedSize int64
+		switch placeholder.GetType() {
+		case commonpb.PlaceholderType_FloatVector:
+			expectedSize = dim * 4
+		case commonpb.PlaceholderType_Float16Vector:
+			expectedSize = dim * 2
+		case commonpb.PlaceholderType_BinaryVector:
+			expectedSize = dim / 8
+		default:
+			continue
+		}
+		for _, value := range placeholder.GetValues() {
+			if int64(len(value)) != expectedSize {
+				return merr.WrapErrParameterInvalid(expectedSize, int64(len(value)),
+					fmt.Sprintf("vector size mismatches the dimension %d of field %d", dim, plan.GetVectorAnns().GetFieldId()))
+			}
+		}
+	}
+	return nil
+}
+
 func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchRequest, channel string) (*internalpb.SearchResults, error) {
 	log := log.Ctx(ctx).With(
 		zap.Int64("msgID", req.GetReq().GetBase().GetMsgID()),
@@ -360,16 +577,19 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 	)
 	traceID := trace.SpanFromContext(ctx).SpanContext().TraceID()
 
-	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+	if err := node.lifetime.Add(node.acceptsNewRequest); err != nil {
 		return nil, err
 	}
 	defer node.lifetime.Done()
 
+	// req may be replaced while serving, the collection is recorded beforehand
+	collectionID := req.GetReq().GetCollectionID()
 	var err error
 	metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SearchLabel, metrics.TotalLabel, metrics.Leader).Inc()
 	defer func() {
 		if err != nil {
 			metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SearchLabel, metrics.FailLabel, metrics.Leader).Inc()
+			node.requestCounters.record(collectionID, metrics.SearchLabel, false)
 		}
 	}()
 
@@ -377,7 +597,18 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 		zap.Bool("fromShardLeader", req.GetFromShardLeader()),
 		zap.Int64s("segmentIDs", req.GetSegmentIDs()),
 	)
-	searchCtx, cancel := context.WithCancel(ctx)
+	if err = node.validateSearchRequest(req); err != nil {
+		log.Warn("invalid search request", zap.Error(err))
+		return nil, err
+	}
+	var droppedQueries []int
+	req, droppedQueries, err = sanitizeQueryVectors(req)
+	if err != nil {
+		log.Warn("invalid query vectors", zap.Error(err))
+		return nil, err
+	}
+	// the deadline is capped by the query timeout of collection
+	searchCtx, cancel := withQueryTimeout(ctx, node.queryTimeoutOverrides.timeout(collectionID))
 	defer cancel()
 
 	// From Proxy
@@ -389,29 +620,268 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 		log.Warn("Query failed, failed to get shard delegator for search", zap.Error(err))
 		return nil, err
 	}
-	req, err = node.optimizeSearchParams(ctx, req, sd)
+	if err := checkReplica(sd, channel, req.GetReq().GetReplicaID()); err != nil {
+		log.Warn("Search failed, requested replica not served here", zap.Error(err))
+		return nil, err
+	}
+	// no segment or no finite query to search, return empty result directly
+	sealed, growing := sd.GetSegmentInfo(true)
+	sealedNum := lo.SumBy(sealed, func(item delegator.SnapshotItem) int { return len(item.Segments) })
+	if (sealedNum == 0 && len(growing) == 0) || int64(len(droppedQueries)) == req.GetReq().GetNq() {
+		log.Debug("no segment in delegator or all queries dropped, return empty search result")
+		metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SearchLabel, metrics.SuccessLabel, metrics.Leader).Inc()
+		node.requestCounters.record(collectionID, metrics.SearchLabel, true)
+		return &internalpb.SearchResults{
+			Status:     merr.Success(),
+			MetricType: req.GetReq().GetMetricType(),
+			NumQueries: req.GetReq().GetNq(),
+			TopK:       req.GetReq().GetTopk(),
+			Topks:      make([]int64, req.GetReq().GetNq()),
+			Truncated:  make([]bool, req.GetReq().GetNq()),
+		}, nil
+	}
+	// defaults are applied before the hook, so the hook could still override them
+	req, err = node.searchParamDefaults.apply(req)
+	if err != nil {
+		log.Warn("failed to apply default search params", zap.Error(err))
+		return nil, err
+	}
+	// plan before optimized by the hook, nil if the hook not applied
+	var requestedPlan []byte
+	if req.GetReq().GetSkipHook() {
+		// searched with the client specified params, e.g. to tell whether the hook causes recall regression
+		log.Info("query hook skipped by request, search runs un-optimized")
+	} else {
+		if node.queryHook != nil {
+			requestedPlan = req.GetReq().GetSerializedExprPlan()
+		}
+		req, err = node.optimizeSearchParams(ctx, req, sd)
+		if err != nil {
+			log.Warn("failed to optimize search params", zap.Error(err))
+			return nil, err
+		}
+	}
+	originTopK := req.GetReq().GetTopk()
+	req, topkCapped, err := node.capSearchTopK(req)
+	if err != nil {
+		log.Warn("failed to cap search topK", zap.Error(err))
+		return nil, err
+	}
+	if topkCapped {
+		log.Debug("search topK capped adaptively", zap.Int64("topK", originTopK), zap.Int64("effectiveTopK", req.GetReq().GetTopk()))
+	}
+	pageTopK := req.GetReq().GetTopk()
+	req, iterToken, err := applyIteratorToken(req)
+	if err != nil {
+		log.Warn("failed to apply search iterator token", zap.Error(err))
+		return nil, err
+	}
+	// topK hits are followed by the over-fetched candidates
+	primaryTopK := req.GetReq().GetTopk()
+	req, err = applyOverfetch(req)
+	if err != nil {
+		log.Warn("failed to apply search over-fetch", zap.Error(err))
+		return nil, err
+	}
+	req, err = applySearchExclusion(req)
+	if err != nil {
+		log.Warn("failed to apply search exclusion", zap.Error(err))
+		return nil, err
+	}
+	req, err = applyScoreStatsOnly(req)
+	if err != nil {
+		log.Warn("invalid score statistics only search", zap.Error(err))
+		return nil, err
+	}
+	if req.GetReq().GetExplainPk() != nil {
+		if err = validateExplainPK(req); err != nil {
+			log.Warn("invalid pk to explain", zap.Error(err))
+			return nil, err
+		}
+	}
+	filterDecision, err := node.chooseFilterStrategy(searchCtx, sd, req, channel)
 	if err != nil {
-		log.Warn("failed to optimize search params", zap.Error(err))
+		err = tagServerTimeout(ctx, searchCtx, err)
+		log.Warn("failed to choose filter strategy", zap.Error(err))
 		return nil, err
 	}
+	if req.GetReq().GetFacet() != nil || req.GetReq().GetPerPartitionTopk() {
+		collection := node.manager.Collection.Get(collectionID)
+		if collection == nil {
+			err = merr.WrapErrCollectionNotLoaded(collectionID)
+			log.Warn("failed to validate search request", zap.Error(err))
+			return nil, err
+		}
+		if req.GetReq().GetFacet() != nil {
+			if err = validateFacet(req.GetReq().GetFacet(), collection.Schema()); err != nil {
+				log.Warn("invalid search facet", zap.Error(err))
+				return nil, err
+			}
+		}
+		if req.GetReq().GetPerPartitionTopk() {
+			if err = validatePerPartitionTopK(req, collection); err != nil {
+				log.Warn("invalid per partition topK search", zap.Error(err))
+				return nil, err
+			}
+		}
+	}
+	// fingerprint of the request as executed, after optimized and rewritten
+	var fingerprint string
+	if req.GetReq().GetReturnFingerprint() {
+		fingerprint, err = queryFingerprint(req, channel, sd.GetTargetVersion())
+		if err != nil {
+			log.Warn("failed to compute query fingerprint", zap.Error(err))
+			return nil, err
+		}
+	}
 	// do search
-	results, err := sd.Search(searchCtx, req)
+	var resp *internalpb.SearchResults
+	var scanDecisions []*internalpb.SegmentScanDecision
+	maxNQ := paramtable.Get().QueryNodeCfg.MaxNQPerBatch.GetAsInt64()
+	switch {
+	case req.GetReq().GetPerPartitionTopk():
+		// validated not to be exact or refined, each partition is searched over the index
+		resp, scanDecisions, err = node.searchPerPartition(searchCtx, sd, req)
+	case req.GetReq().GetExactSearch():
+		log.Debug("exact search requested, index bypassed")
+		resp, err = node.exactSearch(searchCtx, sd, req, channel)
+	case req.GetReq().GetRerankFieldId() > 0:
+		// reranked by another field, the brute forced scores of the searched field are not the final ones either
+		resp, scanDecisions, err = node.refineSearch(searchCtx, sd, req, channel)
+	case filterDecision.GetStrategy() == filterStrategyBruteForce:
+		log.Debug("search with filter brute forced", zap.String("reason", filterDecision.GetReason()))
+		resp, err = node.bruteForceSearch(searchCtx, sd, req, channel)
+	case req.GetReq().GetRefineFactor() > 0:
+		// the brute forced results are exact already, only ANN results are refined
+		resp, scanDecisions, err = node.refineSearch(searchCtx, sd, req, channel)
+	case maxNQ > 0 && req.GetReq().GetNq() > maxNQ:
+		resp, scanDecisions, err = node.searchDelegatorInNQBatches(searchCtx, sd, req, maxNQ)
+	default:
+		resp, scanDecisions, err = node.searchDelegator(searchCtx, sd, req)
+	}
 	if err != nil {
-		log.Warn("failed to search on delegator", zap.Error(err))
+		err = tagServerTimeout(ctx, searchCtx, err)
 		return nil, err
 	}
-
-	// reduce result
-	tr.CtxElapse(ctx, fmt.Sprintf("start reduce query result, traceID = %s, fromSharedLeader = %t, vChannel = %s, segmentIDs = %v",
+	// the rows matching a selective filter may be missed by ANN search, brute forced if too few hits
+	if !req.GetReq().GetPerPartitionTopk() && req.GetReq().GetRerankFieldId() <= 0 {
+		var fallback *internalpb.SearchResults
+		fallback, err = node.recallFallbackSearch(searchCtx, sd, req, channel, filterDecision, resp)
+		if err != nil {
+			err = tagServerTimeout(ctx, searchCtx, err)
+			log.Warn("failed to fall back filtered search for recall", zap.Error(err))
+			return nil, err
+		}
+		if filterDecision.GetRecallFallback() {
+			resp, scanDecisions = fallback, nil
+		}
+	}
+	// the dropped queries were searched with a substitute vector, the hits of them are not theirs
+	if len(droppedQueries) > 0 {
+		log.Warn("queries with NaN or Inf vectors dropped", zap.Ints("queries", droppedQueries))
+		if err = segments.ClearSearchResultsOfQueries(resp, droppedQueries); err != nil {
+			log.Warn("failed to clear search results of dropped queries", zap.Error(err))
+			return nil, err
+		}
+	}
+	// ANN still uses topK to generate candidates, the threshold only drops the selected hits
+	if req.GetReq().GetEnableScoreThreshold() {
+		if err = segments.FilterSearchResultsByScore(resp, req.GetReq().GetScoreThreshold()); err != nil {
+			log.Warn("failed to filter search results by score threshold", zap.Error(err))
+			return nil, err
+		}
+	}
+	if iterToken != nil {
+		if err = pageAfterIteratorToken(resp, iterToken, pageTopK); err != nil {
+			log.Warn("failed to page search results after iterator token", zap.Error(err))
+			return nil, err
+		}
+	}
+	if req.GetReq().GetIsIterator() {
+		if err = fillNextIteratorToken(resp, iterToken, req.GetReq().GetMetricType()); err != nil {
+			log.Warn("failed to fill next search iterator token", zap.Error(err))
+			return nil, err
+		}
+	}
+	if err = segments.FillResultCounts(resp); err != nil {
+		log.Warn("failed to fill result counts of search results", zap.Error(err))
+		return nil, err
+	}
+	if req.GetReq().GetReturnSegmentId() {
+		if err = segments.FillSegmentHitDistributions(resp); err != nil {
+			log.Warn("failed to fill segment hit distributions of search results", zap.Error(err))
+			return nil, err
+		}
+	}
+	if req.GetReq().GetReturnRankScore() {
+		if err = segments.FillRankScores(resp); err != nil {
+			log.Warn("failed to fill rank scores of search results", zap.Error(err))
+			return nil, err
+		}
+	}
+	if req.GetReq().GetPerPartitionTopk() {
+		if err = segments.FillPartitionTopks(resp, lo.Uniq(req.GetReq().GetPartitionIDs())); err != nil {
+			log.Warn("failed to fill partition topks of search results", zap.Error(err))
+			return nil, err
+		}
+	}
+	if req.GetReq().GetOverfetch() > 0 {
+		if err = segments.FillPrimaryTopks(resp, primaryTopK); err != nil {
+			log.Warn("failed to fill primary topks of search results", zap.Error(err))
+			return nil, err
+		}
+	}
+	// hits are kept for reducing among channels, dropped after the statistics computed on the final result
+	if req.GetReq().GetScoreStatsOnly() {
+		if err = segments.FillScoreStats(resp); err != nil {
+			log.Warn("failed to fill score statistics of search results", zap.Error(err))
+			return nil, err
+		}
+	}
+	var facetBuckets []*internalpb.FacetBucket
+	if req.GetReq().GetFacet() != nil {
+		facetBuckets, err = node.facetSearch(searchCtx, sd, req, channel, resp)
+		if err != nil {
+			err = tagServerTimeout(ctx, searchCtx, err)
+			log.Warn("failed to bucket search results by facet", zap.Error(err))
+			return nil, err
+		}
+	}
+	var hitExplanations []*internalpb.HitExplanation
+	if req.GetReq().GetExplainPk() != nil {
+		hitExplanations, err = node.explainHit(searchCtx, sd, req, channel, resp)
+		if err != nil {
+			err = tagServerTimeout(ctx, searchCtx, err)
+			log.Warn("failed to explain hit of search results", zap.Error(err))
+			return nil, err
+		}
+	}
+	tr.CtxElapse(ctx, fmt.Sprintf("search and reduce done, traceID = %s, fromSharedLeader = %t, vChannel = %s, segmentIDs = %v",
 		traceID,
 		req.GetFromShardLeader(),
 		channel,
 		req.GetSegmentIDs(),
 	))
-
-	resp, err := segments.ReduceSearchResults(ctx, results, req.Req.GetNq(), req.Req.GetTopk(), req.Req.GetMetricType())
-	if err != nil {
-		return nil, err
+	resp.IsTopkCapped = topkCapped
+	resp.ScanDecisions = scanDecisions
+	resp.QueryFingerprint = fingerprint
+	resp.FacetBuckets = facetBuckets
+	resp.HitExplanations = hitExplanations
+	// the recall fallback is always reported to tune the threshold
+	if req.GetReq().GetExplain() || filterDecision.GetRecallFallback() {
+		resp.FilterStrategyDecisions = []*internalpb.FilterStrategyDecision{filterDecision}
+	}
+	if req.GetReq().GetExplain() {
+		channelNum := req.GetTotalChannelNum()
+		if channelNum <= 0 {
+			channelNum = 1
+		}
+		resp.ScanEstimates = []*internalpb.SearchScanEstimate{{
+			Channel:             channel,
+			EstimatedSegmentNum: int64(estimateSegmentNum(sealed, channelNum)),
+			ScannedSegments:     resp.GetScannedSegments(),
+			ScannedRows:         resp.GetScannedRows(),
+		}}
 	}
 
 	tr.CtxElapse(ctx, fmt.Sprintf("do search with channel done , vChannel = %s, segmentIDs = %v",
@@ -422,13 +892,160 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 	// update metric to prometheus
 	latency := tr.ElapseSpan()
 	metrics.QueryNodeSQReqLatency.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SearchLabel, metrics.Leader).Observe(float64(latency.Milliseconds()))
+	node.adaptiveTopK.Observe(req.GetReq().GetCollectionID(), latency)
+	node.latencyHistograms.Observe(req.GetReq().GetCollectionID(), latency)
+	if requestedPlan != nil {
+		node.searchParamLog.record(collectionID, requestedPlan, req.GetReq().GetSerializedExprPlan(), latency)
+	}
 	metrics.QueryNodeSQCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SearchLabel, metrics.SuccessLabel, metrics.Leader).Inc()
+	node.requestCounters.record(collectionID, metrics.SearchLabel, true)
 	metrics.QueryNodeSearchNQ.WithLabelValues(fmt.Sprint(paramtable.GetNodeID())).Observe(float64(req.Req.GetNq()))
 	metrics.QueryNodeSearchTopK.WithLabelValues(fmt.Sprint(paramtable.GetNodeID())).Observe(float64(req.Req.GetTopk()))
 
 	return resp, nil
 }
 
+// searchDelegator searches on delegator and reduces the results,
+// scan decisions are returned separately if explain requested.
+func (node *QueryNode) searchDelegator(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest) (*internalpb.SearchResults, []*internalpb.SegmentScanDecision, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.Int64("nq", req.GetReq().GetNq()),
+	)
+
+	results, err := sd.Search(ctx, req)
+	if err != nil {
+		log.Warn("failed to search on delegator", zap.Error(err))
+		return nil, nil, err
+	}
+	var scanDecisions []*internalpb.SegmentScanDecision
+	if req.GetReq().GetExplain() && len(results) > 0 {
+		scanDecisions = results[len(results)-1].GetScanDecisions()
+		results = results[:len(results)-1]
+	}
+
+	// account the memory materialized while reducing, to fail the request instead of oom
+	account := segments.NewRequestReduceMemoryAccount()
+	defer account.Free()
+	err = account.Grow(int64(lo.SumBy(results, func(result *internalpb.SearchResults) int { return len(result.GetSlicedBlob()) })))
+	if err != nil {
+		log.Warn("search results exceed reduce memory limit", zap.Error(err))
+		return nil, nil, err
+	}
+	reduceCtx := segments.WithReduceMemoryAccount(ctx, account)
+	reduceCtx = segments.WithExcludedPKs(reduceCtx, req.GetReq().GetExcludePks())
+	reduceCtx = segments.WithReduceAlgorithm(reduceCtx, req.GetReq().GetReduceAlgorithm())
+	reduceCtx = segments.WithInsertionOrderTiebreak(reduceCtx, req.GetReq().GetInsertionOrderTiebreak())
+	resp, err := segments.ReduceSearchResults(reduceCtx, results, req.Req.GetNq(), excludedReduceTopK(req), req.Req.GetMetricType())
+	if err != nil {
+		log.Warn("failed to reduce search results", zap.Error(err))
+		return nil, nil, err
+	}
+	resp = node.rerank(ctx, req.GetReq(), resp)
+	if segments.IsScoreFieldRequested(req.GetReq().GetOutputFieldsId()) {
+		if err := segments.FillScoreField(resp, req.GetReq().GetOutputFieldsId()); err != nil {
+			log.Warn("failed to fill score field", zap.Error(err))
+			return nil, nil, err
+		}
+	}
+	return resp, scanDecisions, nil
+}
+
+// searchDelegatorInNQBatches splits the search into batches of at most maxNQ queries,
+// and concatenates the results of batches in nq order.
+func (node *QueryNode) searchDelegatorInNQBatches(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, maxNQ int64) (*internalpb.SearchResults, []*internalpb.SegmentScanDecision, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.Int64("nq", req.GetReq().GetNq()),
+		zap.Int64("maxNQPerBatch", maxNQ),
+	)
+
+	batches, err := splitSearchRequestByNQ(req, maxNQ)
+	if err != nil {
+		log.Warn("failed to split search request by nq", zap.Error(err))
+		return nil, nil, err
+	}
+	log.Debug("search in nq batches", zap.Int("batchNum", len(batches)))
+
+	results := make([]*internalpb.SearchResults, len(batches))
+	decisions := make([][]*internalpb.SegmentScanDecision, len(batches))
+	group, groupCtx := errgroup.WithContext(ctx)
+	concurrency := paramtable.Get().QueryNodeCfg.NQBatchConcurrency.GetAsInt()
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	group.SetLimit(concurrency)
+	for i, batch := range batches {
+		i, batch := i, batch
+		group.Go(func() error {
+			var err error
+			results[i], decisions[i], err = node.searchDelegator(groupCtx, sd, batch)
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := concatSearchResults(results, req.GetReq().GetNq(), excludedReduceTopK(req), req.GetReq().GetMetricType())
+	if err != nil {
+		log.Warn("failed to concat search results of nq batches", zap.Error(err))
+		return nil, nil, err
+	}
+	// all batches search the same segments
+	return resp, decisions[0], nil
+}
+
+// rerank invokes the reranker plugin with reduced search results,
+// the original results are returned if reranker not configured or failed.
+func (node *QueryNode) rerank(ctx context.Context, req *internalpb.SearchRequest, result *internalpb.SearchResults) *internalpb.SearchResults {
+	if node.reranker == nil || result.GetSlicedBlob() == nil {
+		return result
+	}
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", req.GetCollectionID()))
+
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	if err != nil {
+		log.Warn("failed to decode search results for reranking, skip rerank", zap.Error(err))
+		return result
+	}
+	reranked, err := node.reranker.Rerank(ctx, req, data[0])
+	if err == nil {
+		err = validateRerankedResult(reranked, result.GetNumQueries())
+	}
+	if err != nil {
+		log.Warn("failed to rerank search results, fall back to original results", zap.Error(err))
+		return result
+	}
+
+	rerankedResult, err := segments.EncodeSearchResultData(reranked, result.GetNumQueries(), result.GetTopK(), result.GetMetricType())
+	if err != nil {
+		log.Warn("failed to encode reranked search results, fall back to original results", zap.Error(err))
+		return result
+	}
+	rerankedResult.CostAggregation = result.GetCostAggregation()
+	rerankedResult.IsPartial = result.GetIsPartial()
+	rerankedResult.TimedOutNodes = result.GetTimedOutNodes()
+	rerankedResult.Truncated = result.GetTruncated()
+	return rerankedResult
+}
+
+// validateRerankedResult checks the reranked result is well formed.
+func validateRerankedResult(result *schemapb.SearchResultData, nq int64) error {
+	if result == nil {
+		return merr.WrapErrParameterInvalidMsg("reranker returns nil result")
+	}
+	if int64(len(result.GetTopks())) != nq {
+		return merr.WrapErrParameterInvalid(nq, int64(len(result.GetTopks())), "reranker changes nq of result")
+	}
+	total := lo.Sum(result.GetTopks())
+	if int64(typeutil.GetSizeOfIDs(result.GetIds())) != total || int64(len(result.GetScores())) != total {
+		return merr.WrapErrParameterInvalidMsg("reranker returns %d ids and %d scores, expected %d",
+			typeutil.GetSizeOfIDs(result.GetIds()), len(result.GetScores()), total)
+	}
+	return nil
+}
+
 func (node *QueryNode) getChannelStatistics(ctx context.Context, req *querypb.GetStatisticsRequest, channel string) (*internalpb.GetStatisticsResponse, error) {
 	log := log.Ctx(ctx).With(
 		zap.Int64("collectionID", req.Req.GetCollectionID()),
@@ -438,6 +1055,11 @@ func (node *QueryNode) getChannelStatistics(ctx context.Context, req *querypb.Ge
 
 	resp := &internalpb.GetStatisticsResponse{}
 
+	if err := node.validateStatisticsRequest(req.GetReq()); err != nil {
+		log.Warn("invalid statistics request", zap.Error(err))
+		return nil, err
+	}
+
 	if req.GetFromShardLeader() {
 		var (
 			results      []segments.SegmentStats
@@ -457,7 +1079,7 @@ func (node *QueryNode) getChannelStatistics(ctx context.Context, req *querypb.Ge
 			return nil, err
 		}
 		defer node.manager.Segment.Unpin(readSegments)
-		return segmentStatsResponse(results), nil
+		return segmentStatsResponse(results, req.GetReq().GetPartitionKeyFieldID() != 0), nil
 	}
 
 	sd, ok := node.delegators.Get(channel)
@@ -484,14 +1106,49 @@ func (node *QueryNode) getChannelStatistics(ctx context.Context, req *querypb.Ge
 	return resp, nil
 }
 
-func segmentStatsResponse(segStats []segments.SegmentStats) *internalpb.GetStatisticsResponse {
+// validateStatisticsRequest checks the partition key field of request matches the collection schema.
+func (node *QueryNode) validateStatisticsRequest(req *internalpb.GetStatisticsRequest) error {
+	if req.GetPartitionKeyFieldID() == 0 {
+		return nil
+	}
+
+	collection := node.manager.Collection.Get(req.GetCollectionID())
+	if collection == nil {
+		return merr.WrapErrCollectionNotLoaded(req.GetCollectionID())
+	}
+	keyField, err := typeutil.GetPartitionKeyFieldSchema(collection.Schema())
+	if err != nil {
+		return merr.WrapErrParameterInvalidMsg("collection %d has no partition key field", req.GetCollectionID())
+	}
+	if keyField.GetFieldID() != req.GetPartitionKeyFieldID() {
+		return merr.WrapErrParameterInvalid(keyField.GetFieldID(), req.GetPartitionKeyFieldID(), "mismatched partition key field")
+	}
+	return nil
+}
+
+const partitionKeyBucketStatPrefix = "partition_key_bucket/"
+
+// partitionKeyBucketStatKey returns the statistic key of row count in a partition key bucket,
+// rows are hashed into partitions by partition key, so a bucket is identified by its partition ID.
+func partitionKeyBucketStatKey(partitionID int64) string {
+	return partitionKeyBucketStatPrefix + strconv.FormatInt(partitionID, 10)
+}
+
+func segmentStatsResponse(segStats []segments.SegmentStats, groupByPartitionKey bool) *internalpb.GetStatisticsResponse {
 	var totalRowNum int64
+	bucketRowNum := make(map[int64]int64)
 	for _, stats := range segStats {
 		totalRowNum += stats.RowCount
+		bucketRowNum[stats.PartitionID] += stats.RowCount
 	}
 
 	resultMap := make(map[string]string)
 	resultMap["row_count"] = strconv.FormatInt(totalRowNum, 10)
+	if groupByPartitionKey {
+		for partitionID, rowNum := range bucketRowNum {
+			resultMap[partitionKeyBucketStatKey(partitionID)] = strconv.FormatInt(rowNum, 10)
+		}
+	}
 
 	ret := &internalpb.GetStatisticsResponse{
 		Status: merr.Success(),
@@ -517,6 +1174,17 @@ func reduceStatisticResponse(results []*internalpb.GetStatisticsResponse) (*inte
 
 	for _, partialResult := range results {
 		for _, pair := range partialResult.Stats {
+			if strings.HasPrefix(pair.Key, partitionKeyBucketStatPrefix) {
+				count, err := strconv.ParseInt(pair.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				if merged, ok := mergedResults[pair.Key]; ok {
+					count += merged.(int64)
+				}
+				mergedResults[pair.Key] = count
+				continue
+			}
 			fn, ok := fieldMethod[pair.Key]
 			if !ok {
 				return nil, fmt.Errorf("unknown statistic field: %s", pair.Key)
@@ -538,3 +1206,1468 @@ func reduceStatisticResponse(results []*internalpb.GetStatisticsResponse) (*inte
 	}
 	return ret, nil
 }
+
+// SingleSegmentLoadStatus is the detailed result of LoadSingleSegment.
+type SingleSegmentLoadStatus struct {
+	SegmentID     int64
+	RowNum        int64
+	MemSize       int64
+	IndexedFields []int64
+	DeltaLogNum   int
+	LoadDataCost  time.Duration
+	LoadIndexCost time.Duration
+	LoadDeltaCost time.Duration
+}
+
+// LoadSingleSegment loads exactly one sealed segment synchronously, raw data, index and delta logs are loaded in turn.
+// It's designed for testing, the collection of segment shall be loaded already.
+func (node *QueryNode) LoadSingleSegment(ctx context.Context, info *querypb.SegmentLoadInfo) (*SingleSegmentLoadStatus, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", info.GetCollectionID()),
+		zap.Int64("segmentID", info.GetSegmentID()),
+	)
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collectionID := info.GetCollectionID()
+	if !node.manager.Collection.Ref(collectionID, 1) {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	defer node.manager.Collection.Unref(collectionID, 1)
+
+	if node.manager.Segment.GetSealed(info.GetSegmentID()) != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("segment %d already loaded", info.GetSegmentID())
+	}
+
+	status := &SingleSegmentLoadStatus{
+		SegmentID:   info.GetSegmentID(),
+		DeltaLogNum: len(info.GetDeltalogs()),
+	}
+	version := time.Now().UnixNano()
+	req := &querypb.LoadSegmentsRequest{
+		CollectionID: collectionID,
+		Infos:        []*querypb.SegmentLoadInfo{info},
+		Version:      version,
+	}
+
+	// load raw data only, index and delta logs are loaded in following steps
+	dataInfo := proto.Clone(info).(*querypb.SegmentLoadInfo)
+	dataInfo.IndexInfos = nil
+	dataInfo.Deltalogs = nil
+	tr := timerecord.NewTimeRecorder("loadSingleSegment")
+	loaded, err := node.loader.Load(ctx, collectionID, segments.SegmentTypeSealed, version, dataInfo)
+	if err != nil {
+		log.Warn("failed to load segment data", zap.Error(err))
+		return nil, err
+	}
+	node.manager.Collection.Ref(collectionID, uint32(len(loaded)))
+	status.LoadDataCost = tr.RecordSpan()
+
+	release := func() {
+		_, count := node.manager.Segment.Remove(info.GetSegmentID(), querypb.DataScope_Historical)
+		node.manager.Collection.Unref(collectionID, uint32(count))
+	}
+	if err := merr.Error(node.loadIndex(ctx, req)); err != nil {
+		release()
+		return nil, err
+	}
+	status.LoadIndexCost = tr.RecordSpan()
+	if err := merr.Error(node.loadDeltaLogs(ctx, req)); err != nil {
+		release()
+		return nil, err
+	}
+	status.LoadDeltaCost = tr.RecordSpan()
+
+	segment := node.manager.Segment.GetSealed(info.GetSegmentID())
+	if segment == nil {
+		return nil, merr.WrapErrSegmentNotLoaded(info.GetSegmentID(), "segment released while loading")
+	}
+	status.RowNum = segment.RowNum()
+	status.MemSize = segment.MemSize()
+	status.IndexedFields = lo.Map(segment.Indexes(), func(index *segments.IndexedFieldInfo, _ int) int64 {
+		return index.IndexInfo.GetFieldID()
+	})
+	log.Info("load single segment done", zap.Any("status", status))
+	return status, nil
+}
+
+// SegmentIndexProgress is the index state of sealed segment for brute-force fallback diagnosis.
+type SegmentIndexProgress struct {
+	SegmentID int64
+	// BruteForce is true if any vector field of segment is not indexed
+	BruteForce bool
+	// IndexLoading is true if index is being loaded into segment
+	IndexLoading bool
+	LoadingSince time.Time
+	// EstimatedCompletion is zero if unknown
+	EstimatedCompletion time.Time
+}
+
+// GetSegmentIndexProgress returns the index state of sealed segments of collection.
+// Index building happens on index nodes and is not visible here,
+// the node only knows the built index is being loaded after coordinator dispatches it.
+func (node *QueryNode) GetSegmentIndexProgress(ctx context.Context, collectionID int64) ([]SegmentIndexProgress, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	vecFields := funcutil.GetVecFieldIDs(collection.Schema())
+
+	sealed := node.manager.Segment.GetBy(segments.WithCollection(collectionID), segments.WithType(segments.SegmentTypeSealed))
+	progresses := make([]SegmentIndexProgress, 0, len(sealed))
+	for _, segment := range sealed {
+		loadProgress := node.loader.GetIndexLoadProgress(segment.ID())
+		progresses = append(progresses, SegmentIndexProgress{
+			SegmentID: segment.ID(),
+			BruteForce: lo.ContainsBy(vecFields, func(fieldID int64) bool {
+				return !segment.ExistIndex(fieldID)
+			}),
+			IndexLoading:        loadProgress.Loading,
+			LoadingSince:        loadProgress.StartTime,
+			EstimatedCompletion: loadProgress.EstimatedCompletion,
+		})
+	}
+	return progresses, nil
+}
+
+// CompactDeleteBuffer compacts the delete buffer of the delegator on provided channel,
+// returns the sizes of delete buffer before and after compaction.
+func (node *QueryNode) CompactDeleteBuffer(ctx context.Context, channel string) (delegator.DeleteBufferStats, delegator.DeleteBufferStats, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("nodeID", paramtable.GetNodeID()),
+		zap.String("channel", channel),
+	)
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return delegator.DeleteBufferStats{}, delegator.DeleteBufferStats{}, err
+	}
+	defer node.lifetime.Done()
+
+	sd, ok := node.delegators.Get(channel)
+	if !ok {
+		err := merr.WrapErrChannelNotFound(channel)
+		log.Warn("failed to compact delete buffer, delegator not found", zap.Error(err))
+		return delegator.DeleteBufferStats{}, delegator.DeleteBufferStats{}, err
+	}
+
+	before, after := sd.CompactDeleteBuffer(ctx)
+	return before, after, nil
+}
+
+// queryHookCacheSnapshot is the exported form of query hook decision cache.
+type queryHookCacheSnapshot struct {
+	Version string `json:"version"`
+	Data    []byte `json:"data"`
+}
+
+// queryHookCacheSnapshotter returns the query hook if it supports to snapshot the decision cache.
+func (node *QueryNode) queryHookCacheSnapshotter() (optimizers.QueryHookCacheSnapshotter, error) {
+	if node.queryHook == nil {
+		return nil, merr.WrapErrServiceUnavailable("query hook not enabled")
+	}
+	snapshotter, ok := node.queryHook.(optimizers.QueryHookCacheSnapshotter)
+	if !ok {
+		return nil, merr.WrapErrServiceUnavailable("query hook does not support cache snapshot")
+	}
+	return snapshotter, nil
+}
+
+// SnapshotQueryHookCache exports the decision cache of query hook along with its version.
+func (node *QueryNode) SnapshotQueryHookCache() ([]byte, error) {
+	snapshotter, err := node.queryHookCacheSnapshotter()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := snapshotter.SnapshotCache()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&queryHookCacheSnapshot{
+		Version: snapshotter.CacheVersion(),
+		Data:    data,
+	})
+}
+
+// RestoreQueryHookCache imports the decision cache exported by SnapshotQueryHookCache,
+// snapshot with incompatible cache version is rejected.
+func (node *QueryNode) RestoreQueryHookCache(snapshot []byte) error {
+	snapshotter, err := node.queryHookCacheSnapshotter()
+	if err != nil {
+		return err
+	}
+
+	cache := &queryHookCacheSnapshot{}
+	if err := json.Unmarshal(snapshot, cache); err != nil {
+		return merr.WrapErrParameterInvalidMsg("malformed query hook cache snapshot: %s", err.Error())
+	}
+	version := snapshotter.CacheVersion()
+	if cache.Version != version {
+		return merr.WrapErrParameterInvalid(version, cache.Version, "incompatible query hook cache version")
+	}
+	return snapshotter.RestoreCache(cache.Data)
+}
+
+// QueryHookInfo is the identity of the query hook running on the node.
+type QueryHookInfo struct {
+	Enabled bool
+	// PluginPath is the configured path the hook plugin is loaded from
+	PluginPath string
+	optimizers.QueryHookMetadata
+}
+
+// unknownQueryHookMetadata is reported for the hook not providing its metadata.
+var unknownQueryHookMetadata = optimizers.QueryHookMetadata{
+	Name:       "unknown",
+	Version:    "unknown",
+	ConfigHash: "unknown",
+}
+
+// GetQueryHookInfo returns the identity, version and config hash of the query hook, and whether it is enabled,
+// metadata is empty if no hook is loaded, and unknown if the hook doesn't provide it.
+func (node *QueryNode) GetQueryHookInfo() *QueryHookInfo {
+	info := &QueryHookInfo{
+		PluginPath: paramtable.Get().QueryNodeCfg.SoPath.GetValue(),
+	}
+	if node.queryHook == nil {
+		return info
+	}
+	info.Enabled = true
+	info.QueryHookMetadata = unknownQueryHookMetadata
+	if provider, ok := node.queryHook.(optimizers.QueryHookMetadataProvider); ok {
+		info.QueryHookMetadata = provider.Metadata()
+	}
+	return info
+}
+
+// saveQueryHookCache persists the query hook decision cache into local file if configured.
+func (node *QueryNode) saveQueryHookCache() {
+	path := paramtable.Get().QueryNodeCfg.QueryHookCachePath.GetValue()
+	if path == "" || node.queryHook == nil {
+		return
+	}
+	if _, ok := node.queryHook.(optimizers.QueryHookCacheSnapshotter); !ok {
+		return
+	}
+
+	log := log.With(zap.String("path", path))
+	snapshot, err := node.SnapshotQueryHookCache()
+	if err != nil {
+		log.Warn("failed to snapshot query hook cache", zap.Error(err))
+		return
+	}
+	if err := os.WriteFile(path, snapshot, 0o600); err != nil {
+		log.Warn("failed to save query hook cache", zap.Error(err))
+		return
+	}
+	log.Info("query hook cache saved", zap.Int("size", len(snapshot)))
+}
+
+// loadQueryHookCache restores the query hook decision cache from local file if configured.
+func (node *QueryNode) loadQueryHookCache() {
+	path := paramtable.Get().QueryNodeCfg.QueryHookCachePath.GetValue()
+	if path == "" || node.queryHook == nil {
+		return
+	}
+	if _, ok := node.queryHook.(optimizers.QueryHookCacheSnapshotter); !ok {
+		return
+	}
+
+	log := log.With(zap.String("path", path))
+	snapshot, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("failed to read query hook cache", zap.Error(err))
+		}
+		return
+	}
+	if err := node.RestoreQueryHookCache(snapshot); err != nil {
+		log.Warn("failed to restore query hook cache, start with cold cache", zap.Error(err))
+		return
+	}
+	log.Info("query hook cache restored", zap.Int("size", len(snapshot)))
+}
+
+// CanceledLoad is the result of a load operation canceled by CancelLoad.
+type CanceledLoad struct {
+	LoadID       int64
+	CollectionID int64
+	Kind         LoadKind
+	SegmentIDs   []int64
+	// segments released as they were partially loaded by the canceled operation
+	RolledBack []int64
+}
+
+// CancelLoad cancels all in-flight load operations of collection, and waits the partially loaded segments rolled back.
+func (node *QueryNode) CancelLoad(ctx context.Context, collectionID int64) ([]CanceledLoad, error) {
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", collectionID))
+
+	if err := node.lifetime.Add(merr.IsHealthyOrStopping); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	loads, err := node.loads.cancel(ctx, collectionID)
+	if err != nil {
+		log.Warn("failed to wait canceled loads done", zap.Error(err))
+		return nil, err
+	}
+
+	result := lo.Map(loads, func(load *inflightLoad, _ int) CanceledLoad {
+		return CanceledLoad{
+			LoadID:       load.id,
+			CollectionID: load.collectionID,
+			Kind:         load.kind,
+			SegmentIDs:   load.segmentIDs,
+			RolledBack:   load.rolledBack,
+		}
+	})
+	log.Info("load operations canceled", zap.Int("loadNum", len(result)))
+	return result, nil
+}
+
+// rollbackCanceledLoad releases the segments loaded by a failed load operation if it's canceled,
+// only the loaded segments returned by the loader are released, the ones loaded by others are not touched.
+func (node *QueryNode) rollbackCanceledLoad(load *inflightLoad, segmentType segments.SegmentType, loaded []int64, loadErr error) []int64 {
+	if loadErr == nil || !load.canceled.Load() {
+		return nil
+	}
+
+	scope := querypb.DataScope_Historical
+	if segmentType == segments.SegmentTypeGrowing {
+		scope = querypb.DataScope_Streaming
+	}
+	rolledBack := make([]int64, 0)
+	for _, segmentID := range loaded {
+		if node.manager.Segment.GetWithType(segmentID, segmentType) == nil {
+			continue
+		}
+		node.manager.Segment.Remove(segmentID, scope)
+		rolledBack = append(rolledBack, segmentID)
+	}
+	log.Info("rolled back canceled load",
+		zap.Int64("loadID", load.id),
+		zap.Int64("collectionID", load.collectionID),
+		zap.Int64s("segmentIDs", rolledBack))
+	return rolledBack
+}
+
+// DistinctCountResult is the approximate distinct count of a field.
+type DistinctCountResult struct {
+	FieldID  int64
+	Estimate int64
+	// relative standard error of the estimate
+	RelativeError float64
+}
+
+// ApproxDistinctCount estimates the distinct count of a scalar field among the rows matched by query request.
+// Each segment is sketched with hyperloglog, only the sketches are merged across segments and shard leaders.
+func (node *QueryNode) ApproxDistinctCount(ctx context.Context, req *querypb.QueryRequest, fieldID int64) (*DistinctCountResult, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.Int64("fieldID", fieldID),
+	)
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	field, ok := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == fieldID
+	})
+	if !ok {
+		return nil, merr.WrapErrFieldNotFound(fieldID)
+	}
+	if typeutil.IsVectorType(field.GetDataType()) {
+		return nil, merr.WrapErrParameterInvalidMsg("distinct count is not supported on vector field %s", field.GetName())
+	}
+
+	req.Req.DistinctCountFieldID = fieldID
+	if !lo.Contains(req.GetReq().GetOutputFieldsId(), fieldID) {
+		req.Req.OutputFieldsId = append(req.Req.OutputFieldsId, fieldID)
+	}
+	resp, err := node.Query(ctx, req)
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		log.Warn("failed to estimate distinct count", zap.Error(err))
+		return nil, err
+	}
+
+	return &DistinctCountResult{
+		FieldID:       fieldID,
+		Estimate:      resp.GetDistinctCount(),
+		RelativeError: resp.GetDistinctCountError(),
+	}, nil
+}
+
+// LoadedSchema is the schema a collection is served with.
+type LoadedSchema struct {
+	CollectionID int64
+	Schema       *schemapb.CollectionSchema
+	Version      string
+}
+
+// GetLoadedSchema returns the exact schema the node is serving for collection,
+// so that clients could verify their plans are built with the same schema version.
+func (node *QueryNode) GetLoadedSchema(ctx context.Context, collectionID int64) (*LoadedSchema, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		err := merr.WrapErrCollectionNotLoaded(collectionID)
+		log.Ctx(ctx).Warn("failed to get loaded schema", zap.Int64("collectionID", collectionID), zap.Error(err))
+		return nil, err
+	}
+
+	return &LoadedSchema{
+		CollectionID: collectionID,
+		Schema:       proto.Clone(collection.Schema()).(*schemapb.CollectionSchema),
+		Version:      collection.SchemaVersion(),
+	}, nil
+}
+
+// DumpedVectors is the raw vectors of entities dumped for debugging.
+type DumpedVectors struct {
+	FieldID  int64
+	DataType schemapb.DataType
+	Dim      int64
+	// pks of entities found, in the same order as vectors
+	IDs *schemapb.IDs
+	// stored bytes of each vector, float vectors are encoded in little endian
+	Vectors [][]byte
+}
+
+// DumpVectors retrieves the raw vectors of vector field for the given pks through the delegators of collection,
+// no distance is computed, so the vectors could be used to reproduce distance computations offline.
+// It's only available when vector dump enabled since it exposes raw embeddings.
+func (node *QueryNode) DumpVectors(ctx context.Context, collectionID int64, fieldID int64, pks *schemapb.IDs) (*DumpedVectors, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", collectionID),
+		zap.Int64("fieldID", fieldID),
+	)
+
+	if !paramtable.Get().QueryNodeCfg.EnableVectorDump.GetAsBool() {
+		return nil, merr.WrapErrPrivilegeNotPermitted("vector dump is disabled, set %s to enable it", paramtable.Get().QueryNodeCfg.EnableVectorDump.Key)
+	}
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	field, ok := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == fieldID
+	})
+	if !ok {
+		return nil, merr.WrapErrFieldNotFound(fieldID)
+	}
+	if !typeutil.IsVectorType(field.GetDataType()) {
+		return nil, merr.WrapErrParameterInvalidMsg("field %s is not a vector field", field.GetName())
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := pkTermPlan(pkField, pks)
+	if err != nil {
+		return nil, err
+	}
+	plan.OutputFieldIds = []int64{fieldID}
+	serializedPlan, err := proto.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]string, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() == collectionID {
+			channels = append(channels, channel)
+		}
+		return true
+	})
+	if len(channels) == 0 {
+		return nil, merr.WrapErrChannelNotFound(fmt.Sprintf("delegators of collection %d", collectionID))
+	}
+
+	resp, err := node.Query(ctx, &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			CollectionID:       collectionID,
+			SerializedExprPlan: serializedPlan,
+			OutputFieldsId:     []int64{fieldID},
+			MvccTimestamp:      typeutil.MaxTimestamp,
+			Limit:              typeutil.Unlimited,
+		},
+		DmlChannels: channels,
+		Scope:       querypb.DataScope_All,
+	})
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		log.Warn("failed to retrieve vectors to dump", zap.Error(err))
+		return nil, err
+	}
+
+	result := &DumpedVectors{
+		FieldID:  fieldID,
+		DataType: field.GetDataType(),
+		IDs:      resp.GetIds(),
+		Vectors:  make([][]byte, 0),
+	}
+	fieldData, ok := lo.Find(resp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldId() == fieldID
+	})
+	if !ok {
+		return result, nil
+	}
+	result.Dim = fieldData.GetVectors().GetDim()
+	result.Vectors, err = rawVectors(fieldData)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// pkTermPlan builds the retrieve plan filtering entities by pks.
+func pkTermPlan(pkField *schemapb.FieldSchema, pks *schemapb.IDs) (*planpb.PlanNode, error) {
+	values := make([]*planpb.GenericValue, 0, typeutil.GetSizeOfIDs(pks))
+	switch pkField.GetDataType() {
+	case schemapb.DataType_Int64:
+		for _, pk := range pks.GetIntId().GetData() {
+			values = append(values, &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: pk}})
+		}
+	case schemapb.DataType_VarChar:
+		for _, pk := range pks.GetStrId().GetData() {
+			values = append(values, &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: pk}})
+		}
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unsupported primary key type %s", pkField.GetDataType().String())
+	}
+	if len(values) == 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("no primary key provided")
+	}
+
+	return &planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{
+					Expr: &planpb.Expr_TermExpr{
+						TermExpr: &planpb.TermExpr{
+							ColumnInfo: &planpb.ColumnInfo{
+								FieldId:      pkField.GetFieldID(),
+								DataType:     pkField.GetDataType(),
+								IsPrimaryKey: true,
+							},
+							Values: values,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// rawVectors splits vector field data into the stored bytes of each row.
+func rawVectors(fieldData *schemapb.FieldData) ([][]byte, error) {
+	vectors := fieldData.GetVectors()
+	dim := int(vectors.GetDim())
+	var (
+		data    []byte
+		rowSize int
+	)
+	switch fieldData.GetType() {
+	case schemapb.DataType_FloatVector:
+		floats := vectors.GetFloatVector().GetData()
+		data = make([]byte, len(floats)*4)
+		for i, v := range floats {
+			binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+		}
+		rowSize = dim * 4
+	case schemapb.DataType_BinaryVector:
+		data = vectors.GetBinaryVector()
+		rowSize = dim / 8
+	case schemapb.DataType_Float16Vector:
+		data = vectors.GetFloat16Vector()
+		rowSize = dim * 2
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("vector dump is not supported on type %s", fieldData.GetType().String())
+	}
+	if rowSize <= 0 || len(data)%rowSize != 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid vector data size %d with dim %d", len(data), dim)
+	}
+
+	rows := make([][]byte, 0, len(data)/rowSize)
+	for offset := 0; offset < len(data); offset += rowSize {
+		rows = append(rows, data[offset:offset+rowSize])
+	}
+	return rows, nil
+}
+
+// LaggingSegment is a loaded segment not at the current target version of its delegator.
+type LaggingSegment struct {
+	SegmentID     int64
+	NodeID        int64
+	Growing       bool
+	TargetVersion int64
+}
+
+// ChannelTargetSkew reports whether segments served by a delegator are all at its current target version.
+type ChannelTargetSkew struct {
+	Channel       string
+	CollectionID  int64
+	TargetVersion int64
+	Consistent    bool
+	Lagging       []LaggingSegment
+}
+
+// GetTargetVersionSkew reports the segments lagging behind the current target version for each channel,
+// a handoff stuck with segments of different target versions could be found before causing inconsistent results.
+func (node *QueryNode) GetTargetVersionSkew(ctx context.Context) ([]*ChannelTargetSkew, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	skews := make([]*ChannelTargetSkew, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		skew := &ChannelTargetSkew{
+			Channel:       channel,
+			CollectionID:  sd.Collection(),
+			TargetVersion: sd.GetTargetVersion(),
+			Lagging:       make([]LaggingSegment, 0),
+		}
+		sealed, growing := sd.GetSegmentInfo(false)
+		for _, item := range sealed {
+			for _, segment := range item.Segments {
+				if segment.TargetVersion != skew.TargetVersion {
+					skew.Lagging = append(skew.Lagging, LaggingSegment{
+						SegmentID:     segment.SegmentID,
+						NodeID:        item.NodeID,
+						TargetVersion: segment.TargetVersion,
+					})
+				}
+			}
+		}
+		for _, segment := range growing {
+			if segment.TargetVersion != skew.TargetVersion {
+				skew.Lagging = append(skew.Lagging, LaggingSegment{
+					SegmentID:     segment.SegmentID,
+					NodeID:        segment.NodeID,
+					Growing:       true,
+					TargetVersion: segment.TargetVersion,
+				})
+			}
+		}
+		skew.Consistent = len(skew.Lagging) == 0
+		if !skew.Consistent {
+			log.Ctx(ctx).Info("delegator serves segments lagging behind target version",
+				zap.String("channel", channel),
+				zap.Int64("targetVersion", skew.TargetVersion),
+				zap.Int("laggingNum", len(skew.Lagging)),
+			)
+		}
+		skews = append(skews, skew)
+		return true
+	})
+	return skews, nil
+}
+
+// GetSegmentLoadErrors returns the recent segment load failures of the node, oldest first,
+// segments failing to load repeatedly could be found without searching logs across restarts.
+func (node *QueryNode) GetSegmentLoadErrors(ctx context.Context) ([]segments.SegmentLoadError, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.loader.GetLoadErrors(), nil
+}
+
+// EstimateLoadFeasibility estimates whether the segments of the load request could be held by the node,
+// with the projected memory and disk usage, so the coordinator could place segments without guessing.
+// Nothing is loaded or reserved.
+func (node *QueryNode) EstimateLoadFeasibility(ctx context.Context, req *querypb.LoadSegmentsRequest) (*segments.LoadFeasibility, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if err := merr.CheckTargetID(req.GetBase()); err != nil {
+		return nil, err
+	}
+
+	feasibility, err := node.loader.EstimateLoad(ctx, req.GetInfos()...)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to estimate load feasibility",
+			zap.Int64("collectionID", req.GetCollectionID()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return feasibility, nil
+}
+
+// SetSearchParamDefaults sets the default search params of collection, e.g. ef or nprobe,
+// which apply to the search requests leaving them unset, empty params remove the defaults.
+// The queryHook could still override them.
+func (node *QueryNode) SetSearchParamDefaults(ctx context.Context, collectionID int64, params map[string]any) error {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
+	if _, err := json.Marshal(params); err != nil {
+		return merr.WrapErrParameterInvalid("json marshalable search params", "search params with marshal error", err.Error())
+	}
+	node.searchParamDefaults.set(collectionID, params)
+	log.Ctx(ctx).Info("default search params updated",
+		zap.Int64("collectionID", collectionID),
+		zap.Any("params", params),
+	)
+	return nil
+}
+
+// GetSearchParamDefaults returns the default search params of all collections, with the last update time.
+func (node *QueryNode) GetSearchParamDefaults(ctx context.Context) ([]*SearchParamDefaults, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.searchParamDefaults.list(), nil
+}
+
+// checkReplica checks the delegator belongs to the requested replica, e.g. to compare index configs of replicas.
+// A node serves at most one replica of a collection, so the request is rejected instead of rerouted.
+func checkReplica(sd delegator.ShardDelegator, channel string, replicaID int64) error {
+	if replicaID <= 0 || sd.Replica() == replicaID {
+		return nil
+	}
+	return merr.WrapErrReplicaNotAvailable(replicaID,
+		fmt.Sprintf("channel %s is served by replica %d on this node", channel, sd.Replica()))
+}
+
+// SegmentRowCount is the row count of loaded segment served by the node, for compaction planning.
+type SegmentRowCount struct {
+	SegmentID   int64
+	PartitionID int64
+	Channel     string
+	Type        segments.SegmentType
+	// RawRowCount is the number of inserted rows, not effected by deletion
+	RawRowCount int64
+	// LiveRowCount is the number of rows minus the deletes applied to segment
+	LiveRowCount int64
+	DeletedRows  int64
+	DeleteRatio  float64
+}
+
+// GetSegmentRowCounts returns the raw and live row counts of segments of collection loaded on the node,
+// segments with high delete ratio are worth compacting, which may differ from datacoord's view
+// before the deletes are flushed.
+func (node *QueryNode) GetSegmentRowCounts(ctx context.Context, collectionID int64) ([]SegmentRowCount, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if node.manager.Collection.Get(collectionID) == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+
+	loaded := node.manager.Segment.GetBy(segments.WithCollection(collectionID))
+	counts := make([]SegmentRowCount, 0, len(loaded))
+	for _, segment := range loaded {
+		count := SegmentRowCount{
+			SegmentID:    segment.ID(),
+			PartitionID:  segment.Partition(),
+			Channel:      segment.Shard(),
+			Type:         segment.Type(),
+			RawRowCount:  segment.InsertCount(),
+			LiveRowCount: segment.RowNum(),
+		}
+		count.DeletedRows = count.RawRowCount - count.LiveRowCount
+		if count.RawRowCount > 0 {
+			count.DeleteRatio = float64(count.DeletedRows) / float64(count.RawRowCount)
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// RuntimeStats is the snapshot of scheduler queue and go runtime state of the node.
+type RuntimeStats struct {
+	// WaitingTasks and WaitingNQ are the tasks queued in scheduler and their total nq
+	WaitingTasks int64
+	WaitingNQ    int64
+	// InflightSearches and InflightQueries are the requests being served as shard leader
+	InflightSearches int64
+	InflightQueries  int64
+	Goroutines       int
+	NumGC            int64
+	// LastGC and LastGCPause are zero if no gc happened yet
+	LastGC      time.Time
+	LastGCPause time.Duration
+}
+
+// GetRuntimeStats returns the current scheduler queue depth, inflight requests and go runtime stats,
+// for live debugging without scraping metrics. It doesn't stop the world, so it's cheap to call frequently.
+func (node *QueryNode) GetRuntimeStats(ctx context.Context) (*RuntimeStats, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	stats := &RuntimeStats{
+		InflightSearches: node.inflightSearches.Load(),
+		InflightQueries:  node.inflightQueries.Load(),
+		Goroutines:       runtime.NumGoroutine(),
+	}
+	if node.scheduler != nil {
+		stats.WaitingTasks = node.scheduler.GetWaitingTaskTotal()
+		stats.WaitingNQ = node.scheduler.GetWaitingTaskTotalNQ()
+	}
+	gcStats := debug.GCStats{}
+	debug.ReadGCStats(&gcStats)
+	stats.NumGC = gcStats.NumGC
+	stats.LastGC = gcStats.LastGC
+	if len(gcStats.Pause) > 0 {
+		stats.LastGCPause = gcStats.Pause[0]
+	}
+	return stats, nil
+}
+
+// ServedCollection is the node-local inventory of a loaded collection.
+type ServedCollection struct {
+	CollectionID int64
+	// Channels are the channels whose delegators are on this node
+	Channels          []string
+	SealedSegmentNum  int
+	GrowingSegmentNum int
+	// SealedMemorySize and GrowingMemorySize are the memory sizes of segments in bytes
+	SealedMemorySize  int64
+	GrowingMemorySize int64
+}
+
+// GetServedCollections returns the collections loaded on the node, ordered by ID,
+// with the channels, segment numbers and memory usage of each one.
+func (node *QueryNode) GetServedCollections(ctx context.Context) ([]*ServedCollection, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collectionIDs := node.manager.Collection.List()
+	sort.Slice(collectionIDs, func(i, j int) bool { return collectionIDs[i] < collectionIDs[j] })
+	served := make([]*ServedCollection, 0, len(collectionIDs))
+	for _, collectionID := range collectionIDs {
+		collection := &ServedCollection{
+			CollectionID: collectionID,
+			Channels:     make([]string, 0),
+		}
+		node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+			if sd.Collection() == collectionID {
+				collection.Channels = append(collection.Channels, channel)
+			}
+			return true
+		})
+		sort.Strings(collection.Channels)
+
+		for _, segment := range node.manager.Segment.GetBy(segments.WithCollection(collectionID)) {
+			if segment.Type() == segments.SegmentTypeGrowing {
+				collection.GrowingSegmentNum++
+				collection.GrowingMemorySize += segment.MemSize()
+			} else {
+				collection.SealedSegmentNum++
+				collection.SealedMemorySize += segment.MemSize()
+			}
+		}
+		served = append(served, collection)
+	}
+	return served, nil
+}
+
+// SetDeleteApplyMode sets how the delta logs of collection are applied while loading segments,
+// empty mode to follow the node default, see paramtable queryNode.deleteApplyMode.
+// The lazy mode shortens the segment loading, while the first search or query of each segment
+// pays the latency of applying the deferred deletes. Both modes return identical results.
+// Switching to the eager mode applies the deferred deletes of loaded segments at once.
+func (node *QueryNode) SetDeleteApplyMode(ctx context.Context, collectionID int64, mode string) error {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
+	if mode != "" && mode != segments.DeleteApplyModeEager && mode != segments.DeleteApplyModeLazy {
+		return merr.WrapErrParameterInvalid("eager or lazy", mode, "invalid delete apply mode")
+	}
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	collection.SetDeleteApplyMode(mode)
+	log.Ctx(ctx).Info("delete apply mode updated",
+		zap.Int64("collectionID", collectionID),
+		zap.String("mode", collection.GetDeleteApplyMode()),
+	)
+
+	if collection.GetDeleteApplyMode() != segments.DeleteApplyModeEager {
+		return nil
+	}
+	for _, segment := range node.manager.Segment.GetBy(segments.WithCollection(collectionID)) {
+		local, ok := segment.(*segments.LocalSegment)
+		if !ok {
+			continue
+		}
+		if err := local.ApplyPendingDeltaData(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChannelDeleteStats is the delete state of delegator on channel.
+type ChannelDeleteStats struct {
+	Channel string
+	delegator.DeleteStats
+	// TotalRows and DeletedRows are the inserted rows and the rows deleted of the segments
+	// loaded on the node, segments served by other nodes are not counted
+	TotalRows   int64
+	DeletedRows int64
+	DeleteRatio float64
+}
+
+// GetChannelDeleteStats returns the delete state of delegators of collection, ordered by channel,
+// a channel with high delete ratio indicates the compaction is overdue.
+func (node *QueryNode) GetChannelDeleteStats(ctx context.Context, collectionID int64) ([]*ChannelDeleteStats, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if node.manager.Collection.Get(collectionID) == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+
+	stats := make([]*ChannelDeleteStats, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() != collectionID {
+			return true
+		}
+		channelStats := &ChannelDeleteStats{
+			Channel:     channel,
+			DeleteStats: sd.GetDeleteStats(),
+		}
+		for _, segment := range node.manager.Segment.GetBy(segments.WithCollection(collectionID), segments.WithChannel(channel)) {
+			channelStats.TotalRows += segment.InsertCount()
+			channelStats.DeletedRows += segment.InsertCount() - segment.RowNum()
+		}
+		if channelStats.TotalRows > 0 {
+			channelStats.DeleteRatio = float64(channelStats.DeletedRows) / float64(channelStats.TotalRows)
+		}
+		stats = append(stats, channelStats)
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Channel < stats[j].Channel })
+	return stats, nil
+}
+
+// DropCollectionPageCache advises the OS to drop the page cache of the mmap files of the sealed segments of collection,
+// returns the released bytes. It relieves the memory pressure without releasing the collection,
+// searches on the collection page the data back in on demand.
+func (node *QueryNode) DropCollectionPageCache(ctx context.Context, collectionID int64) (int64, error) {
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", collectionID))
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return 0, err
+	}
+	defer node.lifetime.Done()
+
+	if node.manager.Collection.Get(collectionID) == nil {
+		return 0, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	if len(paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue()) == 0 {
+		return 0, merr.WrapErrServiceUnavailable("mmap disabled, no page cache to drop")
+	}
+
+	var released int64
+	for _, segment := range node.manager.Segment.GetBy(segments.WithCollection(collectionID), segments.WithType(segments.SegmentTypeSealed)) {
+		bytes, err := segments.DropSegmentPageCache(segment.ID())
+		if err != nil {
+			log.Warn("failed to drop page cache of segment", zap.Int64("segmentID", segment.ID()), zap.Error(err))
+			return released, err
+		}
+		released += bytes
+	}
+	log.Info("page cache of collection dropped", zap.Int64("releasedBytes", released))
+	return released, nil
+}
+
+// CollectionMmapPolicy is the mmap policy of collection and the loaded segments not following it.
+type CollectionMmapPolicy struct {
+	CollectionID int64
+	Policy       string
+	// Mmapped is whether the segments of collection are mmapped under the policy
+	Mmapped bool
+	// PendingSegments are the loaded sealed segments whose mmap state differs from the policy,
+	// e.g. the ones failed to be reloaded, they keep serving as they are
+	PendingSegments []int64
+	// ReloadedSegments are the sealed segments reloaded to follow the policy, set only by SetMmapPolicy
+	ReloadedSegments []int64
+	// EstimatedMemoryDelta is the change of memory usage by the reloaded segments, set only by SetMmapPolicy,
+	// data moved into mmap files counts negative, the replaced segments are released once the reads on them are done
+	EstimatedMemoryDelta int64
+}
+
+// GetMmapPolicy returns the mmap policy of the loaded collection.
+func (node *QueryNode) GetMmapPolicy(ctx context.Context, collectionID int64) (*CollectionMmapPolicy, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	return node.collectionMmapPolicy(collection), nil
+}
+
+// SetMmapPolicy switches the loaded collection between in-memory and mmap at runtime without releasing it,
+// the loaded sealed segments not following the policy are reloaded one by one,
+// each keeps serving until the reloaded one is swapped in.
+func (node *QueryNode) SetMmapPolicy(ctx context.Context, collectionID int64, policy string) (*CollectionMmapPolicy, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", collectionID),
+		zap.String("policy", policy),
+	)
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	if err := collection.SetMmapPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	mmapped := len(collection.MmapDirPath()) > 0
+	reloaded := make([]int64, 0)
+	var memoryDelta int64
+	for _, segment := range node.manager.Segment.GetBy(segments.WithCollection(collectionID), segments.WithType(segments.SegmentTypeSealed)) {
+		local, ok := segment.(*segments.LocalSegment)
+		if !ok || local.Mmapped() == mmapped {
+			continue
+		}
+		memSize := local.MemSize()
+		newSegment, err := node.loader.Reload(ctx, local)
+		if err != nil {
+			log.Warn("failed to reload segment with mmap policy, keep serving it as it is",
+				zap.Int64("segmentID", segment.ID()),
+				zap.Error(err),
+			)
+			continue
+		}
+		reloaded = append(reloaded, segment.ID())
+		if mmapped {
+			memoryDelta -= memSize
+		} else {
+			memoryDelta += newSegment.MemSize()
+		}
+	}
+
+	result := node.collectionMmapPolicy(collection)
+	result.ReloadedSegments = reloaded
+	result.EstimatedMemoryDelta = memoryDelta
+	log.Info("mmap policy of collection set",
+		zap.Int64s("reloadedSegments", result.ReloadedSegments),
+		zap.Int64s("pendingSegments", result.PendingSegments),
+		zap.Int64("estimatedMemoryDelta", result.EstimatedMemoryDelta),
+	)
+	return result, nil
+}
+
+func (node *QueryNode) collectionMmapPolicy(collection *segments.Collection) *CollectionMmapPolicy {
+	result := &CollectionMmapPolicy{
+		CollectionID:    collection.ID(),
+		Policy:          collection.GetMmapPolicy(),
+		Mmapped:         len(collection.MmapDirPath()) > 0,
+		PendingSegments: make([]int64, 0),
+	}
+	for _, segment := range node.manager.Segment.GetBy(segments.WithCollection(collection.ID()), segments.WithType(segments.SegmentTypeSealed)) {
+		local, ok := segment.(*segments.LocalSegment)
+		if ok && local.Mmapped() != result.Mmapped {
+			result.PendingSegments = append(result.PendingSegments, segment.ID())
+		}
+	}
+	sort.Slice(result.PendingSegments, func(i, j int) bool {
+		return result.PendingSegments[i] < result.PendingSegments[j]
+	})
+	return result
+}
+
+// FieldIndexState compares the index of field loaded on the node with the one the coordinator assigned.
+type FieldIndexState struct {
+	CollectionID int64
+	FieldID      int64
+	// IndexName and TargetIndexType are empty if the coordinator assigned no index to the field
+	IndexName       string
+	TargetIndexType string
+	// TargetSegments is the number of target segments served by the node,
+	// LoadedIndexTypes counts the ones loaded with index by index type
+	TargetSegments   int
+	LoadedIndexTypes map[string]int
+	// BruteForceSegments are the target segments expected to be indexed but served by brute force
+	BruteForceSegments []int64
+	MatchesTarget      bool
+}
+
+// GetLoadedIndexes returns the loaded index of each indexed field of collection and whether it matches the index
+// assigned by the coordinator, for the segments in target of delegators served by the node,
+// or all loaded sealed segments if the node is not a delegator of the collection.
+// Segments too small to build index are not expected to be indexed.
+func (node *QueryNode) GetLoadedIndexes(ctx context.Context, collectionID int64) ([]*FieldIndexState, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+
+	targets := node.targetSealedSegments(collectionID)
+	states := make(map[int64]*FieldIndexState)
+	getState := func(fieldID int64) *FieldIndexState {
+		state, ok := states[fieldID]
+		if !ok {
+			state = &FieldIndexState{
+				CollectionID:       collectionID,
+				FieldID:            fieldID,
+				TargetSegments:     len(targets),
+				LoadedIndexTypes:   make(map[string]int),
+				BruteForceSegments: make([]int64, 0),
+			}
+			states[fieldID] = state
+		}
+		return state
+	}
+	for _, meta := range collection.IndexMeta().GetIndexMetas() {
+		state := getState(meta.GetFieldID())
+		state.IndexName = meta.GetIndexName()
+		state.TargetIndexType = funcutil.KeyValuePair2Map(meta.GetIndexParams())[common.IndexTypeKey]
+	}
+	for _, segment := range targets {
+		for _, index := range segment.Indexes() {
+			if index.IndexInfo == nil || !index.IndexInfo.GetEnableIndex() {
+				continue
+			}
+			indexType := funcutil.KeyValuePair2Map(index.IndexInfo.GetIndexParams())[common.IndexTypeKey]
+			getState(index.IndexInfo.GetFieldID()).LoadedIndexTypes[indexType]++
+		}
+	}
+
+	minIndexRows := paramtable.Get().DataCoordCfg.MinSegmentNumRowsToEnableIndex.GetAsInt64()
+	result := make([]*FieldIndexState, 0, len(states))
+	for fieldID, state := range states {
+		state.MatchesTarget = true
+		for indexType := range state.LoadedIndexTypes {
+			if indexType != state.TargetIndexType {
+				state.MatchesTarget = false
+			}
+		}
+		if state.TargetIndexType != "" {
+			for _, segment := range targets {
+				if !segment.ExistIndex(fieldID) && segment.InsertCount() >= minIndexRows {
+					state.BruteForceSegments = append(state.BruteForceSegments, segment.ID())
+				}
+			}
+			state.MatchesTarget = state.MatchesTarget && len(state.BruteForceSegments) == 0
+		}
+		if !state.MatchesTarget {
+			log.Ctx(ctx).Warn("loaded index drifts from target",
+				zap.Int64("collectionID", collectionID),
+				zap.Int64("fieldID", fieldID),
+				zap.String("targetIndexType", state.TargetIndexType),
+				zap.Any("loadedIndexTypes", state.LoadedIndexTypes),
+				zap.Int64s("bruteForceSegments", state.BruteForceSegments),
+			)
+		}
+		result = append(result, state)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FieldID < result[j].FieldID
+	})
+	return result, nil
+}
+
+// targetSealedSegments returns the sealed segments in target of delegators of collection served by the node,
+// or all loaded sealed segments of collection if the node is not a delegator of it.
+func (node *QueryNode) targetSealedSegments(collectionID int64) []segments.Segment {
+	targets := make([]segments.Segment, 0)
+	isDelegator := false
+	node.delegators.Range(func(_ string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() != collectionID {
+			return true
+		}
+		isDelegator = true
+		sealed, _ := sd.GetSegmentInfo(true)
+		for _, item := range sealed {
+			if item.NodeID != paramtable.GetNodeID() {
+				continue
+			}
+			for _, entry := range item.Segments {
+				if segment := node.manager.Segment.GetSealed(entry.SegmentID); segment != nil {
+					targets = append(targets, segment)
+				}
+			}
+		}
+		return true
+	})
+	if !isDelegator {
+		targets = node.manager.Segment.GetBy(segments.WithCollection(collectionID), segments.WithType(segments.SegmentTypeSealed))
+	}
+	return targets
+}
+
+// SegmentIndexConversion is the index a segment serves for an indexed field against the index type assigned by the coordinator.
+type SegmentIndexConversion struct {
+	SegmentID int64
+	FieldID   int64
+	// ServedIndexType is empty if the field of segment is served by brute force
+	ServedIndexType string
+	TargetIndexType string
+	// StaleIndex is true if the segment still serves an index of another type than the target
+	StaleIndex bool
+	// Converted is true if the segment serves the target index type,
+	// or is too small to build index and served by brute force
+	Converted bool
+}
+
+// IndexConversionReport is the index conversion state of the segments of collection served by the node.
+type IndexConversionReport struct {
+	CollectionID int64
+	Segments     []*SegmentIndexConversion
+	// StaleSegments are the segments still serving an index of other type than the target
+	StaleSegments []int64
+	// Completed is true if all segments are converted to the target index type
+	Completed bool
+}
+
+// CheckIndexConversion reports the index type each segment serves for every indexed field of collection,
+// and whether it matches the index type assigned by the coordinator, so that index migrations,
+// e.g. from IVF to HNSW, could be gated on all segments served by the node having converted.
+// The segments checked are the same as GetLoadedIndexes.
+func (node *QueryNode) CheckIndexConversion(ctx context.Context, collectionID int64) (*IndexConversionReport, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+
+	targetIndexTypes := make(map[int64]string)
+	for _, meta := range collection.IndexMeta().GetIndexMetas() {
+		targetIndexTypes[meta.GetFieldID()] = funcutil.KeyValuePair2Map(meta.GetIndexParams())[common.IndexTypeKey]
+	}
+	fieldIDs := lo.Keys(targetIndexTypes)
+	sort.Slice(fieldIDs, func(i, j int) bool {
+		return fieldIDs[i] < fieldIDs[j]
+	})
+
+	targets := node.targetSealedSegments(collectionID)
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].ID() < targets[j].ID()
+	})
+
+	minIndexRows := paramtable.Get().DataCoordCfg.MinSegmentNumRowsToEnableIndex.GetAsInt64()
+	report := &IndexConversionReport{
+		CollectionID:  collectionID,
+		Segments:      make([]*SegmentIndexConversion, 0, len(targets)*len(fieldIDs)),
+		StaleSegments: make([]int64, 0),
+		Completed:     true,
+	}
+	for _, segment := range targets {
+		servedIndexTypes := make(map[int64]string)
+		for _, index := range segment.Indexes() {
+			if index.IndexInfo == nil || !index.IndexInfo.GetEnableIndex() {
+				continue
+			}
+			servedIndexTypes[index.IndexInfo.GetFieldID()] = funcutil.KeyValuePair2Map(index.IndexInfo.GetIndexParams())[common.IndexTypeKey]
+		}
+
+		stale := false
+		for _, fieldID := range fieldIDs {
+			conversion := &SegmentIndexConversion{
+				SegmentID:       segment.ID(),
+				FieldID:         fieldID,
+				ServedIndexType: servedIndexTypes[fieldID],
+				TargetIndexType: targetIndexTypes[fieldID],
+			}
+			if conversion.ServedIndexType == "" {
+				conversion.Converted = segment.InsertCount() < minIndexRows
+			} else {
+				conversion.StaleIndex = conversion.ServedIndexType != conversion.TargetIndexType
+				conversion.Converted = !conversion.StaleIndex
+			}
+			stale = stale || conversion.StaleIndex
+			report.Completed = report.Completed && conversion.Converted
+			report.Segments = append(report.Segments, conversion)
+		}
+		if stale {
+			report.StaleSegments = append(report.StaleSegments, segment.ID())
+		}
+	}
+	if len(report.StaleSegments) > 0 {
+		log.Ctx(ctx).Info("segments still serve index of other type than target",
+			zap.Int64("collectionID", collectionID),
+			zap.Int64s("staleSegments", report.StaleSegments),
+		)
+	}
+	return report, nil
+}
+
+// ChannelCheckpointLag is the lag between the latest position of a channel and the one consumed by its delegator.
+type ChannelCheckpointLag struct {
+	Channel      string
+	CollectionID int64
+	// CheckpointTs is the tsafe of delegator, data before it is searchable
+	CheckpointTs   uint64
+	CheckpointTime time.Time
+	TimeLag        time.Duration
+}
+
+// GetChannelCheckpointLag returns how stale the searchable data of each channel served as delegator is.
+// The latest position of channel is approximated by the current time since time ticks are produced continuously,
+// the lag in message count is not reported as message ids of mq are opaque.
+func (node *QueryNode) GetChannelCheckpointLag(ctx context.Context) ([]*ChannelCheckpointLag, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	now := time.Now()
+	lags := make([]*ChannelCheckpointLag, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		checkpointTs := sd.GetTSafe()
+		checkpointTime := tsoutil.PhysicalTime(checkpointTs)
+		lag := now.Sub(checkpointTime)
+		if lag < 0 {
+			lag = 0
+		}
+		lags = append(lags, &ChannelCheckpointLag{
+			Channel:        channel,
+			CollectionID:   sd.Collection(),
+			CheckpointTs:   checkpointTs,
+			CheckpointTime: checkpointTime,
+			TimeLag:        lag,
+		})
+		return true
+	})
+	sort.Slice(lags, func(i, j int) bool {
+		return lags[i].Channel < lags[j].Channel
+	})
+	return lags, nil
+}
+
+// SetFieldDenylist sets the fields of collection never returned by retrieve to the callers except the allowed users,
+// the caller is identified by the authorization in request metadata, empty fields remove the denylist.
+func (node *QueryNode) SetFieldDenylist(ctx context.Context, collectionID int64, fieldIDs []int64, allowedUsers []string) error {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
+	for _, fieldID := range fieldIDs {
+		if fieldID < common.StartOfUserFieldID {
+			return merr.WrapErrParameterInvalid("user field", fieldID, "system fields could not be denied")
+		}
+	}
+	node.fieldDenylists.set(collectionID, fieldIDs, allowedUsers)
+	log.Ctx(ctx).Info("field denylist updated",
+		zap.Int64("collectionID", collectionID),
+		zap.Int64s("fieldIDs", fieldIDs),
+		zap.Strings("allowedUsers", allowedUsers),
+	)
+	return nil
+}
+
+// GetFieldDenylists returns the field denylists of all collections, with the last update time.
+func (node *QueryNode) GetFieldDenylists(ctx context.Context) ([]*FieldDenylist, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.fieldDenylists.list(), nil
+}
+
+// SetQueryTimeoutOverride sets the max timeout of search and query of collection served as shard leader,
+// overriding queryNode.maxQueryTimeout, which could be longer or shorter than it but bounded by
+// queryNode.queryTimeoutOverride.max. The deadline of client is still kept if earlier. 0 removes the override.
+func (node *QueryNode) SetQueryTimeoutOverride(ctx context.Context, collectionID int64, timeout time.Duration) error {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
+	if err := node.queryTimeoutOverrides.set(collectionID, timeout); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info("query timeout override updated",
+		zap.Int64("collectionID", collectionID),
+		zap.Duration("timeout", timeout),
+	)
+	return nil
+}
+
+// GetQueryTimeoutOverrides returns the query timeout overrides of all collections, with the last update time.
+func (node *QueryNode) GetQueryTimeoutOverrides(ctx context.Context) ([]*QueryTimeoutOverride, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.queryTimeoutOverrides.list(), nil
+}
+
+// GetSearchLatencyPercentiles returns the p50/p95/p99 search latency of collections served as shard leader
+// over the sliding window of queryNode.latencyHistogram.window, for adaptive tuning and autoscalers.
+func (node *QueryNode) GetSearchLatencyPercentiles(ctx context.Context) ([]*optimizers.LatencyPercentiles, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.latencyHistograms.List(), nil
+}
@@ -18,126 +18,3976 @@ package querynodev2
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
 	"os"
+	"runtime"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/planpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/proto/segcorepb"
 	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
 	"github.com/milvus-io/milvus/internal/querynodev2/optimizers"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/internal/querynodev2/tasks"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/testutils"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 type HandlersSuite struct {
-	suite.Suite
+	testutils.PromMetricsSuite
 	// Data
 	collectionID   int64
 	collectionName string
 	segmentID      int64
 	channel        string
 
-	// Dependency
-	params              *paramtable.ComponentParam
-	node                *QueryNode
-	etcd                *clientv3.Client
-	chunkManagerFactory *storage.ChunkManagerFactory
+	// Dependency
+	params              *paramtable.ComponentParam
+	node                *QueryNode
+	etcd                *clientv3.Client
+	chunkManagerFactory *storage.ChunkManagerFactory
+
+	// Mock
+	factory *dependency.MockFactory
+}
+
+func (suite *HandlersSuite) SetupSuite() {
+	suite.collectionID = 111
+	suite.collectionName = "test-collection"
+	suite.segmentID = 1
+	suite.channel = "test-channel"
+}
+
+func (suite *HandlersSuite) SetupTest() {
+	var err error
+	paramtable.Init()
+	suite.params = paramtable.Get()
+	suite.params.Save(suite.params.QueryNodeCfg.GCEnabled.Key, "false")
+
+	// mock factory
+	suite.factory = dependency.NewMockFactory(suite.T())
+	suite.chunkManagerFactory = storage.NewChunkManagerFactory("local", storage.RootPath("/tmp/milvus_test"))
+
+	// new node
+	suite.node = NewQueryNode(context.Background(), suite.factory)
+	// init etcd
+	suite.etcd, err = etcd.GetEtcdClient(
+		suite.params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
+		suite.params.EtcdCfg.EtcdUseSSL.GetAsBool(),
+		suite.params.EtcdCfg.Endpoints.GetAsStrings(),
+		suite.params.EtcdCfg.EtcdTLSCert.GetValue(),
+		suite.params.EtcdCfg.EtcdTLSKey.GetValue(),
+		suite.params.EtcdCfg.EtcdTLSCACert.GetValue(),
+		suite.params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	suite.NoError(err)
+}
+
+func (suite *HandlersSuite) TearDownTest() {
+	suite.etcd.Close()
+	os.RemoveAll("/tmp/milvus-test")
+}
+
+func (suite *HandlersSuite) TestLoadGrowingSegments() {
+	ctx := context.Background()
+	var err error
+	// mock
+	loadSegmetns := []int64{}
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().LoadGrowing(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) {
+		for _, info := range infos {
+			loadSegmetns = append(loadSegmetns, info.SegmentID)
+		}
+	}).Return(nil, nil)
+
+	req := &querypb.WatchDmChannelsRequest{
+		Infos: []*datapb.VchannelInfo{
+			{
+				CollectionID:        suite.collectionID,
+				ChannelName:         suite.channel,
+				UnflushedSegmentIds: []int64{suite.segmentID},
+			},
+		},
+		SegmentInfos: make(map[int64]*datapb.SegmentInfo),
+	}
+
+	// unflushed segment not in segmentInfos, will skip
+	_, err = loadGrowingSegments(ctx, sd, req)
+	suite.NoError(err)
+	suite.Equal(0, len(loadSegmetns))
+
+	// binlog was empty, will skip, only counted by metric in skipWithMetric policy
+	req.SegmentInfos[suite.segmentID] = &datapb.SegmentInfo{
+		ID:           suite.segmentID,
+		CollectionID: suite.collectionID,
+		Binlogs:      make([]*datapb.FieldBinlog, 0),
+	}
+	emptyBinlogCount := metrics.QueryNodeEmptyBinlogGrowingSegmentCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), fmt.Sprint(suite.collectionID))
+	before := testutil.ToFloat64(emptyBinlogCount)
+	_, err = loadGrowingSegments(ctx, sd, req)
+	suite.NoError(err)
+	suite.Equal(0, len(loadSegmetns))
+	suite.MetricsEqual(emptyBinlogCount, before)
+
+	suite.params.Save(suite.params.QueryNodeCfg.EmptyGrowingBinlogPolicy.Key, emptyBinlogPolicySkipWithMetric)
+	defer suite.params.Reset(suite.params.QueryNodeCfg.EmptyGrowingBinlogPolicy.Key)
+	_, err = loadGrowingSegments(ctx, sd, req)
+	suite.NoError(err)
+	suite.Equal(0, len(loadSegmetns))
+	suite.MetricsEqual(emptyBinlogCount, before+1)
+
+	// binlog was empty, load fails in error policy
+	suite.params.Save(suite.params.QueryNodeCfg.EmptyGrowingBinlogPolicy.Key, emptyBinlogPolicyError)
+	_, err = loadGrowingSegments(ctx, sd, req)
+	suite.ErrorIs(err, merr.ErrSegmentLack)
+	suite.Equal(0, len(loadSegmetns))
+	suite.params.Save(suite.params.QueryNodeCfg.EmptyGrowingBinlogPolicy.Key, emptyBinlogPolicySkip)
+
+	// normal load
+	binlog := &datapb.FieldBinlog{}
+	req.SegmentInfos[suite.segmentID].Binlogs = append(req.SegmentInfos[suite.segmentID].Binlogs, binlog)
+	_, err = loadGrowingSegments(ctx, sd, req)
+	suite.NoError(err)
+	suite.Equal(1, len(loadSegmetns))
+
+	// exceeds the growing segment cap of channel
+	suite.params.Save(suite.params.QueryNodeCfg.GrowingSegmentMaxPerChannel.Key, "1")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.GrowingSegmentMaxPerChannel.Key)
+	sd.EXPECT().GetSegmentInfo(false).Return(nil, []delegator.SegmentEntry{{SegmentID: suite.segmentID + 1}})
+	_, err = loadGrowingSegments(ctx, sd, req)
+	suite.ErrorIs(err, merr.ErrServiceRequestLimitExceeded)
+	suite.Equal(1, len(loadSegmetns))
+}
+
+func (suite *HandlersSuite) TestCompactDeleteBuffer() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, _, err := suite.node.CompactDeleteBuffer(ctx, suite.channel)
+	suite.Error(err)
+
+	// delegator not found
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	_, _, err = suite.node.CompactDeleteBuffer(ctx, suite.channel)
+	suite.ErrorIs(err, merr.ErrChannelNotFound)
+
+	// normal case
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().CompactDeleteBuffer(mock.Anything).Return(
+		delegator.DeleteBufferStats{DeleteBufferSize: 200, DeleteBufferFragments: 2},
+		delegator.DeleteBufferStats{DeleteBufferSize: 100, DeleteBufferFragments: 1},
+	)
+	suite.node.delegators.Insert(suite.channel, sd)
+	before, after, err := suite.node.CompactDeleteBuffer(ctx, suite.channel)
+	suite.NoError(err)
+	suite.EqualValues(200, before.DeleteBufferSize)
+	suite.EqualValues(100, after.DeleteBufferSize)
+}
+
+func (suite *HandlersSuite) TestSearchChannelWithoutSegments() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1}}, []delegator.SegmentEntry{})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	placeholderGroup, err := genPlaceHolderGroup(2)
+	suite.Require().NoError(err)
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			CollectionID:     suite.collectionID,
+			MetricType:       "L2",
+			Nq:               2,
+			Topk:             10,
+			PlaceholderGroup: placeholderGroup,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.NoError(err)
+	suite.NoError(merr.Error(result.GetStatus()))
+	suite.EqualValues(2, result.GetNumQueries())
+	suite.EqualValues(10, result.GetTopK())
+	suite.Equal("L2", result.GetMetricType())
+	suite.Nil(result.GetSlicedBlob())
+	// search shall not be dispatched to delegator
+	sd.AssertNotCalled(suite.T(), "Search", mock.Anything, mock.Anything)
+}
+
+func (suite *HandlersSuite) TestSearchChannelInNQBatches() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	suite.params.Save(suite.params.QueryNodeCfg.MaxNQPerBatch.Key, "2")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.MaxNQPerBatch.Key)
+
+	const (
+		nq   = 5
+		topk = 1
+	)
+	// each query hits the pk of its offset in the whole request
+	searched := int64(0)
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		batchNQ := req.GetReq().GetNq()
+		suite.LessOrEqual(batchNQ, int64(2))
+		placeholderGroup := &commonpb.PlaceholderGroup{}
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetPlaceholderGroup(), placeholderGroup))
+		suite.Len(placeholderGroup.GetPlaceholders()[0].GetValues(), int(batchNQ))
+
+		ids := make([]int64, 0, batchNQ)
+		scores := make([]float32, 0, batchNQ)
+		topks := make([]int64, 0, batchNQ)
+		for i := int64(0); i < batchNQ; i++ {
+			ids = append(ids, searched+i)
+			scores = append(scores, 1.0)
+			topks = append(topks, topk)
+		}
+		searched += batchNQ
+		result, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+			NumQueries: batchNQ,
+			TopK:       topk,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+			Scores:     scores,
+			Topks:      topks,
+		}, batchNQ, topk, "L2")
+		return []*internalpb.SearchResults{result}, err
+	}).Times(3)
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	placeholderGroup, err := genPlaceHolderGroup(nq)
+	suite.Require().NoError(err)
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			CollectionID:     suite.collectionID,
+			MetricType:       "L2",
+			Nq:               nq,
+			Topk:             topk,
+			PlaceholderGroup: placeholderGroup,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.EqualValues(nq, result.GetNumQueries())
+	suite.EqualValues(nq, req.GetReq().GetNq())
+
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 1, 1, 1, 1}, data[0].GetTopks())
+	suite.Equal([]int64{0, 1, 2, 3, 4}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]int64{1, 1, 1, 1, 1}, result.GetTopks())
+	suite.Equal([]bool{false, false, false, false, false}, result.GetTruncated())
+}
+
+func (suite *HandlersSuite) TestValidateSearchRequest() {
+	suite.node.manager = segments.NewManager()
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.node.manager.Collection.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType:     querypb.LoadType_LoadCollection,
+		CollectionID: suite.collectionID,
+	})
+
+	planStr, err := genDSLByIndexType(schema, IndexFaissIDMap)
+	suite.Require().NoError(err)
+	var plan planpb.PlanNode
+	suite.Require().NoError(proto.UnmarshalText(planStr, &plan))
+	serializedPlan, err := proto.Marshal(&plan)
+	suite.Require().NoError(err)
+	placeholderGroup, err := genPlaceHolderGroup(2)
+	suite.Require().NoError(err)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			CollectionID:       suite.collectionID,
+			SerializedExprPlan: serializedPlan,
+			PlaceholderGroup:   placeholderGroup,
+			Nq:                 2,
+		},
+	}
+	suite.NoError(suite.node.validateSearchRequest(req))
+
+	// nq mismatch
+	req.Req.Nq = 3
+	suite.ErrorIs(suite.node.validateSearchRequest(req), merr.ErrParameterInvalid)
+
+	// nq = 0
+	req.Req.Nq = 0
+	suite.ErrorIs(suite.node.validateSearchRequest(req), merr.ErrParameterInvalid)
+
+	// empty placeholder group
+	req.Req.Nq = 2
+	req.Req.PlaceholderGroup = nil
+	suite.ErrorIs(suite.node.validateSearchRequest(req), merr.ErrParameterInvalid)
+
+	// dimension mismatch
+	placeholderGroup, err = proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{
+			{
+				Tag:    "$0",
+				Type:   commonpb.PlaceholderType_FloatVector,
+				Values: [][]byte{make([]byte, (defaultDim+1)*4), make([]byte, (defaultDim+1)*4)},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+	req.Req.PlaceholderGroup = placeholderGroup
+	suite.ErrorIs(suite.node.validateSearchRequest(req), merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestPartitionKeyBucketStatistics() {
+	suite.node.manager = segments.NewManager()
+	req := &internalpb.GetStatisticsRequest{
+		CollectionID:        suite.collectionID,
+		PartitionKeyFieldID: 103,
+	}
+	suite.ErrorIs(suite.node.validateStatisticsRequest(req), merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.node.manager.Collection.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	// no partition key field
+	suite.ErrorIs(suite.node.validateStatisticsRequest(req), merr.ErrParameterInvalid)
+
+	schema.Fields[3].IsPartitionKey = true
+	suite.NoError(suite.node.validateStatisticsRequest(req))
+	req.PartitionKeyFieldID = 100
+	suite.ErrorIs(suite.node.validateStatisticsRequest(req), merr.ErrParameterInvalid)
+
+	leader1 := segmentStatsResponse([]segments.SegmentStats{
+		{SegmentID: 1, PartitionID: 10, RowCount: 100},
+		{SegmentID: 2, PartitionID: 10, RowCount: 50},
+		{SegmentID: 3, PartitionID: 11, RowCount: 20},
+	}, true)
+	leader2 := segmentStatsResponse([]segments.SegmentStats{
+		{SegmentID: 4, PartitionID: 11, RowCount: 30},
+		{SegmentID: 5, PartitionID: 12, RowCount: 1},
+	}, true)
+	resp, err := reduceStatisticResponse([]*internalpb.GetStatisticsResponse{leader1, leader2})
+	suite.Require().NoError(err)
+	stats := funcutil.KeyValuePair2Map(resp.GetStats())
+	suite.Equal("201", stats["row_count"])
+	suite.Equal("150", stats[partitionKeyBucketStatKey(10)])
+	suite.Equal("50", stats[partitionKeyBucketStatKey(11)])
+	suite.Equal("1", stats[partitionKeyBucketStatKey(12)])
+
+	// not grouped
+	resp = segmentStatsResponse([]segments.SegmentStats{{SegmentID: 1, PartitionID: 10, RowCount: 100}}, false)
+	suite.Len(resp.GetStats(), 1)
+}
+
+func (suite *HandlersSuite) TestEstimateSearchThreads() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: segments.NewCollectionManager(),
+		Segment:    segmentManager,
+	}
+
+	// collection not loaded
+	_, err := suite.node.EstimateSearchThreads(ctx, &SearchShape{CollectionID: suite.collectionID})
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.node.manager.Collection.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	mockSegment := func(segmentID, partitionID int64) segments.Segment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().ID().Return(segmentID).Maybe()
+		segment.EXPECT().Partition().Return(partitionID).Maybe()
+		return segment
+	}
+	segmentManager.EXPECT().GetBy(mock.Anything).Return([]segments.Segment{
+		mockSegment(1, 10), mockSegment(2, 10), mockSegment(3, 11),
+	})
+
+	estimate, err := suite.node.EstimateSearchThreads(ctx, &SearchShape{CollectionID: suite.collectionID})
+	suite.Require().NoError(err)
+	suite.Equal(3, estimate.Segments)
+	suite.Equal(segments.GetSQPool().Cap(), estimate.PoolCap)
+	suite.EqualValues(lo.Min([]int{3, estimate.PoolCap}), estimate.PeakThreads)
+	suite.GreaterOrEqual(estimate.ConcurrentSegments, 1)
+	suite.LessOrEqual(estimate.Threads, estimate.PeakThreads)
+
+	estimate, err = suite.node.EstimateSearchThreads(ctx, &SearchShape{
+		CollectionID: suite.collectionID,
+		PartitionIDs: []int64{10},
+		SegmentIDs:   []int64{2, 3},
+	})
+	suite.Require().NoError(err)
+	suite.Equal(1, estimate.Segments)
+	suite.EqualValues(1, estimate.PeakThreads)
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err = suite.node.EstimateSearchThreads(ctx, &SearchShape{CollectionID: suite.collectionID})
+	suite.ErrorIs(err, merr.ErrServiceNotReady)
+}
+
+func (suite *HandlersSuite) TestEstimateSearchThreadsByWorkers() {
+	// one thread per segment, capped by workers
+	concurrent, threads := estimateSearchThreads(3, 2)
+	suite.Equal(2, concurrent)
+	suite.EqualValues(2, threads)
+
+	concurrent, threads = estimateSearchThreads(3, 4)
+	suite.Equal(3, concurrent)
+	suite.EqualValues(3, threads)
+
+	concurrent, threads = estimateSearchThreads(0, 4)
+	suite.Equal(0, concurrent)
+	suite.EqualValues(0, threads)
+}
+
+func (suite *HandlersSuite) TestGetSegmentIndexProgress() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+	loader := segments.NewMockLoader(suite.T())
+	suite.node.loader = loader
+
+	// collection not loaded
+	_, err := suite.node.GetSegmentIndexProgress(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	collectionManager.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	indexed := segments.NewMockSegment(suite.T())
+	indexed.EXPECT().ID().Return(1)
+	indexed.EXPECT().ExistIndex(mock.Anything).Return(true)
+	bruteForce := segments.NewMockSegment(suite.T())
+	bruteForce.EXPECT().ID().Return(2)
+	bruteForce.EXPECT().ExistIndex(mock.Anything).Return(false)
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{indexed, bruteForce})
+
+	start := time.Now()
+	loader.EXPECT().GetIndexLoadProgress(int64(1)).Return(segments.IndexLoadProgress{})
+	loader.EXPECT().GetIndexLoadProgress(int64(2)).Return(segments.IndexLoadProgress{
+		Loading:             true,
+		StartTime:           start,
+		EstimatedCompletion: start.Add(time.Minute),
+	})
+
+	progresses, err := suite.node.GetSegmentIndexProgress(ctx, suite.collectionID)
+	suite.Require().NoError(err)
+	suite.Require().Len(progresses, 2)
+	suite.False(progresses[0].BruteForce)
+	suite.False(progresses[0].IndexLoading)
+	suite.True(progresses[1].BruteForce)
+	suite.True(progresses[1].IndexLoading)
+	suite.Equal(start, progresses[1].LoadingSince)
+	suite.Equal(start.Add(time.Minute), progresses[1].EstimatedCompletion)
+}
+
+func (suite *HandlersSuite) TestCancelLoad() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: segments.NewCollectionManager(),
+		Segment:    segmentManager,
+	}
+
+	loadCtx, load := suite.node.loads.register(ctx, suite.collectionID, LoadKindSealed, []int64{1, 2})
+	// loads of other collections are not affected
+	otherCtx, other := suite.node.loads.register(ctx, suite.collectionID+1, LoadKindIndex, []int64{3})
+	defer suite.node.loads.finish(other, nil)
+
+	// segment 2 is loaded by the load before canceled, segment 1 is loaded by others
+	segmentManager.EXPECT().GetWithType(int64(2), segments.SegmentTypeSealed).Return(segments.NewMockSegment(suite.T())).Once()
+	segmentManager.EXPECT().Remove(int64(2), querypb.DataScope_Historical).Return(1, 0).Once()
+	go func() {
+		<-loadCtx.Done()
+		suite.node.loads.finish(load, suite.node.rollbackCanceledLoad(load, segments.SegmentTypeSealed, []int64{2}, loadCtx.Err()))
+	}()
+
+	canceled, err := suite.node.CancelLoad(ctx, suite.collectionID)
+	suite.Require().NoError(err)
+	suite.Require().Len(canceled, 1)
+	suite.Equal(load.id, canceled[0].LoadID)
+	suite.Equal(LoadKindSealed, canceled[0].Kind)
+	suite.Equal([]int64{2}, canceled[0].RolledBack)
+	suite.NoError(otherCtx.Err())
+
+	// nothing in flight
+	canceled, err = suite.node.CancelLoad(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.Empty(canceled)
+}
+
+func (suite *HandlersSuite) TestRerank() {
+	ctx := context.Background()
+	req := &internalpb.SearchRequest{CollectionID: suite.collectionID}
+	genData := func(ids []int64, scores []float32) *schemapb.SearchResultData {
+		return &schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       3,
+			Ids: &schemapb.IDs{
+				IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}},
+			},
+			Scores: scores,
+			Topks:  []int64{int64(len(ids))},
+		}
+	}
+	result, err := segments.EncodeSearchResultData(genData([]int64{1, 2, 3}, []float32{0.3, 0.2, 0.1}), 1, 3, "IP")
+	suite.Require().NoError(err)
+
+	// reranker not configured
+	suite.Equal(result, suite.node.rerank(ctx, req, result))
+
+	reranker := optimizers.NewMockReranker(suite.T())
+	suite.node.reranker = reranker
+	defer func() { suite.node.reranker = nil }()
+
+	reranker.EXPECT().Rerank(mock.Anything, req, mock.Anything).Return(genData([]int64{3, 1}, []float32{0.9, 0.8}), nil).Once()
+	reranked := suite.node.rerank(ctx, req, result)
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{reranked})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{3, 1}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{0.9, 0.8}, data[0].GetScores())
+
+	// fall back when reranker fails
+	reranker.EXPECT().Rerank(mock.Anything, req, mock.Anything).Return(nil, errors.New("mock error")).Once()
+	suite.Equal(result, suite.node.rerank(ctx, req, result))
+
+	// fall back when reranked result is malformed
+	malformed := genData([]int64{3, 1}, []float32{0.9})
+	reranker.EXPECT().Rerank(mock.Anything, req, mock.Anything).Return(malformed, nil).Once()
+	suite.Equal(result, suite.node.rerank(ctx, req, result))
+}
+
+func (suite *HandlersSuite) TestGetLoadedSchema() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.manager = segments.NewManager()
+
+	_, err := suite.node.GetLoadedSchema(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.node.manager.Collection.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	loaded, err := suite.node.GetLoadedSchema(ctx, suite.collectionID)
+	suite.Require().NoError(err)
+	suite.EqualValues(suite.collectionID, loaded.CollectionID)
+	suite.True(proto.Equal(schema, loaded.Schema))
+	suite.Equal(segments.SchemaVersion(schema), loaded.Version)
+
+	// schema with different field ids has different version
+	changed := proto.Clone(schema).(*schemapb.CollectionSchema)
+	changed.Fields[0].FieldID = 999
+	suite.NotEqual(loaded.Version, segments.SchemaVersion(changed))
+}
+
+func (suite *HandlersSuite) TestDumpVectors() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.manager = segments.NewManager()
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	pks := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}}}
+
+	// disabled by default
+	_, err := suite.node.DumpVectors(ctx, suite.collectionID, 0, pks)
+	suite.ErrorIs(err, merr.ErrPrivilegeNotPermitted)
+
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.EnableVectorDump.Key, "true")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.EnableVectorDump.Key)
+
+	_, err = suite.node.DumpVectors(ctx, suite.collectionID, 0, pks)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.node.manager.Collection.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	vectorField, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+		return typeutil.IsVectorType(field.GetDataType())
+	})
+	suite.Require().True(ok)
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	suite.Require().NoError(err)
+
+	// not vector field
+	_, err = suite.node.DumpVectors(ctx, suite.collectionID, pkField.GetFieldID(), pks)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// no pk provided
+	_, err = suite.node.DumpVectors(ctx, suite.collectionID, vectorField.GetFieldID(), &schemapb.IDs{})
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// no delegator of collection
+	_, err = suite.node.DumpVectors(ctx, suite.collectionID, vectorField.GetFieldID(), pks)
+	suite.ErrorIs(err, merr.ErrChannelNotFound)
+
+	// split raw vectors
+	vectors, err := rawVectors(&schemapb.FieldData{
+		Type: schemapb.DataType_FloatVector,
+		Field: &schemapb.FieldData_Vectors{
+			Vectors: &schemapb.VectorField{
+				Dim:  2,
+				Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: []float32{1, 2, 3, 4}}},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(vectors, 2)
+	suite.Len(vectors[0], 8)
+	suite.Equal(math.Float32bits(3), binary.LittleEndian.Uint32(vectors[1][:4]))
+
+	_, err = rawVectors(&schemapb.FieldData{
+		Type: schemapb.DataType_BinaryVector,
+		Field: &schemapb.FieldData_Vectors{
+			Vectors: &schemapb.VectorField{
+				Dim:  16,
+				Data: &schemapb.VectorField_BinaryVector{BinaryVector: []byte{1, 2, 3}},
+			},
+		},
+	})
+	suite.Error(err)
+}
+
+func (suite *HandlersSuite) TestGetTargetVersionSkew() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetTargetVersionSkew(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().GetTargetVersion().Return(2)
+	sd.EXPECT().GetSegmentInfo(false).Return([]delegator.SnapshotItem{
+		{
+			NodeID: 1,
+			Segments: []delegator.SegmentEntry{
+				{NodeID: 1, SegmentID: 100, TargetVersion: 2},
+				{NodeID: 1, SegmentID: 101, TargetVersion: 1},
+			},
+		},
+	}, []delegator.SegmentEntry{
+		{NodeID: 1, SegmentID: 102, TargetVersion: 2},
+		{NodeID: 1, SegmentID: 103, TargetVersion: -2},
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	skews, err := suite.node.GetTargetVersionSkew(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(skews, 1)
+	suite.Equal(suite.channel, skews[0].Channel)
+	suite.EqualValues(2, skews[0].TargetVersion)
+	suite.False(skews[0].Consistent)
+	suite.ElementsMatch([]LaggingSegment{
+		{SegmentID: 101, NodeID: 1, TargetVersion: 1},
+		{SegmentID: 103, NodeID: 1, Growing: true, TargetVersion: -2},
+	}, skews[0].Lagging)
+}
+
+func (suite *HandlersSuite) TestGetSegmentAssignments() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetSegmentAssignments(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().GetSegmentInfo(false).Return([]delegator.SnapshotItem{
+		{
+			NodeID: 2,
+			Segments: []delegator.SegmentEntry{
+				{NodeID: 2, SegmentID: 102, PartitionID: 10, Version: 1},
+				{NodeID: 2, SegmentID: 100, PartitionID: 10, Version: 1},
+				{NodeID: 2, SegmentID: 103, PartitionID: 11, Version: 2},
+			},
+		},
+		{
+			NodeID: 3,
+			Segments: []delegator.SegmentEntry{
+				{NodeID: 3, SegmentID: 101, PartitionID: 10, Version: 1},
+			},
+		},
+	}, []delegator.SegmentEntry{
+		{NodeID: 1, SegmentID: 104},
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	assignments, err := suite.node.GetSegmentAssignments(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(assignments, 1)
+	suite.Equal(suite.channel, assignments[0].Channel)
+	suite.Equal(suite.collectionID, assignments[0].CollectionID)
+	suite.Equal(paramtable.GetNodeID(), assignments[0].LeaderNodeID)
+	// growing segments are not assigned to workers
+	suite.Equal([]SegmentAssignment{
+		{SegmentID: 100, PartitionID: 10, NodeID: 2, Version: 1},
+		{SegmentID: 101, PartitionID: 10, NodeID: 3, Version: 1},
+		{SegmentID: 102, PartitionID: 10, NodeID: 2, Version: 1},
+		{SegmentID: 103, PartitionID: 11, NodeID: 2, Version: 2},
+	}, assignments[0].Segments)
+	suite.Equal(map[int64]int{2: 3, 3: 1}, assignments[0].SegmentNumPerNode)
+	suite.Equal(0.75, assignments[0].MaxNodeShare)
+}
+
+func (suite *HandlersSuite) TestGetTargetVersions() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetTargetVersions(ctx, suite.collectionID, 0, 0)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	// no delegator of collection
+	_, err = suite.node.GetTargetVersions(ctx, suite.collectionID, 0, 0)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	targetVersion := atomic.NewInt64(1)
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().GetTargetVersion().RunAndReturn(targetVersion.Load)
+	suite.node.delegators.Insert(suite.channel, sd)
+	other := delegator.NewMockShardDelegator(suite.T())
+	other.EXPECT().Collection().Return(suite.collectionID + 1)
+	suite.node.delegators.Insert("other-channel", other)
+
+	versions, err := suite.node.GetTargetVersions(ctx, suite.collectionID, 2, 0)
+	suite.Require().NoError(err)
+	suite.Equal([]*ChannelTargetVersion{{
+		Channel:         suite.channel,
+		CollectionID:    suite.collectionID,
+		TargetVersion:   1,
+		RequiredVersion: 2,
+	}}, versions)
+
+	// not reached before timeout
+	versions, err = suite.node.GetTargetVersions(ctx, suite.collectionID, 2, 200*time.Millisecond)
+	suite.Require().NoError(err)
+	suite.Require().Len(versions, 1)
+	suite.False(versions[0].Reached)
+
+	// context done
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = suite.node.GetTargetVersions(timeoutCtx, suite.collectionID, 2, time.Minute)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+
+	// reached while waiting
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		targetVersion.Store(3)
+	}()
+	versions, err = suite.node.GetTargetVersions(ctx, suite.collectionID, 2, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(versions, 1)
+	suite.True(versions[0].Reached)
+	suite.EqualValues(3, versions[0].TargetVersion)
+}
+
+func (suite *HandlersSuite) TestGetSegmentLoadErrors() {
+	ctx := context.Background()
+	loader := segments.NewMockLoader(suite.T())
+	suite.node.loader = loader
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetSegmentLoadErrors(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	loadErrors := []segments.SegmentLoadError{
+		{SegmentID: 1, Phase: segments.LoadPhaseIndex, Error: "mock error", Timestamp: time.Now()},
+	}
+	loader.EXPECT().GetLoadErrors().Return(loadErrors)
+	result, err := suite.node.GetSegmentLoadErrors(ctx)
+	suite.NoError(err)
+	suite.Equal(loadErrors, result)
+}
+
+func (suite *HandlersSuite) TestEstimateLoadFeasibility() {
+	ctx := context.Background()
+	loader := segments.NewMockLoader(suite.T())
+	suite.node.loader = loader
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.EstimateLoadFeasibility(ctx, &querypb.LoadSegmentsRequest{})
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	req := &querypb.LoadSegmentsRequest{
+		Base: &commonpb.MsgBase{
+			TargetID: paramtable.GetNodeID(),
+		},
+		CollectionID: suite.collectionID,
+		Infos:        []*querypb.SegmentLoadInfo{{SegmentID: 1, CollectionID: suite.collectionID}},
+	}
+
+	// target not match
+	req.Base.TargetID = -1
+	_, err = suite.node.EstimateLoadFeasibility(ctx, req)
+	suite.ErrorIs(err, merr.ErrNodeNotMatch)
+	req.Base.TargetID = paramtable.GetNodeID()
+
+	expected := &segments.LoadFeasibility{
+		Feasible:             false,
+		Reason:               "memory not enough",
+		ProjectedMemoryUsage: 1024,
+		MemoryBudget:         512,
+	}
+	loader.EXPECT().EstimateLoad(mock.Anything, req.GetInfos()[0]).Return(expected, nil).Once()
+	feasibility, err := suite.node.EstimateLoadFeasibility(ctx, req)
+	suite.NoError(err)
+	suite.Equal(expected, feasibility)
+
+	loader.EXPECT().EstimateLoad(mock.Anything, req.GetInfos()[0]).Return(nil, merr.ErrServiceInternal).Once()
+	_, err = suite.node.EstimateLoadFeasibility(ctx, req)
+	suite.ErrorIs(err, merr.ErrServiceInternal)
+}
+
+func (suite *HandlersSuite) TestSearchParamDefaults() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	err := suite.node.SetSearchParamDefaults(ctx, suite.collectionID, map[string]any{"ef": 64})
+	suite.Error(err)
+	_, err = suite.node.GetSearchParamDefaults(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	err = suite.node.SetSearchParamDefaults(ctx, suite.collectionID, map[string]any{"ef": 64, "nprobe": 16})
+	suite.Require().NoError(err)
+	defaults, err := suite.node.GetSearchParamDefaults(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(defaults, 1)
+	suite.Equal(suite.collectionID, defaults[0].CollectionID)
+	suite.Equal(map[string]any{"ef": 64, "nprobe": 16}, defaults[0].Params)
+	suite.False(defaults[0].UpdatedAt.IsZero())
+
+	genReq := func(collectionID int64) *querypb.SearchRequest {
+		plan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{
+				VectorAnns: &planpb.VectorANNS{
+					QueryInfo: &planpb.QueryInfo{
+						Topk:         100,
+						SearchParams: `{"ef": 128}`,
+					},
+				},
+			},
+		}
+		bs, err := proto.Marshal(plan)
+		suite.Require().NoError(err)
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				CollectionID:       collectionID,
+				SerializedExprPlan: bs,
+			},
+		}
+	}
+	searchParams := func(req *querypb.SearchRequest) map[string]any {
+		var plan planpb.PlanNode
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan))
+		params := make(map[string]any)
+		suite.Require().NoError(json.Unmarshal([]byte(plan.GetVectorAnns().GetQueryInfo().GetSearchParams()), &params))
+		return params
+	}
+
+	// params set by request are kept, origin request intact
+	origin := genReq(suite.collectionID)
+	req, err := suite.node.searchParamDefaults.apply(origin)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]any{"ef": float64(128), "nprobe": float64(16)}, searchParams(req))
+	suite.Equal(map[string]any{"ef": float64(128)}, searchParams(origin))
+
+	// other collections not affected
+	req, err = suite.node.searchParamDefaults.apply(genReq(suite.collectionID + 1))
+	suite.Require().NoError(err)
+	suite.Equal(map[string]any{"ef": float64(128)}, searchParams(req))
+
+	// defaults removed
+	err = suite.node.SetSearchParamDefaults(ctx, suite.collectionID, nil)
+	suite.Require().NoError(err)
+	defaults, err = suite.node.GetSearchParamDefaults(ctx)
+	suite.Require().NoError(err)
+	suite.Empty(defaults)
+}
+
+func (suite *HandlersSuite) TestQueryChannelWithReplica() {
+	ctx := context.Background()
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Replica().Return(1)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			Base:         &commonpb.MsgBase{},
+			CollectionID: suite.collectionID,
+			ReplicaID:    2,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	_, err := suite.node.queryChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrReplicaNotAvailable)
+
+	suite.NoError(checkReplica(sd, suite.channel, 0))
+	suite.NoError(checkReplica(sd, suite.channel, 1))
+	suite.ErrorIs(checkReplica(sd, suite.channel, 2), merr.ErrReplicaNotAvailable)
+}
+
+func (suite *HandlersSuite) TestSearchIteratorAcrossReplicas() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	const topk = 2
+	// hits ordered by score descending and pk ascending, with tied scores across pages
+	pks := []int64{1, 2, 3, 4, 5, 6}
+	scores := []float32{0.9, 0.8, 0.8, 0.8, 0.7, 0.6}
+	// every replica searches the same data, and emulates the range search of segcore
+	newReplica := func() *delegator.MockShardDelegator {
+		sd := delegator.NewMockShardDelegator(suite.T())
+		sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{}).Maybe()
+		sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+			var plan planpb.PlanNode
+			suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan))
+			queryInfo := plan.GetVectorAnns().GetQueryInfo()
+			suite.Equal(queryInfo.GetTopk(), req.GetReq().GetTopk())
+			params := make(map[string]any)
+			suite.Require().NoError(json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params))
+			rangeFilter, hasRange := params[rangeFilterKey].(float64)
+
+			data := &schemapb.SearchResultData{
+				NumQueries: 1,
+				TopK:       queryInfo.GetTopk(),
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}},
+				Topks:      []int64{0},
+			}
+			for i := range pks {
+				if int64(len(data.Scores)) == queryInfo.GetTopk() {
+					break
+				}
+				if hasRange && scores[i] > float32(rangeFilter) {
+					continue
+				}
+				data.Ids.GetIntId().Data = append(data.Ids.GetIntId().Data, pks[i])
+				data.Scores = append(data.Scores, scores[i])
+			}
+			data.Topks[0] = int64(len(data.Scores))
+			result, err := segments.EncodeSearchResultData(data, 1, queryInfo.GetTopk(), "IP")
+			return []*internalpb.SearchResults{result}, err
+		}).Maybe()
+		return sd
+	}
+	replicas := []delegator.ShardDelegator{newReplica(), newReplica()}
+
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				QueryInfo: &planpb.QueryInfo{
+					Topk:         topk,
+					MetricType:   "IP",
+					SearchParams: `{"ef": 16}`,
+				},
+			},
+		},
+	}
+	serializedPlan, err := proto.Marshal(plan)
+	suite.Require().NoError(err)
+	placeholderGroup, err := genPlaceHolderGroup(1)
+	suite.Require().NoError(err)
+	genReq := func(token []byte) *querypb.SearchRequest {
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				CollectionID:       suite.collectionID,
+				MetricType:         "IP",
+				Nq:                 1,
+				Topk:               topk,
+				PlaceholderGroup:   placeholderGroup,
+				SerializedExprPlan: serializedPlan,
+				IsIterator:         true,
+				IteratorToken:      token,
+			},
+			DmlChannels: []string{suite.channel},
+		}
+	}
+
+	// page N is served by one replica and page N+1 by the other one
+	var token []byte
+	iterated := make([]int64, 0, len(pks))
+	for page := 0; page <= len(pks); page++ {
+		suite.node.delegators.Insert(suite.channel, replicas[page%len(replicas)])
+		result, err := suite.node.searchChannel(ctx, genReq(token), suite.channel)
+		suite.Require().NoError(err)
+		token = result.GetNextIteratorToken()
+		if token == nil {
+			suite.Nil(result.GetSlicedBlob())
+			break
+		}
+		data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+		suite.Require().NoError(err)
+		suite.LessOrEqual(len(data[0].GetScores()), topk)
+		iterated = append(iterated, data[0].GetIds().GetIntId().GetData()...)
+	}
+	suite.Nil(token)
+	suite.Equal(pks, iterated)
+
+	// token of another metric
+	token, err = json.Marshal(&typeutil2.IteratorToken{MetricType: "L2", LastScore: -1})
+	suite.Require().NoError(err)
+	_, err = suite.node.searchChannel(ctx, genReq(token), suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// malformed token
+	_, err = suite.node.searchChannel(ctx, genReq([]byte("invalid")), suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestApplyIteratorTokenRadius() {
+	genReq := func(searchParams string) *querypb.SearchRequest {
+		plan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{
+				VectorAnns: &planpb.VectorANNS{
+					QueryInfo: &planpb.QueryInfo{Topk: 2, MetricType: "L2", SearchParams: searchParams},
+				},
+			},
+		}
+		serializedPlan, err := proto.Marshal(plan)
+		suite.Require().NoError(err)
+		token, err := json.Marshal(&typeutil2.IteratorToken{MetricType: "L2", LastScore: -1, TiedCount: 1})
+		suite.Require().NoError(err)
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				MetricType:         "L2",
+				Nq:                 1,
+				Topk:               2,
+				SerializedExprPlan: serializedPlan,
+				IteratorToken:      token,
+			},
+		}
+	}
+	paramsOf := func(req *querypb.SearchRequest) map[string]any {
+		var plan planpb.PlanNode
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan))
+		suite.EqualValues(3, plan.GetVectorAnns().GetQueryInfo().GetTopk())
+		params := make(map[string]any)
+		suite.Require().NoError(json.Unmarshal([]byte(plan.GetVectorAnns().GetQueryInfo().GetSearchParams()), &params))
+		return params
+	}
+
+	// open range without radius
+	req, token, err := applyIteratorToken(genReq(`{"ef": 16}`))
+	suite.Require().NoError(err)
+	suite.NotNil(token)
+	params := paramsOf(req)
+	suite.EqualValues(math.MaxFloat32, params[radiusKey])
+	suite.EqualValues(1, params[rangeFilterKey])
+
+	// radius of request bounds the range
+	req, _, err = applyIteratorToken(genReq(`{"ef": 16, "radius": 10}`))
+	suite.Require().NoError(err)
+	params = paramsOf(req)
+	suite.EqualValues(10, params[radiusKey])
+	suite.EqualValues(1, params[rangeFilterKey])
+}
+
+func (suite *HandlersSuite) TestGetSegmentRowCounts() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetSegmentRowCounts(ctx, suite.collectionID)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+
+	// collection not loaded
+	_, err = suite.node.GetSegmentRowCounts(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	collectionManager.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	genSegment := func(id int64, typ segments.SegmentType, raw, live int64) segments.Segment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().ID().Return(id)
+		segment.EXPECT().Partition().Return(10)
+		segment.EXPECT().Shard().Return(suite.channel)
+		segment.EXPECT().Type().Return(typ)
+		segment.EXPECT().InsertCount().Return(raw)
+		segment.EXPECT().RowNum().Return(live)
+		return segment
+	}
+	segmentManager.EXPECT().GetBy(mock.Anything).Return([]segments.Segment{
+		genSegment(1, segments.SegmentTypeSealed, 100, 40),
+		genSegment(2, segments.SegmentTypeGrowing, 10, 10),
+		genSegment(3, segments.SegmentTypeSealed, 0, 0),
+	})
+
+	counts, err := suite.node.GetSegmentRowCounts(ctx, suite.collectionID)
+	suite.Require().NoError(err)
+	suite.Equal([]SegmentRowCount{
+		{SegmentID: 1, PartitionID: 10, Channel: suite.channel, Type: segments.SegmentTypeSealed, RawRowCount: 100, LiveRowCount: 40, DeletedRows: 60, DeleteRatio: 0.6},
+		{SegmentID: 2, PartitionID: 10, Channel: suite.channel, Type: segments.SegmentTypeGrowing, RawRowCount: 10, LiveRowCount: 10},
+		{SegmentID: 3, PartitionID: 10, Channel: suite.channel, Type: segments.SegmentTypeSealed},
+	}, counts)
+}
+
+func (suite *HandlersSuite) TestSearchChannelWithExclusion() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	const topk = 2
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				QueryInfo: &planpb.QueryInfo{Topk: topk},
+			},
+		},
+	}
+	serializedPlan, err := proto.Marshal(plan)
+	suite.Require().NoError(err)
+	placeholderGroup, err := genPlaceHolderGroup(1)
+	suite.Require().NoError(err)
+	excluded := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 3}}}}
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		// topK is widened by the excluded pks
+		var plan planpb.PlanNode
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan))
+		suite.EqualValues(topk+2, plan.GetVectorAnns().GetQueryInfo().GetTopk())
+		suite.EqualValues(topk+2, req.GetReq().GetTopk())
+		result, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       topk + 2,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3, 4}}}},
+			Scores:     []float32{0.9, 0.8, 0.7, 0.6},
+			Topks:      []int64{4},
+		}, 1, topk+2, "IP")
+		return []*internalpb.SearchResults{result}, err
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			CollectionID:       suite.collectionID,
+			MetricType:         "IP",
+			Nq:                 1,
+			Topk:               topk,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: serializedPlan,
+			ExcludePks:         excluded,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.EqualValues(topk, result.GetTopK())
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{2, 4}, data[0].GetIds().GetIntId().GetData())
+	// request shared among channels is not modified
+	suite.EqualValues(topk, req.GetReq().GetTopk())
+
+	// too many excluded pks
+	suite.params.Save(suite.params.QueryNodeCfg.MaxSearchExcludedPKs.Key, "1")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.MaxSearchExcludedPKs.Key)
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestGetRuntimeStats() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetRuntimeStats(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.scheduler = tasks.NewScheduler("")
+	suite.node.inflightSearches.Inc()
+	defer suite.node.inflightSearches.Dec()
+	runtime.GC()
+
+	stats, err := suite.node.GetRuntimeStats(ctx)
+	suite.Require().NoError(err)
+	suite.EqualValues(0, stats.WaitingTasks)
+	suite.EqualValues(0, stats.WaitingNQ)
+	suite.EqualValues(1, stats.InflightSearches)
+	suite.EqualValues(0, stats.InflightQueries)
+	suite.Positive(stats.Goroutines)
+	suite.Positive(stats.NumGC)
+	suite.False(stats.LastGC.IsZero())
+}
+
+func (suite *HandlersSuite) TestSearchChannelSkipHook() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				QueryInfo: &planpb.QueryInfo{
+					Topk:         10,
+					SearchParams: `{"ef": 16}`,
+				},
+			},
+		},
+	}
+	serializedPlan, err := proto.Marshal(plan)
+	suite.Require().NoError(err)
+	placeholderGroup, err := genPlaceHolderGroup(1)
+	suite.Require().NoError(err)
+
+	var searchParams string
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(mock.Anything).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		var plan planpb.PlanNode
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan))
+		searchParams = plan.GetVectorAnns().GetQueryInfo().GetSearchParams()
+		return []*internalpb.SearchResults{}, nil
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	mockHook := optimizers.NewMockQueryHook(suite.T())
+	mockHook.EXPECT().Run(mock.Anything).Run(func(params map[string]any) {
+		params[common.SearchParamKey] = `{"ef": 64}`
+	}).Return(nil).Once()
+	suite.node.queryHook = mockHook
+	defer func() { suite.node.queryHook = nil }()
+
+	genReq := func(skipHook bool) *querypb.SearchRequest {
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				CollectionID:       suite.collectionID,
+				MetricType:         "L2",
+				Nq:                 1,
+				Topk:               10,
+				PlaceholderGroup:   placeholderGroup,
+				SerializedExprPlan: serializedPlan,
+				SkipHook:           skipHook,
+			},
+			DmlChannels:     []string{suite.channel},
+			TotalChannelNum: 1,
+		}
+	}
+
+	// hook applied
+	_, err = suite.node.searchChannel(ctx, genReq(false), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(`{"ef": 64}`, searchParams)
+
+	// raw plan searched, hook not called again
+	_, err = suite.node.searchChannel(ctx, genReq(true), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(`{"ef": 16}`, searchParams)
+}
+
+func (suite *HandlersSuite) TestWarmupAndVerify() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.WarmupAndVerify(ctx, suite.collectionID, []int64{1})
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+
+	// collection not loaded
+	_, err = suite.node.WarmupAndVerify(ctx, suite.collectionID, []int64{1})
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	collectionManager.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	noIndex := segments.NewMockSegment(suite.T())
+	noIndex.EXPECT().Collection().Return(suite.collectionID)
+	noIndex.EXPECT().GetIndex(mock.Anything).Return(nil)
+	segmentManager.EXPECT().GetSealed(int64(1)).Return(nil)
+	segmentManager.EXPECT().GetSealed(int64(2)).Return(noIndex)
+
+	verifications, err := suite.node.WarmupAndVerify(ctx, suite.collectionID, []int64{1, 2})
+	suite.Require().NoError(err)
+	suite.Require().Len(verifications, 2)
+	suite.EqualValues(1, verifications[0].SegmentID)
+	suite.False(verifications[0].Passed)
+	suite.Contains(verifications[0].Reason, "not loaded")
+	suite.EqualValues(2, verifications[1].SegmentID)
+	suite.False(verifications[1].Passed)
+	suite.Contains(verifications[1].Reason, "index")
+}
+
+func (suite *HandlersSuite) TestCheckCanaryHit() {
+	genData := func(pks []int64, scores []float32) *schemapb.SearchResultData {
+		return &schemapb.SearchResultData{
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			Scores: scores,
+		}
+	}
+
+	// L2 distances are negated, the canary finds itself with distance 0
+	score, reason := checkCanaryHit(genData([]int64{3, 1}, []float32{0, -0.5}), int64(3))
+	suite.Empty(reason)
+	suite.EqualValues(0, score)
+
+	// tied with a duplicated vector
+	_, reason = checkCanaryHit(genData([]int64{1, 3}, []float32{0, 0}), int64(3))
+	suite.Empty(reason)
+
+	// found but worse than the best hit
+	_, reason = checkCanaryHit(genData([]int64{1, 3}, []float32{0, -0.5}), int64(3))
+	suite.NotEmpty(reason)
+
+	// not found
+	_, reason = checkCanaryHit(genData([]int64{1, 2}, []float32{-0.1, -0.5}), int64(3))
+	suite.NotEmpty(reason)
+
+	_, reason = checkCanaryHit(genData(nil, nil), int64(3))
+	suite.NotEmpty(reason)
+}
+
+func (suite *HandlersSuite) TestGetServedCollections() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetServedCollections(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	for _, collectionID := range []int64{suite.collectionID + 1, suite.collectionID} {
+		collectionManager.PutOrRef(collectionID, schema, nil, &querypb.LoadMetaInfo{
+			LoadType: querypb.LoadType_LoadCollection,
+		})
+	}
+
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	for _, channel := range []string{suite.channel + "-1", suite.channel + "-0"} {
+		sd := delegator.NewMockShardDelegator(suite.T())
+		sd.EXPECT().Collection().Return(suite.collectionID)
+		suite.node.delegators.Insert(channel, sd)
+	}
+
+	genSegment := func(typ segments.SegmentType, size int64) segments.Segment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().Type().Return(typ)
+		segment.EXPECT().MemSize().Return(size)
+		return segment
+	}
+	segmentManager.EXPECT().GetBy(mock.Anything).Return([]segments.Segment{
+		genSegment(segments.SegmentTypeSealed, 100),
+		genSegment(segments.SegmentTypeSealed, 200),
+		genSegment(segments.SegmentTypeGrowing, 10),
+	}).Once()
+	segmentManager.EXPECT().GetBy(mock.Anything).Return(nil).Once()
+
+	served, err := suite.node.GetServedCollections(ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]*ServedCollection{
+		{
+			CollectionID:      suite.collectionID,
+			Channels:          []string{suite.channel + "-0", suite.channel + "-1"},
+			SealedSegmentNum:  2,
+			GrowingSegmentNum: 1,
+			SealedMemorySize:  300,
+			GrowingMemorySize: 10,
+		},
+		{
+			CollectionID: suite.collectionID + 1,
+			Channels:     []string{},
+		},
+	}, served)
+}
+
+func (suite *HandlersSuite) TestSetDeleteApplyMode() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	err := suite.node.SetDeleteApplyMode(ctx, suite.collectionID, segments.DeleteApplyModeLazy)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+
+	// invalid mode
+	err = suite.node.SetDeleteApplyMode(ctx, suite.collectionID, "unknown")
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// collection not loaded
+	err = suite.node.SetDeleteApplyMode(ctx, suite.collectionID, segments.DeleteApplyModeLazy)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	collection := collectionManager.Get(suite.collectionID)
+	suite.Equal(segments.DeleteApplyModeEager, collection.GetDeleteApplyMode())
+
+	err = suite.node.SetDeleteApplyMode(ctx, suite.collectionID, segments.DeleteApplyModeLazy)
+	suite.NoError(err)
+	suite.Equal(segments.DeleteApplyModeLazy, collection.GetDeleteApplyMode())
+
+	// back to node default, deferred deletes applied
+	segment := segments.NewMockSegment(suite.T())
+	segmentManager.EXPECT().GetBy(mock.Anything).Return([]segments.Segment{segment})
+	err = suite.node.SetDeleteApplyMode(ctx, suite.collectionID, "")
+	suite.NoError(err)
+	suite.Equal(segments.DeleteApplyModeEager, collection.GetDeleteApplyMode())
+}
+
+func (suite *HandlersSuite) TestGetSegmentVectorNorms() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetSegmentVectorNorms(ctx, suite.collectionID, 107, 0)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+
+	// collection not loaded
+	_, err = suite.node.GetSegmentVectorNorms(ctx, suite.collectionID, 107, 0)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	// field not found
+	_, err = suite.node.GetSegmentVectorNorms(ctx, suite.collectionID, 999, 0)
+	suite.ErrorIs(err, merr.ErrFieldNotFound)
+
+	// binary vector not supported
+	_, err = suite.node.GetSegmentVectorNorms(ctx, suite.collectionID, 108, 0)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// no delegator
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	_, err = suite.node.GetSegmentVectorNorms(ctx, suite.collectionID, 107, 0)
+	suite.ErrorIs(err, merr.ErrChannelNotFound)
+
+	// segments served by other nodes are not sampled
+	remoteNodeID := paramtable.GetNodeID() + 1
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().GetSegmentInfo(true).Return(
+		[]delegator.SnapshotItem{{NodeID: remoteNodeID, Segments: []delegator.SegmentEntry{{NodeID: remoteNodeID, SegmentID: 1}}}},
+		[]delegator.SegmentEntry{{NodeID: remoteNodeID, SegmentID: 2}},
+	)
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	norms, err := suite.node.GetSegmentVectorNorms(ctx, suite.collectionID, 107, 0)
+	suite.NoError(err)
+	suite.Len(norms, 2)
+	suite.EqualValues(1, norms[0].SegmentID)
+	suite.False(norms[0].Growing)
+	suite.EqualValues(2, norms[1].SegmentID)
+	suite.True(norms[1].Growing)
+	for _, norm := range norms {
+		suite.Equal(remoteNodeID, norm.NodeID)
+		suite.Equal(suite.channel, norm.Channel)
+		suite.Zero(norm.SampledRows)
+		suite.NotEmpty(norm.Reason)
+	}
+}
+
+func (suite *HandlersSuite) TestVectorNorm() {
+	floatVector := func(values ...float32) []byte {
+		data := make([]byte, len(values)*4)
+		for i, v := range values {
+			binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+		}
+		return data
+	}
+	suite.InDelta(5.0, vectorNorm(floatVector(3, 4), schemapb.DataType_FloatVector), 1e-6)
+	suite.InDelta(1.0, vectorNorm(floatVector(0.6, 0.8), schemapb.DataType_FloatVector), 1e-6)
+
+	// float16 of 1.0, -2.0, 0.5 and the smallest subnormal
+	suite.Equal(float32(1), float16ToFloat32(0x3c00))
+	suite.Equal(float32(-2), float16ToFloat32(0xc000))
+	suite.Equal(float32(0.5), float16ToFloat32(0x3800))
+	suite.Equal(float32(1)/(1<<24), float16ToFloat32(0x0001))
+	suite.True(math.IsInf(float64(float16ToFloat32(0x7c00)), 1))
+	float16Vector := []byte{0x00, 0x3c, 0x00, 0xc0}
+	suite.InDelta(math.Sqrt(5), vectorNorm(float16Vector, schemapb.DataType_Float16Vector), 1e-6)
+
+	mean, variance := meanAndVariance([]float64{1, 1, 1})
+	suite.Equal(1.0, mean)
+	suite.Zero(variance)
+	mean, variance = meanAndVariance([]float64{1, 3})
+	suite.Equal(2.0, mean)
+	suite.Equal(1.0, variance)
+	mean, variance = meanAndVariance(nil)
+	suite.Zero(mean)
+	suite.Zero(variance)
+}
+
+func (suite *HandlersSuite) TestApplyLenientOutputFields() {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector},
+			{FieldID: 102, Name: "$meta", IsDynamic: true, DataType: schemapb.DataType_JSON},
+		},
+	}
+	genReq := func(lenient bool, outputFields ...int64) *querypb.QueryRequest {
+		plan, err := proto.Marshal(&planpb.PlanNode{OutputFieldIds: outputFields})
+		suite.Require().NoError(err)
+		return &querypb.QueryRequest{
+			Req: &internalpb.RetrieveRequest{
+				SerializedExprPlan:  plan,
+				OutputFieldsId:      outputFields,
+				LenientOutputFields: lenient,
+			},
+		}
+	}
+
+	// strict mode keeps the request as it is
+	req := genReq(false, 100, 999)
+	applied, dropped, err := applyLenientOutputFields(req, schema)
+	suite.NoError(err)
+	suite.Same(req, applied)
+	suite.Empty(dropped)
+
+	// nothing to drop
+	req = genReq(true, common.RowIDField, common.TimeStampField, 100, 101)
+	applied, dropped, err = applyLenientOutputFields(req, schema)
+	suite.NoError(err)
+	suite.Same(req, applied)
+	suite.Empty(dropped)
+
+	// unknown field and dynamic field of collection without dynamic field enabled are dropped
+	req = genReq(true, 100, 999, 102, 101)
+	applied, dropped, err = applyLenientOutputFields(req, schema)
+	suite.NoError(err)
+	suite.Equal([]int64{999, 102}, dropped)
+	suite.Equal([]int64{100, 101}, applied.GetReq().GetOutputFieldsId())
+	plan := &planpb.PlanNode{}
+	suite.NoError(proto.Unmarshal(applied.GetReq().GetSerializedExprPlan(), plan))
+	suite.Equal([]int64{100, 101}, plan.GetOutputFieldIds())
+	// the shared request is not modified
+	suite.Equal([]int64{100, 999, 102, 101}, req.GetReq().GetOutputFieldsId())
+
+	result := &internalpb.RetrieveResults{}
+	fillDroppedOutputFields(result, dropped)
+	suite.Equal([]int64{999, 102}, result.GetDroppedOutputFieldIDs())
+	suite.Contains(result.GetWarning(), "999")
+
+	// dynamic field is valid if enabled
+	schema.EnableDynamicField = true
+	_, dropped, err = applyLenientOutputFields(genReq(true, 100, 102), schema)
+	suite.NoError(err)
+	suite.Empty(dropped)
+
+	// malformed plan
+	req = genReq(true, 999)
+	req.Req.SerializedExprPlan = []byte("malformed")
+	_, _, err = applyLenientOutputFields(req, schema)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestGetChannelDeleteStats() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetChannelDeleteStats(ctx, suite.collectionID)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+
+	// collection not loaded
+	_, err = suite.node.GetChannelDeleteStats(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	for i, channel := range []string{suite.channel + "-1", suite.channel + "-0"} {
+		sd := delegator.NewMockShardDelegator(suite.T())
+		sd.EXPECT().Collection().Return(suite.collectionID)
+		sd.EXPECT().GetDeleteStats().Return(delegator.DeleteStats{BufferedDeletes: int64(i + 1)})
+		suite.node.delegators.Insert(channel, sd)
+	}
+	// delegator of other collection is ignored
+	other := delegator.NewMockShardDelegator(suite.T())
+	other.EXPECT().Collection().Return(suite.collectionID + 1)
+	suite.node.delegators.Insert(suite.channel+"-other", other)
+
+	segment := segments.NewMockSegment(suite.T())
+	segment.EXPECT().InsertCount().Return(100)
+	segment.EXPECT().RowNum().Return(75)
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{segment})
+
+	stats, err := suite.node.GetChannelDeleteStats(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.Len(stats, 2)
+	suite.Equal(suite.channel+"-0", stats[0].Channel)
+	suite.EqualValues(2, stats[0].BufferedDeletes)
+	suite.Equal(suite.channel+"-1", stats[1].Channel)
+	suite.EqualValues(1, stats[1].BufferedDeletes)
+	for _, channelStats := range stats {
+		suite.EqualValues(100, channelStats.TotalRows)
+		suite.EqualValues(25, channelStats.DeletedRows)
+		suite.Equal(0.25, channelStats.DeleteRatio)
+	}
+}
+
+func (suite *HandlersSuite) TestChooseFilterStrategy() {
+	ctx := context.Background()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, int32FieldID = 107, 109, 103
+	genReq := func(predicates *planpb.Expr, strategy string) *querypb.SearchRequest {
+		plan, err := proto.Marshal(&planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{
+				VectorAnns: &planpb.VectorANNS{
+					FieldId:    vecFieldID,
+					Predicates: predicates,
+					QueryInfo:  &planpb.QueryInfo{Topk: 10, RoundDecimal: -1},
+				},
+			},
+		})
+		suite.Require().NoError(err)
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				Base:               &commonpb.MsgBase{},
+				CollectionID:       suite.collectionID,
+				MetricType:         "L2",
+				Nq:                 1,
+				Topk:               10,
+				SerializedExprPlan: plan,
+				FilterStrategy:     strategy,
+			},
+			DmlChannels: []string{suite.channel},
+		}
+	}
+	pkTerm := &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{
+		ColumnInfo: &planpb.ColumnInfo{FieldId: pkFieldID, DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+		Values: []*planpb.GenericValue{
+			{Val: &planpb.GenericValue_Int64Val{Int64Val: 1}},
+			{Val: &planpb.GenericValue_Int64Val{Int64Val: 2}},
+		},
+	}}}
+	int32Range := &planpb.Expr{Expr: &planpb.Expr_UnaryRangeExpr{UnaryRangeExpr: &planpb.UnaryRangeExpr{
+		ColumnInfo: &planpb.ColumnInfo{FieldId: int32FieldID, DataType: schemapb.DataType_Int32},
+		Op:         planpb.OpType_GreaterThan,
+		Value:      &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: 100}},
+	}}}
+
+	var count int64
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.True(req.GetReq().GetIsCount())
+		suite.Equal([]string{suite.channel}, req.GetDmlChannels())
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			FieldsData: []*schemapb.FieldData{{
+				Type:      schemapb.DataType_Int64,
+				FieldName: "count(*)",
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{count}}},
+				}},
+			}},
+		}}, nil
+	}).Maybe()
+
+	// disabled by default
+	decision, err := suite.node.chooseFilterStrategy(ctx, sd, genReq(pkTerm, ""), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyANN, decision.GetStrategy())
+	suite.EqualValues(-1, decision.GetEstimatedCardinality())
+
+	// invalid strategy
+	_, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(pkTerm, "unknown"), suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// brute force specified
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(int32Range, filterStrategyBruteForce), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyBruteForce, decision.GetStrategy())
+	// brute force specified but not supported
+	_, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(nil, filterStrategyBruteForce), suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	suite.params.Save(suite.params.QueryNodeCfg.FilterBruteForceThreshold.Key, "100")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.FilterBruteForceThreshold.Key)
+
+	// no filter
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(nil, ""), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyANN, decision.GetStrategy())
+	suite.Equal("no filter", decision.GetReason())
+
+	// ann specified
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(pkTerm, filterStrategyANN), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyANN, decision.GetStrategy())
+
+	// output fields not supported
+	req := genReq(pkTerm, "")
+	req.Req.OutputFieldsId = []int64{int32FieldID}
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyANN, decision.GetStrategy())
+	suite.Equal("output fields requested", decision.GetReason())
+
+	// pk term bounds the cardinality
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(pkTerm, ""), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyBruteForce, decision.GetStrategy())
+	suite.EqualValues(2, decision.GetEstimatedCardinality())
+	sd.AssertNotCalled(suite.T(), "Query", mock.Anything, mock.Anything)
+
+	// counted by delegator
+	count = 50
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(int32Range, ""), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyBruteForce, decision.GetStrategy())
+	suite.EqualValues(50, decision.GetEstimatedCardinality())
+
+	count = 500
+	decision, err = suite.node.chooseFilterStrategy(ctx, sd, genReq(int32Range, ""), suite.channel)
+	suite.Require().NoError(err)
+	suite.Equal(filterStrategyANN, decision.GetStrategy())
+	suite.EqualValues(500, decision.GetEstimatedCardinality())
+}
+
+func (suite *HandlersSuite) TestSearchChannelBruteForce() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, dim = 107, 109, 128
+	genVector := func(head ...float32) []float32 {
+		vector := make([]float32, dim)
+		copy(vector, head)
+		return vector
+	}
+	// rows matching the filter
+	pks := []int64{4, 3, 2, 1}
+	rows := [][]float32{genVector(0, 3), genVector(1, 1), genVector(3, 0), genVector(1, 1)}
+
+	query := genVector(1, 0)
+	queryBytes := make([]byte, dim*4)
+	for i, v := range query {
+		binary.LittleEndian.PutUint32(queryBytes[i*4:], math.Float32bits(v))
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{queryBytes}}},
+	})
+	suite.Require().NoError(err)
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:    vecFieldID,
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}},
+				QueryInfo:  &planpb.QueryInfo{Topk: 3, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.ElementsMatch([]int64{pkFieldID, vecFieldID}, req.GetReq().GetOutputFieldsId())
+		vectors := make([]float32, 0, len(rows)*dim)
+		for _, row := range rows {
+			vectors = append(vectors, row...)
+		}
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:    schemapb.DataType_Int64,
+					FieldId: pkFieldID,
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: pks}},
+					}},
+				},
+				{
+					Type:    schemapb.DataType_FloatVector,
+					FieldId: vecFieldID,
+					Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+						Dim:  dim,
+						Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: vectors}},
+					}},
+				},
+			},
+		}}, nil
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "L2",
+			Nq:                 1,
+			Topk:               3,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			FilterStrategy:     filterStrategyBruteForce,
+			Explain:            true,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	sd.AssertNotCalled(suite.T(), "Search", mock.Anything, mock.Anything)
+	suite.Require().Len(result.GetFilterStrategyDecisions(), 1)
+	suite.Equal(filterStrategyBruteForce, result.GetFilterStrategyDecisions()[0].GetStrategy())
+	suite.Equal(suite.channel, result.GetFilterStrategyDecisions()[0].GetChannel())
+
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	// tied rows ordered by pk, distances negated
+	suite.Equal([]int64{1, 3, 2}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{-1, -1, -4}, data[0].GetScores())
+	suite.Equal([]int64{3}, data[0].GetTopks())
+}
+
+func (suite *HandlersSuite) TestSearchChannelRecallFallback() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, dim = 107, 109, 128
+	genVector := func(head ...float32) []float32 {
+		vector := make([]float32, dim)
+		copy(vector, head)
+		return vector
+	}
+	// rows matching the filter
+	pks := []int64{4, 3, 2, 1}
+	rows := [][]float32{genVector(0, 3), genVector(1, 1), genVector(3, 0), genVector(1, 1)}
+
+	query := genVector(1, 0)
+	queryBytes := make([]byte, dim*4)
+	for i, v := range query {
+		binary.LittleEndian.PutUint32(queryBytes[i*4:], math.Float32bits(v))
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{queryBytes}}},
+	})
+	suite.Require().NoError(err)
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:    vecFieldID,
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}},
+				QueryInfo:  &planpb.QueryInfo{Topk: 3, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{}).Maybe()
+	// ANN search misses the most of rows matching the filter
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		result, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       3,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{4}}}},
+			Scores:     []float32{-10},
+			Topks:      []int64{1},
+		}, 1, 3, "L2")
+		return []*internalpb.SearchResults{result}, err
+	})
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		if req.GetReq().GetIsCount() {
+			return []*internalpb.RetrieveResults{{
+				Status: merr.Success(),
+				FieldsData: []*schemapb.FieldData{{
+					Type:      schemapb.DataType_Int64,
+					FieldName: "count(*)",
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{int64(len(pks))}}},
+					}},
+				}},
+			}}, nil
+		}
+		vectors := make([]float32, 0, len(rows)*dim)
+		for _, row := range rows {
+			vectors = append(vectors, row...)
+		}
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:    schemapb.DataType_Int64,
+					FieldId: pkFieldID,
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: pks}},
+					}},
+				},
+				{
+					Type:    schemapb.DataType_FloatVector,
+					FieldId: vecFieldID,
+					Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+						Dim:  dim,
+						Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: vectors}},
+					}},
+				},
+			},
+		}}, nil
+	}).Maybe()
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "L2",
+			Nq:                 1,
+			Topk:               3,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+
+	// disabled by default
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.Empty(result.GetFilterStrategyDecisions())
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{4}, data[0].GetIds().GetIntId().GetData())
+	sd.AssertNotCalled(suite.T(), "Query", mock.Anything, mock.Anything)
+
+	suite.params.Save(suite.params.QueryNodeCfg.FilteredSearchMinRecallRatio.Key, "0.5")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.FilteredSearchMinRecallRatio.Key)
+
+	// too many rows matching the filter to brute force
+	suite.params.Save(suite.params.QueryNodeCfg.FilteredSearchFallbackMaxRows.Key, "3")
+	result, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.Empty(result.GetFilterStrategyDecisions())
+	data, err = segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{4}, data[0].GetIds().GetIntId().GetData())
+
+	// fallen back and reported
+	suite.params.Reset(suite.params.QueryNodeCfg.FilteredSearchFallbackMaxRows.Key)
+	result, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.Require().Len(result.GetFilterStrategyDecisions(), 1)
+	decision := result.GetFilterStrategyDecisions()[0]
+	suite.True(decision.GetRecallFallback())
+	suite.Equal(filterStrategyBruteForce, decision.GetStrategy())
+	suite.EqualValues(len(pks), decision.GetEstimatedCardinality())
+	data, err = segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 3, 2}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]int64{3}, data[0].GetTopks())
+}
+
+func (suite *HandlersSuite) TestSearchChannelExplainHit() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, dim = 107, 109, 128
+	genVector := func(head ...float32) []float32 {
+		vector := make([]float32, dim)
+		copy(vector, head)
+		return vector
+	}
+	query := genVector(1, 0)
+	queryBytes := make([]byte, dim*4)
+	for i, v := range query {
+		binary.LittleEndian.PutUint32(queryBytes[i*4:], math.Float32bits(v))
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{queryBytes}}},
+	})
+	suite.Require().NoError(err)
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   vecFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 2, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	indexed := segments.NewMockSegment(suite.T())
+	indexed.EXPECT().ExistIndex(int64(vecFieldID)).Return(true)
+	segmentManager.EXPECT().GetSealed(int64(11)).Return(indexed)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: paramtable.GetNodeID(), Segments: []delegator.SegmentEntry{{SegmentID: 11}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		if !req.GetReq().GetReturnSegmentId() {
+			result, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+				NumQueries: 1,
+				TopK:       2,
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}}},
+				Scores:     []float32{-0.5, -1},
+				Topks:      []int64{2},
+			}, 1, 2, "L2")
+			return []*internalpb.SearchResults{result}, err
+		}
+		// the pk searched alone
+		searchPlan := planpb.PlanNode{}
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &searchPlan))
+		suite.NotNil(searchPlan.GetVectorAnns().GetPredicates().GetTermExpr())
+		suite.EqualValues(1, req.GetReq().GetTopk())
+		data := &schemapb.SearchResultData{
+			NumQueries: 1,
+			TopK:       1,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{5}}}},
+			Scores:     []float32{-3.5},
+			Topks:      []int64{1},
+		}
+		segments.AppendSegmentIDField(data, 11)
+		result, err := segments.EncodeSearchResultData(data, 1, 1, "L2")
+		return []*internalpb.SearchResults{result}, err
+	})
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.ElementsMatch([]int64{pkFieldID, vecFieldID}, req.GetReq().GetOutputFieldsId())
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{5}}}},
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:    schemapb.DataType_Int64,
+					FieldId: pkFieldID,
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{5}}},
+					}},
+				},
+				{
+					Type:    schemapb.DataType_FloatVector,
+					FieldId: vecFieldID,
+					Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+						Dim:  dim,
+						Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: genVector(3, 0)}},
+					}},
+				},
+			},
+		}}, nil
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "L2",
+			Nq:                 1,
+			Topk:               2,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			FilterStrategy:     filterStrategyANN,
+			ExplainPk:          &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{5, 6}}}},
+		},
+		DmlChannels: []string{suite.channel},
+	}
+
+	// only one pk could be explained
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// explained although not in topK
+	req.Req.ExplainPk = &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{5}}}}
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	suite.Require().Len(result.GetHitExplanations(), 1)
+	explanation := result.GetHitExplanations()[0]
+	suite.True(explanation.GetExists())
+	suite.True(explanation.GetMatchesFilter())
+	suite.InDelta(4, explanation.GetExactScore(), 1e-6)
+	suite.InDelta(3.5, explanation.GetIndexScore(), 1e-6)
+	suite.EqualValues(11, explanation.GetSegmentID())
+	suite.Equal(searchModeIndex, explanation.GetSearchMode())
+	suite.False(explanation.GetInTopk())
+	suite.EqualValues(-1, explanation.GetRank())
+}
+
+// genCountRetrieveResult generates the retrieve result of count(*).
+func genCountRetrieveResult(count int64) *internalpb.RetrieveResults {
+	return &internalpb.RetrieveResults{
+		Status: merr.Success(),
+		FieldsData: []*schemapb.FieldData{{
+			Type:      schemapb.DataType_Int64,
+			FieldName: "count(*)",
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{count}}},
+			}},
+		}},
+	}
+}
+
+func (suite *HandlersSuite) TestSearchChannelExactSearch() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, dim = 107, 109, 128
+	genVector := func(head ...float32) []float32 {
+		vector := make([]float32, dim)
+		copy(vector, head)
+		return vector
+	}
+	pks := []int64{3, 2, 1}
+	rows := [][]float32{genVector(1, 1), genVector(3, 0), genVector(1, 0)}
+
+	queryBytes := make([]byte, dim*4)
+	for i, v := range genVector(1, 0) {
+		binary.LittleEndian.PutUint32(queryBytes[i*4:], math.Float32bits(v))
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{queryBytes}}},
+	})
+	suite.Require().NoError(err)
+	// no filter, all rows are scanned
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   vecFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 2, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		retrievePlan := &planpb.PlanNode{}
+		suite.Require().NoError(proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), retrievePlan))
+		suite.NotNil(retrievePlan.GetQuery().GetPredicates().GetAlwaysTrueExpr())
+		if req.GetReq().GetIsCount() {
+			return []*internalpb.RetrieveResults{genCountRetrieveResult(int64(len(pks)))}, nil
+		}
+		vectors := make([]float32, 0, len(rows)*dim)
+		for _, row := range rows {
+			vectors = append(vectors, row...)
+		}
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:    schemapb.DataType_Int64,
+					FieldId: pkFieldID,
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: pks}},
+					}},
+				},
+				{
+					Type:    schemapb.DataType_FloatVector,
+					FieldId: vecFieldID,
+					Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+						Dim:  dim,
+						Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: vectors}},
+					}},
+				},
+			},
+		}}, nil
+	}).Times(3)
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "L2",
+			Nq:                 1,
+			Topk:               2,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			ExactSearch:        true,
+			Explain:            true,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	sd.AssertNotCalled(suite.T(), "Search", mock.Anything, mock.Anything)
+	suite.Require().Len(result.GetFilterStrategyDecisions(), 1)
+	suite.Equal("exact search requested", result.GetFilterStrategyDecisions()[0].GetReason())
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 3}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{0, -1}, data[0].GetScores())
+	suite.EqualValues(0, suite.node.exactSearches.Load())
+
+	// too many rows to retrieve, only counted
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.ExactSearchMaxRows.Key, "2")
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+	paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.ExactSearchMaxRows.Key)
+	suite.EqualValues(0, suite.node.exactSearches.Load())
+
+	// concurrency limit reached
+	suite.node.exactSearches.Store(paramtable.Get().QueryNodeCfg.ExactSearchMaxConcurrency.GetAsInt64())
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrServiceRateLimit)
+	suite.node.exactSearches.Store(0)
+
+	// not supported with refine
+	req.Req.RefineFactor = 2
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+	req.Req.RefineFactor = 0
+
+	// disabled
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.ExactSearchMaxConcurrency.Key, "0")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.ExactSearchMaxConcurrency.Key)
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrServiceUnavailable)
+}
+
+func (suite *HandlersSuite) TestGetGroundTruth() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	suite.node.groundTruths = newGroundTruthCache()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, dim = 107, 109, 128
+	genVector := func(head ...float32) []float32 {
+		vector := make([]float32, dim)
+		copy(vector, head)
+		return vector
+	}
+	pks := []int64{3, 2, 1}
+	vectors := make([]float32, 0, len(pks)*dim)
+	for _, row := range [][]float32{genVector(1, 1), genVector(3, 0), genVector(1, 0)} {
+		vectors = append(vectors, row...)
+	}
+	queryBytes := make([]byte, dim*4)
+	for i, v := range genVector(1, 0) {
+		binary.LittleEndian.PutUint32(queryBytes[i*4:], math.Float32bits(v))
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{queryBytes}}},
+	})
+	suite.Require().NoError(err)
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   vecFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 2, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	sealed := []delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().GetTargetVersion().Return(1)
+	sd.EXPECT().GetSegmentInfo(true).RunAndReturn(func(bool) ([]delegator.SnapshotItem, []delegator.SegmentEntry) {
+		return sealed, []delegator.SegmentEntry{}
+	})
+	computed := 0
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		if req.GetReq().GetIsCount() {
+			return []*internalpb.RetrieveResults{genCountRetrieveResult(int64(len(pks)))}, nil
+		}
+		computed++
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:    schemapb.DataType_Int64,
+					FieldId: pkFieldID,
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: pks}},
+					}},
+				},
+				{
+					Type:    schemapb.DataType_FloatVector,
+					FieldId: vecFieldID,
+					Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+						Dim:  dim,
+						Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: vectors}},
+					}},
+				},
+			},
+		}}, nil
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "L2",
+			Nq:                 1,
+			Topk:               2,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			GuaranteeTimestamp: 100,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+	truth, err := suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	suite.False(truth.Cached)
+	suite.EqualValues(1, truth.TargetVersion)
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{truth.Results})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 3}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal(1, computed)
+
+	// cached regardless of guarantee timestamp
+	req.Req.GuaranteeTimestamp = 200
+	truth, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	suite.True(truth.Cached)
+	suite.Equal(1, computed)
+
+	// segment distribution changed
+	sealed = []delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}, {SegmentID: 2}}}}
+	truth, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	suite.False(truth.Cached)
+	suite.Equal(2, computed)
+
+	// query set of the name changed
+	req.Req.Topk = 1
+	truth, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	suite.False(truth.Cached)
+	suite.Equal(3, computed)
+
+	// invalidated by sync distribution
+	suite.node.groundTruths.invalidate(suite.channel)
+	truth, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	suite.False(truth.Cached)
+	suite.Equal(4, computed)
+
+	// cache disabled
+	suite.params.Save(suite.params.QueryNodeCfg.GroundTruthCacheCapacity.Key, "0")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.GroundTruthCacheCapacity.Key)
+	suite.node.groundTruths.invalidate(suite.channel)
+	_, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	truth, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.Require().NoError(err)
+	suite.False(truth.Cached)
+	suite.Equal(6, computed)
+
+	// invalid requests
+	_, err = suite.node.GetGroundTruth(ctx, "", req)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+	req.DmlChannels = []string{"unknown"}
+	_, err = suite.node.GetGroundTruth(ctx, "nightly", req)
+	suite.ErrorIs(err, merr.ErrChannelNotFound)
+}
+
+func (suite *HandlersSuite) TestGroundTruthCacheEviction() {
+	suite.params.Save(suite.params.QueryNodeCfg.GroundTruthCacheCapacity.Key, "2")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.GroundTruthCacheCapacity.Key)
+
+	cache := newGroundTruthCache()
+	now := time.Now()
+	for i, name := range []string{"a", "b", "c"} {
+		cache.put(groundTruthKey{name: name, channel: "dml_0"}, &groundTruthEntry{
+			truth: &GroundTruth{Name: name, ComputedAt: now.Add(time.Duration(i) * time.Second)},
+		})
+	}
+	cache.put(groundTruthKey{name: "a", channel: "dml_1"}, &groundTruthEntry{truth: &GroundTruth{Name: "a", ComputedAt: now}})
+
+	// the least recently computed one of channel evicted
+	_, ok := cache.get(groundTruthKey{name: "a", channel: "dml_0"}, "", "")
+	suite.False(ok)
+	truth, ok := cache.get(groundTruthKey{name: "c", channel: "dml_0"}, "", "")
+	suite.True(ok)
+	suite.True(truth.Cached)
+	_, ok = cache.get(groundTruthKey{name: "a", channel: "dml_1"}, "", "")
+	suite.True(ok)
+}
+
+func (suite *HandlersSuite) TestBruteForceScore() {
+	query := []float32{1, 0}
+	vector := []float32{3, 4}
+	suite.InDelta(-20.0, bruteForceScore("L2", query, vector), 1e-6)
+	suite.InDelta(3.0, bruteForceScore("IP", query, vector), 1e-6)
+	suite.InDelta(0.6, bruteForceScore("cosine", query, vector), 1e-6)
+	suite.Zero(bruteForceScore("COSINE", query, []float32{0, 0}))
+
+	suite.Equal(float32(0.123456), roundScore(0.123456, -1))
+	suite.InDelta(0.12, roundScore(0.123456, 2), 1e-6)
+}
+
+func (suite *HandlersSuite) TestRequestCounters() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetRequestCounters(ctx)
+	suite.Error(err)
+	suite.Error(suite.node.ResetRequestCounters(ctx, nil))
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	placeholderGroup, err := genPlaceHolderGroup(1)
+	suite.Require().NoError(err)
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{QueryInfo: &planpb.QueryInfo{Topk: 10}},
+		},
+	})
+	suite.Require().NoError(err)
+	genReq := func(collectionID int64) *querypb.SearchRequest {
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				CollectionID:       collectionID,
+				MetricType:         "L2",
+				Nq:                 1,
+				Topk:               10,
+				PlaceholderGroup:   placeholderGroup,
+				SerializedExprPlan: plan,
+			},
+			DmlChannels: []string{suite.channel},
+		}
+	}
+
+	// invalid request
+	req := genReq(suite.collectionID)
+	req.Req.Nq = 0
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// no segment to search
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{}, []delegator.SegmentEntry{})
+	suite.node.delegators.Insert(suite.channel, sd)
+	_, err = suite.node.searchChannel(ctx, genReq(suite.collectionID), suite.channel)
+	suite.NoError(err)
+	_, err = suite.node.searchChannel(ctx, genReq(suite.collectionID+1), suite.channel)
+	suite.NoError(err)
+
+	counters, err := suite.node.GetRequestCounters(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(counters, 2)
+	suite.Equal(suite.collectionID, counters[0].CollectionID)
+	suite.EqualValues(1, counters[0].SearchSuccess)
+	suite.EqualValues(1, counters[0].SearchFailure)
+	suite.Zero(counters[0].QuerySuccess)
+	suite.Zero(counters[0].QueryFailure)
+	suite.False(counters[0].Since.IsZero())
+	suite.Equal(suite.collectionID+1, counters[1].CollectionID)
+	suite.EqualValues(1, counters[1].SearchSuccess)
+	suite.Zero(counters[1].SearchFailure)
+
+	// reset one collection
+	suite.NoError(suite.node.ResetRequestCounters(ctx, []int64{suite.collectionID}))
+	counters, err = suite.node.GetRequestCounters(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(counters, 1)
+	suite.Equal(suite.collectionID+1, counters[0].CollectionID)
+
+	// reset all
+	suite.NoError(suite.node.ResetRequestCounters(ctx, nil))
+	counters, err = suite.node.GetRequestCounters(ctx)
+	suite.Require().NoError(err)
+	suite.Empty(counters)
+}
+
+func (suite *HandlersSuite) TestDropCollectionPageCache() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.DropCollectionPageCache(ctx, suite.collectionID)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+
+	// collection not loaded
+	_, err = suite.node.DropCollectionPageCache(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	// mmap disabled
+	suite.params.Save(suite.params.QueryNodeCfg.MmapDirPath.Key, "")
+	_, err = suite.node.DropCollectionPageCache(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrServiceUnavailable)
+
+	// segments not mmapped
+	suite.params.Save(suite.params.QueryNodeCfg.MmapDirPath.Key, suite.T().TempDir())
+	defer suite.params.Reset(suite.params.QueryNodeCfg.MmapDirPath.Key)
+	segment := segments.NewMockSegment(suite.T())
+	segment.EXPECT().ID().Return(1)
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{segment})
+	released, err := suite.node.DropCollectionPageCache(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.Zero(released)
+}
+
+func (suite *HandlersSuite) TestSearchChannelScanEstimate() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{QueryInfo: &planpb.QueryInfo{Topk: 2}},
+		},
+	})
+	suite.Require().NoError(err)
+	placeholderGroup, err := genPlaceHolderGroup(1)
+	suite.Require().NoError(err)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{
+		{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}, {SegmentID: 2}}},
+		{NodeID: 2, Segments: []delegator.SegmentEntry{{SegmentID: 3}}},
+	}, []delegator.SegmentEntry{{SegmentID: 4}})
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		genResult := func(pk int64, segmentNum, rowNum int64) *internalpb.SearchResults {
+			result, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+				NumQueries: 1,
+				TopK:       2,
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{pk}}}},
+				Scores:     []float32{0.9},
+				Topks:      []int64{1},
+			}, 1, 2, "IP")
+			suite.Require().NoError(err)
+			result.ScannedSegments, result.ScannedRows = segmentNum, rowNum
+			return result
+		}
+		results := []*internalpb.SearchResults{genResult(1, 3, 3000), genResult(2, 1, 10)}
+		if req.GetReq().GetExplain() {
+			results = append(results, &internalpb.SearchResults{Status: merr.Success()})
+		}
+		return results, nil
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	genReq := func(explain bool) *querypb.SearchRequest {
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				CollectionID:       suite.collectionID,
+				MetricType:         "IP",
+				Nq:                 1,
+				Topk:               2,
+				PlaceholderGroup:   placeholderGroup,
+				SerializedExprPlan: plan,
+				Explain:            explain,
+			},
+			DmlChannels:     []string{suite.channel},
+			TotalChannelNum: 2,
+		}
+	}
+
+	result, err := suite.node.searchChannel(ctx, genReq(false), suite.channel)
+	suite.Require().NoError(err)
+	suite.EqualValues(4, result.GetScannedSegments())
+	suite.EqualValues(3010, result.GetScannedRows())
+	suite.Empty(result.GetScanEstimates())
+
+	// sealed segments of all channels estimated, growing ones ignored
+	result, err = suite.node.searchChannel(ctx, genReq(true), suite.channel)
+	suite.Require().NoError(err)
+	suite.Require().Len(result.GetScanEstimates(), 1)
+	estimate := result.GetScanEstimates()[0]
+	suite.Equal(suite.channel, estimate.GetChannel())
+	suite.EqualValues(6, estimate.GetEstimatedSegmentNum())
+	suite.EqualValues(4, estimate.GetScannedSegments())
+	suite.EqualValues(3010, estimate.GetScannedRows())
+}
+
+func (suite *HandlersSuite) TestGetLoadedIndexes() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetLoadedIndexes(ctx, suite.collectionID)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	// collection not loaded
+	_, err = suite.node.GetLoadedIndexes(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	indexMeta := &segcorepb.CollectionIndexMeta{
+		IndexMetas: []*segcorepb.FieldIndexMeta{{
+			FieldID:     107,
+			IndexName:   "vector_index",
+			IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "HNSW"}},
+		}},
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), indexMeta, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	minIndexRows := paramtable.Get().DataCoordCfg.MinSegmentNumRowsToEnableIndex.GetAsInt64()
+	mockSegment := func(segmentID int64, rows int64, indexType string) *segments.MockSegment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().ID().Return(segmentID).Maybe()
+		segment.EXPECT().InsertCount().Return(rows).Maybe()
+		if indexType == "" {
+			segment.EXPECT().Indexes().Return(nil).Maybe()
+			segment.EXPECT().ExistIndex(int64(107)).Return(false).Maybe()
+			return segment
+		}
+		segment.EXPECT().Indexes().Return([]*segments.IndexedFieldInfo{{
+			IndexInfo: &querypb.FieldIndexInfo{
+				FieldID:     107,
+				EnableIndex: true,
+				IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: indexType}},
+			},
+		}}).Maybe()
+		segment.EXPECT().ExistIndex(int64(107)).Return(true).Maybe()
+		return segment
+	}
+
+	// not a delegator, all loaded sealed segments are checked
+	indexed := mockSegment(1, minIndexRows, "HNSW")
+	small := mockSegment(2, minIndexRows-1, "")
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{indexed, small}).Once()
+	states, err := suite.node.GetLoadedIndexes(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.Len(states, 1)
+	suite.EqualValues(107, states[0].FieldID)
+	suite.Equal("vector_index", states[0].IndexName)
+	suite.Equal("HNSW", states[0].TargetIndexType)
+	suite.Equal(2, states[0].TargetSegments)
+	suite.Equal(map[string]int{"HNSW": 1}, states[0].LoadedIndexTypes)
+	suite.Empty(states[0].BruteForceSegments)
+	suite.True(states[0].MatchesTarget)
+
+	// delegator target, segments served by other nodes are not checked
+	remoteNodeID := paramtable.GetNodeID() + 1
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{
+		{NodeID: paramtable.GetNodeID(), Segments: []delegator.SegmentEntry{{SegmentID: 3}, {SegmentID: 4}}},
+		{NodeID: remoteNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 5}}},
+	}, nil)
+	suite.node.delegators.Insert(suite.channel, sd)
+	segmentManager.EXPECT().GetSealed(int64(3)).Return(mockSegment(3, minIndexRows, "IVF_FLAT"))
+	segmentManager.EXPECT().GetSealed(int64(4)).Return(mockSegment(4, minIndexRows, ""))
+
+	states, err = suite.node.GetLoadedIndexes(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.Len(states, 1)
+	suite.Equal(2, states[0].TargetSegments)
+	suite.Equal(map[string]int{"IVF_FLAT": 1}, states[0].LoadedIndexTypes)
+	suite.Equal([]int64{4}, states[0].BruteForceSegments)
+	suite.False(states[0].MatchesTarget)
+}
+
+func (suite *HandlersSuite) TestCheckIndexConversion() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.CheckIndexConversion(ctx, suite.collectionID)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	// collection not loaded
+	_, err = suite.node.CheckIndexConversion(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	indexMeta := &segcorepb.CollectionIndexMeta{
+		IndexMetas: []*segcorepb.FieldIndexMeta{{
+			FieldID:     107,
+			IndexName:   "vector_index",
+			IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "HNSW"}},
+		}},
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), indexMeta, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	minIndexRows := paramtable.Get().DataCoordCfg.MinSegmentNumRowsToEnableIndex.GetAsInt64()
+	mockSegment := func(segmentID int64, rows int64, indexType string) *segments.MockSegment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().ID().Return(segmentID).Maybe()
+		segment.EXPECT().InsertCount().Return(rows).Maybe()
+		if indexType == "" {
+			segment.EXPECT().Indexes().Return(nil).Maybe()
+			return segment
+		}
+		segment.EXPECT().Indexes().Return([]*segments.IndexedFieldInfo{{
+			IndexInfo: &querypb.FieldIndexInfo{
+				FieldID:     107,
+				EnableIndex: true,
+				IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: indexType}},
+			},
+		}}).Maybe()
+		return segment
+	}
+
+	// all converted, small segment served by brute force
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{
+		mockSegment(2, minIndexRows-1, ""),
+		mockSegment(1, minIndexRows, "HNSW"),
+	}).Once()
+	report, err := suite.node.CheckIndexConversion(ctx, suite.collectionID)
+	suite.Require().NoError(err)
+	suite.True(report.Completed)
+	suite.Empty(report.StaleSegments)
+	suite.Require().Len(report.Segments, 2)
+	suite.EqualValues(1, report.Segments[0].SegmentID)
+	suite.Equal("HNSW", report.Segments[0].ServedIndexType)
+	suite.True(report.Segments[0].Converted)
+	suite.EqualValues(2, report.Segments[1].SegmentID)
+	suite.Equal("", report.Segments[1].ServedIndexType)
+	suite.True(report.Segments[1].Converted)
+
+	// migrating from IVF_FLAT, stale and brute force segments are not converted
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{
+		mockSegment(1, minIndexRows, "HNSW"),
+		mockSegment(2, minIndexRows, "IVF_FLAT"),
+		mockSegment(3, minIndexRows, ""),
+	}).Once()
+	report, err = suite.node.CheckIndexConversion(ctx, suite.collectionID)
+	suite.Require().NoError(err)
+	suite.False(report.Completed)
+	suite.Equal([]int64{2}, report.StaleSegments)
+	suite.Require().Len(report.Segments, 3)
+	suite.Equal("IVF_FLAT", report.Segments[1].ServedIndexType)
+	suite.Equal("HNSW", report.Segments[1].TargetIndexType)
+	suite.True(report.Segments[1].StaleIndex)
+	suite.False(report.Segments[1].Converted)
+	suite.False(report.Segments[2].StaleIndex)
+	suite.False(report.Segments[2].Converted)
+}
+
+func (suite *HandlersSuite) TestGetChannelCheckpointLag() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetChannelCheckpointLag(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	lags, err := suite.node.GetChannelCheckpointLag(ctx)
+	suite.NoError(err)
+	suite.Empty(lags)
+
+	checkpoint := time.Now().Add(-time.Minute)
+	stale := delegator.NewMockShardDelegator(suite.T())
+	stale.EXPECT().Collection().Return(suite.collectionID)
+	stale.EXPECT().GetTSafe().Return(tsoutil.ComposeTSByTime(checkpoint, 0))
+	suite.node.delegators.Insert("channel-a", stale)
+	// tsafe ahead of local clock is treated as no lag
+	ahead := delegator.NewMockShardDelegator(suite.T())
+	ahead.EXPECT().Collection().Return(suite.collectionID)
+	ahead.EXPECT().GetTSafe().Return(tsoutil.ComposeTSByTime(time.Now().Add(time.Hour), 0))
+	suite.node.delegators.Insert("channel-b", ahead)
+
+	lags, err = suite.node.GetChannelCheckpointLag(ctx)
+	suite.NoError(err)
+	suite.Len(lags, 2)
+	suite.Equal("channel-a", lags[0].Channel)
+	suite.Equal(suite.collectionID, lags[0].CollectionID)
+	suite.Equal(checkpoint.UnixMilli(), lags[0].CheckpointTime.UnixMilli())
+	suite.GreaterOrEqual(lags[0].TimeLag, time.Minute-time.Millisecond)
+	suite.Equal("channel-b", lags[1].Channel)
+	suite.Zero(lags[1].TimeLag)
+}
+
+func (suite *HandlersSuite) TestFieldDenylist() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	suite.Error(suite.node.SetFieldDenylist(ctx, suite.collectionID, []int64{101}, nil))
+	_, err := suite.node.GetFieldDenylists(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	// system fields could not be denied
+	err = suite.node.SetFieldDenylist(ctx, suite.collectionID, []int64{common.RowIDField}, nil)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	suite.NoError(suite.node.SetFieldDenylist(ctx, suite.collectionID, []int64{101}, []string{"admin"}))
+	denylists, err := suite.node.GetFieldDenylists(ctx)
+	suite.NoError(err)
+	suite.Len(denylists, 1)
+	suite.Equal(suite.collectionID, denylists[0].CollectionID)
+	suite.Equal([]int64{101}, denylists[0].FieldIDs)
+	suite.Equal([]string{"admin"}, denylists[0].AllowedUsers)
+
+	suite.NoError(suite.node.SetFieldDenylist(ctx, suite.collectionID, nil, nil))
+	denylists, err = suite.node.GetFieldDenylists(ctx)
+	suite.NoError(err)
+	suite.Empty(denylists)
+}
+
+func (suite *HandlersSuite) TestGetSearchLatencyPercentiles() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetSearchLatencyPercentiles(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.latencyHistograms = optimizers.NewLatencyHistograms()
+	percentiles, err := suite.node.GetSearchLatencyPercentiles(ctx)
+	suite.NoError(err)
+	suite.Empty(percentiles)
+
+	suite.node.latencyHistograms.Observe(suite.collectionID, 10*time.Millisecond)
+	percentiles, err = suite.node.GetSearchLatencyPercentiles(ctx)
+	suite.NoError(err)
+	suite.Len(percentiles, 1)
+	suite.Equal(suite.collectionID, percentiles[0].CollectionID)
+	suite.EqualValues(1, percentiles[0].Count)
+	suite.InEpsilon(float64(10*time.Millisecond), float64(percentiles[0].P99), 0.1)
+}
+
+func (suite *HandlersSuite) TestSearchChannelRefine() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	collectionManager.PutOrRef(suite.collectionID, schema, nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	const vecFieldID, pkFieldID, dim = 107, 109, 128
+	genVector := func(head ...float32) []float32 {
+		vector := make([]float32, dim)
+		copy(vector, head)
+		return vector
+	}
+	// candidates found over quantized index with approximate scores
+	pks := []int64{4, 3, 2, 1}
+	rows := [][]float32{genVector(0, 3), genVector(1, 1), genVector(3, 0), genVector(1, 1)}
+
+	query := genVector(1, 0)
+	queryBytes := make([]byte, dim*4)
+	for i, v := range query {
+		binary.LittleEndian.PutUint32(queryBytes[i*4:], math.Float32bits(v))
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{queryBytes}}},
+	})
+	suite.Require().NoError(err)
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   vecFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 2, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+	candidates, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+		NumQueries: 1,
+		TopK:       4,
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+		Scores:     []float32{-0.1, -0.2, -0.3, -0.4},
+		Topks:      []int64{4},
+	}, 1, 4, "L2")
+	suite.Require().NoError(err)
+	rawVectors := func(pks []int64, rows [][]float32) []*internalpb.RetrieveResults {
+		vectors := make([]float32, 0, len(rows)*dim)
+		for _, row := range rows {
+			vectors = append(vectors, row...)
+		}
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:    schemapb.DataType_Int64,
+					FieldId: pkFieldID,
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: pks}},
+					}},
+				},
+				{
+					Type:    schemapb.DataType_FloatVector,
+					FieldId: vecFieldID,
+					Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+						Dim:  dim,
+						Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: vectors}},
+					}},
+				},
+			},
+		}}
+	}
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{})
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		// topK * refine factor candidates
+		suite.EqualValues(4, req.GetReq().GetTopk())
+		return []*internalpb.SearchResults{candidates}, nil
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "L2",
+			Nq:                 1,
+			Topk:               2,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			FilterStrategy:     filterStrategyANN,
+			RefineFactor:       2,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+
+	// reranked by exact distances, tied ones ordered by pk
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.ElementsMatch([]int64{pkFieldID, vecFieldID}, req.GetReq().GetOutputFieldsId())
+		return rawVectors(pks, rows), nil
+	}).Once()
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 3}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{-1, -1}, data[0].GetScores())
+	suite.Equal([]int64{2}, result.GetTopks())
+	suite.Equal([]bool{true}, result.GetTruncated())
+
+	// raw vectors of some candidates not available
+	sd.EXPECT().Query(mock.Anything, mock.Anything).Return(rawVectors(pks[:2], rows[:2]), nil).Once()
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrServiceUnavailable)
+
+	// invalid refine factor
+	req.Req.RefineFactor = paramtable.Get().QueryNodeCfg.RefineMaxFactor.GetAsInt64() + 1
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestSearchChannelRerankByField() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+
+	const coarseFieldID, fineFieldID, pkFieldID, coarseDim, fineDim = 107, 110, 109, 128, 4
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	schema.Fields = append(schema.Fields, &schemapb.FieldSchema{
+		FieldID:    fineFieldID,
+		Name:       "fine_vector",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: strconv.Itoa(fineDim)}},
+	})
+	indexMeta := &segcorepb.CollectionIndexMeta{
+		IndexMetas: []*segcorepb.FieldIndexMeta{
+			{FieldID: coarseFieldID, IndexParams: []*commonpb.KeyValuePair{{Key: common.MetricTypeKey, Value: "L2"}}},
+			{FieldID: fineFieldID, IndexParams: []*commonpb.KeyValuePair{{Key: common.MetricTypeKey, Value: "IP"}}},
+		},
+	}
+	collectionManager.PutOrRef(suite.collectionID, schema, indexMeta, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	placeholderGroup := func(dim int, head ...float32) []byte {
+		query := make([]byte, dim*4)
+		for i, v := range head {
+			binary.LittleEndian.PutUint32(query[i*4:], math.Float32bits(v))
+		}
+		group, err := proto.Marshal(&commonpb.PlaceholderGroup{
+			Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{query}}},
+		})
+		suite.Require().NoError(err)
+		return group
+	}
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   coarseFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 2, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	// candidates found over the coarse field
+	pks := []int64{4, 3, 2, 1}
+	candidates, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+		NumQueries: 1,
+		TopK:       4,
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+		Scores:     []float32{-0.1, -0.2, -0.3, -0.4},
+		Topks:      []int64{4},
+	}, 1, 4, "L2")
+	suite.Require().NoError(err)
+	fineVectors := []float32{
+		0, 0, 0, 1,
+		1, 0, 0, 0,
+		0.5, 0, 0, 0,
+		0, 1, 0, 0,
+	}
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{}).Maybe()
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		suite.EqualValues(4, req.GetReq().GetTopk())
+		return []*internalpb.SearchResults{candidates}, nil
+	}).Once()
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.ElementsMatch([]int64{pkFieldID, fineFieldID}, req.GetReq().GetOutputFieldsId())
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{{
+				Type:    schemapb.DataType_FloatVector,
+				FieldId: fineFieldID,
+				Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+					Dim:  fineDim,
+					Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: fineVectors}},
+				}},
+			}},
+		}}, nil
+	}).Once()
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:                   &commonpb.MsgBase{},
+			CollectionID:           suite.collectionID,
+			MetricType:             "L2",
+			Nq:                     1,
+			Topk:                   2,
+			PlaceholderGroup:       placeholderGroup(coarseDim, 1),
+			SerializedExprPlan:     plan,
+			FilterStrategy:         filterStrategyANN,
+			RefineFactor:           2,
+			RerankFieldId:          fineFieldID,
+			RerankMetricType:       "IP",
+			RerankPlaceholderGroup: placeholderGroup(fineDim, 1),
+		},
+		DmlChannels: []string{suite.channel},
+	}
+
+	// reranked by the exact scores on fine field
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{3, 2}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{1, 0.5}, data[0].GetScores())
+	suite.Equal([]bool{true}, result.GetTruncated())
+
+	// dimension of rerank queries mismatches the field
+	req.Req.RerankPlaceholderGroup = placeholderGroup(coarseDim, 1)
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+	req.Req.RerankPlaceholderGroup = placeholderGroup(fineDim, 1)
+
+	// metric type mismatches the index of rerank field
+	req.Req.RerankMetricType = "L2"
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+	req.Req.RerankMetricType = "IP"
+
+	// rerank field is the searched one
+	req.Req.RerankFieldId = coarseFieldID
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// rerank field not found
+	req.Req.RerankFieldId = 999
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrFieldNotFound)
+}
+
+func (suite *HandlersSuite) TestValidateRefine() {
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			MetricType:   "IP",
+			RefineFactor: 4,
+		},
+	}
+	suite.NoError(validateRefine(req, schema, 107, req.GetReq().GetMetricType()))
+	// binary vector field
+	suite.ErrorIs(validateRefine(req, schema, 108, req.GetReq().GetMetricType()), merr.ErrParameterInvalid)
+
+	req.Req.OutputFieldsId = []int64{100}
+	suite.ErrorIs(validateRefine(req, schema, 107, req.GetReq().GetMetricType()), merr.ErrParameterInvalid)
+	req.Req.OutputFieldsId = nil
+
+	req.Req.IsIterator = true
+	suite.ErrorIs(validateRefine(req, schema, 107, req.GetReq().GetMetricType()), merr.ErrParameterInvalid)
+	req.Req.IsIterator = false
+
+	req.Req.MetricType = "HAMMING"
+	suite.ErrorIs(validateRefine(req, schema, 107, req.GetReq().GetMetricType()), merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestReadinessCheck() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.ReadinessCheck(ctx, suite.collectionID)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	// collection not loaded
+	_, err = suite.node.ReadinessCheck(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	indexMeta := &segcorepb.CollectionIndexMeta{
+		IndexMetas: []*segcorepb.FieldIndexMeta{{
+			FieldID:     107,
+			IndexName:   "vector_index",
+			IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "HNSW"}},
+		}},
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), indexMeta, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	// not a delegator of collection
+	_, err = suite.node.ReadinessCheck(ctx, suite.collectionID)
+	suite.ErrorIs(err, merr.ErrServiceUnavailable)
+
+	minIndexRows := paramtable.Get().DataCoordCfg.MinSegmentNumRowsToEnableIndex.GetAsInt64()
+	mockSegment := func(rows int64, indexed bool) *segments.MockSegment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().InsertCount().Return(rows).Maybe()
+		segment.EXPECT().ExistIndex(int64(107)).Return(indexed).Maybe()
+		return segment
+	}
+	localNodeID := paramtable.GetNodeID()
+	remoteNodeID := localNodeID + 1
+
+	// all segments in target are readable
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().Serviceable().Return(true).Once()
+	sd.EXPECT().GetTargetSegments().Return([]int64{1, 2, 3}, []int64{10}).Once()
+	sd.EXPECT().GetSegmentInfo(false).Return([]delegator.SnapshotItem{
+		{NodeID: localNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 1}, {SegmentID: 2}}},
+		{NodeID: remoteNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 3}}},
+	}, []delegator.SegmentEntry{{NodeID: localNodeID, SegmentID: 10}}).Once()
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{
+		{NodeID: localNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 1}, {SegmentID: 2}}},
+		{NodeID: remoteNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 3}}},
+	}, []delegator.SegmentEntry{{NodeID: localNodeID, SegmentID: 10}}).Once()
+	suite.node.delegators.Insert(suite.channel, sd)
+	segmentManager.EXPECT().GetSealed(int64(1)).Return(mockSegment(minIndexRows, true)).Once()
+	// too small to be indexed
+	segmentManager.EXPECT().GetSealed(int64(2)).Return(mockSegment(minIndexRows-1, false)).Once()
+
+	readiness, err := suite.node.ReadinessCheck(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.True(readiness.Ready)
+	suite.Equal([]string{suite.channel}, readiness.Channels)
+	suite.Equal(4, readiness.TargetSegments)
+	suite.Equal(1, readiness.RemoteSegments)
+	suite.Empty(readiness.Missing)
+	suite.Empty(readiness.Degraded)
+
+	// missing and degraded segments
+	sd.EXPECT().Serviceable().Return(false).Once()
+	sd.EXPECT().GetTargetSegments().Return([]int64{1, 2, 3, 4}, []int64{10, 11}).Once()
+	sd.EXPECT().GetSegmentInfo(false).Return([]delegator.SnapshotItem{
+		{NodeID: localNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 1}, {SegmentID: 2}, {SegmentID: 3}}},
+	}, []delegator.SegmentEntry{{NodeID: localNodeID, SegmentID: 10}}).Once()
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{
+		{NodeID: localNodeID, Segments: []delegator.SegmentEntry{{SegmentID: 1}, {SegmentID: 2}}},
+	}, []delegator.SegmentEntry{}).Once()
+	segmentManager.EXPECT().GetSealed(int64(1)).Return(mockSegment(minIndexRows, false)).Once()
+	segmentManager.EXPECT().GetSealed(int64(2)).Return(nil).Once()
+
+	readiness, err = suite.node.ReadinessCheck(ctx, suite.collectionID)
+	suite.NoError(err)
+	suite.False(readiness.Ready)
+	suite.Equal([]string{suite.channel}, readiness.UnserviceableChannels)
+	suite.Equal([]*SegmentReadinessIssue{
+		{SegmentID: 2, Channel: suite.channel, NodeID: localNodeID, Reason: "released from node"},
+		{SegmentID: 3, Channel: suite.channel, NodeID: localNodeID, Reason: "loaded but not readable in current target"},
+		{SegmentID: 4, Channel: suite.channel, Reason: "not loaded by any node"},
+		{SegmentID: 10, Channel: suite.channel, NodeID: localNodeID, Reason: "growing segment not readable in current target"},
+		{SegmentID: 11, Channel: suite.channel, Reason: "growing segment not consumed"},
+	}, readiness.Missing)
+	suite.Equal([]*SegmentReadinessIssue{
+		{SegmentID: 1, Channel: suite.channel, NodeID: localNodeID, Reason: "field 107 served by brute force"},
+	}, readiness.Degraded)
+}
+
+func (suite *HandlersSuite) TestGetGrowingMemoryDistribution() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetGrowingMemoryDistribution(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: segments.NewCollectionManager(),
+		Segment:    segmentManager,
+	}
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	mockGrowing := func(channel string, rows, memSize int64) segments.Segment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().Shard().Return(channel).Maybe()
+		segment.EXPECT().Type().Return(segments.SegmentTypeGrowing).Maybe()
+		segment.EXPECT().RowNum().Return(rows).Maybe()
+		segment.EXPECT().MemSize().Return(memSize).Maybe()
+		return segment
+	}
+	growings := make([]segments.Segment, 0)
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).RunAndReturn(func(filters ...segments.SegmentFilter) []segments.Segment {
+		return lo.Filter(growings, func(segment segments.Segment, _ int) bool {
+			for _, filter := range filters {
+				if !filter(segment) {
+					return false
+				}
+			}
+			return true
+		})
+	})
+	addDelegator := func(channel string, collectionID int64) {
+		sd := delegator.NewMockShardDelegator(suite.T())
+		sd.EXPECT().Collection().Return(collectionID)
+		suite.node.delegators.Insert(channel, sd)
+	}
+
+	// single delegator, no candidate
+	addDelegator("dml_0", 1)
+	growings = append(growings, mockGrowing("dml_0", 100, 1000))
+	distribution, err := suite.node.GetGrowingMemoryDistribution(ctx)
+	suite.NoError(err)
+	suite.Len(distribution.Channels, 1)
+	suite.EqualValues(1000, distribution.TotalMem)
+	suite.Empty(distribution.SealCandidate)
+
+	// the heaviest channel is the candidate
+	addDelegator("dml_1", 2)
+	addDelegator("dml_2", 2)
+	growings = append(growings, mockGrowing("dml_1", 200, 2000), mockGrowing("dml_1", 100, 1000))
+	distribution, err = suite.node.GetGrowingMemoryDistribution(ctx)
+	suite.NoError(err)
+	suite.Equal([]string{"dml_1", "dml_0", "dml_2"}, lo.Map(distribution.Channels, func(usage *ChannelGrowingMemory, _ int) string {
+		return usage.Channel
+	}))
+	suite.Equal(&ChannelGrowingMemory{Channel: "dml_1", CollectionID: 2, Segments: 2, Rows: 300, MemSize: 3000, Share: 0.75}, distribution.Channels[0])
+	suite.EqualValues(0, distribution.Channels[2].MemSize)
+	suite.EqualValues(4000, distribution.TotalMem)
+	suite.Equal("dml_1", distribution.SealCandidate)
+}
+
+func (suite *HandlersSuite) TestGetGrowingSegmentCounts() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetGrowingSegmentCounts(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: segments.NewCollectionManager(),
+		Segment:    segmentManager,
+	}
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+
+	mockGrowing := func(channel string, rows int64) segments.Segment {
+		segment := segments.NewMockSegment(suite.T())
+		segment.EXPECT().Shard().Return(channel).Maybe()
+		segment.EXPECT().Type().Return(segments.SegmentTypeGrowing).Maybe()
+		segment.EXPECT().RowNum().Return(rows).Maybe()
+		return segment
+	}
+	growings := []segments.Segment{mockGrowing("dml_0", 100), mockGrowing("dml_1", 200), mockGrowing("dml_1", 100)}
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).RunAndReturn(func(filters ...segments.SegmentFilter) []segments.Segment {
+		return lo.Filter(growings, func(segment segments.Segment, _ int) bool {
+			for _, filter := range filters {
+				if !filter(segment) {
+					return false
+				}
+			}
+			return true
+		})
+	})
+	for i, channel := range []string{"dml_0", "dml_1", "dml_2"} {
+		sd := delegator.NewMockShardDelegator(suite.T())
+		sd.EXPECT().Collection().Return(int64(i))
+		suite.node.delegators.Insert(channel, sd)
+	}
+
+	// no limit by default
+	counts, err := suite.node.GetGrowingSegmentCounts(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(0, counts.MaxPerChannel)
+	suite.Equal([]string{"dml_1", "dml_0", "dml_2"}, lo.Map(counts.Channels, func(count *ChannelGrowingSegments, _ int) string {
+		return count.Channel
+	}))
+	suite.Equal(&ChannelGrowingSegments{Channel: "dml_1", CollectionID: 1, Segments: 2, Rows: 300}, counts.Channels[0])
+	suite.Equal(0, counts.Channels[2].Segments)
 
-	// Mock
-	factory *dependency.MockFactory
+	suite.params.Save(suite.params.QueryNodeCfg.GrowingSegmentMaxPerChannel.Key, "1")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.GrowingSegmentMaxPerChannel.Key)
+	counts, err = suite.node.GetGrowingSegmentCounts(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, counts.MaxPerChannel)
+	suite.True(counts.Channels[0].Exceeded)
+	suite.False(counts.Channels[1].Exceeded)
+	suite.False(counts.Channels[2].Exceeded)
 }
 
-func (suite *HandlersSuite) SetupSuite() {
-	suite.collectionID = 111
-	suite.collectionName = "test-collection"
-	suite.segmentID = 1
-	suite.channel = "test-channel"
-}
+func (suite *HandlersSuite) TestMmapPolicy() {
+	ctx := context.Background()
 
-func (suite *HandlersSuite) SetupTest() {
-	var err error
-	paramtable.Init()
-	suite.params = paramtable.Get()
-	suite.params.Save(suite.params.QueryNodeCfg.GCEnabled.Key, "false")
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.GetMmapPolicy(ctx, suite.collectionID)
+	suite.Error(err)
+	_, err = suite.node.SetMmapPolicy(ctx, suite.collectionID, segments.MmapPolicyDisabled)
+	suite.Error(err)
 
-	// mock factory
-	suite.factory = dependency.NewMockFactory(suite.T())
-	suite.chunkManagerFactory = storage.NewChunkManagerFactory("local", storage.RootPath("/tmp/milvus_test"))
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	collectionManager := segments.NewCollectionManager()
+	segmentManager := segments.NewMockSegmentManager(suite.T())
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segmentManager,
+	}
 
-	// new node
-	suite.node = NewQueryNode(context.Background(), suite.factory)
-	// init etcd
-	suite.etcd, err = etcd.GetEtcdClient(
-		suite.params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
-		suite.params.EtcdCfg.EtcdUseSSL.GetAsBool(),
-		suite.params.EtcdCfg.Endpoints.GetAsStrings(),
-		suite.params.EtcdCfg.EtcdTLSCert.GetValue(),
-		suite.params.EtcdCfg.EtcdTLSKey.GetValue(),
-		suite.params.EtcdCfg.EtcdTLSCACert.GetValue(),
-		suite.params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	// collection not loaded
+	_, err = suite.node.SetMmapPolicy(ctx, suite.collectionID, segments.MmapPolicyDisabled)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+	collection := collectionManager.Get(suite.collectionID)
+	suite.params.Save(suite.params.QueryNodeCfg.MmapDirPath.Key, suite.T().TempDir())
+	defer suite.params.Reset(suite.params.QueryNodeCfg.MmapDirPath.Key)
+	loader := segments.NewMockLoader(suite.T())
+	suite.node.loader = loader
+
+	newSegment := func(segmentID int64, version int64) *segments.LocalSegment {
+		segment, err := segments.NewSegment(collection, segmentID, 10, suite.collectionID, suite.channel, segments.SegmentTypeSealed, version, nil, nil)
+		suite.Require().NoError(err)
+		suite.T().Cleanup(segment.Release)
+		return segment
+	}
+	// segment 1 is mmapped, segment 2 is in memory
+	mmapped := newSegment(1, 1)
+	suite.Require().NoError(collection.SetMmapPolicy(segments.MmapPolicyDisabled))
+	inMemory := newSegment(2, 1)
+	suite.Require().NoError(collection.SetMmapPolicy(segments.MmapPolicyDefault))
+
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{mmapped, inMemory}).Times(3)
+	policy, err := suite.node.GetMmapPolicy(ctx, suite.collectionID)
 	suite.NoError(err)
-}
+	suite.Equal(segments.MmapPolicyDefault, policy.Policy)
+	suite.True(policy.Mmapped)
+	suite.Equal([]int64{2}, policy.PendingSegments)
 
-func (suite *HandlersSuite) TearDownTest() {
-	suite.etcd.Close()
-	os.RemoveAll("/tmp/milvus-test")
+	// failed to reload, the segment keeps pending
+	loader.EXPECT().Reload(mock.Anything, mmapped).Return(nil, merr.WrapErrServiceMemoryLimitExceeded(100, 10)).Once()
+	policy, err = suite.node.SetMmapPolicy(ctx, suite.collectionID, segments.MmapPolicyDisabled)
+	suite.NoError(err)
+	suite.Equal(segments.MmapPolicyDisabled, policy.Policy)
+	suite.False(policy.Mmapped)
+	suite.Empty(policy.ReloadedSegments)
+	suite.Equal([]int64{1}, policy.PendingSegments)
+
+	// the reloaded segment replaces the mmapped one
+	reloaded := newSegment(1, 2)
+	loader.EXPECT().Reload(mock.Anything, mmapped).Return(reloaded, nil).Once()
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{mmapped, inMemory}).Once()
+	segmentManager.EXPECT().GetBy(mock.Anything, mock.Anything).Return([]segments.Segment{reloaded, inMemory}).Once()
+	policy, err = suite.node.SetMmapPolicy(ctx, suite.collectionID, segments.MmapPolicyDisabled)
+	suite.NoError(err)
+	suite.Equal([]int64{1}, policy.ReloadedSegments)
+	suite.Zero(policy.EstimatedMemoryDelta)
+	suite.Empty(policy.PendingSegments)
+
+	_, err = suite.node.SetMmapPolicy(ctx, suite.collectionID, "unknown")
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
 }
 
-func (suite *HandlersSuite) TestLoadGrowingSegments() {
+func (suite *HandlersSuite) TestSearchChannelFacet() {
 	ctx := context.Background()
-	var err error
-	// mock
-	loadSegmetns := []int64{}
-	delegator := delegator.NewMockShardDelegator(suite.T())
-	delegator.EXPECT().LoadGrowing(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, infos []*querypb.SegmentLoadInfo, version int64) {
-		for _, info := range infos {
-			loadSegmetns = append(loadSegmetns, info.SegmentID)
-		}
-	}).Return(nil)
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
 
-	req := &querypb.WatchDmChannelsRequest{
-		Infos: []*datapb.VchannelInfo{
-			{
-				CollectionID:        suite.collectionID,
-				ChannelName:         suite.channel,
-				UnflushedSegmentIds: []int64{suite.segmentID},
+	const vectorFieldID, doubleFieldID, jsonFieldID, pkFieldID, dim = 107, 105, 106, 109, 128
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   vectorFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 4, RoundDecimal: -1},
 			},
 		},
-		SegmentInfos: make(map[int64]*datapb.SegmentInfo),
+	})
+	suite.Require().NoError(err)
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{make([]byte, dim*4)}}},
+	})
+	suite.Require().NoError(err)
+
+	pks := []int64{4, 3, 2, 1}
+	hits, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+		NumQueries: 1,
+		TopK:       4,
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+		Scores:     []float32{0.4, 0.3, 0.2, 0.1},
+		Topks:      []int64{4},
+	}, 1, 4, "IP")
+	suite.Require().NoError(err)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{}).Maybe()
+	sd.EXPECT().Search(mock.Anything, mock.Anything).Return([]*internalpb.SearchResults{hits}, nil).Once()
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.ElementsMatch([]int64{pkFieldID, doubleFieldID}, req.GetReq().GetOutputFieldsId())
+		return []*internalpb.RetrieveResults{{
+			Status: merr.Success(),
+			Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{{
+				Type:    schemapb.DataType_Double,
+				FieldId: doubleFieldID,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_DoubleData{DoubleData: &schemapb.DoubleArray{Data: []float64{5, 15, 25, 12}}},
+				}},
+			}},
+		}}, nil
+	}).Once()
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			MetricType:         "IP",
+			Nq:                 1,
+			Topk:               4,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			FilterStrategy:     filterStrategyANN,
+			Facet: &internalpb.FacetSpec{
+				FieldID:    doubleFieldID,
+				Boundaries: []float64{10, 20},
+				TopHits:    1,
+			},
+		},
+		DmlChannels: []string{suite.channel},
 	}
 
-	// unflushed segment not in segmentInfos, will skip
-	err = loadGrowingSegments(ctx, delegator, req)
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	buckets := result.GetFacetBuckets()
+	suite.Require().Len(buckets, 3)
+	suite.True(math.IsInf(buckets[0].GetLower(), -1))
+	suite.EqualValues(10, buckets[0].GetUpper())
+	suite.EqualValues(1, buckets[0].GetCount())
+	suite.Equal([]int64{4}, buckets[0].GetTopHitIds().GetIntId().GetData())
+	suite.EqualValues(10, buckets[1].GetLower())
+	suite.EqualValues(20, buckets[1].GetUpper())
+	suite.EqualValues(2, buckets[1].GetCount())
+	suite.Equal([]int64{3}, buckets[1].GetTopHitIds().GetIntId().GetData())
+	suite.Equal([]float32{0.3}, buckets[1].GetTopHitScores())
+	suite.EqualValues(20, buckets[2].GetLower())
+	suite.True(math.IsInf(buckets[2].GetUpper(), 1))
+	suite.EqualValues(1, buckets[2].GetCount())
+
+	// boundaries not ascending
+	req.Req.Facet.Boundaries = []float64{20, 10}
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// too many buckets
+	suite.params.Save(suite.params.QueryNodeCfg.FacetMaxBuckets.Key, "2")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.FacetMaxBuckets.Key)
+	req.Req.Facet.Boundaries = []float64{10, 20}
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// non-numeric facet field
+	req.Req.Facet = &internalpb.FacetSpec{FieldID: jsonFieldID, Boundaries: []float64{10}}
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestDrain() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	suite.Error(suite.node.SetDraining(ctx, true))
+	_, err := suite.node.GetDrainStatus(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	status, err := suite.node.GetDrainStatus(ctx)
 	suite.NoError(err)
-	suite.Equal(0, len(loadSegmetns))
+	suite.False(status.Draining)
+	suite.False(status.Idle)
 
-	// binlog was empty, will skip
-	req.SegmentInfos[suite.segmentID] = &datapb.SegmentInfo{
-		ID:           suite.segmentID,
-		CollectionID: suite.collectionID,
-		Binlogs:      make([]*datapb.FieldBinlog, 0),
+	suite.NoError(suite.node.SetDraining(ctx, true))
+	defer suite.node.SetDraining(ctx, false)
+
+	// new requests of channels rejected with retriable error
+	_, err = suite.node.searchChannel(ctx, &querypb.SearchRequest{Req: &internalpb.SearchRequest{Base: &commonpb.MsgBase{}}}, suite.channel)
+	suite.ErrorIs(err, merr.ErrServiceNotReady)
+	suite.True(merr.IsRetryableErr(err))
+	_, err = suite.node.queryChannel(ctx, &querypb.QueryRequest{Req: &internalpb.RetrieveRequest{Base: &commonpb.MsgBase{}}}, suite.channel)
+	suite.ErrorIs(err, merr.ErrServiceNotReady)
+
+	// in-flight search not finished
+	suite.node.inflightSearches.Inc()
+	status, err = suite.node.GetDrainStatus(ctx)
+	suite.NoError(err)
+	suite.True(status.Draining)
+	suite.EqualValues(1, status.InflightSearches)
+	suite.False(status.Idle)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	_, err = suite.node.WaitDrained(timeoutCtx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		suite.node.inflightSearches.Dec()
+	}()
+	status, err = suite.node.WaitDrained(ctx)
+	suite.NoError(err)
+	suite.True(status.Idle)
+	suite.Zero(status.InflightSearches)
+
+	// drain canceled
+	suite.NoError(suite.node.SetDraining(ctx, false))
+	_, err = suite.node.WaitDrained(ctx)
+	suite.ErrorIs(err, merr.ErrServiceUnavailable)
+}
+
+func (suite *HandlersSuite) TestTraceRowDelete() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
 	}
-	err = loadGrowingSegments(ctx, delegator, req)
+	pk := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{100}}}}
+
+	// collection not loaded
+	_, err := suite.node.TraceRowDelete(ctx, suite.collectionID, pk, 0)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType: querypb.LoadType_LoadCollection,
+	})
+
+	// more than one pk
+	_, err = suite.node.TraceRowDelete(ctx, suite.collectionID, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}}}, 0)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// no delegator
+	_, err = suite.node.TraceRowDelete(ctx, suite.collectionID, pk, 0)
+	suite.ErrorIs(err, merr.ErrChannelNotFound)
+
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().Collection().Return(suite.collectionID)
+	sd.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.QueryRequest) ([]*internalpb.RetrieveResults, error) {
+		suite.EqualValues(15, req.GetReq().GetMvccTimestamp())
+		return []*internalpb.RetrieveResults{{Status: merr.Success(), Ids: &schemapb.IDs{}}}, nil
+	})
+	sd.EXPECT().GetPKDeleteTrace(storage.NewInt64PrimaryKey(100)).Return(delegator.PKDeleteTrace{
+		CandidateSegments:  []int64{1},
+		DeleteTss:          []uint64{10, 20},
+		DeleteBufferSafeTs: 5,
+	})
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	traces, err := suite.node.TraceRowDelete(ctx, suite.collectionID, pk, 15)
 	suite.NoError(err)
-	suite.Equal(0, len(loadSegmetns))
+	suite.Require().Len(traces, 1)
+	suite.Equal(suite.channel, traces[0].Channel)
+	suite.Equal([]int64{1}, traces[0].CandidateSegments)
+	suite.False(traces[0].Visible)
+	suite.Equal([]uint64{10, 20}, traces[0].DeleteTss)
+	suite.EqualValues(5, traces[0].DeleteBufferSafeTs)
+	suite.EqualValues(10, traces[0].MaskingDeleteTs)
+}
 
-	// normal load
-	binlog := &datapb.FieldBinlog{}
-	req.SegmentInfos[suite.segmentID].Binlogs = append(req.SegmentInfos[suite.segmentID].Binlogs, binlog)
-	err = loadGrowingSegments(ctx, delegator, req)
+func (suite *HandlersSuite) TestSearchChannelPerPartitionTopK() {
+	ctx := context.Background()
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.delegators = typeutil.NewConcurrentMap[string, delegator.ShardDelegator]()
+	collectionManager := segments.NewCollectionManager()
+	suite.node.manager = &segments.Manager{
+		Collection: collectionManager,
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	collectionManager.PutOrRef(suite.collectionID, segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64), nil, &querypb.LoadMetaInfo{
+		LoadType:     querypb.LoadType_LoadPartition,
+		PartitionIDs: []int64{10, 20},
+	})
+
+	const vectorFieldID, dim = 107, 128
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				FieldId:   vectorFieldID,
+				QueryInfo: &planpb.QueryInfo{Topk: 2, RoundDecimal: -1},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{Tag: "$0", Type: commonpb.PlaceholderType_FloatVector, Values: [][]byte{make([]byte, dim*4)}}},
+	})
+	suite.Require().NoError(err)
+
+	partitionHits := map[int64]*schemapb.SearchResultData{
+		10: {
+			NumQueries: 1,
+			TopK:       2,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}}},
+			Scores:     []float32{0.5, 0.4},
+			Topks:      []int64{2},
+		},
+		20: {
+			NumQueries: 1,
+			TopK:       2,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{3}}}},
+			Scores:     []float32{0.9},
+			Topks:      []int64{1},
+		},
+	}
+	sd := delegator.NewMockShardDelegator(suite.T())
+	sd.EXPECT().GetSegmentInfo(true).Return([]delegator.SnapshotItem{{NodeID: 1, Segments: []delegator.SegmentEntry{{SegmentID: 1}}}}, []delegator.SegmentEntry{}).Maybe()
+	sd.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req *querypb.SearchRequest) ([]*internalpb.SearchResults, error) {
+		suite.Require().Len(req.GetReq().GetPartitionIDs(), 1)
+		result, err := segments.EncodeSearchResultData(partitionHits[req.GetReq().GetPartitionIDs()[0]], 1, 2, "IP")
+		return []*internalpb.SearchResults{result}, err
+	}).Times(2)
+	suite.node.delegators.Insert(suite.channel, sd)
+
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       suite.collectionID,
+			PartitionIDs:       []int64{20, 10},
+			MetricType:         "IP",
+			Nq:                 1,
+			Topk:               2,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: plan,
+			FilterStrategy:     filterStrategyANN,
+			PerPartitionTopk:   true,
+		},
+		DmlChannels: []string{suite.channel},
+	}
+
+	// hits grouped by partition in the requested order
+	result, err := suite.node.searchChannel(ctx, req, suite.channel)
+	suite.Require().NoError(err)
+	data, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{3, 1, 2}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]int64{3}, data[0].GetTopks())
+	suite.Require().Len(result.GetPartitionTopks(), 2)
+	suite.EqualValues(20, result.GetPartitionTopks()[0].GetPartitionID())
+	suite.Equal([]int64{1}, result.GetPartitionTopks()[0].GetTopks())
+	suite.EqualValues(10, result.GetPartitionTopks()[1].GetPartitionID())
+	suite.Equal([]int64{2}, result.GetPartitionTopks()[1].GetTopks())
+
+	// partition not loaded
+	req.Req.PartitionIDs = []int64{10, 30}
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrPartitionNotLoaded)
+
+	// no partition
+	req.Req.PartitionIDs = nil
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// too many hits
+	suite.params.Save(suite.params.QueryNodeCfg.PerPartitionTopKMaxHits.Key, "3")
+	defer suite.params.Reset(suite.params.QueryNodeCfg.PerPartitionTopKMaxHits.Key)
+	req.Req.PartitionIDs = []int64{10, 20}
+	_, err = suite.node.searchChannel(ctx, req, suite.channel)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestQueryTimeoutOverride() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	suite.Error(suite.node.SetQueryTimeoutOverride(ctx, suite.collectionID, time.Second))
+	_, err := suite.node.GetQueryTimeoutOverrides(ctx)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.NoError(suite.node.SetQueryTimeoutOverride(ctx, suite.collectionID, time.Second))
+	overrides, err := suite.node.GetQueryTimeoutOverrides(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(overrides, 1)
+	suite.Equal(suite.collectionID, overrides[0].CollectionID)
+	suite.Equal(time.Second, overrides[0].Timeout)
+	suite.Equal(time.Second, suite.node.queryTimeoutOverrides.timeout(suite.collectionID))
+
+	// exceeds the max
+	maxTimeout := suite.params.QueryNodeCfg.QueryTimeoutOverrideMax.GetAsDuration(time.Millisecond)
+	suite.ErrorIs(suite.node.SetQueryTimeoutOverride(ctx, suite.collectionID, maxTimeout+time.Second), merr.ErrParameterInvalid)
+
+	suite.NoError(suite.node.SetQueryTimeoutOverride(ctx, suite.collectionID, 0))
+	overrides, err = suite.node.GetQueryTimeoutOverrides(ctx)
 	suite.NoError(err)
-	suite.Equal(1, len(loadSegmetns))
+	suite.Empty(overrides)
+}
+
+func (suite *HandlersSuite) TestVerifyQueryChecksum() {
+	ctx := context.Background()
+	req := &querypb.QueryRequest{Req: &internalpb.RetrieveRequest{CollectionID: suite.collectionID}}
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.ComputeQueryChecksum(ctx, req, 0)
+	suite.Error(err)
+	_, err = suite.node.VerifyQueryChecksum(ctx, req, &QueryChecksum{Buckets: make([]uint64, 1)}, 10)
+	suite.Error(err)
+
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	_, err = suite.node.ComputeQueryChecksum(ctx, req, maxChecksumBuckets+1)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+	_, err = suite.node.VerifyQueryChecksum(ctx, req, &QueryChecksum{}, 10)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *HandlersSuite) TestCheckRowCountConsistency() {
+	ctx := context.Background()
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := suite.node.CheckRowCountConsistency(ctx, suite.collectionID, 0)
+	suite.Error(err)
+
+	// collection not loaded
+	suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	suite.node.manager = &segments.Manager{
+		Collection: segments.NewCollectionManager(),
+		Segment:    segments.NewMockSegmentManager(suite.T()),
+	}
+	_, err = suite.node.CheckRowCountConsistency(ctx, suite.collectionID, 0)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
 }
 
 func TestHandlersSuite(t *testing.T) {
@@ -303,6 +4153,74 @@ func (suite *OptimizeSearchParamSuite) TestOptimizeSearchParam() {
 		}, suite.delegator)
 		suite.Error(err)
 	})
+
+	suite.Run("hook_transient_error_recovered", func() {
+		mockHook := optimizers.NewMockQueryHook(suite.T())
+		mockHook.EXPECT().Run(mock.Anything).Run(func(params map[string]any) {
+			params[common.TopKKey] = int64(10)
+		}).Return(optimizers.NewTransientError(errors.New("mocked"))).Once()
+		mockHook.EXPECT().Run(mock.Anything).Run(func(params map[string]any) {
+			suite.Equal(int64(100), params[common.TopKKey])
+			params[common.TopKKey] = int64(50)
+			params[common.SearchParamKey] = `{"param": 2}`
+		}).Return(nil).Once()
+		suite.node.queryHook = mockHook
+		defer func() { suite.node.queryHook = nil }()
+
+		plan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{
+				VectorAnns: &planpb.VectorANNS{
+					QueryInfo: &planpb.QueryInfo{
+						Topk:         100,
+						SearchParams: `{"param": 1}`,
+					},
+				},
+			},
+		}
+		bs, err := proto.Marshal(plan)
+		suite.Require().NoError(err)
+
+		req, err := suite.node.optimizeSearchParams(ctx, &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				SerializedExprPlan: bs,
+			},
+		}, suite.delegator)
+		suite.NoError(err)
+		suite.verifyQueryInfo(req, 50, `{"param": 2}`)
+	})
+
+	suite.Run("hook_transient_error_exhausted", func() {
+		paramtable.Get().Save(paramtable.Get().QueryNodeCfg.QueryHookRetryAttempts.Key, "2")
+		defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.QueryHookRetryAttempts.Key)
+
+		mockHook := optimizers.NewMockQueryHook(suite.T())
+		mockHook.EXPECT().Run(mock.Anything).Run(func(params map[string]any) {
+			params[common.TopKKey] = int64(50)
+		}).Return(optimizers.NewTransientError(errors.New("mocked"))).Times(2)
+		suite.node.queryHook = mockHook
+		defer func() { suite.node.queryHook = nil }()
+
+		plan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{
+				VectorAnns: &planpb.VectorANNS{
+					QueryInfo: &planpb.QueryInfo{
+						Topk:         100,
+						SearchParams: `{"param": 1}`,
+					},
+				},
+			},
+		}
+		bs, err := proto.Marshal(plan)
+		suite.Require().NoError(err)
+
+		req, err := suite.node.optimizeSearchParams(ctx, &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				SerializedExprPlan: bs,
+			},
+		}, suite.delegator)
+		suite.NoError(err)
+		suite.verifyQueryInfo(req, 100, `{"param": 1}`)
+	})
 }
 
 func (suite *OptimizeSearchParamSuite) verifyQueryInfo(req *querypb.SearchRequest, topK int64, param string) {
@@ -317,6 +4235,102 @@ func (suite *OptimizeSearchParamSuite) verifyQueryInfo(req *querypb.SearchReques
 	suite.Equal(param, queryInfo.GetSearchParams())
 }
 
+func (suite *OptimizeSearchParamSuite) TestQueryHookCache() {
+	suite.Run("hook_not_enabled", func() {
+		_, err := suite.node.SnapshotQueryHookCache()
+		suite.Error(err)
+		suite.Error(suite.node.RestoreQueryHookCache([]byte("{}")))
+	})
+
+	suite.Run("not_supported", func() {
+		suite.node.queryHook = optimizers.NewMockQueryHook(suite.T())
+		defer func() { suite.node.queryHook = nil }()
+
+		_, err := suite.node.SnapshotQueryHookCache()
+		suite.ErrorIs(err, merr.ErrServiceUnavailable)
+		suite.ErrorIs(suite.node.RestoreQueryHookCache([]byte("{}")), merr.ErrServiceUnavailable)
+	})
+
+	suite.Run("snapshot_and_restore", func() {
+		snapshotter := optimizers.NewMockQueryHookCacheSnapshotter(suite.T())
+		snapshotter.EXPECT().CacheVersion().Return("v1")
+		snapshotter.EXPECT().SnapshotCache().Return([]byte("cache"), nil)
+		snapshotter.EXPECT().RestoreCache([]byte("cache")).Return(nil)
+		suite.node.queryHook = &snapshottableQueryHook{optimizers.NewMockQueryHook(suite.T()), snapshotter}
+		defer func() { suite.node.queryHook = nil }()
+
+		snapshot, err := suite.node.SnapshotQueryHookCache()
+		suite.Require().NoError(err)
+		suite.NoError(suite.node.RestoreQueryHookCache(snapshot))
+	})
+
+	suite.Run("incompatible_version", func() {
+		snapshotter := optimizers.NewMockQueryHookCacheSnapshotter(suite.T())
+		snapshotter.EXPECT().CacheVersion().Return("v2")
+		suite.node.queryHook = &snapshottableQueryHook{optimizers.NewMockQueryHook(suite.T()), snapshotter}
+		defer func() { suite.node.queryHook = nil }()
+
+		err := suite.node.RestoreQueryHookCache([]byte(`{"version":"v1","data":"Y2FjaGU="}`))
+		suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+		err = suite.node.RestoreQueryHookCache([]byte("malformed"))
+		suite.ErrorIs(err, merr.ErrParameterInvalid)
+	})
+
+	suite.Run("snapshot_failed", func() {
+		snapshotter := optimizers.NewMockQueryHookCacheSnapshotter(suite.T())
+		snapshotter.EXPECT().SnapshotCache().Return(nil, errors.New("mock error"))
+		suite.node.queryHook = &snapshottableQueryHook{optimizers.NewMockQueryHook(suite.T()), snapshotter}
+		defer func() { suite.node.queryHook = nil }()
+
+		_, err := suite.node.SnapshotQueryHookCache()
+		suite.Error(err)
+	})
+}
+
+// snapshottableQueryHook is a query hook supporting to snapshot its decision cache.
+type snapshottableQueryHook struct {
+	*optimizers.MockQueryHook
+	*optimizers.MockQueryHookCacheSnapshotter
+}
+
+func (suite *OptimizeSearchParamSuite) TestGetQueryHookInfo() {
+	suite.Run("hook_not_enabled", func() {
+		info := suite.node.GetQueryHookInfo()
+		suite.False(info.Enabled)
+		suite.Empty(info.Version)
+	})
+
+	suite.Run("hook_enabled", func() {
+		metadata := optimizers.QueryHookMetadata{Name: "autoindex", Version: "v2", ConfigHash: "abcd"}
+		provider := optimizers.NewMockQueryHookMetadataProvider(suite.T())
+		provider.EXPECT().Metadata().Return(metadata)
+		suite.node.queryHook = &describedQueryHook{optimizers.NewMockQueryHook(suite.T()), provider}
+		defer func() { suite.node.queryHook = nil }()
+
+		info := suite.node.GetQueryHookInfo()
+		suite.True(info.Enabled)
+		suite.Equal(paramtable.Get().QueryNodeCfg.SoPath.GetValue(), info.PluginPath)
+		suite.Equal(metadata, info.QueryHookMetadata)
+	})
+
+	suite.Run("metadata_not_provided", func() {
+		suite.node.queryHook = optimizers.NewMockQueryHook(suite.T())
+		defer func() { suite.node.queryHook = nil }()
+
+		info := suite.node.GetQueryHookInfo()
+		suite.True(info.Enabled)
+		suite.Equal("unknown", info.Name)
+		suite.Equal("unknown", info.Version)
+	})
+}
+
+// describedQueryHook is a query hook providing its metadata.
+type describedQueryHook struct {
+	*optimizers.MockQueryHook
+	*optimizers.MockQueryHookMetadataProvider
+}
+
 func TestOptimizeSearchParam(t *testing.T) {
 	suite.Run(t, new(OptimizeSearchParamSuite))
 }
@@ -0,0 +1,270 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	searchModeIndex      = "index"
+	searchModeBruteForce = "brute_force"
+)
+
+func validateExplainPK(req *querypb.SearchRequest) error {
+	if num := typeutil.GetSizeOfIDs(req.GetReq().GetExplainPk()); num != 1 {
+		return merr.WrapErrParameterInvalid(1, num, "exactly one pk could be explained")
+	}
+	return nil
+}
+
+// explainHit explains the hit of the explain pk for each query of the search on channel.
+// The row is retrieved with its raw vector to compute the exact score, and searched alone
+// to find the segment it's in and the score computed by the search engine, whether it's in topK or not.
+// The IVF cluster of the hit is not reported, since it's not exposed by segcore.
+func (node *QueryNode) explainHit(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string, resp *internalpb.SearchResults) ([]*internalpb.HitExplanation, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.String("channel", channel),
+	)
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+	vectorAnns, queries, err := searchQueries(req)
+	if err != nil {
+		return nil, err
+	}
+	pks := req.GetReq().GetExplainPk()
+	explanations := make([]*internalpb.HitExplanation, 0, len(queries))
+	for i := range queries {
+		explanations = append(explanations, &internalpb.HitExplanation{QueryIndex: int64(i), Rank: -1})
+	}
+
+	// the row with its raw vector, absent if the row is in another channel or deleted
+	plan, err := pkTermPlan(pkField, pks)
+	if err != nil {
+		return nil, err
+	}
+	pkTerm := plan.GetQuery().GetPredicates()
+	plan.OutputFieldIds = []int64{pkField.GetFieldID(), vectorAnns.GetFieldId()}
+	queryReq, err := filterQueryRequest(req, plan, channel)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := node.queryDelegatorForFilter(ctx, sd, queryReq, collection.Schema())
+	if err != nil {
+		log.Warn("failed to retrieve row to explain", zap.Error(err))
+		return nil, err
+	}
+	if typeutil.GetSizeOfIDs(rows.GetIds()) == 0 {
+		return explanations, nil
+	}
+	var vector []float32
+	if fieldData, ok := lo.Find(rows.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldId() == vectorAnns.GetFieldId()
+	}); ok {
+		vector = fieldData.GetVectors().GetFloatVector().GetData()
+	}
+
+	matchesFilter := true
+	if vectorAnns.GetPredicates() != nil {
+		filterPlan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_Query{
+				Query: &planpb.QueryPlanNode{
+					Predicates: &planpb.Expr{
+						Expr: &planpb.Expr_BinaryExpr{
+							BinaryExpr: &planpb.BinaryExpr{
+								Op:    planpb.BinaryExpr_LogicalAnd,
+								Left:  vectorAnns.GetPredicates(),
+								Right: pkTerm,
+							},
+						},
+					},
+				},
+			},
+			OutputFieldIds: []int64{pkField.GetFieldID()},
+		}
+		queryReq, err := filterQueryRequest(req, filterPlan, channel)
+		if err != nil {
+			return nil, err
+		}
+		matched, err := node.queryDelegatorForFilter(ctx, sd, queryReq, collection.Schema())
+		if err != nil {
+			log.Warn("failed to check whether row to explain matches filter", zap.Error(err))
+			return nil, err
+		}
+		matchesFilter = typeutil.GetSizeOfIDs(matched.GetIds()) > 0
+	}
+
+	// search the pk alone, regardless of the filter
+	searchPlan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &searchPlan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	searchPlan.GetVectorAnns().Predicates = pkTerm
+	searchPlan.GetVectorAnns().GetQueryInfo().Topk = 1
+	searchPlan.OutputFieldIds = nil
+	serializedExprPlan, err := proto.Marshal(&searchPlan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+	pkReq := proto.Clone(req).(*querypb.SearchRequest)
+	pkReq.Req.SerializedExprPlan = serializedExprPlan
+	pkReq.Req.Topk = 1
+	pkReq.Req.OutputFieldsId = nil
+	pkReq.Req.ReturnSegmentId = true
+	pkReq.Req.Explain = false
+	pkReq.Req.ExplainPk = nil
+	pkResult, _, err := node.searchDelegator(ctx, sd, pkReq)
+	if err != nil {
+		log.Warn("failed to search row to explain", zap.Error(err))
+		return nil, err
+	}
+	decoded, err := segments.DecodeSearchResults([]*internalpb.SearchResults{pkResult})
+	if err != nil {
+		return nil, err
+	}
+
+	metricType := req.GetReq().GetMetricType()
+	// scores of distance metrics are negated in search results
+	metricScore := func(score float32) float32 {
+		if metric.PositivelyRelated(metricType) {
+			return score
+		}
+		return -score
+	}
+	for i, query := range queries {
+		explanations[i].Exists = true
+		explanations[i].MatchesFilter = matchesFilter
+		if len(vector) == len(query) {
+			explanations[i].ExactScore = metricScore(bruteForceScore(metricType, query, vector))
+		}
+	}
+	if len(decoded) > 0 {
+		data := decoded[0]
+		var segmentIDs []int64
+		if field, ok := lo.Find(data.GetFieldsData(), func(field *schemapb.FieldData) bool {
+			return field.GetFieldId() == common.SegmentIDField
+		}); ok {
+			segmentIDs = field.GetScalars().GetLongData().GetData()
+		}
+		_, growing := sd.GetSegmentInfo(true)
+		growingIDs := typeutil.NewUniqueSet(lo.Map(growing, func(entry delegator.SegmentEntry, _ int) int64 { return entry.SegmentID })...)
+		var offset int64
+		for i, topk := range data.GetTopks() {
+			if topk > 0 && i < len(explanations) {
+				explanations[i].IndexScore = metricScore(data.GetScores()[offset])
+				if offset < int64(len(segmentIDs)) {
+					explanations[i].SegmentID = segmentIDs[offset]
+					explanations[i].SearchMode = node.segmentSearchMode(segmentIDs[offset], vectorAnns.GetFieldId(), growingIDs)
+				}
+			}
+			offset += topk
+		}
+	}
+	if err := fillHitExplanationRanks(resp, pks, explanations); err != nil {
+		return nil, err
+	}
+	return explanations, nil
+}
+
+// segmentSearchMode returns whether the vector field of segment is searched over index or brute forced,
+// empty if the segment is not served by the node.
+func (node *QueryNode) segmentSearchMode(segmentID int64, fieldID int64, growingIDs typeutil.UniqueSet) string {
+	if growingIDs.Contain(segmentID) {
+		return searchModeBruteForce
+	}
+	segment := node.manager.Segment.GetSealed(segmentID)
+	if segment == nil {
+		return ""
+	}
+	if segment.ExistIndex(fieldID) {
+		return searchModeIndex
+	}
+	return searchModeBruteForce
+}
+
+// fillHitExplanationRanks fills the rank of the hit of pk among the hits of each query of the search result.
+func fillHitExplanationRanks(result *internalpb.SearchResults, pks *schemapb.IDs, explanations []*internalpb.HitExplanation) error {
+	for _, explanation := range explanations {
+		explanation.InTopk = false
+		explanation.Rank = -1
+	}
+	if result.GetSlicedBlob() == nil || typeutil.GetSizeOfIDs(pks) == 0 {
+		return nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return err
+	}
+	pk := typeutil.GetPK(pks, 0)
+	var offset int64
+	for i, topk := range data.GetTopks() {
+		for j := offset; j < offset+topk && i < len(explanations); j++ {
+			if typeutil.GetPK(data.GetIds(), j) == pk {
+				explanations[i].InTopk = true
+				explanations[i].Rank = j - offset
+				break
+			}
+		}
+		offset += topk
+	}
+	return nil
+}
+
+// mergeHitExplanations merges the hit explanations of channels, the row of pk exists in one channel at most.
+// Channels without any segment return no explanation.
+func mergeHitExplanations(results []*internalpb.SearchResults, nq int64) []*internalpb.HitExplanation {
+	merged := make([]*internalpb.HitExplanation, 0, nq)
+	for i := int64(0); i < nq; i++ {
+		merged = append(merged, &internalpb.HitExplanation{QueryIndex: i, Rank: -1})
+	}
+	for _, result := range results {
+		for _, explanation := range result.GetHitExplanations() {
+			i := explanation.GetQueryIndex()
+			if i < 0 || i >= nq || merged[i].GetExists() {
+				continue
+			}
+			if explanation.GetExists() {
+				merged[i] = explanation
+			}
+		}
+	}
+	return merged
+}
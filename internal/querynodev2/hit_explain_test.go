@@ -0,0 +1,76 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+)
+
+func TestFillHitExplanationRanks(t *testing.T) {
+	result, err := segments.EncodeSearchResultData(&schemapb.SearchResultData{
+		NumQueries: 3,
+		TopK:       2,
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 5, 5, 2}}}},
+		Scores:     []float32{0.9, 0.8, 0.7, 0.6},
+		Topks:      []int64{2, 2, 0},
+	}, 3, 2, "IP")
+	require.NoError(t, err)
+	pk := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{5}}}}
+	explanations := []*internalpb.HitExplanation{{QueryIndex: 0}, {QueryIndex: 1}, {QueryIndex: 2, InTopk: true, Rank: 0}}
+
+	require.NoError(t, fillHitExplanationRanks(result, pk, explanations))
+	assert.True(t, explanations[0].GetInTopk())
+	assert.EqualValues(t, 1, explanations[0].GetRank())
+	assert.True(t, explanations[1].GetInTopk())
+	assert.EqualValues(t, 0, explanations[1].GetRank())
+	assert.False(t, explanations[2].GetInTopk())
+	assert.EqualValues(t, -1, explanations[2].GetRank())
+
+	// empty result
+	require.NoError(t, fillHitExplanationRanks(&internalpb.SearchResults{}, pk, explanations))
+	assert.False(t, explanations[0].GetInTopk())
+	assert.EqualValues(t, -1, explanations[0].GetRank())
+}
+
+func TestMergeHitExplanations(t *testing.T) {
+	results := []*internalpb.SearchResults{
+		{HitExplanations: []*internalpb.HitExplanation{{QueryIndex: 0, Rank: -1}, {QueryIndex: 1, Rank: -1}}},
+		{HitExplanations: []*internalpb.HitExplanation{{QueryIndex: 0, Exists: true, SegmentID: 11, Rank: 0}, {QueryIndex: 1, Exists: true, SegmentID: 11, Rank: -1}}},
+		// channel without segments
+		{},
+	}
+	merged := mergeHitExplanations(results, 2)
+	require.Len(t, merged, 2)
+	for i, explanation := range merged {
+		assert.EqualValues(t, i, explanation.GetQueryIndex())
+		assert.True(t, explanation.GetExists())
+		assert.EqualValues(t, 11, explanation.GetSegmentID())
+	}
+
+	// pk not found in any channel
+	merged = mergeHitExplanations(results[:1], 2)
+	require.Len(t, merged, 2)
+	assert.False(t, merged[0].GetExists())
+	assert.EqualValues(t, -1, merged[0].GetRank())
+}
@@ -0,0 +1,328 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/indexparamcheck"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	// default params of proposed index if not specified
+	defaultSimulationNList  = 128
+	defaultSimulationNProbe = 8
+	nprobeKey               = "nprobe"
+	efKey                   = "ef"
+)
+
+// IndexSimulationRequest is a proposed index config to simulate searching with on the loaded rows of collection.
+type IndexSimulationRequest struct {
+	CollectionID int64
+	FieldID      int64
+	MetricType   string
+	// IndexParams and SearchParams of the proposed index, e.g. index_type, nlist and nprobe
+	IndexParams  map[string]string
+	SearchParams map[string]string
+	TopK         int64
+	Queries      [][]float32
+}
+
+// IndexSimulation is the result of searching the sampled rows with the proposed index config, no index is built.
+type IndexSimulation struct {
+	IndexType   string
+	SampledRows int64
+	// GroundTruth is the exact topK pks of each query over the sampled rows
+	GroundTruth []*schemapb.IDs
+	// SimulatedRecall is the mean recall of the proposed params against the ground truth,
+	// -1 if the index type could not be simulated without building it, see Reason.
+	SimulatedRecall float64
+	// EstimatedScanRows is the mean number of distances computed per query by the proposed index on the sampled rows
+	EstimatedScanRows  int64
+	EstimatedScanRatio float64
+	Reason             string
+}
+
+// SimulateIndexSearch estimates the recall and scan cost of a proposed index config before building it.
+// The ground truth is computed by brute force over rows sampled through the delegators of collection,
+// IVF indexes are simulated by partitioning the sampled rows, graph indexes are only estimated in scan cost.
+// It's only available when vector dump enabled since it reads raw embeddings.
+func (node *QueryNode) SimulateIndexSearch(ctx context.Context, req *IndexSimulationRequest) (*IndexSimulation, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.CollectionID),
+		zap.Int64("fieldID", req.FieldID),
+	)
+
+	if !paramtable.Get().QueryNodeCfg.EnableVectorDump.GetAsBool() {
+		return nil, merr.WrapErrPrivilegeNotPermitted("vector dump is disabled, set %s to enable it", paramtable.Get().QueryNodeCfg.EnableVectorDump.Key)
+	}
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(req.CollectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(req.CollectionID)
+	}
+	field, ok := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == req.FieldID
+	})
+	if !ok {
+		return nil, merr.WrapErrFieldNotFound(req.FieldID)
+	}
+	if err := validateIndexSimulation(req, field); err != nil {
+		return nil, err
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]string, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() == req.CollectionID {
+			channels = append(channels, channel)
+		}
+		return true
+	})
+	if len(channels) == 0 {
+		return nil, merr.WrapErrChannelNotFound(fmt.Sprintf("delegators of collection %d", req.CollectionID))
+	}
+
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}},
+			},
+		},
+		OutputFieldIds: []int64{pkField.GetFieldID(), req.FieldID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := node.Query(ctx, &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			CollectionID:       req.CollectionID,
+			SerializedExprPlan: plan,
+			OutputFieldsId:     []int64{pkField.GetFieldID(), req.FieldID},
+			MvccTimestamp:      typeutil.MaxTimestamp,
+			Limit:              paramtable.Get().QueryNodeCfg.IndexSimulationMaxRows.GetAsInt64(),
+		},
+		DmlChannels: channels,
+		Scope:       querypb.DataScope_All,
+	})
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		log.Warn("failed to sample rows to simulate index", zap.Error(err))
+		return nil, err
+	}
+
+	vectors := make([][]float32, 0)
+	if fieldData, ok := lo.Find(resp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldId() == req.FieldID
+	}); ok {
+		data := fieldData.GetVectors().GetFloatVector().GetData()
+		dim := int(fieldData.GetVectors().GetDim())
+		for i := 0; dim > 0 && (i+1)*dim <= len(data); i++ {
+			vectors = append(vectors, data[i*dim:(i+1)*dim])
+		}
+	}
+
+	simulation := simulateIndex(req, vectors, resp.GetIds())
+	log.Info("index search simulated",
+		zap.String("indexType", simulation.IndexType),
+		zap.Int64("sampledRows", simulation.SampledRows),
+		zap.Float64("simulatedRecall", simulation.SimulatedRecall),
+		zap.Int64("estimatedScanRows", simulation.EstimatedScanRows),
+	)
+	return simulation, nil
+}
+
+func validateIndexSimulation(req *IndexSimulationRequest, field *schemapb.FieldSchema) error {
+	if field.GetDataType() != schemapb.DataType_FloatVector {
+		return merr.WrapErrParameterInvalidMsg("field %s is not a float vector field", field.GetName())
+	}
+	metricType := strings.ToUpper(req.MetricType)
+	if metricType != metric.L2 && metricType != metric.IP && metricType != metric.COSINE {
+		return merr.WrapErrParameterInvalid("L2, IP or COSINE", req.MetricType, "invalid metric type")
+	}
+	if req.TopK <= 0 {
+		return merr.WrapErrParameterInvalidRange(1, math.MaxInt64, req.TopK, "invalid topK")
+	}
+	if len(req.Queries) == 0 {
+		return merr.WrapErrParameterInvalidMsg("no query vector provided")
+	}
+	dim, err := typeutil.GetDim(field)
+	if err != nil {
+		return err
+	}
+	for _, query := range req.Queries {
+		if int64(len(query)) != dim {
+			return merr.WrapErrParameterInvalid(dim, len(query), "dimension of query vector mismatch")
+		}
+	}
+	return nil
+}
+
+// simulateIndex searches the sampled vectors with the proposed index config, and compares with the exact results.
+func simulateIndex(req *IndexSimulationRequest, vectors [][]float32, pks *schemapb.IDs) *IndexSimulation {
+	indexType := strings.ToUpper(req.IndexParams[common.IndexTypeKey])
+	simulation := &IndexSimulation{
+		IndexType:   indexType,
+		SampledRows: int64(len(vectors)),
+		GroundTruth: make([]*schemapb.IDs, 0, len(req.Queries)),
+	}
+	all := lo.Range(len(vectors))
+	groundTruth := make([][]int, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		hits := exactTopK(req.MetricType, query, vectors, all, req.TopK)
+		groundTruth = append(groundTruth, hits)
+		ids := &schemapb.IDs{}
+		for _, offset := range hits {
+			typeutil.AppendPKs(ids, typeutil.GetPK(pks, int64(offset)))
+		}
+		simulation.GroundTruth = append(simulation.GroundTruth, ids)
+	}
+	if len(vectors) == 0 {
+		simulation.SimulatedRecall = -1
+		simulation.Reason = "no row sampled"
+		return simulation
+	}
+
+	switch indexparamcheck.IndexType(indexType) {
+	case indexparamcheck.IndexFaissIDMap:
+		simulation.SimulatedRecall = 1
+		simulation.EstimatedScanRows = int64(len(vectors))
+	case indexparamcheck.IndexFaissIvfFlat, indexparamcheck.IndexFaissIvfSQ8, indexparamcheck.IndexFaissIvfPQ, indexparamcheck.IndexScaNN:
+		nlist := intParam(req.IndexParams, indexparamcheck.NLIST, defaultSimulationNList)
+		nprobe := intParam(req.SearchParams, nprobeKey, defaultSimulationNProbe)
+		recall, scanRows := simulateIVF(req, vectors, groundTruth, nlist, nprobe)
+		simulation.SimulatedRecall = recall
+		simulation.EstimatedScanRows = scanRows
+		if indexType != string(indexparamcheck.IndexFaissIvfFlat) {
+			simulation.Reason = "quantization error not simulated, recall is an upper bound"
+		}
+	case indexparamcheck.IndexHNSW:
+		// a search visits about ef candidates per layer of the graph
+		ef := intParam(req.SearchParams, efKey, int(req.TopK))
+		if int64(ef) < req.TopK {
+			ef = int(req.TopK)
+		}
+		scanRows := int64(float64(ef) * math.Max(1, math.Log2(float64(len(vectors)))))
+		if scanRows > int64(len(vectors)) {
+			scanRows = int64(len(vectors))
+		}
+		simulation.SimulatedRecall = -1
+		simulation.EstimatedScanRows = scanRows
+		simulation.Reason = "graph index could not be simulated without building it"
+	default:
+		simulation.SimulatedRecall = -1
+		simulation.EstimatedScanRows = int64(len(vectors))
+		simulation.Reason = fmt.Sprintf("index type %q could not be simulated", indexType)
+	}
+	simulation.EstimatedScanRatio = float64(simulation.EstimatedScanRows) / float64(len(vectors))
+	return simulation
+}
+
+// simulateIVF partitions the sampled vectors into nlist lists around evenly picked centroids,
+// and searches the nprobe nearest lists of each query, returns the mean recall and distances computed per query.
+func simulateIVF(req *IndexSimulationRequest, vectors [][]float32, groundTruth [][]int, nlist int, nprobe int) (float64, int64) {
+	if nlist > len(vectors) {
+		nlist = len(vectors)
+	}
+	if nprobe > nlist {
+		nprobe = nlist
+	}
+	centroids := make([][]float32, nlist)
+	for i := range centroids {
+		centroids[i] = vectors[i*len(vectors)/nlist]
+	}
+	allCentroids := lo.Range(nlist)
+	lists := make([][]int, nlist)
+	for offset, vector := range vectors {
+		nearest := exactTopK(req.MetricType, vector, centroids, allCentroids, 1)[0]
+		lists[nearest] = append(lists[nearest], offset)
+	}
+
+	var recall float64
+	var scanRows int64
+	for i, query := range req.Queries {
+		candidates := make([]int, 0)
+		for _, list := range exactTopK(req.MetricType, query, centroids, allCentroids, int64(nprobe)) {
+			candidates = append(candidates, lists[list]...)
+		}
+		scanRows += int64(nlist + len(candidates))
+		found := typeutil.NewSet(exactTopK(req.MetricType, query, vectors, candidates, req.TopK)...)
+		if len(groundTruth[i]) == 0 {
+			recall++
+			continue
+		}
+		hit := lo.CountBy(groundTruth[i], func(offset int) bool { return found.Contain(offset) })
+		recall += float64(hit) / float64(len(groundTruth[i]))
+	}
+	return recall / float64(len(req.Queries)), scanRows / int64(len(req.Queries))
+}
+
+// exactTopK returns the offsets of topK candidate vectors nearest to the query, ordered by exact score.
+func exactTopK(metricType string, query []float32, vectors [][]float32, candidates []int, topK int64) []int {
+	scores := make(map[int]float32, len(candidates))
+	for _, offset := range candidates {
+		scores[offset] = bruteForceScore(metricType, query, vectors[offset])
+	}
+	sorted := make([]int, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return scores[sorted[i]] > scores[sorted[j]]
+	})
+	if int64(len(sorted)) > topK {
+		sorted = sorted[:topK]
+	}
+	return sorted
+}
+
+// intParam returns the positive integer param, or the default value if not set or invalid.
+func intParam(params map[string]string, key string, defaultValue int) int {
+	value, err := strconv.Atoi(params[key])
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func genSimulationRows(num int) ([][]float32, *schemapb.IDs) {
+	vectors := make([][]float32, num)
+	pks := make([]int64, num)
+	for i := range vectors {
+		vectors[i] = []float32{float32(i), 0}
+		pks[i] = int64(i + 1000)
+	}
+	return vectors, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}}
+}
+
+func TestSimulateIndex(t *testing.T) {
+	vectors, pks := genSimulationRows(100)
+	req := &IndexSimulationRequest{
+		MetricType: "L2",
+		TopK:       3,
+		Queries:    [][]float32{{10.2, 0}, {90, 0}},
+	}
+
+	req.IndexParams = map[string]string{common.IndexTypeKey: "FLAT"}
+	simulation := simulateIndex(req, vectors, pks)
+	assert.EqualValues(t, 100, simulation.SampledRows)
+	assert.Equal(t, []int64{1010, 1011, 1009}, simulation.GroundTruth[0].GetIntId().GetData())
+	assert.Equal(t, []int64{1090, 1089, 1091}, simulation.GroundTruth[1].GetIntId().GetData())
+	assert.EqualValues(t, 1, simulation.SimulatedRecall)
+	assert.EqualValues(t, 100, simulation.EstimatedScanRows)
+	assert.EqualValues(t, 1, simulation.EstimatedScanRatio)
+
+	// probing all lists finds the exact results
+	req.IndexParams = map[string]string{common.IndexTypeKey: "IVF_FLAT", "nlist": "10"}
+	req.SearchParams = map[string]string{"nprobe": "10"}
+	simulation = simulateIndex(req, vectors, pks)
+	assert.EqualValues(t, 1, simulation.SimulatedRecall)
+	assert.EqualValues(t, 110, simulation.EstimatedScanRows)
+	assert.Empty(t, simulation.Reason)
+
+	// probing the nearest list only scans part of rows
+	req.SearchParams = map[string]string{"nprobe": "1"}
+	simulation = simulateIndex(req, vectors, pks)
+	assert.Greater(t, simulation.SimulatedRecall, 0.0)
+	assert.LessOrEqual(t, simulation.SimulatedRecall, 1.0)
+	assert.Less(t, simulation.EstimatedScanRatio, 0.5)
+
+	req.IndexParams = map[string]string{common.IndexTypeKey: "IVF_PQ", "nlist": "10"}
+	simulation = simulateIndex(req, vectors, pks)
+	assert.NotEmpty(t, simulation.Reason)
+
+	req.IndexParams = map[string]string{common.IndexTypeKey: "HNSW"}
+	req.SearchParams = map[string]string{"ef": "8"}
+	simulation = simulateIndex(req, vectors, pks)
+	assert.EqualValues(t, -1, simulation.SimulatedRecall)
+	assert.Greater(t, simulation.EstimatedScanRows, int64(8))
+	assert.LessOrEqual(t, simulation.EstimatedScanRows, int64(100))
+	assert.NotEmpty(t, simulation.Reason)
+
+	req.IndexParams = map[string]string{common.IndexTypeKey: "UNKNOWN"}
+	simulation = simulateIndex(req, vectors, pks)
+	assert.EqualValues(t, -1, simulation.SimulatedRecall)
+	assert.NotEmpty(t, simulation.Reason)
+
+	// nothing sampled
+	simulation = simulateIndex(req, nil, &schemapb.IDs{})
+	assert.EqualValues(t, -1, simulation.SimulatedRecall)
+	assert.Len(t, simulation.GroundTruth, 2)
+}
+
+func TestValidateIndexSimulation(t *testing.T) {
+	schema := segments.GenTestCollectionSchema("simulation", schemapb.DataType_Int64)
+	fields := make(map[int64]*schemapb.FieldSchema)
+	for _, field := range schema.GetFields() {
+		fields[field.GetFieldID()] = field
+	}
+	req := &IndexSimulationRequest{
+		MetricType: "IP",
+		TopK:       10,
+		Queries:    [][]float32{make([]float32, 128)},
+	}
+	assert.NoError(t, validateIndexSimulation(req, fields[107]))
+	assert.ErrorIs(t, validateIndexSimulation(req, fields[108]), merr.ErrParameterInvalid)
+
+	req.Queries = [][]float32{make([]float32, 64)}
+	assert.ErrorIs(t, validateIndexSimulation(req, fields[107]), merr.ErrParameterInvalid)
+	req.Queries = nil
+	assert.ErrorIs(t, validateIndexSimulation(req, fields[107]), merr.ErrParameterInvalid)
+	req.Queries = [][]float32{make([]float32, 128)}
+
+	req.TopK = 0
+	assert.ErrorIs(t, validateIndexSimulation(req, fields[107]), merr.ErrParameterInvalid)
+	req.TopK = 10
+
+	req.MetricType = "HAMMING"
+	assert.ErrorIs(t, validateIndexSimulation(req, fields[107]), merr.ErrParameterInvalid)
+}
+
+func TestSimulateIndexSearchDisabled(t *testing.T) {
+	paramtable.Init()
+	node := &QueryNode{}
+	_, err := node.SimulateIndexSearch(context.Background(), &IndexSimulationRequest{})
+	assert.ErrorIs(t, err, merr.ErrPrivilegeNotPermitted)
+}
@@ -0,0 +1,149 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// LoadDuration is the wall-clock duration of a load operation.
+type LoadDuration struct {
+	CollectionID int64
+	Kind         LoadKind
+	SegmentNum   int
+	StartTime    time.Time
+	Duration     time.Duration
+	Canceled     bool
+}
+
+// LoadPhaseDuration summarizes the durations of the recent load operations of a kind,
+// e.g. index loads of a collection.
+type LoadPhaseDuration struct {
+	Kind       LoadKind
+	Count      int
+	SegmentNum int
+	Total      time.Duration
+	Max        time.Duration
+}
+
+// CollectionLoadDurations is the durations of recent load operations of a collection.
+type CollectionLoadDurations struct {
+	CollectionID int64
+	// Phases are ordered by kind, canceled loads are excluded
+	Phases []LoadPhaseDuration
+	// Recent loads from the latest
+	Recent []LoadDuration
+}
+
+// loadDurationLog keeps the durations of the latest load operations of each collection,
+// bounded by queryNode.loadDurationLog.sizePerCollection.
+type loadDurationLog struct {
+	mu        sync.Mutex
+	durations map[int64][]LoadDuration
+}
+
+func newLoadDurationLog() *loadDurationLog {
+	return &loadDurationLog{
+		durations: make(map[int64][]LoadDuration),
+	}
+}
+
+func (l *loadDurationLog) record(load *inflightLoad) {
+	size := paramtable.Get().QueryNodeCfg.LoadDurationLogSize.GetAsInt()
+	if size <= 0 {
+		return
+	}
+	duration := load.tr.ElapseSpan()
+	entry := LoadDuration{
+		CollectionID: load.collectionID,
+		Kind:         load.kind,
+		SegmentNum:   len(load.segmentIDs),
+		StartTime:    time.Now().Add(-duration),
+		Duration:     duration,
+		Canceled:     load.canceled.Load(),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	durations := append(l.durations[load.collectionID], entry)
+	if len(durations) > size {
+		durations = append([]LoadDuration(nil), durations[len(durations)-size:]...)
+	}
+	l.durations[load.collectionID] = durations
+}
+
+func (l *loadDurationLog) list() []*CollectionLoadDurations {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]*CollectionLoadDurations, 0, len(l.durations))
+	for collectionID, durations := range l.durations {
+		phases := make(map[LoadKind]*LoadPhaseDuration)
+		recent := make([]LoadDuration, 0, len(durations))
+		for i := len(durations) - 1; i >= 0; i-- {
+			duration := durations[i]
+			recent = append(recent, duration)
+			if duration.Canceled {
+				continue
+			}
+			phase, ok := phases[duration.Kind]
+			if !ok {
+				phase = &LoadPhaseDuration{Kind: duration.Kind}
+				phases[duration.Kind] = phase
+			}
+			phase.Count++
+			phase.SegmentNum += duration.SegmentNum
+			phase.Total += duration.Duration
+			if duration.Duration > phase.Max {
+				phase.Max = duration.Duration
+			}
+		}
+		collectionDurations := &CollectionLoadDurations{
+			CollectionID: collectionID,
+			Phases:       make([]LoadPhaseDuration, 0, len(phases)),
+			Recent:       recent,
+		}
+		for _, phase := range phases {
+			collectionDurations.Phases = append(collectionDurations.Phases, *phase)
+		}
+		sort.Slice(collectionDurations.Phases, func(i, j int) bool {
+			return collectionDurations.Phases[i].Kind < collectionDurations.Phases[j].Kind
+		})
+		result = append(result, collectionDurations)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CollectionID < result[j].CollectionID
+	})
+	return result
+}
+
+// GetLoadDurations returns the durations of the recent load operations of each collection,
+// summarized by phase, i.e. sealed, index, delta and growing loads, for modeling how long rehydrating a node takes.
+func (node *QueryNode) GetLoadDurations(ctx context.Context) ([]*CollectionLoadDurations, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.loads.durations.list(), nil
+}
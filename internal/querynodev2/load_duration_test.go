@@ -0,0 +1,75 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestLoadDurationLog(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.QueryNodeCfg.LoadDurationLogSize.Key, "3")
+	defer params.Reset(params.QueryNodeCfg.LoadDurationLogSize.Key)
+
+	ctx := context.Background()
+	registry := newLoadRegistry()
+	for _, kind := range []LoadKind{LoadKindGrowing, LoadKindSealed, LoadKindIndex, LoadKindIndex} {
+		_, load := registry.register(ctx, 100, kind, []int64{1, 2})
+		registry.finish(load, nil)
+	}
+	_, load := registry.register(ctx, 101, LoadKindDelta, []int64{3})
+	registry.finish(load, nil)
+	_, canceled := registry.register(ctx, 101, LoadKindSealed, []int64{4})
+	canceled.canceled.Store(true)
+	registry.finish(canceled, []int64{4})
+
+	durations := registry.durations.list()
+	require.Len(t, durations, 2)
+
+	// bounded per collection, from the latest
+	assert.EqualValues(t, 100, durations[0].CollectionID)
+	assert.Equal(t, []LoadKind{LoadKindIndex, LoadKindIndex, LoadKindSealed}, lo.Map(durations[0].Recent, func(duration LoadDuration, _ int) LoadKind {
+		return duration.Kind
+	}))
+	require.Len(t, durations[0].Phases, 2)
+	assert.Equal(t, LoadKindIndex, durations[0].Phases[0].Kind)
+	assert.Equal(t, 2, durations[0].Phases[0].Count)
+	assert.Equal(t, 4, durations[0].Phases[0].SegmentNum)
+	assert.LessOrEqual(t, durations[0].Phases[0].Max, durations[0].Phases[0].Total)
+	assert.Equal(t, LoadKindSealed, durations[0].Phases[1].Kind)
+
+	// canceled loads are not summarized
+	assert.EqualValues(t, 101, durations[1].CollectionID)
+	require.Len(t, durations[1].Recent, 2)
+	assert.True(t, durations[1].Recent[0].Canceled)
+	require.Len(t, durations[1].Phases, 1)
+	assert.Equal(t, LoadKindDelta, durations[1].Phases[0].Kind)
+
+	// disabled
+	params.Save(params.QueryNodeCfg.LoadDurationLogSize.Key, "0")
+	_, load = registry.register(ctx, 102, LoadKindSealed, nil)
+	registry.finish(load, nil)
+	assert.Len(t, registry.durations.list(), 2)
+}
@@ -0,0 +1,115 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/pkg/util/timerecord"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// LoadKind is the kind of load operation.
+type LoadKind string
+
+const (
+	LoadKindSealed  LoadKind = "sealed"
+	LoadKindGrowing LoadKind = "growing"
+	LoadKindIndex   LoadKind = "index"
+	LoadKindDelta   LoadKind = "delta"
+)
+
+// inflightLoad is a load operation in progress, which could be canceled.
+type inflightLoad struct {
+	id           int64
+	collectionID int64
+	kind         LoadKind
+	segmentIDs   []int64
+	cancel       context.CancelFunc
+	canceled     *atomic.Bool
+	done         chan struct{}
+	tr           *timerecord.TimeRecorder
+	// segments released after the load canceled, set before done closed
+	rolledBack []int64
+}
+
+// loadRegistry keeps the cancelable contexts of in-flight load operations, keyed by load id.
+type loadRegistry struct {
+	idAllocator *atomic.Int64
+	loads       *typeutil.ConcurrentMap[int64, *inflightLoad]
+	durations   *loadDurationLog
+}
+
+func newLoadRegistry() *loadRegistry {
+	return &loadRegistry{
+		idAllocator: atomic.NewInt64(0),
+		loads:       typeutil.NewConcurrentMap[int64, *inflightLoad](),
+		durations:   newLoadDurationLog(),
+	}
+}
+
+// register starts tracking a load operation, the load shall run with the returned context,
+// and call finish after done.
+func (r *loadRegistry) register(ctx context.Context, collectionID int64, kind LoadKind, segmentIDs []int64) (context.Context, *inflightLoad) {
+	ctx, cancel := context.WithCancel(ctx)
+	load := &inflightLoad{
+		id:           r.idAllocator.Inc(),
+		collectionID: collectionID,
+		kind:         kind,
+		segmentIDs:   segmentIDs,
+		cancel:       cancel,
+		canceled:     atomic.NewBool(false),
+		done:         make(chan struct{}),
+		tr:           timerecord.NewTimeRecorder("load"),
+	}
+	r.loads.Insert(load.id, load)
+	return ctx, load
+}
+
+// finish stops tracking the load operation and records its duration,
+// rolledBack are the segments released due to cancellation.
+func (r *loadRegistry) finish(load *inflightLoad, rolledBack []int64) {
+	r.loads.Remove(load.id)
+	r.durations.record(load)
+	load.rolledBack = rolledBack
+	load.cancel()
+	close(load.done)
+}
+
+// cancel cancels all in-flight load operations of collection, and waits them done.
+func (r *loadRegistry) cancel(ctx context.Context, collectionID int64) ([]*inflightLoad, error) {
+	canceled := make([]*inflightLoad, 0)
+	r.loads.Range(func(_ int64, load *inflightLoad) bool {
+		if load.collectionID == collectionID {
+			load.canceled.Store(true)
+			load.cancel()
+			canceled = append(canceled, load)
+		}
+		return true
+	})
+
+	for _, load := range canceled {
+		select {
+		case <-load.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return canceled, nil
+}
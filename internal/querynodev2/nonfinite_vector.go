@@ -0,0 +1,148 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// NonFiniteVectorReject fails the search with the indexes of queries containing NaN or Inf
+	NonFiniteVectorReject = "reject"
+	// NonFiniteVectorDrop returns empty result for the queries containing NaN or Inf
+	NonFiniteVectorDrop = "drop"
+	// NonFiniteVectorZero replaces the NaN and Inf components of query vectors with 0
+	NonFiniteVectorZero = "zero"
+
+	// maxReportedNonFiniteQueries bounds the query indexes reported in error
+	maxReportedNonFiniteQueries = 16
+)
+
+// nonFiniteComponents returns the offsets of NaN or Inf components of query vector, float or float16 vector only.
+func nonFiniteComponents(vector []byte, typ commonpb.PlaceholderType) []int {
+	var offsets []int
+	switch typ {
+	case commonpb.PlaceholderType_FloatVector:
+		for i := 0; i+4 <= len(vector); i += 4 {
+			v := float64(math.Float32frombits(binary.LittleEndian.Uint32(vector[i:])))
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				offsets = append(offsets, i)
+			}
+		}
+	case commonpb.PlaceholderType_Float16Vector:
+		for i := 0; i+2 <= len(vector); i += 2 {
+			// all exponent bits set for NaN and Inf
+			if binary.LittleEndian.Uint16(vector[i:])&0x7c00 == 0x7c00 {
+				offsets = append(offsets, i)
+			}
+		}
+	}
+	return offsets
+}
+
+// sanitizeQueryVectors checks the placeholder vectors of search request for NaN or Inf,
+// and handles the bad queries by paramtable queryNode.nonFiniteVectorPolicy:
+// reject fails with the bad query indexes, zero replaces the bad components with 0,
+// drop returns the bad query indexes, whose vectors are replaced by a finite one of the request
+// for the search to run, and the hits of them shall be cleared after searched.
+// All the queries are returned as dropped if none of them is finite.
+func sanitizeQueryVectors(req *querypb.SearchRequest) (*querypb.SearchRequest, []int, error) {
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetReq().GetPlaceholderGroup(), placeholderGroup); err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("valid placeholder group", "no unmarshalable one", err.Error())
+	}
+
+	bad := make([]int, 0)
+	badSet := make(map[int]struct{})
+	for _, placeholder := range placeholderGroup.GetPlaceholders() {
+		for i, value := range placeholder.GetValues() {
+			if _, ok := badSet[i]; !ok && len(nonFiniteComponents(value, placeholder.GetType())) > 0 {
+				badSet[i] = struct{}{}
+				bad = append(bad, i)
+			}
+		}
+	}
+	if len(bad) == 0 {
+		return req, nil, nil
+	}
+
+	nq := req.GetReq().GetNq()
+	policy := paramtable.Get().QueryNodeCfg.NonFiniteVectorPolicy.GetValue()
+	switch policy {
+	case NonFiniteVectorZero:
+		for _, placeholder := range placeholderGroup.GetPlaceholders() {
+			for i, value := range placeholder.GetValues() {
+				offsets := nonFiniteComponents(value, placeholder.GetType())
+				if len(offsets) == 0 {
+					continue
+				}
+				size := 4
+				if placeholder.GetType() == commonpb.PlaceholderType_Float16Vector {
+					size = 2
+				}
+				zeroed := make([]byte, len(value))
+				copy(zeroed, value)
+				for _, offset := range offsets {
+					copy(zeroed[offset:offset+size], make([]byte, size))
+				}
+				placeholder.Values[i] = zeroed
+			}
+		}
+	case NonFiniteVectorDrop:
+		if int64(len(bad)) == nq {
+			return req, bad, nil
+		}
+		for _, placeholder := range placeholderGroup.GetPlaceholders() {
+			var substitute []byte
+			for i, value := range placeholder.GetValues() {
+				if _, ok := badSet[i]; !ok {
+					substitute = value
+					break
+				}
+			}
+			for _, i := range bad {
+				placeholder.Values[i] = substitute
+			}
+		}
+	default:
+		reported := bad
+		if len(reported) > maxReportedNonFiniteQueries {
+			reported = reported[:maxReportedNonFiniteQueries]
+		}
+		return nil, nil, merr.WrapErrParameterInvalidMsg("query vectors contain NaN or Inf, %d of nq %d are bad, indexes %v",
+			len(bad), nq, reported)
+	}
+
+	serializedGroup, err := proto.Marshal(placeholderGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+	sanitized := proto.Clone(req).(*querypb.SearchRequest)
+	sanitized.Req.PlaceholderGroup = serializedGroup
+	if policy == NonFiniteVectorZero {
+		return sanitized, nil, nil
+	}
+	return sanitized, bad, nil
+}
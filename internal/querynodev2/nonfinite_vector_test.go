@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func floatVectorBytes(values ...float32) []byte {
+	bytes := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(bytes[4*i:], math.Float32bits(v))
+	}
+	return bytes
+}
+
+func TestSanitizeQueryVectors(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	defer params.Reset(params.QueryNodeCfg.NonFiniteVectorPolicy.Key)
+
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	genRequest := func(vectors ...[]byte) *querypb.SearchRequest {
+		group, err := proto.Marshal(&commonpb.PlaceholderGroup{Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    "$0",
+			Type:   commonpb.PlaceholderType_FloatVector,
+			Values: vectors,
+		}}})
+		require.NoError(t, err)
+		return &querypb.SearchRequest{Req: &internalpb.SearchRequest{Nq: int64(len(vectors)), PlaceholderGroup: group}}
+	}
+	vectorsOf := func(req *querypb.SearchRequest) [][]byte {
+		group := &commonpb.PlaceholderGroup{}
+		require.NoError(t, proto.Unmarshal(req.GetReq().GetPlaceholderGroup(), group))
+		return group.GetPlaceholders()[0].GetValues()
+	}
+	req := genRequest(floatVectorBytes(1, 2), floatVectorBytes(nan, 1), floatVectorBytes(3, 4), floatVectorBytes(1, inf))
+
+	// all finite
+	finite := genRequest(floatVectorBytes(1, 2))
+	sanitized, dropped, err := sanitizeQueryVectors(finite)
+	assert.NoError(t, err)
+	assert.Same(t, finite, sanitized)
+	assert.Empty(t, dropped)
+
+	// rejected by default
+	_, _, err = sanitizeQueryVectors(req)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	assert.Contains(t, err.Error(), "[1 3]")
+
+	params.Save(params.QueryNodeCfg.NonFiniteVectorPolicy.Key, NonFiniteVectorZero)
+	sanitized, dropped, err = sanitizeQueryVectors(req)
+	assert.NoError(t, err)
+	assert.Empty(t, dropped)
+	assert.Equal(t, [][]byte{
+		floatVectorBytes(1, 2), floatVectorBytes(0, 1), floatVectorBytes(3, 4), floatVectorBytes(1, 0),
+	}, vectorsOf(sanitized))
+	// the request is not modified
+	assert.Equal(t, floatVectorBytes(nan, 1), vectorsOf(req)[1])
+
+	params.Save(params.QueryNodeCfg.NonFiniteVectorPolicy.Key, NonFiniteVectorDrop)
+	sanitized, dropped, err = sanitizeQueryVectors(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, dropped)
+	// bad vectors are substituted by the first finite one
+	assert.Equal(t, [][]byte{
+		floatVectorBytes(1, 2), floatVectorBytes(1, 2), floatVectorBytes(3, 4), floatVectorBytes(1, 2),
+	}, vectorsOf(sanitized))
+
+	// no finite query
+	_, dropped, err = sanitizeQueryVectors(genRequest(floatVectorBytes(nan, 1)))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0}, dropped)
+}
+
+func TestNonFiniteComponents(t *testing.T) {
+	assert.Equal(t, []int{4}, nonFiniteComponents(floatVectorBytes(1, float32(math.Inf(-1))), commonpb.PlaceholderType_FloatVector))
+	// float16 1.0, NaN and +Inf
+	float16s := []byte{0x00, 0x3c, 0x01, 0x7e, 0x00, 0x7c}
+	assert.Equal(t, []int{2, 4}, nonFiniteComponents(float16s, commonpb.PlaceholderType_Float16Vector))
+	assert.Empty(t, nonFiniteComponents([]byte{0xff}, commonpb.PlaceholderType_BinaryVector))
+}
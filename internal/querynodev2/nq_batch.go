@@ -0,0 +1,134 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// splitSearchRequestByNQ splits search request into requests of at most maxNQ queries, in nq order.
+func splitSearchRequestByNQ(req *querypb.SearchRequest, maxNQ int64) ([]*querypb.SearchRequest, error) {
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetReq().GetPlaceholderGroup(), placeholderGroup); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid placeholder group", "malformed placeholder group", err.Error())
+	}
+	nq := req.GetReq().GetNq()
+	for _, placeholder := range placeholderGroup.GetPlaceholders() {
+		if int64(len(placeholder.GetValues())) != nq {
+			return nil, merr.WrapErrParameterInvalid(nq, int64(len(placeholder.GetValues())), "nq mismatches the placeholder group")
+		}
+	}
+
+	batches := make([]*querypb.SearchRequest, 0, (nq+maxNQ-1)/maxNQ)
+	for start := int64(0); start < nq; start += maxNQ {
+		end := start + maxNQ
+		if end > nq {
+			end = nq
+		}
+		batchGroup := &commonpb.PlaceholderGroup{
+			Placeholders: lo.Map(placeholderGroup.GetPlaceholders(), func(placeholder *commonpb.PlaceholderValue, _ int) *commonpb.PlaceholderValue {
+				return &commonpb.PlaceholderValue{
+					Tag:    placeholder.GetTag(),
+					Type:   placeholder.GetType(),
+					Values: placeholder.GetValues()[start:end],
+				}
+			}),
+		}
+		serializedGroup, err := proto.Marshal(batchGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := proto.Clone(req).(*querypb.SearchRequest)
+		batch.Req.Nq = end - start
+		batch.Req.PlaceholderGroup = serializedGroup
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// concatSearchResults concatenates the reduced results of nq batches, in the same order as batches.
+func concatSearchResults(results []*internalpb.SearchResults, nq int64, topk int64, metricType string) (*internalpb.SearchResults, error) {
+	datas, err := segments.DecodeSearchResults(results)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &schemapb.SearchResultData{
+		NumQueries: nq,
+		TopK:       topk,
+		FieldsData: make([]*schemapb.FieldData, 0),
+		Scores:     make([]float32, 0),
+		Ids:        &schemapb.IDs{},
+		Topks:      make([]int64, 0),
+	}
+	for i, result := range results {
+		if result.GetSlicedBlob() == nil {
+			// batch without any hit
+			merged.Topks = append(merged.Topks, make([]int64, result.GetNumQueries())...)
+			continue
+		}
+		data := datas[0]
+		datas = datas[1:]
+		if int64(len(data.GetTopks())) != result.GetNumQueries() {
+			return nil, merr.WrapErrServiceInternal(fmt.Sprintf("topks of batch %d mismatches its nq", i))
+		}
+		merged.Topks = append(merged.Topks, data.GetTopks()...)
+		merged.Scores = append(merged.Scores, data.GetScores()...)
+		for idx := 0; idx < typeutil.GetSizeOfIDs(data.GetIds()); idx++ {
+			typeutil.AppendIDs(merged.Ids, data.GetIds(), idx)
+		}
+		if len(merged.FieldsData) == 0 {
+			merged.FieldsData = data.GetFieldsData()
+		} else if err := typeutil.MergeFieldData(merged.FieldsData, data.GetFieldsData()); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := segments.EncodeSearchResultData(merged, nq, topk, metricType)
+	if err != nil {
+		return nil, err
+	}
+	resp.Topks = merged.GetTopks()
+	resp.CostAggregation = &internalpb.CostAggregation{}
+	for _, result := range results {
+		truncated := make([]bool, result.GetNumQueries())
+		copy(truncated, result.GetTruncated())
+		resp.Truncated = append(resp.Truncated, truncated...)
+		resp.IsPartial = resp.IsPartial || result.GetIsPartial()
+		resp.TimedOutNodes = append(resp.TimedOutNodes, result.GetTimedOutNodes()...)
+		resp.CostAggregation.ResponseTime += result.GetCostAggregation().GetResponseTime()
+		resp.CostAggregation.ServiceTime += result.GetCostAggregation().GetServiceTime()
+		resp.CostAggregation.TotalNQ += result.GetCostAggregation().GetTotalNQ()
+	}
+	resp.TimedOutNodes = lo.Uniq(resp.TimedOutNodes)
+	// all batches search the same segments
+	resp.ScannedSegments = results[0].GetScannedSegments()
+	resp.ScannedRows = results[0].GetScannedRows()
+	return resp, nil
+}
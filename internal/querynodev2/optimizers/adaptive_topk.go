@@ -0,0 +1,119 @@
+package optimizers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// slow request ratio of a window above which topK cap is tightened
+	adaptiveTopKHighWatermark = 0.5
+	// slow request ratio of a window below which topK cap is relaxed
+	adaptiveTopKLowWatermark = 0.1
+	// windows with fewer samples are not evaluated
+	adaptiveTopKMinSamples = 10
+	// cap ratio will not go below this value
+	adaptiveTopKMinRatio = 1.0 / 64
+)
+
+// AdaptiveTopK lowers the effective topK of collections whose search latency keeps exceeding the slo.
+// The latency is aggregated per window, the cap is tightened when most requests of a window are slow,
+// and relaxed only when few requests are slow, so it won't oscillate with single slow requests.
+type AdaptiveTopK struct {
+	mu     sync.Mutex
+	states map[int64]*adaptiveTopKState
+}
+
+type adaptiveTopKState struct {
+	windowStart time.Time
+	total       int
+	slow        int
+	// ratio of effective topK to requested topK, 1 means not capped
+	ratio float64
+}
+
+func NewAdaptiveTopK() *AdaptiveTopK {
+	return &AdaptiveTopK{
+		states: make(map[int64]*adaptiveTopKState),
+	}
+}
+
+// Observe records a search latency of collection.
+func (a *AdaptiveTopK) Observe(collectionID int64, latency time.Duration) {
+	if !paramtable.Get().QueryNodeCfg.AdaptiveTopKEnabled.GetAsBool() {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.observe(collectionID, latency, time.Now())
+}
+
+func (a *AdaptiveTopK) observe(collectionID int64, latency time.Duration, now time.Time) {
+	state, ok := a.states[collectionID]
+	if !ok {
+		state = &adaptiveTopKState{windowStart: now, ratio: 1}
+		a.states[collectionID] = state
+	}
+
+	window := paramtable.Get().QueryNodeCfg.AdaptiveTopKWindow.GetAsDuration(time.Second)
+	if now.Sub(state.windowStart) >= window {
+		state.evaluate()
+		state.windowStart = now
+		state.total = 0
+		state.slow = 0
+	}
+
+	threshold := paramtable.Get().QueryNodeCfg.AdaptiveTopKLatencyThreshold.GetAsDuration(time.Millisecond)
+	state.total++
+	if latency > threshold {
+		state.slow++
+	}
+}
+
+func (s *adaptiveTopKState) evaluate() {
+	if s.total < adaptiveTopKMinSamples {
+		return
+	}
+
+	slowRatio := float64(s.slow) / float64(s.total)
+	switch {
+	case slowRatio >= adaptiveTopKHighWatermark:
+		s.ratio /= 2
+		if s.ratio < adaptiveTopKMinRatio {
+			s.ratio = adaptiveTopKMinRatio
+		}
+	case slowRatio <= adaptiveTopKLowWatermark:
+		s.ratio *= 2
+		if s.ratio > 1 {
+			s.ratio = 1
+		}
+	}
+}
+
+// Cap returns the effective topK of collection and whether it's capped.
+func (a *AdaptiveTopK) Cap(collectionID int64, topk int64) (int64, bool) {
+	if !paramtable.Get().QueryNodeCfg.AdaptiveTopKEnabled.GetAsBool() {
+		return topk, false
+	}
+
+	a.mu.Lock()
+	state, ok := a.states[collectionID]
+	ratio := 1.0
+	if ok {
+		ratio = state.ratio
+	}
+	a.mu.Unlock()
+
+	floor := paramtable.Get().QueryNodeCfg.AdaptiveTopKFloor.GetAsInt64()
+	if ratio >= 1 || topk <= floor {
+		return topk, false
+	}
+	effective := int64(float64(topk) * ratio)
+	if effective < floor {
+		effective = floor
+	}
+	return effective, effective < topk
+}
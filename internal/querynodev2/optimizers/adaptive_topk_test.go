@@ -0,0 +1,111 @@
+package optimizers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type AdaptiveTopKSuite struct {
+	suite.Suite
+
+	adaptive *AdaptiveTopK
+}
+
+func (suite *AdaptiveTopKSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *AdaptiveTopKSuite) SetupTest() {
+	params := paramtable.Get()
+	params.Save(params.QueryNodeCfg.AdaptiveTopKEnabled.Key, "true")
+	params.Save(params.QueryNodeCfg.AdaptiveTopKLatencyThreshold.Key, "100")
+	params.Save(params.QueryNodeCfg.AdaptiveTopKWindow.Key, "10")
+	params.Save(params.QueryNodeCfg.AdaptiveTopKFloor.Key, "10")
+	suite.adaptive = NewAdaptiveTopK()
+}
+
+func (suite *AdaptiveTopKSuite) TearDownTest() {
+	params := paramtable.Get()
+	params.Reset(params.QueryNodeCfg.AdaptiveTopKEnabled.Key)
+	params.Reset(params.QueryNodeCfg.AdaptiveTopKLatencyThreshold.Key)
+	params.Reset(params.QueryNodeCfg.AdaptiveTopKWindow.Key)
+	params.Reset(params.QueryNodeCfg.AdaptiveTopKFloor.Key)
+}
+
+// fillWindow observes a window of requests with given slow ratio, and starts next window.
+func (suite *AdaptiveTopKSuite) fillWindow(start time.Time, slow int, fast int) time.Time {
+	for i := 0; i < slow; i++ {
+		suite.adaptive.observe(1, time.Second, start)
+	}
+	for i := 0; i < fast; i++ {
+		suite.adaptive.observe(1, time.Millisecond, start)
+	}
+	next := start.Add(10 * time.Second)
+	// trigger window evaluation
+	suite.adaptive.observe(1, time.Millisecond, next)
+	return next
+}
+
+func (suite *AdaptiveTopKSuite) TestCapAndRecover() {
+	now := time.Now()
+	topk, capped := suite.adaptive.Cap(1, 100)
+	suite.EqualValues(100, topk)
+	suite.False(capped)
+
+	// sustained slow
+	now = suite.fillWindow(now, 8, 2)
+	topk, capped = suite.adaptive.Cap(1, 100)
+	suite.EqualValues(50, topk)
+	suite.True(capped)
+
+	// other collection not affected
+	topk, capped = suite.adaptive.Cap(2, 100)
+	suite.EqualValues(100, topk)
+	suite.False(capped)
+
+	// bounded by floor
+	now = suite.fillWindow(now, 10, 0)
+	now = suite.fillWindow(now, 10, 0)
+	now = suite.fillWindow(now, 10, 0)
+	topk, capped = suite.adaptive.Cap(1, 100)
+	suite.EqualValues(10, topk)
+	suite.True(capped)
+	topk, capped = suite.adaptive.Cap(1, 5)
+	suite.EqualValues(5, topk)
+	suite.False(capped)
+
+	// between watermarks, cap kept
+	now = suite.fillWindow(now, 3, 7)
+	topk, _ = suite.adaptive.Cap(1, 1000)
+	suite.EqualValues(62, topk)
+
+	// too few samples, cap kept
+	now = suite.fillWindow(now, 0, 3)
+	topk, _ = suite.adaptive.Cap(1, 1000)
+	suite.EqualValues(62, topk)
+
+	// recover
+	for i := 0; i < 4; i++ {
+		now = suite.fillWindow(now, 0, 10)
+	}
+	topk, capped = suite.adaptive.Cap(1, 100)
+	suite.EqualValues(100, topk)
+	suite.False(capped)
+}
+
+func (suite *AdaptiveTopKSuite) TestDisabled() {
+	suite.fillWindow(time.Now(), 10, 0)
+
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.AdaptiveTopKEnabled.Key, "false")
+	topk, capped := suite.adaptive.Cap(1, 100)
+	suite.EqualValues(100, topk)
+	suite.False(capped)
+}
+
+func TestAdaptiveTopK(t *testing.T) {
+	suite.Run(t, new(AdaptiveTopKSuite))
+}
@@ -0,0 +1,173 @@
+package optimizers
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// bucket bounds grow by 10% from 1us, so percentiles are accurate within 10%
+	latencyHistogramGrowth = 1.1
+	// buckets cover latency up to about 1 hour, larger ones fall into the last bucket
+	latencyHistogramBucketNum = 232
+	// the sliding window is divided into slices, expired slices are dropped as a whole
+	latencyHistogramSliceNum = 6
+)
+
+// LatencyPercentiles is the search latency percentiles of collection over the sliding window.
+type LatencyPercentiles struct {
+	CollectionID int64
+	Count        int64
+	P50          time.Duration
+	P95          time.Duration
+	P99          time.Duration
+}
+
+// LatencyHistograms maintains the search latency distributions of collections over a sliding window.
+// Each collection keeps fixed size bucketed histograms, one per slice of the window, so memory is fixed per collection.
+type LatencyHistograms struct {
+	mu         sync.Mutex
+	histograms map[int64]*latencyHistogram
+}
+
+type latencyHistogram struct {
+	buckets [latencyHistogramSliceNum][latencyHistogramBucketNum]int64
+	// start time of the current slice
+	sliceStart time.Time
+	current    int
+}
+
+func NewLatencyHistograms() *LatencyHistograms {
+	return &LatencyHistograms{
+		histograms: make(map[int64]*latencyHistogram),
+	}
+}
+
+// Observe records a search latency of collection.
+func (h *LatencyHistograms) Observe(collectionID int64, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.observe(collectionID, latency, time.Now())
+}
+
+func (h *LatencyHistograms) observe(collectionID int64, latency time.Duration, now time.Time) {
+	histogram, ok := h.histograms[collectionID]
+	if !ok {
+		histogram = &latencyHistogram{sliceStart: now}
+		h.histograms[collectionID] = histogram
+	}
+	histogram.rotate(now)
+	histogram.buckets[histogram.current][latencyBucket(latency)]++
+}
+
+// Percentiles returns the latency percentiles of collection, false if nothing observed in the window.
+func (h *LatencyHistograms) Percentiles(collectionID int64) (*LatencyPercentiles, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.percentiles(collectionID, time.Now())
+}
+
+func (h *LatencyHistograms) percentiles(collectionID int64, now time.Time) (*LatencyPercentiles, bool) {
+	histogram, ok := h.histograms[collectionID]
+	if !ok {
+		return nil, false
+	}
+	histogram.rotate(now)
+
+	var merged [latencyHistogramBucketNum]int64
+	var count int64
+	for _, slice := range histogram.buckets {
+		for i, n := range slice {
+			merged[i] += n
+			count += n
+		}
+	}
+	if count == 0 {
+		return nil, false
+	}
+	return &LatencyPercentiles{
+		CollectionID: collectionID,
+		Count:        count,
+		P50:          percentileOf(merged, count, 0.5),
+		P95:          percentileOf(merged, count, 0.95),
+		P99:          percentileOf(merged, count, 0.99),
+	}, true
+}
+
+// List returns the latency percentiles of all collections observed in the window, ordered by collection id.
+func (h *LatencyHistograms) List() []*LatencyPercentiles {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	result := make([]*LatencyPercentiles, 0, len(h.histograms))
+	for collectionID := range h.histograms {
+		if percentiles, ok := h.percentiles(collectionID, now); ok {
+			result = append(result, percentiles)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CollectionID < result[j].CollectionID
+	})
+	return result
+}
+
+// Remove drops the histogram of collection, e.g. after it's released.
+func (h *LatencyHistograms) Remove(collectionID int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.histograms, collectionID)
+}
+
+// rotate moves the current slice forward to now, clearing the slices expired.
+func (h *latencyHistogram) rotate(now time.Time) {
+	window := paramtable.Get().QueryNodeCfg.LatencyHistogramWindow.GetAsDuration(time.Second)
+	sliceDuration := window / latencyHistogramSliceNum
+	if sliceDuration <= 0 {
+		sliceDuration = time.Second
+	}
+	steps := int64(now.Sub(h.sliceStart) / sliceDuration)
+	if steps <= 0 {
+		return
+	}
+	if steps >= latencyHistogramSliceNum {
+		h.buckets = [latencyHistogramSliceNum][latencyHistogramBucketNum]int64{}
+	} else {
+		for i := int64(0); i < steps; i++ {
+			h.current = (h.current + 1) % latencyHistogramSliceNum
+			h.buckets[h.current] = [latencyHistogramBucketNum]int64{}
+		}
+	}
+	h.sliceStart = h.sliceStart.Add(time.Duration(steps) * sliceDuration)
+}
+
+// latencyBucket returns the bucket of latency, bucket i covers [growth^i, growth^(i+1)) microseconds.
+func latencyBucket(latency time.Duration) int {
+	us := latency.Microseconds()
+	if us <= 1 {
+		return 0
+	}
+	bucket := int(math.Log(float64(us)) / math.Log(latencyHistogramGrowth))
+	if bucket >= latencyHistogramBucketNum {
+		return latencyHistogramBucketNum - 1
+	}
+	return bucket
+}
+
+// percentileOf returns the geometric middle of the bucket where the q-th percentile falls.
+func percentileOf(buckets [latencyHistogramBucketNum]int64, count int64, q float64) time.Duration {
+	rank := int64(math.Ceil(q * float64(count)))
+	var cumulative int64
+	for i, n := range buckets {
+		cumulative += n
+		if cumulative >= rank {
+			us := math.Pow(latencyHistogramGrowth, float64(i)+0.5)
+			return time.Duration(us * float64(time.Microsecond))
+		}
+	}
+	return 0
+}
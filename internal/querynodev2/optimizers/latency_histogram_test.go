@@ -0,0 +1,110 @@
+package optimizers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type LatencyHistogramsSuite struct {
+	suite.Suite
+
+	histograms *LatencyHistograms
+}
+
+func (suite *LatencyHistogramsSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *LatencyHistogramsSuite) SetupTest() {
+	params := paramtable.Get()
+	params.Save(params.QueryNodeCfg.LatencyHistogramWindow.Key, "60")
+	suite.histograms = NewLatencyHistograms()
+}
+
+func (suite *LatencyHistogramsSuite) TearDownTest() {
+	params := paramtable.Get()
+	params.Reset(params.QueryNodeCfg.LatencyHistogramWindow.Key)
+}
+
+// assertClose asserts the percentile is within the bucket resolution of expected.
+func (suite *LatencyHistogramsSuite) assertClose(expected time.Duration, actual time.Duration) {
+	suite.InEpsilon(float64(expected), float64(actual), latencyHistogramGrowth-1, "expected %v, actual %v", expected, actual)
+}
+
+func (suite *LatencyHistogramsSuite) TestPercentiles() {
+	now := time.Now()
+	_, ok := suite.histograms.percentiles(1, now)
+	suite.False(ok)
+
+	for i := 1; i <= 100; i++ {
+		suite.histograms.observe(1, time.Duration(i)*time.Millisecond, now)
+	}
+	percentiles, ok := suite.histograms.percentiles(1, now)
+	suite.True(ok)
+	suite.EqualValues(1, percentiles.CollectionID)
+	suite.EqualValues(100, percentiles.Count)
+	suite.assertClose(50*time.Millisecond, percentiles.P50)
+	suite.assertClose(95*time.Millisecond, percentiles.P95)
+	suite.assertClose(99*time.Millisecond, percentiles.P99)
+
+	// other collections are not affected
+	_, ok = suite.histograms.percentiles(2, now)
+	suite.False(ok)
+}
+
+func (suite *LatencyHistogramsSuite) TestSlidingWindow() {
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		suite.histograms.observe(1, time.Second, now)
+	}
+	// half window later, the old observations are still counted
+	now = now.Add(30 * time.Second)
+	for i := 0; i < 10; i++ {
+		suite.histograms.observe(1, time.Millisecond, now)
+	}
+	percentiles, ok := suite.histograms.percentiles(1, now)
+	suite.True(ok)
+	suite.EqualValues(20, percentiles.Count)
+	suite.assertClose(time.Second, percentiles.P99)
+
+	// the slow ones expire after the window
+	now = now.Add(40 * time.Second)
+	percentiles, ok = suite.histograms.percentiles(1, now)
+	suite.True(ok)
+	suite.EqualValues(10, percentiles.Count)
+	suite.assertClose(time.Millisecond, percentiles.P99)
+
+	// all expired
+	now = now.Add(time.Hour)
+	_, ok = suite.histograms.percentiles(1, now)
+	suite.False(ok)
+}
+
+func (suite *LatencyHistogramsSuite) TestListAndRemove() {
+	suite.histograms.Observe(2, time.Millisecond)
+	suite.histograms.Observe(1, time.Millisecond)
+	list := suite.histograms.List()
+	suite.Len(list, 2)
+	suite.EqualValues(1, list[0].CollectionID)
+	suite.EqualValues(2, list[1].CollectionID)
+
+	suite.histograms.Remove(1)
+	list = suite.histograms.List()
+	suite.Len(list, 1)
+	suite.EqualValues(2, list[0].CollectionID)
+}
+
+func (suite *LatencyHistogramsSuite) TestLatencyBucket() {
+	suite.Equal(0, latencyBucket(0))
+	suite.Equal(0, latencyBucket(time.Microsecond))
+	suite.Less(latencyBucket(time.Millisecond), latencyBucket(2*time.Millisecond))
+	suite.Equal(latencyHistogramBucketNum-1, latencyBucket(24*time.Hour))
+}
+
+func TestLatencyHistograms(t *testing.T) {
+	suite.Run(t, new(LatencyHistogramsSuite))
+}
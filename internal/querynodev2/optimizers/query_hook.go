@@ -1,5 +1,9 @@
 package optimizers
 
+import (
+	"github.com/cockroachdb/errors"
+)
+
 // QueryHook is the interface for search/query parameter optimizer.
 type QueryHook interface {
 	Run(map[string]any) error
@@ -7,3 +11,51 @@ type QueryHook interface {
 	InitTuningConfig(map[string]string) error
 	DeleteTuningConfig(string) error
 }
+
+// QueryHookCacheSnapshotter is optionally implemented by the hook supporting to snapshot its decision cache,
+// so that the warm cache survives node restarts.
+type QueryHookCacheSnapshotter interface {
+	// CacheVersion returns the format version of the decision cache.
+	CacheVersion() string
+	// SnapshotCache exports the decision cache of the hook.
+	SnapshotCache() ([]byte, error)
+	// RestoreCache imports the decision cache exported by SnapshotCache.
+	RestoreCache([]byte) error
+}
+
+// QueryHookMetadataProvider is optionally implemented by the hook reporting its identity.
+type QueryHookMetadataProvider interface {
+	// Metadata returns the identity of the hook and the hash of the config it currently runs with.
+	Metadata() QueryHookMetadata
+}
+
+// QueryHookMetadata identifies the running hook, e.g. to verify the hook picked up by nodes during rollout.
+type QueryHookMetadata struct {
+	Name       string
+	Version    string
+	ConfigHash string
+}
+
+// TransientError is returned by the hook for errors which may be resolved by retrying,
+// e.g. the backing config store is temporarily unavailable.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return "transient query hook error: " + e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+func NewTransientError(err error) error {
+	return &TransientError{Err: err}
+}
+
+// IsTransientError returns whether err is marked as transient by the hook.
+func IsTransientError(err error) bool {
+	var transient *TransientError
+	return errors.As(err, &transient)
+}
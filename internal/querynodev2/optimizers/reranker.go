@@ -0,0 +1,17 @@
+package optimizers
+
+import (
+	"context"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+)
+
+// Reranker is the interface for post-reduce reranking plugin,
+// which could reorder or trim the reduced search results before returned by shard leader.
+type Reranker interface {
+	Init(string) error
+	// Rerank returns the reranked result of search request, the nq of result shall not be changed,
+	// and the fields data shall be kept aligned with ids.
+	Rerank(ctx context.Context, req *internalpb.SearchRequest, result *schemapb.SearchResultData) (*schemapb.SearchResultData, error)
+}
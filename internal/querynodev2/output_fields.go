@@ -0,0 +1,168 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// isValidOutputField returns whether the output field could be retrieved from the collection,
+// the dynamic field is valid only if the schema enables it.
+func isValidOutputField(schema *schemapb.CollectionSchema, fieldID int64) bool {
+	if fieldID == common.RowIDField || fieldID == common.TimeStampField {
+		return true
+	}
+	field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == fieldID
+	})
+	if !ok {
+		return false
+	}
+	return !field.GetIsDynamic() || schema.GetEnableDynamicField()
+}
+
+// applyLenientOutputFields drops the output fields not in schema from the retrieve request and its plan
+// if lenient output fields requested, returns the dropped ones.
+// The request is cloned if any field dropped, since it's shared among channels.
+func applyLenientOutputFields(req *querypb.QueryRequest, schema *schemapb.CollectionSchema) (*querypb.QueryRequest, []int64, error) {
+	if !req.GetReq().GetLenientOutputFields() {
+		return req, nil, nil
+	}
+	valid := make([]int64, 0, len(req.GetReq().GetOutputFieldsId()))
+	dropped := make([]int64, 0)
+	for _, fieldID := range req.GetReq().GetOutputFieldsId() {
+		if isValidOutputField(schema, fieldID) {
+			valid = append(valid, fieldID)
+		} else {
+			dropped = append(dropped, fieldID)
+		}
+	}
+	if len(dropped) == 0 {
+		return req, nil, nil
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("valid serialized retrieve plan", "no unmarshalable one", err.Error())
+	}
+	plan.OutputFieldIds = lo.Filter(plan.GetOutputFieldIds(), func(fieldID int64, _ int) bool {
+		return isValidOutputField(schema, fieldID)
+	})
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("marshalable retrieve plan", "plan with marshal error", err.Error())
+	}
+
+	cloned := proto.Clone(req).(*querypb.QueryRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.OutputFieldsId = valid
+	return cloned, dropped, nil
+}
+
+// fillDroppedOutputFields reports the dropped output fields as warning of the result.
+func fillDroppedOutputFields(result *internalpb.RetrieveResults, dropped []int64) {
+	if len(dropped) == 0 {
+		return
+	}
+	result.DroppedOutputFieldIDs = dropped
+	result.Warning = fmt.Sprintf("output fields %v not found in collection schema, dropped", dropped)
+}
+
+// fieldFormat returns the encoding of the field derived from schema.
+func fieldFormat(field *schemapb.FieldSchema) *internalpb.FieldFormat {
+	format := &internalpb.FieldFormat{
+		FieldID:      field.GetFieldID(),
+		Name:         field.GetName(),
+		DataType:     field.GetDataType(),
+		IsDynamic:    field.GetIsDynamic(),
+		IsPrimaryKey: field.GetIsPrimaryKey(),
+	}
+	params := funcutil.KeyValuePair2Map(field.GetTypeParams())
+	parseParam := func(key string) int64 {
+		value, _ := strconv.ParseInt(params[key], 10, 64)
+		return value
+	}
+	switch {
+	case typeutil.IsVectorType(field.GetDataType()):
+		format.Dim = parseParam(common.DimKey)
+	case field.GetDataType() == schemapb.DataType_VarChar:
+		format.MaxLength = parseParam(common.MaxLengthKey)
+	case field.GetDataType() == schemapb.DataType_Array:
+		format.ElementType = field.GetElementType()
+		format.MaxCapacity = parseParam(common.MaxCapacityKey)
+		if field.GetElementType() == schemapb.DataType_VarChar {
+			format.MaxLength = parseParam(common.MaxLengthKey)
+		}
+	}
+	return format
+}
+
+// fillFieldFormats attaches the formats of the fields returned in result if requested,
+// the system fields not in schema are skipped.
+func fillFieldFormats(result *internalpb.RetrieveResults, schema *schemapb.CollectionSchema) {
+	formats := make([]*internalpb.FieldFormat, 0, len(result.GetFieldsData()))
+	for _, fieldData := range result.GetFieldsData() {
+		field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+			return field.GetFieldID() == fieldData.GetFieldId()
+		})
+		if ok {
+			formats = append(formats, fieldFormat(field))
+		}
+	}
+	result.FieldFormats = formats
+}
+
+var _ streamrpc.QueryStreamServer = (*fieldFormatsQueryStreamServer)(nil)
+
+// fieldFormatsQueryStreamServer attaches the formats of returned fields to each streamed result.
+type fieldFormatsQueryStreamServer struct {
+	server streamrpc.QueryStreamServer
+	schema *schemapb.CollectionSchema
+}
+
+func newFieldFormatsQueryStreamServer(srv streamrpc.QueryStreamServer, schema *schemapb.CollectionSchema) *fieldFormatsQueryStreamServer {
+	return &fieldFormatsQueryStreamServer{
+		server: srv,
+		schema: schema,
+	}
+}
+
+func (s *fieldFormatsQueryStreamServer) Send(result *internalpb.RetrieveResults) error {
+	if merr.Ok(result.GetStatus()) {
+		fillFieldFormats(result, s.schema)
+	}
+	return s.server.Send(result)
+}
+
+func (s *fieldFormatsQueryStreamServer) Context() context.Context {
+	return s.server.Context()
+}
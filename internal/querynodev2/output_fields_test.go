@@ -0,0 +1,84 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestFillFieldFormats(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		EnableDynamicField: true,
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_VarChar, IsPrimaryKey: true, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.MaxLengthKey, Value: "64"},
+			}},
+			{FieldID: 101, Name: "vector", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.DimKey, Value: "128"},
+			}},
+			{FieldID: 102, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.MaxCapacityKey, Value: "16"},
+				{Key: common.MaxLengthKey, Value: "32"},
+			}},
+			{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+			{FieldID: 104, Name: "count", DataType: schemapb.DataType_Int32},
+		},
+	}
+	result := &internalpb.RetrieveResults{
+		Status: merr.Success(),
+		FieldsData: []*schemapb.FieldData{
+			{FieldId: 100}, {FieldId: 101}, {FieldId: 102}, {FieldId: 103}, {FieldId: common.TimeStampField},
+		},
+	}
+	fillFieldFormats(result, schema)
+	assert.Equal(t, []*internalpb.FieldFormat{
+		{FieldID: 100, Name: "pk", DataType: schemapb.DataType_VarChar, MaxLength: 64, IsPrimaryKey: true},
+		{FieldID: 101, Name: "vector", DataType: schemapb.DataType_FloatVector, Dim: 128},
+		{FieldID: 102, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_VarChar, MaxCapacity: 16, MaxLength: 32},
+		{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	}, result.GetFieldFormats())
+
+	// attached to each streamed result
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := streamrpc.NewLocalQueryClient(ctx)
+	srv := newFieldFormatsQueryStreamServer(client.CreateServer(), schema)
+	require.NoError(t, srv.Send(&internalpb.RetrieveResults{
+		Status:     merr.Success(),
+		FieldsData: []*schemapb.FieldData{{FieldId: 104}},
+	}))
+	streamed, err := client.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, []*internalpb.FieldFormat{{FieldID: 104, Name: "count", DataType: schemapb.DataType_Int32}}, streamed.GetFieldFormats())
+
+	// failures are sent as is
+	require.NoError(t, srv.Send(&internalpb.RetrieveResults{Status: merr.Status(merr.ErrServiceInternal)}))
+	streamed, err = client.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, streamed.GetFieldFormats())
+}
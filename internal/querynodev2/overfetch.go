@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// applyOverfetch widens the topK of search request by the over-fetch candidates,
+// so the candidates following the topK hits are kept by reduce instead of discarded.
+// The request is cloned since it's shared among channels.
+func applyOverfetch(req *querypb.SearchRequest) (*querypb.SearchRequest, error) {
+	overfetch := req.GetReq().GetOverfetch()
+	if overfetch == 0 {
+		return req, nil
+	}
+	switch {
+	case overfetch < 0:
+		return nil, merr.WrapErrParameterInvalid("overfetch >= 0", fmt.Sprintf("overfetch = %d", overfetch), "invalid overfetch")
+	case req.GetReq().GetIsIterator():
+		return nil, merr.WrapErrParameterInvalidMsg("over-fetched search could not be iterated")
+	case req.GetReq().GetPerPartitionTopk():
+		return nil, merr.WrapErrParameterInvalidMsg("over-fetched search could not keep topK per partition")
+	case req.GetReq().GetScoreStatsOnly():
+		return nil, merr.WrapErrParameterInvalidMsg("score statistics only search could not be over-fetched")
+	}
+	topK := req.GetReq().GetTopk() + overfetch
+	if limit := paramtable.Get().QuotaConfig.TopKLimit.GetAsInt64(); topK > limit {
+		return nil, merr.WrapErrParameterInvalid(fmt.Sprintf("topK + overfetch <= %d", limit), fmt.Sprintf("topK + overfetch = %d", topK), "too many candidates over-fetched")
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	queryInfo := plan.GetVectorAnns().GetQueryInfo()
+	if queryInfo == nil {
+		return nil, merr.WrapErrParameterInvalidMsg("over-fetch requires vector search plan")
+	}
+	queryInfo.Topk = topK
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.Topk = topK
+	return cloned, nil
+}
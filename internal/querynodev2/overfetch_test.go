@@ -0,0 +1,76 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestApplyOverfetch(t *testing.T) {
+	paramtable.Init()
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{FieldId: 107, QueryInfo: &planpb.QueryInfo{Topk: 10}},
+		},
+	})
+	require.NoError(t, err)
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			SerializedExprPlan: plan,
+			Topk:               10,
+		},
+	}
+
+	// not requested
+	applied, err := applyOverfetch(req)
+	assert.NoError(t, err)
+	assert.Same(t, req, applied)
+
+	// topK widened in cloned request
+	req.Req.Overfetch = 5
+	applied, err = applyOverfetch(req)
+	require.NoError(t, err)
+	assert.EqualValues(t, 15, applied.GetReq().GetTopk())
+	appliedPlan := planpb.PlanNode{}
+	require.NoError(t, proto.Unmarshal(applied.GetReq().GetSerializedExprPlan(), &appliedPlan))
+	assert.EqualValues(t, 15, appliedPlan.GetVectorAnns().GetQueryInfo().GetTopk())
+	assert.EqualValues(t, 10, req.GetReq().GetTopk())
+
+	// exceeds topK limit
+	req.Req.Overfetch = paramtable.Get().QuotaConfig.TopKLimit.GetAsInt64()
+	_, err = applyOverfetch(req)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// invalid
+	req.Req.Overfetch = -1
+	_, err = applyOverfetch(req)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	req.Req.Overfetch = 5
+	req.Req.IsIterator = true
+	_, err = applyOverfetch(req)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
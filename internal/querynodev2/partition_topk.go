@@ -0,0 +1,173 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// validatePerPartitionTopK checks the partitions to search with per partition topK are loaded,
+// and the total hits of each query are bounded by queryNode.perPartitionTopK.maxHits.
+func validatePerPartitionTopK(req *querypb.SearchRequest, collection *segments.Collection) error {
+	partitionIDs := lo.Uniq(req.GetReq().GetPartitionIDs())
+	if len(partitionIDs) == 0 {
+		return merr.WrapErrParameterInvalidMsg("per partition topK requires the partitions to search")
+	}
+	for _, partitionID := range partitionIDs {
+		if !collection.ExistPartition(partitionID) {
+			return merr.WrapErrPartitionNotLoaded(partitionID)
+		}
+	}
+	switch {
+	case req.GetReq().GetExactSearch():
+		return merr.WrapErrParameterInvalidMsg("per partition topK could not be combined with exact search")
+	case refineFactor(req) > 0:
+		return merr.WrapErrParameterInvalidMsg("per partition topK could not be combined with refine or rerank")
+	case req.GetReq().GetIsIterator() || len(req.GetReq().GetIteratorToken()) > 0:
+		return merr.WrapErrParameterInvalidMsg("per partition topK could not be combined with search iterator")
+	}
+	maxHits := paramtable.Get().QueryNodeCfg.PerPartitionTopKMaxHits.GetAsInt64()
+	if hits := req.GetReq().GetTopk() * int64(len(partitionIDs)); hits > maxHits {
+		return merr.WrapErrParameterInvalid(maxHits, hits, "too many hits of each query with per partition topK")
+	}
+	return nil
+}
+
+// searchPerPartition searches each partition separately to keep topK hits for each of them,
+// hits of each query are grouped by partition in the requested order and annotated with the partition id pseudo field.
+func (node *QueryNode) searchPerPartition(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest) (*internalpb.SearchResults, []*internalpb.SegmentScanDecision, error) {
+	partitionIDs := lo.Uniq(req.GetReq().GetPartitionIDs())
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.Int64s("partitionIDs", partitionIDs),
+	)
+
+	results := make([]*internalpb.SearchResults, len(partitionIDs))
+	decisions := make([][]*internalpb.SegmentScanDecision, len(partitionIDs))
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i, partitionID := range partitionIDs {
+		i := i
+		partitionReq := proto.Clone(req).(*querypb.SearchRequest)
+		partitionReq.Req.PartitionIDs = []int64{partitionID}
+		group.Go(func() error {
+			var err error
+			if maxNQ := paramtable.Get().QueryNodeCfg.MaxNQPerBatch.GetAsInt64(); maxNQ > 0 && partitionReq.GetReq().GetNq() > maxNQ {
+				results[i], decisions[i], err = node.searchDelegatorInNQBatches(groupCtx, sd, partitionReq, maxNQ)
+			} else {
+				results[i], decisions[i], err = node.searchDelegator(groupCtx, sd, partitionReq)
+			}
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		log.Warn("failed to search partitions", zap.Error(err))
+		return nil, nil, err
+	}
+
+	nq, topk := req.GetReq().GetNq(), excludedReduceTopK(req)
+	datas := make(map[int64]*schemapb.SearchResultData, len(partitionIDs))
+	for i, result := range results {
+		if result.GetSlicedBlob() == nil {
+			continue
+		}
+		decoded, err := segments.DecodeSearchResults([]*internalpb.SearchResults{result})
+		if err != nil {
+			return nil, nil, err
+		}
+		segments.AppendPartitionIDField(decoded[0], partitionIDs[i])
+		datas[partitionIDs[i]] = decoded[0]
+	}
+	merged := typeutil2.ConcatPartitionSearchResultData(partitionIDs, datas, nq, topk*int64(len(partitionIDs)))
+	resp, err := segments.EncodeSearchResultData(merged, nq, topk*int64(len(partitionIDs)), req.GetReq().GetMetricType())
+	if err != nil {
+		return nil, nil, err
+	}
+	resp.Topks = merged.GetTopks()
+	resp.Truncated = make([]bool, nq)
+	for _, result := range results {
+		for i, truncated := range result.GetTruncated() {
+			if int64(i) < nq && truncated {
+				resp.Truncated[i] = true
+			}
+		}
+		resp.ScannedSegments += result.GetScannedSegments()
+		resp.ScannedRows += result.GetScannedRows()
+	}
+	return resp, lo.Flatten(decisions), nil
+}
+
+// reducePerPartitionSearchResults reduces the search results of channels with hits grouped by partition,
+// hits of each partition are reduced separately to keep topK hits for each of them.
+func reducePerPartitionSearchResults(ctx context.Context, results []*internalpb.SearchResults, req *querypb.SearchRequest) (*internalpb.SearchResults, error) {
+	partitionIDs := lo.Uniq(req.GetReq().GetPartitionIDs())
+	nq, topk, metricType := req.GetReq().GetNq(), req.GetReq().GetTopk(), req.GetReq().GetMetricType()
+	totalTopK := topk * int64(len(partitionIDs))
+
+	decoded, err := segments.DecodeSearchResults(results)
+	if err != nil {
+		return nil, err
+	}
+	partitionDatas := make(map[int64][]*schemapb.SearchResultData)
+	for _, data := range decoded {
+		split, err := typeutil2.SplitSearchResultDataByPartition(data)
+		if err != nil {
+			return nil, err
+		}
+		for partitionID, partitionData := range split {
+			partitionDatas[partitionID] = append(partitionDatas[partitionID], partitionData)
+		}
+	}
+	reduced := make(map[int64]*schemapb.SearchResultData, len(partitionDatas))
+	for partitionID, datas := range partitionDatas {
+		reduced[partitionID], err = segments.ReduceSearchResultData(ctx, datas, nq, topk, metricType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// metadata of results are merged as usual, hits are replaced by the ones reduced by partition
+	result, err := segments.ReduceSearchResults(ctx, results, nq, totalTopK, metricType)
+	if err != nil {
+		return nil, err
+	}
+	merged := typeutil2.ConcatPartitionSearchResultData(partitionIDs, reduced, nq, totalTopK)
+	encoded, err := segments.EncodeSearchResultData(merged, nq, totalTopK, metricType)
+	if err != nil {
+		return nil, err
+	}
+	result.SlicedBlob = encoded.GetSlicedBlob()
+	result.Topks = merged.GetTopks()
+	if err := segments.FillPartitionTopks(result, partitionIDs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
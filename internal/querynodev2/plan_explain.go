@@ -0,0 +1,341 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// maxExplainedValues is the max number of values of term or json contains expression to render,
+// the rest are summarized by count.
+const maxExplainedValues = 16
+
+var explainOps = map[planpb.OpType]string{
+	planpb.OpType_GreaterThan:  ">",
+	planpb.OpType_GreaterEqual: ">=",
+	planpb.OpType_LessThan:     "<",
+	planpb.OpType_LessEqual:    "<=",
+	planpb.OpType_Equal:        "==",
+	planpb.OpType_NotEqual:     "!=",
+	planpb.OpType_In:           "IN",
+	planpb.OpType_NotIn:        "NOT IN",
+}
+
+var explainArithOps = map[planpb.ArithOpType]string{
+	planpb.ArithOpType_Add: "+",
+	planpb.ArithOpType_Sub: "-",
+	planpb.ArithOpType_Mul: "*",
+	planpb.ArithOpType_Div: "/",
+	planpb.ArithOpType_Mod: "%",
+}
+
+// explainNode is a node of the rendered plan tree.
+type explainNode struct {
+	label    string
+	children []*explainNode
+}
+
+func newExplainNode(format string, args ...any) *explainNode {
+	return &explainNode{label: fmt.Sprintf(format, args...)}
+}
+
+func (n *explainNode) add(children ...*explainNode) *explainNode {
+	n.children = append(n.children, children...)
+	return n
+}
+
+func (n *explainNode) render(builder *strings.Builder, prefix string, last bool, root bool) {
+	childPrefix := prefix
+	if root {
+		builder.WriteString(n.label)
+	} else {
+		builder.WriteString(prefix + lo.Ternary(last, "└── ", "├── ") + n.label)
+		childPrefix = prefix + lo.Ternary(last, "    ", "│   ")
+	}
+	builder.WriteString("\n")
+	for i, child := range n.children {
+		child.render(builder, childPrefix, i == len(n.children)-1, false)
+	}
+}
+
+// planExplainer renders plan nodes with the field ids resolved to names by schema.
+type planExplainer struct {
+	fields map[int64]*schemapb.FieldSchema
+}
+
+func newPlanExplainer(schema *schemapb.CollectionSchema) *planExplainer {
+	return &planExplainer{
+		fields: lo.SliceToMap(schema.GetFields(), func(field *schemapb.FieldSchema) (int64, *schemapb.FieldSchema) {
+			return field.GetFieldID(), field
+		}),
+	}
+}
+
+func (e *planExplainer) fieldName(fieldID int64) string {
+	if field, ok := e.fields[fieldID]; ok {
+		return field.GetName()
+	}
+	return fmt.Sprintf("<unknown field %d>", fieldID)
+}
+
+func (e *planExplainer) field(fieldID int64) string {
+	return fmt.Sprintf("%s (%d)", e.fieldName(fieldID), fieldID)
+}
+
+func (e *planExplainer) column(info *planpb.ColumnInfo) string {
+	name := e.fieldName(info.GetFieldId())
+	for _, path := range info.GetNestedPath() {
+		name += fmt.Sprintf("[%q]", path)
+	}
+	return name
+}
+
+func explainValue(value *planpb.GenericValue) string {
+	switch v := value.GetVal().(type) {
+	case *planpb.GenericValue_BoolVal:
+		return fmt.Sprint(v.BoolVal)
+	case *planpb.GenericValue_Int64Val:
+		return fmt.Sprint(v.Int64Val)
+	case *planpb.GenericValue_FloatVal:
+		return fmt.Sprint(v.FloatVal)
+	case *planpb.GenericValue_StringVal:
+		return fmt.Sprintf("%q", v.StringVal)
+	case *planpb.GenericValue_ArrayVal:
+		return explainValues(v.ArrayVal.GetArray())
+	default:
+		return "<nil>"
+	}
+}
+
+func explainValues(values []*planpb.GenericValue) string {
+	rendered := lo.Map(values, func(value *planpb.GenericValue, _ int) string { return explainValue(value) })
+	if len(rendered) > maxExplainedValues {
+		rendered = append(rendered[:maxExplainedValues], fmt.Sprintf("... %d more", len(values)-maxExplainedValues))
+	}
+	return "[" + strings.Join(rendered, ", ") + "]"
+}
+
+func explainOp(op planpb.OpType, column string, value *planpb.GenericValue) string {
+	switch op {
+	case planpb.OpType_PrefixMatch:
+		return fmt.Sprintf("%s LIKE %q", column, value.GetStringVal()+"%")
+	case planpb.OpType_PostfixMatch:
+		return fmt.Sprintf("%s LIKE %q", column, "%"+value.GetStringVal())
+	case planpb.OpType_Match:
+		return fmt.Sprintf("%s LIKE %q", column, value.GetStringVal())
+	}
+	symbol, ok := explainOps[op]
+	if !ok {
+		symbol = op.String()
+	}
+	return fmt.Sprintf("%s %s %s", column, symbol, explainValue(value))
+}
+
+func (e *planExplainer) arith(op planpb.ArithOpType, column string, operand *planpb.GenericValue) string {
+	if op == planpb.ArithOpType_ArrayLength {
+		return fmt.Sprintf("array_length(%s)", column)
+	}
+	symbol, ok := explainArithOps[op]
+	if !ok {
+		symbol = op.String()
+	}
+	return fmt.Sprintf("%s %s %s", column, symbol, explainValue(operand))
+}
+
+func (e *planExplainer) expr(expr *planpb.Expr) *explainNode {
+	switch {
+	case expr.GetTermExpr() != nil:
+		term := expr.GetTermExpr()
+		node := newExplainNode("%s IN %s", e.column(term.GetColumnInfo()), explainValues(term.GetValues()))
+		if term.GetIsInField() {
+			node.label += " (in field)"
+		}
+		return node
+	case expr.GetUnaryExpr() != nil:
+		unary := expr.GetUnaryExpr()
+		return newExplainNode("%s", strings.ToUpper(unary.GetOp().String())).add(e.expr(unary.GetChild()))
+	case expr.GetBinaryExpr() != nil:
+		binary := expr.GetBinaryExpr()
+		label := map[planpb.BinaryExpr_BinaryOp]string{
+			planpb.BinaryExpr_LogicalAnd: "AND",
+			planpb.BinaryExpr_LogicalOr:  "OR",
+		}[binary.GetOp()]
+		if label == "" {
+			label = binary.GetOp().String()
+		}
+		return newExplainNode("%s", label).add(e.expr(binary.GetLeft()), e.expr(binary.GetRight()))
+	case expr.GetCompareExpr() != nil:
+		compare := expr.GetCompareExpr()
+		symbol, ok := explainOps[compare.GetOp()]
+		if !ok {
+			symbol = compare.GetOp().String()
+		}
+		return newExplainNode("%s %s %s", e.column(compare.GetLeftColumnInfo()), symbol, e.column(compare.GetRightColumnInfo()))
+	case expr.GetUnaryRangeExpr() != nil:
+		unaryRange := expr.GetUnaryRangeExpr()
+		return newExplainNode("%s", explainOp(unaryRange.GetOp(), e.column(unaryRange.GetColumnInfo()), unaryRange.GetValue()))
+	case expr.GetBinaryRangeExpr() != nil:
+		binaryRange := expr.GetBinaryRangeExpr()
+		return newExplainNode("%s %s %s %s %s",
+			explainValue(binaryRange.GetLowerValue()), lo.Ternary(binaryRange.GetLowerInclusive(), "<=", "<"),
+			e.column(binaryRange.GetColumnInfo()),
+			lo.Ternary(binaryRange.GetUpperInclusive(), "<=", "<"), explainValue(binaryRange.GetUpperValue()))
+	case expr.GetBinaryArithOpEvalRangeExpr() != nil:
+		arith := expr.GetBinaryArithOpEvalRangeExpr()
+		operand := e.arith(arith.GetArithOp(), e.column(arith.GetColumnInfo()), arith.GetRightOperand())
+		return newExplainNode("%s", explainOp(arith.GetOp(), operand, arith.GetValue()))
+	case expr.GetBinaryArithExpr() != nil:
+		arith := expr.GetBinaryArithExpr()
+		symbol, ok := explainArithOps[arith.GetOp()]
+		if !ok {
+			symbol = arith.GetOp().String()
+		}
+		return newExplainNode("%s", symbol).add(e.expr(arith.GetLeft()), e.expr(arith.GetRight()))
+	case expr.GetValueExpr() != nil:
+		return newExplainNode("%s", explainValue(expr.GetValueExpr().GetValue()))
+	case expr.GetColumnExpr() != nil:
+		return newExplainNode("%s", e.column(expr.GetColumnExpr().GetInfo()))
+	case expr.GetExistsExpr() != nil:
+		return newExplainNode("EXISTS %s", e.column(expr.GetExistsExpr().GetInfo()))
+	case expr.GetAlwaysTrueExpr() != nil:
+		return newExplainNode("TRUE")
+	case expr.GetJsonContainsExpr() != nil:
+		contains := expr.GetJsonContainsExpr()
+		function := map[planpb.JSONContainsExpr_JSONOp]string{
+			planpb.JSONContainsExpr_Contains:    "json_contains",
+			planpb.JSONContainsExpr_ContainsAll: "json_contains_all",
+			planpb.JSONContainsExpr_ContainsAny: "json_contains_any",
+		}[contains.GetOp()]
+		if function == "" {
+			function = contains.GetOp().String()
+		}
+		return newExplainNode("%s(%s, %s)", function, e.column(contains.GetColumnInfo()), explainValues(contains.GetElements()))
+	default:
+		return newExplainNode("<unknown expr>")
+	}
+}
+
+func (e *planExplainer) filter(predicates *planpb.Expr) *explainNode {
+	if predicates == nil {
+		return newExplainNode("filter: none")
+	}
+	return newExplainNode("filter").add(e.expr(predicates))
+}
+
+func (e *planExplainer) plan(plan *planpb.PlanNode) *explainNode {
+	var root *explainNode
+	switch {
+	case plan.GetVectorAnns() != nil:
+		anns := plan.GetVectorAnns()
+		info := anns.GetQueryInfo()
+		root = newExplainNode("Search").add(
+			newExplainNode("vector field: %s, %s", e.field(anns.GetFieldId()), anns.GetVectorType()),
+			newExplainNode("metric: %s, topk: %d, round decimal: %d", info.GetMetricType(), info.GetTopk(), info.GetRoundDecimal()),
+			newExplainNode("search params: %s", info.GetSearchParams()),
+			e.filter(anns.GetPredicates()),
+		)
+	case plan.GetQuery() != nil:
+		query := plan.GetQuery()
+		root = newExplainNode("Query").add(
+			newExplainNode("count: %t, limit: %d", query.GetIsCount(), query.GetLimit()),
+			e.filter(query.GetPredicates()),
+		)
+	case plan.GetPredicates() != nil:
+		root = newExplainNode("Query").add(e.filter(plan.GetPredicates()))
+	default:
+		root = newExplainNode("<empty plan>")
+	}
+	if len(plan.GetOutputFieldIds()) > 0 {
+		root.add(newExplainNode("output fields: %s",
+			strings.Join(lo.Map(plan.GetOutputFieldIds(), func(fieldID int64, _ int) string { return e.field(fieldID) }), ", ")))
+	}
+	return root
+}
+
+// explainPlan renders the plan as a human-readable tree, with field ids resolved to names by schema.
+func explainPlan(plan *planpb.PlanNode, schema *schemapb.CollectionSchema) string {
+	builder := &strings.Builder{}
+	newPlanExplainer(schema).plan(plan).render(builder, "", true, true)
+	return builder.String()
+}
+
+// ExplainPlan renders the serialized plan of collection as a human-readable tree, essentially an EXPLAIN:
+// the vector field, metric, topk and search params of a search, the filter expression tree and the output fields,
+// with field ids resolved to names by the collection schema.
+func (node *QueryNode) ExplainPlan(ctx context.Context, collectionID int64, serializedPlan []byte) (string, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return "", err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return "", merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(serializedPlan, &plan); err != nil {
+		return "", merr.WrapErrParameterInvalid("valid serialized plan", "no unmarshalable one", err.Error())
+	}
+	return explainPlan(&plan, collection.Schema()), nil
+}
+
+// ExplainSearch renders the plan the search request would run on channel, which is the requested one
+// with the default search params and the query hook applied as searchChannel does.
+func (node *QueryNode) ExplainSearch(ctx context.Context, req *querypb.SearchRequest, channel string) (string, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return "", err
+	}
+	defer node.lifetime.Done()
+
+	sd, ok := node.delegators.Get(channel)
+	if !ok {
+		return "", merr.WrapErrChannelNotFound(channel)
+	}
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return "", merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+
+	req, err := node.searchParamDefaults.apply(req)
+	if err != nil {
+		return "", err
+	}
+	if !req.GetReq().GetSkipHook() {
+		req, err = node.optimizeSearchParams(ctx, req, sd)
+		if err != nil {
+			log.Ctx(ctx).Warn("failed to optimize search params for explaining", zap.Error(err))
+			return "", err
+		}
+	}
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return "", merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	return explainPlan(&plan, collection.Schema()), nil
+}
@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+)
+
+func TestExplainPlan(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector},
+			{FieldID: 102, Name: "age", DataType: schemapb.DataType_Int64},
+			{FieldID: 103, Name: "meta", DataType: schemapb.DataType_JSON},
+		},
+	}
+	int64Value := func(v int64) *planpb.GenericValue {
+		return &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: v}}
+	}
+
+	t.Run("search", func(t *testing.T) {
+		plan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{VectorAnns: &planpb.VectorANNS{
+				VectorType: planpb.VectorType_FloatVector,
+				FieldId:    101,
+				QueryInfo: &planpb.QueryInfo{
+					Topk:         10,
+					MetricType:   "L2",
+					SearchParams: `{"nprobe":16}`,
+					RoundDecimal: -1,
+				},
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_BinaryExpr{BinaryExpr: &planpb.BinaryExpr{
+					Op: planpb.BinaryExpr_LogicalAnd,
+					Left: &planpb.Expr{Expr: &planpb.Expr_UnaryRangeExpr{UnaryRangeExpr: &planpb.UnaryRangeExpr{
+						ColumnInfo: &planpb.ColumnInfo{FieldId: 102},
+						Op:         planpb.OpType_GreaterThan,
+						Value:      int64Value(18),
+					}}},
+					Right: &planpb.Expr{Expr: &planpb.Expr_UnaryExpr{UnaryExpr: &planpb.UnaryExpr{
+						Op: planpb.UnaryExpr_Not,
+						Child: &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{
+							ColumnInfo: &planpb.ColumnInfo{FieldId: 103, NestedPath: []string{"tag"}},
+							Values: []*planpb.GenericValue{
+								{Val: &planpb.GenericValue_StringVal{StringVal: "a"}},
+								{Val: &planpb.GenericValue_StringVal{StringVal: "b"}},
+							},
+						}}},
+					}}},
+				}}},
+			}},
+			OutputFieldIds: []int64{100, 102},
+		}
+		expected := `Search
+├── vector field: vec (101), FloatVector
+├── metric: L2, topk: 10, round decimal: -1
+├── search params: {"nprobe":16}
+├── filter
+│   └── AND
+│       ├── age > 18
+│       └── NOT
+│           └── meta["tag"] IN ["a", "b"]
+└── output fields: pk (100), age (102)
+`
+		assert.Equal(t, expected, explainPlan(plan, schema))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		values := make([]*planpb.GenericValue, 0, maxExplainedValues+2)
+		for i := 0; i < maxExplainedValues+2; i++ {
+			values = append(values, int64Value(int64(i)))
+		}
+		plan := &planpb.PlanNode{
+			Node: &planpb.PlanNode_Query{Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{
+					ColumnInfo: &planpb.ColumnInfo{FieldId: 100},
+					Values:     values,
+				}}},
+				Limit: 5,
+			}},
+			OutputFieldIds: []int64{104},
+		}
+		expected := `Query
+├── count: false, limit: 5
+├── filter
+│   └── pk IN [0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, ... 2 more]
+└── output fields: <unknown field 104> (104)
+`
+		assert.Equal(t, expected, explainPlan(plan, schema))
+	})
+}
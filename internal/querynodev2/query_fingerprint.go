@@ -0,0 +1,117 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// queryFingerprint computes the canonical fingerprint of the search executed on channel,
+// so that identical concurrent searches could be coalesced by proxy.
+// The request is normalized before hashing: the fields varying by sender, e.g. msg base, request id and timeout,
+// are dropped, partitions and segments are sorted, and the plan is marshaled deterministically.
+// The guarantee timestamp and the target version of channel are hashed as well, the same logical query
+// gets the same fingerprint on any node serving the same target.
+func queryFingerprint(req *querypb.SearchRequest, channel string, targetVersion int64) (string, error) {
+	normalized := proto.Clone(req.GetReq()).(*internalpb.SearchRequest)
+	normalized.Base = nil
+	normalized.ReqID = 0
+	normalized.TimeoutTimestamp = 0
+	normalized.Username = ""
+	normalized.ReturnFingerprint = false
+	sort.Slice(normalized.PartitionIDs, func(i, j int) bool {
+		return normalized.PartitionIDs[i] < normalized.PartitionIDs[j]
+	})
+
+	plan := &planpb.PlanNode{}
+	if err := proto.Unmarshal(normalized.GetSerializedExprPlan(), plan); err != nil {
+		return "", merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	serializedPlan, err := marshalDeterministic(plan)
+	if err != nil {
+		return "", err
+	}
+	normalized.SerializedExprPlan = serializedPlan
+	serializedReq, err := marshalDeterministic(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	segmentIDs := append([]int64(nil), req.GetSegmentIDs()...)
+	sort.Slice(segmentIDs, func(i, j int) bool {
+		return segmentIDs[i] < segmentIDs[j]
+	})
+
+	hash := sha256.New()
+	writeBytes := func(data []byte) {
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(data)))
+		hash.Write(size[:])
+		hash.Write(data)
+	}
+	writeInt := func(value int64) {
+		var data [8]byte
+		binary.LittleEndian.PutUint64(data[:], uint64(value))
+		hash.Write(data[:])
+	}
+	writeBytes(serializedReq)
+	writeBytes([]byte(channel))
+	writeInt(targetVersion)
+	writeInt(int64(req.GetScope()))
+	writeInt(int64(len(segmentIDs)))
+	for _, segmentID := range segmentIDs {
+		writeInt(segmentID)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// combineQueryFingerprints computes the fingerprint of search over multiple channels from the ones of channels,
+// the fingerprint of single channel is kept as is.
+func combineQueryFingerprints(results []*internalpb.SearchResults) string {
+	fingerprints := make([]string, 0, len(results))
+	for _, result := range results {
+		fingerprints = append(fingerprints, result.GetQueryFingerprint())
+	}
+	if len(fingerprints) == 1 {
+		return fingerprints[0]
+	}
+	sort.Strings(fingerprints)
+	hash := sha256.New()
+	for _, fingerprint := range fingerprints {
+		hash.Write([]byte(fingerprint))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func marshalDeterministic(msg proto.Message) ([]byte, error) {
+	buffer := proto.NewBuffer(nil)
+	buffer.SetDeterministic(true)
+	if err := buffer.Marshal(msg); err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable message", "message with marshal error", err.Error())
+	}
+	return buffer.Bytes(), nil
+}
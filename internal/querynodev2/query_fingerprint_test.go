@@ -0,0 +1,120 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	genReq := func(topK int64) *querypb.SearchRequest {
+		plan, err := proto.Marshal(&planpb.PlanNode{
+			Node: &planpb.PlanNode_VectorAnns{
+				VectorAnns: &planpb.VectorANNS{
+					FieldId:   107,
+					QueryInfo: &planpb.QueryInfo{Topk: topK, MetricType: "L2", SearchParams: `{"nprobe": 8}`},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return &querypb.SearchRequest{
+			Req: &internalpb.SearchRequest{
+				Base:               &commonpb.MsgBase{MsgID: 1, SourceID: 1},
+				ReqID:              1,
+				CollectionID:       100,
+				PartitionIDs:       []int64{2, 1},
+				SerializedExprPlan: plan,
+				PlaceholderGroup:   []byte("queries"),
+				GuaranteeTimestamp: 1000,
+				TimeoutTimestamp:   2000,
+				Nq:                 1,
+				Topk:               topK,
+				MetricType:         "L2",
+				ReturnFingerprint:  true,
+			},
+			SegmentIDs: []int64{3, 4},
+		}
+	}
+
+	fingerprint, err := queryFingerprint(genReq(10), "dml_0", 1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, fingerprint)
+
+	// fields varying by sender are ignored
+	req := genReq(10)
+	req.Req.Base = &commonpb.MsgBase{MsgID: 2, SourceID: 2}
+	req.Req.ReqID = 2
+	req.Req.TimeoutTimestamp = 3000
+	req.Req.PartitionIDs = []int64{1, 2}
+	req.SegmentIDs = []int64{4, 3}
+	another, err := queryFingerprint(req, "dml_0", 1)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, another)
+
+	// different logical queries
+	for _, tc := range []struct {
+		name          string
+		req           *querypb.SearchRequest
+		channel       string
+		targetVersion int64
+	}{
+		{"topk", genReq(20), "dml_0", 1},
+		{"channel", genReq(10), "dml_1", 1},
+		{"target_version", genReq(10), "dml_0", 2},
+		{"guarantee_ts", func() *querypb.SearchRequest {
+			req := genReq(10)
+			req.Req.GuaranteeTimestamp = 1001
+			return req
+		}(), "dml_0", 1},
+		{"queries", func() *querypb.SearchRequest {
+			req := genReq(10)
+			req.Req.PlaceholderGroup = []byte("other queries")
+			return req
+		}(), "dml_0", 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			other, err := queryFingerprint(tc.req, tc.channel, tc.targetVersion)
+			require.NoError(t, err)
+			assert.NotEqual(t, fingerprint, other)
+		})
+	}
+
+	// malformed plan
+	req = genReq(10)
+	req.Req.SerializedExprPlan = []byte("malformed")
+	_, err = queryFingerprint(req, "dml_0", 1)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestCombineQueryFingerprints(t *testing.T) {
+	results := []*internalpb.SearchResults{{QueryFingerprint: "a"}, {QueryFingerprint: "b"}}
+	combined := combineQueryFingerprints(results)
+	assert.NotEmpty(t, combined)
+	// independent of the order channels finished
+	assert.Equal(t, combined, combineQueryFingerprints([]*internalpb.SearchResults{results[1], results[0]}))
+	assert.Equal(t, "a", combineQueryFingerprints(results[:1]))
+}
@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type queryTimeoutKey struct{}
+
+// withMaxQueryTimeout derives the context of request, whose deadline is the earlier one of
+// the client deadline and the server max query timeout.
+func withMaxQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withQueryTimeout(ctx, paramtable.Get().QueryNodeCfg.MaxQueryTimeout.GetAsDuration(time.Millisecond))
+}
+
+// withQueryTimeout derives the context of request, whose deadline is the earlier one of
+// the client deadline and the timeout, 0 means not capped.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	// context.WithTimeout keeps the parent deadline if it's earlier
+	return context.WithTimeout(context.WithValue(ctx, queryTimeoutKey{}, timeout), timeout)
+}
+
+// tagServerTimeout tags err as capped by server if the request context derived by withQueryTimeout
+// exceeds its deadline while the client context is still alive.
+func tagServerTimeout(clientCtx context.Context, requestCtx context.Context, err error) error {
+	if err == nil || clientCtx.Err() != nil || !errors.Is(requestCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	maxTimeout, ok := requestCtx.Value(queryTimeoutKey{}).(time.Duration)
+	if !ok {
+		maxTimeout = paramtable.Get().QueryNodeCfg.MaxQueryTimeout.GetAsDuration(time.Millisecond)
+	}
+	return errors.Wrapf(context.DeadlineExceeded, "request exceeds server max query timeout %s, capped by server: %s", maxTimeout, err.Error())
+}
+
+// QueryTimeoutOverride is the max timeout of search and query of collection, overriding the server max query timeout.
+type QueryTimeoutOverride struct {
+	CollectionID int64
+	Timeout      time.Duration
+	UpdatedAt    time.Time
+}
+
+// queryTimeoutOverrides keeps the query timeout overrides of collections set by admin.
+type queryTimeoutOverrides struct {
+	overrides *typeutil.ConcurrentMap[int64, *QueryTimeoutOverride]
+}
+
+func newQueryTimeoutOverrides() *queryTimeoutOverrides {
+	return &queryTimeoutOverrides{
+		overrides: typeutil.NewConcurrentMap[int64, *QueryTimeoutOverride](),
+	}
+}
+
+// set replaces the query timeout override of collection, 0 removes the override.
+// The timeout is bounded by queryNode.queryTimeoutOverride.max.
+func (o *queryTimeoutOverrides) set(collectionID int64, timeout time.Duration) error {
+	if timeout < 0 {
+		return merr.WrapErrParameterInvalid("non-negative timeout", timeout.String(), "invalid query timeout override")
+	}
+	if timeout == 0 {
+		o.overrides.Remove(collectionID)
+		return nil
+	}
+	maxTimeout := paramtable.Get().QueryNodeCfg.QueryTimeoutOverrideMax.GetAsDuration(time.Millisecond)
+	if maxTimeout <= 0 {
+		return merr.WrapErrParameterInvalidMsg("query timeout overrides are disabled")
+	}
+	if timeout > maxTimeout {
+		return merr.WrapErrParameterInvalid("timeout <= "+maxTimeout.String(), timeout.String(), "query timeout override exceeds the max")
+	}
+	o.overrides.Insert(collectionID, &QueryTimeoutOverride{
+		CollectionID: collectionID,
+		Timeout:      timeout,
+		UpdatedAt:    time.Now(),
+	})
+	return nil
+}
+
+func (o *queryTimeoutOverrides) list() []*QueryTimeoutOverride {
+	result := make([]*QueryTimeoutOverride, 0, o.overrides.Len())
+	o.overrides.Range(func(_ int64, override *QueryTimeoutOverride) bool {
+		result = append(result, override)
+		return true
+	})
+	return result
+}
+
+// timeout returns the max query timeout of collection, which is the override if set,
+// otherwise the server max query timeout. The override is still bounded by the max settable one,
+// in case the max lowered after it's set. 0 means not capped.
+func (o *queryTimeoutOverrides) timeout(collectionID int64) time.Duration {
+	serverTimeout := paramtable.Get().QueryNodeCfg.MaxQueryTimeout.GetAsDuration(time.Millisecond)
+	override, ok := o.overrides.Get(collectionID)
+	if !ok {
+		return serverTimeout
+	}
+	maxTimeout := paramtable.Get().QueryNodeCfg.QueryTimeoutOverrideMax.GetAsDuration(time.Millisecond)
+	if maxTimeout <= 0 {
+		return serverTimeout
+	}
+	if override.Timeout > maxTimeout {
+		return maxTimeout
+	}
+	return override.Timeout
+}
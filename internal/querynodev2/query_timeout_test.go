@@ -0,0 +1,112 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestMaxQueryTimeout(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+
+	// not capped
+	ctx, cancel := withMaxQueryTimeout(context.Background())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+
+	params.Save(params.QueryNodeCfg.MaxQueryTimeout.Key, "10")
+	defer params.Reset(params.QueryNodeCfg.MaxQueryTimeout.Key)
+
+	// earlier client deadline kept
+	clientCtx, clientCancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer clientCancel()
+	ctx, cancel = withMaxQueryTimeout(clientCtx)
+	clientDeadline, _ := clientCtx.Deadline()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, clientDeadline, deadline)
+	<-ctx.Done()
+	err := tagServerTimeout(clientCtx, ctx, ctx.Err())
+	assert.Equal(t, context.DeadlineExceeded, err)
+	cancel()
+
+	// capped by server
+	clientCtx = context.Background()
+	ctx, cancel = withMaxQueryTimeout(clientCtx)
+	defer cancel()
+	<-ctx.Done()
+	err = tagServerTimeout(clientCtx, ctx, errors.New("search failed"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "capped by server")
+	assert.Equal(t, merr.TimeoutCode, merr.Code(err))
+
+	assert.NoError(t, tagServerTimeout(clientCtx, ctx, nil))
+}
+
+func TestQueryTimeoutOverrides(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.QueryNodeCfg.MaxQueryTimeout.Key, "1000")
+	defer params.Reset(params.QueryNodeCfg.MaxQueryTimeout.Key)
+	params.Save(params.QueryNodeCfg.QueryTimeoutOverrideMax.Key, "60000")
+	defer params.Reset(params.QueryNodeCfg.QueryTimeoutOverrideMax.Key)
+
+	overrides := newQueryTimeoutOverrides()
+	// server max query timeout without override
+	assert.Equal(t, time.Second, overrides.timeout(100))
+
+	// longer ceiling for analytical collection, tighter one for interactive collection
+	assert.NoError(t, overrides.set(100, 30*time.Second))
+	assert.NoError(t, overrides.set(101, 100*time.Millisecond))
+	assert.Equal(t, 30*time.Second, overrides.timeout(100))
+	assert.Equal(t, 100*time.Millisecond, overrides.timeout(101))
+	assert.Len(t, overrides.list(), 2)
+
+	// bounded by the max
+	assert.ErrorIs(t, overrides.set(100, 2*time.Minute), merr.ErrParameterInvalid)
+	assert.ErrorIs(t, overrides.set(100, -time.Second), merr.ErrParameterInvalid)
+	assert.Equal(t, 30*time.Second, overrides.timeout(100))
+	params.Save(params.QueryNodeCfg.QueryTimeoutOverrideMax.Key, "10000")
+	assert.Equal(t, 10*time.Second, overrides.timeout(100))
+
+	// disabled
+	params.Save(params.QueryNodeCfg.QueryTimeoutOverrideMax.Key, "0")
+	assert.Equal(t, time.Second, overrides.timeout(100))
+	assert.ErrorIs(t, overrides.set(102, time.Second), merr.ErrParameterInvalid)
+
+	// removed
+	assert.NoError(t, overrides.set(100, 0))
+	assert.Len(t, overrides.list(), 1)
+
+	// server timeout tagged with the override
+	ctx, cancel := withQueryTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	err := tagServerTimeout(context.Background(), ctx, errors.New("query failed"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "timeout 1ms")
+}
@@ -0,0 +1,188 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// SegmentReadinessIssue is a segment in target which is not ready to be queried.
+type SegmentReadinessIssue struct {
+	SegmentID int64
+	Channel   string
+	// NodeID is the node serving the segment in distribution, 0 if the segment is not in distribution
+	NodeID int64
+	Reason string
+}
+
+// CollectionReadiness is the readiness of the channels of collection delegated by the node.
+type CollectionReadiness struct {
+	CollectionID int64
+	Ready        bool
+	Channels     []string
+	// TargetSegments is the number of sealed and growing segments in target of the channels,
+	// RemoteSegments is the number of the readable sealed ones served by other nodes, whose index is not checked.
+	TargetSegments int
+	RemoteSegments int
+	// Missing segments are in target but not readable, Degraded segments are readable
+	// but served by brute force though large enough to be indexed.
+	Missing               []*SegmentReadinessIssue
+	Degraded              []*SegmentReadinessIssue
+	UnserviceableChannels []string
+}
+
+// ReadinessCheck checks that all segments in target of the channels of collection delegated by the node
+// are loaded, readable and indexed, segments too small to build index are intentionally served by brute force.
+func (node *QueryNode) ReadinessCheck(ctx context.Context, collectionID int64) (*CollectionReadiness, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+
+	result := &CollectionReadiness{
+		CollectionID:          collectionID,
+		Channels:              make([]string, 0),
+		Missing:               make([]*SegmentReadinessIssue, 0),
+		Degraded:              make([]*SegmentReadinessIssue, 0),
+		UnserviceableChannels: make([]string, 0),
+	}
+	minIndexRows := paramtable.Get().DataCoordCfg.MinSegmentNumRowsToEnableIndex.GetAsInt64()
+	indexedFields := make([]int64, 0)
+	for _, meta := range collection.IndexMeta().GetIndexMetas() {
+		indexedFields = append(indexedFields, meta.GetFieldID())
+	}
+
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() != collectionID {
+			return true
+		}
+		result.Channels = append(result.Channels, channel)
+		if !sd.Serviceable() {
+			result.UnserviceableChannels = append(result.UnserviceableChannels, channel)
+		}
+
+		sealedInTarget, growingInTarget := sd.GetTargetSegments()
+		result.TargetSegments += len(sealedInTarget) + len(growingInTarget)
+		sealed, growing := sd.GetSegmentInfo(false)
+		readableSealed, readableGrowing := sd.GetSegmentInfo(true)
+		distributed := make(map[int64]int64)
+		for _, item := range sealed {
+			for _, entry := range item.Segments {
+				distributed[entry.SegmentID] = item.NodeID
+			}
+		}
+		readable := make(map[int64]int64)
+		for _, item := range readableSealed {
+			for _, entry := range item.Segments {
+				readable[entry.SegmentID] = item.NodeID
+			}
+		}
+
+		for _, segmentID := range sealedInTarget {
+			nodeID, ok := readable[segmentID]
+			if !ok {
+				issue := &SegmentReadinessIssue{SegmentID: segmentID, Channel: channel, Reason: "not loaded by any node"}
+				if nodeID, ok := distributed[segmentID]; ok {
+					issue.NodeID = nodeID
+					issue.Reason = "loaded but not readable in current target"
+				}
+				result.Missing = append(result.Missing, issue)
+				continue
+			}
+			if nodeID != paramtable.GetNodeID() {
+				result.RemoteSegments++
+				continue
+			}
+			segment := node.manager.Segment.GetSealed(segmentID)
+			if segment == nil {
+				result.Missing = append(result.Missing, &SegmentReadinessIssue{SegmentID: segmentID, Channel: channel, NodeID: nodeID, Reason: "released from node"})
+				continue
+			}
+			if segment.InsertCount() < minIndexRows {
+				continue
+			}
+			for _, fieldID := range indexedFields {
+				if !segment.ExistIndex(fieldID) {
+					result.Degraded = append(result.Degraded, &SegmentReadinessIssue{
+						SegmentID: segmentID,
+						Channel:   channel,
+						NodeID:    nodeID,
+						Reason:    fmt.Sprintf("field %d served by brute force", fieldID),
+					})
+				}
+			}
+		}
+
+		readableGrowingIDs := make(map[int64]struct{})
+		for _, entry := range readableGrowing {
+			readableGrowingIDs[entry.SegmentID] = struct{}{}
+		}
+		growingIDs := make(map[int64]int64)
+		for _, entry := range growing {
+			growingIDs[entry.SegmentID] = entry.NodeID
+		}
+		for _, segmentID := range growingInTarget {
+			if _, ok := readableGrowingIDs[segmentID]; ok {
+				continue
+			}
+			issue := &SegmentReadinessIssue{SegmentID: segmentID, Channel: channel, Reason: "growing segment not consumed"}
+			if nodeID, ok := growingIDs[segmentID]; ok {
+				issue.NodeID = nodeID
+				issue.Reason = "growing segment not readable in current target"
+			}
+			result.Missing = append(result.Missing, issue)
+		}
+		return true
+	})
+	if len(result.Channels) == 0 {
+		return nil, merr.WrapErrServiceUnavailable(fmt.Sprintf("node is not delegator of collection %d", collectionID))
+	}
+
+	sort.Strings(result.Channels)
+	sort.Strings(result.UnserviceableChannels)
+	sortIssues := func(issues []*SegmentReadinessIssue) {
+		sort.Slice(issues, func(i, j int) bool {
+			return issues[i].SegmentID < issues[j].SegmentID
+		})
+	}
+	sortIssues(result.Missing)
+	sortIssues(result.Degraded)
+	result.Ready = len(result.Missing) == 0 && len(result.Degraded) == 0 && len(result.UnserviceableChannels) == 0
+	if !result.Ready {
+		log.Ctx(ctx).Warn("collection not ready",
+			zap.Int64("collectionID", collectionID),
+			zap.Int("missing", len(result.Missing)),
+			zap.Int("degraded", len(result.Degraded)),
+			zap.Strings("unserviceableChannels", result.UnserviceableChannels),
+		)
+	}
+	return result, nil
+}
@@ -0,0 +1,303 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// refineFactor returns the factor of candidates to rerank, searches reranked by another field refine topK candidates by default.
+func refineFactor(req *querypb.SearchRequest) int64 {
+	if req.GetReq().GetRefineFactor() == 0 && req.GetReq().GetRerankFieldId() > 0 {
+		return 1
+	}
+	return req.GetReq().GetRefineFactor()
+}
+
+// validateRefine checks the search could be refined with raw vectors of field, e.g. ANN on binary quantized index.
+// The scores are recomputed exactly, so only the metrics supported by brute force are allowed,
+// and the results carrying more than ids and scores are not supported.
+func validateRefine(req *querypb.SearchRequest, schema *schemapb.CollectionSchema, vectorFieldID int64, metricType string) error {
+	factor := refineFactor(req)
+	maxFactor := paramtable.Get().QueryNodeCfg.RefineMaxFactor.GetAsInt64()
+	if factor < 1 || factor > maxFactor {
+		return merr.WrapErrParameterInvalidRange(1, maxFactor, factor, "invalid refine factor")
+	}
+
+	var reason string
+	switch upperMetricType := strings.ToUpper(metricType); {
+	case len(req.GetReq().GetOutputFieldsId()) > 0:
+		reason = "output fields requested"
+	case req.GetReq().GetIsIterator() || len(req.GetReq().GetIteratorToken()) > 0:
+		reason = "search iterator"
+	case req.GetReq().GetReturnSegmentId():
+		reason = "segment id requested"
+	case upperMetricType != metric.L2 && upperMetricType != metric.IP && upperMetricType != metric.COSINE:
+		reason = fmt.Sprintf("metric type %s", metricType)
+	}
+	if reason != "" {
+		return merr.WrapErrParameterInvalidMsg("search could not be refined: %s", reason)
+	}
+	field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == vectorFieldID
+	})
+	if !ok || field.GetDataType() != schemapb.DataType_FloatVector {
+		return merr.WrapErrParameterInvalidMsg("search could not be refined: not a float vector field")
+	}
+	return nil
+}
+
+// rerankQueries validates the search reranked by another vector field and returns the queries of the rerank field.
+// The dimensions of queries and the metric types of both fields are checked against the schema and the loaded index meta,
+// as the exact scores are not comparable to the index built with another metric.
+func rerankQueries(req *querypb.SearchRequest, collection *segments.Collection, searchFieldID int64, queries [][]float32) ([][]float32, error) {
+	rerankFieldID := req.GetReq().GetRerankFieldId()
+	if rerankFieldID == searchFieldID {
+		return nil, merr.WrapErrParameterInvalidMsg("rerank field %d is the searched field", rerankFieldID)
+	}
+	placeholderGroup := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetReq().GetRerankPlaceholderGroup(), placeholderGroup); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid rerank placeholder group", "no unmarshalable one", err.Error())
+	}
+	rerankQueries := make([][]float32, 0, req.GetReq().GetNq())
+	if len(placeholderGroup.GetPlaceholders()) > 0 {
+		for _, value := range placeholderGroup.GetPlaceholders()[0].GetValues() {
+			rerankQueries = append(rerankQueries, bytesToFloats(value))
+		}
+	}
+	if int64(len(rerankQueries)) != req.GetReq().GetNq() {
+		return nil, merr.WrapErrParameterInvalid(req.GetReq().GetNq(), len(rerankQueries), "rerank queries mismatch nq")
+	}
+
+	indexMetricTypes := make(map[int64]string)
+	for _, meta := range collection.IndexMeta().GetIndexMetas() {
+		indexMetricTypes[meta.GetFieldID()] = funcutil.KeyValuePair2Map(meta.GetIndexParams())[common.MetricTypeKey]
+	}
+	checkField := func(fieldID int64, metricType string, queries [][]float32) error {
+		field, ok := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+			return field.GetFieldID() == fieldID
+		})
+		if !ok {
+			return merr.WrapErrFieldNotFound(fieldID)
+		}
+		if indexMetricType := indexMetricTypes[fieldID]; indexMetricType != "" && !strings.EqualFold(indexMetricType, metricType) {
+			return merr.WrapErrParameterInvalidMsg("metric type %s of field %d mismatches the index metric type %s", metricType, fieldID, indexMetricType)
+		}
+		// dimensions of other vector types are checked by segcore
+		if field.GetDataType() != schemapb.DataType_FloatVector {
+			return nil
+		}
+		dim, err := typeutil.GetDim(field)
+		if err != nil {
+			return err
+		}
+		for _, query := range queries {
+			if int64(len(query)) != dim {
+				return merr.WrapErrParameterInvalidMsg("dimension %d of query mismatches the dimension %d of field %d", len(query), dim, fieldID)
+			}
+		}
+		return nil
+	}
+	if err := checkField(searchFieldID, req.GetReq().GetMetricType(), queries); err != nil {
+		return nil, err
+	}
+	if err := checkField(rerankFieldID, req.GetReq().GetRerankMetricType(), rerankQueries); err != nil {
+		return nil, err
+	}
+	return rerankQueries, nil
+}
+
+// refineSearch searches topK * refine factor candidates over the quantized index,
+// then reranks the candidates by exact scores computed with their raw vectors and returns the topK.
+// If a rerank field is specified, the candidates are reranked by the exact scores on the rerank field instead,
+// e.g. ANN on a coarse embedding and rerank by a fine one.
+// It fails if raw vectors of any candidate are not available.
+func (node *QueryNode) refineSearch(ctx context.Context, sd delegator.ShardDelegator, req *querypb.SearchRequest, channel string) (*internalpb.SearchResults, []*internalpb.SegmentScanDecision, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+		zap.String("channel", channel),
+		zap.Int64("refineFactor", refineFactor(req)),
+		zap.Int64("rerankFieldID", req.GetReq().GetRerankFieldId()),
+	)
+
+	collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+	if collection == nil {
+		return nil, nil, merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, nil, err
+	}
+	vectorAnns, queries, err := searchQueries(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	// rerank by the searched field, or the rerank field if specified
+	rerankFieldID, metricType := vectorAnns.GetFieldId(), req.GetReq().GetMetricType()
+	if req.GetReq().GetRerankFieldId() > 0 {
+		rerankFieldID, metricType = req.GetReq().GetRerankFieldId(), req.GetReq().GetRerankMetricType()
+		queries, err = rerankQueries(req, collection, vectorAnns.GetFieldId(), queries)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := validateRefine(req, collection.Schema(), rerankFieldID, metricType); err != nil {
+		return nil, nil, err
+	}
+
+	// search candidates over the quantized index
+	topK := req.GetReq().GetTopk()
+	candidateReq := proto.Clone(req).(*querypb.SearchRequest)
+	candidateReq.Req.Topk = topK * refineFactor(req)
+	var candidates *internalpb.SearchResults
+	var scanDecisions []*internalpb.SegmentScanDecision
+	if maxNQ := paramtable.Get().QueryNodeCfg.MaxNQPerBatch.GetAsInt64(); maxNQ > 0 && req.GetReq().GetNq() > maxNQ {
+		candidates, scanDecisions, err = node.searchDelegatorInNQBatches(ctx, sd, candidateReq, maxNQ)
+	} else {
+		candidates, scanDecisions, err = node.searchDelegator(ctx, sd, candidateReq)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	decoded, err := segments.DecodeSearchResults([]*internalpb.SearchResults{candidates})
+	if err != nil {
+		return nil, nil, err
+	}
+	candidateData := &schemapb.SearchResultData{Ids: &schemapb.IDs{}, Topks: make([]int64, len(queries))}
+	if len(decoded) > 0 {
+		candidateData = decoded[0]
+	}
+
+	// retrieve raw vectors of candidates
+	pks := &schemapb.IDs{}
+	seen := make(map[any]struct{})
+	for i := 0; i < typeutil.GetSizeOfIDs(candidateData.GetIds()); i++ {
+		pk := typeutil.GetPK(candidateData.GetIds(), int64(i))
+		if _, ok := seen[pk]; !ok {
+			seen[pk] = struct{}{}
+			typeutil.AppendPKs(pks, pk)
+		}
+	}
+	vectors := make(map[any][]float32, len(seen))
+	if len(seen) > 0 {
+		plan, err := pkTermPlan(pkField, pks)
+		if err != nil {
+			return nil, nil, err
+		}
+		plan.OutputFieldIds = []int64{pkField.GetFieldID(), rerankFieldID}
+		queryReq, err := filterQueryRequest(req, plan, channel)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows, err := node.queryDelegatorForFilter(ctx, sd, queryReq, collection.Schema())
+		if err != nil {
+			log.Warn("failed to retrieve raw vectors of candidates", zap.Error(err))
+			return nil, nil, err
+		}
+		if fieldData, ok := lo.Find(rows.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+			return fieldData.GetFieldId() == rerankFieldID
+		}); ok {
+			data := fieldData.GetVectors().GetFloatVector().GetData()
+			dim := int(fieldData.GetVectors().GetDim())
+			for i := 0; dim > 0 && (i+1)*dim <= len(data); i++ {
+				vectors[typeutil.GetPK(rows.GetIds(), int64(i))] = data[i*dim : (i+1)*dim]
+			}
+		}
+	}
+	if len(vectors) < len(seen) {
+		err := merr.WrapErrServiceUnavailable(fmt.Sprintf("raw vectors of %d candidates not available", len(seen)-len(vectors)),
+			"search could not be refined")
+		log.Warn("failed to refine search", zap.Error(err))
+		return nil, nil, err
+	}
+
+	// rerank candidates of each query by exact scores
+	data := &schemapb.SearchResultData{
+		NumQueries: req.GetReq().GetNq(),
+		TopK:       topK,
+		Ids:        &schemapb.IDs{},
+		Topks:      make([]int64, 0, len(queries)),
+	}
+	truncated := make([]bool, len(queries))
+	var offset int64
+	for i, query := range queries {
+		type hit struct {
+			pk    any
+			score float32
+		}
+		hits := make([]hit, 0)
+		if i < len(candidateData.GetTopks()) {
+			for j := offset; j < offset+candidateData.GetTopks()[i]; j++ {
+				pk := typeutil.GetPK(candidateData.GetIds(), j)
+				score := bruteForceScore(metricType, query, vectors[pk])
+				hits = append(hits, hit{pk: pk, score: roundScore(score, vectorAnns.GetQueryInfo().GetRoundDecimal())})
+			}
+			offset += candidateData.GetTopks()[i]
+		}
+		// ordered as segcore does, score descending and pk ascending for tied scores
+		sort.SliceStable(hits, func(a, b int) bool {
+			if hits[a].score != hits[b].score {
+				return hits[a].score > hits[b].score
+			}
+			return typeutil.ComparePK(hits[a].pk, hits[b].pk)
+		})
+		if int64(len(hits)) > topK {
+			hits = hits[:topK]
+			truncated[i] = true
+		}
+		if i < len(candidates.GetTruncated()) && candidates.GetTruncated()[i] {
+			truncated[i] = true
+		}
+		for _, hit := range hits {
+			typeutil.AppendPKs(data.Ids, hit.pk)
+			data.Scores = append(data.Scores, hit.score)
+		}
+		data.Topks = append(data.Topks, int64(len(hits)))
+	}
+	log.Debug("search candidates reranked with raw vectors", zap.Int("candidateNum", len(seen)))
+
+	result, err := segments.EncodeSearchResultData(data, req.GetReq().GetNq(), topK, metricType)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Truncated = truncated
+	result.ScannedSegments = candidates.GetScannedSegments()
+	result.ScannedRows = candidates.GetScannedRows()
+	result.CostAggregation = candidates.GetCostAggregation()
+	return result, scanDecisions, nil
+}
@@ -0,0 +1,219 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	defaultChecksumBuckets = 256
+	maxChecksumBuckets     = 65536
+)
+
+// QueryChecksum is the checksum of the rows of a query result, independent of the row order.
+// Rows are bucketed by pk hash, so the rows differing from another replica could be narrowed down
+// to the mismatched buckets without transferring the rows.
+type QueryChecksum struct {
+	RowCount int64
+	// Buckets are the sums of the hashes of rows in each bucket
+	Buckets []uint64
+}
+
+// ChecksumVerification is the result of verifying the query result of the node against the checksum of a peer replica.
+type ChecksumVerification struct {
+	Match bool
+	Local *QueryChecksum
+	// RowCountDiff is the local row count minus the expected one
+	RowCountDiff      int64
+	MismatchedBuckets []int
+	// SuspectRowNum is the number of local rows in mismatched buckets, which contain the differing rows
+	// and the matching ones sharing the buckets. Rows missing locally are only reflected by RowCountDiff.
+	SuspectRowNum int64
+	// SuspectPKs are the first local pks in mismatched buckets in pk order, bounded by the max requested
+	SuspectPKs *schemapb.IDs
+}
+
+func mixHash(hash uint64) uint64 {
+	// fnv hash is not well distributed in high bits, mix it before use
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+	return hash
+}
+
+// checksumRowValue returns the value of field data at offset to hash.
+func checksumRowValue(field *schemapb.FieldData, offset int) any {
+	switch field.GetType() {
+	case schemapb.DataType_JSON:
+		return field.GetScalars().GetJsonData().GetData()[offset]
+	case schemapb.DataType_Array:
+		data, _ := proto.Marshal(field.GetScalars().GetArrayData().GetData()[offset])
+		return data
+	default:
+		return typeutil.GetData(field, offset)
+	}
+}
+
+// queryResultChecksum computes the checksum of retrieve result, and the bucket of each row.
+func queryResultChecksum(result *internalpb.RetrieveResults, buckets int) (*QueryChecksum, []int) {
+	fields := append([]*schemapb.FieldData(nil), result.GetFieldsData()...)
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].GetFieldId() < fields[j].GetFieldId()
+	})
+
+	rowNum := typeutil.GetSizeOfIDs(result.GetIds())
+	checksum := &QueryChecksum{
+		RowCount: int64(rowNum),
+		Buckets:  make([]uint64, buckets),
+	}
+	rowBuckets := make([]int, rowNum)
+	for i := 0; i < rowNum; i++ {
+		pk := typeutil.GetPK(result.GetIds(), int64(i))
+		pkHasher := fnv.New64a()
+		fmt.Fprintf(pkHasher, "%v", pk)
+		bucket := int(mixHash(pkHasher.Sum64()) % uint64(buckets))
+
+		rowHasher := fnv.New64a()
+		fmt.Fprintf(rowHasher, "%v", pk)
+		for _, field := range fields {
+			fmt.Fprintf(rowHasher, "|%d:%v", field.GetFieldId(), checksumRowValue(field, i))
+		}
+		checksum.Buckets[bucket] += mixHash(rowHasher.Sum64())
+		rowBuckets[i] = bucket
+	}
+	return checksum, rowBuckets
+}
+
+// verifyQueryChecksum compares the checksum of local result with the expected one,
+// the local pks in mismatched buckets are reported as suspects.
+func verifyQueryChecksum(result *internalpb.RetrieveResults, expected *QueryChecksum, maxSuspectPKs int) *ChecksumVerification {
+	local, rowBuckets := queryResultChecksum(result, len(expected.Buckets))
+	verification := &ChecksumVerification{
+		Local:             local,
+		RowCountDiff:      local.RowCount - expected.RowCount,
+		MismatchedBuckets: make([]int, 0),
+		SuspectPKs:        &schemapb.IDs{},
+	}
+	mismatched := make(map[int]struct{})
+	for i := range local.Buckets {
+		if local.Buckets[i] != expected.Buckets[i] {
+			verification.MismatchedBuckets = append(verification.MismatchedBuckets, i)
+			mismatched[i] = struct{}{}
+		}
+	}
+	verification.Match = verification.RowCountDiff == 0 && len(mismatched) == 0
+	if len(mismatched) == 0 {
+		return verification
+	}
+
+	suspects := make([]any, 0)
+	for i, bucket := range rowBuckets {
+		if _, ok := mismatched[bucket]; ok {
+			suspects = append(suspects, typeutil.GetPK(result.GetIds(), int64(i)))
+		}
+	}
+	verification.SuspectRowNum = int64(len(suspects))
+	sort.Slice(suspects, func(i, j int) bool {
+		return typeutil.ComparePK(suspects[i], suspects[j])
+	})
+	for i := 0; i < len(suspects) && i < maxSuspectPKs; i++ {
+		typeutil.AppendPKs(verification.SuspectPKs, suspects[i])
+	}
+	return verification
+}
+
+// queryForChecksum runs the query on the channels served as shard leader.
+func (node *QueryNode) queryForChecksum(ctx context.Context, req *querypb.QueryRequest) (*internalpb.RetrieveResults, error) {
+	resp, err := node.Query(ctx, req)
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to query for checksum",
+			zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ComputeQueryChecksum computes the checksum of the query result of the node, with rows bucketed into buckets,
+// defaultChecksumBuckets if buckets <= 0. The query shall be deterministic, e.g. with a guarantee timestamp
+// shared by replicas and without limit, for the checksums of replicas to be comparable.
+func (node *QueryNode) ComputeQueryChecksum(ctx context.Context, req *querypb.QueryRequest, buckets int) (*QueryChecksum, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if buckets <= 0 {
+		buckets = defaultChecksumBuckets
+	}
+	if buckets > maxChecksumBuckets {
+		return nil, merr.WrapErrParameterInvalid(fmt.Sprintf("buckets <= %d", maxChecksumBuckets), buckets, "too many checksum buckets")
+	}
+	result, err := node.queryForChecksum(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	checksum, _ := queryResultChecksum(result, buckets)
+	return checksum, nil
+}
+
+// VerifyQueryChecksum verifies the query result of the node against the checksum computed by a peer replica,
+// reports whether they agree, and on mismatch, at most maxSuspectPKs local pks in the mismatched buckets.
+func (node *QueryNode) VerifyQueryChecksum(ctx context.Context, req *querypb.QueryRequest, expected *QueryChecksum, maxSuspectPKs int) (*ChecksumVerification, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if expected == nil || len(expected.Buckets) == 0 || len(expected.Buckets) > maxChecksumBuckets {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid expected checksum")
+	}
+	result, err := node.queryForChecksum(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	verification := verifyQueryChecksum(result, expected, maxSuspectPKs)
+	if !verification.Match {
+		log.Ctx(ctx).Warn("query result diverges from peer replica",
+			zap.Int64("collectionID", req.GetReq().GetCollectionID()),
+			zap.Int64("rowCountDiff", verification.RowCountDiff),
+			zap.Int("mismatchedBuckets", len(verification.MismatchedBuckets)),
+			zap.Int64("suspectRowNum", verification.SuspectRowNum),
+		)
+	}
+	return verification, nil
+}
@@ -0,0 +1,72 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+)
+
+func TestQueryChecksum(t *testing.T) {
+	genResult := func(pks []int64, values []int64) *internalpb.RetrieveResults {
+		return &internalpb.RetrieveResults{
+			Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: pks}}},
+			FieldsData: []*schemapb.FieldData{{
+				Type:    schemapb.DataType_Int64,
+				FieldId: 101,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: values}},
+				}},
+			}},
+		}
+	}
+
+	// independent of row order
+	expected, _ := queryResultChecksum(genResult([]int64{1, 2, 3, 4}, []int64{10, 20, 30, 40}), defaultChecksumBuckets)
+	local, _ := queryResultChecksum(genResult([]int64{4, 3, 2, 1}, []int64{40, 30, 20, 10}), defaultChecksumBuckets)
+	assert.Equal(t, expected, local)
+	assert.EqualValues(t, 4, local.RowCount)
+
+	verification := verifyQueryChecksum(genResult([]int64{3, 1, 2, 4}, []int64{30, 10, 20, 40}), expected, 10)
+	assert.True(t, verification.Match)
+	assert.Empty(t, verification.MismatchedBuckets)
+	assert.EqualValues(t, 0, verification.SuspectRowNum)
+
+	// value of a row differs
+	verification = verifyQueryChecksum(genResult([]int64{1, 2, 3, 4}, []int64{10, 20, 31, 40}), expected, 10)
+	assert.False(t, verification.Match)
+	assert.EqualValues(t, 0, verification.RowCountDiff)
+	assert.Len(t, verification.MismatchedBuckets, 1)
+	assert.Contains(t, verification.SuspectPKs.GetIntId().GetData(), int64(3))
+
+	// extra row, suspects bounded
+	verification = verifyQueryChecksum(genResult([]int64{1, 2, 3, 4, 5}, []int64{10, 20, 30, 40, 50}), expected, 0)
+	assert.False(t, verification.Match)
+	assert.EqualValues(t, 1, verification.RowCountDiff)
+	assert.GreaterOrEqual(t, verification.SuspectRowNum, int64(1))
+	assert.Empty(t, verification.SuspectPKs.GetIntId().GetData())
+
+	// missing row
+	verification = verifyQueryChecksum(genResult([]int64{1, 2, 3}, []int64{10, 20, 30}), expected, 10)
+	assert.False(t, verification.Match)
+	assert.EqualValues(t, -1, verification.RowCountDiff)
+	assert.Len(t, verification.MismatchedBuckets, 1)
+}
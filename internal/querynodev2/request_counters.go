@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// CollectionRequestCounters is the number of search and query requests of collection
+// served as shard leader since the counters were created or reset.
+type CollectionRequestCounters struct {
+	CollectionID  int64
+	SearchSuccess int64
+	SearchFailure int64
+	QuerySuccess  int64
+	QueryFailure  int64
+	Since         time.Time
+}
+
+// requestCounterRegistry keeps the node-local request counters of collections,
+// maintained alongside the QueryNodeSQCount metrics.
+type requestCounterRegistry struct {
+	mu       sync.Mutex
+	counters map[int64]*CollectionRequestCounters
+}
+
+func newRequestCounterRegistry() *requestCounterRegistry {
+	return &requestCounterRegistry{
+		counters: make(map[int64]*CollectionRequestCounters),
+	}
+}
+
+// record counts a finished request, the label is metrics.SearchLabel or metrics.QueryLabel.
+func (r *requestCounterRegistry) record(collectionID int64, label string, success bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counters, ok := r.counters[collectionID]
+	if !ok {
+		counters = &CollectionRequestCounters{CollectionID: collectionID, Since: time.Now()}
+		r.counters[collectionID] = counters
+	}
+	switch {
+	case label == metrics.SearchLabel && success:
+		counters.SearchSuccess++
+	case label == metrics.SearchLabel:
+		counters.SearchFailure++
+	case success:
+		counters.QuerySuccess++
+	default:
+		counters.QueryFailure++
+	}
+}
+
+// list returns the copied counters ordered by collection id.
+func (r *requestCounterRegistry) list() []CollectionRequestCounters {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]CollectionRequestCounters, 0, len(r.counters))
+	for _, counters := range r.counters {
+		result = append(result, *counters)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CollectionID < result[j].CollectionID
+	})
+	return result
+}
+
+// reset drops the counters of given collections, or all counters if no collection given.
+func (r *requestCounterRegistry) reset(collectionIDs ...int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(collectionIDs) == 0 {
+		r.counters = make(map[int64]*CollectionRequestCounters)
+		return
+	}
+	for _, collectionID := range collectionIDs {
+		delete(r.counters, collectionID)
+	}
+}
+
+// GetRequestCounters returns the per-collection success and failure counts of search and query requests
+// served as shard leader since last reset, which is available even if the metrics are not scraped.
+func (node *QueryNode) GetRequestCounters(ctx context.Context) ([]CollectionRequestCounters, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.requestCounters.list(), nil
+}
+
+// ResetRequestCounters resets the request counters of given collections, or of all collections if none given.
+func (node *QueryNode) ResetRequestCounters(ctx context.Context, collectionIDs []int64) error {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return err
+	}
+	defer node.lifetime.Done()
+
+	log.Ctx(ctx).Info("reset request counters", zap.Int64s("collectionIDs", collectionIDs))
+	node.requestCounters.reset(collectionIDs...)
+	return nil
+}
@@ -0,0 +1,143 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// RowDeleteTrace is the existence and delete state of a row on a channel served by the node.
+type RowDeleteTrace struct {
+	Channel string
+	// CandidateSegments may contain the row by their bloom filters, false positive possible
+	CandidateSegments []int64
+	// Visible is whether the row is retrieved at the guarantee timestamp
+	Visible bool
+	// DeleteTss are the timestamps of buffered deletes of the row in ascending order,
+	// the deletes before DeleteBufferSafeTs are evicted from buffer and not reported
+	DeleteTss          []uint64
+	DeleteBufferSafeTs uint64
+	// MaskingDeleteTs is the latest buffered delete applied to reads at the guarantee timestamp, zero if none
+	MaskingDeleteTs uint64
+}
+
+// TraceRowDelete reports whether the row of pk exists in the segments loaded by the delegators of collection,
+// and the buffered deletes of it, for diagnosing rows expected but missing from results.
+// The row is retrieved at the guarantee timestamp, or the latest one if zero.
+func (node *QueryNode) TraceRowDelete(ctx context.Context, collectionID int64, pk *schemapb.IDs, guaranteeTs uint64) ([]*RowDeleteTrace, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", collectionID),
+		zap.Uint64("guaranteeTs", guaranteeTs),
+	)
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	if size := typeutil.GetSizeOfIDs(pk); size != 1 {
+		return nil, merr.WrapErrParameterInvalid(1, size, "exactly one pk to trace")
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+	plan, err := pkTermPlan(pkField, pk)
+	if err != nil {
+		return nil, err
+	}
+	plan.OutputFieldIds = []int64{pkField.GetFieldID()}
+	serializedPlan, err := proto.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+	primaryKey := storage.ParseIDs2PrimaryKeys(pk)[0]
+	mvccTs := guaranteeTs
+	if mvccTs == 0 {
+		mvccTs = typeutil.MaxTimestamp
+	}
+
+	delegators := make([]delegator.ShardDelegator, 0)
+	channels := make([]string, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() == collectionID {
+			delegators = append(delegators, sd)
+			channels = append(channels, channel)
+		}
+		return true
+	})
+	if len(channels) == 0 {
+		return nil, merr.WrapErrChannelNotFound(fmt.Sprintf("delegators of collection %d", collectionID))
+	}
+
+	traces := make([]*RowDeleteTrace, 0, len(channels))
+	for i, sd := range delegators {
+		rows, err := node.queryDelegatorForFilter(ctx, sd, &querypb.QueryRequest{
+			Req: &internalpb.RetrieveRequest{
+				Base:               &commonpb.MsgBase{},
+				CollectionID:       collectionID,
+				SerializedExprPlan: serializedPlan,
+				OutputFieldsId:     plan.GetOutputFieldIds(),
+				MvccTimestamp:      mvccTs,
+				GuaranteeTimestamp: guaranteeTs,
+				Limit:              typeutil.Unlimited,
+			},
+			DmlChannels: []string{channels[i]},
+			Scope:       querypb.DataScope_All,
+		}, collection.Schema())
+		if err != nil {
+			log.Warn("failed to retrieve row to trace", zap.String("channel", channels[i]), zap.Error(err))
+			return nil, err
+		}
+
+		deleteTrace := sd.GetPKDeleteTrace(primaryKey)
+		trace := &RowDeleteTrace{
+			Channel:            channels[i],
+			CandidateSegments:  deleteTrace.CandidateSegments,
+			Visible:            typeutil.GetSizeOfIDs(rows.GetIds()) > 0,
+			DeleteTss:          deleteTrace.DeleteTss,
+			DeleteBufferSafeTs: deleteTrace.DeleteBufferSafeTs,
+		}
+		for _, ts := range trace.DeleteTss {
+			if ts <= mvccTs {
+				trace.MaskingDeleteTs = ts
+			}
+		}
+		traces = append(traces, trace)
+	}
+	sort.Slice(traces, func(i, j int) bool { return traces[i].Channel < traces[j].Channel })
+	return traces, nil
+}
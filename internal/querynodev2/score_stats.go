@@ -0,0 +1,60 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// applyScoreStatsOnly drops the output fields from the search request if only score statistics requested,
+// so that no field of hits is retrieved since the hits are not returned at all.
+// The request is cloned if any field dropped, since it's shared among channels.
+func applyScoreStatsOnly(req *querypb.SearchRequest) (*querypb.SearchRequest, error) {
+	if !req.GetReq().GetScoreStatsOnly() {
+		return req, nil
+	}
+	switch {
+	case req.GetReq().GetIsIterator():
+		return nil, merr.WrapErrParameterInvalidMsg("score statistics only search could not be iterated")
+	case req.GetReq().GetFacet() != nil:
+		return nil, merr.WrapErrParameterInvalidMsg("score statistics only search could not be bucketed by facet")
+	case req.GetReq().GetPerPartitionTopk():
+		return nil, merr.WrapErrParameterInvalidMsg("score statistics only search could not keep topK per partition")
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	if len(plan.GetOutputFieldIds()) == 0 && len(req.GetReq().GetOutputFieldsId()) == 0 {
+		return req, nil
+	}
+	plan.OutputFieldIds = nil
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.OutputFieldsId = nil
+	return cloned, nil
+}
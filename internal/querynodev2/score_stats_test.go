@@ -0,0 +1,76 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestApplyScoreStatsOnly(t *testing.T) {
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{FieldId: 107, QueryInfo: &planpb.QueryInfo{Topk: 10}},
+		},
+		OutputFieldIds: []int64{100, 101},
+	})
+	assert.NoError(t, err)
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			SerializedExprPlan: plan,
+			OutputFieldsId:     []int64{100, 101},
+		},
+	}
+
+	// not requested
+	applied, err := applyScoreStatsOnly(req)
+	assert.NoError(t, err)
+	assert.Same(t, req, applied)
+
+	// output fields dropped from cloned request
+	req.Req.ScoreStatsOnly = true
+	applied, err = applyScoreStatsOnly(req)
+	assert.NoError(t, err)
+	assert.NotSame(t, req, applied)
+	assert.Empty(t, applied.GetReq().GetOutputFieldsId())
+	appliedPlan := planpb.PlanNode{}
+	assert.NoError(t, proto.Unmarshal(applied.GetReq().GetSerializedExprPlan(), &appliedPlan))
+	assert.Empty(t, appliedPlan.GetOutputFieldIds())
+	assert.EqualValues(t, 10, appliedPlan.GetVectorAnns().GetQueryInfo().GetTopk())
+	assert.Equal(t, []int64{100, 101}, req.GetReq().GetOutputFieldsId())
+
+	// no output field
+	again, err := applyScoreStatsOnly(applied)
+	assert.NoError(t, err)
+	assert.Same(t, applied, again)
+
+	// hits required
+	req.Req.IsIterator = true
+	_, err = applyScoreStatsOnly(req)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	req.Req.IsIterator = false
+	req.Req.PerPartitionTopk = true
+	_, err = applyScoreStatsOnly(req)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
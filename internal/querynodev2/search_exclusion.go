@@ -0,0 +1,64 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// applySearchExclusion widens the topK of search request by the number of excluded pks,
+// so segments and workers still return enough hits after the excluded ones dropped by the final reduce.
+// The request is cloned since it's shared among channels.
+func applySearchExclusion(req *querypb.SearchRequest) (*querypb.SearchRequest, error) {
+	excludedNum := int64(typeutil.GetSizeOfIDs(req.GetReq().GetExcludePks()))
+	if excludedNum == 0 {
+		return req, nil
+	}
+	if maxNum := paramtable.Get().QueryNodeCfg.MaxSearchExcludedPKs.GetAsInt64(); excludedNum > maxNum {
+		return nil, merr.WrapErrParameterInvalid(maxNum, excludedNum, "too many excluded pks")
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	queryInfo := plan.GetVectorAnns().GetQueryInfo()
+	if queryInfo == nil {
+		return nil, merr.WrapErrParameterInvalidMsg("search exclusion requires vector search plan")
+	}
+	queryInfo.Topk += excludedNum
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.Topk += excludedNum
+	return cloned, nil
+}
+
+// excludedReduceTopK returns the topK to reduce results with, before widened by applySearchExclusion.
+func excludedReduceTopK(req *querypb.SearchRequest) int64 {
+	return req.GetReq().GetTopk() - int64(typeutil.GetSizeOfIDs(req.GetReq().GetExcludePks()))
+}
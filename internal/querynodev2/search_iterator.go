@@ -0,0 +1,165 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	radiusKey      = "radius"
+	rangeFilterKey = "range_filter"
+)
+
+// applyIteratorToken resumes the search from the iterator token of request,
+// the hits after the last score are searched by range, with topK widened by the tied hits returned before.
+// The range is open on the far side unless the request sets a radius,
+// so each page after the first scans every row of the index that is worse than the last hit,
+// the cost of later pages grows toward a brute force search. Callers could bound it by radius.
+// The request is cloned since it's shared among channels.
+func applyIteratorToken(req *querypb.SearchRequest) (*querypb.SearchRequest, *typeutil2.IteratorToken, error) {
+	token, err := typeutil2.ParseIteratorToken(req.GetReq().GetIteratorToken())
+	if err != nil || token == nil {
+		return req, nil, err
+	}
+	if !strings.EqualFold(token.MetricType, req.GetReq().GetMetricType()) {
+		return nil, nil, merr.WrapErrParameterInvalid(req.GetReq().GetMetricType(), token.MetricType, "metric type of iterator token mismatches the request")
+	}
+	if req.GetReq().GetNq() != 1 {
+		return nil, nil, merr.WrapErrParameterInvalid(int64(1), req.GetReq().GetNq(), "search iterator supports nq 1 only")
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	queryInfo := plan.GetVectorAnns().GetQueryInfo()
+	if queryInfo == nil {
+		return nil, nil, merr.WrapErrParameterInvalidMsg("search iterator requires vector search plan")
+	}
+	params := make(map[string]any)
+	if len(queryInfo.GetSearchParams()) > 0 {
+		if err := json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params); err != nil {
+			return nil, nil, merr.WrapErrParameterInvalid("json search params", queryInfo.GetSearchParams(), err.Error())
+		}
+	}
+	// range filter is inclusive, so hits tied with the last one are searched again,
+	// radius of request is kept to bound the range
+	if metric.PositivelyRelated(token.MetricType) {
+		if _, ok := params[radiusKey]; !ok {
+			params[radiusKey] = -math.MaxFloat32
+		}
+		params[rangeFilterKey] = token.LastScore
+	} else {
+		if _, ok := params[radiusKey]; !ok {
+			params[radiusKey] = math.MaxFloat32
+		}
+		params[rangeFilterKey] = -token.LastScore
+	}
+	searchParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("json marshalable search params", "search params with marshal error", err.Error())
+	}
+	queryInfo.SearchParams = string(searchParams)
+	queryInfo.Topk += token.TiedCount
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	cloned.Req.Topk = queryInfo.GetTopk()
+	return cloned, token, nil
+}
+
+// pageAfterIteratorToken drops the hits returned by previous pages from the result, at most topK hits kept.
+func pageAfterIteratorToken(result *internalpb.SearchResults, token *typeutil2.IteratorToken, topK int64) error {
+	result.TopK = topK
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return err
+	}
+
+	paged := &schemapb.SearchResultData{
+		NumQueries:   data.GetNumQueries(),
+		TopK:         topK,
+		Ids:          &schemapb.IDs{},
+		FieldsData:   make([]*schemapb.FieldData, len(data.GetFieldsData())),
+		Topks:        make([]int64, len(data.GetTopks())),
+		OutputFields: data.GetOutputFields(),
+	}
+	for i := 0; i < typeutil.GetSizeOfIDs(data.GetIds()) && int64(len(paged.GetScores())) < topK; i++ {
+		pk := typeutil.GetPK(data.GetIds(), int64(i))
+		if token.Before(data.GetScores()[i], pk) {
+			continue
+		}
+		typeutil.AppendPKs(paged.Ids, pk)
+		typeutil.AppendFieldData(paged.FieldsData, data.GetFieldsData(), int64(i))
+		paged.Scores = append(paged.Scores, data.GetScores()[i])
+	}
+	if len(paged.Topks) > 0 {
+		paged.Topks[0] = int64(len(paged.GetScores()))
+	}
+
+	result.Topks = paged.GetTopks()
+	if len(paged.GetScores()) == 0 {
+		result.SlicedBlob = nil
+		return nil
+	}
+	slicedBlob, err := proto.Marshal(paged)
+	if err != nil {
+		return err
+	}
+	result.SlicedBlob = slicedBlob
+	return nil
+}
+
+// fillNextIteratorToken sets the token to resume after the last hit of result,
+// no token is set if nothing found, which means the iteration ends.
+func fillNextIteratorToken(result *internalpb.SearchResults, prev *typeutil2.IteratorToken, metricType string) error {
+	result.NextIteratorToken = nil
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return err
+	}
+	token, err := typeutil2.NextIteratorToken(data.GetIds(), data.GetScores(), prev, metricType)
+	if err != nil {
+		return err
+	}
+	result.NextIteratorToken = token
+	return nil
+}
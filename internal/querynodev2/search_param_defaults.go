@@ -0,0 +1,127 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// SearchParamDefaults is the default search params of collection, e.g. ef or nprobe,
+// applied to the search requests leaving them unset.
+type SearchParamDefaults struct {
+	CollectionID int64
+	Params       map[string]any
+	UpdatedAt    time.Time
+}
+
+// searchParamDefaultsRegistry keeps the default search params of collections.
+type searchParamDefaultsRegistry struct {
+	defaults *typeutil.ConcurrentMap[int64, *SearchParamDefaults]
+}
+
+func newSearchParamDefaultsRegistry() *searchParamDefaultsRegistry {
+	return &searchParamDefaultsRegistry{
+		defaults: typeutil.NewConcurrentMap[int64, *SearchParamDefaults](),
+	}
+}
+
+// set replaces the default search params of collection, empty params remove the defaults.
+func (r *searchParamDefaultsRegistry) set(collectionID int64, params map[string]any) {
+	if len(params) == 0 {
+		r.defaults.Remove(collectionID)
+		return
+	}
+	copied := make(map[string]any, len(params))
+	for key, value := range params {
+		copied[key] = value
+	}
+	r.defaults.Insert(collectionID, &SearchParamDefaults{
+		CollectionID: collectionID,
+		Params:       copied,
+		UpdatedAt:    time.Now(),
+	})
+}
+
+func (r *searchParamDefaultsRegistry) get(collectionID int64) (*SearchParamDefaults, bool) {
+	return r.defaults.Get(collectionID)
+}
+
+func (r *searchParamDefaultsRegistry) list() []*SearchParamDefaults {
+	result := make([]*SearchParamDefaults, 0, r.defaults.Len())
+	r.defaults.Range(func(_ int64, defaults *SearchParamDefaults) bool {
+		result = append(result, defaults)
+		return true
+	})
+	return result
+}
+
+// apply fills the default search params of collection into the search plan,
+// params set by the request are kept.
+func (r *searchParamDefaultsRegistry) apply(req *querypb.SearchRequest) (*querypb.SearchRequest, error) {
+	defaults, ok := r.get(req.GetReq().GetCollectionID())
+	if !ok {
+		return req, nil
+	}
+
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized search plan", "no unmarshalable one", err.Error())
+	}
+	queryInfo := plan.GetVectorAnns().GetQueryInfo()
+	if queryInfo == nil {
+		return req, nil
+	}
+
+	params := make(map[string]any)
+	if len(queryInfo.GetSearchParams()) > 0 {
+		if err := json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params); err != nil {
+			return nil, merr.WrapErrParameterInvalid("json search params", queryInfo.GetSearchParams(), err.Error())
+		}
+	}
+	applied := false
+	for key, value := range defaults.Params {
+		if _, ok := params[key]; !ok {
+			params[key] = value
+			applied = true
+		}
+	}
+	if !applied {
+		return req, nil
+	}
+
+	searchParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("json marshalable search params", "search params with marshal error", err.Error())
+	}
+	queryInfo.SearchParams = string(searchParams)
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable search plan", "plan with marshal error", err.Error())
+	}
+	// request is shared by the searches of channels, keep it intact
+	cloned := proto.Clone(req).(*querypb.SearchRequest)
+	cloned.Req.SerializedExprPlan = serializedExprPlan
+	return cloned, nil
+}
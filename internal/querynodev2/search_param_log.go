@@ -0,0 +1,137 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// AppliedSearchParams is the search params of a search before and after optimized by query hook, and its latency.
+type AppliedSearchParams struct {
+	CollectionID    int64
+	Time            time.Time
+	RequestedTopK   int64
+	RequestedParams string
+	// applied ones are effective in the executed search, which may be adjusted further by the node, e.g. topK capped
+	AppliedTopK   int64
+	AppliedParams string
+	// Latency of searching the channel, including the hook
+	Latency time.Duration
+}
+
+// searchParamLog keeps the latest search params applied by query hook of each collection,
+// in ring buffers bounded by queryNode.searchParamLog.sizePerCollection.
+type searchParamLog struct {
+	mu      sync.Mutex
+	records map[int64][]*AppliedSearchParams
+	// next is the position to write of the ring buffer of each collection
+	next map[int64]int
+}
+
+func newSearchParamLog() *searchParamLog {
+	return &searchParamLog{
+		records: make(map[int64][]*AppliedSearchParams),
+		next:    make(map[int64]int),
+	}
+}
+
+// record logs the search params of the requested and optimized search plans,
+// the plans are decoded only if the log enabled, to keep it off the search path otherwise.
+func (l *searchParamLog) record(collectionID int64, requestedPlan []byte, appliedPlan []byte, latency time.Duration) {
+	size := paramtable.Get().QueryNodeCfg.SearchParamLogSize.GetAsInt()
+	if size <= 0 {
+		return
+	}
+	requested, applied := planpb.PlanNode{}, planpb.PlanNode{}
+	if proto.Unmarshal(requestedPlan, &requested) != nil || proto.Unmarshal(appliedPlan, &applied) != nil {
+		return
+	}
+	entry := &AppliedSearchParams{
+		CollectionID:    collectionID,
+		Time:            time.Now(),
+		RequestedTopK:   requested.GetVectorAnns().GetQueryInfo().GetTopk(),
+		RequestedParams: requested.GetVectorAnns().GetQueryInfo().GetSearchParams(),
+		AppliedTopK:     applied.GetVectorAnns().GetQueryInfo().GetTopk(),
+		AppliedParams:   applied.GetVectorAnns().GetQueryInfo().GetSearchParams(),
+		Latency:         latency,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	records := l.records[collectionID]
+	if len(records) > size {
+		// size shrunk, keep the latest ones from the oldest
+		records = lo.Reverse(l.latest(collectionID, size))
+		l.next[collectionID] = 0
+	}
+	if len(records) < size {
+		l.records[collectionID] = append(records, entry)
+		l.next[collectionID] = len(records) + 1
+		return
+	}
+	next := l.next[collectionID] % size
+	records[next] = entry
+	l.records[collectionID] = records
+	l.next[collectionID] = next + 1
+}
+
+// latest returns at most n records of collection from the latest, all records if n <= 0.
+// The caller must hold the lock.
+func (l *searchParamLog) latest(collectionID int64, n int) []*AppliedSearchParams {
+	records := l.records[collectionID]
+	if n <= 0 || n > len(records) {
+		n = len(records)
+	}
+	result := make([]*AppliedSearchParams, 0, n)
+	for i := 0; i < n; i++ {
+		pos := (l.next[collectionID] - 1 - i + 2*len(records)) % len(records)
+		result = append(result, records[pos])
+	}
+	return result
+}
+
+func (l *searchParamLog) list(collectionID int64, n int) []AppliedSearchParams {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records := l.latest(collectionID, n)
+	result := make([]AppliedSearchParams, 0, len(records))
+	for _, record := range records {
+		result = append(result, *record)
+	}
+	return result
+}
+
+// GetAppliedSearchParams returns the search params applied by query hook to the latest n searches of collection,
+// from the latest, with their requested params and latency. All buffered ones are returned if n <= 0.
+func (node *QueryNode) GetAppliedSearchParams(ctx context.Context, collectionID int64, n int) ([]AppliedSearchParams, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	return node.searchParamLog.list(collectionID, n), nil
+}
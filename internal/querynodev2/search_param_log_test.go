@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func searchPlanWithParams(t *testing.T, topK int64, params string) []byte {
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				QueryInfo: &planpb.QueryInfo{Topk: topK, SearchParams: params},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return plan
+}
+
+func TestSearchParamLog(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.QueryNodeCfg.SearchParamLogSize.Key, "3")
+	defer params.Reset(params.QueryNodeCfg.SearchParamLogSize.Key)
+
+	log := newSearchParamLog()
+	requested := searchPlanWithParams(t, 10, `{"ef": 10}`)
+	for i := 1; i <= 5; i++ {
+		log.record(100, requested, searchPlanWithParams(t, 10, fmt.Sprintf(`{"ef": %d}`, i*100)), time.Duration(i)*time.Millisecond)
+	}
+	log.record(101, requested, requested, time.Millisecond)
+
+	// bounded per collection, from the latest
+	records := log.list(100, 0)
+	assert.Equal(t, []string{`{"ef": 500}`, `{"ef": 400}`, `{"ef": 300}`}, lo.Map(records, func(record AppliedSearchParams, _ int) string {
+		return record.AppliedParams
+	}))
+	assert.Equal(t, `{"ef": 10}`, records[0].RequestedParams)
+	assert.EqualValues(t, 10, records[0].AppliedTopK)
+	assert.Equal(t, 5*time.Millisecond, records[0].Latency)
+	assert.Len(t, log.list(100, 2), 2)
+	assert.Len(t, log.list(101, 0), 1)
+	assert.Empty(t, log.list(102, 0))
+
+	// size shrunk
+	params.Save(params.QueryNodeCfg.SearchParamLogSize.Key, "2")
+	log.record(100, requested, searchPlanWithParams(t, 10, `{"ef": 600}`), time.Millisecond)
+	assert.Equal(t, []string{`{"ef": 600}`, `{"ef": 500}`}, lo.Map(log.list(100, 0), func(record AppliedSearchParams, _ int) string {
+		return record.AppliedParams
+	}))
+
+	// disabled
+	params.Save(params.QueryNodeCfg.SearchParamLogSize.Key, "0")
+	log.record(102, requested, requested, time.Millisecond)
+	assert.Empty(t, log.list(102, 0))
+}
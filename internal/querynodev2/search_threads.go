@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// SearchShape describes a search to estimate thread usage for.
+type SearchShape struct {
+	CollectionID int64
+	// PartitionIDs limits the segments to the partitions, empty means all partitions
+	PartitionIDs []int64
+	// SegmentIDs limits the segments to the given ones, empty means all segments
+	SegmentIDs []int64
+	Scope      querypb.DataScope
+}
+
+// SearchThreadEstimate is the estimated cpu threads a search would use on the node.
+type SearchThreadEstimate struct {
+	CollectionID int64
+	Segments     int
+	// ConcurrentSegments is the number of segments searched at once under current load
+	ConcurrentSegments int
+	// Threads is the effective threads of the search under current load
+	Threads int64
+	// PeakThreads is the threads of the search if the node were idle
+	PeakThreads int64
+
+	PoolCap  int
+	PoolFree int
+}
+
+// estimateSearchThreads mirrors how segments of a search are executed: each segment search occupies a worker
+// of the search/query pool while calling segcore, segments unable to get a worker wait for the running ones.
+// It returns the segments searched at once and the threads they use.
+func estimateSearchThreads(segmentNum int, workers int) (int, int64) {
+	concurrent := lo.Min([]int{segmentNum, workers})
+	return concurrent, int64(concurrent)
+}
+
+// EstimateSearchThreads estimates the cpu threads the node would devote to a search of the shape,
+// with the search/query pool the executor applies.
+// The estimate under current load uses the free workers of the pool,
+// at least one segment is regarded as running since a queued search gets a worker eventually.
+func (node *QueryNode) EstimateSearchThreads(ctx context.Context, shape *SearchShape) (*SearchThreadEstimate, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	if node.manager.Collection.Get(shape.CollectionID) == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(shape.CollectionID)
+	}
+
+	filters := []segments.SegmentFilter{segments.WithCollection(shape.CollectionID)}
+	switch shape.Scope {
+	case querypb.DataScope_Historical:
+		filters = append(filters, segments.WithType(segments.SegmentTypeSealed))
+	case querypb.DataScope_Streaming:
+		filters = append(filters, segments.WithType(segments.SegmentTypeGrowing))
+	}
+	segmentNum := 0
+	for _, segment := range node.manager.Segment.GetBy(filters...) {
+		if len(shape.PartitionIDs) > 0 && !lo.Contains(shape.PartitionIDs, segment.Partition()) {
+			continue
+		}
+		if len(shape.SegmentIDs) > 0 && !lo.Contains(shape.SegmentIDs, segment.ID()) {
+			continue
+		}
+		segmentNum++
+	}
+
+	pool := segments.GetSQPool()
+	estimate := &SearchThreadEstimate{
+		CollectionID: shape.CollectionID,
+		Segments:     segmentNum,
+		PoolCap:      pool.Cap(),
+		PoolFree:     pool.Free(),
+	}
+	_, estimate.PeakThreads = estimateSearchThreads(segmentNum, estimate.PoolCap)
+	estimate.ConcurrentSegments, estimate.Threads = estimateSearchThreads(segmentNum, lo.Max([]int{estimate.PoolFree, 1}))
+	return estimate, nil
+}
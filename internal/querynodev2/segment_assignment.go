@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// SegmentAssignment is the worker node serving a sealed segment in the distribution of delegator.
+type SegmentAssignment struct {
+	SegmentID   int64
+	PartitionID int64
+	NodeID      int64
+	Version     int64
+}
+
+// ChannelSegmentAssignment is the segment to worker node mapping of a delegator.
+type ChannelSegmentAssignment struct {
+	Channel      string
+	CollectionID int64
+	// LeaderNodeID is the node of the delegator, which fans out searches to the workers
+	LeaderNodeID int64
+	Segments     []SegmentAssignment
+	// SegmentNumPerNode is the number of sealed segments served by each worker node
+	SegmentNumPerNode map[int64]int
+	// MaxNodeShare is the fraction of sealed segments served by the busiest worker node,
+	// 1 if all on one node, which is a hotspot if the collection has multiple workers
+	MaxNodeShare float64
+}
+
+// newChannelSegmentAssignment builds the segment assignment of channel from the sealed distribution of delegator.
+func newChannelSegmentAssignment(channel string, collectionID int64, sealed []delegator.SnapshotItem) *ChannelSegmentAssignment {
+	assignment := &ChannelSegmentAssignment{
+		Channel:           channel,
+		CollectionID:      collectionID,
+		LeaderNodeID:      paramtable.GetNodeID(),
+		Segments:          make([]SegmentAssignment, 0),
+		SegmentNumPerNode: make(map[int64]int),
+	}
+	for _, item := range sealed {
+		for _, segment := range item.Segments {
+			assignment.Segments = append(assignment.Segments, SegmentAssignment{
+				SegmentID:   segment.SegmentID,
+				PartitionID: segment.PartitionID,
+				NodeID:      item.NodeID,
+				Version:     segment.Version,
+			})
+			assignment.SegmentNumPerNode[item.NodeID]++
+		}
+	}
+	sort.Slice(assignment.Segments, func(i, j int) bool {
+		return assignment.Segments[i].SegmentID < assignment.Segments[j].SegmentID
+	})
+	if len(assignment.Segments) > 0 {
+		assignment.MaxNodeShare = float64(lo.Max(lo.Values(assignment.SegmentNumPerNode))) / float64(len(assignment.Segments))
+	}
+	return assignment
+}
+
+// GetSegmentAssignments reports, for each channel served as delegator, which worker node serves each sealed segment
+// in the distribution, to tell whether the segments of a channel are poorly distributed, e.g. all on one worker
+// causing a hotspot and the cross node fan-out cost of searches.
+func (node *QueryNode) GetSegmentAssignments(ctx context.Context) ([]*ChannelSegmentAssignment, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	assignments := make([]*ChannelSegmentAssignment, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		sealed, _ := sd.GetSegmentInfo(false)
+		assignments = append(assignments, newChannelSegmentAssignment(channel, sd.Collection(), sealed))
+		return true
+	})
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].Channel < assignments[j].Channel
+	})
+	return assignments, nil
+}
@@ -25,10 +25,13 @@ package segments
 import "C"
 
 import (
+	"hash/fnv"
+	"strconv"
 	"sync"
 	"unsafe"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
@@ -36,10 +39,13 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/proto/segcorepb"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 type CollectionManager interface {
+	// List returns the IDs of loaded collections
+	List() []int64
 	Get(collectionID int64) *Collection
 	PutOrRef(collectionID int64, schema *schemapb.CollectionSchema, meta *segcorepb.CollectionIndexMeta, loadMeta *querypb.LoadMetaInfo)
 	Ref(collectionID int64, count uint32) bool
@@ -60,6 +66,13 @@ func NewCollectionManager() *collectionManager {
 	}
 }
 
+func (m *collectionManager) List() []int64 {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	return lo.Keys(m.collections)
+}
+
 func (m *collectionManager) Get(collectionID int64) *Collection {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
@@ -121,6 +134,13 @@ type Collection struct {
 	loadType      querypb.LoadType
 	metricType    atomic.String
 	schema        *schemapb.CollectionSchema
+	schemaVersion string
+	// index meta assigned by coordinator, nil if no index
+	indexMeta *segcorepb.CollectionIndexMeta
+	// empty to follow the node default, see paramtable queryNode.deleteApplyMode
+	deleteApplyMode atomic.String
+	// empty to follow the node default, see MmapPolicyDefault
+	mmapPolicy atomic.String
 
 	refCount *atomic.Uint32
 }
@@ -135,6 +155,28 @@ func (c *Collection) Schema() *schemapb.CollectionSchema {
 	return c.schema
 }
 
+// SchemaVersion returns the version of collection schema, see SchemaVersion.
+func (c *Collection) SchemaVersion() string {
+	return c.schemaVersion
+}
+
+// SchemaVersion returns the fingerprint of schema as its version,
+// schema doesn't carry a version number, so any change of fields or properties produces a new version.
+func SchemaVersion(schema *schemapb.CollectionSchema) string {
+	return schemaVersionOfBlob(proto.MarshalTextString(schema))
+}
+
+func schemaVersionOfBlob(blob string) string {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(blob))
+	return strconv.FormatUint(hasher.Sum64(), 16)
+}
+
+// IndexMeta returns the index meta of collection assigned by coordinator
+func (c *Collection) IndexMeta() *segcorepb.CollectionIndexMeta {
+	return c.indexMeta
+}
+
 // getPartitionIDs return partitionIDs of collection
 func (c *Collection) GetPartitions() []int64 {
 	return c.partitions.Collect()
@@ -171,6 +213,19 @@ func (c *Collection) GetMetricType() string {
 	return c.metricType.Load()
 }
 
+// SetDeleteApplyMode overrides the delete apply mode of collection, empty mode to follow the node default.
+func (c *Collection) SetDeleteApplyMode(mode string) {
+	c.deleteApplyMode.Store(mode)
+}
+
+// GetDeleteApplyMode returns the delete apply mode of collection, eager or lazy.
+func (c *Collection) GetDeleteApplyMode() string {
+	if mode := c.deleteApplyMode.Load(); mode != "" {
+		return mode
+	}
+	return paramtable.Get().QueryNodeCfg.DeleteApplyMode.GetValue()
+}
+
 func (c *Collection) Ref(count uint32) uint32 {
 	refCount := c.refCount.Add(count)
 	log.Debug("collection ref increment",
@@ -211,6 +266,8 @@ func NewCollection(collectionID int64, schema *schemapb.CollectionSchema, indexM
 		collectionPtr: collection,
 		id:            collectionID,
 		schema:        schema,
+		schemaVersion: schemaVersionOfBlob(schemaBlob),
+		indexMeta:     indexMeta,
 		partitions:    typeutil.NewConcurrentSet[int64](),
 		loadType:      loadType,
 		refCount:      atomic.NewUint32(0),
@@ -0,0 +1,212 @@
+package segments
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/bits"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/segcorepb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	hllPrecision = 14
+	hllRegisters = 1 << hllPrecision
+)
+
+// HyperLogLog is a sketch estimating the distinct count of values,
+// sketches are merged by taking the max of each register, so they could be reduced level by level.
+type HyperLogLog struct {
+	registers []uint8
+}
+
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{
+		registers: make([]uint8, hllRegisters),
+	}
+}
+
+// HyperLogLogFromBytes restores a sketch serialized by Bytes.
+func HyperLogLogFromBytes(data []byte) (*HyperLogLog, error) {
+	if len(data) != hllRegisters {
+		return nil, merr.WrapErrParameterInvalid(hllRegisters, len(data), "invalid hyperloglog sketch size")
+	}
+	return &HyperLogLog{
+		registers: append([]uint8(nil), data...),
+	}, nil
+}
+
+func (h *HyperLogLog) addHash(hash uint64) {
+	idx := hash >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// Add adds a value into the sketch.
+func (h *HyperLogLog) Add(value []byte) {
+	hasher := fnv.New64a()
+	hasher.Write(value)
+	// fnv hash is not well distributed in high bits, mix it before use
+	hash := hasher.Sum64()
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+	h.addHash(hash)
+}
+
+// Merge merges other sketch into this one.
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	for i, rank := range other.registers {
+		if rank > h.registers[i] {
+			h.registers[i] = rank
+		}
+	}
+}
+
+func (h *HyperLogLog) Bytes() []byte {
+	return append([]byte(nil), h.registers...)
+}
+
+// Estimate returns the approximate distinct count.
+func (h *HyperLogLog) Estimate() int64 {
+	m := float64(hllRegisters)
+	sum := 0.0
+	zeros := 0
+	for _, rank := range h.registers {
+		sum += 1.0 / float64(uint64(1)<<rank)
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// linear counting for small cardinality
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int64(math.Round(estimate))
+}
+
+// RelativeError returns the relative standard error of the estimate.
+func (h *HyperLogLog) RelativeError() float64 {
+	return 1.04 / math.Sqrt(hllRegisters)
+}
+
+// addFieldData adds all values of scalar field data into the sketch.
+func (h *HyperLogLog) addFieldData(field *schemapb.FieldData) error {
+	buf := make([]byte, 8)
+	addUint64 := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf, v)
+		h.Add(buf)
+	}
+
+	scalars := field.GetScalars()
+	switch field.GetType() {
+	case schemapb.DataType_Bool:
+		for _, v := range scalars.GetBoolData().GetData() {
+			addUint64(uint64(lo.Ternary(v, 1, 0)))
+		}
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		for _, v := range scalars.GetIntData().GetData() {
+			addUint64(uint64(v))
+		}
+	case schemapb.DataType_Int64:
+		for _, v := range scalars.GetLongData().GetData() {
+			addUint64(uint64(v))
+		}
+	case schemapb.DataType_Float:
+		for _, v := range scalars.GetFloatData().GetData() {
+			addUint64(uint64(math.Float32bits(v)))
+		}
+	case schemapb.DataType_Double:
+		for _, v := range scalars.GetDoubleData().GetData() {
+			addUint64(math.Float64bits(v))
+		}
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		for _, v := range scalars.GetStringData().GetData() {
+			h.Add([]byte(v))
+		}
+	case schemapb.DataType_JSON:
+		for _, v := range scalars.GetJsonData().GetData() {
+			h.Add(v)
+		}
+	default:
+		return merr.WrapErrParameterInvalidMsg("distinct count is not supported on field %d of type %s", field.GetFieldId(), field.GetType().String())
+	}
+	return nil
+}
+
+// SketchDistinct builds the hyperloglog sketch of field from the results of segments,
+// values of each segment are sketched separately and merged, so the rows are not merged by pk.
+func SketchDistinct(results []*segcorepb.RetrieveResults, fieldID int64) (*HyperLogLog, error) {
+	sketch := NewHyperLogLog()
+	for _, result := range results {
+		if result == nil || len(result.GetFieldsData()) == 0 {
+			continue
+		}
+		field, ok := lo.Find(result.GetFieldsData(), func(field *schemapb.FieldData) bool {
+			return field.GetFieldId() == fieldID
+		})
+		if !ok {
+			return nil, merr.WrapErrFieldNotFound(fieldID, "distinct count field not retrieved")
+		}
+		segmentSketch := NewHyperLogLog()
+		if err := segmentSketch.addFieldData(field); err != nil {
+			return nil, err
+		}
+		sketch.Merge(segmentSketch)
+	}
+	return sketch, nil
+}
+
+// distinctReducer merges the distinct sketches of results and estimates the distinct count.
+type distinctReducer struct{}
+
+func (r *distinctReducer) Reduce(ctx context.Context, results []*internalpb.RetrieveResults) (*internalpb.RetrieveResults, error) {
+	sketch := NewHyperLogLog()
+	for _, result := range results {
+		if len(result.GetDistinctSketch()) == 0 {
+			continue
+		}
+		other, err := HyperLogLogFromBytes(result.GetDistinctSketch())
+		if err != nil {
+			return nil, err
+		}
+		sketch.Merge(other)
+	}
+
+	requestCosts := lo.FilterMap(results, func(result *internalpb.RetrieveResults, _ int) (*internalpb.CostAggregation, bool) {
+		if paramtable.Get().QueryNodeCfg.EnableWorkerSQCostMetrics.GetAsBool() {
+			return result.GetCostAggregation(), true
+		}
+
+		if result.GetBase().GetSourceID() == paramtable.GetNodeID() {
+			return result.GetCostAggregation(), true
+		}
+
+		return nil, false
+	})
+
+	return &internalpb.RetrieveResults{
+		Status:             merr.Success(),
+		Ids:                &schemapb.IDs{},
+		DistinctSketch:     sketch.Bytes(),
+		DistinctCount:      sketch.Estimate(),
+		DistinctCountError: sketch.RelativeError(),
+		CostAggregation:    mergeRequestCost(requestCosts),
+	}, nil
+}
+
+func newDistinctReducer() *distinctReducer {
+	return &distinctReducer{}
+}
@@ -0,0 +1,123 @@
+package segments
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/segcorepb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type DistinctReducerSuite struct {
+	suite.Suite
+}
+
+func (suite *DistinctReducerSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func TestDistinctReducerSuite(t *testing.T) {
+	suite.Run(t, new(DistinctReducerSuite))
+}
+
+func (suite *DistinctReducerSuite) genLongField(values []int64) *schemapb.FieldData {
+	return &schemapb.FieldData{
+		Type:    schemapb.DataType_Int64,
+		FieldId: common.StartOfUserFieldID,
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{
+					LongData: &schemapb.LongArray{Data: values},
+				},
+			},
+		},
+	}
+}
+
+func (suite *DistinctReducerSuite) assertEstimate(expected int64, sketch *HyperLogLog) {
+	bound := 3 * sketch.RelativeError() * float64(expected)
+	suite.LessOrEqual(math.Abs(float64(sketch.Estimate()-expected)), math.Max(bound, 1))
+}
+
+func (suite *DistinctReducerSuite) TestHyperLogLog() {
+	sketch := NewHyperLogLog()
+	suite.EqualValues(0, sketch.Estimate())
+
+	for i := 0; i < 100000; i++ {
+		sketch.Add([]byte(fmt.Sprint(i % 50000)))
+	}
+	suite.assertEstimate(50000, sketch)
+
+	// merged sketch counts the union
+	other := NewHyperLogLog()
+	for i := 25000; i < 75000; i++ {
+		other.Add([]byte(fmt.Sprint(i)))
+	}
+	sketch.Merge(other)
+	suite.assertEstimate(75000, sketch)
+
+	restored, err := HyperLogLogFromBytes(sketch.Bytes())
+	suite.Require().NoError(err)
+	suite.Equal(sketch.Estimate(), restored.Estimate())
+
+	_, err = HyperLogLogFromBytes([]byte{1, 2, 3})
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *DistinctReducerSuite) TestSketchDistinct() {
+	values := make([]int64, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, int64(i%100))
+	}
+	results := []*segcorepb.RetrieveResults{
+		{FieldsData: []*schemapb.FieldData{suite.genLongField(values)}},
+		{FieldsData: []*schemapb.FieldData{suite.genLongField([]int64{100, 101, 1})}},
+		nil,
+	}
+	sketch, err := SketchDistinct(results, common.StartOfUserFieldID)
+	suite.Require().NoError(err)
+	suite.assertEstimate(102, sketch)
+
+	// field not retrieved
+	_, err = SketchDistinct(results, common.StartOfUserFieldID+1)
+	suite.ErrorIs(err, merr.ErrFieldNotFound)
+
+	// vector field not supported
+	_, err = SketchDistinct([]*segcorepb.RetrieveResults{
+		{FieldsData: []*schemapb.FieldData{{Type: schemapb.DataType_FloatVector, FieldId: common.StartOfUserFieldID}}},
+	}, common.StartOfUserFieldID)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *DistinctReducerSuite) TestReduce() {
+	sketch1 := NewHyperLogLog()
+	sketch2 := NewHyperLogLog()
+	for i := 0; i < 3000; i++ {
+		sketch1.Add([]byte(fmt.Sprint(i)))
+		sketch2.Add([]byte(fmt.Sprint(i + 1000)))
+	}
+
+	r := newDistinctReducer()
+	res, err := r.Reduce(context.TODO(), []*internalpb.RetrieveResults{
+		{DistinctSketch: sketch1.Bytes()},
+		{DistinctSketch: sketch2.Bytes()},
+		{},
+	})
+	suite.Require().NoError(err)
+	merged, err := HyperLogLogFromBytes(res.GetDistinctSketch())
+	suite.Require().NoError(err)
+	suite.assertEstimate(4000, merged)
+	suite.Equal(merged.Estimate(), res.GetDistinctCount())
+	suite.Equal(merged.RelativeError(), res.GetDistinctCountError())
+
+	_, err = r.Reduce(context.TODO(), []*internalpb.RetrieveResults{{DistinctSketch: []byte{1}}})
+	suite.Error(err)
+}
@@ -0,0 +1,153 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type insertionOrderTiebreakKey struct{}
+
+// WithInsertionOrderTiebreak returns a context requesting hits of tied scores ordered by insertion,
+// the earliest inserted first, instead of by primary key.
+// The hits shall carry the insertion timestamp pseudo field, see InsertionTsAnnotator.
+func WithInsertionOrderTiebreak(ctx context.Context, enabled bool) context.Context {
+	if !enabled {
+		return ctx
+	}
+	return context.WithValue(ctx, insertionOrderTiebreakKey{}, true)
+}
+
+// insertionOrderTiebreakFromContext returns whether hits of tied scores are ordered by insertion.
+func insertionOrderTiebreakFromContext(ctx context.Context) bool {
+	enabled, _ := ctx.Value(insertionOrderTiebreakKey{}).(bool)
+	return enabled
+}
+
+// insertionTsOfResults returns the insertion timestamps of hits of each result data,
+// nil for the ones without the insertion timestamp pseudo field.
+func insertionTsOfResults(dataArray []*schemapb.SearchResultData) [][]int64 {
+	return lo.Map(dataArray, func(data *schemapb.SearchResultData, _ int) []int64 {
+		field, ok := lo.Find(data.GetFieldsData(), func(field *schemapb.FieldData) bool {
+			return field.GetFieldId() == common.InsertionTsField
+		})
+		if !ok {
+			return nil
+		}
+		return field.GetScalars().GetLongData().GetData()
+	})
+}
+
+// InsertionTsAnnotator returns the annotator annotating each hit with the insertion timestamp of its row,
+// as the insertion timestamp pseudo field. The timestamps are retrieved from the searched segment by primary key,
+// hits whose rows are not found, e.g. deleted meanwhile, are annotated with 0 and ordered by primary key.
+func InsertionTsAnnotator(ctx context.Context, collection *Collection, searched []Segment) SearchResultAnnotator {
+	segments := lo.SliceToMap(searched, func(segment Segment) (int64, Segment) {
+		return segment.ID(), segment
+	})
+	return func(result *SearchResult, data *schemapb.SearchResultData) error {
+		timestamps := make([]int64, typeutil.GetSizeOfIDs(data.GetIds()))
+		if segment, ok := segments[result.segmentID]; ok && len(timestamps) > 0 {
+			insertionTs, err := retrieveInsertionTs(ctx, collection, segment, data.GetIds())
+			if err != nil {
+				return err
+			}
+			for i := range timestamps {
+				timestamps[i] = insertionTs[typeutil.GetPK(data.GetIds(), int64(i))]
+			}
+		}
+		appendLongPseudoField(data, common.InsertionTsField, common.InsertionTsFieldName, timestamps)
+		return nil
+	}
+}
+
+// retrieveInsertionTs retrieves the insertion timestamps of rows of pks in segment,
+// the latest one is kept if a pk is inserted multiple times.
+func retrieveInsertionTs(ctx context.Context, collection *Collection, segment Segment, pks *schemapb.IDs) (map[any]int64, error) {
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+	values := make([]*planpb.GenericValue, 0, typeutil.GetSizeOfIDs(pks))
+	switch pkField.GetDataType() {
+	case schemapb.DataType_Int64:
+		for _, pk := range pks.GetIntId().GetData() {
+			values = append(values, &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: pk}})
+		}
+	case schemapb.DataType_VarChar:
+		for _, pk := range pks.GetStrId().GetData() {
+			values = append(values, &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: pk}})
+		}
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unsupported primary key type %s", pkField.GetDataType().String())
+	}
+	expr, err := proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{
+					Expr: &planpb.Expr_TermExpr{
+						TermExpr: &planpb.TermExpr{
+							ColumnInfo: &planpb.ColumnInfo{
+								FieldId:      pkField.GetFieldID(),
+								DataType:     pkField.GetDataType(),
+								IsPrimaryKey: true,
+							},
+							Values: values,
+						},
+					},
+				},
+			},
+		},
+		OutputFieldIds: []int64{pkField.GetFieldID(), common.TimeStampField},
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan, err := NewRetrievePlan(collection, expr, typeutil.MaxTimestamp, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer plan.Delete()
+
+	result, err := segment.Retrieve(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	var timestamps []int64
+	if field, ok := lo.Find(result.GetFieldsData(), func(field *schemapb.FieldData) bool {
+		return field.GetFieldId() == common.TimeStampField
+	}); ok {
+		timestamps = field.GetScalars().GetLongData().GetData()
+	}
+	insertionTs := make(map[any]int64, len(timestamps))
+	for i, ts := range timestamps {
+		pk := typeutil.GetPK(result.GetIds(), int64(i))
+		if ts > insertionTs[pk] {
+			insertionTs[pk] = ts
+		}
+	}
+	return insertionTs, nil
+}
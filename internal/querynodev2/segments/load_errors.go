@@ -0,0 +1,92 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"sync"
+	"time"
+)
+
+// SegmentLoadPhase is the phase of loading segment which fails.
+type SegmentLoadPhase string
+
+const (
+	LoadPhaseIndex    SegmentLoadPhase = "index"
+	LoadPhaseDeltaLog SegmentLoadPhase = "deltalog"
+	LoadPhaseGrowing  SegmentLoadPhase = "growing"
+)
+
+// SegmentLoadError is a failure of loading segment.
+type SegmentLoadError struct {
+	SegmentID int64
+	Phase     SegmentLoadPhase
+	Error     string
+	Timestamp time.Time
+}
+
+// loadErrorHistory keeps the recent segment load failures in a ring buffer,
+// the oldest failure is overwritten once full.
+type loadErrorHistory struct {
+	mu     sync.Mutex
+	errors []SegmentLoadError
+	next   int
+	full   bool
+}
+
+func newLoadErrorHistory(size int) *loadErrorHistory {
+	if size < 0 {
+		size = 0
+	}
+	return &loadErrorHistory{
+		errors: make([]SegmentLoadError, size),
+	}
+}
+
+func (h *loadErrorHistory) Record(segmentID int64, phase SegmentLoadPhase, err error) {
+	if err == nil || len(h.errors) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errors[h.next] = SegmentLoadError{
+		SegmentID: segmentID,
+		Phase:     phase,
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	}
+	h.next = (h.next + 1) % len(h.errors)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// List returns the recorded failures, oldest first.
+func (h *loadErrorHistory) List() []SegmentLoadError {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		result := make([]SegmentLoadError, h.next)
+		copy(result, h.errors[:h.next])
+		return result
+	}
+	result := make([]SegmentLoadError, 0, len(h.errors))
+	result = append(result, h.errors[h.next:]...)
+	result = append(result, h.errors[:h.next]...)
+	return result
+}
@@ -0,0 +1,53 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadErrorHistory(t *testing.T) {
+	history := newLoadErrorHistory(3)
+	assert.Empty(t, history.List())
+
+	history.Record(1, LoadPhaseIndex, nil)
+	assert.Empty(t, history.List())
+
+	history.Record(1, LoadPhaseIndex, errors.New("index"))
+	history.Record(2, LoadPhaseDeltaLog, errors.New("deltalog"))
+	errs := history.List()
+	assert.Equal(t, []int64{1, 2}, lo.Map(errs, func(err SegmentLoadError, _ int) int64 { return err.SegmentID }))
+	assert.Equal(t, LoadPhaseDeltaLog, errs[1].Phase)
+	assert.Equal(t, "deltalog", errs[1].Error)
+	assert.False(t, errs[1].Timestamp.IsZero())
+
+	// the oldest failures are overwritten
+	history.Record(3, LoadPhaseGrowing, errors.New("growing"))
+	history.Record(4, LoadPhaseIndex, errors.New("index"))
+	history.Record(5, LoadPhaseIndex, errors.New("index"))
+	errs = history.List()
+	assert.Equal(t, []int64{3, 4, 5}, lo.Map(errs, func(err SegmentLoadError, _ int) int64 { return err.SegmentID }))
+
+	// disabled
+	history = newLoadErrorHistory(0)
+	history.Record(1, LoadPhaseIndex, errors.New("index"))
+	assert.Empty(t, history.List())
+}
@@ -42,6 +42,12 @@ import (
 
 type SegmentFilter func(segment Segment) bool
 
+func WithCollection(collectionID UniqueID) SegmentFilter {
+	return func(segment Segment) bool {
+		return segment.Collection() == collectionID
+	}
+}
+
 func WithPartition(partitionID UniqueID) SegmentFilter {
 	return func(segment Segment) bool {
 		return segment.Partition() == partitionID
@@ -0,0 +1,59 @@
+package segments
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// mmap policies of collection
+const (
+	// follow paramtable queryNode.mmapDirPath
+	MmapPolicyDefault  = "default"
+	MmapPolicyEnabled  = "enabled"
+	MmapPolicyDisabled = "disabled"
+)
+
+// SetMmapPolicy sets whether the segments of collection are mmapped when they are loaded,
+// enabling requires paramtable queryNode.mmapDirPath configured.
+func (c *Collection) SetMmapPolicy(policy string) error {
+	switch policy {
+	case MmapPolicyDefault:
+		c.mmapPolicy.Store("")
+	case MmapPolicyEnabled:
+		if len(paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue()) == 0 {
+			return merr.WrapErrServiceUnavailable("mmap dir path not configured")
+		}
+		c.mmapPolicy.Store(policy)
+	case MmapPolicyDisabled:
+		c.mmapPolicy.Store(policy)
+	default:
+		return merr.WrapErrParameterInvalid(fmt.Sprintf("%s, %s or %s", MmapPolicyDefault, MmapPolicyEnabled, MmapPolicyDisabled), policy, "invalid mmap policy")
+	}
+	return nil
+}
+
+// GetMmapPolicy returns the mmap policy of collection.
+func (c *Collection) GetMmapPolicy() string {
+	if policy := c.mmapPolicy.Load(); policy != "" {
+		return policy
+	}
+	return MmapPolicyDefault
+}
+
+// MmapDirPath returns the dir path to mmap the segments of collection in, empty if they are loaded into memory.
+func (c *Collection) MmapDirPath() string {
+	if c.GetMmapPolicy() == MmapPolicyDisabled {
+		return ""
+	}
+	return paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue()
+}
+
+// mmapDirPathOf returns the mmap dir path of the loaded collection, the node default if not loaded.
+func mmapDirPathOf(manager CollectionManager, collectionID int64) string {
+	if collection := manager.Get(collectionID); collection != nil {
+		return collection.MmapDirPath()
+	}
+	return paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue()
+}
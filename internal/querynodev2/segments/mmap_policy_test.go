@@ -0,0 +1,68 @@
+package segments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type MmapPolicySuite struct {
+	suite.Suite
+
+	mmapDirPath string
+}
+
+func (suite *MmapPolicySuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *MmapPolicySuite) SetupTest() {
+	suite.mmapDirPath = suite.T().TempDir()
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.MmapDirPath.Key, suite.mmapDirPath)
+}
+
+func (suite *MmapPolicySuite) TearDownTest() {
+	paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.MmapDirPath.Key)
+}
+
+func (suite *MmapPolicySuite) TestPolicy() {
+	collection := &Collection{id: 100}
+
+	suite.Equal(MmapPolicyDefault, collection.GetMmapPolicy())
+	suite.Equal(suite.mmapDirPath, collection.MmapDirPath())
+
+	suite.NoError(collection.SetMmapPolicy(MmapPolicyDisabled))
+	suite.Equal(MmapPolicyDisabled, collection.GetMmapPolicy())
+	suite.Empty(collection.MmapDirPath())
+	// other collections not affected
+	suite.Equal(suite.mmapDirPath, (&Collection{id: 101}).MmapDirPath())
+
+	suite.NoError(collection.SetMmapPolicy(MmapPolicyEnabled))
+	suite.Equal(suite.mmapDirPath, collection.MmapDirPath())
+
+	suite.ErrorIs(collection.SetMmapPolicy("unknown"), merr.ErrParameterInvalid)
+
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.MmapDirPath.Key, "")
+	suite.ErrorIs(collection.SetMmapPolicy(MmapPolicyEnabled), merr.ErrServiceUnavailable)
+	suite.NoError(collection.SetMmapPolicy(MmapPolicyDefault))
+	suite.Equal(MmapPolicyDefault, collection.GetMmapPolicy())
+	suite.Empty(collection.MmapDirPath())
+}
+
+func (suite *MmapPolicySuite) TestMmapDirPathOf() {
+	manager := NewCollectionManager()
+	collection := &Collection{id: 100}
+	manager.collections[collection.ID()] = collection
+	suite.Require().NoError(collection.SetMmapPolicy(MmapPolicyDisabled))
+
+	suite.Empty(mmapDirPathOf(manager, 100))
+	// not loaded collection follows the node default
+	suite.Equal(suite.mmapDirPath, mmapDirPathOf(manager, 101))
+}
+
+func TestMmapPolicy(t *testing.T) {
+	suite.Run(t, new(MmapPolicySuite))
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metric"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -1543,3 +1544,172 @@ func genSearchResultData(nq int64, topk int64, ids []int64, scores []float32, to
 		Topks: topks,
 	}
 }
+
+// SearchHarness is an in-process search test harness,
+// which loads sealed segments with generated data and index,
+// and searches them through the segcore and delegator reducers.
+type SearchHarness struct {
+	Manager      *Manager
+	Collection   *Collection
+	CollectionID int64
+	PartitionID  int64
+
+	chunkManager storage.ChunkManager
+	loader       Loader
+}
+
+func NewSearchHarness(chunkManager storage.ChunkManager, collectionID int64, partitionID int64) *SearchHarness {
+	manager := NewManager()
+	schema := GenTestCollectionSchema("test-search-harness", schemapb.DataType_Int64)
+	manager.Collection.PutOrRef(collectionID,
+		schema,
+		GenTestIndexMeta(collectionID, schema),
+		&querypb.LoadMetaInfo{
+			LoadType:     querypb.LoadType_LoadCollection,
+			CollectionID: collectionID,
+			PartitionIDs: []int64{partitionID},
+		},
+	)
+
+	return &SearchHarness{
+		Manager:      manager,
+		Collection:   manager.Collection.Get(collectionID),
+		CollectionID: collectionID,
+		PartitionID:  partitionID,
+		chunkManager: chunkManager,
+		loader:       NewLoader(manager, chunkManager),
+	}
+}
+
+// AddSealedSegment saves msgLength generated rows as binlogs, builds vector index of indexType if not empty,
+// and loads them as a sealed segment.
+func (h *SearchHarness) AddSealedSegment(ctx context.Context, segmentID int64, msgLength int, indexType string) (Segment, error) {
+	binlogs, statsLogs, err := SaveBinLog(ctx,
+		h.CollectionID,
+		h.PartitionID,
+		segmentID,
+		msgLength,
+		h.Collection.Schema(),
+		h.chunkManager,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	loadInfo := &querypb.SegmentLoadInfo{
+		SegmentID:     segmentID,
+		PartitionID:   h.PartitionID,
+		CollectionID:  h.CollectionID,
+		BinlogPaths:   binlogs,
+		Statslogs:     statsLogs,
+		NumOfRows:     int64(msgLength),
+		InsertChannel: "dml",
+	}
+	if indexType != "" {
+		indexInfo, err := GenAndSaveIndex(
+			h.CollectionID,
+			h.PartitionID,
+			segmentID,
+			simpleFloatVecField.id,
+			msgLength,
+			indexType,
+			defaultMetricType,
+			h.chunkManager,
+		)
+		if err != nil {
+			return nil, err
+		}
+		loadInfo.IndexInfos = []*querypb.FieldIndexInfo{indexInfo}
+	}
+
+	segments, err := h.loader.Load(ctx, h.CollectionID, SegmentTypeSealed, 0, loadInfo)
+	if err != nil {
+		return nil, err
+	}
+	return segments[0], nil
+}
+
+// GenSearchRequest generates a brute force search request of nq random vectors on segments.
+func (h *SearchHarness) GenSearchRequest(nq int64, segmentIDs ...int64) (*querypb.SearchRequest, error) {
+	req, err := genSearchRequest(nq, IndexFaissIDMap, h.Collection)
+	if err != nil {
+		return nil, err
+	}
+	req.Topk = defaultTopK
+	req.MetricType = defaultMetricType
+
+	return &querypb.SearchRequest{
+		Req:             req,
+		DmlChannels:     []string{"dml"},
+		SegmentIDs:      segmentIDs,
+		FromShardLeader: true,
+		Scope:           querypb.DataScope_Historical,
+	}, nil
+}
+
+// Search searches each segment of request as if it's on a different worker,
+// the results are reduced by segcore on each segment, then by the delegator reducer.
+func (h *SearchHarness) Search(ctx context.Context, req *querypb.SearchRequest) (*internalpb.SearchResults, error) {
+	nq := req.GetReq().GetNq()
+	topk := req.GetReq().GetTopk()
+
+	results := make([]*internalpb.SearchResults, 0, len(req.GetSegmentIDs()))
+	for _, segmentID := range req.GetSegmentIDs() {
+		result, err := h.searchSegment(ctx, req, segmentID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return ReduceSearchResults(ctx, results, nq, topk, req.GetReq().GetMetricType())
+}
+
+func (h *SearchHarness) searchSegment(ctx context.Context, req *querypb.SearchRequest, segmentID int64) (*internalpb.SearchResults, error) {
+	nq := req.GetReq().GetNq()
+	topk := req.GetReq().GetTopk()
+
+	searchReq, err := NewSearchRequest(h.Collection, req, req.GetReq().GetPlaceholderGroup())
+	if err != nil {
+		return nil, err
+	}
+	defer searchReq.Delete()
+
+	searchResults, segments, err := SearchHistorical(ctx, h.Manager, searchReq, h.CollectionID, nil, []int64{segmentID})
+	defer h.Manager.Segment.Unpin(segments)
+	if err != nil {
+		return nil, err
+	}
+	defer DeleteSearchResults(searchResults)
+
+	blobs, err := ReduceSearchResultsAndFillData(searchReq.Plan(), searchResults, int64(len(searchResults)), []int64{nq}, []int64{topk})
+	if err != nil {
+		return nil, err
+	}
+	defer DeleteSearchResultDataBlobs(blobs)
+	blob, err := GetSearchResultDataBlob(blobs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// blob is unsafe because get from C
+	bs := make([]byte, len(blob))
+	copy(bs, blob)
+	return &internalpb.SearchResults{
+		Status:         merr.Success(),
+		MetricType:     req.GetReq().GetMetricType(),
+		NumQueries:     nq,
+		TopK:           topk,
+		SlicedBlob:     bs,
+		SlicedOffset:   1,
+		SlicedNumCount: 1,
+	}, nil
+}
+
+// Release releases all segments and the collection of harness.
+func (h *SearchHarness) Release() {
+	for _, segment := range h.Manager.Segment.GetBy() {
+		h.Manager.Segment.Remove(segment.ID(), querypb.DataScope_All)
+	}
+	DeleteCollection(h.Collection)
+}
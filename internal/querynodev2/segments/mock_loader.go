@@ -29,6 +29,160 @@ func (_m *MockLoader) EXPECT() *MockLoader_Expecter {
 	return &MockLoader_Expecter{mock: &_m.Mock}
 }
 
+// EstimateLoad provides a mock function with given fields: ctx, infos
+func (_m *MockLoader) EstimateLoad(ctx context.Context, infos ...*querypb.SegmentLoadInfo) (*LoadFeasibility, error) {
+	_va := make([]interface{}, len(infos))
+	for _i := range infos {
+		_va[_i] = infos[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *LoadFeasibility
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...*querypb.SegmentLoadInfo) (*LoadFeasibility, error)); ok {
+		return rf(ctx, infos...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...*querypb.SegmentLoadInfo) *LoadFeasibility); ok {
+		r0 = rf(ctx, infos...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*LoadFeasibility)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...*querypb.SegmentLoadInfo) error); ok {
+		r1 = rf(ctx, infos...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLoader_EstimateLoad_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EstimateLoad'
+type MockLoader_EstimateLoad_Call struct {
+	*mock.Call
+}
+
+// EstimateLoad is a helper method to define mock.On call
+//   - ctx context.Context
+//   - infos ...*querypb.SegmentLoadInfo
+func (_e *MockLoader_Expecter) EstimateLoad(ctx interface{}, infos ...interface{}) *MockLoader_EstimateLoad_Call {
+	return &MockLoader_EstimateLoad_Call{Call: _e.mock.On("EstimateLoad",
+		append([]interface{}{ctx}, infos...)...)}
+}
+
+func (_c *MockLoader_EstimateLoad_Call) Run(run func(ctx context.Context, infos ...*querypb.SegmentLoadInfo)) *MockLoader_EstimateLoad_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]*querypb.SegmentLoadInfo, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(*querypb.SegmentLoadInfo)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLoader_EstimateLoad_Call) Return(_a0 *LoadFeasibility, _a1 error) *MockLoader_EstimateLoad_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLoader_EstimateLoad_Call) RunAndReturn(run func(context.Context, ...*querypb.SegmentLoadInfo) (*LoadFeasibility, error)) *MockLoader_EstimateLoad_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIndexLoadProgress provides a mock function with given fields: segmentID
+func (_m *MockLoader) GetIndexLoadProgress(segmentID int64) IndexLoadProgress {
+	ret := _m.Called(segmentID)
+
+	var r0 IndexLoadProgress
+	if rf, ok := ret.Get(0).(func(int64) IndexLoadProgress); ok {
+		r0 = rf(segmentID)
+	} else {
+		r0 = ret.Get(0).(IndexLoadProgress)
+	}
+
+	return r0
+}
+
+// MockLoader_GetIndexLoadProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIndexLoadProgress'
+type MockLoader_GetIndexLoadProgress_Call struct {
+	*mock.Call
+}
+
+// GetIndexLoadProgress is a helper method to define mock.On call
+//   - segmentID int64
+func (_e *MockLoader_Expecter) GetIndexLoadProgress(segmentID interface{}) *MockLoader_GetIndexLoadProgress_Call {
+	return &MockLoader_GetIndexLoadProgress_Call{Call: _e.mock.On("GetIndexLoadProgress", segmentID)}
+}
+
+func (_c *MockLoader_GetIndexLoadProgress_Call) Run(run func(segmentID int64)) *MockLoader_GetIndexLoadProgress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockLoader_GetIndexLoadProgress_Call) Return(_a0 IndexLoadProgress) *MockLoader_GetIndexLoadProgress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoader_GetIndexLoadProgress_Call) RunAndReturn(run func(int64) IndexLoadProgress) *MockLoader_GetIndexLoadProgress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoadErrors provides a mock function with given fields:
+func (_m *MockLoader) GetLoadErrors() []SegmentLoadError {
+	ret := _m.Called()
+
+	var r0 []SegmentLoadError
+	if rf, ok := ret.Get(0).(func() []SegmentLoadError); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]SegmentLoadError)
+		}
+	}
+
+	return r0
+}
+
+// MockLoader_GetLoadErrors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoadErrors'
+type MockLoader_GetLoadErrors_Call struct {
+	*mock.Call
+}
+
+// GetLoadErrors is a helper method to define mock.On call
+func (_e *MockLoader_Expecter) GetLoadErrors() *MockLoader_GetLoadErrors_Call {
+	return &MockLoader_GetLoadErrors_Call{Call: _e.mock.On("GetLoadErrors")}
+}
+
+func (_c *MockLoader_GetLoadErrors_Call) Run(run func()) *MockLoader_GetLoadErrors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockLoader_GetLoadErrors_Call) Return(_a0 []SegmentLoadError) *MockLoader_GetLoadErrors_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoader_GetLoadErrors_Call) RunAndReturn(run func() []SegmentLoadError) *MockLoader_GetLoadErrors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Load provides a mock function with given fields: ctx, collectionID, segmentType, version, segments
 func (_m *MockLoader) Load(ctx context.Context, collectionID int64, segmentType commonpb.SegmentState, version int64, segments ...*querypb.SegmentLoadInfo) ([]Segment, error) {
 	_va := make([]interface{}, len(segments))
@@ -261,6 +415,105 @@ func (_c *MockLoader_LoadIndex_Call) RunAndReturn(run func(context.Context, *Loc
 	return _c
 }
 
+// Reload provides a mock function with given fields: ctx, segment
+func (_m *MockLoader) Reload(ctx context.Context, segment *LocalSegment) (*LocalSegment, error) {
+	ret := _m.Called(ctx, segment)
+
+	var r0 *LocalSegment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *LocalSegment) (*LocalSegment, error)); ok {
+		return rf(ctx, segment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *LocalSegment) *LocalSegment); ok {
+		r0 = rf(ctx, segment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*LocalSegment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *LocalSegment) error); ok {
+		r1 = rf(ctx, segment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLoader_Reload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reload'
+type MockLoader_Reload_Call struct {
+	*mock.Call
+}
+
+// Reload is a helper method to define mock.On call
+//   - ctx context.Context
+//   - segment *LocalSegment
+func (_e *MockLoader_Expecter) Reload(ctx interface{}, segment interface{}) *MockLoader_Reload_Call {
+	return &MockLoader_Reload_Call{Call: _e.mock.On("Reload", ctx, segment)}
+}
+
+func (_c *MockLoader_Reload_Call) Run(run func(ctx context.Context, segment *LocalSegment)) *MockLoader_Reload_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*LocalSegment))
+	})
+	return _c
+}
+
+func (_c *MockLoader_Reload_Call) Return(_a0 *LocalSegment, _a1 error) *MockLoader_Reload_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLoader_Reload_Call) RunAndReturn(run func(context.Context, *LocalSegment) (*LocalSegment, error)) *MockLoader_Reload_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReloadDeltaLogs provides a mock function with given fields: ctx, segment, deltaLogs
+func (_m *MockLoader) ReloadDeltaLogs(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog) error {
+	ret := _m.Called(ctx, segment, deltaLogs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *LocalSegment, []*datapb.FieldBinlog) error); ok {
+		r0 = rf(ctx, segment, deltaLogs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLoader_ReloadDeltaLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReloadDeltaLogs'
+type MockLoader_ReloadDeltaLogs_Call struct {
+	*mock.Call
+}
+
+// ReloadDeltaLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - segment *LocalSegment
+//   - deltaLogs []*datapb.FieldBinlog
+func (_e *MockLoader_Expecter) ReloadDeltaLogs(ctx interface{}, segment interface{}, deltaLogs interface{}) *MockLoader_ReloadDeltaLogs_Call {
+	return &MockLoader_ReloadDeltaLogs_Call{Call: _e.mock.On("ReloadDeltaLogs", ctx, segment, deltaLogs)}
+}
+
+func (_c *MockLoader_ReloadDeltaLogs_Call) Run(run func(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog)) *MockLoader_ReloadDeltaLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*LocalSegment), args[2].([]*datapb.FieldBinlog))
+	})
+	return _c
+}
+
+func (_c *MockLoader_ReloadDeltaLogs_Call) Return(_a0 error) *MockLoader_ReloadDeltaLogs_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoader_ReloadDeltaLogs_Call) RunAndReturn(run func(context.Context, *LocalSegment, []*datapb.FieldBinlog) error) *MockLoader_ReloadDeltaLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockLoader creates a new instance of MockLoader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockLoader(t interface {
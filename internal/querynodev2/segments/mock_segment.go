@@ -232,6 +232,47 @@ func (_c *MockSegment_ExistIndex_Call) RunAndReturn(run func(int64) bool) *MockS
 	return _c
 }
 
+// FlushTimestamp provides a mock function with given fields:
+func (_m *MockSegment) FlushTimestamp() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// MockSegment_FlushTimestamp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FlushTimestamp'
+type MockSegment_FlushTimestamp_Call struct {
+	*mock.Call
+}
+
+// FlushTimestamp is a helper method to define mock.On call
+func (_e *MockSegment_Expecter) FlushTimestamp() *MockSegment_FlushTimestamp_Call {
+	return &MockSegment_FlushTimestamp_Call{Call: _e.mock.On("FlushTimestamp")}
+}
+
+func (_c *MockSegment_FlushTimestamp_Call) Run(run func()) *MockSegment_FlushTimestamp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSegment_FlushTimestamp_Call) Return(_a0 uint64) *MockSegment_FlushTimestamp_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSegment_FlushTimestamp_Call) RunAndReturn(run func() uint64) *MockSegment_FlushTimestamp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetIndex provides a mock function with given fields: fieldID
 func (_m *MockSegment) GetIndex(fieldID int64) *IndexedFieldInfo {
 	ret := _m.Called(fieldID)
@@ -0,0 +1,61 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// DropSegmentPageCache advises the OS to drop the page cache of the mmap files of segment,
+// which are placed at {mmapDirPath}/{segmentID}, returns the released bytes.
+// The segment is regarded as cold afterwards, searches page the data back in on demand.
+func DropSegmentPageCache(segmentID int64) (int64, error) {
+	mmapDirPath := paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue()
+	if len(mmapDirPath) == 0 {
+		return 0, nil
+	}
+
+	var released int64
+	dir := filepath.Join(mmapDirPath, fmt.Sprint(segmentID))
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		bytes, err := dropFilePageCache(path)
+		if err != nil {
+			return err
+		}
+		released += bytes
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		// not mmapped
+		return 0, nil
+	}
+	GetSegmentResidency().Remove(segmentID)
+	return released, err
+}
@@ -0,0 +1,89 @@
+//go:build linux
+
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// dropFilePageCache advises the OS to drop the cached pages of file, returns the released bytes.
+// Pages mapped by the loaded segment are dropped by madvise on a shared mapping of the same file,
+// and will be paged back in on demand.
+func dropFilePageCache(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() == 0 {
+		return 0, nil
+	}
+	data, err := unix.Mmap(int(f.Fd()), 0, int(info.Size()), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return 0, err
+	}
+	defer unix.Munmap(data)
+
+	before, err := residentBytes(data)
+	if err != nil {
+		return 0, err
+	}
+	if err := unix.Madvise(data, unix.MADV_DONTNEED); err != nil {
+		return 0, err
+	}
+	if err := unix.Fadvise(int(f.Fd()), 0, 0, unix.FADV_DONTNEED); err != nil {
+		return 0, err
+	}
+	after, err := residentBytes(data)
+	if err != nil {
+		return 0, err
+	}
+	if after > before {
+		return 0, nil
+	}
+	return before - after, nil
+}
+
+// residentBytes returns the bytes of mapped data resident in page cache.
+func residentBytes(data []byte) (int64, error) {
+	pageSize := os.Getpagesize()
+	vec := make([]byte, (len(data)+pageSize-1)/pageSize)
+	if err := unix.Mincore(data, vec); err != nil {
+		return 0, err
+	}
+	var resident int64
+	for i, v := range vec {
+		if v&1 == 0 {
+			continue
+		}
+		if end := (i + 1) * pageSize; end > len(data) {
+			resident += int64(len(data) - i*pageSize)
+		} else {
+			resident += int64(pageSize)
+		}
+	}
+	return resident, nil
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package segments
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type PageCacheSuite struct {
+	suite.Suite
+
+	mmapDirPath string
+}
+
+func (suite *PageCacheSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *PageCacheSuite) SetupTest() {
+	suite.mmapDirPath = suite.T().TempDir()
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.MmapDirPath.Key, suite.mmapDirPath)
+}
+
+func (suite *PageCacheSuite) TearDownTest() {
+	paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.MmapDirPath.Key)
+}
+
+func (suite *PageCacheSuite) TestDropSegmentPageCache() {
+	const segmentID = 1
+	dir := filepath.Join(suite.mmapDirPath, fmt.Sprint(segmentID), "100")
+	suite.Require().NoError(os.MkdirAll(dir, 0o755))
+	data := make([]byte, 4*os.Getpagesize()+1)
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "raw"), data, 0o644))
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "empty"), nil, 0o644))
+	GetSegmentResidency().Touch(segmentID)
+
+	released, err := DropSegmentPageCache(segmentID)
+	suite.NoError(err)
+	suite.GreaterOrEqual(released, int64(0))
+	suite.LessOrEqual(released, int64(len(data)))
+	suite.False(GetSegmentResidency().IsLikelyResident(&Collection{id: 100}, segmentID))
+}
+
+func (suite *PageCacheSuite) TestSegmentNotMmapped() {
+	released, err := DropSegmentPageCache(2)
+	suite.NoError(err)
+	suite.Zero(released)
+}
+
+func (suite *PageCacheSuite) TestDropFilePageCache() {
+	f, err := os.CreateTemp(suite.T().TempDir(), "resident")
+	suite.Require().NoError(err)
+	defer f.Close()
+	size := os.Getpagesize() + 10
+	_, err = f.Write(make([]byte, size))
+	suite.Require().NoError(err)
+
+	released, err := dropFilePageCache(f.Name())
+	suite.NoError(err)
+	suite.LessOrEqual(released, int64(size))
+}
+
+func TestPageCache(t *testing.T) {
+	suite.Run(t, new(PageCacheSuite))
+}
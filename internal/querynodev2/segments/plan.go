@@ -89,6 +89,10 @@ type SearchRequest struct {
 	cPlaceholderGroup C.CPlaceholderGroup
 	msgID             UniqueID
 	searchFieldID     UniqueID
+	// preferCached skips sealed segments guessed not resident in page cache, see SegmentResidency
+	preferCached bool
+	// partial is set if any segment is skipped
+	partial bool
 }
 
 func NewSearchRequest(collection *Collection, req *querypb.SearchRequest, placeholderGrp []byte) (*SearchRequest, error) {
@@ -128,11 +132,17 @@ func NewSearchRequest(collection *Collection, req *querypb.SearchRequest, placeh
 		cPlaceholderGroup: cPlaceholderGroup,
 		msgID:             req.GetReq().GetBase().GetMsgID(),
 		searchFieldID:     int64(fieldID),
+		preferCached:      req.GetReq().GetPreferCached(),
 	}
 
 	return ret, nil
 }
 
+// Partial returns whether some segments are skipped in this search.
+func (req *SearchRequest) Partial() bool {
+	return req.partial
+}
+
 func (req *SearchRequest) getNumOfQuery() int64 {
 	numQueries := C.GetNumOfQueries(req.cPlaceholderGroup)
 	return int64(numQueries)
@@ -25,7 +25,12 @@ package segments
 import "C"
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 )
 
 type SliceInfo struct {
@@ -36,6 +41,8 @@ type SliceInfo struct {
 // SearchResult contains a pointer to the search result in C++ memory
 type SearchResult struct {
 	cSearchResult C.CSearchResult
+	// segmentID is the id of segment the result searched from
+	segmentID int64
 }
 
 // searchResultDataBlobs is the CSearchResultsDataBlobs in C++
@@ -106,6 +113,86 @@ func ReduceSearchResultsAndFillData(plan *SearchPlan, searchResults []*SearchRes
 	return cSearchResultDataBlobs, nil
 }
 
+// SearchResultAnnotator annotates the hits of the result data reduced from the search result of a segment,
+// e.g. with pseudo fields.
+type SearchResultAnnotator func(result *SearchResult, data *schemapb.SearchResultData) error
+
+// AnnotateSegmentID annotates each hit with the segment it's found in, as the segment id pseudo field.
+func AnnotateSegmentID(result *SearchResult, data *schemapb.SearchResultData) error {
+	AppendSegmentIDField(data, result.segmentID)
+	return nil
+}
+
+// SegmentFlushTsAnnotator returns the annotator annotating each hit with the flush timestamp of the segment
+// it's found in, as the segment flush timestamp pseudo field, 0 for growing segments.
+// It costs 8 bytes per hit, and is bounded by topK as the segment id.
+func SegmentFlushTsAnnotator(searched []Segment) SearchResultAnnotator {
+	flushTs := make(map[int64]uint64, len(searched))
+	for _, segment := range searched {
+		flushTs[segment.ID()] = segment.FlushTimestamp()
+	}
+	return func(result *SearchResult, data *schemapb.SearchResultData) error {
+		AppendSegmentFlushTsField(data, flushTs[result.segmentID])
+		return nil
+	}
+}
+
+// ReduceSearchResultsWithSegmentID reduces search results with each hit annotated by the segment it's found in.
+// The result of each segment is reduced and filled separately, then merged with the segment id pseudo field,
+// so the provenance of the best-score occurrence is kept while removing duplicates.
+func ReduceSearchResultsWithSegmentID(ctx context.Context, plan *SearchPlan, searchResults []*SearchResult,
+	sliceNQs []int64, sliceTopKs []int64, metricType string,
+) ([][]byte, error) {
+	return ReduceSearchResultsBySegment(ctx, plan, searchResults, sliceNQs, sliceTopKs, metricType, AnnotateSegmentID)
+}
+
+// ReduceSearchResultsBySegment reduces search results with the hits of each segment annotated by annotators.
+// The result of each segment is reduced and filled separately, annotated, then merged by ReduceSearchResultData.
+func ReduceSearchResultsBySegment(ctx context.Context, plan *SearchPlan, searchResults []*SearchResult,
+	sliceNQs []int64, sliceTopKs []int64, metricType string, annotators ...SearchResultAnnotator,
+) ([][]byte, error) {
+	sliceData := make([][]*schemapb.SearchResultData, len(sliceNQs))
+	for _, result := range searchResults {
+		blobs, err := ReduceSearchResultsAndFillData(plan, []*SearchResult{result}, 1, sliceNQs, sliceTopKs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range sliceNQs {
+			blob, err := GetSearchResultDataBlob(blobs, i)
+			if err != nil {
+				DeleteSearchResultDataBlobs(blobs)
+				return nil, err
+			}
+			data := &schemapb.SearchResultData{}
+			if err := proto.Unmarshal(blob, data); err != nil {
+				DeleteSearchResultDataBlobs(blobs)
+				return nil, err
+			}
+			for _, annotate := range annotators {
+				if err := annotate(result, data); err != nil {
+					DeleteSearchResultDataBlobs(blobs)
+					return nil, err
+				}
+			}
+			sliceData[i] = append(sliceData[i], data)
+		}
+		DeleteSearchResultDataBlobs(blobs)
+	}
+
+	ret := make([][]byte, len(sliceNQs))
+	for i := range sliceNQs {
+		reduced, err := ReduceSearchResultData(ctx, sliceData[i], sliceNQs[i], sliceTopKs[i], metricType)
+		if err != nil {
+			return nil, err
+		}
+		ret[i], err = proto.Marshal(reduced)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 func GetSearchResultDataBlob(cSearchResultDataBlobs searchResultDataBlobs, blobIndex int) ([]byte, error) {
 	var blob C.CProto
 	status := C.GetSearchResultDataBlob(&blob, cSearchResultDataBlobs, C.int32_t(blobIndex))
@@ -0,0 +1,237 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// algorithms to select topK among the results of segments, see queryNode.reduce.algorithm
+const (
+	ReduceAlgorithmAuto = "auto"
+	ReduceAlgorithmHeap = "heap"
+	ReduceAlgorithmSort = "sort"
+)
+
+type reduceAlgorithmKey struct{}
+
+// WithReduceAlgorithm returns a context carrying the reduce algorithm requested,
+// which overrides the configured one, empty means not overridden.
+func WithReduceAlgorithm(ctx context.Context, algorithm string) context.Context {
+	if algorithm == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, reduceAlgorithmKey{}, algorithm)
+}
+
+// reduceAlgorithmFromContext returns the reduce algorithm requested in context, or the configured one if not set.
+func reduceAlgorithmFromContext(ctx context.Context) (string, error) {
+	algorithm, ok := ctx.Value(reduceAlgorithmKey{}).(string)
+	if !ok {
+		algorithm = paramtable.Get().QueryNodeCfg.ReduceAlgorithm.GetValue()
+	}
+	switch lower := strings.ToLower(algorithm); lower {
+	case ReduceAlgorithmAuto, ReduceAlgorithmHeap, ReduceAlgorithmSort:
+		return lower, nil
+	default:
+		return "", merr.WrapErrParameterInvalid("auto, heap or sort", algorithm, "invalid reduce algorithm")
+	}
+}
+
+// chooseReduceAlgorithm returns the algorithm to reduce a query with the candidates of all results,
+// heap pops only topK candidates which is cheap for small topK,
+// while sorting all candidates is faster once topK gets close to the candidate count.
+func chooseReduceAlgorithm(algorithm string, topk int64, candidates int64) string {
+	if algorithm != ReduceAlgorithmAuto {
+		return algorithm
+	}
+	params := &paramtable.Get().QueryNodeCfg
+	if candidates == 0 || candidates > params.ReduceSortMaxCandidates.GetAsInt64() {
+		return ReduceAlgorithmHeap
+	}
+	if float64(topk)/float64(candidates) >= params.ReduceSortRatioThreshold.GetAsFloat() {
+		return ReduceAlgorithmSort
+	}
+	return ReduceAlgorithmHeap
+}
+
+// resultCursors are the cursors of results of segments for a query,
+// each result is sorted by score and offsets point to the next candidate of each one.
+type resultCursors struct {
+	dataArray     []*schemapb.SearchResultData
+	resultOffsets [][]int64
+	offsets       []int64
+	qi            int64
+	tolerance     scoreTolerance
+	// insertionTs are the insertion timestamps of hits of each result,
+	// hits of tied scores are ordered by insertion if set
+	insertionTs [][]int64
+}
+
+// exhausted returns whether all candidates of the i-th result are consumed.
+func (c *resultCursors) exhausted(i int) bool {
+	return c.offsets[i] >= c.dataArray[i].Topks[c.qi]
+}
+
+// candidateNum returns the number of candidates left in all results.
+func (c *resultCursors) candidateNum() int64 {
+	var num int64
+	for i, offset := range c.offsets {
+		num += c.dataArray[i].Topks[c.qi] - offset
+	}
+	return num
+}
+
+// before returns whether the candidate at offsetA of result a precedes the one at offsetB of result b,
+// scores tied within tolerance are ordered by primary key,
+// or by insertion timestamp first if requested and known for both.
+func (c *resultCursors) before(a int, offsetA int64, b int, offsetB int64) bool {
+	idxA := c.resultOffsets[a][c.qi] + offsetA
+	idxB := c.resultOffsets[b][c.qi] + offsetB
+	scoreA, scoreB := c.dataArray[a].Scores[idxA], c.dataArray[b].Scores[idxB]
+	if c.tolerance.tied(scoreA, scoreB) {
+		if tsA, tsB := c.insertionTsOf(a, idxA), c.insertionTsOf(b, idxB); tsA > 0 && tsB > 0 && tsA != tsB {
+			return tsA < tsB
+		}
+		return typeutil.ComparePK(typeutil.GetPK(c.dataArray[a].GetIds(), idxA), typeutil.GetPK(c.dataArray[b].GetIds(), idxB))
+	}
+	return scoreA > scoreB
+}
+
+// insertionTsOf returns the insertion timestamp of the hit at idx of result i, 0 if unknown.
+func (c *resultCursors) insertionTsOf(i int, idx int64) int64 {
+	if i >= len(c.insertionTs) || idx >= int64(len(c.insertionTs[i])) {
+		return 0
+	}
+	return c.insertionTs[i][idx]
+}
+
+// resultSelector yields the result to take the next candidate from, -1 if all exhausted.
+// The caller consumes the candidate by advancing the offset of the yielded result before next call.
+type resultSelector interface {
+	next() int
+}
+
+func newResultSelector(algorithm string, cursors *resultCursors) resultSelector {
+	if algorithm == ReduceAlgorithmSort {
+		return newSortSelector(cursors)
+	}
+	return newHeapSelector(cursors)
+}
+
+// heapSelector merges the results with a heap of their next candidates.
+type heapSelector struct {
+	cursors *resultCursors
+	results []int
+	// last yielded result, pushed back on next call if not exhausted
+	last int
+}
+
+func newHeapSelector(cursors *resultCursors) *heapSelector {
+	h := &heapSelector{
+		cursors: cursors,
+		results: make([]int, 0, len(cursors.offsets)),
+		last:    -1,
+	}
+	for i := range cursors.offsets {
+		if !cursors.exhausted(i) {
+			h.results = append(h.results, i)
+		}
+	}
+	heap.Init(h)
+	return h
+}
+
+func (h *heapSelector) Len() int {
+	return len(h.results)
+}
+
+func (h *heapSelector) Less(i, j int) bool {
+	a, b := h.results[i], h.results[j]
+	return h.cursors.before(a, h.cursors.offsets[a], b, h.cursors.offsets[b])
+}
+
+func (h *heapSelector) Swap(i, j int) {
+	h.results[i], h.results[j] = h.results[j], h.results[i]
+}
+
+func (h *heapSelector) Push(x any) {
+	h.results = append(h.results, x.(int))
+}
+
+func (h *heapSelector) Pop() any {
+	n := len(h.results)
+	x := h.results[n-1]
+	h.results = h.results[:n-1]
+	return x
+}
+
+func (h *heapSelector) next() int {
+	if h.last != -1 && !h.cursors.exhausted(h.last) {
+		heap.Push(h, h.last)
+	}
+	h.last = -1
+	if h.Len() == 0 {
+		return -1
+	}
+	h.last = heap.Pop(h).(int)
+	return h.last
+}
+
+// sortSelector sorts all candidates of the results once and yields them in order.
+type sortSelector struct {
+	order []int
+	pos   int
+}
+
+func newSortSelector(cursors *resultCursors) *sortSelector {
+	type candidate struct {
+		result int
+		offset int64
+	}
+	candidates := make([]candidate, 0, cursors.candidateNum())
+	for i, offset := range cursors.offsets {
+		for ; offset < cursors.dataArray[i].Topks[cursors.qi]; offset++ {
+			candidates = append(candidates, candidate{result: i, offset: offset})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return cursors.before(candidates[i].result, candidates[i].offset, candidates[j].result, candidates[j].offset)
+	})
+
+	order := make([]int, len(candidates))
+	for i, candidate := range candidates {
+		order[i] = candidate.result
+	}
+	return &sortSelector{order: order}
+}
+
+func (s *sortSelector) next() int {
+	if s.pos >= len(s.order) {
+		return -1
+	}
+	s.pos++
+	return s.order[s.pos-1]
+}
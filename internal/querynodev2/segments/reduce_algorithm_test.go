@@ -0,0 +1,282 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segments
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// genSortedSearchResults generates results of segments for nq queries with k candidates each,
+// scores of each query are sorted descending and primary keys are unique across results.
+func genSortedSearchResults(resultNum int, nq int64, k int64) []*schemapb.SearchResultData {
+	r := rand.New(rand.NewSource(int64(resultNum)*nq*k + 1))
+	results := make([]*schemapb.SearchResultData, resultNum)
+	var pk int64
+	for i := range results {
+		data := &schemapb.SearchResultData{
+			NumQueries: nq,
+			TopK:       k,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}},
+			Scores:     make([]float32, 0, nq*k),
+			Topks:      make([]int64, nq),
+		}
+		for q := int64(0); q < nq; q++ {
+			scores := make([]float32, k)
+			for j := range scores {
+				scores[j] = r.Float32()
+			}
+			sort.Slice(scores, func(a, b int) bool { return scores[a] > scores[b] })
+			for _, score := range scores {
+				pk++
+				data.Ids.GetIntId().Data = append(data.Ids.GetIntId().Data, pk)
+				data.Scores = append(data.Scores, score)
+			}
+			data.Topks[q] = k
+		}
+		results[i] = data
+	}
+	return results
+}
+
+type ReduceAlgorithmSuite struct {
+	suite.Suite
+}
+
+func (suite *ReduceAlgorithmSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *ReduceAlgorithmSuite) TestReduceAlgorithmFromContext() {
+	ctx := context.Background()
+	algorithm, err := reduceAlgorithmFromContext(ctx)
+	suite.NoError(err)
+	suite.Equal(ReduceAlgorithmAuto, algorithm)
+
+	algorithm, err = reduceAlgorithmFromContext(WithReduceAlgorithm(ctx, "Sort"))
+	suite.NoError(err)
+	suite.Equal(ReduceAlgorithmSort, algorithm)
+
+	// empty means not overridden
+	algorithm, err = reduceAlgorithmFromContext(WithReduceAlgorithm(ctx, ""))
+	suite.NoError(err)
+	suite.Equal(ReduceAlgorithmAuto, algorithm)
+
+	_, err = reduceAlgorithmFromContext(WithReduceAlgorithm(ctx, "bubble"))
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.ReduceAlgorithm.Key, "bubble")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.ReduceAlgorithm.Key)
+	_, err = ReduceSearchResultData(ctx, genSortedSearchResults(2, 1, 10), 1, 10, metric.IP)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
+func (suite *ReduceAlgorithmSuite) TestChooseReduceAlgorithm() {
+	params := &paramtable.Get().QueryNodeCfg
+	paramtable.Get().Save(params.ReduceSortRatioThreshold.Key, "0.5")
+	defer paramtable.Get().Reset(params.ReduceSortRatioThreshold.Key)
+	paramtable.Get().Save(params.ReduceSortMaxCandidates.Key, "1000")
+	defer paramtable.Get().Reset(params.ReduceSortMaxCandidates.Key)
+
+	suite.Equal(ReduceAlgorithmHeap, chooseReduceAlgorithm(ReduceAlgorithmAuto, 10, 100))
+	suite.Equal(ReduceAlgorithmSort, chooseReduceAlgorithm(ReduceAlgorithmAuto, 50, 100))
+	suite.Equal(ReduceAlgorithmSort, chooseReduceAlgorithm(ReduceAlgorithmAuto, 200, 100))
+	// too many candidates to sort
+	suite.Equal(ReduceAlgorithmHeap, chooseReduceAlgorithm(ReduceAlgorithmAuto, 1000, 1001))
+	suite.Equal(ReduceAlgorithmHeap, chooseReduceAlgorithm(ReduceAlgorithmAuto, 10, 0))
+	// requested algorithm is never changed
+	suite.Equal(ReduceAlgorithmSort, chooseReduceAlgorithm(ReduceAlgorithmSort, 1, 1001))
+	suite.Equal(ReduceAlgorithmHeap, chooseReduceAlgorithm(ReduceAlgorithmHeap, 100, 100))
+}
+
+func (suite *ReduceAlgorithmSuite) TestReduceConsistent() {
+	ctx := context.Background()
+	for _, topk := range []int64{1, 10, 40, 100} {
+		results := genSortedSearchResults(4, 3, 10)
+		expected, err := ReduceSearchResultData(WithReduceAlgorithm(ctx, ReduceAlgorithmHeap), results, 3, topk, metric.IP)
+		suite.Require().NoError(err)
+		suite.Equal([]int64{min64(topk, 40), min64(topk, 40), min64(topk, 40)}, expected.GetTopks())
+		for q, offset := int64(0), int64(0); q < 3; q++ {
+			scores := expected.GetScores()[offset : offset+expected.GetTopks()[q]]
+			suite.True(sort.SliceIsSorted(scores, func(a, b int) bool { return scores[a] > scores[b] }))
+			offset += expected.GetTopks()[q]
+		}
+
+		for _, algorithm := range []string{ReduceAlgorithmSort, ReduceAlgorithmAuto} {
+			actual, err := ReduceSearchResultData(WithReduceAlgorithm(ctx, algorithm), results, 3, topk, metric.IP)
+			suite.Require().NoError(err)
+			suite.Equal(expected.GetTopks(), actual.GetTopks(), algorithm)
+			suite.Equal(expected.GetScores(), actual.GetScores(), algorithm)
+			suite.Equal(expected.GetIds(), actual.GetIds(), algorithm)
+		}
+	}
+}
+
+func (suite *ReduceAlgorithmSuite) TestReduceTiedScores() {
+	ctx := context.Background()
+	results := []*schemapb.SearchResultData{
+		{
+			NumQueries: 1,
+			TopK:       2,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{3, 1}}}},
+			Scores:     []float32{0.9, 0.5},
+			Topks:      []int64{2},
+		},
+		{
+			NumQueries: 1,
+			TopK:       2,
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{2, 3}}}},
+			Scores:     []float32{0.9, 0.4},
+			Topks:      []int64{2},
+		},
+	}
+	for _, algorithm := range []string{ReduceAlgorithmHeap, ReduceAlgorithmSort} {
+		ret, err := ReduceSearchResultData(WithReduceAlgorithm(ctx, algorithm), results, 1, 3, metric.IP)
+		suite.Require().NoError(err)
+		// tied scores ordered by pk, duplicated pk skipped
+		suite.Equal([]int64{2, 3, 1}, ret.GetIds().GetIntId().GetData(), algorithm)
+		suite.Equal([]float32{0.9, 0.9, 0.5}, ret.GetScores(), algorithm)
+	}
+}
+
+func (suite *ReduceAlgorithmSuite) TestResultSelector() {
+	genDataArray := func(scores0, scores1 []float32) []*schemapb.SearchResultData {
+		return []*schemapb.SearchResultData{
+			{
+				Ids: &schemapb.IDs{
+					IdField: &schemapb.IDs_IntId{
+						IntId: &schemapb.LongArray{
+							Data: []int64{11, 9, 7, 5, 3, 1},
+						},
+					},
+				},
+				Scores: scores0,
+				Topks:  []int64{2, 2, 2},
+			},
+			{
+				Ids: &schemapb.IDs{
+					IdField: &schemapb.IDs_IntId{
+						IntId: &schemapb.LongArray{
+							Data: []int64{12, 10, 8, 6, 4, 2},
+						},
+					},
+				},
+				Scores: scores1,
+				Topks:  []int64{2, 2, 2},
+			},
+		}
+	}
+	bad := []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+	tests := []struct {
+		name      string
+		dataArray []*schemapb.SearchResultData
+		qi        int64
+		offsets   []int64
+		want      []int
+	}{
+		{
+			name:      "integer_id",
+			dataArray: genDataArray([]float32{1.1, 0.9, 0.7, 0.5, 0.3, 0.1}, []float32{1.2, 1.0, 0.8, 0.6, 0.4, 0.2}),
+			offsets:   []int64{0, 1},
+			want:      []int{0, 1, 0, -1},
+		},
+		{
+			name:      "second_query",
+			dataArray: genDataArray([]float32{1.1, 0.9, 0.7, 0.5, 0.3, 0.1}, []float32{1.2, 1.0, 0.8, 0.6, 0.4, 0.2}),
+			qi:        1,
+			offsets:   []int64{0, 0},
+			want:      []int{1, 0, 1, 0, -1},
+		},
+		{
+			// scores tied at the bad distance knowhere may return are ordered by pk
+			name:      "integer_id_with_bad_score",
+			dataArray: genDataArray(bad, bad),
+			offsets:   []int64{0, 1},
+			want:      []int{1, 0, 0, -1},
+		},
+	}
+	for _, tt := range tests {
+		for _, algorithm := range []string{ReduceAlgorithmHeap, ReduceAlgorithmSort} {
+			suite.Run(fmt.Sprintf("%s_%s", tt.name, algorithm), func() {
+				cursors := &resultCursors{
+					dataArray:     tt.dataArray,
+					resultOffsets: [][]int64{{0, 2, 4}, {0, 2, 4}},
+					offsets:       append([]int64{}, tt.offsets...),
+					qi:            tt.qi,
+				}
+				selector := newResultSelector(algorithm, cursors)
+				got := make([]int, 0, len(tt.want))
+				for range tt.want {
+					sel := selector.next()
+					got = append(got, sel)
+					if sel != -1 {
+						cursors.offsets[sel]++
+					}
+				}
+				suite.Equal(tt.want, got)
+			})
+		}
+	}
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestReduceAlgorithm(t *testing.T) {
+	suite.Run(t, new(ReduceAlgorithmSuite))
+}
+
+// BenchmarkReduceAlgorithm compares heap and sort with growing ratio of topK to candidates,
+// which grounds the default of queryNode.reduce.sortRatioThreshold.
+func BenchmarkReduceAlgorithm(b *testing.B) {
+	paramtable.Init()
+	const (
+		resultNum = 8
+		nq        = 10
+		k         = 256
+	)
+	results := genSortedSearchResults(resultNum, nq, k)
+	candidates := int64(resultNum * k)
+	for _, ratio := range []float64{0.01, 0.1, 0.25, 0.5, 0.75, 1} {
+		topk := int64(float64(candidates) * ratio)
+		for _, algorithm := range []string{ReduceAlgorithmHeap, ReduceAlgorithmSort} {
+			ctx := WithReduceAlgorithm(context.Background(), algorithm)
+			b.Run(fmt.Sprintf("ratio=%v/%s", ratio, algorithm), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := ReduceSearchResultData(ctx, results, nq, topk, metric.IP); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
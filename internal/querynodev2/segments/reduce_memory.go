@@ -0,0 +1,73 @@
+package segments
+
+import (
+	"context"
+
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type reduceMemoryAccountKey struct{}
+
+// ReduceMemoryAccount accounts the memory materialized while reducing the results of a single request,
+// reduce is aborted once the usage exceeds the limit, instead of risking oom of the node.
+type ReduceMemoryAccount struct {
+	limit int64
+	used  *atomic.Int64
+}
+
+// NewReduceMemoryAccount returns an account with limit in bytes, 0 means unlimited.
+func NewReduceMemoryAccount(limit int64) *ReduceMemoryAccount {
+	return &ReduceMemoryAccount{
+		limit: limit,
+		used:  atomic.NewInt64(0),
+	}
+}
+
+// NewRequestReduceMemoryAccount returns an account limited by queryNode.reduce.maxMemoryPerRequest.
+func NewRequestReduceMemoryAccount() *ReduceMemoryAccount {
+	return NewReduceMemoryAccount(paramtable.Get().QueryNodeCfg.MaxReduceMemoryPerRequest.GetAsInt64() * 1024 * 1024)
+}
+
+// WithReduceMemoryAccount returns a context carrying the account, reduce functions charge the account in context.
+func WithReduceMemoryAccount(ctx context.Context, account *ReduceMemoryAccount) context.Context {
+	return context.WithValue(ctx, reduceMemoryAccountKey{}, account)
+}
+
+// reduceMemoryAccountFromContext returns the account in context, nil if not set.
+func reduceMemoryAccountFromContext(ctx context.Context) *ReduceMemoryAccount {
+	account, _ := ctx.Value(reduceMemoryAccountKey{}).(*ReduceMemoryAccount)
+	return account
+}
+
+// Grow charges size bytes to the account, the charge is rolled back and error returned if limit exceeded.
+// It's safe to call on nil account.
+func (a *ReduceMemoryAccount) Grow(size int64) error {
+	if a == nil {
+		return nil
+	}
+	used := a.used.Add(size)
+	if a.limit > 0 && used > a.limit {
+		a.used.Sub(size)
+		return merr.WrapErrServiceMemoryLimitExceeded(float32(used), float32(a.limit), "reduce memory of request exceeds limit")
+	}
+	return nil
+}
+
+// Used returns the bytes charged.
+func (a *ReduceMemoryAccount) Used() int64 {
+	if a == nil {
+		return 0
+	}
+	return a.used.Load()
+}
+
+// Free releases all charged memory, shall be called after reduce done.
+func (a *ReduceMemoryAccount) Free() {
+	if a == nil {
+		return
+	}
+	a.used.Store(0)
+}
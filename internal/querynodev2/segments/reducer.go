@@ -17,6 +17,12 @@ func CreateInternalReducer(req *querypb.QueryRequest, schema *schemapb.Collectio
 	if req.GetReq().GetIsCount() {
 		return &cntReducer{}
 	}
+	if req.GetReq().GetDistinctCountFieldID() != 0 {
+		return newDistinctReducer()
+	}
+	if req.GetReq().GetSampleSize() > 0 {
+		return newSampleReducer(req, schema)
+	}
 	return newDefaultLimitReducer(req, schema)
 }
 
@@ -28,5 +34,8 @@ func CreateSegCoreReducer(req *querypb.QueryRequest, schema *schemapb.Collection
 	if req.GetReq().GetIsCount() {
 		return &cntReducerSegCore{}
 	}
+	if req.GetReq().GetSampleSize() > 0 {
+		return newSampleReducerSegcore(req, schema)
+	}
 	return newDefaultLimitReducerSegcore(req, schema)
 }
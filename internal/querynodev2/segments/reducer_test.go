@@ -40,6 +40,17 @@ func (suite *ReducerFactorySuite) TestCreateInternalReducer() {
 	suite.ir = CreateInternalReducer(req, nil)
 	_, suite.ok = suite.ir.(*cntReducer)
 	suite.True(suite.ok)
+
+	req.Req.IsCount = false
+	req.Req.SampleSize = 10
+	suite.ir = CreateInternalReducer(req, nil)
+	_, suite.ok = suite.ir.(*sampleReducer)
+	suite.True(suite.ok)
+
+	req.Req.DistinctCountFieldID = 100
+	suite.ir = CreateInternalReducer(req, nil)
+	_, suite.ok = suite.ir.(*distinctReducer)
+	suite.True(suite.ok)
 }
 
 func (suite *ReducerFactorySuite) TestCreateSegCoreReducer() {
@@ -57,4 +68,10 @@ func (suite *ReducerFactorySuite) TestCreateSegCoreReducer() {
 	suite.sr = CreateSegCoreReducer(req, nil)
 	_, suite.ok = suite.sr.(*cntReducerSegCore)
 	suite.True(suite.ok)
+
+	req.Req.IsCount = false
+	req.Req.SampleSize = 10
+	suite.sr = CreateSegCoreReducer(req, nil)
+	_, suite.ok = suite.sr.(*sampleReducerSegcore)
+	suite.True(suite.ok)
 }
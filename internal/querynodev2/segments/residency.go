@@ -0,0 +1,75 @@
+package segments
+
+import (
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+var (
+	segmentResidency     *SegmentResidency
+	segmentResidencyOnce sync.Once
+)
+
+// GetSegmentResidency returns the singleton segment residency tracker.
+func GetSegmentResidency() *SegmentResidency {
+	segmentResidencyOnce.Do(func() {
+		segmentResidency = NewSegmentResidency()
+	})
+	return segmentResidency
+}
+
+// SegmentResidency guesses whether the mmap data of segments is resident in page cache by their last access.
+// Pages of mmap files are populated while loading and kept warm by searches,
+// so segments not loaded or searched within the warm window are guessed cold.
+// It is a heuristic only, the page cache is never inspected and pages may be evicted within the window.
+type SegmentResidency struct {
+	lastAccess *typeutil.ConcurrentMap[int64, time.Time]
+}
+
+func NewSegmentResidency() *SegmentResidency {
+	return &SegmentResidency{
+		lastAccess: typeutil.NewConcurrentMap[int64, time.Time](),
+	}
+}
+
+// Touch records the data of segment has been accessed.
+func (r *SegmentResidency) Touch(segmentID int64) {
+	r.lastAccess.Insert(segmentID, time.Now())
+}
+
+// Remove stops tracking the segment.
+func (r *SegmentResidency) Remove(segmentID int64) {
+	r.lastAccess.Remove(segmentID)
+}
+
+// IsLikelyResident guesses whether the segment data is resident in memory by its last access,
+// segments are always resident if the mmap policy of their collection does not mmap the data.
+func (r *SegmentResidency) IsLikelyResident(collection *Collection, segmentID int64) bool {
+	if collection == nil || len(collection.MmapDirPath()) == 0 {
+		return true
+	}
+
+	lastAccess, ok := r.lastAccess.Get(segmentID)
+	if !ok {
+		return false
+	}
+	window := paramtable.Get().QueryNodeCfg.PreferCachedWarmWindow.GetAsDuration(time.Second)
+	return time.Since(lastAccess) <= window
+}
+
+// FilterLikelyResident splits segments of the collection into the ones likely resident and the ones likely cold.
+func (r *SegmentResidency) FilterLikelyResident(collection *Collection, segments []Segment) ([]Segment, []Segment) {
+	resident := make([]Segment, 0, len(segments))
+	cold := make([]Segment, 0)
+	for _, segment := range segments {
+		if r.IsLikelyResident(collection, segment.ID()) {
+			resident = append(resident, segment)
+		} else {
+			cold = append(cold, segment)
+		}
+	}
+	return resident, cold
+}
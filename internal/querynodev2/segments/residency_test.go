@@ -0,0 +1,70 @@
+package segments
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type SegmentResidencySuite struct {
+	suite.Suite
+
+	residency *SegmentResidency
+}
+
+func (suite *SegmentResidencySuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *SegmentResidencySuite) SetupTest() {
+	suite.residency = NewSegmentResidency()
+}
+
+func (suite *SegmentResidencySuite) TestMmapDisabled() {
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.MmapDirPath.Key, "")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.MmapDirPath.Key)
+
+	suite.True(suite.residency.IsLikelyResident(&Collection{id: 100}, 1))
+}
+
+func (suite *SegmentResidencySuite) TestMmapDisabledByCollection() {
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.MmapDirPath.Key, "/tmp/mmap")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.MmapDirPath.Key)
+
+	collection := &Collection{id: 100}
+	suite.False(suite.residency.IsLikelyResident(collection, 1))
+	suite.Require().NoError(collection.SetMmapPolicy(MmapPolicyDisabled))
+	suite.True(suite.residency.IsLikelyResident(collection, 1))
+}
+
+func (suite *SegmentResidencySuite) TestMmapEnabled() {
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.MmapDirPath.Key, "/tmp/mmap")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.MmapDirPath.Key)
+
+	collection := &Collection{id: 100}
+	suite.False(suite.residency.IsLikelyResident(collection, 1))
+	suite.residency.Touch(1)
+	suite.True(suite.residency.IsLikelyResident(collection, 1))
+
+	// out of warm window
+	suite.residency.lastAccess.Insert(2, time.Now().Add(-time.Hour))
+	suite.False(suite.residency.IsLikelyResident(collection, 2))
+
+	warm := NewMockSegment(suite.T())
+	warm.EXPECT().ID().Return(1)
+	cold := NewMockSegment(suite.T())
+	cold.EXPECT().ID().Return(2)
+	resident, skipped := suite.residency.FilterLikelyResident(collection, []Segment{warm, cold})
+	suite.Equal([]Segment{warm}, resident)
+	suite.Equal([]Segment{cold}, skipped)
+
+	suite.residency.Remove(1)
+	suite.False(suite.residency.IsLikelyResident(collection, 1))
+}
+
+func TestSegmentResidency(t *testing.T) {
+	suite.Run(t, new(SegmentResidencySuite))
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/samber/lo"
@@ -32,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -41,11 +44,27 @@ var _ typeutil.ResultWithID = &internalpb.RetrieveResults{}
 var _ typeutil.ResultWithID = &segcorepb.RetrieveResults{}
 
 func ReduceSearchResults(ctx context.Context, results []*internalpb.SearchResults, nq int64, topk int64, metricType string) (*internalpb.SearchResults, error) {
+	// partial flag shall be kept even if the partial result is empty
+	partial := lo.ContainsBy(results, func(result *internalpb.SearchResults) bool {
+		return result.GetIsPartial()
+	})
+	timedOut := lo.Uniq(lo.FlatMap(results, func(result *internalpb.SearchResults, _ int) []int64 {
+		return result.GetTimedOutNodes()
+	}))
+	truncated := mergeTruncated(results, nq)
+	scannedSegments := lo.SumBy(results, func(result *internalpb.SearchResults) int64 { return result.GetScannedSegments() })
+	scannedRows := lo.SumBy(results, func(result *internalpb.SearchResults) int64 { return result.GetScannedRows() })
 	results = lo.Filter(results, func(result *internalpb.SearchResults, _ int) bool {
 		return result != nil && result.GetSlicedBlob() != nil
 	})
 
-	if len(results) == 1 {
+	// excluded hits shall be dropped even if there is only one result
+	if len(results) == 1 && excludedPKsFromContext(ctx) == nil {
+		results[0].IsPartial = partial
+		results[0].TimedOutNodes = timedOut
+		results[0].Truncated = truncated
+		results[0].ScannedSegments = scannedSegments
+		results[0].ScannedRows = scannedRows
 		return results[0], nil
 	}
 
@@ -56,6 +75,14 @@ func ReduceSearchResults(ctx context.Context, results []*internalpb.SearchResult
 		log.Warn("shard leader decode search results errors", zap.Error(err))
 		return nil, err
 	}
+	var decodedSize int64
+	for _, data := range searchResultData {
+		decodedSize += int64(proto.Size(data))
+	}
+	if err := reduceMemoryAccountFromContext(ctx).Grow(decodedSize); err != nil {
+		log.Warn("shard leader decode search results exceeds reduce memory limit", zap.Error(err))
+		return nil, err
+	}
 	log.Debug("shard leader get valid search results", zap.Int("numbers", len(searchResultData)))
 
 	for i, sData := range searchResultData {
@@ -65,11 +92,14 @@ func ReduceSearchResults(ctx context.Context, results []*internalpb.SearchResult
 			zap.Int64("topk", sData.TopK))
 	}
 
-	reducedResultData, err := ReduceSearchResultData(ctx, searchResultData, nq, topk)
+	reducedResultData, dropped, err := reduceSearchResultData(ctx, searchResultData, nq, topk, metricType)
 	if err != nil {
 		log.Warn("shard leader reduce errors", zap.Error(err))
 		return nil, err
 	}
+	for i := range truncated {
+		truncated[i] = truncated[i] || dropped[i]
+	}
 	searchResults, err := EncodeSearchResultData(reducedResultData, nq, topk, metricType)
 	if err != nil {
 		log.Warn("shard leader encode search result errors", zap.Error(err))
@@ -88,13 +118,131 @@ func ReduceSearchResults(ctx context.Context, results []*internalpb.SearchResult
 		return nil, false
 	})
 	searchResults.CostAggregation = mergeRequestCost(requestCosts)
+	searchResults.IsPartial = partial
+	searchResults.TimedOutNodes = timedOut
+	searchResults.Topks = reducedResultData.GetTopks()
+	searchResults.Truncated = truncated
+	searchResults.ScannedSegments = scannedSegments
+	searchResults.ScannedRows = scannedRows
+	searchResults.SegmentHitDistributions = segmentHitDistributions(reducedResultData)
 
 	return searchResults, nil
 }
 
-func ReduceSearchResultData(ctx context.Context, searchResultData []*schemapb.SearchResultData, nq int64, topk int64) (*schemapb.SearchResultData, error) {
+// mergeTruncated merges the per-query truncated flags of results,
+// a query is truncated if it's truncated in any result.
+func mergeTruncated(results []*internalpb.SearchResults, nq int64) []bool {
+	truncated := make([]bool, nq)
+	for _, result := range results {
+		for i, flag := range result.GetTruncated() {
+			if int64(i) < nq && flag {
+				truncated[i] = true
+			}
+		}
+	}
+	return truncated
+}
+
+type excludedPKsKey struct{}
+
+// WithExcludedPKs returns a context carrying the primary keys to exclude,
+// hits of them are dropped by ReduceSearchResults before topK selected.
+func WithExcludedPKs(ctx context.Context, pks *schemapb.IDs) context.Context {
+	size := typeutil.GetSizeOfIDs(pks)
+	if size == 0 {
+		return ctx
+	}
+	excluded := make(map[any]struct{}, size)
+	for i := 0; i < size; i++ {
+		excluded[typeutil.GetPK(pks, int64(i))] = struct{}{}
+	}
+	return context.WithValue(ctx, excludedPKsKey{}, excluded)
+}
+
+// excludedPKsFromContext returns the excluded primary keys in context, nil if not set.
+func excludedPKsFromContext(ctx context.Context) map[any]struct{} {
+	excluded, _ := ctx.Value(excludedPKsKey{}).(map[any]struct{})
+	return excluded
+}
+
+// FillResultCounts makes sure the search result carries the number of results and truncated flag of each query.
+func FillResultCounts(result *internalpb.SearchResults) error {
+	nq := result.GetNumQueries()
+	if int64(len(result.GetTopks())) != nq {
+		result.Topks = make([]int64, nq)
+		if result.GetSlicedBlob() != nil {
+			var resultData schemapb.SearchResultData
+			if err := proto.Unmarshal(result.GetSlicedBlob(), &resultData); err != nil {
+				return err
+			}
+			copy(result.Topks, resultData.GetTopks())
+		}
+	}
+	if int64(len(result.GetTruncated())) != nq {
+		truncated := make([]bool, nq)
+		copy(truncated, result.GetTruncated())
+		result.Truncated = truncated
+	}
+	return nil
+}
+
+// scoreTolerance decides whether two scores shall be treated as tied.
+type scoreTolerance struct {
+	epsilon  float32
+	relative bool
+}
+
+// newScoreTolerance returns the score tolerance for provided metric type.
+// L2 distances are not bounded so relative tolerance is used,
+// integer valued metrics are always compared exactly.
+func newScoreTolerance(metricType string) scoreTolerance {
+	epsilon := float32(paramtable.Get().QueryNodeCfg.ReduceScoreEpsilon.GetAsFloat())
+	if epsilon <= 0 {
+		return scoreTolerance{}
+	}
+
+	switch strings.ToUpper(metricType) {
+	case metric.L2:
+		return scoreTolerance{epsilon: epsilon, relative: true}
+	case metric.IP, metric.COSINE, metric.JACCARD:
+		return scoreTolerance{epsilon: epsilon}
+	default:
+		return scoreTolerance{}
+	}
+}
+
+// tied returns whether score a and b are equal within tolerance.
+func (t scoreTolerance) tied(a, b float32) bool {
+	if a == b {
+		return true
+	}
+	if t.epsilon <= 0 {
+		return false
+	}
+	diff := math.Abs(float64(a) - float64(b))
+	if t.relative {
+		scale := math.Max(1, math.Max(math.Abs(float64(a)), math.Abs(float64(b))))
+		return diff <= float64(t.epsilon)*scale
+	}
+	return diff <= float64(t.epsilon)
+}
+
+func ReduceSearchResultData(ctx context.Context, searchResultData []*schemapb.SearchResultData, nq int64, topk int64, metricType string) (*schemapb.SearchResultData, error) {
+	ret, _, err := reduceSearchResultData(ctx, searchResultData, nq, topk, metricType)
+	return ret, err
+}
+
+// reduceSearchResultData reduces the search result data,
+// and reports whether candidates of each query are dropped as topK reached.
+func reduceSearchResultData(ctx context.Context, searchResultData []*schemapb.SearchResultData, nq int64, topk int64, metricType string) (*schemapb.SearchResultData, []bool, error) {
 	log := log.Ctx(ctx)
 
+	algorithm, err := reduceAlgorithmFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dropped := make([]bool, nq)
 	if len(searchResultData) == 0 {
 		return &schemapb.SearchResultData{
 			NumQueries: nq,
@@ -103,7 +251,7 @@ func ReduceSearchResultData(ctx context.Context, searchResultData []*schemapb.Se
 			Scores:     make([]float32, 0),
 			Ids:        &schemapb.IDs{},
 			Topks:      make([]int64, 0),
-		}, nil
+		}, dropped, nil
 	}
 	ret := &schemapb.SearchResultData{
 		NumQueries: nq,
@@ -125,13 +273,30 @@ func ReduceSearchResultData(ctx context.Context, searchResultData []*schemapb.Se
 	var skipDupCnt int64
 	var retSize int64
 	maxOutputSize := paramtable.Get().QuotaConfig.MaxOutputSize.GetAsInt64()
+	account := reduceMemoryAccountFromContext(ctx)
+	excluded := excludedPKsFromContext(ctx)
+	var skipExcludedCnt int64
+	tolerance := newScoreTolerance(metricType)
+	var insertionTs [][]int64
+	if insertionOrderTiebreakFromContext(ctx) {
+		insertionTs = insertionTsOfResults(searchResultData)
+	}
 	for i := int64(0); i < nq; i++ {
 		offsets := make([]int64, len(searchResultData))
+		cursors := &resultCursors{
+			dataArray:     searchResultData,
+			resultOffsets: resultOffsets,
+			offsets:       offsets,
+			qi:            i,
+			tolerance:     tolerance,
+			insertionTs:   insertionTs,
+		}
+		selector := newResultSelector(chooseReduceAlgorithm(algorithm, topk, cursors.candidateNum()), cursors)
 
 		idSet := make(map[interface{}]struct{})
 		var j int64
 		for j = 0; j < topk; {
-			sel := SelectSearchResultData(searchResultData, resultOffsets, offsets, i)
+			sel := selector.next()
 			if sel == -1 {
 				break
 			}
@@ -140,9 +305,19 @@ func ReduceSearchResultData(ctx context.Context, searchResultData []*schemapb.Se
 			id := typeutil.GetPK(searchResultData[sel].GetIds(), idx)
 			score := searchResultData[sel].Scores[idx]
 
+			// excluded hits don't consume topK
+			if _, ok := excluded[id]; ok {
+				skipExcludedCnt++
+				offsets[sel]++
+				continue
+			}
 			// remove duplicates
 			if _, ok := idSet[id]; !ok {
-				retSize += typeutil.AppendFieldData(ret.FieldsData, searchResultData[sel].FieldsData, idx)
+				size := typeutil.AppendFieldData(ret.FieldsData, searchResultData[sel].FieldsData, idx)
+				if err := account.Grow(size); err != nil {
+					return nil, nil, err
+				}
+				retSize += size
 				typeutil.AppendPKs(ret.Ids, id)
 				ret.Scores = append(ret.Scores, score)
 				idSet[id] = struct{}{}
@@ -159,49 +334,32 @@ func ReduceSearchResultData(ctx context.Context, searchResultData []*schemapb.Se
 		// 	// return nil, errors.New("the length (topk) between all result of query is different")
 		// }
 		ret.Topks = append(ret.Topks, j)
+		if j == topk {
+			dropped[i] = hasCandidateLeft(searchResultData, resultOffsets, offsets, i, idSet, excluded)
+		}
 
 		// limit search result to avoid oom
 		if retSize > maxOutputSize {
-			return nil, fmt.Errorf("search results exceed the maxOutputSize Limit %d", maxOutputSize)
+			return nil, nil, fmt.Errorf("search results exceed the maxOutputSize Limit %d", maxOutputSize)
 		}
 	}
-	log.Debug("skip duplicated search result", zap.Int64("count", skipDupCnt))
-	return ret, nil
+	log.Debug("skip duplicated search result", zap.Int64("count", skipDupCnt), zap.Int64("excludedCount", skipExcludedCnt))
+	return ret, dropped, nil
 }
 
-func SelectSearchResultData(dataArray []*schemapb.SearchResultData, resultOffsets [][]int64, offsets []int64, qi int64) int {
-	var (
-		sel                 = -1
-		maxDistance         = -float32(math.MaxFloat32)
-		resultDataIdx int64 = -1
-	)
-	for i, offset := range offsets { // query num, the number of ways to merge
-		if offset >= dataArray[i].Topks[qi] {
-			continue
-		}
-
-		idx := resultOffsets[i][qi] + offset
-		distance := dataArray[i].Scores[idx]
-
-		if distance > maxDistance {
-			sel = i
-			maxDistance = distance
-			resultDataIdx = idx
-		} else if distance == maxDistance {
-			if sel == -1 {
-				// A bad case happens where knowhere returns distance == +/-maxFloat32
-				// by mistake.
-				log.Warn("a bad distance is found, something is wrong here!", zap.Float32("score", distance))
-			} else if typeutil.ComparePK(
-				typeutil.GetPK(dataArray[i].GetIds(), idx),
-				typeutil.GetPK(dataArray[sel].GetIds(), resultDataIdx)) {
-				sel = i
-				maxDistance = distance
-				resultDataIdx = idx
+// hasCandidateLeft returns whether any result not selected remains for the query, duplicates and excluded ones ignored.
+func hasCandidateLeft(dataArray []*schemapb.SearchResultData, resultOffsets [][]int64, offsets []int64, qi int64, selected map[interface{}]struct{}, excluded map[any]struct{}) bool {
+	for i, offset := range offsets {
+		for ; offset < dataArray[i].Topks[qi]; offset++ {
+			id := typeutil.GetPK(dataArray[i].GetIds(), resultOffsets[i][qi]+offset)
+			_, isSelected := selected[id]
+			_, isExcluded := excluded[id]
+			if !isSelected && !isExcluded {
+				return true
 			}
 		}
 	}
-	return sel
+	return false
 }
 
 func DecodeSearchResults(searchResults []*internalpb.SearchResults) ([]*schemapb.SearchResultData, error) {
@@ -240,6 +398,423 @@ func EncodeSearchResultData(searchResultData *schemapb.SearchResultData, nq int6
 	return
 }
 
+// IsScoreFieldRequested returns whether the score pseudo field is in the output fields.
+// The score field uses a reserved system field id, so it never collides with user fields named "$score".
+func IsScoreFieldRequested(outputFieldIDs []int64) bool {
+	return lo.Contains(outputFieldIDs, common.ScoreField)
+}
+
+// SortFieldsDataByOutputFields orders fields data as the requested output fields,
+// fields not requested, e.g. pk filled by segcore, are kept at the end in their original order.
+func SortFieldsDataByOutputFields(fieldsData []*schemapb.FieldData, outputFieldIDs []int64) []*schemapb.FieldData {
+	positions := make(map[int64]int, len(outputFieldIDs))
+	for i, fieldID := range outputFieldIDs {
+		if _, ok := positions[fieldID]; !ok {
+			positions[fieldID] = i
+		}
+	}
+	position := func(fieldData *schemapb.FieldData) int {
+		if pos, ok := positions[fieldData.GetFieldId()]; ok {
+			return pos
+		}
+		return len(outputFieldIDs)
+	}
+
+	sorted := make([]*schemapb.FieldData, len(fieldsData))
+	copy(sorted, fieldsData)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return position(sorted[i]) < position(sorted[j])
+	})
+	return sorted
+}
+
+// FillScoreField attaches per-hit scores as the score pseudo field into the search result,
+// at the position requested in output fields.
+// Score field already in the result is replaced, so it could be called after each reduce.
+func FillScoreField(result *internalpb.SearchResults, outputFieldIDs []int64) error {
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+
+	var resultData schemapb.SearchResultData
+	err := proto.Unmarshal(result.GetSlicedBlob(), &resultData)
+	if err != nil {
+		return err
+	}
+
+	resultData.FieldsData = lo.Filter(resultData.GetFieldsData(), func(fieldData *schemapb.FieldData, _ int) bool {
+		return fieldData.GetFieldId() != common.ScoreField
+	})
+	scores := make([]float32, len(resultData.GetScores()))
+	copy(scores, resultData.GetScores())
+	resultData.FieldsData = append(resultData.FieldsData, &schemapb.FieldData{
+		Type:      schemapb.DataType_Float,
+		FieldName: common.ScoreFieldName,
+		FieldId:   common.ScoreField,
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_FloatData{
+					FloatData: &schemapb.FloatArray{
+						Data: scores,
+					},
+				},
+			},
+		},
+	})
+	resultData.FieldsData = SortFieldsDataByOutputFields(resultData.GetFieldsData(), outputFieldIDs)
+
+	slicedBlob, err := proto.Marshal(&resultData)
+	if err != nil {
+		return err
+	}
+	result.SlicedBlob = slicedBlob
+	return nil
+}
+
+// FillRankScores attaches the normalized rank score of each hit, hits of each query are scored by their position,
+// 1 for the best and decaying linearly by 1/n for a query with n hits, so ties in raw score keep the deterministic order.
+func FillRankScores(result *internalpb.SearchResults) error {
+	result.RankScores = nil
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return err
+	}
+	rankScores := make([]float32, 0, len(data.GetScores()))
+	for _, topk := range data.GetTopks() {
+		for rank := int64(0); rank < topk; rank++ {
+			rankScores = append(rankScores, 1-float32(rank)/float32(topk))
+		}
+	}
+	result.RankScores = rankScores
+	return nil
+}
+
+// FillSegmentHitDistributions recounts the final hits of each query by source segment,
+// after the hits of reduced result are filtered or reordered.
+// Nothing is counted if the segment ids are not returned.
+func FillSegmentHitDistributions(result *internalpb.SearchResults) error {
+	result.SegmentHitDistributions = nil
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return err
+	}
+	result.SegmentHitDistributions = segmentHitDistributions(&data)
+	return nil
+}
+
+// segmentHitDistributions counts the hits of each query by the segment id pseudo field,
+// segments ordered by id, nil if the field is absent.
+func segmentHitDistributions(data *schemapb.SearchResultData) []*internalpb.SegmentHitDistribution {
+	segmentField, ok := lo.Find(data.GetFieldsData(), func(field *schemapb.FieldData) bool {
+		return field.GetFieldId() == common.SegmentIDField
+	})
+	if !ok {
+		return nil
+	}
+	segmentIDs := segmentField.GetScalars().GetLongData().GetData()
+	flushTs := make(map[int64]uint64)
+	if flushTsField, ok := lo.Find(data.GetFieldsData(), func(field *schemapb.FieldData) bool {
+		return field.GetFieldId() == common.SegmentFlushTsField
+	}); ok {
+		for i, ts := range flushTsField.GetScalars().GetLongData().GetData() {
+			if i < len(segmentIDs) {
+				flushTs[segmentIDs[i]] = uint64(ts)
+			}
+		}
+	}
+
+	distributions := make([]*internalpb.SegmentHitDistribution, 0, len(data.GetTopks()))
+	var offset int64
+	for _, topk := range data.GetTopks() {
+		hits := make(map[int64]int64)
+		for i := offset; i < offset+topk && i < int64(len(segmentIDs)); i++ {
+			hits[segmentIDs[i]]++
+		}
+		offset += topk
+
+		distribution := &internalpb.SegmentHitDistribution{
+			Segments: make([]*internalpb.SegmentHits, 0, len(hits)),
+		}
+		for segmentID, count := range hits {
+			distribution.Segments = append(distribution.Segments, &internalpb.SegmentHits{
+				SegmentID: segmentID,
+				Hits:      count,
+				FlushTs:   flushTs[segmentID],
+			})
+		}
+		sort.Slice(distribution.Segments, func(i, j int) bool {
+			return distribution.Segments[i].GetSegmentID() < distribution.Segments[j].GetSegmentID()
+		})
+		distributions = append(distributions, distribution)
+	}
+	return distributions
+}
+
+// AppendSegmentIDField annotates each hit of search result data with the segment it's found in,
+// as the segment id pseudo field.
+func AppendSegmentIDField(data *schemapb.SearchResultData, segmentID int64) {
+	appendIDPseudoField(data, common.SegmentIDField, common.SegmentIDFieldName, segmentID)
+}
+
+// AppendSegmentFlushTsField annotates each hit of search result data with the flush timestamp of the segment
+// it's found in, as the segment flush timestamp pseudo field.
+func AppendSegmentFlushTsField(data *schemapb.SearchResultData, flushTs uint64) {
+	appendIDPseudoField(data, common.SegmentFlushTsField, common.SegmentFlushTsFieldName, int64(flushTs))
+}
+
+// AppendPartitionIDField annotates each hit of search result data with the partition it's found in,
+// as the partition id pseudo field.
+func AppendPartitionIDField(data *schemapb.SearchResultData, partitionID int64) {
+	appendIDPseudoField(data, common.PartitionIDField, common.PartitionIDFieldName, partitionID)
+}
+
+func appendIDPseudoField(data *schemapb.SearchResultData, fieldID int64, fieldName string, id int64) {
+	ids := make([]int64, typeutil.GetSizeOfIDs(data.GetIds()))
+	for i := range ids {
+		ids[i] = id
+	}
+	appendLongPseudoField(data, fieldID, fieldName, ids)
+}
+
+func appendLongPseudoField(data *schemapb.SearchResultData, fieldID int64, fieldName string, values []int64) {
+	data.FieldsData = append(data.FieldsData, &schemapb.FieldData{
+		Type:      schemapb.DataType_Int64,
+		FieldName: fieldName,
+		FieldId:   fieldID,
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{
+					LongData: &schemapb.LongArray{
+						Data: values,
+					},
+				},
+			},
+		},
+	})
+}
+
+// FillPartitionTopks counts the hits of each query by partition with the partition id pseudo field,
+// for the result with hits grouped by partition. Nothing is counted if the partition ids are not returned.
+func FillPartitionTopks(result *internalpb.SearchResults, partitionIDs []int64) error {
+	result.PartitionTopks = nil
+	partitionTopks := make([]*internalpb.PartitionTopks, 0, len(partitionIDs))
+	positions := make(map[int64]int, len(partitionIDs))
+	for i, partitionID := range partitionIDs {
+		partitionTopks = append(partitionTopks, &internalpb.PartitionTopks{
+			PartitionID: partitionID,
+			Topks:       make([]int64, result.GetNumQueries()),
+		})
+		positions[partitionID] = i
+	}
+	if result.GetSlicedBlob() == nil {
+		result.PartitionTopks = partitionTopks
+		return nil
+	}
+	var data schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &data); err != nil {
+		return err
+	}
+	partitionField, ok := lo.Find(data.GetFieldsData(), func(field *schemapb.FieldData) bool {
+		return field.GetFieldId() == common.PartitionIDField
+	})
+	if !ok {
+		return nil
+	}
+	hitPartitions := partitionField.GetScalars().GetLongData().GetData()
+	var offset int64
+	for i, topk := range data.GetTopks() {
+		for j := offset; j < offset+topk && j < int64(len(hitPartitions)); j++ {
+			if pos, ok := positions[hitPartitions[j]]; ok && i < len(partitionTopks[pos].Topks) {
+				partitionTopks[pos].Topks[i]++
+			}
+		}
+		offset += topk
+	}
+	result.PartitionTopks = partitionTopks
+	return nil
+}
+
+// FillPrimaryTopks counts the topK hits of each query of search result over-fetched,
+// the hits following them are the over-fetched candidates.
+func FillPrimaryTopks(result *internalpb.SearchResults, topK int64) error {
+	primaryTopks := make([]int64, result.GetNumQueries())
+	result.PrimaryTopks = primaryTopks
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+	var resultData schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &resultData); err != nil {
+		return err
+	}
+	for i, topk := range resultData.GetTopks() {
+		if i < len(primaryTopks) {
+			primaryTopks[i] = lo.Min([]int64{topk, topK})
+		}
+	}
+	return nil
+}
+
+// FillScoreStats computes the statistics of the scores of hits of each query,
+// the scores are reported as distances for distance metrics, e.g. L2, instead of the negated ones.
+func FillScoreStats(result *internalpb.SearchResults) error {
+	stats := make([]*internalpb.ScoreStats, result.GetNumQueries())
+	for i := range stats {
+		stats[i] = &internalpb.ScoreStats{}
+	}
+	result.ScoreStats = stats
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+	var resultData schemapb.SearchResultData
+	if err := proto.Unmarshal(result.GetSlicedBlob(), &resultData); err != nil {
+		return err
+	}
+
+	positivelyRelated := metric.PositivelyRelated(result.GetMetricType())
+	var offset int64
+	for i, topk := range resultData.GetTopks() {
+		if i >= len(stats) || topk == 0 {
+			offset += topk
+			continue
+		}
+		var sum float64
+		for j := offset; j < offset+topk; j++ {
+			score := resultData.GetScores()[j]
+			if !positivelyRelated {
+				score = -score
+			}
+			if j == offset || score < stats[i].Min {
+				stats[i].Min = score
+			}
+			if j == offset || score > stats[i].Max {
+				stats[i].Max = score
+			}
+			sum += float64(score)
+		}
+		stats[i].Count = topk
+		stats[i].Mean = float32(sum / float64(topk))
+		offset += topk
+	}
+	return nil
+}
+
+// FilterSearchResultsByScore drops the hits worse than the score threshold from the reduced search result,
+// the threshold is a similarity lower bound for positively related metrics, e.g. IP and COSINE,
+// otherwise it's a distance upper bound, e.g. L2.
+// Each query may keep 0 to topK hits.
+func FilterSearchResultsByScore(result *internalpb.SearchResults, threshold float32) error {
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+
+	var resultData schemapb.SearchResultData
+	err := proto.Unmarshal(result.GetSlicedBlob(), &resultData)
+	if err != nil {
+		return err
+	}
+
+	// scores of distance metrics are negated in search results
+	keep := func(score float32) bool {
+		if metric.PositivelyRelated(result.GetMetricType()) {
+			return score >= threshold
+		}
+		return -score <= threshold
+	}
+
+	filtered := &schemapb.SearchResultData{
+		NumQueries:   resultData.GetNumQueries(),
+		TopK:         resultData.GetTopK(),
+		Ids:          &schemapb.IDs{},
+		FieldsData:   make([]*schemapb.FieldData, len(resultData.GetFieldsData())),
+		Topks:        make([]int64, len(resultData.GetTopks())),
+		OutputFields: resultData.GetOutputFields(),
+	}
+	var offset int64
+	for i, topk := range resultData.GetTopks() {
+		for j := offset; j < offset+topk; j++ {
+			score := resultData.GetScores()[j]
+			if !keep(score) {
+				continue
+			}
+			typeutil.AppendPKs(filtered.Ids, typeutil.GetPK(resultData.GetIds(), j))
+			typeutil.AppendFieldData(filtered.FieldsData, resultData.GetFieldsData(), j)
+			filtered.Scores = append(filtered.Scores, score)
+			filtered.Topks[i]++
+		}
+		offset += topk
+	}
+
+	result.Topks = filtered.GetTopks()
+	if len(filtered.GetScores()) == 0 {
+		result.SlicedBlob = nil
+		return nil
+	}
+	slicedBlob, err := proto.Marshal(filtered)
+	if err != nil {
+		return err
+	}
+	result.SlicedBlob = slicedBlob
+	return nil
+}
+
+// ClearSearchResultsOfQueries drops all the hits of queries in the search results, the queries keep 0 hit.
+func ClearSearchResultsOfQueries(result *internalpb.SearchResults, queries []int) error {
+	cleared := typeutil.NewSet(queries...)
+	for _, query := range queries {
+		if query < len(result.GetTruncated()) {
+			result.Truncated[query] = false
+		}
+	}
+	if result.GetSlicedBlob() == nil {
+		return nil
+	}
+
+	var resultData schemapb.SearchResultData
+	err := proto.Unmarshal(result.GetSlicedBlob(), &resultData)
+	if err != nil {
+		return err
+	}
+
+	filtered := &schemapb.SearchResultData{
+		NumQueries:   resultData.GetNumQueries(),
+		TopK:         resultData.GetTopK(),
+		Ids:          &schemapb.IDs{},
+		FieldsData:   make([]*schemapb.FieldData, len(resultData.GetFieldsData())),
+		Topks:        make([]int64, len(resultData.GetTopks())),
+		OutputFields: resultData.GetOutputFields(),
+	}
+	var offset int64
+	for i, topk := range resultData.GetTopks() {
+		if !cleared.Contain(i) {
+			for j := offset; j < offset+topk; j++ {
+				typeutil.AppendPKs(filtered.Ids, typeutil.GetPK(resultData.GetIds(), j))
+				typeutil.AppendFieldData(filtered.FieldsData, resultData.GetFieldsData(), j)
+				filtered.Scores = append(filtered.Scores, resultData.GetScores()[j])
+			}
+			filtered.Topks[i] = topk
+		}
+		offset += topk
+	}
+
+	result.Topks = filtered.GetTopks()
+	if len(filtered.GetScores()) == 0 {
+		result.SlicedBlob = nil
+		return nil
+	}
+	slicedBlob, err := proto.Marshal(filtered)
+	if err != nil {
+		return err
+	}
+	result.SlicedBlob = slicedBlob
+	return nil
+}
+
 func MergeInternalRetrieveResult(ctx context.Context, retrieveResults []*internalpb.RetrieveResults, param *mergeParam) (*internalpb.RetrieveResults, error) {
 	log.Ctx(ctx).Debug("mergeInternelRetrieveResults",
 		zap.Int64("limit", param.limit),
@@ -278,6 +853,7 @@ func MergeInternalRetrieveResult(ctx context.Context, retrieveResults []*interna
 
 	var retSize int64
 	maxOutputSize := paramtable.Get().QuotaConfig.MaxOutputSize.GetAsInt64()
+	account := reduceMemoryAccountFromContext(ctx)
 	for j := 0; j < loopEnd; {
 		sel := typeutil.SelectMinPK(validRetrieveResults, cursors, param.mergeStopForBest, param.limit)
 		if sel == -1 {
@@ -288,7 +864,11 @@ func MergeInternalRetrieveResult(ctx context.Context, retrieveResults []*interna
 		ts := getTS(validRetrieveResults[sel], cursors[sel])
 		if _, ok := idTsMap[pk]; !ok {
 			typeutil.AppendPKs(ret.Ids, pk)
-			retSize += typeutil.AppendFieldData(ret.FieldsData, validRetrieveResults[sel].GetFieldsData(), cursors[sel])
+			size := typeutil.AppendFieldData(ret.FieldsData, validRetrieveResults[sel].GetFieldsData(), cursors[sel])
+			if err := account.Grow(size); err != nil {
+				return nil, err
+			}
+			retSize += size
 			idTsMap[pk] = ts
 			j++
 		} else {
@@ -297,7 +877,11 @@ func MergeInternalRetrieveResult(ctx context.Context, retrieveResults []*interna
 			if ts != 0 && ts > idTsMap[pk] {
 				idTsMap[pk] = ts
 				typeutil.DeleteFieldData(ret.FieldsData)
-				retSize += typeutil.AppendFieldData(ret.FieldsData, validRetrieveResults[sel].GetFieldsData(), cursors[sel])
+				size := typeutil.AppendFieldData(ret.FieldsData, validRetrieveResults[sel].GetFieldsData(), cursors[sel])
+				if err := account.Grow(size); err != nil {
+					return nil, err
+				}
+				retSize += size
 			}
 		}
 
@@ -426,6 +1010,7 @@ func mergeInternalRetrieveResultsAndFillIfEmpty(
 	if err := typeutil2.FillRetrieveResultIfEmpty(typeutil2.NewInternalResult(mergedResult), param.outputFieldsId, param.schema); err != nil {
 		return nil, fmt.Errorf("failed to fill internal retrieve results: %s", err.Error())
 	}
+	mergedResult.FieldsData = SortFieldsDataByOutputFields(mergedResult.GetFieldsData(), param.outputFieldsId)
 
 	return mergedResult, nil
 }
@@ -443,6 +1028,7 @@ func mergeSegcoreRetrieveResultsAndFillIfEmpty(
 	if err := typeutil2.FillRetrieveResultIfEmpty(typeutil2.NewSegcoreResults(mergedResult), param.outputFieldsId, param.schema); err != nil {
 		return nil, fmt.Errorf("failed to fill segcore retrieve results: %s", err.Error())
 	}
+	mergedResult.FieldsData = SortFieldsDataByOutputFields(mergedResult.GetFieldsData(), param.outputFieldsId)
 
 	return mergedResult, nil
 }
@@ -18,17 +18,21 @@ package segments
 
 import (
 	"context"
-	"math"
 	"sort"
 	"testing"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/proto/segcorepb"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -580,7 +584,7 @@ func (suite *ResultSuite) TestResult_ReduceSearchResultData() {
 		dataArray := make([]*schemapb.SearchResultData, 0)
 		dataArray = append(dataArray, data1)
 		dataArray = append(dataArray, data2)
-		res, err := ReduceSearchResultData(context.TODO(), dataArray, nq, topk)
+		res, err := ReduceSearchResultData(context.TODO(), dataArray, nq, topk, metricType)
 		suite.Nil(err)
 		suite.Equal(ids, res.Ids.GetIntId().Data)
 		suite.Equal(scores, res.Scores)
@@ -597,120 +601,645 @@ func (suite *ResultSuite) TestResult_ReduceSearchResultData() {
 		dataArray := make([]*schemapb.SearchResultData, 0)
 		dataArray = append(dataArray, data1)
 		dataArray = append(dataArray, data2)
-		res, err := ReduceSearchResultData(context.TODO(), dataArray, nq, topk)
+		res, err := ReduceSearchResultData(context.TODO(), dataArray, nq, topk, metricType)
 		suite.Nil(err)
 		suite.ElementsMatch([]int64{1, 5, 2, 3}, res.Ids.GetIntId().Data)
 	})
 }
 
-func (suite *ResultSuite) TestResult_SelectSearchResultData_int() {
-	type args struct {
-		dataArray     []*schemapb.SearchResultData
-		resultOffsets [][]int64
-		offsets       []int64
-		topk          int64
-		nq            int64
-		qi            int64
+func (suite *ResultSuite) TestResult_ReduceSearchResultDataWithTolerance() {
+	const (
+		nq         = 1
+		topk       = 4
+		metricType = "IP"
+	)
+	paramtable.Init()
+
+	// same logical distances with different float32 summation order
+	ids1 := []int64{2, 4}
+	scores1 := []float32{0.90000004, 0.5}
+	ids2 := []int64{1, 3}
+	scores2 := []float32{0.9, 0.5}
+	reduce := func(reverse bool) *schemapb.SearchResultData {
+		data1 := genSearchResultData(nq, topk, ids1, scores1, []int64{2})
+		data2 := genSearchResultData(nq, topk, ids2, scores2, []int64{2})
+		dataArray := []*schemapb.SearchResultData{data1, data2}
+		if reverse {
+			dataArray = []*schemapb.SearchResultData{data2, data1}
+		}
+		res, err := ReduceSearchResultData(context.TODO(), dataArray, nq, topk, metricType)
+		suite.Require().NoError(err)
+		return res
 	}
-	suite.Run("Integer ID", func() {
-		tests := []struct {
-			name string
-			args args
-			want int
-		}{
-			{
-				args: args{
-					dataArray: []*schemapb.SearchResultData{
-						{
-							Ids: &schemapb.IDs{
-								IdField: &schemapb.IDs_IntId{
-									IntId: &schemapb.LongArray{
-										Data: []int64{11, 9, 7, 5, 3, 1},
-									},
-								},
-							},
-							Scores: []float32{1.1, 0.9, 0.7, 0.5, 0.3, 0.1},
-							Topks:  []int64{2, 2, 2},
-						},
-						{
-							Ids: &schemapb.IDs{
-								IdField: &schemapb.IDs_IntId{
-									IntId: &schemapb.LongArray{
-										Data: []int64{12, 10, 8, 6, 4, 2},
-									},
-								},
-							},
-							Scores: []float32{1.2, 1.0, 0.8, 0.6, 0.4, 0.2},
-							Topks:  []int64{2, 2, 2},
-						},
+
+	suite.Run("tolerance_disabled", func() {
+		res := reduce(false)
+		// the larger score always wins, which depends on float error
+		suite.Equal([]int64{2, 1, 3, 4}, res.GetIds().GetIntId().GetData())
+	})
+
+	suite.Run("tolerance_enabled", func() {
+		paramtable.Get().Save(paramtable.Get().QueryNodeCfg.ReduceScoreEpsilon.Key, "1e-6")
+		defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.ReduceScoreEpsilon.Key)
+
+		// tied scores are ordered by pk regardless of result order
+		res := reduce(false)
+		suite.Equal([]int64{1, 2, 3, 4}, res.GetIds().GetIntId().GetData())
+		res = reduce(true)
+		suite.Equal([]int64{1, 2, 3, 4}, res.GetIds().GetIntId().GetData())
+	})
+
+	suite.Run("tolerance_by_metric", func() {
+		paramtable.Get().Save(paramtable.Get().QueryNodeCfg.ReduceScoreEpsilon.Key, "0.01")
+		defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.ReduceScoreEpsilon.Key)
+
+		tolerance := newScoreTolerance(metric.IP)
+		suite.True(tolerance.tied(0.5, 0.505))
+		suite.False(tolerance.tied(0.5, 0.52))
+
+		// relative for l2
+		tolerance = newScoreTolerance(metric.L2)
+		suite.True(tolerance.tied(-1000, -1005))
+		suite.False(tolerance.tied(-1000, -1020))
+
+		// exact for hamming
+		tolerance = newScoreTolerance(metric.HAMMING)
+		suite.False(tolerance.tied(1, 1.001))
+	})
+}
+
+func (suite *ResultSuite) TestResult_FillScoreField() {
+	const (
+		nq   = 1
+		topk = 3
+	)
+	suite.True(IsScoreFieldRequested([]int64{common.StartOfUserFieldID, common.ScoreField}))
+	suite.False(IsScoreFieldRequested([]int64{common.StartOfUserFieldID}))
+
+	ids := []int64{1, 2, 3}
+	scores := []float32{0.9, 0.8, 0.7}
+	data := genSearchResultData(nq, topk, ids, scores, []int64{3})
+	// a user field named "$score"
+	data.FieldsData = []*schemapb.FieldData{
+		{
+			Type:      schemapb.DataType_Int64,
+			FieldName: common.ScoreFieldName,
+			FieldId:   common.StartOfUserFieldID,
+			Field: &schemapb.FieldData_Scalars{
+				Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{
+						LongData: &schemapb.LongArray{Data: []int64{10, 20, 30}},
 					},
-					resultOffsets: [][]int64{{0, 2, 4}, {0, 2, 4}},
-					offsets:       []int64{0, 1},
-					topk:          2,
-					nq:            3,
-					qi:            0,
 				},
-				want: 0,
+			},
+		},
+	}
+	result, err := EncodeSearchResultData(data, nq, topk, "IP")
+	suite.Require().NoError(err)
+
+	// fill twice, score field shall be replaced
+	suite.Require().NoError(FillScoreField(result, []int64{common.StartOfUserFieldID, common.ScoreField}))
+	suite.Require().NoError(FillScoreField(result, []int64{common.StartOfUserFieldID, common.ScoreField}))
+
+	decoded, err := DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Require().Equal(2, len(decoded[0].GetFieldsData()))
+	suite.EqualValues(common.StartOfUserFieldID, decoded[0].GetFieldsData()[0].GetFieldId())
+	suite.Equal([]int64{10, 20, 30}, decoded[0].GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	scoreField := decoded[0].GetFieldsData()[1]
+	suite.EqualValues(common.ScoreField, scoreField.GetFieldId())
+	suite.Equal(common.ScoreFieldName, scoreField.GetFieldName())
+	suite.Equal(scores, scoreField.GetScalars().GetFloatData().GetData())
+
+	// empty result
+	suite.NoError(FillScoreField(&internalpb.SearchResults{}, nil))
+}
+
+func (suite *ResultSuite) TestResult_RetrieveFieldsOrder() {
+	const (
+		Dim                  = 8
+		PkFieldID            = common.StartOfUserFieldID
+		Int64FieldID         = common.StartOfUserFieldID + 1
+		FloatVectorFieldID   = common.StartOfUserFieldID + 2
+		FloatVectorFieldName = "FloatVectorField"
+	)
+	FloatVector := []float32{1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0, 11.0, 22.0, 33.0, 44.0, 55.0, 66.0, 77.0, 88.0}
+
+	// fields in schema order
+	result := &internalpb.RetrieveResults{
+		Ids: &schemapb.IDs{
+			IdField: &schemapb.IDs_IntId{
+				IntId: &schemapb.LongArray{
+					Data: []int64{0, 1},
+				},
+			},
+		},
+		FieldsData: []*schemapb.FieldData{
+			genFieldData("pk", PkFieldID, schemapb.DataType_Int64, []int64{0, 1}, 1),
+			genFieldData("Int64Field", Int64FieldID, schemapb.DataType_Int64, []int64{11, 22}, 1),
+			genFieldData(FloatVectorFieldName, FloatVectorFieldID, schemapb.DataType_FloatVector, FloatVector, Dim),
+		},
+	}
+
+	outputFields := []int64{FloatVectorFieldID, PkFieldID, Int64FieldID}
+	reducer := newDefaultLimitReducer(&querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			Limit:          typeutil.Unlimited,
+			OutputFieldsId: outputFields,
+		},
+	}, nil)
+	reduced, err := reducer.Reduce(context.Background(), []*internalpb.RetrieveResults{result})
+	suite.Require().NoError(err)
+	suite.Equal(outputFields, lo.Map(reduced.GetFieldsData(), func(fieldData *schemapb.FieldData, _ int) int64 {
+		return fieldData.GetFieldId()
+	}))
+	suite.InDeltaSlice(FloatVector, reduced.GetFieldsData()[0].GetVectors().GetFloatVector().GetData(), 10e-10)
+
+	// fields not requested are kept at last
+	sorted := SortFieldsDataByOutputFields(result.GetFieldsData(), []int64{Int64FieldID})
+	suite.Equal([]int64{Int64FieldID, PkFieldID, FloatVectorFieldID}, lo.Map(sorted, func(fieldData *schemapb.FieldData, _ int) int64 {
+		return fieldData.GetFieldId()
+	}))
+
+	// score field placed at requested position
+	data := genSearchResultData(1, 2, []int64{1, 2}, []float32{0.9, 0.8}, []int64{2})
+	data.FieldsData = []*schemapb.FieldData{
+		genFieldData("Int64Field", Int64FieldID, schemapb.DataType_Int64, []int64{11, 22}, 1),
+	}
+	searchResult, err := EncodeSearchResultData(data, 1, 2, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillScoreField(searchResult, []int64{common.ScoreField, Int64FieldID}))
+	decoded, err := DecodeSearchResults([]*internalpb.SearchResults{searchResult})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{common.ScoreField, Int64FieldID}, lo.Map(decoded[0].GetFieldsData(), func(fieldData *schemapb.FieldData, _ int) int64 {
+		return fieldData.GetFieldId()
+	}))
+}
+
+func (suite *ResultSuite) TestResult_FilterSearchResultsByScore() {
+	const Int64FieldID = common.StartOfUserFieldID + 1
+	genResult := func(scores []float32, metricType string) *internalpb.SearchResults {
+		data := &schemapb.SearchResultData{
+			NumQueries: 2,
+			TopK:       2,
+			Ids: &schemapb.IDs{
+				IdField: &schemapb.IDs_IntId{
+					IntId: &schemapb.LongArray{
+						Data: []int64{1, 2, 3, 4},
+					},
+				},
+			},
+			Scores: scores,
+			Topks:  []int64{2, 2},
+			FieldsData: []*schemapb.FieldData{
+				genFieldData("Int64Field", Int64FieldID, schemapb.DataType_Int64, []int64{11, 22, 33, 44}, 1),
 			},
 		}
-		for _, tt := range tests {
-			suite.Run(tt.name, func() {
-				if got := SelectSearchResultData(tt.args.dataArray, tt.args.resultOffsets, tt.args.offsets, tt.args.qi); got != tt.want {
-					suite.T().Errorf("SelectSearchResultData() = %v, want %v", got, tt.want)
-				}
-			})
-		}
+		result, err := EncodeSearchResultData(data, 2, 2, metricType)
+		suite.Require().NoError(err)
+		return result
+	}
+	decode := func(result *internalpb.SearchResults) *schemapb.SearchResultData {
+		datas, err := DecodeSearchResults([]*internalpb.SearchResults{result})
+		suite.Require().NoError(err)
+		suite.Require().Len(datas, 1)
+		return datas[0]
+	}
+
+	suite.Run("similarity", func() {
+		result := genResult([]float32{0.9, 0.4, 0.3, 0.2}, metric.IP)
+		err := FilterSearchResultsByScore(result, 0.35)
+		suite.Require().NoError(err)
+		data := decode(result)
+		suite.Equal([]int64{2, 0}, data.GetTopks())
+		suite.Equal([]int64{1, 2}, data.GetIds().GetIntId().GetData())
+		suite.Equal([]float32{0.9, 0.4}, data.GetScores())
+		suite.Equal([]int64{11, 22}, data.GetFieldsData()[0].GetScalars().GetLongData().GetData())
 	})
 
-	suite.Run("Integer ID with bad score", func() {
-		tests := []struct {
-			name string
-			args args
-			want int
-		}{
-			{
-				args: args{
-					dataArray: []*schemapb.SearchResultData{
-						{
-							Ids: &schemapb.IDs{
-								IdField: &schemapb.IDs_IntId{
-									IntId: &schemapb.LongArray{
-										Data: []int64{11, 9, 7, 5, 3, 1},
-									},
-								},
-							},
-							Scores: []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32},
-							Topks:  []int64{2, 2, 2},
-						},
-						{
-							Ids: &schemapb.IDs{
-								IdField: &schemapb.IDs_IntId{
-									IntId: &schemapb.LongArray{
-										Data: []int64{12, 10, 8, 6, 4, 2},
-									},
-								},
-							},
-							Scores: []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32},
-							Topks:  []int64{2, 2, 2},
-						},
+	suite.Run("distance", func() {
+		// distances are negated in search results
+		result := genResult([]float32{-0.1, -0.5, -0.2, -0.8}, metric.L2)
+		err := FilterSearchResultsByScore(result, 0.3)
+		suite.Require().NoError(err)
+		data := decode(result)
+		suite.Equal([]int64{1, 1}, data.GetTopks())
+		suite.Equal([]int64{1, 3}, data.GetIds().GetIntId().GetData())
+		suite.Equal([]int64{11, 33}, data.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	})
+
+	suite.Run("all_dropped", func() {
+		result := genResult([]float32{0.9, 0.4, 0.3, 0.2}, metric.COSINE)
+		err := FilterSearchResultsByScore(result, 0.95)
+		suite.Require().NoError(err)
+		suite.Nil(result.GetSlicedBlob())
+	})
+}
+
+func (suite *ResultSuite) TestResult_ReduceSearchResultsPartial() {
+	const (
+		nq   = 1
+		topk = 2
+	)
+	data := genSearchResultData(nq, topk, []int64{1, 2}, []float32{0.9, 0.8}, []int64{2})
+	result, err := EncodeSearchResultData(data, nq, topk, "IP")
+	suite.Require().NoError(err)
+
+	// empty partial result shall still mark reduced result partial
+	partial := &internalpb.SearchResults{Status: merr.Success(), IsPartial: true, TimedOutNodes: []int64{2}}
+	reduced, err := ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result, partial}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.True(reduced.GetIsPartial())
+	suite.EqualValues([]int64{2}, reduced.GetTimedOutNodes())
+
+	result.IsPartial = false
+	other, err := EncodeSearchResultData(data, nq, topk, "IP")
+	suite.Require().NoError(err)
+	reduced, err = ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result, other}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.False(reduced.GetIsPartial())
+}
+
+func (suite *ResultSuite) TestResult_ReduceSearchResultsTruncated() {
+	const (
+		nq   = 2
+		topk = 2
+	)
+	data1 := genSearchResultData(nq, topk, []int64{1, 2, 5}, []float32{0.9, 0.8, 0.7}, []int64{2, 1})
+	data2 := genSearchResultData(nq, topk, []int64{3, 5}, []float32{0.85, 0.6}, []int64{1, 1})
+	result1, err := EncodeSearchResultData(data1, nq, topk, "IP")
+	suite.Require().NoError(err)
+	result2, err := EncodeSearchResultData(data2, nq, topk, "IP")
+	suite.Require().NoError(err)
+
+	// pk 2 of the first query is dropped by topK, the duplicated pk 5 of the second query is not counted
+	reduced, err := ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result1, result2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Equal([]int64{2, 1}, reduced.GetTopks())
+	suite.Equal([]bool{true, false}, reduced.GetTruncated())
+
+	// truncated flags of inputs are kept
+	result1.Truncated = []bool{false, true}
+	reduced, err = ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result1, result2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Equal([]bool{true, true}, reduced.GetTruncated())
+
+	// counts filled from the result data
+	result, err := EncodeSearchResultData(data1, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillResultCounts(result))
+	suite.Equal([]int64{2, 1}, result.GetTopks())
+	suite.Equal([]bool{false, false}, result.GetTruncated())
+
+	empty := &internalpb.SearchResults{NumQueries: nq}
+	suite.Require().NoError(FillResultCounts(empty))
+	suite.Equal([]int64{0, 0}, empty.GetTopks())
+	suite.Equal([]bool{false, false}, empty.GetTruncated())
+}
+
+func (suite *ResultSuite) TestResult_ReduceSearchResultsScanned() {
+	const (
+		nq   = 1
+		topk = 2
+	)
+	data := genSearchResultData(nq, topk, []int64{1, 2}, []float32{0.9, 0.8}, []int64{2})
+	result1, err := EncodeSearchResultData(data, nq, topk, "IP")
+	suite.Require().NoError(err)
+	result1.ScannedSegments, result1.ScannedRows = 2, 300
+	result2, err := EncodeSearchResultData(data, nq, topk, "IP")
+	suite.Require().NoError(err)
+	result2.ScannedSegments, result2.ScannedRows = 1, 100
+
+	reduced, err := ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result1, result2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.EqualValues(3, reduced.GetScannedSegments())
+	suite.EqualValues(400, reduced.GetScannedRows())
+
+	// segments without hit are scanned too
+	empty := &internalpb.SearchResults{Status: merr.Success(), ScannedSegments: 1, ScannedRows: 50}
+	reduced, err = ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result2, empty}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.EqualValues(2, reduced.GetScannedSegments())
+	suite.EqualValues(150, reduced.GetScannedRows())
+}
+
+func (suite *ResultSuite) TestResult_ReduceSearchResultsWithExclusion() {
+	const (
+		nq   = 2
+		topk = 2
+	)
+	// results are searched with topK widened by the excluded pks
+	data1 := genSearchResultData(nq, topk+2, []int64{1, 2, 3, 6, 7}, []float32{0.9, 0.8, 0.7, 0.9, 0.5}, []int64{3, 2})
+	data2 := genSearchResultData(nq, topk+2, []int64{4, 5, 8}, []float32{0.85, 0.6, 0.8}, []int64{2, 1})
+	result1, err := EncodeSearchResultData(data1, nq, topk+2, "IP")
+	suite.Require().NoError(err)
+	result2, err := EncodeSearchResultData(data2, nq, topk+2, "IP")
+	suite.Require().NoError(err)
+
+	excluded := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 4, 6}}}}
+	ctx := WithExcludedPKs(context.Background(), excluded)
+
+	// topK is filled by the next best hits which are not excluded
+	reduced, err := ReduceSearchResults(ctx, []*internalpb.SearchResults{result1, result2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	data, err := DecodeSearchResults([]*internalpb.SearchResults{reduced})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{2, 3, 8, 7}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{0.8, 0.7, 0.8, 0.5}, data[0].GetScores())
+	suite.Equal([]int64{2, 2}, reduced.GetTopks())
+	// pk 5 of the first query is left, excluded pks are not counted
+	suite.Equal([]bool{true, false}, reduced.GetTruncated())
+
+	// excluded hits are dropped from single result as well
+	result1, err = EncodeSearchResultData(data1, nq, topk+2, "IP")
+	suite.Require().NoError(err)
+	reduced, err = ReduceSearchResults(ctx, []*internalpb.SearchResults{result1}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	data, err = DecodeSearchResults([]*internalpb.SearchResults{reduced})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{2, 3, 7}, data[0].GetIds().GetIntId().GetData())
+	suite.Equal([]int64{2, 1}, reduced.GetTopks())
+
+	// no exclusion
+	suite.Equal(context.Background(), WithExcludedPKs(context.Background(), &schemapb.IDs{}))
+}
+
+func (suite *ResultSuite) TestResult_ReduceSearchResultDataWithSegmentID() {
+	const (
+		nq   = 1
+		topk = 3
+	)
+	// pk 2 is found in both segments, the best score occurrence is in segment 20
+	data1 := genSearchResultData(nq, topk, []int64{1, 2, 3}, []float32{0.9, 0.5, 0.4}, []int64{3})
+	AppendSegmentIDField(data1, 10)
+	data2 := genSearchResultData(nq, topk, []int64{2, 4}, []float32{0.8, 0.1}, []int64{2})
+	AppendSegmentIDField(data2, 20)
+
+	reduced, err := ReduceSearchResultData(context.Background(), []*schemapb.SearchResultData{data1, data2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 2, 3}, reduced.GetIds().GetIntId().GetData())
+	suite.Equal([]float32{0.9, 0.8, 0.4}, reduced.GetScores())
+	suite.Require().Equal(1, len(reduced.GetFieldsData()))
+	segmentField := reduced.GetFieldsData()[0]
+	suite.EqualValues(common.SegmentIDField, segmentField.GetFieldId())
+	suite.Equal(common.SegmentIDFieldName, segmentField.GetFieldName())
+	suite.Equal([]int64{10, 20, 10}, segmentField.GetScalars().GetLongData().GetData())
+}
+
+func (suite *ResultSuite) TestResult_ReduceSearchResultDataWithInsertionOrder() {
+	const (
+		nq   = 1
+		topk = 3
+	)
+	// pk 3 is inserted before pk 1 and 2 with the same score
+	data1 := genSearchResultData(nq, topk, []int64{1, 2}, []float32{0.9, 0.5}, []int64{2})
+	appendLongPseudoField(data1, common.InsertionTsField, common.InsertionTsFieldName, []int64{200, 100})
+	data2 := genSearchResultData(nq, topk, []int64{3, 4}, []float32{0.9, 0.5}, []int64{2})
+	appendLongPseudoField(data2, common.InsertionTsField, common.InsertionTsFieldName, []int64{100, 0})
+
+	// ordered by pk by default
+	reduced, err := ReduceSearchResultData(context.Background(), []*schemapb.SearchResultData{data1, data2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 3, 2}, reduced.GetIds().GetIntId().GetData())
+
+	for _, algorithm := range []string{ReduceAlgorithmHeap, ReduceAlgorithmSort} {
+		ctx := WithInsertionOrderTiebreak(WithReduceAlgorithm(context.Background(), algorithm), true)
+		reduced, err = ReduceSearchResultData(ctx, []*schemapb.SearchResultData{data1, data2}, nq, topk, "IP")
+		suite.Require().NoError(err)
+		suite.Equal([]int64{3, 1, 2}, reduced.GetIds().GetIntId().GetData(), algorithm)
+		suite.Equal([]float32{0.9, 0.9, 0.5}, reduced.GetScores())
+		// timestamp of pk 4 is unknown, ordered by pk
+		suite.Equal([]int64{100, 200, 100}, reduced.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	}
+}
+
+func (suite *ResultSuite) TestResult_SegmentHitDistributions() {
+	const (
+		nq   = 2
+		topk = 2
+	)
+	// segment 10 holds the best hits of the first query, while segment 20 contributes nothing to it
+	data1 := genSearchResultData(nq, topk, []int64{1, 2, 5}, []float32{0.9, 0.8, 0.4}, []int64{2, 1})
+	AppendSegmentIDField(data1, 10)
+	data2 := genSearchResultData(nq, topk, []int64{3, 4}, []float32{0.7, 0.6}, []int64{1, 1})
+	AppendSegmentIDField(data2, 20)
+	result1, err := EncodeSearchResultData(data1, nq, topk, "IP")
+	suite.Require().NoError(err)
+	result2, err := EncodeSearchResultData(data2, nq, topk, "IP")
+	suite.Require().NoError(err)
+
+	reduced, err := ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result1, result2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	expected := []*internalpb.SegmentHitDistribution{
+		{Segments: []*internalpb.SegmentHits{{SegmentID: 10, Hits: 2}}},
+		{Segments: []*internalpb.SegmentHits{{SegmentID: 10, Hits: 1}, {SegmentID: 20, Hits: 1}}},
+	}
+	suite.Equal(expected, reduced.GetSegmentHitDistributions())
+
+	// recounted from the result data
+	reduced.SegmentHitDistributions = nil
+	suite.Require().NoError(FillSegmentHitDistributions(reduced))
+	suite.Equal(expected, reduced.GetSegmentHitDistributions())
+
+	// segment ids not returned
+	result, err := EncodeSearchResultData(genSearchResultData(nq, topk, []int64{1, 2}, []float32{0.9, 0.8}, []int64{1, 1}), nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillSegmentHitDistributions(result))
+	suite.Nil(result.GetSegmentHitDistributions())
+}
+
+func (suite *ResultSuite) TestResult_SegmentHitDistributionsWithFlushTs() {
+	const (
+		nq   = 1
+		topk = 3
+	)
+	// segment 10 is sealed, while segment 20 is growing and not flushed yet
+	data1 := genSearchResultData(nq, topk, []int64{1, 2}, []float32{0.9, 0.7}, []int64{2})
+	AppendSegmentIDField(data1, 10)
+	AppendSegmentFlushTsField(data1, 1000)
+	data2 := genSearchResultData(nq, topk, []int64{3}, []float32{0.8}, []int64{1})
+	AppendSegmentIDField(data2, 20)
+	AppendSegmentFlushTsField(data2, 0)
+	result1, err := EncodeSearchResultData(data1, nq, topk, "IP")
+	suite.Require().NoError(err)
+	result2, err := EncodeSearchResultData(data2, nq, topk, "IP")
+	suite.Require().NoError(err)
+
+	reduced, err := ReduceSearchResults(context.Background(), []*internalpb.SearchResults{result1, result2}, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Equal([]*internalpb.SegmentHitDistribution{
+		{Segments: []*internalpb.SegmentHits{{SegmentID: 10, Hits: 2, FlushTs: 1000}, {SegmentID: 20, Hits: 1}}},
+	}, reduced.GetSegmentHitDistributions())
+}
+
+func (suite *ResultSuite) TestResult_ClearSearchResultsOfQueries() {
+	const (
+		nq   = 3
+		topk = 2
+	)
+	data := genSearchResultData(nq, topk, []int64{1, 2, 3, 4, 5}, []float32{0.9, 0.8, 0.7, 0.6, 0.5}, []int64{2, 1, 2})
+	result, err := EncodeSearchResultData(data, nq, topk, "IP")
+	suite.Require().NoError(err)
+	result.Truncated = []bool{false, true, false}
+
+	suite.Require().NoError(ClearSearchResultsOfQueries(result, []int{1}))
+	suite.Equal([]int64{2, 0, 2}, result.GetTopks())
+	suite.Equal([]bool{false, false, false}, result.GetTruncated())
+	decoded, err := DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 2, 4, 5}, decoded[0].GetIds().GetIntId().GetData())
+	suite.Equal([]float32{0.9, 0.8, 0.6, 0.5}, decoded[0].GetScores())
+
+	// all hits cleared
+	suite.Require().NoError(ClearSearchResultsOfQueries(result, []int{0, 2}))
+	suite.Equal([]int64{0, 0, 0}, result.GetTopks())
+	suite.Nil(result.GetSlicedBlob())
+}
+
+func (suite *ResultSuite) TestResult_FillRankScores() {
+	const (
+		nq   = 2
+		topk = 4
+	)
+	result, err := EncodeSearchResultData(genSearchResultData(nq, topk, []int64{1, 2, 3, 4, 5, 6}, []float32{0.9, 0.8, 0.8, 0.7, 0.6, 0.5}, []int64{4, 2}), nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillRankScores(result))
+	suite.Equal([]float32{1, 0.75, 0.5, 0.25, 1, 0.5}, result.GetRankScores())
+
+	// stable across calls
+	suite.Require().NoError(FillRankScores(result))
+	suite.Equal([]float32{1, 0.75, 0.5, 0.25, 1, 0.5}, result.GetRankScores())
+
+	// empty result
+	empty := &internalpb.SearchResults{RankScores: []float32{1}}
+	suite.Require().NoError(FillRankScores(empty))
+	suite.Nil(empty.GetRankScores())
+}
+
+func (suite *ResultSuite) TestResult_PartitionTopks() {
+	const (
+		nq   = 2
+		topk = 3
+	)
+	data := genSearchResultData(nq, topk, []int64{1, 2, 3, 4, 5}, []float32{0.9, 0.8, 0.7, 0.6, 0.5}, []int64{3, 2})
+	data.FieldsData = []*schemapb.FieldData{{
+		Type:      schemapb.DataType_Int64,
+		FieldName: common.PartitionIDFieldName,
+		FieldId:   common.PartitionIDField,
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{10, 20, 10, 20, 20}}},
+			},
+		},
+	}}
+
+	datas, err := typeutil2.SplitSearchResultDataByPartition(data)
+	suite.Require().NoError(err)
+	concat := typeutil2.ConcatPartitionSearchResultData([]int64{20, 10, 30}, datas, nq, topk)
+
+	result, err := EncodeSearchResultData(concat, nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillPartitionTopks(result, []int64{20, 10, 30}))
+	suite.Require().Len(result.GetPartitionTopks(), 3)
+	suite.Equal([]int64{1, 2}, result.GetPartitionTopks()[0].GetTopks())
+	suite.Equal([]int64{2, 0}, result.GetPartitionTopks()[1].GetTopks())
+	suite.Equal([]int64{0, 0}, result.GetPartitionTopks()[2].GetTopks())
+}
+
+func (suite *ResultSuite) TestResult_FillScoreStats() {
+	const (
+		nq   = 3
+		topk = 3
+	)
+	result, err := EncodeSearchResultData(genSearchResultData(nq, topk, []int64{1, 2, 3, 4, 5}, []float32{0.9, 0.6, 0.3, 0.8, 0.4}, []int64{3, 0, 2}), nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillScoreStats(result))
+	suite.Require().Len(result.GetScoreStats(), nq)
+	suite.EqualValues(3, result.GetScoreStats()[0].GetCount())
+	suite.InDelta(0.3, result.GetScoreStats()[0].GetMin(), 1e-6)
+	suite.InDelta(0.9, result.GetScoreStats()[0].GetMax(), 1e-6)
+	suite.InDelta(0.6, result.GetScoreStats()[0].GetMean(), 1e-6)
+	suite.EqualValues(0, result.GetScoreStats()[1].GetCount())
+	suite.EqualValues(2, result.GetScoreStats()[2].GetCount())
+	suite.InDelta(0.6, result.GetScoreStats()[2].GetMean(), 1e-6)
+
+	// distances of L2 are negated in results
+	result, err = EncodeSearchResultData(genSearchResultData(1, 2, []int64{1, 2}, []float32{-1, -3}, []int64{2}), 1, 2, "L2")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillScoreStats(result))
+	suite.InDelta(1, result.GetScoreStats()[0].GetMin(), 1e-6)
+	suite.InDelta(3, result.GetScoreStats()[0].GetMax(), 1e-6)
+	suite.InDelta(2, result.GetScoreStats()[0].GetMean(), 1e-6)
+
+	// empty result
+	empty := &internalpb.SearchResults{NumQueries: 1}
+	suite.Require().NoError(FillScoreStats(empty))
+	suite.Require().Len(empty.GetScoreStats(), 1)
+	suite.EqualValues(0, empty.GetScoreStats()[0].GetCount())
+}
+
+func (suite *ResultSuite) TestResult_FillPrimaryTopks() {
+	const (
+		nq   = 3
+		topk = 4
+	)
+	result, err := EncodeSearchResultData(genSearchResultData(nq, topk, []int64{1, 2, 3, 4, 5, 6, 7}, []float32{0.9, 0.8, 0.7, 0.6, 0.5, 0.4, 0.3}, []int64{4, 1, 2}), nq, topk, "IP")
+	suite.Require().NoError(err)
+	suite.Require().NoError(FillPrimaryTopks(result, 2))
+	suite.Equal([]int64{2, 1, 2}, result.GetPrimaryTopks())
+
+	// empty result
+	empty := &internalpb.SearchResults{NumQueries: 2}
+	suite.Require().NoError(FillPrimaryTopks(empty, 2))
+	suite.Equal([]int64{0, 0}, empty.GetPrimaryTopks())
+}
+
+func (suite *ResultSuite) TestResult_ReduceMemoryAccount() {
+	account := NewReduceMemoryAccount(100)
+	suite.NoError(account.Grow(60))
+	suite.ErrorIs(account.Grow(60), merr.ErrServiceMemoryLimitExceeded)
+	suite.EqualValues(60, account.Used())
+	account.Free()
+	suite.EqualValues(0, account.Used())
+
+	// nil account is unlimited
+	var nilAccount *ReduceMemoryAccount
+	suite.NoError(nilAccount.Grow(1 << 30))
+	// zero limit is unlimited
+	suite.NoError(NewReduceMemoryAccount(0).Grow(1 << 30))
+
+	const (
+		Int64FieldName = "Int64Field"
+		Int64FieldID   = common.StartOfUserFieldID + 1
+	)
+	genResult := func(ids []int64) *internalpb.RetrieveResults {
+		return &internalpb.RetrieveResults{
+			Ids: &schemapb.IDs{
+				IdField: &schemapb.IDs_IntId{
+					IntId: &schemapb.LongArray{
+						Data: ids,
 					},
-					resultOffsets: [][]int64{{0, 2, 4}, {0, 2, 4}},
-					offsets:       []int64{0, 1},
-					topk:          2,
-					nq:            3,
-					qi:            0,
 				},
-				want: -1,
+			},
+			FieldsData: []*schemapb.FieldData{
+				genFieldData(Int64FieldName, Int64FieldID, schemapb.DataType_Int64, ids, 1),
 			},
 		}
-		for _, tt := range tests {
-			suite.Run(tt.name, func() {
-				if got := SelectSearchResultData(tt.args.dataArray, tt.args.resultOffsets, tt.args.offsets, tt.args.qi); got != tt.want {
-					suite.T().Errorf("SelectSearchResultData() = %v, want %v", got, tt.want)
-				}
-			})
-		}
-	})
+	}
+	results := []*internalpb.RetrieveResults{genResult([]int64{1, 3, 5}), genResult([]int64{2, 4, 6})}
+
+	// 6 int64 values materialized
+	account = NewReduceMemoryAccount(40)
+	_, err := MergeInternalRetrieveResult(WithReduceMemoryAccount(context.Background(), account), results,
+		NewMergeParam(typeutil.Unlimited, make([]int64, 0), nil, false))
+	suite.ErrorIs(err, merr.ErrServiceMemoryLimitExceeded)
+
+	account = NewReduceMemoryAccount(48)
+	ret, err := MergeInternalRetrieveResult(WithReduceMemoryAccount(context.Background(), account), results,
+		NewMergeParam(typeutil.Unlimited, make([]int64, 0), nil, false))
+	suite.NoError(err)
+	suite.Equal([]int64{1, 2, 3, 4, 5, 6}, ret.GetIds().GetIntId().GetData())
+	suite.EqualValues(48, account.Used())
 }
 
 func (suite *ResultSuite) TestSort() {
@@ -0,0 +1,214 @@
+package segments
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/proto/segcorepb"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// newSampleRand returns the random source for sampling, fixed seed makes sampling reproducible.
+func newSampleRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// sampleReducer merges sampled results into a uniform random sample of the union.
+// Each result is weighted by the number of matched rows it is drawn from.
+type sampleReducer struct {
+	size           int64
+	seed           int64
+	outputFieldsID []int64
+	schema         *schemapb.CollectionSchema
+}
+
+type samplePick struct {
+	result int
+	offset int64
+}
+
+// weightedSample draws at most size rows without replacement from results with the given row numbers,
+// each result is weighted by its population, which is the number of matched rows its rows are drawn from.
+func weightedSample(rng *rand.Rand, rowNums []int, populations []int64, size int64) []samplePick {
+	// weights holds the population of each result not picked yet
+	weights := make([]int64, len(rowNums))
+	candidates := make([][]int, len(rowNums))
+	var remaining int64
+	for i, rowNum := range rowNums {
+		weights[i] = populations[i]
+		if weights[i] < int64(rowNum) {
+			weights[i] = int64(rowNum)
+		}
+		candidates[i] = rng.Perm(rowNum)
+		remaining += weights[i]
+	}
+
+	picks := make([]samplePick, 0, size)
+	for int64(len(picks)) < size && remaining > 0 {
+		target := rng.Int63n(remaining)
+		sel := 0
+		for ; sel < len(weights); sel++ {
+			if target < weights[sel] {
+				break
+			}
+			target -= weights[sel]
+		}
+
+		picks = append(picks, samplePick{result: sel, offset: int64(candidates[sel][0])})
+		candidates[sel] = candidates[sel][1:]
+		weights[sel]--
+		remaining--
+		// rows not returned by this result could not be picked
+		if len(candidates[sel]) == 0 {
+			remaining -= weights[sel]
+			weights[sel] = 0
+		}
+	}
+	return picks
+}
+
+// collectSamplePicks gathers the picked rows of results, ordered by pk as other retrieve results.
+func collectSamplePicks(picks []samplePick, ids []*schemapb.IDs, fieldsData [][]*schemapb.FieldData) (*schemapb.IDs, []*schemapb.FieldData, error) {
+	sort.Slice(picks, func(i, j int) bool {
+		return typeutil.ComparePK(
+			typeutil.GetPK(ids[picks[i].result], picks[i].offset),
+			typeutil.GetPK(ids[picks[j].result], picks[j].offset))
+	})
+
+	retIds := &schemapb.IDs{}
+	var retFieldsData []*schemapb.FieldData
+	if len(picks) > 0 {
+		retFieldsData = make([]*schemapb.FieldData, len(fieldsData[picks[0].result]))
+	}
+	var retSize int64
+	maxOutputSize := paramtable.Get().QuotaConfig.MaxOutputSize.GetAsInt64()
+	for _, pick := range picks {
+		typeutil.AppendPKs(retIds, typeutil.GetPK(ids[pick.result], pick.offset))
+		retSize += typeutil.AppendFieldData(retFieldsData, fieldsData[pick.result], pick.offset)
+		if retSize > maxOutputSize {
+			return nil, nil, fmt.Errorf("query results exceed the maxOutputSize Limit %d", maxOutputSize)
+		}
+	}
+	return retIds, retFieldsData, nil
+}
+
+func (r *sampleReducer) Reduce(ctx context.Context, results []*internalpb.RetrieveResults) (*internalpb.RetrieveResults, error) {
+	ret := &internalpb.RetrieveResults{
+		Status: merr.Success(),
+		Ids:    &schemapb.IDs{},
+	}
+
+	validResults := lo.Filter(results, func(result *internalpb.RetrieveResults, _ int) bool {
+		return result != nil && len(result.GetFieldsData()) > 0 && typeutil.GetSizeOfIDs(result.GetIds()) > 0
+	})
+
+	rowNums := make([]int, len(validResults))
+	populations := make([]int64, len(validResults))
+	for i, result := range validResults {
+		rowNums[i] = typeutil.GetSizeOfIDs(result.GetIds())
+		populations[i] = lo.Max([]int64{result.GetSamplePopulation(), int64(rowNums[i])})
+		ret.SamplePopulation += populations[i]
+	}
+
+	picks := weightedSample(newSampleRand(r.seed), rowNums, populations, r.size)
+	ids, fieldsData, err := collectSamplePicks(picks,
+		lo.Map(validResults, func(result *internalpb.RetrieveResults, _ int) *schemapb.IDs { return result.GetIds() }),
+		lo.Map(validResults, func(result *internalpb.RetrieveResults, _ int) []*schemapb.FieldData { return result.GetFieldsData() }))
+	if err != nil {
+		return nil, err
+	}
+	ret.Ids, ret.FieldsData = ids, fieldsData
+
+	requestCosts := lo.FilterMap(results, func(result *internalpb.RetrieveResults, _ int) (*internalpb.CostAggregation, bool) {
+		if paramtable.Get().QueryNodeCfg.EnableWorkerSQCostMetrics.GetAsBool() {
+			return result.GetCostAggregation(), true
+		}
+
+		if result.GetBase().GetSourceID() == paramtable.GetNodeID() {
+			return result.GetCostAggregation(), true
+		}
+
+		return nil, false
+	})
+	ret.CostAggregation = mergeRequestCost(requestCosts)
+
+	if err := typeutil2.FillRetrieveResultIfEmpty(typeutil2.NewInternalResult(ret), r.outputFieldsID, r.schema); err != nil {
+		return nil, fmt.Errorf("failed to fill internal retrieve results: %s", err.Error())
+	}
+	return ret, nil
+}
+
+func newSampleReducer(req *querypb.QueryRequest, schema *schemapb.CollectionSchema) *sampleReducer {
+	return &sampleReducer{
+		size:           req.GetReq().GetSampleSize(),
+		seed:           req.GetReq().GetSampleSeed(),
+		outputFieldsID: req.GetReq().GetOutputFieldsId(),
+		schema:         schema,
+	}
+}
+
+// sampleReducerSegcore samples the matched rows of segments, weighted by the matched rows of each segment,
+// so that at most sample size rows are merged and returned instead of all matched rows.
+// The number of matched rows is reported by SampleSegcorePopulation for the sampling across nodes.
+type sampleReducerSegcore struct {
+	req    *querypb.QueryRequest
+	schema *schemapb.CollectionSchema
+}
+
+func (r *sampleReducerSegcore) Reduce(ctx context.Context, results []*segcorepb.RetrieveResults) (*segcorepb.RetrieveResults, error) {
+	validResults := lo.Filter(results, func(result *segcorepb.RetrieveResults, _ int) bool {
+		return result != nil && len(result.GetFieldsData()) > 0 && typeutil.GetSizeOfIDs(result.GetIds()) > 0
+	})
+
+	rowNums := lo.Map(validResults, func(result *segcorepb.RetrieveResults, _ int) int {
+		return typeutil.GetSizeOfIDs(result.GetIds())
+	})
+	populations := lo.Map(rowNums, func(rowNum int, _ int) int64 { return int64(rowNum) })
+
+	picks := weightedSample(newSampleRand(r.req.GetReq().GetSampleSeed()), rowNums, populations, r.req.GetReq().GetSampleSize())
+	ids, fieldsData, err := collectSamplePicks(picks,
+		lo.Map(validResults, func(result *segcorepb.RetrieveResults, _ int) *schemapb.IDs { return result.GetIds() }),
+		lo.Map(validResults, func(result *segcorepb.RetrieveResults, _ int) []*schemapb.FieldData { return result.GetFieldsData() }))
+	if err != nil {
+		return nil, err
+	}
+	ret := &segcorepb.RetrieveResults{
+		Ids:        ids,
+		FieldsData: fieldsData,
+	}
+
+	outputFieldsID := r.req.GetReq().GetOutputFieldsId()
+	if err := typeutil2.FillRetrieveResultIfEmpty(typeutil2.NewSegcoreResults(ret), outputFieldsID, r.schema); err != nil {
+		return nil, fmt.Errorf("failed to fill segcore retrieve results: %s", err.Error())
+	}
+	ret.FieldsData = SortFieldsDataByOutputFields(ret.GetFieldsData(), outputFieldsID)
+	return ret, nil
+}
+
+// SampleSegcorePopulation returns the number of matched rows the segment results are sampled from.
+func SampleSegcorePopulation(results []*segcorepb.RetrieveResults) int64 {
+	return lo.SumBy(results, func(result *segcorepb.RetrieveResults) int64 {
+		return int64(typeutil.GetSizeOfIDs(result.GetIds()))
+	})
+}
+
+func newSampleReducerSegcore(req *querypb.QueryRequest, schema *schemapb.CollectionSchema) *sampleReducerSegcore {
+	return &sampleReducerSegcore{
+		req:    req,
+		schema: schema,
+	}
+}
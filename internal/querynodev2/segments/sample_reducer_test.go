@@ -0,0 +1,126 @@
+package segments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/proto/segcorepb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type SampleReducerSuite struct {
+	suite.Suite
+}
+
+func (suite *SampleReducerSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func TestSampleReducerSuite(t *testing.T) {
+	suite.Run(t, new(SampleReducerSuite))
+}
+
+func (suite *SampleReducerSuite) genResult(ids []int64, population int64) *internalpb.RetrieveResults {
+	return &internalpb.RetrieveResults{
+		Ids: &schemapb.IDs{
+			IdField: &schemapb.IDs_IntId{
+				IntId: &schemapb.LongArray{Data: ids},
+			},
+		},
+		FieldsData: []*schemapb.FieldData{
+			{
+				Type:    schemapb.DataType_Int64,
+				FieldId: common.StartOfUserFieldID,
+				Field: &schemapb.FieldData_Scalars{
+					Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{
+							LongData: &schemapb.LongArray{Data: ids},
+						},
+					},
+				},
+			},
+		},
+		SamplePopulation: population,
+	}
+}
+
+func (suite *SampleReducerSuite) TestReduce() {
+	results := []*internalpb.RetrieveResults{
+		suite.genResult([]int64{1, 3, 5, 7, 9}, 0),
+		suite.genResult([]int64{2, 4, 6}, 100),
+		nil,
+	}
+
+	r := &sampleReducer{size: 4, seed: 1}
+	res, err := r.Reduce(context.TODO(), results)
+	suite.Require().NoError(err)
+	ids := res.GetIds().GetIntId().GetData()
+	suite.Equal(4, len(ids))
+	suite.EqualValues(105, res.GetSamplePopulation())
+	suite.IsIncreasing(ids)
+	suite.Equal(ids, res.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+	// fixed seed shall be reproducible
+	res2, err := r.Reduce(context.TODO(), results)
+	suite.Require().NoError(err)
+	suite.Equal(ids, res2.GetIds().GetIntId().GetData())
+}
+
+func (suite *SampleReducerSuite) TestSampleSizeExceedRows() {
+	results := []*internalpb.RetrieveResults{
+		suite.genResult([]int64{1, 3}, 0),
+		suite.genResult([]int64{2}, 0),
+	}
+
+	r := &sampleReducer{size: 10}
+	res, err := r.Reduce(context.TODO(), results)
+	suite.Require().NoError(err)
+	suite.Equal([]int64{1, 2, 3}, res.GetIds().GetIntId().GetData())
+	suite.EqualValues(3, res.GetSamplePopulation())
+}
+
+func (suite *SampleReducerSuite) TestEmpty() {
+	r := &sampleReducer{size: 10, schema: &schemapb.CollectionSchema{}}
+	res, err := r.Reduce(context.TODO(), []*internalpb.RetrieveResults{})
+	suite.Require().NoError(err)
+	suite.EqualValues(0, res.GetSamplePopulation())
+}
+
+func (suite *SampleReducerSuite) TestReduceSegcore() {
+	genSegmentResult := func(ids ...int64) *segcorepb.RetrieveResults {
+		result := suite.genResult(ids, 0)
+		return &segcorepb.RetrieveResults{Ids: result.GetIds(), FieldsData: result.GetFieldsData()}
+	}
+	results := []*segcorepb.RetrieveResults{
+		genSegmentResult(1, 3, 5, 7, 9, 11, 13, 15),
+		genSegmentResult(2, 4, 6),
+		nil,
+	}
+	req := &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			SampleSize:     4,
+			SampleSeed:     1,
+			OutputFieldsId: []int64{common.StartOfUserFieldID},
+		},
+	}
+
+	r := newSampleReducerSegcore(req, nil)
+	res, err := r.Reduce(context.TODO(), results)
+	suite.Require().NoError(err)
+	ids := res.GetIds().GetIntId().GetData()
+	// only sampled rows are merged
+	suite.Equal(4, len(ids))
+	suite.IsIncreasing(ids)
+	suite.Equal(ids, res.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	suite.EqualValues(11, SampleSegcorePopulation(results))
+
+	res2, err := r.Reduce(context.TODO(), results)
+	suite.Require().NoError(err)
+	suite.Equal(ids, res2.GetIds().GetIntId().GetData())
+}
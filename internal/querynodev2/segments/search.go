@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -59,6 +60,7 @@ func searchSegments(ctx context.Context, segments []Segment, segType SegmentType
 				segmentsWithoutIndex = append(segmentsWithoutIndex, seg.ID())
 				mu.Unlock()
 			}
+			GetSegmentResidency().Touch(seg.ID())
 			// record search time
 			tr := timerecord.NewTimeRecorder("searchOnSegments")
 			searchResult, err := seg.Search(ctx, searchReq)
@@ -103,7 +105,18 @@ func SearchHistorical(ctx context.Context, manager *Manager, searchReq *SearchRe
 	if err != nil {
 		return nil, nil, err
 	}
-	searchResults, err := searchSegments(ctx, segments, SegmentTypeSealed, searchReq)
+	searchSegs := segments
+	if searchReq.preferCached {
+		var cold []Segment
+		searchSegs, cold = GetSegmentResidency().FilterLikelyResident(manager.Collection.Get(collID), segments)
+		if len(cold) > 0 {
+			searchReq.partial = true
+			log.Ctx(ctx).Debug("skip cold segments in prefer cached mode",
+				zap.Int64s("segmentIDs", lo.Map(cold, func(s Segment, _ int) int64 { return s.ID() })))
+		}
+	}
+	// all validated segments are returned to be unpinned
+	searchResults, err := searchSegments(ctx, searchSegs, SegmentTypeSealed, searchReq)
 	return searchResults, segments, err
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	storage "github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/initcore"
@@ -159,3 +160,60 @@ func (suite *SearchSuite) TestSearchGrowing() {
 func TestSearch(t *testing.T) {
 	suite.Run(t, new(SearchSuite))
 }
+
+type SearchHarnessSuite struct {
+	suite.Suite
+	chunkManager storage.ChunkManager
+
+	harness *SearchHarness
+}
+
+func (suite *SearchHarnessSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (suite *SearchHarnessSuite) SetupTest() {
+	ctx := context.Background()
+	chunkManagerFactory := storage.NewChunkManagerFactoryWithParam(paramtable.Get())
+	suite.chunkManager, _ = chunkManagerFactory.NewPersistentStorageChunkManager(ctx)
+	initcore.InitRemoteChunkManager(paramtable.Get())
+
+	suite.harness = NewSearchHarness(suite.chunkManager, 100, 10)
+}
+
+func (suite *SearchHarnessSuite) TearDownTest() {
+	suite.harness.Release()
+	ctx := context.Background()
+	suite.chunkManager.RemoveWithPrefix(ctx, paramtable.Get().MinioCfg.RootPath.GetValue())
+}
+
+func (suite *SearchHarnessSuite) TestSearch() {
+	ctx := context.Background()
+	nq := int64(2)
+
+	indexed, err := suite.harness.AddSealedSegment(ctx, 1, 100, IndexFaissIVFFlat)
+	suite.Require().NoError(err)
+	suite.True(indexed.ExistIndex(simpleFloatVecField.id))
+	bruteForce, err := suite.harness.AddSealedSegment(ctx, 2, 100, "")
+	suite.Require().NoError(err)
+	suite.False(bruteForce.ExistIndex(simpleFloatVecField.id))
+
+	req, err := suite.harness.GenSearchRequest(nq, indexed.ID(), bruteForce.ID())
+	suite.Require().NoError(err)
+	result, err := suite.harness.Search(ctx, req)
+	suite.Require().NoError(err)
+	suite.EqualValues(nq, result.GetNumQueries())
+
+	data, err := DecodeSearchResults([]*internalpb.SearchResults{result})
+	suite.Require().NoError(err)
+	suite.Equal([]int64{defaultTopK, defaultTopK}, data[0].GetTopks())
+
+	// deterministic for the same request
+	again, err := suite.harness.Search(ctx, req)
+	suite.Require().NoError(err)
+	suite.Equal(result.GetSlicedBlob(), again.GetSlicedBlob())
+}
+
+func TestSearchHarness(t *testing.T) {
+	suite.Run(t, new(SearchHarnessSuite))
+}
@@ -60,6 +60,12 @@ const (
 	SegmentTypeSealed  = commonpb.SegmentState_Sealed
 )
 
+// Delete apply modes, see paramtable queryNode.deleteApplyMode
+const (
+	DeleteApplyModeEager = "eager"
+	DeleteApplyModeLazy  = "lazy"
+)
+
 var ErrSegmentUnhealthy = errors.New("segment unhealthy")
 
 // IndexedFieldInfo contains binlog info of vector field
@@ -147,7 +153,22 @@ type LocalSegment struct {
 	size               int64
 	row                int64
 	lastDeltaTimestamp *atomic.Uint64
-	fieldIndexes       *typeutil.ConcurrentMap[int64, *IndexedFieldInfo]
+	// paths of the delta logs loaded, the deletes consumed from the delete stream are not counted
+	appliedDeltaLogs *typeutil.ConcurrentSet[string]
+	fieldIndexes     *typeutil.ConcurrentMap[int64, *IndexedFieldInfo]
+	// checkpoint timestamp of the segment when flushed, 0 for growing segment
+	flushTs uint64
+
+	pendingDeltaMu sync.Mutex // protects pendingDelta
+	// delta data loaded in lazy mode, applied on the first read
+	pendingDelta *storage.DeleteData
+
+	// dir path the segment data mmapped in, empty if loaded into memory
+	mmapDirPath string
+	// load info of sealed segment, to load the segment again
+	loadInfo *querypb.SegmentLoadInfo
+	// replaced by the segment reloaded, protected by ptrLock
+	replaced bool
 }
 
 func NewSegment(collection *Collection,
@@ -184,12 +205,28 @@ func NewSegment(collection *Collection,
 		baseSegment:        newBaseSegment(segmentID, partitionID, collectionID, shard, segmentType, version, startPosition),
 		ptr:                segmentPtr,
 		lastDeltaTimestamp: atomic.NewUint64(0),
+		appliedDeltaLogs:   typeutil.NewConcurrentSet[string](),
 		fieldIndexes:       typeutil.NewConcurrentMap[int64, *IndexedFieldInfo](),
+		mmapDirPath:        collection.MmapDirPath(),
+	}
+	if segmentType == SegmentTypeSealed {
+		// the delta position of sealed segment is its dml checkpoint when flushed
+		segment.flushTs = deltaPosition.GetTimestamp()
 	}
 
 	return segment, nil
 }
 
+// Mmapped returns whether the segment data is mmapped.
+func (s *LocalSegment) Mmapped() bool {
+	return len(s.mmapDirPath) > 0
+}
+
+// FlushTimestamp returns the timestamp the segment flushed at, 0 for growing segment.
+func (s *LocalSegment) FlushTimestamp() uint64 {
+	return s.flushTs
+}
+
 func (s *LocalSegment) isValid() bool {
 	return s.ptr != nil
 }
@@ -262,6 +299,11 @@ func (s *LocalSegment) LastDeltaTimestamp() uint64 {
 	return s.lastDeltaTimestamp.Load()
 }
 
+// AppliedDeltaLogs returns the paths of the delta logs loaded into the segment.
+func (s *LocalSegment) AppliedDeltaLogs() []string {
+	return s.appliedDeltaLogs.Collect()
+}
+
 func (s *LocalSegment) AddIndex(fieldID int64, info *IndexedFieldInfo) {
 	s.fieldIndexes.Insert(fieldID, info)
 }
@@ -317,6 +359,10 @@ func (s *LocalSegment) Search(ctx context.Context, searchReq *SearchRequest) (*S
 		zap.Int64("segmentID", s.ID()),
 		zap.String("segmentType", s.typ.String()),
 	)
+	if err := s.ApplyPendingDeltaData(); err != nil {
+		return nil, err
+	}
+
 	s.ptrLock.RLock()
 	defer s.ptrLock.RUnlock()
 
@@ -338,7 +384,7 @@ func (s *LocalSegment) Search(ctx context.Context, searchReq *SearchRequest) (*S
 	log = log.With(zap.Bool("withIndex", hasIndex))
 	log.Debug("search segment...")
 
-	var searchResult SearchResult
+	searchResult := SearchResult{segmentID: s.segmentID}
 	var status C.CStatus
 	GetSQPool().Submit(func() (any, error) {
 		tr := timerecord.NewTimeRecorder("cgoSearch")
@@ -359,6 +405,10 @@ func (s *LocalSegment) Search(ctx context.Context, searchReq *SearchRequest) (*S
 }
 
 func (s *LocalSegment) Retrieve(ctx context.Context, plan *RetrievePlan) (*segcorepb.RetrieveResults, error) {
+	if err := s.ApplyPendingDeltaData(); err != nil {
+		return nil, err
+	}
+
 	s.ptrLock.RLock()
 	defer s.ptrLock.RUnlock()
 
@@ -524,6 +574,10 @@ func (s *LocalSegment) Delete(primaryKeys []storage.PrimaryKey, timestamps []typ
 	if s.ptr == nil {
 		return merr.WrapErrSegmentNotLoaded(s.segmentID, "segment released")
 	}
+	// the delete is retried on the segment replacing this one
+	if s.replaced {
+		return merr.WrapErrSegmentNotLoaded(s.segmentID, "segment replaced")
+	}
 
 	cOffset := C.int64_t(0) // depre
 	cSize := C.int64_t(len(primaryKeys))
@@ -616,7 +670,7 @@ func (s *LocalSegment) LoadMultiFieldData(rowCount int64, fields []*datapb.Field
 			}
 		}
 
-		loadFieldDataInfo.appendMMapDirPath(paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue())
+		loadFieldDataInfo.appendMMapDirPath(s.mmapDirPath)
 	}
 
 	var status C.CStatus
@@ -669,7 +723,7 @@ func (s *LocalSegment) LoadFieldData(fieldID int64, rowCount int64, field *datap
 			return err
 		}
 	}
-	loadFieldDataInfo.appendMMapDirPath(paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue())
+	loadFieldDataInfo.appendMMapDirPath(s.mmapDirPath)
 
 	var status C.CStatus
 	GetDynamicPool().Submit(func() (any, error) {
@@ -736,6 +790,61 @@ func (s *LocalSegment) AddFieldDataInfo(rowCount int64, fields []*datapb.FieldBi
 }
 
 func (s *LocalSegment) LoadDeltaData(deltaData *storage.DeleteData) error {
+	if err := s.loadDeletedRecord(deltaData); err != nil {
+		return err
+	}
+	s.lastDeltaTimestamp.Store(deltaData.Tss[len(deltaData.Tss)-1])
+	return nil
+}
+
+// DeferDeltaData keeps the delta data in memory instead of applying it, used in lazy delete apply mode.
+// The delta data is applied before the first search or retrieve of segment,
+// so the results are identical to the eager mode.
+func (s *LocalSegment) DeferDeltaData(deltaData *storage.DeleteData) {
+	s.pendingDeltaMu.Lock()
+	defer s.pendingDeltaMu.Unlock()
+
+	if s.pendingDelta == nil {
+		s.pendingDelta = &storage.DeleteData{}
+	}
+	for i := range deltaData.Pks {
+		s.pendingDelta.Append(deltaData.Pks[i], deltaData.Tss[i])
+	}
+	// the delta logs are consumed, the later loading skips them as they were applied
+	if last := deltaData.Tss[len(deltaData.Tss)-1]; last > s.lastDeltaTimestamp.Load() {
+		s.lastDeltaTimestamp.Store(last)
+	}
+}
+
+// HasPendingDeltaData returns whether there is delta data deferred and not applied yet.
+func (s *LocalSegment) HasPendingDeltaData() bool {
+	s.pendingDeltaMu.Lock()
+	defer s.pendingDeltaMu.Unlock()
+	return s.pendingDelta != nil
+}
+
+// ApplyPendingDeltaData applies the deferred delta data if any, the delta data is applied only once.
+func (s *LocalSegment) ApplyPendingDeltaData() error {
+	s.pendingDeltaMu.Lock()
+	defer s.pendingDeltaMu.Unlock()
+
+	if s.pendingDelta == nil {
+		return nil
+	}
+	tr := timerecord.NewTimeRecorder("applyPendingDeltaData")
+	if err := s.loadDeletedRecord(s.pendingDelta); err != nil {
+		return err
+	}
+	log.Info("pending delta data applied",
+		zap.Int64("segmentID", s.ID()),
+		zap.Int64("rowNum", s.pendingDelta.RowCount),
+		zap.Duration("elapse", tr.ElapseSpan()),
+	)
+	s.pendingDelta = nil
+	return nil
+}
+
+func (s *LocalSegment) loadDeletedRecord(deltaData *storage.DeleteData) error {
 	pks, tss := deltaData.Pks, deltaData.Tss
 	rowNum := deltaData.RowCount
 
@@ -804,14 +913,52 @@ func (s *LocalSegment) LoadDeltaData(deltaData *storage.DeleteData) error {
 		return err
 	}
 
-	s.lastDeltaTimestamp.Store(tss[len(tss)-1])
-
 	log.Info("load deleted record done",
 		zap.Int64("rowNum", rowNum),
 		zap.String("segmentType", s.Type().String()))
 	return nil
 }
 
+// replace copies the deleted record of the old sealed segment and calls swap to serve s instead of it,
+// the old segment is locked meanwhile and refuses the deletes afterwards, so that no delete is lost.
+func (s *LocalSegment) replace(old *LocalSegment, swap func()) error {
+	// the deferred deletes are not in the deleted record yet
+	if err := old.ApplyPendingDeltaData(); err != nil {
+		return err
+	}
+
+	old.ptrLock.Lock()
+	defer old.ptrLock.Unlock()
+	if old.ptr == nil {
+		return merr.WrapErrSegmentNotLoaded(old.segmentID, "segment released")
+	}
+
+	s.ptrLock.RLock()
+	defer s.ptrLock.RUnlock()
+	if s.ptr == nil {
+		return merr.WrapErrSegmentNotLoaded(s.segmentID, "segment released")
+	}
+
+	/*
+		CStatus
+		CopyDeletedRecord(CSegmentInterface c_source, CSegmentInterface c_target);
+	*/
+	var status C.CStatus
+	GetDynamicPool().Submit(func() (any, error) {
+		status = C.CopyDeletedRecord(old.ptr, s.ptr)
+		return nil, nil
+	}).Await()
+	if err := HandleCStatus(&status, "CopyDeletedRecord failed"); err != nil {
+		return err
+	}
+	s.lastDeltaTimestamp.Store(old.lastDeltaTimestamp.Load())
+	s.appliedDeltaLogs.Upsert(old.appliedDeltaLogs.Collect()...)
+
+	old.replaced = true
+	swap()
+	return nil
+}
+
 func (s *LocalSegment) LoadIndex(indexInfo *querypb.FieldIndexInfo, fieldType schemapb.DataType) error {
 	loadIndexInfo, err := newLoadIndexInfo()
 	defer deleteLoadIndexInfo(loadIndexInfo)
@@ -819,7 +966,7 @@ func (s *LocalSegment) LoadIndex(indexInfo *querypb.FieldIndexInfo, fieldType sc
 		return err
 	}
 
-	err = loadIndexInfo.appendLoadIndexInfo(indexInfo, s.collectionID, s.partitionID, s.segmentID, fieldType)
+	err = loadIndexInfo.appendLoadIndexInfo(indexInfo, s.collectionID, s.partitionID, s.segmentID, fieldType, s.mmapDirPath)
 	if err != nil {
 		if loadIndexInfo.cleanLocalData() != nil {
 			log.Warn("failed to clean cached data on disk after append index failed",
@@ -903,6 +1050,7 @@ func (s *LocalSegment) Release() {
 	}
 
 	C.DeleteSegment(ptr)
+	GetSegmentResidency().Remove(s.ID())
 	log.Info("delete segment from memory",
 		zap.Int64("collectionID", s.collectionID),
 		zap.Int64("partitionID", s.partitionID),
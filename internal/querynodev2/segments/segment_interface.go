@@ -34,6 +34,8 @@ type Segment interface {
 	Version() int64
 	CASVersion(int64, int64) bool
 	StartPosition() *msgpb.MsgPosition
+	// FlushTimestamp returns the timestamp the segment flushed at, 0 for growing segment
+	FlushTimestamp() uint64
 	Type() SegmentType
 	RLock() error
 	RUnlock()
@@ -34,6 +34,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -69,16 +70,63 @@ var ErrReadDeltaMsgFailed = errors.New("ReadDeltaMsgFailed")
 
 type Loader interface {
 	// Load loads binlogs, and spawn segments,
+	// the segments loaded before a failure are returned along with the error.
 	// NOTE: make sure the ref count of the corresponding collection will never go down to 0 during this
 	Load(ctx context.Context, collectionID int64, segmentType SegmentType, version int64, segments ...*querypb.SegmentLoadInfo) ([]Segment, error)
 
 	LoadDeltaLogs(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog) error
 
+	// ReloadDeltaLogs loads the delta logs even if the segment has applied later deletes,
+	// to repair the deletes missed by the segment.
+	ReloadDeltaLogs(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog) error
+
 	// LoadBloomFilterSet loads needed statslog for RemoteSegment.
 	LoadBloomFilterSet(ctx context.Context, collectionID int64, version int64, infos ...*querypb.SegmentLoadInfo) ([]*pkoracle.BloomFilterSet, error)
 
 	// LoadIndex append index for segment and remove vector binlogs.
 	LoadIndex(ctx context.Context, segment *LocalSegment, info *querypb.SegmentLoadInfo, version int64) error
+
+	// Reload loads the loaded sealed segment again with the current mmap policy of its collection,
+	// and replaces the loaded one with it, the segment keeps serving while reloading.
+	Reload(ctx context.Context, segment *LocalSegment) (*LocalSegment, error)
+
+	// GetIndexLoadProgress returns the progress of loading index of segment.
+	GetIndexLoadProgress(segmentID int64) IndexLoadProgress
+
+	// GetLoadErrors returns the recent segment load failures, oldest first.
+	GetLoadErrors() []SegmentLoadError
+
+	// EstimateLoad estimates whether the segments could be loaded with the current resource usage,
+	// nothing is committed.
+	EstimateLoad(ctx context.Context, infos ...*querypb.SegmentLoadInfo) (*LoadFeasibility, error)
+}
+
+// IndexLoadProgress is the progress of loading index of segment.
+type IndexLoadProgress struct {
+	Loading   bool
+	StartTime time.Time
+	// EstimatedCompletion is zero if unknown
+	EstimatedCompletion time.Time
+}
+
+// LoadFeasibility is the estimation of loading segments against the node capacity.
+type LoadFeasibility struct {
+	Feasible bool
+	// why it's infeasible, empty if feasible
+	Reason string
+
+	// estimated resource needed by the segments
+	EstimatedMemory uint64
+	EstimatedDisk   uint64
+	// current usage, including the resource committed to loading segments
+	MemoryUsage uint64
+	DiskUsage   uint64
+	// usage after the segments loaded
+	ProjectedMemoryUsage uint64
+	ProjectedDiskUsage   uint64
+	// the max usage allowed
+	MemoryBudget uint64
+	DiskBudget   uint64
 }
 
 type LoadResource struct {
@@ -124,6 +172,8 @@ func NewLoader(
 		manager:         manager,
 		cm:              cm,
 		loadingSegments: typeutil.NewConcurrentMap[int64, *loadResult](),
+		indexLoading:    typeutil.NewConcurrentMap[int64, time.Time](),
+		loadErrors:      newLoadErrorHistory(paramtable.Get().QueryNodeCfg.LoadErrorHistorySize.GetAsInt()),
 	}
 
 	return loader
@@ -163,6 +213,14 @@ type segmentLoader struct {
 	// The channel will be closed as the segment loaded
 	loadingSegments   *typeutil.ConcurrentMap[int64, *loadResult]
 	committedResource LoadResource
+
+	// segments loading index, and the start time
+	indexLoading     *typeutil.ConcurrentMap[int64, time.Time]
+	indexLoadRateMut sync.Mutex
+	// rows per second of loading index
+	indexLoadRate float64
+
+	loadErrors *loadErrorHistory
 }
 
 var _ Loader = (*segmentLoader)(nil)
@@ -253,6 +311,8 @@ func (loader *segmentLoader) Load(ctx context.Context,
 			)
 			return err
 		}
+		// pages are populated while loading
+		GetSegmentResidency().Touch(segmentID)
 		loader.manager.Segment.Put(segmentType, segment)
 		newSegments.GetAndRemove(segmentID)
 		loaded.Insert(segmentID, segment)
@@ -268,26 +328,91 @@ func (loader *segmentLoader) Load(ctx context.Context,
 	log.Info("start to load segments in parallel",
 		zap.Int("segmentNum", len(infos)),
 		zap.Int("concurrencyLevel", concurrencyLevel))
+	loadedSegments := func() []Segment {
+		var result []Segment
+		loaded.Range(func(_ int64, s *LocalSegment) bool {
+			result = append(result, s)
+			return true
+		})
+		return result
+	}
 	err = funcutil.ProcessFuncParallel(len(infos),
 		concurrencyLevel, loadSegmentFunc, "loadSegmentFunc")
 	if err != nil {
 		log.Warn("failed to load some segments", zap.Error(err))
-		return nil, err
+		return loadedSegments(), err
 	}
 
 	// Wait for all segments loaded
 	if err := loader.waitSegmentLoadDone(ctx, segmentType, lo.Map(segments, func(info *querypb.SegmentLoadInfo, _ int) int64 { return info.GetSegmentID() })...); err != nil {
 		log.Warn("failed to wait the filtered out segments load done", zap.Error(err))
-		return nil, err
+		return loadedSegments(), err
 	}
 
 	log.Info("all segment load done")
-	var result []Segment
-	loaded.Range(func(_ int64, s *LocalSegment) bool {
-		result = append(result, s)
-		return true
+	return loadedSegments(), nil
+}
+
+func (loader *segmentLoader) Reload(ctx context.Context, segment *LocalSegment) (*LocalSegment, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", segment.Collection()),
+		zap.Int64("segmentID", segment.ID()),
+	)
+
+	if segment.Type() != SegmentTypeSealed || segment.loadInfo == nil {
+		return nil, merr.WrapErrParameterInvalid("loaded sealed segment", segment.Type().String(), "only loaded sealed segment could be reloaded")
+	}
+	collection := loader.manager.Collection.Get(segment.Collection())
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotFound(segment.Collection())
+	}
+
+	loadInfo := typeutil.Clone(segment.loadInfo)
+	// the indexes loaded afterwards are loaded as well
+	loadInfo.IndexInfos = lo.FilterMap(segment.Indexes(), func(info *IndexedFieldInfo, _ int) (*querypb.FieldIndexInfo, bool) {
+		return info.IndexInfo, info.IndexInfo != nil
+	})
+	// the deletes are copied from the loaded segment, including the streamed ones
+	loadInfo.Deltalogs = nil
+
+	// Filter out LOADING segments only
+	infos := loader.prepare(commonpb.SegmentState_SegmentStateNone, segment.Version(), loadInfo)
+	if len(infos) == 0 {
+		return nil, merr.WrapErrServiceUnavailable(fmt.Sprintf("segment %d is loading", segment.ID()))
+	}
+	defer loader.unregister(infos...)
+
+	resource, _, err := loader.requestResource(ctx, loadInfo)
+	if err != nil {
+		log.Warn("request resource failed", zap.Error(err))
+		return nil, err
+	}
+	defer loader.freeRequest(resource)
+
+	newSegment, err := NewSegment(collection, segment.ID(), segment.Partition(), segment.Collection(), segment.Shard(),
+		SegmentTypeSealed, segment.Version()+1, segment.StartPosition(), loadInfo.GetDeltaPosition())
+	if err != nil {
+		return nil, err
+	}
+	if err := loader.loadSegment(ctx, newSegment, loadInfo); err != nil {
+		log.Warn("failed to reload segment", zap.Error(err))
+		newSegment.Release()
+		return nil, err
+	}
+	err = newSegment.replace(segment, func() {
+		loader.manager.Segment.Put(SegmentTypeSealed, newSegment)
 	})
-	return result, nil
+	if err != nil {
+		log.Warn("failed to replace segment with the reloaded one", zap.Error(err))
+		newSegment.Release()
+		return nil, err
+	}
+	// the replaced one is released by the manager once the reads on it are done
+	GetSegmentResidency().Touch(newSegment.ID())
+	loader.notifyLoadFinish(loadInfo)
+
+	log.Info("segment reloaded", zap.Bool("mmapped", newSegment.Mmapped()), zap.Int64("version", newSegment.Version()))
+	return newSegment, nil
 }
 
 func (loader *segmentLoader) prepare(segmentType SegmentType, version int64, segments ...*querypb.SegmentLoadInfo) []*querypb.SegmentLoadInfo {
@@ -559,6 +684,7 @@ func (loader *segmentLoader) loadSegment(ctx context.Context,
 	defer debug.FreeOSMemory()
 
 	if segment.Type() == SegmentTypeSealed {
+		segment.loadInfo = loadInfo
 		fieldID2IndexInfo := make(map[int64]*querypb.FieldIndexInfo)
 		for _, indexInfo := range loadInfo.IndexInfos {
 			if len(indexInfo.GetIndexFilePaths()) > 0 {
@@ -603,6 +729,7 @@ func (loader *segmentLoader) loadSegment(ctx context.Context,
 		}
 	} else {
 		if err := segment.LoadMultiFieldData(loadInfo.GetNumOfRows(), loadInfo.BinlogPaths); err != nil {
+			loader.loadErrors.Record(segment.ID(), LoadPhaseGrowing, err)
 			return err
 		}
 	}
@@ -613,6 +740,7 @@ func (loader *segmentLoader) loadSegment(ctx context.Context,
 		pkStatsBinlogs, logType := loader.filterPKStatsBinlogs(loadInfo.Statslogs, pkField.GetFieldID())
 		err := loader.loadBloomFilter(ctx, segment.segmentID, segment.bloomFilterSet, pkStatsBinlogs, logType)
 		if err != nil {
+			loader.loadErrors.Record(segment.ID(), LoadPhaseGrowing, err)
 			return err
 		}
 	}
@@ -676,6 +804,7 @@ func (loader *segmentLoader) loadFieldsIndex(ctx context.Context,
 		indexInfo := fieldInfo.IndexInfo
 		err := loader.loadFieldIndex(ctx, segment, indexInfo)
 		if err != nil {
+			loader.loadErrors.Record(segment.ID(), LoadPhaseIndex, err)
 			return err
 		}
 
@@ -716,12 +845,41 @@ func (loader *segmentLoader) loadFieldIndex(ctx context.Context, segment *LocalS
 
 	// 2. use index path to update segment
 	indexInfo.IndexFilePaths = filteredPaths
-	fieldType, err := loader.getFieldType(segment.Collection(), indexInfo.FieldID)
+	field, err := loader.getFieldSchema(segment.Collection(), indexInfo.FieldID)
 	if err != nil {
 		return err
 	}
+	if err := checkIndexMetricType(field, indexInfo); err != nil {
+		log.Ctx(ctx).Warn("failed to load index with mismatched metric type",
+			zap.Int64("collection", segment.Collection()),
+			zap.Int64("segment", segment.ID()),
+			zap.Int64("fieldID", indexInfo.GetFieldID()),
+			zap.Int64("indexID", indexInfo.GetIndexID()),
+			zap.Error(err),
+		)
+		return err
+	}
 
-	return segment.LoadIndex(indexInfo, fieldType)
+	return segment.LoadIndex(indexInfo, field.GetDataType())
+}
+
+// checkIndexMetricType checks the metric type of index against the one declared in field schema,
+// an index built with wrong metric fails the load instead of serving results of another metric.
+// Nothing to check if either one has no metric type.
+func checkIndexMetricType(field *schemapb.FieldSchema, indexInfo *querypb.FieldIndexInfo) error {
+	declared, err := funcutil.GetAttrByKeyFromRepeatedKV(common.MetricTypeKey, append(field.GetTypeParams(), field.GetIndexParams()...))
+	if err != nil {
+		return nil
+	}
+	indexMetric, err := funcutil.GetAttrByKeyFromRepeatedKV(common.MetricTypeKey, indexInfo.GetIndexParams())
+	if err != nil {
+		return nil
+	}
+	if !strings.EqualFold(declared, indexMetric) {
+		return merr.WrapErrParameterInvalid(declared, indexMetric,
+			fmt.Sprintf("metric type of index %s mismatches the one declared by field %s", indexInfo.GetIndexName(), field.GetName()))
+	}
+	return nil
 }
 
 func (loader *segmentLoader) loadBloomFilter(ctx context.Context, segmentID int64, bfs *pkoracle.BloomFilterSet,
@@ -775,12 +933,31 @@ func (loader *segmentLoader) loadBloomFilter(ctx context.Context, segmentID int6
 }
 
 func (loader *segmentLoader) LoadDeltaLogs(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog) error {
+	err := loader.loadDeltaLogs(ctx, segment, deltaLogs, true)
+	if err != nil {
+		loader.loadErrors.Record(segment.ID(), LoadPhaseDeltaLog, err)
+	}
+	return err
+}
+
+func (loader *segmentLoader) ReloadDeltaLogs(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog) error {
+	err := loader.loadDeltaLogs(ctx, segment, deltaLogs, false)
+	if err != nil {
+		loader.loadErrors.Record(segment.ID(), LoadPhaseDeltaLog, err)
+	}
+	return err
+}
+
+// loadDeltaLogs loads the delete records of delta logs into segment,
+// the logs ending before the latest delete of segment are skipped if skipApplied.
+func (loader *segmentLoader) loadDeltaLogs(ctx context.Context, segment *LocalSegment, deltaLogs []*datapb.FieldBinlog, skipApplied bool) error {
 	dCodec := storage.DeleteCodec{}
 	var blobs []*storage.Blob
 	for _, deltaLog := range deltaLogs {
 		for _, bLog := range deltaLog.GetBinlogs() {
 			// the segment has applied the delta logs, skip it
-			if bLog.GetTimestampTo() > 0 && // this field may be missed in legacy versions
+			if skipApplied &&
+				bLog.GetTimestampTo() > 0 && // this field may be missed in legacy versions
 				bLog.GetTimestampTo() < segment.LastDeltaTimestamp() {
 				continue
 			}
@@ -803,11 +980,28 @@ func (loader *segmentLoader) LoadDeltaLogs(ctx context.Context, segment *LocalSe
 	if err != nil {
 		return err
 	}
+	applied := lo.Map(blobs, func(blob *storage.Blob, _ int) string { return blob.Key })
+	if deltaData.RowCount == 0 {
+		segment.appliedDeltaLogs.Upsert(applied...)
+		return nil
+	}
+
+	collection := loader.manager.Collection.Get(segment.Collection())
+	if collection != nil && collection.GetDeleteApplyMode() == DeleteApplyModeLazy {
+		log.Info("defer applying delete record until the first read",
+			zap.Int64("segmentID", segment.ID()),
+			zap.Int64("rowNum", deltaData.RowCount),
+		)
+		segment.DeferDeltaData(deltaData)
+		segment.appliedDeltaLogs.Upsert(applied...)
+		return nil
+	}
 
 	err = segment.LoadDeltaData(deltaData)
 	if err != nil {
 		return err
 	}
+	segment.appliedDeltaLogs.Upsert(applied...)
 	return nil
 }
 
@@ -929,12 +1123,68 @@ func (loader *segmentLoader) checkSegmentSize(ctx context.Context, segmentLoadIn
 	metrics.QueryNodeDiskUsedSize.WithLabelValues(fmt.Sprint(paramtable.GetNodeID())).Set(toMB(uint64(localDiskUsage)))
 	diskUsage := uint64(localDiskUsage) + loader.committedResource.DiskSize
 
-	mmapEnabled := len(paramtable.Get().QueryNodeCfg.MmapDirPath.GetValue()) > 0
+	mmapEnabled := len(mmapDirPathOf(loader.manager.Collection, segmentLoadInfos[0].GetCollectionID())) > 0
+	neededMem, neededDisk, maxSegmentSize, err := loader.estimateLoadResource(ctx, segmentLoadInfos)
+	if err != nil {
+		return 0, 0, err
+	}
+	predictMemUsage := memUsage + neededMem
+	predictDiskUsage := diskUsage + neededDisk
+
+	log.Info("predict memory and disk usage while loading (in MiB)",
+		zap.Float64("maxSegmentSize", toMB(maxSegmentSize)),
+		zap.Int("concurrency", concurrency),
+		zap.Float64("committedMemSize", toMB(loader.committedResource.MemorySize)),
+		zap.Float64("memUsage", toMB(memUsage)),
+		zap.Float64("committedDiskSize", toMB(loader.committedResource.DiskSize)),
+		zap.Float64("diskUsage", toMB(diskUsage)),
+		zap.Float64("predictMemUsage", toMB(predictMemUsage)),
+		zap.Float64("predictDiskUsage", toMB(predictDiskUsage)),
+		zap.Bool("mmapEnabled", mmapEnabled),
+	)
+
+	if !mmapEnabled && predictMemUsage > uint64(float64(totalMem)*paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat()) {
+		return 0, 0, fmt.Errorf("load segment failed, OOM if load, maxSegmentSize = %v MB, concurrency = %d, memUsage = %v MB, predictMemUsage = %v MB, totalMem = %v MB thresholdFactor = %f",
+			toMB(maxSegmentSize),
+			concurrency,
+			toMB(memUsage),
+			toMB(predictMemUsage),
+			toMB(totalMem),
+			paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat())
+	}
+
+	if mmapEnabled && memUsage > uint64(float64(totalMem)*paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat()) {
+		return 0, 0, fmt.Errorf("load segment failed, OOM if load, maxSegmentSize = %v MB, concurrency = %d, memUsage = %v MB, predictMemUsage = %v MB, totalMem = %v MB thresholdFactor = %f",
+			toMB(maxSegmentSize),
+			concurrency,
+			toMB(memUsage),
+			toMB(predictMemUsage),
+			toMB(totalMem),
+			paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat())
+	}
+
+	if predictDiskUsage > uint64(float64(paramtable.Get().QueryNodeCfg.DiskCapacityLimit.GetAsInt64())*paramtable.Get().QueryNodeCfg.MaxDiskUsagePercentage.GetAsFloat()) {
+		return 0, 0, fmt.Errorf("load segment failed, disk space is not enough, diskUsage = %v MB, predictDiskUsage = %v MB, totalDisk = %v MB, thresholdFactor = %f",
+			toMB(diskUsage),
+			toMB(predictDiskUsage),
+			toMB(uint64(paramtable.Get().QueryNodeCfg.DiskCapacityLimit.GetAsInt64())),
+			paramtable.Get().QueryNodeCfg.MaxDiskUsagePercentage.GetAsFloat())
+	}
+
+	return predictMemUsage - memUsage, predictDiskUsage - diskUsage, nil
+}
+
+// estimateLoadResource estimates the memory and disk needed to load the segments,
+// from the size of binlogs, indexes, statslogs and deltalogs,
+// the max memory needed by a single segment is returned as well.
+func (loader *segmentLoader) estimateLoadResource(ctx context.Context, segmentLoadInfos []*querypb.SegmentLoadInfo) (uint64, uint64, uint64, error) {
+	log := log.Ctx(ctx)
 	maxSegmentSize := uint64(0)
-	predictMemUsage := memUsage
-	predictDiskUsage := diskUsage
+	predictMemUsage := uint64(0)
+	predictDiskUsage := uint64(0)
 	for _, loadInfo := range segmentLoadInfos {
 		oldUsedMem := predictMemUsage
+		mmapEnabled := len(mmapDirPathOf(loader.manager.Collection, loadInfo.GetCollectionID())) > 0
 		vecFieldID2IndexInfo := make(map[int64]*querypb.FieldIndexInfo)
 		for _, fieldIndexInfo := range loadInfo.IndexInfos {
 			if fieldIndexInfo.EnableIndex {
@@ -954,7 +1204,7 @@ func (loader *segmentLoader) checkSegmentSize(ctx context.Context, segmentLoadIn
 						zap.Int64("indexBuildID", fieldIndexInfo.BuildID),
 						zap.Error(err),
 					)
-					return 0, 0, err
+					return 0, 0, 0, err
 				}
 				if mmapEnabled {
 					predictDiskUsage += neededMemSize + neededDiskSize
@@ -986,61 +1236,67 @@ func (loader *segmentLoader) checkSegmentSize(ctx context.Context, segmentLoadIn
 		}
 	}
 
-	log.Info("predict memory and disk usage while loading (in MiB)",
-		zap.Float64("maxSegmentSize", toMB(maxSegmentSize)),
-		zap.Int("concurrency", concurrency),
-		zap.Float64("committedMemSize", toMB(loader.committedResource.MemorySize)),
-		zap.Float64("memUsage", toMB(memUsage)),
-		zap.Float64("committedDiskSize", toMB(loader.committedResource.DiskSize)),
-		zap.Float64("diskUsage", toMB(diskUsage)),
-		zap.Float64("predictMemUsage", toMB(predictMemUsage)),
-		zap.Float64("predictDiskUsage", toMB(predictDiskUsage)),
-		zap.Bool("mmapEnabled", mmapEnabled),
-	)
+	return predictMemUsage, predictDiskUsage, maxSegmentSize, nil
+}
 
-	if !mmapEnabled && predictMemUsage > uint64(float64(totalMem)*paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat()) {
-		return 0, 0, fmt.Errorf("load segment failed, OOM if load, maxSegmentSize = %v MB, concurrency = %d, memUsage = %v MB, predictMemUsage = %v MB, totalMem = %v MB thresholdFactor = %f",
-			toMB(maxSegmentSize),
-			concurrency,
-			toMB(memUsage),
-			toMB(predictMemUsage),
-			toMB(totalMem),
-			paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat())
-	}
+func (loader *segmentLoader) EstimateLoad(ctx context.Context, infos ...*querypb.SegmentLoadInfo) (*LoadFeasibility, error) {
+	loader.mut.Lock()
+	committed := loader.committedResource
+	loader.mut.Unlock()
 
-	if mmapEnabled && memUsage > uint64(float64(totalMem)*paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat()) {
-		return 0, 0, fmt.Errorf("load segment failed, OOM if load, maxSegmentSize = %v MB, concurrency = %d, memUsage = %v MB, predictMemUsage = %v MB, totalMem = %v MB thresholdFactor = %f",
-			toMB(maxSegmentSize),
-			concurrency,
-			toMB(memUsage),
-			toMB(predictMemUsage),
-			toMB(totalMem),
-			paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat())
+	totalMem := hardware.GetMemoryCount()
+	usedMem := hardware.GetUsedMemoryCount()
+	if usedMem == 0 || totalMem == 0 {
+		return nil, errors.New("get memory failed when estimating load")
+	}
+	localDiskUsage, err := GetLocalUsedSize(paramtable.Get().LocalStorageCfg.Path.GetValue())
+	if err != nil {
+		return nil, errors.Wrap(err, "get local used size failed")
 	}
 
-	if predictDiskUsage > uint64(float64(paramtable.Get().QueryNodeCfg.DiskCapacityLimit.GetAsInt64())*paramtable.Get().QueryNodeCfg.MaxDiskUsagePercentage.GetAsFloat()) {
-		return 0, 0, fmt.Errorf("load segment failed, disk space is not enough, diskUsage = %v MB, predictDiskUsage = %v MB, totalDisk = %v MB, thresholdFactor = %f",
-			toMB(diskUsage),
-			toMB(predictDiskUsage),
-			toMB(uint64(paramtable.Get().QueryNodeCfg.DiskCapacityLimit.GetAsInt64())),
-			paramtable.Get().QueryNodeCfg.MaxDiskUsagePercentage.GetAsFloat())
+	neededMem, neededDisk, _, err := loader.estimateLoadResource(ctx, infos)
+	if err != nil {
+		return nil, err
 	}
 
-	return predictMemUsage - memUsage, predictDiskUsage - diskUsage, nil
+	feasibility := &LoadFeasibility{
+		Feasible:        true,
+		EstimatedMemory: neededMem,
+		EstimatedDisk:   neededDisk,
+		MemoryUsage:     usedMem + committed.MemorySize,
+		DiskUsage:       uint64(localDiskUsage) + committed.DiskSize,
+		MemoryBudget:    uint64(float64(totalMem) * paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.GetAsFloat()),
+		DiskBudget:      uint64(float64(paramtable.Get().QueryNodeCfg.DiskCapacityLimit.GetAsInt64()) * paramtable.Get().QueryNodeCfg.MaxDiskUsagePercentage.GetAsFloat()),
+	}
+	feasibility.ProjectedMemoryUsage = feasibility.MemoryUsage + neededMem
+	feasibility.ProjectedDiskUsage = feasibility.DiskUsage + neededDisk
+
+	// same as the check while loading, segments are loaded into disk if mmap enabled
+	mmapEnabled := len(infos) > 0 && len(mmapDirPathOf(loader.manager.Collection, infos[0].GetCollectionID())) > 0
+	memoryToCheck := lo.Ternary(mmapEnabled, feasibility.MemoryUsage, feasibility.ProjectedMemoryUsage)
+	switch {
+	case memoryToCheck > feasibility.MemoryBudget:
+		feasibility.Feasible = false
+		feasibility.Reason = fmt.Sprintf("memory not enough, projected usage %d exceeds budget %d", memoryToCheck, feasibility.MemoryBudget)
+	case feasibility.ProjectedDiskUsage > feasibility.DiskBudget:
+		feasibility.Feasible = false
+		feasibility.Reason = fmt.Sprintf("disk not enough, projected usage %d exceeds budget %d", feasibility.ProjectedDiskUsage, feasibility.DiskBudget)
+	}
+	return feasibility, nil
 }
 
-func (loader *segmentLoader) getFieldType(collectionID, fieldID int64) (schemapb.DataType, error) {
+func (loader *segmentLoader) getFieldSchema(collectionID, fieldID int64) (*schemapb.FieldSchema, error) {
 	collection := loader.manager.Collection.Get(collectionID)
 	if collection == nil {
-		return 0, merr.WrapErrCollectionNotFound(collectionID)
+		return nil, merr.WrapErrCollectionNotFound(collectionID)
 	}
 
 	for _, field := range collection.Schema().GetFields() {
 		if field.GetFieldID() == fieldID {
-			return field.GetDataType(), nil
+			return field, nil
 		}
 	}
-	return 0, merr.WrapErrFieldNotFound(fieldID)
+	return nil, merr.WrapErrFieldNotFound(fieldID)
 }
 
 func (loader *segmentLoader) LoadIndex(ctx context.Context, segment *LocalSegment, loadInfo *querypb.SegmentLoadInfo, version int64) error {
@@ -1070,36 +1326,90 @@ func (loader *segmentLoader) LoadIndex(ctx context.Context, segment *LocalSegmen
 	log.Info("segment loader start to load index", zap.Int("segmentNumAfterFilter", len(infos)))
 
 	for _, loadInfo := range infos {
-		fieldIDs := typeutil.NewSet(lo.Map(loadInfo.GetIndexInfos(), func(info *querypb.FieldIndexInfo, _ int) int64 { return info.GetFieldID() })...)
-		fieldInfos := lo.SliceToMap(lo.Filter(loadInfo.GetBinlogPaths(), func(info *datapb.FieldBinlog, _ int) bool { return fieldIDs.Contain(info.GetFieldID()) }),
-			func(info *datapb.FieldBinlog) (int64, *datapb.FieldBinlog) { return info.GetFieldID(), info })
-
-		for _, info := range loadInfo.GetIndexInfos() {
-			if len(info.GetIndexFilePaths()) == 0 {
-				log.Warn("failed to add index for segment, index file list is empty, the segment may be too small")
-				return merr.WrapErrIndexNotFound("index file list empty")
-			}
-
-			fieldInfo, ok := fieldInfos[info.GetFieldID()]
-			if !ok {
-				return merr.WrapErrParameterInvalid("index info with corresponding  field info", "missing field info", strconv.FormatInt(fieldInfo.GetFieldID(), 10))
-			}
-			err := loader.loadFieldIndex(ctx, segment, info)
-			if err != nil {
-				log.Warn("failed to load index for segment", zap.Error(err))
-				return err
-			}
-			segment.AddIndex(info.FieldID, &IndexedFieldInfo{
-				IndexInfo:   info,
-				FieldBinlog: fieldInfo,
-			})
+		start := time.Now()
+		loader.indexLoading.Insert(segment.ID(), start)
+		err := loader.loadSegmentIndexes(ctx, segment, loadInfo)
+		loader.indexLoading.Remove(segment.ID())
+		if err != nil {
+			log.Warn("failed to load index for segment", zap.Error(err))
+			return err
 		}
+		loader.observeIndexLoad(segment.InsertCount(), time.Since(start))
 		loader.notifyLoadFinish(loadInfo)
 	}
 
 	return loader.waitSegmentLoadDone(ctx, commonpb.SegmentState_SegmentStateNone, loadInfo.GetSegmentID())
 }
 
+func (loader *segmentLoader) loadSegmentIndexes(ctx context.Context, segment *LocalSegment, loadInfo *querypb.SegmentLoadInfo) error {
+	fieldIDs := typeutil.NewSet(lo.Map(loadInfo.GetIndexInfos(), func(info *querypb.FieldIndexInfo, _ int) int64 { return info.GetFieldID() })...)
+	fieldInfos := lo.SliceToMap(lo.Filter(loadInfo.GetBinlogPaths(), func(info *datapb.FieldBinlog, _ int) bool { return fieldIDs.Contain(info.GetFieldID()) }),
+		func(info *datapb.FieldBinlog) (int64, *datapb.FieldBinlog) { return info.GetFieldID(), info })
+
+	for _, info := range loadInfo.GetIndexInfos() {
+		if len(info.GetIndexFilePaths()) == 0 {
+			log.Ctx(ctx).Warn("failed to add index for segment, index file list is empty, the segment may be too small")
+			return merr.WrapErrIndexNotFound("index file list empty")
+		}
+
+		fieldInfo, ok := fieldInfos[info.GetFieldID()]
+		if !ok {
+			return merr.WrapErrParameterInvalid("index info with corresponding  field info", "missing field info", strconv.FormatInt(fieldInfo.GetFieldID(), 10))
+		}
+		err := loader.loadFieldIndex(ctx, segment, info)
+		if err != nil {
+			loader.loadErrors.Record(segment.ID(), LoadPhaseIndex, err)
+			return err
+		}
+		segment.AddIndex(info.FieldID, &IndexedFieldInfo{
+			IndexInfo:   info,
+			FieldBinlog: fieldInfo,
+		})
+	}
+	return nil
+}
+
+// observeIndexLoad updates the index loading rate with a finished index load.
+func (loader *segmentLoader) observeIndexLoad(rows int64, elapsed time.Duration) {
+	if rows <= 0 || elapsed <= 0 {
+		return
+	}
+	rate := float64(rows) / elapsed.Seconds()
+
+	loader.indexLoadRateMut.Lock()
+	defer loader.indexLoadRateMut.Unlock()
+	if loader.indexLoadRate == 0 {
+		loader.indexLoadRate = rate
+		return
+	}
+	// exponential moving average, recent loads weigh more
+	loader.indexLoadRate = 0.8*loader.indexLoadRate + 0.2*rate
+}
+
+func (loader *segmentLoader) GetIndexLoadProgress(segmentID int64) IndexLoadProgress {
+	start, ok := loader.indexLoading.Get(segmentID)
+	if !ok {
+		return IndexLoadProgress{}
+	}
+
+	progress := IndexLoadProgress{
+		Loading:   true,
+		StartTime: start,
+	}
+	loader.indexLoadRateMut.Lock()
+	rate := loader.indexLoadRate
+	loader.indexLoadRateMut.Unlock()
+	segment := loader.manager.Segment.GetSealed(segmentID)
+	if rate > 0 && segment != nil {
+		progress.EstimatedCompletion = start.Add(time.Duration(float64(segment.InsertCount()) / rate * float64(time.Second)))
+	}
+	return progress
+}
+
+func (loader *segmentLoader) GetLoadErrors() []SegmentLoadError {
+	return loader.loadErrors.List()
+}
+
 func getBinlogDataSize(fieldBinlog *datapb.FieldBinlog) int64 {
 	fieldSize := int64(0)
 	for _, binlog := range fieldBinlog.Binlogs {
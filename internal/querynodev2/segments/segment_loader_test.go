@@ -28,12 +28,14 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/proto/segcorepb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/initcore"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metric"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 type SegmentLoaderSuite struct {
@@ -343,7 +345,7 @@ func (suite *SegmentLoaderSuite) TestLoadDeltaLogs() {
 	segments, err := suite.loader.Load(ctx, suite.collectionID, SegmentTypeGrowing, 0, loadInfos...)
 	suite.NoError(err)
 
-	for _, segment := range segments {
+	for i, segment := range segments {
 		suite.Equal(int64(100-2), segment.RowNum())
 		for pk := 0; pk < 100; pk++ {
 			if pk == 1 || pk == 2 {
@@ -352,6 +354,90 @@ func (suite *SegmentLoaderSuite) TestLoadDeltaLogs() {
 			exist := segment.MayPkExist(storage.NewInt64PrimaryKey(int64(pk)))
 			suite.Require().True(exist)
 		}
+
+		local := segment.(*LocalSegment)
+		deltaLogPath := loadInfos[i].GetDeltalogs()[0].GetBinlogs()[0].GetLogPath()
+		suite.Equal([]string{deltaLogPath}, local.AppliedDeltaLogs())
+
+		// the log ending before the latest delete is skipped, unless reloaded
+		local.appliedDeltaLogs = typeutil.NewConcurrentSet[string]()
+		loadInfos[i].Deltalogs[0].Binlogs[0].TimestampTo = local.LastDeltaTimestamp() - 1
+		suite.NoError(suite.loader.LoadDeltaLogs(ctx, local, loadInfos[i].GetDeltalogs()))
+		suite.Empty(local.AppliedDeltaLogs())
+		suite.NoError(suite.loader.ReloadDeltaLogs(ctx, local, loadInfos[i].GetDeltalogs()))
+		suite.Equal([]string{deltaLogPath}, local.AppliedDeltaLogs())
+	}
+}
+
+func (suite *SegmentLoaderSuite) TestLoadDeltaLogsLazy() {
+	ctx := context.Background()
+	collection := suite.manager.Collection.Get(suite.collectionID)
+	defer collection.SetDeleteApplyMode("")
+
+	msgLength := 100
+	results := make(map[string][]*segcorepb.RetrieveResults)
+	for _, mode := range []string{DeleteApplyModeEager, DeleteApplyModeLazy} {
+		collection.SetDeleteApplyMode(mode)
+
+		loadInfos := make([]*querypb.SegmentLoadInfo, 0, suite.segmentNum)
+		for i := 0; i < suite.segmentNum; i++ {
+			segmentID := suite.segmentID + int64(i)
+			binlogs, statsLogs, err := SaveBinLog(ctx,
+				suite.collectionID,
+				suite.partitionID,
+				segmentID,
+				msgLength,
+				suite.schema,
+				suite.chunkManager,
+			)
+			suite.NoError(err)
+
+			// Delete PKs 1, 2
+			deltaLogs, err := SaveDeltaLog(suite.collectionID,
+				suite.partitionID,
+				segmentID,
+				suite.chunkManager,
+			)
+			suite.NoError(err)
+
+			loadInfos = append(loadInfos, &querypb.SegmentLoadInfo{
+				SegmentID:    segmentID,
+				PartitionID:  suite.partitionID,
+				CollectionID: suite.collectionID,
+				BinlogPaths:  binlogs,
+				Statslogs:    statsLogs,
+				Deltalogs:    deltaLogs,
+				NumOfRows:    int64(msgLength),
+			})
+		}
+
+		segments, err := suite.loader.Load(ctx, suite.collectionID, SegmentTypeSealed, 0, loadInfos...)
+		suite.NoError(err)
+
+		plan, err := genSimpleRetrievePlan(collection)
+		suite.NoError(err)
+		for _, segment := range segments {
+			segment := segment.(*LocalSegment)
+			suite.Equal(mode == DeleteApplyModeLazy, segment.HasPendingDeltaData())
+			suite.EqualValues(200, segment.LastDeltaTimestamp())
+
+			result, err := segment.Retrieve(ctx, plan)
+			suite.NoError(err)
+			suite.False(segment.HasPendingDeltaData())
+			results[mode] = append(results[mode], result)
+		}
+		plan.Delete()
+
+		for i := 0; i < suite.segmentNum; i++ {
+			suite.manager.Segment.Remove(suite.segmentID+int64(i), querypb.DataScope_All)
+		}
+	}
+
+	// deleted pks 1, 2 are filtered in both modes
+	suite.Len(results[DeleteApplyModeLazy], suite.segmentNum)
+	for i, result := range results[DeleteApplyModeLazy] {
+		suite.Equal(results[DeleteApplyModeEager][i].GetIds(), result.GetIds())
+		suite.Equal([]int64{3}, result.GetIds().GetIntId().GetData())
 	}
 }
 
@@ -433,6 +519,36 @@ func (suite *SegmentLoaderSuite) TestLoadIndex() {
 	suite.ErrorIs(err, merr.ErrIndexNotFound)
 }
 
+func (suite *SegmentLoaderSuite) TestIndexLoadProgress() {
+	segmentManager := NewMockSegmentManager(suite.T())
+	loader := NewLoader(&Manager{
+		Collection: NewCollectionManager(),
+		Segment:    segmentManager,
+	}, suite.chunkManager)
+
+	progress := loader.GetIndexLoadProgress(100)
+	suite.False(progress.Loading)
+
+	// loading, rate unknown
+	start := time.Now()
+	loader.indexLoading.Insert(100, start)
+	progress = loader.GetIndexLoadProgress(100)
+	suite.True(progress.Loading)
+	suite.Equal(start, progress.StartTime)
+	suite.True(progress.EstimatedCompletion.IsZero())
+
+	// estimate with observed rate
+	loader.observeIndexLoad(1000, time.Second)
+	segment := NewMockSegment(suite.T())
+	segment.EXPECT().InsertCount().Return(2000)
+	segmentManager.EXPECT().GetSealed(int64(100)).Return(segment)
+	progress = loader.GetIndexLoadProgress(100)
+	suite.Equal(start.Add(2*time.Second), progress.EstimatedCompletion)
+
+	loader.indexLoading.Remove(100)
+	suite.False(loader.GetIndexLoadProgress(100).Loading)
+}
+
 func (suite *SegmentLoaderSuite) TestLoadWithMmap() {
 	key := paramtable.Get().QueryNodeCfg.MmapDirPath.Key
 	paramtable.Get().Save(key, "/tmp/mmap-test")
@@ -462,6 +578,72 @@ func (suite *SegmentLoaderSuite) TestLoadWithMmap() {
 	suite.NoError(err)
 }
 
+func (suite *SegmentLoaderSuite) TestReload() {
+	key := paramtable.Get().QueryNodeCfg.MmapDirPath.Key
+	paramtable.Get().Save(key, suite.T().TempDir())
+	defer paramtable.Get().Reset(key)
+	ctx := context.Background()
+
+	msgLength := 100
+	binlogs, statsLogs, err := SaveBinLog(ctx,
+		suite.collectionID,
+		suite.partitionID,
+		suite.segmentID,
+		msgLength,
+		suite.schema,
+		suite.chunkManager,
+	)
+	suite.NoError(err)
+	// Delete PKs 1, 2
+	deltaLogs, err := SaveDeltaLog(suite.collectionID,
+		suite.partitionID,
+		suite.segmentID,
+		suite.chunkManager,
+	)
+	suite.NoError(err)
+
+	loaded, err := suite.loader.Load(ctx, suite.collectionID, SegmentTypeSealed, 0, &querypb.SegmentLoadInfo{
+		SegmentID:    suite.segmentID,
+		PartitionID:  suite.partitionID,
+		CollectionID: suite.collectionID,
+		BinlogPaths:  binlogs,
+		Statslogs:    statsLogs,
+		Deltalogs:    deltaLogs,
+		NumOfRows:    int64(msgLength),
+	})
+	suite.NoError(err)
+	suite.Require().Len(loaded, 1)
+	segment := loaded[0].(*LocalSegment)
+	suite.True(segment.Mmapped())
+	// streamed delete of PK 3
+	suite.NoError(segment.Delete([]storage.PrimaryKey{storage.NewInt64PrimaryKey(3)}, []typeutil.Timestamp{1000}))
+
+	collection := suite.manager.Collection.Get(suite.collectionID)
+	suite.Require().NoError(collection.SetMmapPolicy(MmapPolicyDisabled))
+	defer collection.SetMmapPolicy(MmapPolicyDefault)
+
+	reloaded, err := suite.loader.Reload(ctx, segment)
+	suite.NoError(err)
+	suite.False(reloaded.Mmapped())
+	suite.Equal(segment.Version()+1, reloaded.Version())
+	suite.Equal(reloaded, suite.manager.Segment.GetSealed(suite.segmentID))
+	// the deletes are kept
+	suite.Equal(int64(msgLength-3), reloaded.RowNum())
+	suite.EqualValues(1000, reloaded.LastDeltaTimestamp())
+
+	// the replaced one refuses the deletes
+	suite.ErrorIs(segment.Delete([]storage.PrimaryKey{storage.NewInt64PrimaryKey(4)}, []typeutil.Timestamp{1001}), merr.ErrSegmentNotLoaded)
+	suite.NoError(reloaded.Delete([]storage.PrimaryKey{storage.NewInt64PrimaryKey(4)}, []typeutil.Timestamp{1001}))
+	suite.Equal(int64(msgLength-4), reloaded.RowNum())
+
+	// growing segment could not be reloaded
+	growing, err := NewSegment(collection, suite.segmentID+1, suite.partitionID, suite.collectionID, "", SegmentTypeGrowing, 0, nil, nil)
+	suite.Require().NoError(err)
+	defer growing.Release()
+	_, err = suite.loader.Reload(ctx, growing)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+}
+
 func (suite *SegmentLoaderSuite) TestPatchEntryNum() {
 	ctx := context.Background()
 
@@ -724,6 +906,70 @@ func (suite *SegmentLoaderDetailSuite) TestWaitSegmentLoadDone() {
 	})
 }
 
+func (suite *SegmentLoaderSuite) TestCheckIndexMetricType() {
+	field := &schemapb.FieldSchema{
+		FieldID:    101,
+		Name:       "vec",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: metricTypeKey, Value: metric.L2}},
+	}
+	indexInfo := &querypb.FieldIndexInfo{
+		FieldID:     101,
+		IndexName:   "vec_index",
+		IndexParams: []*commonpb.KeyValuePair{{Key: metricTypeKey, Value: "l2"}},
+	}
+	suite.NoError(checkIndexMetricType(field, indexInfo))
+
+	indexInfo.IndexParams = []*commonpb.KeyValuePair{{Key: metricTypeKey, Value: metric.IP}}
+	suite.ErrorIs(checkIndexMetricType(field, indexInfo), merr.ErrParameterInvalid)
+
+	// metric not declared by schema
+	field.TypeParams = nil
+	suite.NoError(checkIndexMetricType(field, indexInfo))
+}
+
+func (suite *SegmentLoaderSuite) TestEstimateLoad() {
+	ctx := context.Background()
+
+	msgLength := 100
+	binlogs, statsLogs, err := SaveBinLog(ctx,
+		suite.collectionID,
+		suite.partitionID,
+		suite.segmentID,
+		msgLength,
+		suite.schema,
+		suite.chunkManager,
+	)
+	suite.Require().NoError(err)
+	loadInfo := &querypb.SegmentLoadInfo{
+		SegmentID:    suite.segmentID,
+		PartitionID:  suite.partitionID,
+		CollectionID: suite.collectionID,
+		BinlogPaths:  binlogs,
+		Statslogs:    statsLogs,
+		NumOfRows:    int64(msgLength),
+	}
+
+	feasibility, err := suite.loader.EstimateLoad(ctx, loadInfo)
+	suite.Require().NoError(err)
+	suite.True(feasibility.Feasible)
+	suite.Empty(feasibility.Reason)
+	suite.Greater(feasibility.EstimatedMemory, uint64(0))
+	suite.Equal(feasibility.MemoryUsage+feasibility.EstimatedMemory, feasibility.ProjectedMemoryUsage)
+	suite.Equal(feasibility.DiskUsage+feasibility.EstimatedDisk, feasibility.ProjectedDiskUsage)
+
+	// nothing committed by estimation
+	suite.Zero(suite.loader.committedResource.MemorySize)
+
+	key := paramtable.Get().QueryNodeCfg.OverloadedMemoryThresholdPercentage.Key
+	paramtable.Get().Save(key, "0")
+	defer paramtable.Get().Reset(key)
+	feasibility, err = suite.loader.EstimateLoad(ctx, loadInfo)
+	suite.Require().NoError(err)
+	suite.False(feasibility.Feasible)
+	suite.NotEmpty(feasibility.Reason)
+}
+
 func TestSegmentLoader(t *testing.T) {
 	suite.Run(t, &SegmentLoaderSuite{})
 	suite.Run(t, &SegmentLoaderDetailSuite{})
@@ -23,8 +23,9 @@ import (
 
 // SegmentStats struct for segment statistics.
 type SegmentStats struct {
-	SegmentID int64
-	RowCount  int64
+	SegmentID   int64
+	PartitionID int64
+	RowCount    int64
 }
 
 // statisticOnSegments performs statistic on listed segments
@@ -41,8 +42,9 @@ func statisticOnSegments(ctx context.Context, segments []Segment, segType Segmen
 		go func(segment Segment, i int) {
 			defer wg.Done()
 			resultCh <- SegmentStats{
-				SegmentID: segment.ID(),
-				RowCount:  segment.RowNum(),
+				SegmentID:   segment.ID(),
+				PartitionID: segment.Partition(),
+				RowCount:    segment.RowNum(),
 			}
 		}(segment, i)
 	}
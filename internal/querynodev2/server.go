@@ -43,6 +43,7 @@ import (
 
 	"github.com/samber/lo"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -129,6 +130,49 @@ type QueryNode struct {
 
 	// parameter turning hook
 	queryHook optimizers.QueryHook
+
+	// post-reduce reranker, nil if not configured
+	reranker optimizers.Reranker
+
+	// adaptive topK controller
+	adaptiveTopK *optimizers.AdaptiveTopK
+
+	// search latency distributions of collections over sliding window
+	latencyHistograms *optimizers.LatencyHistograms
+
+	// in-flight load operations
+	loads *loadRegistry
+
+	// default search params of collections set by admin
+	searchParamDefaults *searchParamDefaultsRegistry
+
+	// latest search params applied by query hook of collections
+	searchParamLog *searchParamLog
+
+	// number of search and query requests being served as shard leader
+	inflightSearches *atomic.Int64
+	inflightQueries  *atomic.Int64
+
+	// number of exact searches being served, bounded by queryNode.exactSearch.maxConcurrency
+	exactSearches *atomic.Int64
+
+	// new searches and queries of channels are rejected while draining, the in-flight ones are still served
+	draining *atomic.Bool
+
+	// node-local success and failure counts of requests served as shard leader
+	requestCounters *requestCounterRegistry
+
+	// fields of collections never returned by retrieve and search to the callers not allowed
+	fieldDenylists *fieldDenylistRegistry
+
+	// max timeout of search and query of collections set by admin
+	queryTimeoutOverrides *queryTimeoutOverrides
+
+	// server side cursors of resumable query streams
+	streamCursors *streamCursorRegistry
+
+	// cached exact ground truth of named query sets
+	groundTruths *groundTruthCache
 }
 
 // NewQueryNode will return a QueryNode with abnormal state.
@@ -139,6 +183,21 @@ func NewQueryNode(ctx context.Context, factory dependency.Factory) *QueryNode {
 		cancel:   cancel,
 		factory:  factory,
 		lifetime: lifetime.NewLifetime(commonpb.StateCode_Abnormal),
+
+		adaptiveTopK:          optimizers.NewAdaptiveTopK(),
+		latencyHistograms:     optimizers.NewLatencyHistograms(),
+		loads:                 newLoadRegistry(),
+		searchParamDefaults:   newSearchParamDefaultsRegistry(),
+		searchParamLog:        newSearchParamLog(),
+		inflightSearches:      atomic.NewInt64(0),
+		inflightQueries:       atomic.NewInt64(0),
+		exactSearches:         atomic.NewInt64(0),
+		draining:              atomic.NewBool(false),
+		requestCounters:       newRequestCounterRegistry(),
+		fieldDenylists:        newFieldDenylistRegistry(),
+		queryTimeoutOverrides: newQueryTimeoutOverrides(),
+		streamCursors:         newStreamCursorRegistry(),
+		groundTruths:          newGroundTruthCache(),
 	}
 
 	node.tSafeManager = tsafe.NewTSafeReplica()
@@ -282,6 +341,12 @@ func (node *QueryNode) Init() error {
 			}
 		}
 
+		err = node.initReranker()
+		if err != nil {
+			// search works without reranker
+			log.Warn("QueryNode init reranker failed", zap.Error(err))
+		}
+
 		node.factory.Init(paramtable.Get())
 
 		localRootPath := paramtable.Get().LocalStorageCfg.Path.GetValue()
@@ -453,6 +518,7 @@ func (node *QueryNode) Stop() error {
 
 		node.UpdateStateCode(commonpb.StateCode_Abnormal)
 		node.lifetime.Wait()
+		node.saveQueryHookCache()
 		node.cancel()
 		if node.scheduler != nil {
 			node.scheduler.Stop()
@@ -524,11 +590,42 @@ func (node *QueryNode) initHook() error {
 	}
 
 	node.queryHook = hoo
+	node.loadQueryHookCache()
 	node.handleQueryHookEvent()
 
 	return nil
 }
 
+// initReranker loads the post-reduce reranker plugin if configured.
+func (node *QueryNode) initReranker() error {
+	path := paramtable.Get().QueryNodeCfg.RerankerSoPath.GetValue()
+	if path == "" {
+		return nil
+	}
+	log.Info("start to load reranker plugin", zap.String("path", path))
+
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("fail to open the reranker plugin, error: %s", err.Error())
+	}
+
+	r, err := p.Lookup("QueryNodeReranker")
+	if err != nil {
+		return fmt.Errorf("fail to find the 'QueryNodeReranker' object in the plugin, error: %s", err.Error())
+	}
+
+	reranker, ok := r.(optimizers.Reranker)
+	if !ok {
+		return fmt.Errorf("fail to convert the `Reranker` interface")
+	}
+	if err = reranker.Init(paramtable.Get().QueryNodeCfg.RerankerConfig.GetValue()); err != nil {
+		return fmt.Errorf("fail to init the reranker, error: %s", err.Error())
+	}
+
+	node.reranker = reranker
+	return nil
+}
+
 func (node *QueryNode) handleQueryHookEvent() {
 	onEvent := func(event *config.Event) {
 		if node.queryHook != nil {
@@ -22,6 +22,7 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/trace"
@@ -43,6 +44,7 @@ import (
 	"github.com/milvus-io/milvus/internal/querynodev2/tasks"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
@@ -303,7 +305,12 @@ func (node *QueryNode) WatchDmChannels(ctx context.Context, req *querypb.WatchDm
 		pipeline.ExcludedSegments(droppedInfos...)
 	}
 
-	err = loadGrowingSegments(ctx, delegator, req)
+	growingIDs := lo.FlatMap(req.GetInfos(), func(info *datapb.VchannelInfo, _ int) []int64 {
+		return info.GetUnflushedSegmentIds()
+	})
+	loadCtx, load := node.loads.register(ctx, req.GetCollectionID(), LoadKindGrowing, growingIDs)
+	loadedGrowing, err := loadGrowingSegments(loadCtx, delegator, req)
+	node.loads.finish(load, node.rollbackCanceledLoad(load, segments.SegmentTypeGrowing, loadedGrowing, err))
 	if err != nil {
 		msg := "failed to load growing segments"
 		log.Warn(msg, zap.Error(err))
@@ -362,6 +369,7 @@ func (node *QueryNode) UnsubDmChannel(ctx context.Context, req *querypb.UnsubDmC
 		node.pipelineManager.Remove(req.GetChannelName())
 		node.manager.Segment.RemoveBy(segments.WithChannel(req.GetChannelName()), segments.WithType(segments.SegmentTypeGrowing))
 		node.tSafeManager.Remove(req.GetChannelName())
+		node.groundTruths.invalidate(req.GetChannelName())
 
 		node.manager.Collection.Unref(req.GetCollectionID(), 1)
 	}
@@ -439,11 +447,18 @@ func (node *QueryNode) LoadSegments(ctx context.Context, req *querypb.LoadSegmen
 		return merr.Success(), nil
 	}
 
+	segmentIDs := lo.Map(req.GetInfos(), func(info *querypb.SegmentLoadInfo, _ int) int64 { return info.GetSegmentID() })
 	if req.GetLoadScope() == querypb.LoadScope_Delta {
-		return node.loadDeltaLogs(ctx, req), nil
+		// delta logs are applied to loaded segments, nothing to roll back
+		loadCtx, load := node.loads.register(ctx, req.GetCollectionID(), LoadKindDelta, segmentIDs)
+		defer node.loads.finish(load, nil)
+		return node.loadDeltaLogs(loadCtx, req), nil
 	}
 	if req.GetLoadScope() == querypb.LoadScope_Index {
-		return node.loadIndex(ctx, req), nil
+		// segments stay searchable with partially loaded indexes, nothing to roll back
+		loadCtx, load := node.loads.register(ctx, req.GetCollectionID(), LoadKindIndex, segmentIDs)
+		defer node.loads.finish(load, nil)
+		return node.loadIndex(loadCtx, req), nil
 	}
 
 	node.manager.Collection.PutOrRef(req.GetCollectionID(), req.GetSchema(),
@@ -452,12 +467,15 @@ func (node *QueryNode) LoadSegments(ctx context.Context, req *querypb.LoadSegmen
 
 	// Actual load segment
 	log.Info("start to load segments...")
-	loaded, err := node.loader.Load(ctx,
+	loadCtx, load := node.loads.register(ctx, req.GetCollectionID(), LoadKindSealed, segmentIDs)
+	loaded, err := node.loader.Load(loadCtx,
 		req.GetCollectionID(),
 		segments.SegmentTypeSealed,
 		req.GetVersion(),
 		req.GetInfos()...,
 	)
+	node.loads.finish(load, node.rollbackCanceledLoad(load, segments.SegmentTypeSealed,
+		lo.Map(loaded, func(s segments.Segment, _ int) int64 { return s.ID() }), err))
 	if err != nil {
 		return merr.Status(err), nil
 	}
@@ -730,6 +748,8 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 		}, nil
 	}
 	defer node.lifetime.Done()
+	node.inflightSearches.Inc()
+	defer node.inflightSearches.Dec()
 
 	err := merr.CheckTargetID(req.GetReq().GetBase())
 	if err != nil {
@@ -762,6 +782,17 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 		req.Req.MetricType = collection.GetMetricType()
 	}
 
+	// the consistency level is enforced at node rather than the guarantee timestamp computed by proxy
+	guaranteeTs, enforced, err := node.enforcedGuaranteeTs(ctx, req.GetReq(), req.GetDmlChannels())
+	if err != nil {
+		failRet.Status = merr.Status(err)
+		return failRet, nil
+	}
+	if enforced {
+		req = typeutil.Clone(req)
+		req.Req.GuaranteeTimestamp = guaranteeTs
+	}
+
 	var toReduceResults []*internalpb.SearchResults
 	var mu sync.Mutex
 	runningGp, runningCtx := errgroup.WithContext(ctx)
@@ -796,12 +827,92 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 	}
 
 	tr.RecordSpan()
-	result, err := segments.ReduceSearchResults(ctx, toReduceResults, req.Req.GetNq(), req.Req.GetTopk(), req.Req.GetMetricType())
+	scanDecisions := lo.FlatMap(toReduceResults, func(result *internalpb.SearchResults, _ int) []*internalpb.SegmentScanDecision {
+		return result.GetScanDecisions()
+	})
+	reduceCtx := segments.WithReduceAlgorithm(ctx, req.GetReq().GetReduceAlgorithm())
+	reduceCtx = segments.WithInsertionOrderTiebreak(reduceCtx, req.GetReq().GetInsertionOrderTiebreak())
+	var result *internalpb.SearchResults
+	if req.GetReq().GetPerPartitionTopk() {
+		result, err = reducePerPartitionSearchResults(reduceCtx, toReduceResults, req)
+	} else {
+		// over-fetched candidates are kept after the topK hits
+		result, err = segments.ReduceSearchResults(reduceCtx, toReduceResults, req.Req.GetNq(), req.Req.GetTopk()+req.Req.GetOverfetch(), req.Req.GetMetricType())
+	}
 	if err != nil {
 		log.Warn("failed to reduce search results", zap.Error(err))
 		failRet.Status = merr.Status(err)
 		return failRet, nil
 	}
+	result.ScanDecisions = scanDecisions
+	result.FilterStrategyDecisions = lo.FlatMap(toReduceResults, func(result *internalpb.SearchResults, _ int) []*internalpb.FilterStrategyDecision {
+		return result.GetFilterStrategyDecisions()
+	})
+	result.ScanEstimates = lo.FlatMap(toReduceResults, func(result *internalpb.SearchResults, _ int) []*internalpb.SearchScanEstimate {
+		return result.GetScanEstimates()
+	})
+	if req.GetReq().GetReturnFingerprint() {
+		result.QueryFingerprint = combineQueryFingerprints(toReduceResults)
+	}
+	if req.GetReq().GetFacet() != nil {
+		result.FacetBuckets = mergeFacetBuckets(toReduceResults, req.GetReq().GetFacet().GetTopHits())
+	}
+	// hits of channels are merged, rank again
+	if req.GetReq().GetReturnRankScore() {
+		if err := segments.FillRankScores(result); err != nil {
+			log.Warn("failed to fill rank scores of search results", zap.Error(err))
+			failRet.Status = merr.Status(err)
+			return failRet, nil
+		}
+	}
+	if req.GetReq().GetIsIterator() {
+		// resume after the last hit among all channels
+		iterToken, err := typeutil2.ParseIteratorToken(req.GetReq().GetIteratorToken())
+		if err == nil {
+			err = fillNextIteratorToken(result, iterToken, req.GetReq().GetMetricType())
+		}
+		if err != nil {
+			log.Warn("failed to fill next search iterator token", zap.Error(err))
+			failRet.Status = merr.Status(err)
+			return failRet, nil
+		}
+	}
+	if req.GetReq().GetOverfetch() > 0 {
+		if err := segments.FillPrimaryTopks(result, req.GetReq().GetTopk()); err != nil {
+			log.Warn("failed to fill primary topks of search results", zap.Error(err))
+			failRet.Status = merr.Status(err)
+			return failRet, nil
+		}
+	}
+	// hits of channels are merged, rank again
+	if req.GetReq().GetExplainPk() != nil {
+		result.HitExplanations = mergeHitExplanations(toReduceResults, req.GetReq().GetNq())
+		if err := fillHitExplanationRanks(result, req.GetReq().GetExplainPk(), result.HitExplanations); err != nil {
+			log.Warn("failed to fill hit explanation ranks of search results", zap.Error(err))
+			failRet.Status = merr.Status(err)
+			return failRet, nil
+		}
+	}
+	if req.GetReq().GetScoreStatsOnly() {
+		if err := segments.FillScoreStats(result); err != nil {
+			log.Warn("failed to fill score statistics of search results", zap.Error(err))
+			failRet.Status = merr.Status(err)
+			return failRet, nil
+		}
+		result.SlicedBlob = nil
+	}
+	// enforced at node, so that a proxy can't bypass it
+	if denied := node.fieldDenylists.deniedFields(req.GetReq().GetCollectionID(), callerOfRequest(req.GetReq().GetUsername())); len(denied) > 0 {
+		omitted, err := omitDeniedSearchFields(result, denied)
+		if err != nil {
+			log.Warn("failed to omit denied output fields of search results", zap.Error(err))
+			failRet.Status = merr.Status(err)
+			return failRet, nil
+		}
+		if len(omitted) > 0 {
+			log.Info("output fields denied to the caller, omitted", zap.Int64s("fieldIDs", omitted))
+		}
+	}
 	reduceLatency := tr.RecordSpan()
 	metrics.QueryNodeReduceLatency.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.SearchLabel, metrics.ReduceShards).
 		Observe(float64(reduceLatency.Milliseconds()))
@@ -918,6 +1029,8 @@ func (node *QueryNode) Query(ctx context.Context, req *querypb.QueryRequest) (*i
 		}, nil
 	}
 	defer node.lifetime.Done()
+	node.inflightQueries.Inc()
+	defer node.inflightQueries.Dec()
 
 	err := merr.CheckTargetID(req.GetReq().GetBase())
 	if err != nil {
@@ -927,6 +1040,54 @@ func (node *QueryNode) Query(ctx context.Context, req *querypb.QueryRequest) (*i
 		}, nil
 	}
 
+	// the consistency level is enforced at node rather than the guarantee timestamp computed by proxy
+	guaranteeTs, enforced, err := node.enforcedGuaranteeTs(ctx, req.GetReq(), req.GetDmlChannels())
+	if err != nil {
+		return &internalpb.RetrieveResults{
+			Status: merr.Status(err),
+		}, nil
+	}
+	if enforced {
+		req = typeutil.Clone(req)
+		req.Req.GuaranteeTimestamp = guaranteeTs
+	}
+
+	// drop the invalid output fields once for all channels
+	var droppedOutputFields []int64
+	if req.GetReq().GetLenientOutputFields() {
+		collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+		if collection == nil {
+			err := merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+			return &internalpb.RetrieveResults{
+				Status: merr.Status(err),
+			}, nil
+		}
+		req, droppedOutputFields, err = applyLenientOutputFields(req, collection.Schema())
+		if err != nil {
+			log.Warn("invalid retrieve plan", zap.Error(err))
+			return &internalpb.RetrieveResults{
+				Status: merr.Status(err),
+			}, nil
+		}
+	}
+
+	if len(req.GetReq().GetSortKeys()) > 0 {
+		collection := node.manager.Collection.Get(req.GetReq().GetCollectionID())
+		if collection == nil {
+			err := merr.WrapErrCollectionNotLoaded(req.GetReq().GetCollectionID())
+			return &internalpb.RetrieveResults{
+				Status: merr.Status(err),
+			}, nil
+		}
+		err := typeutil2.CheckSortKeys(collection.Schema(), req.GetReq().GetOutputFieldsId(), req.GetReq().GetSortKeys())
+		if err != nil {
+			log.Warn("invalid sort keys", zap.Error(err))
+			return &internalpb.RetrieveResults{
+				Status: merr.Status(err),
+			}, nil
+		}
+	}
+
 	toMergeResults := make([]*internalpb.RetrieveResults, len(req.GetDmlChannels()))
 	runningGp, runningCtx := errgroup.WithContext(ctx)
 
@@ -960,13 +1121,29 @@ func (node *QueryNode) Query(ctx context.Context, req *querypb.QueryRequest) (*i
 	}
 
 	tr.RecordSpan()
+	scanDecisions := lo.FlatMap(toMergeResults, func(result *internalpb.RetrieveResults, _ int) []*internalpb.SegmentScanDecision {
+		return result.GetScanDecisions()
+	})
 	reducer := segments.CreateInternalReducer(req, node.manager.Collection.Get(req.GetReq().GetCollectionID()).Schema())
 	ret, err := reducer.Reduce(ctx, toMergeResults)
+	if err == nil {
+		// merging shards breaks the order of each shard, sort again
+		err = typeutil2.SortRetrieveResults(ret, req.GetReq().GetSortKeys())
+	}
 	if err != nil {
 		return &internalpb.RetrieveResults{
 			Status: merr.Status(err),
 		}, nil
 	}
+	ret.ScanDecisions = scanDecisions
+	fillDroppedOutputFields(ret, droppedOutputFields)
+	// enforced at node, so that a proxy can't bypass it
+	omitDeniedFields(ret, node.fieldDenylists.deniedFields(req.GetReq().GetCollectionID(), callerOfRequest(req.GetReq().GetUsername())))
+	if req.GetReq().GetReturnFieldFormats() {
+		if collection := node.manager.Collection.Get(req.GetReq().GetCollectionID()); collection != nil {
+			fillFieldFormats(ret, collection.Schema())
+		}
+	}
 	reduceLatency := tr.RecordSpan()
 	metrics.QueryNodeReduceLatency.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), metrics.QueryLabel, metrics.ReduceShards).
 		Observe(float64(reduceLatency.Milliseconds()))
@@ -988,7 +1165,30 @@ func (node *QueryNode) QueryStream(req *querypb.QueryRequest, srv querypb.QueryN
 		zap.Int64("collectionID", req.GetReq().GetCollectionID()),
 		zap.Strings("shards", req.GetDmlChannels()),
 	)
-	concurrentSrv := streamrpc.NewConcurrentQueryStreamServer(srv)
+	var sender streamrpc.QueryStreamServer = srv
+	if req.GetReq().GetCompressStream() {
+		compressedSrv, err := newCompressedQueryStreamServer(srv)
+		if err != nil {
+			log.Warn("failed to create stream compressor, send results uncompressed", zap.Error(err))
+		} else {
+			defer compressedSrv.Close()
+			sender = compressedSrv
+		}
+	}
+	// the bound applies to the uncompressed payload which clients accumulate
+	if maxBytes := maxStreamBytes(req.GetReq().GetMaxStreamBytes()); maxBytes > 0 {
+		sender = newSizeLimitedQueryStreamServer(sender, maxBytes)
+	}
+	// formats of the fields not denied, attached before compressed
+	if req.GetReq().GetReturnFieldFormats() {
+		if collection := node.manager.Collection.Get(req.GetReq().GetCollectionID()); collection != nil {
+			sender = newFieldFormatsQueryStreamServer(sender, collection.Schema())
+		}
+	}
+	if denied := node.fieldDenylists.deniedFields(req.GetReq().GetCollectionID(), callerOfRequest(req.GetReq().GetUsername())); len(denied) > 0 {
+		sender = newDeniedFieldsQueryStreamServer(sender, denied)
+	}
+	concurrentSrv := streamrpc.NewConcurrentQueryStreamServer(sender)
 
 	log.Debug("received query stream request",
 		zap.Int64s("outputFields", req.GetReq().GetOutputFieldsId()),
@@ -1003,6 +1203,8 @@ func (node *QueryNode) QueryStream(req *querypb.QueryRequest, srv querypb.QueryN
 		return nil
 	}
 	defer node.lifetime.Done()
+	node.inflightQueries.Inc()
+	defer node.inflightQueries.Dec()
 
 	err := merr.CheckTargetID(req.GetReq().GetBase())
 	if err != nil {
@@ -1010,28 +1212,49 @@ func (node *QueryNode) QueryStream(req *querypb.QueryRequest, srv querypb.QueryN
 		return err
 	}
 
-	runningGp, runningCtx := errgroup.WithContext(ctx)
-
-	for _, ch := range req.GetDmlChannels() {
-		ch := ch
-		req := &querypb.QueryRequest{
-			Req:             req.Req,
-			DmlChannels:     []string{ch},
-			SegmentIDs:      req.SegmentIDs,
-			FromShardLeader: req.FromShardLeader,
-			Scope:           req.Scope,
-		}
+	// the consistency level is enforced at node rather than the guarantee timestamp computed by proxy
+	guaranteeTs, enforced, err := node.enforcedGuaranteeTs(ctx, req.GetReq(), req.GetDmlChannels())
+	if err != nil {
+		concurrentSrv.Send(&internalpb.RetrieveResults{Status: merr.Status(err)})
+		return nil
+	}
+	if enforced {
+		req = typeutil.Clone(req)
+		req.Req.GuaranteeTimestamp = guaranteeTs
+	}
 
-		runningGp.Go(func() error {
-			err := node.queryChannelStream(runningCtx, req, ch, concurrentSrv)
-			if err != nil {
-				return err
+	if req.GetReq().GetResumableStream() || req.GetReq().GetStreamCursor() != "" {
+		err = node.queryStreamResumable(ctx, req, concurrentSrv)
+	} else {
+		runningGp, runningCtx := errgroup.WithContext(ctx)
+
+		for _, ch := range req.GetDmlChannels() {
+			ch := ch
+			req := &querypb.QueryRequest{
+				Req:             req.Req,
+				DmlChannels:     []string{ch},
+				SegmentIDs:      req.SegmentIDs,
+				FromShardLeader: req.FromShardLeader,
+				Scope:           req.Scope,
 			}
-			return nil
-		})
+
+			runningGp.Go(func() error {
+				err := node.queryChannelStream(runningCtx, req, ch, concurrentSrv)
+				if err != nil {
+					return err
+				}
+				return nil
+			})
+		}
+		err = runningGp.Wait()
 	}
 
-	if err := runningGp.Wait(); err != nil {
+	if err != nil {
+		if errors.Is(err, errStreamTruncated) {
+			log.Info("query stream truncated, streamed results exceeded max stream bytes",
+				zap.Int64("maxStreamBytes", req.GetReq().GetMaxStreamBytes()))
+			return nil
+		}
 		concurrentSrv.Send(&internalpb.RetrieveResults{
 			Status: merr.Status(err),
 		})
@@ -1374,6 +1597,10 @@ func (node *QueryNode) SyncDistribution(ctx context.Context, req *querypb.SyncDi
 			CollectionID: req.GetCollectionID(),
 		}, true)
 	}
+	// the cached ground truth is computed on the previous distribution
+	if len(req.GetActions()) > 0 {
+		node.groundTruths.invalidate(req.GetChannel())
+	}
 
 	return merr.Success(), nil
 }
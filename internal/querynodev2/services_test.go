@@ -541,6 +541,37 @@ func (suite *ServiceSuite) TestLoadSegments_Int64() {
 	}
 }
 
+func (suite *ServiceSuite) TestLoadSingleSegment() {
+	ctx := context.Background()
+	suite.TestWatchDmChannelsInt64()
+	// data
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	info := suite.genSegmentLoadInfos(schema)[0]
+
+	status, err := suite.node.LoadSingleSegment(ctx, info)
+	suite.Require().NoError(err)
+	suite.Equal(info.GetSegmentID(), status.SegmentID)
+	suite.Greater(status.RowNum, int64(0))
+	suite.NotNil(suite.node.manager.Segment.GetSealed(info.GetSegmentID()))
+
+	// already loaded
+	_, err = suite.node.LoadSingleSegment(ctx, info)
+	suite.ErrorIs(err, merr.ErrParameterInvalid)
+
+	// collection not loaded
+	info = &querypb.SegmentLoadInfo{
+		SegmentID:    info.GetSegmentID() + 1,
+		CollectionID: -1,
+	}
+	_, err = suite.node.LoadSingleSegment(ctx, info)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err = suite.node.LoadSingleSegment(ctx, info)
+	suite.ErrorIs(err, merr.ErrServiceNotReady)
+}
+
 func (suite *ServiceSuite) TestLoadSegments_VarChar() {
 	ctx := context.Background()
 	suite.TestWatchDmChannelsVarchar()
@@ -761,6 +792,151 @@ func (suite *ServiceSuite) TestLoadIndex_Failed() {
 	})
 }
 
+func (suite *ServiceSuite) TestRepairDeltaDivergence() {
+	ctx := context.Background()
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.TestLoadSegments_Int64()
+
+	watermarks, err := suite.node.GetDeltaWatermarks(ctx, suite.collectionID, nil)
+	suite.Require().NoError(err)
+	suite.Len(watermarks, len(suite.validSegmentIDs))
+	_, err = suite.node.GetDeltaWatermarks(ctx, suite.collectionID+1, nil)
+	suite.ErrorIs(err, merr.ErrCollectionNotLoaded)
+
+	loader := suite.node.loader
+	mockLoader := segments.NewMockLoader(suite.T())
+	suite.node.loader = mockLoader
+	defer func() {
+		suite.node.loader = loader
+	}()
+
+	// the peer has applied a deltalog ending before the watermark of the first segment, which the node hasn't
+	segmentID := watermarks[0].SegmentID
+	local := watermarks[0].Timestamp
+	applied := &datapb.Binlog{LogPath: "delta/1", TimestampFrom: 1, TimestampTo: 1}
+	missing := &datapb.Binlog{LogPath: "delta/2", TimestampFrom: 1, TimestampTo: 1}
+	infos := suite.genSegmentLoadInfos(schema)
+	for _, info := range infos {
+		info.Deltalogs = []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{applied, missing}}}
+	}
+	req := &querypb.LoadSegmentsRequest{
+		CollectionID: suite.collectionID,
+		Infos:        infos,
+	}
+	peer := []*DeltaWatermark{{
+		SegmentID:        segmentID,
+		Timestamp:        local,
+		AppliedDeltaLogs: append(watermarks[0].AppliedDeltaLogs, missing.GetLogPath()),
+	}}
+
+	// report only
+	repairs, err := suite.node.RepairDeltaDivergence(ctx, req, peer, false)
+	suite.Require().NoError(err)
+	suite.Require().Len(repairs, 1)
+	suite.Equal(segmentID, repairs[0].SegmentID)
+	suite.Equal([]*datapb.Binlog{missing}, repairs[0].Missing[0].GetBinlogs())
+	suite.False(repairs[0].Repaired)
+
+	// repair by loading the missing deltalogs regardless of the watermark
+	mockLoader.EXPECT().ReloadDeltaLogs(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, segment *segments.LocalSegment, deltaLogs []*datapb.FieldBinlog) error {
+			suite.Equal(segmentID, segment.ID())
+			suite.Equal([]*datapb.Binlog{missing}, deltaLogs[0].GetBinlogs())
+			return nil
+		}).Once()
+	repairs, err = suite.node.RepairDeltaDivergence(ctx, req, peer, true)
+	suite.Require().NoError(err)
+	suite.Require().Len(repairs, 1)
+	suite.True(repairs[0].Repaired)
+	suite.True(merr.Ok(repairs[0].Status))
+
+	// failed to repair
+	mockLoader.EXPECT().ReloadDeltaLogs(mock.Anything, mock.Anything, mock.Anything).Return(merr.WrapErrIoFailedReason("mock")).Once()
+	repairs, err = suite.node.RepairDeltaDivergence(ctx, req, peer, true)
+	suite.Require().NoError(err)
+	suite.Require().Len(repairs, 1)
+	suite.False(repairs[0].Repaired)
+	suite.False(merr.Ok(repairs[0].Status))
+}
+
+func (suite *ServiceSuite) TestBatchLoadIndex() {
+	ctx := context.Background()
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64)
+	suite.TestLoadSegments_Int64()
+
+	loader := suite.node.loader
+	mockLoader := segments.NewMockLoader(suite.T())
+	suite.node.loader = mockLoader
+	defer func() {
+		suite.node.loader = loader
+	}()
+
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.BatchLoadIndexMaxConcurrency.Key, "1")
+	defer paramtable.Get().Reset(paramtable.Get().QueryNodeCfg.BatchLoadIndexMaxConcurrency.Key)
+
+	failedSegmentID := suite.validSegmentIDs[0]
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	mockLoader.EXPECT().LoadIndex(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, segment *segments.LocalSegment, info *querypb.SegmentLoadInfo, version int64) error {
+			mu.Lock()
+			running++
+			maxRunning = lo.Max([]int{running, maxRunning})
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			if info.GetSegmentID() == failedSegmentID {
+				return errors.New("mocked error")
+			}
+			return nil
+		})
+
+	specs := []*querypb.LoadSegmentsRequest{
+		{
+			CollectionID: suite.collectionID,
+			Infos:        suite.genSegmentLoadInfos(schema),
+			LoadScope:    querypb.LoadScope_Index,
+		},
+		{
+			CollectionID: suite.collectionID + 1,
+			Infos:        []*querypb.SegmentLoadInfo{{SegmentID: suite.validSegmentIDs[0] + 1000, CollectionID: suite.collectionID + 1}},
+			LoadScope:    querypb.LoadScope_Index,
+		},
+	}
+	results, err := suite.node.BatchLoadIndex(ctx, specs)
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 2)
+	suite.Equal(1, maxRunning)
+
+	suite.Equal(suite.collectionID, results[0].CollectionID)
+	suite.Require().Len(results[0].Segments, len(suite.validSegmentIDs))
+	for _, status := range results[0].Segments {
+		suite.False(status.Skipped)
+		if status.SegmentID == failedSegmentID {
+			suite.False(merr.Ok(status.Status))
+		} else {
+			suite.True(merr.Ok(status.Status))
+		}
+	}
+
+	suite.Equal(suite.collectionID+1, results[1].CollectionID)
+	suite.Require().Len(results[1].Segments, 1)
+	suite.True(results[1].Segments[0].Skipped)
+	suite.True(merr.Ok(results[1].Segments[0].Status))
+	// all loads finished
+	suite.Eventually(func() bool {
+		return suite.node.loads.loads.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// node not healthy
+	suite.node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	defer suite.node.UpdateStateCode(commonpb.StateCode_Healthy)
+	_, err = suite.node.BatchLoadIndex(ctx, specs)
+	suite.ErrorIs(err, merr.ErrServiceNotReady)
+}
+
 func (suite *ServiceSuite) TestLoadSegments_Failed() {
 	ctx := context.Background()
 	// data
@@ -0,0 +1,192 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/internal/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// SegmentRowCountCheck is the row count of a segment computed by the statistics path and by scanning the segment.
+type SegmentRowCountCheck struct {
+	SegmentID     int64
+	PartitionID   int64
+	Channel       string
+	Growing       bool
+	StatsRowCount int64
+	ScanRowCount  int64
+}
+
+// RowCountConsistencyReport compares the row counts of the segments loaded on node
+// computed by the statistics path and by scanning, at the same timestamp.
+type RowCountConsistencyReport struct {
+	CollectionID  int64
+	Timestamp     uint64
+	StatsRowCount int64
+	ScanRowCount  int64
+	Consistent    bool
+	// Discrepancies are the segments whose row counts differ
+	Discrepancies []*SegmentRowCountCheck
+	CheckedNum    int
+}
+
+// newRowCountConsistencyReport sums up the row counts of segments and picks the discrepant ones.
+func newRowCountConsistencyReport(collectionID int64, ts uint64, counts []*SegmentRowCountCheck) *RowCountConsistencyReport {
+	report := &RowCountConsistencyReport{
+		CollectionID:  collectionID,
+		Timestamp:     ts,
+		Discrepancies: make([]*SegmentRowCountCheck, 0),
+		CheckedNum:    len(counts),
+	}
+	for _, count := range counts {
+		report.StatsRowCount += count.StatsRowCount
+		report.ScanRowCount += count.ScanRowCount
+		if count.StatsRowCount != count.ScanRowCount {
+			report.Discrepancies = append(report.Discrepancies, count)
+		}
+	}
+	report.Consistent = len(report.Discrepancies) == 0
+	return report
+}
+
+// scanRowCountPlan counts the rows by evaluating an always true filter, so that the deleted rows are
+// filtered out by the query path instead of the row number of segment.
+func scanRowCountPlan() ([]byte, error) {
+	return proto.Marshal(&planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}},
+				IsCount:    true,
+			},
+		},
+	})
+}
+
+// CheckRowCountConsistency computes the row count of each segment of the collection loaded on node both by
+// the statistics path, as GetStatistics does, and by a count query over the segment at the timestamp,
+// and reports the segments whose counts differ to find where the two paths diverge, e.g. deletes applied differently.
+// The timestamp defaults to the max one, which counts all the rows and deletes consumed as statistics do.
+func (node *QueryNode) CheckRowCountConsistency(ctx context.Context, collectionID int64, ts uint64) (*RowCountConsistencyReport, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", collectionID))
+	if node.manager.Collection.Get(collectionID) == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	if ts == 0 {
+		ts = typeutil.MaxTimestamp
+	}
+	plan, err := scanRowCountPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]*SegmentRowCountCheck, 0)
+	for _, scope := range []querypb.DataScope{querypb.DataScope_Historical, querypb.DataScope_Streaming} {
+		var stats []segments.SegmentStats
+		var readSegments []segments.Segment
+		if scope == querypb.DataScope_Historical {
+			stats, readSegments, err = segments.StatisticsHistorical(ctx, node.manager, collectionID, nil, nil)
+		} else {
+			stats, readSegments, err = segments.StatisticStreaming(ctx, node.manager, collectionID, nil, nil)
+		}
+		if err != nil {
+			log.Warn("failed to get segment statistics", zap.Error(err))
+			return nil, err
+		}
+		scopeCounts, err := node.scanRowCounts(ctx, collectionID, ts, plan, scope, stats, readSegments)
+		node.manager.Segment.Unpin(readSegments)
+		if err != nil {
+			log.Warn("failed to count rows by scanning segments", zap.Error(err))
+			return nil, err
+		}
+		counts = append(counts, scopeCounts...)
+	}
+
+	report := newRowCountConsistencyReport(collectionID, ts, counts)
+	if !report.Consistent {
+		log.Warn("row counts of statistics and scan differ",
+			zap.Int64("statsRowCount", report.StatsRowCount),
+			zap.Int64("scanRowCount", report.ScanRowCount),
+			zap.Int("discrepantSegmentNum", len(report.Discrepancies)),
+		)
+	}
+	return report, nil
+}
+
+// scanRowCounts counts the rows of the pinned segments one by one by the query path.
+func (node *QueryNode) scanRowCounts(ctx context.Context, collectionID int64, ts uint64, plan []byte, scope querypb.DataScope,
+	stats []segments.SegmentStats, readSegments []segments.Segment,
+) ([]*SegmentRowCountCheck, error) {
+	channels := make(map[int64]string, len(readSegments))
+	for _, segment := range readSegments {
+		channels[segment.ID()] = segment.Shard()
+	}
+	counts := make([]*SegmentRowCountCheck, 0, len(stats))
+	for _, stat := range stats {
+		count := &SegmentRowCountCheck{
+			SegmentID:     stat.SegmentID,
+			PartitionID:   stat.PartitionID,
+			Channel:       channels[stat.SegmentID],
+			Growing:       scope == querypb.DataScope_Streaming,
+			StatsRowCount: stat.RowCount,
+		}
+		result, err := node.QuerySegments(ctx, &querypb.QueryRequest{
+			Req: &internalpb.RetrieveRequest{
+				Base:               &commonpb.MsgBase{TargetID: paramtable.GetNodeID()},
+				CollectionID:       collectionID,
+				SerializedExprPlan: plan,
+				MvccTimestamp:      ts,
+				GuaranteeTimestamp: ts,
+				IsCount:            true,
+				Limit:              typeutil.Unlimited,
+			},
+			DmlChannels:     []string{count.Channel},
+			SegmentIDs:      []int64{stat.SegmentID},
+			FromShardLeader: true,
+			Scope:           scope,
+		})
+		if err == nil {
+			err = merr.Error(result.GetStatus())
+		}
+		if err != nil {
+			return nil, err
+		}
+		count.ScanRowCount, err = funcutil.CntOfInternalResult(result)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
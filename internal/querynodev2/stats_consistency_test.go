@@ -0,0 +1,60 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+)
+
+func TestRowCountConsistencyReport(t *testing.T) {
+	counts := []*SegmentRowCountCheck{
+		{SegmentID: 1, Channel: "dml_0", StatsRowCount: 100, ScanRowCount: 100},
+		{SegmentID: 2, Channel: "dml_0", StatsRowCount: 100, ScanRowCount: 90},
+		{SegmentID: 3, Channel: "dml_1", Growing: true, StatsRowCount: 10, ScanRowCount: 10},
+	}
+	report := newRowCountConsistencyReport(100, 1000, counts)
+	assert.False(t, report.Consistent)
+	assert.EqualValues(t, 210, report.StatsRowCount)
+	assert.EqualValues(t, 200, report.ScanRowCount)
+	assert.Equal(t, 3, report.CheckedNum)
+	assert.Equal(t, []*SegmentRowCountCheck{counts[1]}, report.Discrepancies)
+
+	report = newRowCountConsistencyReport(100, 1000, counts[2:])
+	assert.True(t, report.Consistent)
+	assert.Empty(t, report.Discrepancies)
+
+	// nothing loaded
+	report = newRowCountConsistencyReport(100, 1000, nil)
+	assert.True(t, report.Consistent)
+	assert.Equal(t, 0, report.CheckedNum)
+}
+
+func TestScanRowCountPlan(t *testing.T) {
+	serialized, err := scanRowCountPlan()
+	require.NoError(t, err)
+	plan := &planpb.PlanNode{}
+	require.NoError(t, proto.Unmarshal(serialized, plan))
+	assert.True(t, plan.GetQuery().GetIsCount())
+	// deleted rows are filtered by evaluating the filter
+	assert.NotNil(t, plan.GetQuery().GetPredicates().GetAlwaysTrueExpr())
+}
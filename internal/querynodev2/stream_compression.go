@@ -0,0 +1,98 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/compressor"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+var _ streamrpc.QueryStreamServer = (*compressedQueryStreamServer)(nil)
+
+// compressedQueryStreamServer compresses the fields data of results before sending,
+// results are sent as is if too small or compression doesn't reduce the size.
+// It's not concurrent safe, the single encoder bounds the cpu used by compression of a request.
+type compressedQueryStreamServer struct {
+	server     streamrpc.QueryStreamServer
+	compressor *compressor.ZstdCompressor
+	minSize    int
+}
+
+func newCompressedQueryStreamServer(srv streamrpc.QueryStreamServer) (*compressedQueryStreamServer, error) {
+	level := paramtable.Get().QueryNodeCfg.StreamCompressionLevel.GetAsInt()
+	if level < int(zstd.SpeedFastest) || level > int(zstd.SpeedBestCompression) {
+		level = int(zstd.SpeedFastest)
+	}
+	c, err := compressor.NewZstdCompressor(nil,
+		zstd.WithEncoderLevel(zstd.EncoderLevel(level)),
+		zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &compressedQueryStreamServer{
+		server:     srv,
+		compressor: c,
+		minSize:    paramtable.Get().QueryNodeCfg.StreamCompressionMinSize.GetAsInt(),
+	}, nil
+}
+
+func (s *compressedQueryStreamServer) Send(result *internalpb.RetrieveResults) error {
+	if len(result.GetFieldsData()) == 0 || len(result.GetCompressedFieldsData()) > 0 {
+		return s.server.Send(result)
+	}
+
+	raw, err := proto.Marshal(&internalpb.RetrieveResults{FieldsData: result.GetFieldsData()})
+	if err != nil {
+		return err
+	}
+	if len(raw) < s.minSize {
+		return s.server.Send(result)
+	}
+
+	compressed := s.compressor.CompressBytes(raw, nil)
+	if len(compressed) >= len(raw) {
+		return s.server.Send(result)
+	}
+	metrics.QueryNodeStreamCompressionRatio.WithLabelValues(fmt.Sprint(paramtable.GetNodeID())).
+		Observe(float64(len(raw)) / float64(len(compressed)))
+
+	result.FieldsData = nil
+	result.CompressedFieldsData = compressed
+	result.CompressType = string(compressor.CompressTypeZstd)
+	return s.server.Send(result)
+}
+
+func (s *compressedQueryStreamServer) Context() context.Context {
+	return s.server.Context()
+}
+
+func (s *compressedQueryStreamServer) Close() {
+	if err := s.compressor.Close(); err != nil {
+		log.Warn("failed to close stream compressor", zap.Error(err))
+	}
+}
@@ -0,0 +1,88 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/util/compressor"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func genLongFieldResult(num int) *internalpb.RetrieveResults {
+	data := make([]int64, num)
+	for i := range data {
+		data[i] = int64(i % 16)
+	}
+	return &internalpb.RetrieveResults{
+		FieldsData: []*schemapb.FieldData{
+			{
+				Type:    schemapb.DataType_Int64,
+				FieldId: 100,
+				Field: &schemapb.FieldData_Scalars{
+					Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: data}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCompressedQueryStreamServer(t *testing.T) {
+	paramtable.Init()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := streamrpc.NewLocalQueryClient(ctx)
+	srv, err := newCompressedQueryStreamServer(client.CreateServer())
+	require.NoError(t, err)
+	defer srv.Close()
+
+	// tiny batch sent uncompressed
+	require.NoError(t, srv.Send(genLongFieldResult(2)))
+	result, err := client.Recv()
+	require.NoError(t, err)
+	assert.Len(t, result.GetFieldsData(), 1)
+	assert.Empty(t, result.GetCompressedFieldsData())
+
+	// large batch compressed
+	expected := genLongFieldResult(10000)
+	require.NoError(t, srv.Send(proto.Clone(expected).(*internalpb.RetrieveResults)))
+	result, err = client.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, result.GetFieldsData())
+	assert.Equal(t, string(compressor.CompressTypeZstd), result.GetCompressType())
+	assert.Less(t, len(result.GetCompressedFieldsData()), proto.Size(expected))
+
+	decompressor, err := compressor.NewZstdDecompressor(nil)
+	require.NoError(t, err)
+	defer decompressor.Close()
+	raw, err := decompressor.DecompressBytes(result.GetCompressedFieldsData(), nil)
+	require.NoError(t, err)
+	decoded := &internalpb.RetrieveResults{}
+	require.NoError(t, proto.Unmarshal(raw, decoded))
+	assert.True(t, proto.Equal(expected, decoded))
+}
@@ -0,0 +1,375 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// streamCursorPosition is the last pk of the channel emitted by the result of the sequence,
+// done if all rows of the channel emitted.
+type streamCursorPosition struct {
+	seq     int64
+	channel string
+	lastPK  any
+	done    bool
+}
+
+// streamCursor is the server side state of a resumable stream. Rows of each channel are retrieved in pages
+// in primary key order, and every streamed result is tagged by a sequence, so that the stream could be resumed
+// after the last result received by client, even if more results were sent before disconnected.
+type streamCursor struct {
+	id string
+	// request of the stream, whose mvcc timestamp is pinned so that the resumed stream reads the same snapshot
+	req *querypb.QueryRequest
+
+	mu        sync.Mutex
+	seq       int64
+	positions []streamCursorPosition
+	active    bool
+	expireAt  time.Time
+}
+
+// emit sends the result tagged by the cursor of the next sequence, and records the position of channel.
+// Results of channels are emitted one by one to keep the sequence in the order sent.
+func (c *streamCursor) emit(srv streamrpc.QueryStreamServer, result *internalpb.RetrieveResults, channel string, lastPK any, done bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	c.positions = append(c.positions, streamCursorPosition{
+		seq:     c.seq,
+		channel: channel,
+		lastPK:  lastPK,
+		done:    done,
+	})
+	result.StreamCursor = fmt.Sprintf("%s:%d", c.id, c.seq)
+	return srv.Send(result)
+}
+
+// resumeAt drops the positions after the sequence, which are not received by client,
+// and returns the latest position of each channel.
+func (c *streamCursor) resumeAt(seq int64) map[string]streamCursorPosition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	positions := make(map[string]streamCursorPosition)
+	kept := c.positions[:0]
+	for _, position := range c.positions {
+		if position.seq > seq {
+			continue
+		}
+		kept = append(kept, position)
+		positions[position.channel] = position
+	}
+	c.positions = kept
+	c.seq = seq
+	return positions
+}
+
+// streamCursorRegistry keeps the cursors of resumable streams, a cursor expires if not resumed within
+// paramtable queryNode.stream.cursorTTL after its stream ends.
+type streamCursorRegistry struct {
+	mu sync.Mutex
+	// epoch distinguishes the cursors of restarted node
+	epoch   int64
+	nextID  int64
+	cursors map[string]*streamCursor
+}
+
+func newStreamCursorRegistry() *streamCursorRegistry {
+	return &streamCursorRegistry{
+		epoch:   time.Now().UnixNano(),
+		cursors: make(map[string]*streamCursor),
+	}
+}
+
+// gc removes the expired cursors, the caller shall hold the lock.
+func (r *streamCursorRegistry) gc(now time.Time) {
+	for id, cursor := range r.cursors {
+		if !cursor.active && now.After(cursor.expireAt) {
+			delete(r.cursors, id)
+		}
+	}
+}
+
+// create registers the cursor of a new stream, which is active until released.
+func (r *streamCursorRegistry) create(req *querypb.QueryRequest) *streamCursor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gc(time.Now())
+	r.nextID++
+	cursor := &streamCursor{
+		id:     fmt.Sprintf("%d-%d-%d", paramtable.GetNodeID(), r.epoch, r.nextID),
+		req:    req,
+		active: true,
+	}
+	r.cursors[cursor.id] = cursor
+	return cursor
+}
+
+// acquire activates the cursor of token to resume its stream, and returns the positions of channels to resume from.
+func (r *streamCursorRegistry) acquire(token string, collectionID int64) (*streamCursor, map[string]streamCursorPosition, error) {
+	sep := strings.LastIndex(token, ":")
+	if sep < 0 {
+		return nil, nil, merr.WrapErrParameterInvalid("stream cursor of id:seq", token, "invalid stream cursor")
+	}
+	seq, err := strconv.ParseInt(token[sep+1:], 10, 64)
+	if err != nil || seq < 0 {
+		return nil, nil, merr.WrapErrParameterInvalid("stream cursor of id:seq", token, "invalid stream cursor")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gc(time.Now())
+	cursor, ok := r.cursors[token[:sep]]
+	if !ok {
+		return nil, nil, merr.WrapErrParameterInvalidMsg("stream cursor %s not found, expired or served by another node", token)
+	}
+	if cursor.req.GetReq().GetCollectionID() != collectionID {
+		return nil, nil, merr.WrapErrParameterInvalidMsg("stream cursor %s is not of collection %d", token, collectionID)
+	}
+	if cursor.active {
+		return nil, nil, merr.WrapErrParameterInvalidMsg("stream cursor %s is being resumed by another stream", token)
+	}
+	if seq > cursor.seq {
+		return nil, nil, merr.WrapErrParameterInvalidMsg("stream cursor %s is ahead of the stream", token)
+	}
+	cursor.active = true
+	return cursor, cursor.resumeAt(seq), nil
+}
+
+// release deactivates the cursor when its stream ends, which expires after the ttl.
+func (r *streamCursorRegistry) release(cursor *streamCursor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cursor.active = false
+	cursor.expireAt = time.Now().Add(paramtable.Get().QueryNodeCfg.StreamCursorTTL.GetAsDuration(time.Second))
+}
+
+// validateResumableStream checks the retrieve request could be streamed in primary key order by pages.
+func validateResumableStream(req *querypb.QueryRequest) error {
+	switch {
+	case req.GetReq().GetIsCount():
+		return merr.WrapErrParameterInvalidMsg("resumable stream of count query is not supported")
+	case req.GetReq().GetLimit() > 0 && req.GetReq().GetLimit() != typeutil.Unlimited:
+		return merr.WrapErrParameterInvalidMsg("resumable stream with limit is not supported")
+	case req.GetReq().GetSampleSize() > 0:
+		return merr.WrapErrParameterInvalidMsg("resumable stream of sampled query is not supported")
+	case req.GetReq().GetDistinctCountFieldID() > 0:
+		return merr.WrapErrParameterInvalidMsg("resumable stream of distinct count is not supported")
+	case len(req.GetReq().GetSortKeys()) > 0:
+		return merr.WrapErrParameterInvalidMsg("resumable stream is ordered by primary key, sort keys are not supported")
+	}
+	return nil
+}
+
+// pinStreamSnapshot pins the mvcc timestamp of the stream request, the current time if neither mvcc nor guarantee
+// timestamp set, and makes delegators wait for it, so every page reads the same snapshot.
+func pinStreamSnapshot(req *querypb.QueryRequest, now time.Time) *querypb.QueryRequest {
+	pinned := proto.Clone(req).(*querypb.QueryRequest)
+	mvccTs := pinned.GetReq().GetMvccTimestamp()
+	if mvccTs == 0 {
+		mvccTs = pinned.GetReq().GetGuaranteeTimestamp()
+	}
+	if mvccTs == 0 {
+		mvccTs = tsoutil.ComposeTSByTime(now, 0)
+	}
+	pinned.Req.MvccTimestamp = mvccTs
+	if pinned.GetReq().GetGuaranteeTimestamp() < mvccTs {
+		pinned.Req.GuaranteeTimestamp = mvccTs
+	}
+	pinned.Req.ResumableStream = true
+	pinned.Req.StreamCursor = ""
+	return pinned
+}
+
+// pkAfterExpr returns the expr of primary key greater than the pk.
+func pkAfterExpr(pkField *schemapb.FieldSchema, pk any) (*planpb.Expr, error) {
+	var value *planpb.GenericValue
+	switch pk := pk.(type) {
+	case int64:
+		value = &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: pk}}
+	case string:
+		value = &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: pk}}
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unsupported primary key type %s", pkField.GetDataType().String())
+	}
+	return &planpb.Expr{
+		Expr: &planpb.Expr_UnaryRangeExpr{
+			UnaryRangeExpr: &planpb.UnaryRangeExpr{
+				ColumnInfo: &planpb.ColumnInfo{
+					FieldId:      pkField.GetFieldID(),
+					DataType:     pkField.GetDataType(),
+					IsPrimaryKey: true,
+				},
+				Op:    planpb.OpType_GreaterThan,
+				Value: value,
+			},
+		},
+	}, nil
+}
+
+// streamPageRequest returns the request retrieving the page of channel after the last pk, nil for the first page.
+// The limited retrieve of segcore picks the rows in primary key order and the reducer merges them by primary key,
+// so pages are in primary key order.
+func streamPageRequest(req *querypb.QueryRequest, pkField *schemapb.FieldSchema, channel string, lastPK any, pageSize int64) (*querypb.QueryRequest, error) {
+	plan := planpb.PlanNode{}
+	if err := proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), &plan); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid serialized retrieve plan", "no unmarshalable one", err.Error())
+	}
+	query := plan.GetQuery()
+	if query == nil {
+		return nil, merr.WrapErrParameterInvalidMsg("resumable stream requires a retrieve plan")
+	}
+	if lastPK != nil {
+		after, err := pkAfterExpr(pkField, lastPK)
+		if err != nil {
+			return nil, err
+		}
+		if query.GetPredicates() == nil {
+			query.Predicates = after
+		} else {
+			query.Predicates = &planpb.Expr{
+				Expr: &planpb.Expr_BinaryExpr{
+					BinaryExpr: &planpb.BinaryExpr{
+						Op:    planpb.BinaryExpr_LogicalAnd,
+						Left:  query.GetPredicates(),
+						Right: after,
+					},
+				},
+			}
+		}
+	}
+	query.Limit = pageSize
+	serializedExprPlan, err := proto.Marshal(&plan)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("marshalable retrieve plan", "plan with marshal error", err.Error())
+	}
+
+	pageReq := &querypb.QueryRequest{
+		Req:         proto.Clone(req.GetReq()).(*internalpb.RetrieveRequest),
+		DmlChannels: []string{channel},
+		SegmentIDs:  req.GetSegmentIDs(),
+		Scope:       req.GetScope(),
+	}
+	pageReq.Req.SerializedExprPlan = serializedExprPlan
+	pageReq.Req.Limit = pageSize
+	return pageReq, nil
+}
+
+// queryStreamResumable streams the rows of channels in pages, or resumes the stream of the cursor requested.
+func (node *QueryNode) queryStreamResumable(ctx context.Context, req *querypb.QueryRequest, srv streamrpc.QueryStreamServer) error {
+	var cursor *streamCursor
+	positions := make(map[string]streamCursorPosition)
+	if token := req.GetReq().GetStreamCursor(); token != "" {
+		var err error
+		cursor, positions, err = node.streamCursors.acquire(token, req.GetReq().GetCollectionID())
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := validateResumableStream(req); err != nil {
+			return err
+		}
+		cursor = node.streamCursors.create(pinStreamSnapshot(req, time.Now()))
+	}
+	defer node.streamCursors.release(cursor)
+
+	collection := node.manager.Collection.Get(cursor.req.GetReq().GetCollectionID())
+	if collection == nil {
+		return merr.WrapErrCollectionNotLoaded(cursor.req.GetReq().GetCollectionID())
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return err
+	}
+	log.Ctx(ctx).Debug("stream resumable query",
+		zap.String("cursor", cursor.id),
+		zap.Bool("resumed", req.GetReq().GetStreamCursor() != ""),
+		zap.Uint64("mvccTimestamp", cursor.req.GetReq().GetMvccTimestamp()),
+	)
+
+	runningGp, runningCtx := errgroup.WithContext(ctx)
+	for _, ch := range cursor.req.GetDmlChannels() {
+		ch := ch
+		position, ok := positions[ch]
+		if ok && position.done {
+			continue
+		}
+		runningGp.Go(func() error {
+			return node.queryChannelResumable(runningCtx, cursor, pkField, ch, position.lastPK, srv)
+		})
+	}
+	return runningGp.Wait()
+}
+
+// queryChannelResumable retrieves and streams the rows of channel after the last pk page by page,
+// until a page not full.
+func (node *QueryNode) queryChannelResumable(ctx context.Context, cursor *streamCursor, pkField *schemapb.FieldSchema, channel string, lastPK any, srv streamrpc.QueryStreamServer) error {
+	pageSize := paramtable.Get().QueryNodeCfg.StreamResumablePageSize.GetAsInt64()
+	if pageSize <= 0 {
+		pageSize = 1
+	}
+	for {
+		pageReq, err := streamPageRequest(cursor.req, pkField, channel, lastPK, pageSize)
+		if err != nil {
+			return err
+		}
+		result, err := node.queryChannel(ctx, pageReq, channel)
+		if err != nil {
+			return err
+		}
+		if !merr.Ok(result.GetStatus()) {
+			return merr.Error(result.GetStatus())
+		}
+		rowNum := typeutil.GetSizeOfIDs(result.GetIds())
+		done := int64(rowNum) < pageSize
+		if rowNum == 0 {
+			return nil
+		}
+		for i := 0; i < rowNum; i++ {
+			if pk := typeutil.GetPK(result.GetIds(), int64(i)); lastPK == nil || typeutil.ComparePK(lastPK, pk) {
+				lastPK = pk
+			}
+		}
+		if err := cursor.emit(srv, result, channel, lastPK, done); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
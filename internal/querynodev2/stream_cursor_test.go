@@ -0,0 +1,191 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+func TestStreamCursorRegistry(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := streamrpc.NewLocalQueryClient(ctx)
+	srv := client.CreateServer()
+
+	registry := newStreamCursorRegistry()
+	req := &querypb.QueryRequest{
+		Req:         &internalpb.RetrieveRequest{CollectionID: 100},
+		DmlChannels: []string{"ch1", "ch2"},
+	}
+	cursor := registry.create(req)
+	emit := func(channel string, lastPK any, done bool) string {
+		require.NoError(t, cursor.emit(srv, &internalpb.RetrieveResults{Status: merr.Success()}, channel, lastPK, done))
+		result, err := client.Recv()
+		require.NoError(t, err)
+		return result.GetStreamCursor()
+	}
+	assert.Equal(t, cursor.id+":1", emit("ch1", int64(10), false))
+	token := emit("ch2", int64(5), false)
+	assert.Equal(t, cursor.id+":2", token)
+	assert.Equal(t, cursor.id+":3", emit("ch1", int64(20), true))
+
+	// being streamed
+	_, _, err := registry.acquire(token, 100)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	registry.release(cursor)
+
+	// invalid cursors
+	for _, invalid := range []string{"", cursor.id, cursor.id + ":x", cursor.id + ":-1", "unknown:1", cursor.id + ":4"} {
+		_, _, err = registry.acquire(invalid, 100)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, invalid)
+	}
+	_, _, err = registry.acquire(token, 101)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// resumed after the last received result, the positions after it are dropped
+	resumed, positions, err := registry.acquire(token, 100)
+	require.NoError(t, err)
+	assert.Same(t, cursor, resumed)
+	assert.Equal(t, map[string]streamCursorPosition{
+		"ch1": {seq: 1, channel: "ch1", lastPK: int64(10)},
+		"ch2": {seq: 2, channel: "ch2", lastPK: int64(5)},
+	}, positions)
+	assert.Equal(t, cursor.id+":3", emit("ch1", int64(15), false))
+	registry.release(cursor)
+
+	// expired
+	params.Save(params.QueryNodeCfg.StreamCursorTTL.Key, "0")
+	defer params.Reset(params.QueryNodeCfg.StreamCursorTTL.Key)
+	_, _, err = registry.acquire(token, 100)
+	require.NoError(t, err)
+	registry.release(cursor)
+	time.Sleep(time.Millisecond)
+	_, _, err = registry.acquire(token, 100)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// cursors of restarted node never resolved
+	assert.NotEqual(t, cursor.id, newStreamCursorRegistry().create(req).id)
+}
+
+func TestValidateResumableStream(t *testing.T) {
+	assert.NoError(t, validateResumableStream(&querypb.QueryRequest{Req: &internalpb.RetrieveRequest{}}))
+	for _, req := range []*internalpb.RetrieveRequest{
+		{IsCount: true},
+		{Limit: 10},
+		{SampleSize: 10},
+		{DistinctCountFieldID: 101},
+		{SortKeys: []*internalpb.SortKey{{FieldID: 101}}},
+	} {
+		assert.ErrorIs(t, validateResumableStream(&querypb.QueryRequest{Req: req}), merr.ErrParameterInvalid)
+	}
+}
+
+func TestPinStreamSnapshot(t *testing.T) {
+	now := time.Now()
+	req := &querypb.QueryRequest{Req: &internalpb.RetrieveRequest{StreamCursor: "cursor"}}
+	pinned := pinStreamSnapshot(req, now)
+	assert.Equal(t, tsoutil.ComposeTSByTime(now, 0), pinned.GetReq().GetMvccTimestamp())
+	assert.Equal(t, pinned.GetReq().GetMvccTimestamp(), pinned.GetReq().GetGuaranteeTimestamp())
+	assert.True(t, pinned.GetReq().GetResumableStream())
+	assert.Empty(t, pinned.GetReq().GetStreamCursor())
+	// not modified
+	assert.EqualValues(t, 0, req.GetReq().GetMvccTimestamp())
+
+	pinned = pinStreamSnapshot(&querypb.QueryRequest{Req: &internalpb.RetrieveRequest{GuaranteeTimestamp: 100}}, now)
+	assert.EqualValues(t, 100, pinned.GetReq().GetMvccTimestamp())
+	assert.EqualValues(t, 100, pinned.GetReq().GetGuaranteeTimestamp())
+
+	pinned = pinStreamSnapshot(&querypb.QueryRequest{Req: &internalpb.RetrieveRequest{MvccTimestamp: 200, GuaranteeTimestamp: 100}}, now)
+	assert.EqualValues(t, 200, pinned.GetReq().GetMvccTimestamp())
+	assert.EqualValues(t, 200, pinned.GetReq().GetGuaranteeTimestamp())
+}
+
+func TestStreamPageRequest(t *testing.T) {
+	pkField := &schemapb.FieldSchema{FieldID: 100, DataType: schemapb.DataType_Int64, IsPrimaryKey: true}
+	predicates := &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}}
+	plan, err := proto.Marshal(&planpb.PlanNode{
+		Node:           &planpb.PlanNode_Query{Query: &planpb.QueryPlanNode{Predicates: predicates}},
+		OutputFieldIds: []int64{100},
+	})
+	require.NoError(t, err)
+	req := &querypb.QueryRequest{
+		Req:         &internalpb.RetrieveRequest{CollectionID: 1, SerializedExprPlan: plan, MvccTimestamp: 1000},
+		DmlChannels: []string{"ch1", "ch2"},
+	}
+	decode := func(req *querypb.QueryRequest) *planpb.PlanNode {
+		plan := &planpb.PlanNode{}
+		require.NoError(t, proto.Unmarshal(req.GetReq().GetSerializedExprPlan(), plan))
+		return plan
+	}
+
+	// first page
+	pageReq, err := streamPageRequest(req, pkField, "ch2", nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch2"}, pageReq.GetDmlChannels())
+	assert.EqualValues(t, 10, pageReq.GetReq().GetLimit())
+	assert.EqualValues(t, 1000, pageReq.GetReq().GetMvccTimestamp())
+	assert.EqualValues(t, 10, decode(pageReq).GetQuery().GetLimit())
+	assert.True(t, proto.Equal(predicates, decode(pageReq).GetQuery().GetPredicates()))
+	assert.Equal(t, []int64{100}, decode(pageReq).GetOutputFieldIds())
+	// the template is not modified
+	assert.EqualValues(t, 0, req.GetReq().GetLimit())
+
+	// after the last pk
+	pageReq, err = streamPageRequest(req, pkField, "ch2", int64(42), 10)
+	require.NoError(t, err)
+	and := decode(pageReq).GetQuery().GetPredicates().GetBinaryExpr()
+	require.NotNil(t, and)
+	assert.Equal(t, planpb.BinaryExpr_LogicalAnd, and.GetOp())
+	assert.True(t, proto.Equal(predicates, and.GetLeft()))
+	after := and.GetRight().GetUnaryRangeExpr()
+	assert.Equal(t, planpb.OpType_GreaterThan, after.GetOp())
+	assert.True(t, after.GetColumnInfo().GetIsPrimaryKey())
+	assert.EqualValues(t, 42, after.GetValue().GetInt64Val())
+
+	// varchar pk without filter
+	varcharField := &schemapb.FieldSchema{FieldID: 100, DataType: schemapb.DataType_VarChar, IsPrimaryKey: true}
+	plan, err = proto.Marshal(&planpb.PlanNode{Node: &planpb.PlanNode_Query{Query: &planpb.QueryPlanNode{}}})
+	require.NoError(t, err)
+	req.Req.SerializedExprPlan = plan
+	pageReq, err = streamPageRequest(req, varcharField, "ch1", "a", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "a", decode(pageReq).GetQuery().GetPredicates().GetUnaryRangeExpr().GetValue().GetStringVal())
+
+	// not a retrieve plan
+	plan, err = proto.Marshal(&planpb.PlanNode{Node: &planpb.PlanNode_VectorAnns{VectorAnns: &planpb.VectorANNS{}}})
+	require.NoError(t, err)
+	req.Req.SerializedExprPlan = plan
+	_, err = streamPageRequest(req, pkField, "ch1", nil, 10)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
@@ -0,0 +1,98 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// errStreamTruncated stops the streaming of delegators once the max stream bytes exceeded,
+// the truncated result has been sent to client already.
+var errStreamTruncated = errors.New("query stream truncated")
+
+var _ streamrpc.QueryStreamServer = (*sizeLimitedQueryStreamServer)(nil)
+
+// sizeLimitedQueryStreamServer ends the stream with a truncated result once the streamed payload exceeds the max bytes,
+// the result exceeding the bound is not sent.
+// It's not concurrent safe, which shall be wrapped by the concurrent server.
+type sizeLimitedQueryStreamServer struct {
+	server    streamrpc.QueryStreamServer
+	maxBytes  int64
+	sentBytes int64
+	truncated bool
+}
+
+// maxStreamBytes returns the max bytes of stream requested by client and capped by paramtable
+// queryNode.stream.maxResultBytes, 0 means no limit.
+func maxStreamBytes(requested int64) int64 {
+	if requested <= 0 {
+		return 0
+	}
+	limit := paramtable.Get().QueryNodeCfg.StreamMaxResultBytes.GetAsInt64()
+	if limit > 0 && requested > limit {
+		return limit
+	}
+	return requested
+}
+
+func newSizeLimitedQueryStreamServer(srv streamrpc.QueryStreamServer, maxBytes int64) *sizeLimitedQueryStreamServer {
+	return &sizeLimitedQueryStreamServer{
+		server:   srv,
+		maxBytes: maxBytes,
+	}
+}
+
+func (s *sizeLimitedQueryStreamServer) Send(result *internalpb.RetrieveResults) error {
+	// failure status is always sent to client
+	if !merr.Ok(result.GetStatus()) {
+		return s.server.Send(result)
+	}
+	if s.truncated {
+		return errStreamTruncated
+	}
+
+	size := int64(proto.Size(result))
+	if s.sentBytes+size <= s.maxBytes {
+		s.sentBytes += size
+		return s.server.Send(result)
+	}
+
+	s.truncated = true
+	metrics.QueryNodeTruncatedStreamCount.WithLabelValues(fmt.Sprint(paramtable.GetNodeID())).Inc()
+	err := s.server.Send(&internalpb.RetrieveResults{
+		Status:    merr.Success(fmt.Sprintf("result truncated, streamed %d bytes exceeds max stream bytes %d", s.sentBytes+size, s.maxBytes)),
+		Truncated: true,
+	})
+	if err != nil {
+		return err
+	}
+	return errStreamTruncated
+}
+
+func (s *sizeLimitedQueryStreamServer) Context() context.Context {
+	return s.server.Context()
+}
@@ -0,0 +1,86 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestMaxStreamBytes(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+
+	assert.EqualValues(t, 0, maxStreamBytes(0))
+	assert.EqualValues(t, 0, maxStreamBytes(-1))
+	assert.EqualValues(t, 1024, maxStreamBytes(1024))
+
+	params.Save(params.QueryNodeCfg.StreamMaxResultBytes.Key, "100")
+	defer params.Reset(params.QueryNodeCfg.StreamMaxResultBytes.Key)
+	assert.EqualValues(t, 100, maxStreamBytes(1024))
+	assert.EqualValues(t, 10, maxStreamBytes(10))
+
+	// not capped
+	params.Save(params.QueryNodeCfg.StreamMaxResultBytes.Key, "0")
+	assert.EqualValues(t, 1024, maxStreamBytes(1024))
+}
+
+func TestSizeLimitedQueryStreamServer(t *testing.T) {
+	paramtable.Init()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	batch := genLongFieldResult(100)
+	batchSize := int64(proto.Size(batch))
+	client := streamrpc.NewLocalQueryClient(ctx)
+	srv := newSizeLimitedQueryStreamServer(client.CreateServer(), batchSize*2+batchSize/2)
+
+	// batches within the bound are sent
+	for i := 0; i < 2; i++ {
+		require.NoError(t, srv.Send(proto.Clone(batch).(*internalpb.RetrieveResults)))
+		result, err := client.Recv()
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(batch, result))
+	}
+
+	// batch exceeding the bound is replaced by the truncated result
+	err := srv.Send(proto.Clone(batch).(*internalpb.RetrieveResults))
+	assert.True(t, errors.Is(err, errStreamTruncated))
+	result, err := client.Recv()
+	require.NoError(t, err)
+	assert.True(t, merr.Ok(result.GetStatus()))
+	assert.True(t, result.GetTruncated())
+	assert.Empty(t, result.GetFieldsData())
+
+	// nothing sent after truncated except failures
+	err = srv.Send(genLongFieldResult(1))
+	assert.True(t, errors.Is(err, errStreamTruncated))
+	require.NoError(t, srv.Send(&internalpb.RetrieveResults{Status: merr.Status(merr.ErrServiceInternal)}))
+	result, err = client.Recv()
+	require.NoError(t, err)
+	assert.False(t, merr.Ok(result.GetStatus()))
+}
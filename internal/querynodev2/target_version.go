@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// targetVersionCheckInterval is the interval to check whether the delegators reached the required target version.
+const targetVersionCheckInterval = 100 * time.Millisecond
+
+// ChannelTargetVersion is the current target version of a delegator, and whether it reached the required one.
+type ChannelTargetVersion struct {
+	Channel         string
+	CollectionID    int64
+	TargetVersion   int64
+	RequiredVersion int64
+	Reached         bool
+}
+
+// channelTargetVersions returns the target versions of the delegators of the collection on node.
+func (node *QueryNode) channelTargetVersions(collectionID int64, requiredVersion int64) []*ChannelTargetVersion {
+	versions := make([]*ChannelTargetVersion, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() != collectionID {
+			return true
+		}
+		version := &ChannelTargetVersion{
+			Channel:         channel,
+			CollectionID:    collectionID,
+			TargetVersion:   sd.GetTargetVersion(),
+			RequiredVersion: requiredVersion,
+		}
+		version.Reached = version.TargetVersion >= requiredVersion
+		versions = append(versions, version)
+		return true
+	})
+	return versions
+}
+
+// GetTargetVersions returns the current target version of each channel of the collection served by the node,
+// and whether it reached the version required by client, e.g. the one its writes landed in for read-your-writes
+// without Strong consistency. If timeout is positive, it waits until all channels reached the required version
+// or timeout, the versions are returned without error on timeout and the client checks whether reached.
+func (node *QueryNode) GetTargetVersions(ctx context.Context, collectionID int64, requiredVersion int64, timeout time.Duration) ([]*ChannelTargetVersion, error) {
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	versions := node.channelTargetVersions(collectionID, requiredVersion)
+	if len(versions) == 0 {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID, "no delegator of collection on node")
+	}
+	if timeout <= 0 {
+		return versions, nil
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(targetVersionCheckInterval)
+	defer ticker.Stop()
+	for {
+		reached := true
+		for _, version := range versions {
+			reached = reached && version.Reached
+		}
+		if reached {
+			return versions, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			log.Ctx(ctx).Info("delegators not reached required target version before timeout",
+				zap.Int64("collectionID", collectionID),
+				zap.Int64("requiredVersion", requiredVersion),
+				zap.Duration("timeout", timeout),
+			)
+			return versions, nil
+		case <-ticker.C:
+		}
+		versions = node.channelTargetVersions(collectionID, requiredVersion)
+		if len(versions) == 0 {
+			return nil, merr.WrapErrCollectionNotLoaded(collectionID, "delegators of collection released while waiting")
+		}
+	}
+}
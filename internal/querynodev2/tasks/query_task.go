@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/querynodev2/collector"
@@ -95,6 +96,26 @@ func (t *QueryTask) Execute() error {
 		return err
 	}
 
+	// sketch the distinct values of segments, so that matched rows are not returned
+	if fieldID := t.req.GetReq().GetDistinctCountFieldID(); fieldID != 0 {
+		sketch, err := segments.SketchDistinct(results, fieldID)
+		if err != nil {
+			return err
+		}
+		t.result = &internalpb.RetrieveResults{
+			Base: &commonpb.MsgBase{
+				SourceID: paramtable.GetNodeID(),
+			},
+			Status:         merr.Success(),
+			Ids:            &schemapb.IDs{},
+			DistinctSketch: sketch.Bytes(),
+			CostAggregation: &internalpb.CostAggregation{
+				ServiceTime: tr.ElapseSpan().Milliseconds(),
+			},
+		}
+		return nil
+	}
+
 	reducer := segments.CreateSegCoreReducer(
 		t.req,
 		t.collection.Schema(),
@@ -121,6 +142,11 @@ func (t *QueryTask) Execute() error {
 			ServiceTime: tr.ElapseSpan().Milliseconds(),
 		},
 	}
+
+	// rows are sampled from segments, report the matched rows for the sampling across nodes
+	if t.req.GetReq().GetSampleSize() > 0 {
+		t.result.SamplePopulation = segments.SampleSegcorePopulation(results)
+	}
 	return nil
 }
 
@@ -153,6 +153,12 @@ func (t *SearchTask) Execute() error {
 	}
 	defer segments.DeleteSearchResults(results)
 
+	// merged tasks search the same segments
+	var scannedRows int64
+	for _, segment := range searchedSegments {
+		scannedRows += segment.RowNum()
+	}
+
 	if len(results) == 0 {
 		for i := range t.originNqs {
 			var task *SearchTask
@@ -172,38 +178,53 @@ func (t *SearchTask) Execute() error {
 				TopK:           t.originTopks[i],
 				SlicedOffset:   1,
 				SlicedNumCount: 1,
+				IsPartial:      searchReq.Partial(),
 				CostAggregation: &internalpb.CostAggregation{
 					ServiceTime: tr.ElapseSpan().Milliseconds(),
 				},
+				ScannedSegments: int64(len(searchedSegments)),
+				ScannedRows:     scannedRows,
 			}
 		}
 		return nil
 	}
 
 	tr.RecordSpan()
-	blobs, err := segments.ReduceSearchResultsAndFillData(
-		searchReq.Plan(),
-		results,
-		int64(len(results)),
-		t.originNqs,
-		t.originTopks,
-	)
+	var sliceBlobs [][]byte
+	if req.GetReq().GetReturnSegmentId() || req.GetReq().GetInsertionOrderTiebreak() {
+		// hits are annotated per segment, segment results shall be reduced separately
+		annotators := make([]segments.SearchResultAnnotator, 0, 3)
+		if req.GetReq().GetReturnSegmentId() {
+			annotators = append(annotators, segments.AnnotateSegmentID)
+			if req.GetReq().GetReturnSegmentFlushTs() {
+				annotators = append(annotators, segments.SegmentFlushTsAnnotator(searchedSegments))
+			}
+		}
+		if req.GetReq().GetInsertionOrderTiebreak() {
+			annotators = append(annotators, segments.InsertionTsAnnotator(t.ctx, t.collection, searchedSegments))
+		}
+		sliceBlobs, err = segments.ReduceSearchResultsBySegment(
+			segments.WithInsertionOrderTiebreak(t.ctx, req.GetReq().GetInsertionOrderTiebreak()),
+			searchReq.Plan(),
+			results,
+			t.originNqs,
+			t.originTopks,
+			req.GetReq().GetMetricType(),
+			annotators...,
+		)
+	} else {
+		sliceBlobs, err = t.reduceResults(searchReq, results)
+	}
 	if err != nil {
 		log.Warn("failed to reduce search results", zap.Error(err))
 		return err
 	}
-	defer segments.DeleteSearchResultDataBlobs(blobs)
 	metrics.QueryNodeReduceLatency.WithLabelValues(
 		fmt.Sprint(paramtable.GetNodeID()),
 		metrics.SearchLabel,
 		metrics.ReduceSegments).
 		Observe(float64(tr.RecordSpan().Milliseconds()))
 	for i := range t.originNqs {
-		blob, err := segments.GetSearchResultDataBlob(blobs, i)
-		if err != nil {
-			return err
-		}
-
 		var task *SearchTask
 		if i == 0 {
 			task = t
@@ -211,10 +232,6 @@ func (t *SearchTask) Execute() error {
 			task = t.others[i-1]
 		}
 
-		// Note: blob is unsafe because get from C
-		bs := make([]byte, len(blob))
-		copy(bs, blob)
-
 		task.result = &internalpb.SearchResults{
 			Base: &commonpb.MsgBase{
 				SourceID: paramtable.GetNodeID(),
@@ -223,18 +240,50 @@ func (t *SearchTask) Execute() error {
 			MetricType:     req.GetReq().GetMetricType(),
 			NumQueries:     t.originNqs[i],
 			TopK:           t.originTopks[i],
-			SlicedBlob:     bs,
+			SlicedBlob:     sliceBlobs[i],
 			SlicedOffset:   1,
 			SlicedNumCount: 1,
+			IsPartial:      searchReq.Partial(),
 			CostAggregation: &internalpb.CostAggregation{
 				ServiceTime: tr.ElapseSpan().Milliseconds(),
 			},
+			ScannedSegments: int64(len(searchedSegments)),
+			ScannedRows:     scannedRows,
 		}
 	}
 
 	return nil
 }
 
+// reduceResults reduces segment results in segcore, returns result blob of each merged task.
+func (t *SearchTask) reduceResults(searchReq *segments.SearchRequest, results []*segments.SearchResult) ([][]byte, error) {
+	blobs, err := segments.ReduceSearchResultsAndFillData(
+		searchReq.Plan(),
+		results,
+		int64(len(results)),
+		t.originNqs,
+		t.originTopks,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer segments.DeleteSearchResultDataBlobs(blobs)
+
+	sliceBlobs := make([][]byte, len(t.originNqs))
+	for i := range t.originNqs {
+		blob, err := segments.GetSearchResultDataBlob(blobs, i)
+		if err != nil {
+			return nil, err
+		}
+
+		// Note: blob is unsafe because get from C
+		bs := make([]byte, len(blob))
+		copy(bs, blob)
+		sliceBlobs[i] = bs
+	}
+	return sliceBlobs, nil
+}
+
 func (t *SearchTask) Merge(other *SearchTask) bool {
 	var (
 		nq        = t.nq
@@ -258,7 +307,11 @@ func (t *SearchTask) Merge(other *SearchTask) bool {
 		diffTopk && ratio > paramtable.Get().QueryNodeCfg.TopKMergeRatio.GetAsFloat() ||
 		!funcutil.SliceSetEqual(t.req.GetReq().GetPartitionIDs(), other.req.GetReq().GetPartitionIDs()) ||
 		!funcutil.SliceSetEqual(t.req.GetSegmentIDs(), other.req.GetSegmentIDs()) ||
-		!bytes.Equal(t.req.GetReq().GetSerializedExprPlan(), other.req.GetReq().GetSerializedExprPlan()) {
+		!bytes.Equal(t.req.GetReq().GetSerializedExprPlan(), other.req.GetReq().GetSerializedExprPlan()) ||
+		t.req.GetReq().GetPreferCached() != other.req.GetReq().GetPreferCached() ||
+		t.req.GetReq().GetReturnSegmentId() != other.req.GetReq().GetReturnSegmentId() ||
+		t.req.GetReq().GetInsertionOrderTiebreak() != other.req.GetReq().GetInsertionOrderTiebreak() ||
+		t.req.GetReq().GetReturnSegmentFlushTs() != other.req.GetReq().GetReturnSegmentFlushTs() {
 		return false
 	}
 
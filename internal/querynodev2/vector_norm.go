@@ -0,0 +1,244 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// SegmentVectorNorm is the L2 norm statistics of sampled vectors of segment.
+type SegmentVectorNorm struct {
+	SegmentID   int64
+	Channel     string
+	NodeID      int64
+	Growing     bool
+	SampledRows int64
+	MeanNorm    float64
+	// population variance of the sampled norms
+	NormVariance float64
+	// Reason tells why the segment is not sampled, empty if sampled
+	Reason string
+}
+
+// GetSegmentVectorNorms samples the vectors of each readable segment in the distribution of delegators,
+// and returns the mean and variance of their L2 norms, which reveals whether the vectors were normalized,
+// e.g. mean norm 1 with variance 0 for normalized ones.
+// At most sampleSize vectors are sampled per segment, bounded by paramtable queryNode.vectorNormSampleSize,
+// non-positive sampleSize uses the bound. Segments served by other nodes are reported but not sampled.
+func (node *QueryNode) GetSegmentVectorNorms(ctx context.Context, collectionID int64, fieldID int64, sampleSize int64) ([]*SegmentVectorNorm, error) {
+	log := log.Ctx(ctx).With(
+		zap.Int64("collectionID", collectionID),
+		zap.Int64("fieldID", fieldID),
+	)
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	field, ok := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == fieldID
+	})
+	if !ok {
+		return nil, merr.WrapErrFieldNotFound(fieldID)
+	}
+	if field.GetDataType() != schemapb.DataType_FloatVector && field.GetDataType() != schemapb.DataType_Float16Vector {
+		return nil, merr.WrapErrParameterInvalidMsg("vector norm is not supported on type %s", field.GetDataType().String())
+	}
+	maxSampleSize := paramtable.Get().QueryNodeCfg.VectorNormSampleSize.GetAsInt64()
+	if sampleSize <= 0 || sampleSize > maxSampleSize {
+		sampleSize = maxSampleSize
+	}
+
+	channels := make([]string, 0)
+	node.delegators.Range(func(channel string, sd delegator.ShardDelegator) bool {
+		if sd.Collection() == collectionID {
+			channels = append(channels, channel)
+		}
+		return true
+	})
+	if len(channels) == 0 {
+		return nil, merr.WrapErrChannelNotFound(fmt.Sprintf("delegators of collection %d", collectionID))
+	}
+	sort.Strings(channels)
+
+	norms := make([]*SegmentVectorNorm, 0)
+	for _, channel := range channels {
+		sd, ok := node.delegators.Get(channel)
+		if !ok {
+			continue
+		}
+		sealed, growing := sd.GetSegmentInfo(true)
+		entries := make([]*SegmentVectorNorm, 0)
+		for _, item := range sealed {
+			for _, segment := range item.Segments {
+				entries = append(entries, &SegmentVectorNorm{SegmentID: segment.SegmentID, Channel: channel, NodeID: item.NodeID})
+			}
+		}
+		for _, segment := range growing {
+			entries = append(entries, &SegmentVectorNorm{SegmentID: segment.SegmentID, Channel: channel, NodeID: segment.NodeID, Growing: true})
+		}
+
+		for _, norm := range entries {
+			if norm.NodeID != paramtable.GetNodeID() {
+				norm.Reason = fmt.Sprintf("served by node %d", norm.NodeID)
+			} else if err := node.sampleVectorNorm(ctx, collectionID, field, sampleSize, norm); err != nil {
+				log.Warn("failed to sample vectors of segment", zap.Int64("segmentID", norm.SegmentID), zap.Error(err))
+				norm.Reason = fmt.Sprintf("failed to sample vectors: %s", err.Error())
+			}
+			norms = append(norms, norm)
+		}
+	}
+	return norms, nil
+}
+
+// sampleVectorNorm retrieves at most sampleSize vectors of local segment and fills the norm statistics.
+func (node *QueryNode) sampleVectorNorm(ctx context.Context, collectionID int64, field *schemapb.FieldSchema, sampleSize int64, norm *SegmentVectorNorm) error {
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}},
+				Limit:      sampleSize,
+			},
+		},
+		OutputFieldIds: []int64{field.GetFieldID()},
+	}
+	serializedPlan, err := proto.Marshal(plan)
+	if err != nil {
+		return err
+	}
+
+	scope := querypb.DataScope_Historical
+	if norm.Growing {
+		scope = querypb.DataScope_Streaming
+	}
+	resp, err := node.QuerySegments(ctx, &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       collectionID,
+			SerializedExprPlan: serializedPlan,
+			OutputFieldsId:     plan.GetOutputFieldIds(),
+			MvccTimestamp:      typeutil.MaxTimestamp,
+			Limit:              sampleSize,
+		},
+		DmlChannels:     []string{norm.Channel},
+		SegmentIDs:      []int64{norm.SegmentID},
+		FromShardLeader: true,
+		Scope:           scope,
+	})
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		return err
+	}
+
+	fieldData, ok := lo.Find(resp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldId() == field.GetFieldID()
+	})
+	if !ok {
+		return nil
+	}
+	vectors, err := rawVectors(fieldData)
+	if err != nil {
+		return err
+	}
+	values := make([]float64, 0, len(vectors))
+	for _, vector := range vectors {
+		values = append(values, vectorNorm(vector, field.GetDataType()))
+	}
+	norm.SampledRows = int64(len(values))
+	norm.MeanNorm, norm.NormVariance = meanAndVariance(values)
+	return nil
+}
+
+// vectorNorm returns the L2 norm of stored vector bytes, float or float16 vector only.
+func vectorNorm(vector []byte, dataType schemapb.DataType) float64 {
+	var sum float64
+	if dataType == schemapb.DataType_Float16Vector {
+		for i := 0; i+1 < len(vector); i += 2 {
+			v := float64(float16ToFloat32(binary.LittleEndian.Uint16(vector[i:])))
+			sum += v * v
+		}
+		return math.Sqrt(sum)
+	}
+	for i := 0; i+3 < len(vector); i += 4 {
+		v := float64(math.Float32frombits(binary.LittleEndian.Uint32(vector[i:])))
+		sum += v * v
+	}
+	return math.Sqrt(sum)
+}
+
+// float16ToFloat32 converts IEEE 754 half precision bits to float32.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+	switch exp {
+	case 0:
+		// zero or subnormal
+		v := float32(frac) / (1 << 24)
+		if sign != 0 {
+			return -v
+		}
+		return v
+	case 0x1f:
+		// inf or nan
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+	}
+}
+
+func meanAndVariance(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values))
+}
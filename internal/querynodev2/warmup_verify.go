@@ -0,0 +1,296 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynodev2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	canaryTopK = 10
+	// search params of common index types, unknown ones are ignored by index
+	canarySearchParams = `{"nprobe": 16, "ef": 64, "search_list": 64}`
+	// relative tolerance of canary score, quantized indexes may not find the exact vector
+	canaryScoreTolerance = 1e-3
+)
+
+// SegmentVerification is the warmup and verification result of sealed segment.
+type SegmentVerification struct {
+	SegmentID int64
+	Passed    bool
+	// Reason tells why the verification failed or skipped, empty if passed
+	Reason string
+	// CanaryPK is the primary key of the stored vector searched with, nil if nothing searched
+	CanaryPK any
+	// Score of the canary hit, distances are negated as in search results
+	Score   float32
+	Latency time.Duration
+}
+
+// WarmupAndVerify warms up the index of sealed segments and verifies them before serving traffic.
+// For each segment, a stored vector is searched in the segment only,
+// which faults the index into cache, and the segment shall find the vector itself with the best score.
+// Verification failures are reported per segment instead of failing the whole call.
+func (node *QueryNode) WarmupAndVerify(ctx context.Context, collectionID int64, segmentIDs []int64) ([]*SegmentVerification, error) {
+	log := log.Ctx(ctx).With(zap.Int64("collectionID", collectionID))
+
+	if err := node.lifetime.Add(merr.IsHealthy); err != nil {
+		return nil, err
+	}
+	defer node.lifetime.Done()
+
+	collection := node.manager.Collection.Get(collectionID)
+	if collection == nil {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionID)
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(collection.Schema())
+	if err != nil {
+		return nil, err
+	}
+	vecFieldIDs := funcutil.GetVecFieldIDs(collection.Schema())
+	if len(vecFieldIDs) == 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("collection %d has no vector field", collectionID)
+	}
+	vecField, _ := lo.Find(collection.Schema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == vecFieldIDs[0]
+	})
+
+	verifications := make([]*SegmentVerification, 0, len(segmentIDs))
+	for _, segmentID := range segmentIDs {
+		start := time.Now()
+		verification := node.verifySegment(ctx, collectionID, pkField, vecField, segmentID)
+		verification.Latency = time.Since(start)
+		if !verification.Passed {
+			log.Warn("segment failed warmup verification",
+				zap.Int64("segmentID", segmentID),
+				zap.String("reason", verification.Reason),
+			)
+		}
+		verifications = append(verifications, verification)
+	}
+	return verifications, nil
+}
+
+func (node *QueryNode) verifySegment(ctx context.Context, collectionID int64, pkField, vecField *schemapb.FieldSchema, segmentID int64) *SegmentVerification {
+	verification := &SegmentVerification{SegmentID: segmentID}
+	fail := func(format string, args ...any) *SegmentVerification {
+		verification.Reason = fmt.Sprintf(format, args...)
+		return verification
+	}
+
+	segment := node.manager.Segment.GetSealed(segmentID)
+	if segment == nil || segment.Collection() != collectionID {
+		return fail("sealed segment not loaded")
+	}
+	index := segment.GetIndex(vecField.GetFieldID())
+	if index == nil {
+		return fail("index of field %d not loaded", vecField.GetFieldID())
+	}
+	metricType, err := funcutil.GetAttrByKeyFromRepeatedKV(common.MetricTypeKey, index.IndexInfo.GetIndexParams())
+	if err != nil {
+		return fail("metric type of index not found")
+	}
+
+	pk, vector, err := node.retrieveCanary(ctx, collectionID, segment, pkField, vecField)
+	if err != nil {
+		return fail("failed to retrieve canary vector: %s", err.Error())
+	}
+	if pk == nil {
+		verification.Passed = true
+		verification.Reason = "skipped, no row in segment"
+		return verification
+	}
+	verification.CanaryPK = pk
+
+	data, err := node.searchCanary(ctx, collectionID, segment, vecField, metricType, vector)
+	if err != nil {
+		return fail("failed to search canary vector: %s", err.Error())
+	}
+	verification.Score, verification.Reason = checkCanaryHit(data, pk)
+	verification.Passed = verification.Reason == ""
+	return verification
+}
+
+// retrieveCanary retrieves a stored vector of segment, nil pk returned if segment is empty.
+func (node *QueryNode) retrieveCanary(ctx context.Context, collectionID int64, segment segments.Segment, pkField, vecField *schemapb.FieldSchema) (any, []byte, error) {
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}},
+				Limit:      1,
+			},
+		},
+		OutputFieldIds: []int64{pkField.GetFieldID(), vecField.GetFieldID()},
+	}
+	serializedPlan, err := proto.Marshal(plan)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := node.QuerySegments(ctx, &querypb.QueryRequest{
+		Req: &internalpb.RetrieveRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       collectionID,
+			SerializedExprPlan: serializedPlan,
+			OutputFieldsId:     plan.GetOutputFieldIds(),
+			MvccTimestamp:      typeutil.MaxTimestamp,
+			Limit:              1,
+		},
+		DmlChannels:     []string{segment.Shard()},
+		SegmentIDs:      []int64{segment.ID()},
+		FromShardLeader: true,
+		Scope:           querypb.DataScope_Historical,
+	})
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if typeutil.GetSizeOfIDs(resp.GetIds()) == 0 {
+		return nil, nil, nil
+	}
+
+	fieldData, ok := lo.Find(resp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldId() == vecField.GetFieldID()
+	})
+	if !ok {
+		return nil, nil, merr.WrapErrFieldNotFound(vecField.GetFieldID())
+	}
+	vectors, err := rawVectors(fieldData)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(vectors) == 0 {
+		return nil, nil, merr.WrapErrServiceInternal("no vector retrieved with the row")
+	}
+	return typeutil.GetPK(resp.GetIds(), 0), vectors[0], nil
+}
+
+// searchCanary searches the vector in the segment only.
+func (node *QueryNode) searchCanary(ctx context.Context, collectionID int64, segment segments.Segment, vecField *schemapb.FieldSchema, metricType string, vector []byte) (*schemapb.SearchResultData, error) {
+	var (
+		vectorType      planpb.VectorType
+		placeholderType commonpb.PlaceholderType
+	)
+	switch vecField.GetDataType() {
+	case schemapb.DataType_BinaryVector:
+		vectorType, placeholderType = planpb.VectorType_BinaryVector, commonpb.PlaceholderType_BinaryVector
+	case schemapb.DataType_Float16Vector:
+		vectorType, placeholderType = planpb.VectorType_Float16Vector, commonpb.PlaceholderType_Float16Vector
+	default:
+		vectorType, placeholderType = planpb.VectorType_FloatVector, commonpb.PlaceholderType_FloatVector
+	}
+	plan := &planpb.PlanNode{
+		Node: &planpb.PlanNode_VectorAnns{
+			VectorAnns: &planpb.VectorANNS{
+				VectorType: vectorType,
+				FieldId:    vecField.GetFieldID(),
+				QueryInfo: &planpb.QueryInfo{
+					Topk:         canaryTopK,
+					MetricType:   metricType,
+					SearchParams: canarySearchParams,
+					RoundDecimal: -1,
+				},
+				PlaceholderTag: "$0",
+			},
+		},
+	}
+	serializedPlan, err := proto.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    "$0",
+			Type:   placeholderType,
+			Values: [][]byte{vector},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := node.SearchSegments(ctx, &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{
+			Base:               &commonpb.MsgBase{},
+			CollectionID:       collectionID,
+			PlaceholderGroup:   placeholderGroup,
+			SerializedExprPlan: serializedPlan,
+			Nq:                 1,
+			Topk:               canaryTopK,
+			MetricType:         metricType,
+		},
+		DmlChannels:     []string{segment.Shard()},
+		SegmentIDs:      []int64{segment.ID()},
+		FromShardLeader: true,
+		Scope:           querypb.DataScope_Historical,
+	})
+	if err == nil {
+		err = merr.Error(resp.GetStatus())
+	}
+	if err != nil {
+		return nil, err
+	}
+	data := &schemapb.SearchResultData{}
+	if resp.GetSlicedBlob() != nil {
+		if err := proto.Unmarshal(resp.GetSlicedBlob(), data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// checkCanaryHit checks the canary pk is found with the best score within tolerance,
+// returns the score of canary hit and the reason if check failed.
+func checkCanaryHit(data *schemapb.SearchResultData, pk any) (float32, string) {
+	scores := data.GetScores()
+	if len(scores) == 0 {
+		return 0, "nothing found by canary search"
+	}
+	for i := range scores {
+		if typeutil.GetPK(data.GetIds(), int64(i)) != pk {
+			continue
+		}
+		best := scores[0]
+		tolerance := canaryScoreTolerance * math.Max(1, math.Abs(float64(best)))
+		if float64(best)-float64(scores[i]) > tolerance {
+			return scores[i], fmt.Sprintf("canary found with score %f, worse than the best score %f", scores[i], best)
+		}
+		return scores[i], ""
+	}
+	return 0, fmt.Sprintf("canary not found in top %d hits", len(scores))
+}
@@ -0,0 +1,99 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// IteratorToken is the cursor of search iterator, the position after the last hit of previous page.
+// Hits are ordered by score descending and pk ascending for tied scores on every replica,
+// so any replica could resume from the token.
+type IteratorToken struct {
+	MetricType string `json:"metric_type"`
+	// score of the last hit, distances are negated as in search results of query nodes
+	LastScore float32 `json:"last_score"`
+	LastIntPK int64   `json:"last_int_pk,omitempty"`
+	LastStrPK string  `json:"last_str_pk,omitempty"`
+	IsStrPK   bool    `json:"is_str_pk,omitempty"`
+	// number of hits with the last score returned by previous pages
+	TiedCount int64 `json:"tied_count"`
+}
+
+// ParseIteratorToken decodes the iterator token, nil if it's the first page.
+func ParseIteratorToken(bs []byte) (*IteratorToken, error) {
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	token := &IteratorToken{}
+	if err := json.Unmarshal(bs, token); err != nil {
+		return nil, merr.WrapErrParameterInvalid("valid iterator token", "malformed iterator token", err.Error())
+	}
+	return token, nil
+}
+
+// LastPK returns the pk of the last hit.
+func (t *IteratorToken) LastPK() any {
+	if t.IsStrPK {
+		return t.LastStrPK
+	}
+	return t.LastIntPK
+}
+
+// Before returns whether the hit is at or before the token, i.e. returned by previous pages.
+func (t *IteratorToken) Before(score float32, pk any) bool {
+	if score != t.LastScore {
+		return score > t.LastScore
+	}
+	return pk == t.LastPK() || typeutil.ComparePK(pk, t.LastPK())
+}
+
+// NextIteratorToken returns the token to resume after the last hit of the page,
+// nil if nothing found, which means the iteration ends.
+// The scores must be ordered descending, i.e. distances negated as in search results of query nodes.
+func NextIteratorToken(ids *schemapb.IDs, scores []float32, prev *IteratorToken, metricType string) ([]byte, error) {
+	if len(scores) == 0 {
+		return nil, nil
+	}
+
+	last := len(scores) - 1
+	token := &IteratorToken{
+		MetricType: metricType,
+		LastScore:  scores[last],
+	}
+	switch pk := typeutil.GetPK(ids, int64(last)).(type) {
+	case int64:
+		token.LastIntPK = pk
+	case string:
+		token.LastStrPK = pk
+		token.IsStrPK = true
+	default:
+		return nil, merr.WrapErrServiceInternal(fmt.Sprintf("unexpected pk type %T", pk))
+	}
+	for i := last; i >= 0 && scores[i] == token.LastScore; i-- {
+		token.TiedCount++
+	}
+	if prev != nil && prev.LastScore == token.LastScore {
+		token.TiedCount += prev.TiedCount
+	}
+	return json.Marshal(token)
+}
@@ -0,0 +1,97 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// SplitSearchResultDataByPartition splits the hits of search result data by the partition id pseudo field,
+// hits of each query keep their order in each partition.
+func SplitSearchResultDataByPartition(data *schemapb.SearchResultData) (map[int64]*schemapb.SearchResultData, error) {
+	partitionField, ok := lo.Find(data.GetFieldsData(), func(field *schemapb.FieldData) bool {
+		return field.GetFieldId() == common.PartitionIDField
+	})
+	if !ok {
+		return nil, merr.WrapErrServiceInternal("partition id of search hits not found")
+	}
+	partitionIDs := partitionField.GetScalars().GetLongData().GetData()
+	if len(partitionIDs) != typeutil.GetSizeOfIDs(data.GetIds()) {
+		return nil, merr.WrapErrServiceInternal("partition ids mismatch search hits")
+	}
+
+	result := make(map[int64]*schemapb.SearchResultData)
+	var offset int64
+	for i, topk := range data.GetTopks() {
+		for j := offset; j < offset+topk; j++ {
+			partitionData, ok := result[partitionIDs[j]]
+			if !ok {
+				partitionData = &schemapb.SearchResultData{
+					NumQueries: data.GetNumQueries(),
+					TopK:       data.GetTopK(),
+					Ids:        &schemapb.IDs{},
+					FieldsData: make([]*schemapb.FieldData, len(data.GetFieldsData())),
+					Topks:      make([]int64, len(data.GetTopks())),
+				}
+				result[partitionIDs[j]] = partitionData
+			}
+			typeutil.AppendPKs(partitionData.Ids, typeutil.GetPK(data.GetIds(), j))
+			typeutil.AppendFieldData(partitionData.FieldsData, data.GetFieldsData(), j)
+			partitionData.Scores = append(partitionData.Scores, data.GetScores()[j])
+			partitionData.Topks[i]++
+		}
+		offset += topk
+	}
+	return result, nil
+}
+
+// ConcatPartitionSearchResultData concatenates the hits of partitions query by query,
+// hits of each query are grouped by partition in the given order. Partitions without hit could be absent.
+func ConcatPartitionSearchResultData(partitionIDs []int64, datas map[int64]*schemapb.SearchResultData, nq int64, topk int64) *schemapb.SearchResultData {
+	result := &schemapb.SearchResultData{
+		NumQueries: nq,
+		TopK:       topk,
+		Ids:        &schemapb.IDs{},
+		Topks:      make([]int64, nq),
+	}
+	offsets := make(map[int64]int64, len(datas))
+	for i := int64(0); i < nq; i++ {
+		for _, partitionID := range partitionIDs {
+			data, ok := datas[partitionID]
+			if !ok || i >= int64(len(data.GetTopks())) {
+				continue
+			}
+			if result.FieldsData == nil {
+				result.FieldsData = make([]*schemapb.FieldData, len(data.GetFieldsData()))
+			}
+			offset := offsets[partitionID]
+			for j := offset; j < offset+data.GetTopks()[i]; j++ {
+				typeutil.AppendPKs(result.Ids, typeutil.GetPK(data.GetIds(), j))
+				typeutil.AppendFieldData(result.FieldsData, data.GetFieldsData(), j)
+				result.Scores = append(result.Scores, data.GetScores()[j])
+			}
+			result.Topks[i] += data.GetTopks()[i]
+			offsets[partitionID] = offset + data.GetTopks()[i]
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestPartitionSearchResultData(t *testing.T) {
+	data := &schemapb.SearchResultData{
+		NumQueries: 2,
+		TopK:       3,
+		Scores:     []float32{0.9, 0.8, 0.7, 0.6, 0.5},
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3, 4, 5}}}},
+		Topks:      []int64{3, 2},
+		FieldsData: []*schemapb.FieldData{{
+			Type:      schemapb.DataType_Int64,
+			FieldName: common.PartitionIDFieldName,
+			FieldId:   common.PartitionIDField,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{10, 20, 10, 20, 20}}},
+			}},
+		}},
+	}
+
+	datas, err := SplitSearchResultDataByPartition(data)
+	require.NoError(t, err)
+	require.Len(t, datas, 2)
+	assert.Equal(t, []int64{1, 3}, datas[10].GetIds().GetIntId().GetData())
+	assert.Equal(t, []float32{0.9, 0.7}, datas[10].GetScores())
+	assert.Equal(t, []int64{2, 0}, datas[10].GetTopks())
+	assert.Equal(t, []int64{2, 4, 5}, datas[20].GetIds().GetIntId().GetData())
+	assert.Equal(t, []int64{1, 2}, datas[20].GetTopks())
+	assert.Equal(t, []int64{20, 20, 20}, datas[20].GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+	// concat in the given partition order
+	concat := ConcatPartitionSearchResultData([]int64{20, 10, 30}, datas, 2, 3)
+	assert.Equal(t, []int64{2, 1, 3, 4, 5}, concat.GetIds().GetIntId().GetData())
+	assert.Equal(t, []float32{0.8, 0.9, 0.7, 0.6, 0.5}, concat.GetScores())
+	assert.Equal(t, []int64{3, 2}, concat.GetTopks())
+	assert.Equal(t, []int64{20, 10, 10, 20, 20}, concat.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+	// partition ids not returned
+	data.FieldsData = nil
+	_, err = SplitSearchResultDataByPartition(data)
+	assert.ErrorIs(t, err, merr.ErrServiceInternal)
+}
@@ -0,0 +1,304 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// CheckSortKeys validates the sort keys of a retrieve request,
+// all keys must be sortable fields of the collection present in output fields.
+func CheckSortKeys(schema *schemapb.CollectionSchema, outputFieldIDs []int64, sortKeys []*internalpb.SortKey) error {
+	for _, key := range sortKeys {
+		if !lo.Contains(outputFieldIDs, key.GetFieldID()) {
+			return merr.WrapErrParameterInvalidMsg("sort key field %d not in output fields", key.GetFieldID())
+		}
+		field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+			return field.GetFieldID() == key.GetFieldID()
+		})
+		if !ok {
+			return merr.WrapErrFieldNotFound(key.GetFieldID())
+		}
+		if !isSortableType(field.GetDataType()) {
+			return merr.WrapErrParameterInvalidMsg("sort key field %s of type %s is not sortable", field.GetName(), field.GetDataType().String())
+		}
+	}
+	return nil
+}
+
+func isSortableType(dataType schemapb.DataType) bool {
+	switch dataType {
+	case schemapb.DataType_Bool,
+		schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64,
+		schemapb.DataType_Float, schemapb.DataType_Double,
+		schemapb.DataType_VarChar,
+		schemapb.DataType_JSON:
+		return true
+	}
+	return false
+}
+
+// SortRetrieveResults orders the rows of the retrieve result by the sort keys,
+// the sort is stable so rows equal on all keys keep the order of the reduce.
+func SortRetrieveResults(result *internalpb.RetrieveResults, sortKeys []*internalpb.SortKey) error {
+	if len(sortKeys) == 0 {
+		return nil
+	}
+	order, err := sortOrder(result.GetFieldsData(), typeutil.GetSizeOfIDs(result.GetIds()), sortKeys)
+	if err != nil || order == nil {
+		return err
+	}
+
+	ids := &schemapb.IDs{}
+	fieldsData := make([]*schemapb.FieldData, len(result.GetFieldsData()))
+	for _, idx := range order {
+		typeutil.AppendIDs(ids, result.GetIds(), idx)
+		typeutil.AppendFieldData(fieldsData, result.GetFieldsData(), int64(idx))
+	}
+	result.Ids = ids
+	result.FieldsData = fieldsData
+	return nil
+}
+
+// SortQueryResults orders the rows of the reduced query result by the sort keys,
+// used by proxy to restore the order lost when merging the results of shards.
+func SortQueryResults(result *milvuspb.QueryResults, sortKeys []*internalpb.SortKey) error {
+	if len(sortKeys) == 0 || len(result.GetFieldsData()) == 0 {
+		return nil
+	}
+	rows, err := funcutil.GetNumRowOfFieldData(result.GetFieldsData()[0])
+	if err != nil {
+		return err
+	}
+	order, err := sortOrder(result.GetFieldsData(), int(rows), sortKeys)
+	if err != nil || order == nil {
+		return err
+	}
+
+	fieldsData := make([]*schemapb.FieldData, len(result.GetFieldsData()))
+	for _, idx := range order {
+		typeutil.AppendFieldData(fieldsData, result.GetFieldsData(), int64(idx))
+	}
+	result.FieldsData = fieldsData
+	return nil
+}
+
+// sortOrder returns the row offsets in the order of the sort keys,
+// nil if there is nothing to sort.
+func sortOrder(fieldsData []*schemapb.FieldData, rows int, sortKeys []*internalpb.SortKey) ([]int, error) {
+	if rows <= 1 {
+		return nil, nil
+	}
+
+	columns := make([]*sortColumn, len(sortKeys))
+	for i, key := range sortKeys {
+		fieldData, ok := lo.Find(fieldsData, func(fieldData *schemapb.FieldData) bool {
+			return fieldData.GetFieldId() == key.GetFieldID()
+		})
+		if !ok {
+			return nil, merr.WrapErrParameterInvalidMsg("sort key field %d not in retrieve result", key.GetFieldID())
+		}
+		column, err := newSortColumn(fieldData, key)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = column
+	}
+
+	order := make([]int, rows)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		for _, column := range columns {
+			if c := column.compare(order[i], order[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return order, nil
+}
+
+// sortColumn compares the rows of one sort key column.
+type sortColumn struct {
+	key *internalpb.SortKey
+	// isNull reports whether the row has no value
+	isNull func(i int) bool
+	// cmp compares the values of two rows with value, in ascending order
+	cmp func(i, j int) int
+}
+
+func newSortColumn(fieldData *schemapb.FieldData, key *internalpb.SortKey) (*sortColumn, error) {
+	column := &sortColumn{
+		key:    key,
+		isNull: func(int) bool { return false },
+	}
+	scalars := fieldData.GetScalars()
+	switch fieldData.GetType() {
+	case schemapb.DataType_Bool:
+		data := scalars.GetBoolData().GetData()
+		column.cmp = func(i, j int) int {
+			return compareOrdered(lo.Ternary(data[i], 1, 0), lo.Ternary(data[j], 1, 0))
+		}
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		data := scalars.GetIntData().GetData()
+		column.cmp = func(i, j int) int { return compareOrdered(data[i], data[j]) }
+	case schemapb.DataType_Int64:
+		data := scalars.GetLongData().GetData()
+		column.cmp = func(i, j int) int { return compareOrdered(data[i], data[j]) }
+	case schemapb.DataType_Float:
+		data := scalars.GetFloatData().GetData()
+		column.cmp = func(i, j int) int { return compareOrdered(data[i], data[j]) }
+	case schemapb.DataType_Double:
+		data := scalars.GetDoubleData().GetData()
+		column.cmp = func(i, j int) int { return compareOrdered(data[i], data[j]) }
+	case schemapb.DataType_VarChar:
+		data := scalars.GetStringData().GetData()
+		column.cmp = func(i, j int) int { return compareOrdered(data[i], data[j]) }
+	case schemapb.DataType_JSON:
+		values := make([]any, len(scalars.GetJsonData().GetData()))
+		for i, data := range scalars.GetJsonData().GetData() {
+			value, err := decodeJSONSortValue(data)
+			if err != nil {
+				return nil, merr.WrapErrParameterInvalidMsg("sort key field %d has invalid json value: %s", key.GetFieldID(), err.Error())
+			}
+			values[i] = value
+		}
+		column.isNull = func(i int) bool { return values[i] == nil }
+		column.cmp = func(i, j int) int { return compareJSONValue(values[i], values[j]) }
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("sort key field %d of type %s is not sortable", key.GetFieldID(), fieldData.GetType().String())
+	}
+	return column, nil
+}
+
+// compare returns negative if row i goes before row j under the key,
+// rows without value are placed by the nulls first flag of the key regardless of direction.
+func (c *sortColumn) compare(i, j int) int {
+	iNull, jNull := c.isNull(i), c.isNull(j)
+	switch {
+	case iNull && jNull:
+		return 0
+	case iNull != jNull:
+		if iNull == c.key.GetNullsFirst() {
+			return -1
+		}
+		return 1
+	}
+	if c.key.GetDescending() {
+		return -c.cmp(i, j)
+	}
+	return c.cmp(i, j)
+}
+
+// decodeJSONSortValue decodes the json value of a row, nil for missing value or json null.
+func decodeJSONSortValue(data []byte) (any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// jsonTypeRank orders values of different json types: bool < number < string < array < object.
+func jsonTypeRank(value any) int {
+	switch value.(type) {
+	case bool:
+		return 0
+	case json.Number:
+		return 1
+	case string:
+		return 2
+	case []any:
+		return 3
+	}
+	return 4
+}
+
+// compareJSONValue compares two non null json values, values of the same type are compared by value,
+// arrays element by element, objects by their encoding.
+func compareJSONValue(a, b any) int {
+	if c := compareOrdered(jsonTypeRank(a), jsonTypeRank(b)); c != 0 {
+		return c
+	}
+	switch a := a.(type) {
+	case bool:
+		return compareOrdered(lo.Ternary(a, 1, 0), lo.Ternary(b.(bool), 1, 0))
+	case json.Number:
+		return compareJSONNumber(a, b.(json.Number))
+	case string:
+		return strings.Compare(a, b.(string))
+	case []any:
+		b := b.([]any)
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if a[i] == nil || b[i] == nil {
+				if c := compareOrdered(lo.Ternary(a[i] == nil, 0, 1), lo.Ternary(b[i] == nil, 0, 1)); c != 0 {
+					return c
+				}
+				continue
+			}
+			if c := compareJSONValue(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return compareOrdered(len(a), len(b))
+	}
+	// encoding/json sorts map keys, so equal objects have the same encoding
+	aBytes, _ := json.Marshal(a)
+	bBytes, _ := json.Marshal(b)
+	return bytes.Compare(aBytes, bBytes)
+}
+
+// compareJSONNumber compares as integers when both fit, as floats otherwise.
+func compareJSONNumber(a, b json.Number) int {
+	aInt, aErr := a.Int64()
+	bInt, bErr := b.Int64()
+	if aErr == nil && bErr == nil {
+		return compareOrdered(aInt, bInt)
+	}
+	aFloat, _ := a.Float64()
+	bFloat, _ := b.Float64()
+	return compareOrdered(aFloat, bFloat)
+}
+
+func compareOrdered[T int | int32 | int64 | float32 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,177 @@
+package typeutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	sortPkFieldID      = common.StartOfUserFieldID
+	sortInt64FieldID   = common.StartOfUserFieldID + 1
+	sortVarCharFieldID = common.StartOfUserFieldID + 2
+	sortJSONFieldID    = common.StartOfUserFieldID + 3
+)
+
+func genSortFieldsData(pks []int64, int64s []int64, strs []string, jsons [][]byte) []*schemapb.FieldData {
+	return []*schemapb.FieldData{
+		{
+			Type:    schemapb.DataType_Int64,
+			FieldId: sortPkFieldID,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: pks}},
+			}},
+		},
+		{
+			Type:    schemapb.DataType_Int64,
+			FieldId: sortInt64FieldID,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: int64s}},
+			}},
+		},
+		{
+			Type:    schemapb.DataType_VarChar,
+			FieldId: sortVarCharFieldID,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: strs}},
+			}},
+		},
+		{
+			Type:    schemapb.DataType_JSON,
+			FieldId: sortJSONFieldID,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: jsons}},
+			}},
+		},
+	}
+}
+
+func genSortRetrieveResults(jsons ...[]byte) *internalpb.RetrieveResults {
+	if len(jsons) == 0 {
+		jsons = [][]byte{[]byte(`1`), []byte(`null`), []byte(`2`), nil}
+	}
+	return &internalpb.RetrieveResults{
+		Ids: &schemapb.IDs{
+			IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{0, 1, 2, 3}}},
+		},
+		FieldsData: genSortFieldsData([]int64{0, 1, 2, 3}, []int64{2, 1, 2, 1}, []string{"a", "b", "c", "a"}, jsons),
+	}
+}
+
+func TestSortRetrieveResults(t *testing.T) {
+	t.Run("multi_keys", func(t *testing.T) {
+		result := genSortRetrieveResults()
+		err := SortRetrieveResults(result, []*internalpb.SortKey{
+			{FieldID: sortInt64FieldID},
+			{FieldID: sortVarCharFieldID, Descending: true},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 3, 2, 0}, result.GetIds().GetIntId().GetData())
+		assert.Equal(t, []int64{1, 3, 2, 0}, result.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+		assert.Equal(t, []string{"b", "a", "c", "a"}, result.GetFieldsData()[2].GetScalars().GetStringData().GetData())
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		result := genSortRetrieveResults()
+		err := SortRetrieveResults(result, []*internalpb.SortKey{{FieldID: sortInt64FieldID, Descending: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{0, 2, 1, 3}, result.GetIds().GetIntId().GetData())
+	})
+
+	t.Run("nulls_placement", func(t *testing.T) {
+		result := genSortRetrieveResults()
+		err := SortRetrieveResults(result, []*internalpb.SortKey{{FieldID: sortJSONFieldID, Descending: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 0, 1, 3}, result.GetIds().GetIntId().GetData())
+
+		result = genSortRetrieveResults()
+		err = SortRetrieveResults(result, []*internalpb.SortKey{{FieldID: sortJSONFieldID, Descending: true, NullsFirst: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 3, 2, 0}, result.GetIds().GetIntId().GetData())
+	})
+
+	t.Run("nulls_placement_per_key", func(t *testing.T) {
+		// nulls of the second key are placed by its own flag within the ties of the first key
+		result := genSortRetrieveResults([]byte(`null`), []byte(`1`), []byte(`2`), nil)
+		err := SortRetrieveResults(result, []*internalpb.SortKey{
+			{FieldID: sortInt64FieldID, NullsFirst: true},
+			{FieldID: sortJSONFieldID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 3, 2, 0}, result.GetIds().GetIntId().GetData())
+
+		result = genSortRetrieveResults([]byte(`null`), []byte(`1`), []byte(`2`), nil)
+		err = SortRetrieveResults(result, []*internalpb.SortKey{
+			{FieldID: sortInt64FieldID},
+			{FieldID: sortJSONFieldID, NullsFirst: true},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 1, 0, 2}, result.GetIds().GetIntId().GetData())
+	})
+
+	t.Run("json_typed_compare", func(t *testing.T) {
+		// bytes order would put 10 before 9 and "b" before 1.5
+		result := genSortRetrieveResults([]byte(`10`), []byte(`"b"`), []byte(`9`), []byte(`1.5`))
+		err := SortRetrieveResults(result, []*internalpb.SortKey{{FieldID: sortJSONFieldID}})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 2, 0, 1}, result.GetIds().GetIntId().GetData())
+	})
+
+	t.Run("invalid_json", func(t *testing.T) {
+		result := genSortRetrieveResults([]byte(`{`), []byte(`1`), []byte(`2`), nil)
+		err := SortRetrieveResults(result, []*internalpb.SortKey{{FieldID: sortJSONFieldID}})
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("unsortable_column", func(t *testing.T) {
+		result := genSortRetrieveResults()
+		result.FieldsData = append(result.FieldsData, &schemapb.FieldData{
+			Type:    schemapb.DataType_FloatVector,
+			FieldId: sortJSONFieldID + 1,
+			Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+				Dim:  1,
+				Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: []float32{1, 2, 3, 4}}},
+			}},
+		})
+		err := SortRetrieveResults(result, []*internalpb.SortKey{{FieldID: sortJSONFieldID + 1}})
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+}
+
+func TestSortQueryResults(t *testing.T) {
+	result := &milvuspb.QueryResults{
+		FieldsData: genSortFieldsData([]int64{0, 1, 2, 3}, []int64{2, 1, 2, 1}, []string{"a", "b", "c", "a"},
+			[][]byte{[]byte(`1`), []byte(`null`), []byte(`2`), nil}),
+	}
+	err := SortQueryResults(result, []*internalpb.SortKey{
+		{FieldID: sortInt64FieldID},
+		{FieldID: sortVarCharFieldID, Descending: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 2, 0}, result.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+}
+
+func TestCheckSortKeys(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: sortPkFieldID, Name: "pk", DataType: schemapb.DataType_Int64},
+			{FieldID: sortInt64FieldID, Name: "Int64Field", DataType: schemapb.DataType_Int64},
+			{FieldID: sortVarCharFieldID + 10, Name: "FloatVectorField", DataType: schemapb.DataType_FloatVector},
+		},
+	}
+	err := CheckSortKeys(schema, []int64{sortPkFieldID}, []*internalpb.SortKey{{FieldID: sortInt64FieldID}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	err = CheckSortKeys(schema, []int64{sortVarCharFieldID + 10}, []*internalpb.SortKey{{FieldID: sortVarCharFieldID + 10}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	err = CheckSortKeys(schema, []int64{sortPkFieldID, sortInt64FieldID}, []*internalpb.SortKey{{FieldID: sortInt64FieldID}, {FieldID: sortPkFieldID}})
+	assert.NoError(t, err)
+}
@@ -21,6 +21,7 @@ import "encoding/binary"
 // system field id:
 // 0: unique row id
 // 1: timestamp
+// 2: search score, pseudo field which is not stored
 // 100: first user field id
 // 101: second user field id
 // 102: ...
@@ -35,6 +36,21 @@ const (
 	// TimeStampField is the ID of the Timestamp field reserved by the system
 	TimeStampField = 1
 
+	// ScoreField is the ID of the search score pseudo field reserved by the system
+	ScoreField = 2
+
+	// SegmentIDField is the ID of the search hit segment id pseudo field reserved by the system
+	SegmentIDField = 3
+
+	// PartitionIDField is the ID of the search hit partition id pseudo field reserved by the system
+	PartitionIDField = 4
+
+	// InsertionTsField is the ID of the search hit insertion timestamp pseudo field reserved by the system
+	InsertionTsField = 5
+
+	// SegmentFlushTsField is the ID of the search hit segment flush timestamp pseudo field reserved by the system
+	SegmentFlushTsField = 6
+
 	// RowIDFieldName defines the name of the RowID field
 	RowIDFieldName = "RowID"
 
@@ -44,6 +60,21 @@ const (
 	// MetaFieldName is the field name of dynamic schema
 	MetaFieldName = "$meta"
 
+	// ScoreFieldName is the field name of the search score pseudo field
+	ScoreFieldName = "$score"
+
+	// SegmentIDFieldName is the field name of the search hit segment id pseudo field
+	SegmentIDFieldName = "$segment_id"
+
+	// PartitionIDFieldName is the field name of the search hit partition id pseudo field
+	PartitionIDFieldName = "$partition_id"
+
+	// InsertionTsFieldName is the field name of the search hit insertion timestamp pseudo field
+	InsertionTsFieldName = "$insertion_ts"
+
+	// SegmentFlushTsFieldName is the field name of the search hit segment flush timestamp pseudo field
+	SegmentFlushTsFieldName = "$segment_flush_ts"
+
 	// DefaultShardsNum defines the default number of shards when creating a collection
 	DefaultShardsNum = int32(1)
 
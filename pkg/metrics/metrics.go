@@ -64,6 +64,9 @@ const (
 	ReduceSegments = "segments"
 	ReduceShards   = "shards"
 
+	HedgePrimaryLabel = "primary"
+	HedgeLabel        = "hedge"
+
 	nodeIDLabelName          = "node_id"
 	statusLabelName          = "status"
 	indexTaskStatusLabelName = "index_task_status"
@@ -88,6 +91,7 @@ const (
 	lockSource               = "lock_source"
 	lockType                 = "lock_type"
 	lockOp                   = "lock_op"
+	hedgeWinnerLabelName     = "winner"
 )
 
 var (
@@ -288,6 +288,18 @@ var (
 		}, []string{
 			nodeIDLabelName,
 		})
+
+	// ProxyHedgedReadCount records the hedged reads by the replica winning the race, to tell the hedge win rate.
+	ProxyHedgedReadCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "hedged_read_count",
+			Help:      "count of hedged reads by the winner, primary, hedge or fail if both failed",
+		}, []string{
+			nodeIDLabelName,
+			hedgeWinnerLabelName,
+		})
 )
 
 // RegisterProxy registers Proxy metrics
@@ -331,6 +343,7 @@ func RegisterProxy(registry *prometheus.Registry) {
 
 	registry.MustRegister(ProxyWorkLoadScore)
 	registry.MustRegister(ProxyExecutingTotalNq)
+	registry.MustRegister(ProxyHedgedReadCount)
 }
 
 func CleanupCollectionMetrics(nodeID int64, collection string) {
@@ -442,6 +442,38 @@ var (
 		}, []string{
 			nodeIDLabelName,
 		})
+
+	QueryNodeStreamCompressionRatio = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "stream_compression_ratio",
+			Help:      "ratio of raw size to compressed size of streamed query results",
+			Buckets:   []float64{1, 1.5, 2, 3, 4, 6, 8, 16},
+		}, []string{
+			nodeIDLabelName,
+		})
+
+	QueryNodeTruncatedStreamCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "truncated_stream_count",
+			Help:      "number of query streams ended early since the streamed result exceeded the max bytes",
+		}, []string{
+			nodeIDLabelName,
+		})
+
+	QueryNodeEmptyBinlogGrowingSegmentCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "empty_binlog_growing_segment_count",
+			Help:      "count of unflushed segments with empty binlog while watching channel",
+		}, []string{
+			nodeIDLabelName,
+			collectionIDLabelName,
+		})
 )
 
 // RegisterQueryNode registers QueryNode metrics
@@ -483,6 +515,9 @@ func RegisterQueryNode(registry *prometheus.Registry) {
 	registry.MustRegister(QueryNodeDiskUsedSize)
 	registry.MustRegister(QueryNodeProcessCost)
 	registry.MustRegister(QueryNodeWaitProcessingMsgCount)
+	registry.MustRegister(QueryNodeStreamCompressionRatio)
+	registry.MustRegister(QueryNodeTruncatedStreamCount)
+	registry.MustRegister(QueryNodeEmptyBinlogGrowingSegmentCount)
 }
 
 func CleanupQueryNodeCollectionMetrics(nodeID int64, collectionID int64) {
@@ -871,6 +871,8 @@ type proxyConfig struct {
 	CostMetricsExpireTime        ParamItem `refreshable:"true"`
 	RetryTimesOnReplica          ParamItem `refreshable:"true"`
 	RetryTimesOnHealthCheck      ParamItem `refreshable:"true"`
+	HedgedReadMaxRate            ParamItem `refreshable:"true"`
+	HedgedReadDelay              ParamItem `refreshable:"true"`
 }
 
 func (p *proxyConfig) init(base *BaseTable) {
@@ -1131,6 +1133,22 @@ please adjust in embedded Milvus: false`,
 		Doc:          "set query node unavailable on proxy when heartbeat failures reach this limit",
 	}
 	p.RetryTimesOnHealthCheck.Init(base.mgr)
+
+	p.HedgedReadMaxRate = ParamItem{
+		Key:          "proxy.hedgedRead.maxRate",
+		Version:      "2.3.4",
+		DefaultValue: "10",
+		Doc:          "max hedged reads per second, searches requesting hedged read over the rate are sent to one replica as usual, <= 0 disables hedged read",
+	}
+	p.HedgedReadMaxRate.Init(base.mgr)
+
+	p.HedgedReadDelay = ParamItem{
+		Key:          "proxy.hedgedRead.delay",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc:          "in ms, the hedged read is sent to another replica only if the first one doesn't respond within the delay",
+	}
+	p.HedgedReadDelay.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -1563,6 +1581,93 @@ type queryNodeConfig struct {
 	CGOPoolSizeRatio ParamItem `refreshable:"false"`
 
 	EnableWorkerSQCostMetrics ParamItem `refreshable:"true"`
+
+	ReduceScoreEpsilon ParamItem `refreshable:"true"`
+
+	QueryHookCachePath ParamItem `refreshable:"false"`
+
+	PreferCachedWarmWindow ParamItem `refreshable:"true"`
+
+	// adaptive topK
+	AdaptiveTopKEnabled          ParamItem `refreshable:"true"`
+	AdaptiveTopKLatencyThreshold ParamItem `refreshable:"true"`
+	AdaptiveTopKWindow           ParamItem `refreshable:"true"`
+	AdaptiveTopKFloor            ParamItem `refreshable:"true"`
+
+	MaxReduceMemoryPerRequest ParamItem `refreshable:"true"`
+
+	// post-reduce reranker plugin
+	RerankerSoPath ParamItem `refreshable:"false"`
+	RerankerConfig ParamItem `refreshable:"false"`
+
+	// retry of query hook on transient errors
+	QueryHookRetryAttempts ParamItem `refreshable:"true"`
+	QueryHookRetryInterval ParamItem `refreshable:"true"`
+
+	WorkerSearchTimeout ParamItem `refreshable:"true"`
+
+	EnableVectorDump ParamItem `refreshable:"true"`
+
+	// nq batching of search
+	MaxNQPerBatch      ParamItem `refreshable:"true"`
+	NQBatchConcurrency ParamItem `refreshable:"true"`
+
+	// compression of streamed query results
+	StreamCompressionLevel   ParamItem `refreshable:"true"`
+	StreamCompressionMinSize ParamItem `refreshable:"true"`
+
+	MaxQueryTimeout ParamItem `refreshable:"true"`
+
+	LoadErrorHistorySize ParamItem `refreshable:"false"`
+
+	MaxSearchExcludedPKs ParamItem `refreshable:"true"`
+
+	EmptyGrowingBinlogPolicy ParamItem `refreshable:"true"`
+
+	DeleteApplyMode ParamItem `refreshable:"true"`
+
+	VectorNormSampleSize ParamItem `refreshable:"true"`
+
+	FilterBruteForceThreshold ParamItem `refreshable:"true"`
+
+	StreamMaxResultBytes ParamItem `refreshable:"true"`
+
+	ReduceAlgorithm          ParamItem `refreshable:"true"`
+	ReduceSortRatioThreshold ParamItem `refreshable:"true"`
+	ReduceSortMaxCandidates  ParamItem `refreshable:"true"`
+
+	LatencyHistogramWindow ParamItem `refreshable:"true"`
+
+	RefineMaxFactor ParamItem `refreshable:"true"`
+
+	IndexSimulationMaxRows ParamItem `refreshable:"true"`
+
+	ExactSearchMaxConcurrency ParamItem `refreshable:"true"`
+	ExactSearchMaxRows        ParamItem `refreshable:"true"`
+
+	FacetMaxBuckets         ParamItem `refreshable:"true"`
+	FacetMaxCandidateFactor ParamItem `refreshable:"true"`
+
+	SearchParamLogSize ParamItem `refreshable:"true"`
+
+	PerPartitionTopKMaxHits ParamItem `refreshable:"true"`
+
+	LoadDurationLogSize ParamItem `refreshable:"true"`
+
+	QueryTimeoutOverrideMax ParamItem `refreshable:"true"`
+
+	FilteredSearchMinRecallRatio  ParamItem `refreshable:"true"`
+	FilteredSearchFallbackMaxRows ParamItem `refreshable:"true"`
+
+	StreamResumablePageSize ParamItem `refreshable:"true"`
+	StreamCursorTTL         ParamItem `refreshable:"true"`
+
+	GrowingSegmentMaxPerChannel ParamItem `refreshable:"true"`
+
+	BatchLoadIndexMaxConcurrency ParamItem `refreshable:"true"`
+
+	GroundTruthCacheCapacity ParamItem `refreshable:"true"`
+	NonFiniteVectorPolicy    ParamItem `refreshable:"true"`
 }
 
 func (p *queryNodeConfig) init(base *BaseTable) {
@@ -1941,6 +2046,399 @@ Max read concurrency must greater than or equal to 1, and less than or equal to
 		Doc:          "whether use worker's cost to measure delegator's workload",
 	}
 	p.EnableWorkerSQCostMetrics.Init(base.mgr)
+
+	p.ReduceScoreEpsilon = ParamItem{
+		Key:          "queryNode.reduce.scoreEpsilon",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc: `tolerance when comparing scores during search reduce, scores within the tolerance are treated as tied and ordered by primary key.
+relative tolerance is used for L2, absolute tolerance is used for IP, COSINE and JACCARD, 0 means disabled`,
+	}
+	p.ReduceScoreEpsilon.Init(base.mgr)
+
+	p.QueryHookCachePath = ParamItem{
+		Key:          "queryNode.queryHookCachePath",
+		Version:      "2.3.4",
+		DefaultValue: "",
+		Doc:          "local file to persist the query hook decision cache across restarts, empty means disabled",
+	}
+	p.QueryHookCachePath.Init(base.mgr)
+
+	p.PreferCachedWarmWindow = ParamItem{
+		Key:          "queryNode.preferCached.warmWindow",
+		Version:      "2.3.4",
+		DefaultValue: "300",
+		Doc:          "seconds since last load or search within which a mmap segment is guessed resident in page cache, the page cache is not inspected",
+	}
+	p.PreferCachedWarmWindow.Init(base.mgr)
+
+	p.AdaptiveTopKEnabled = ParamItem{
+		Key:          "queryNode.adaptiveTopK.enabled",
+		Version:      "2.3.4",
+		DefaultValue: "false",
+		Doc:          "whether to lower effective topK of collections whose search latency keeps exceeding the threshold",
+	}
+	p.AdaptiveTopKEnabled.Init(base.mgr)
+
+	p.AdaptiveTopKLatencyThreshold = ParamItem{
+		Key:          "queryNode.adaptiveTopK.latencyThreshold",
+		Version:      "2.3.4",
+		DefaultValue: "1000",
+		Doc:          "search latency slo of adaptive topK in milliseconds",
+	}
+	p.AdaptiveTopKLatencyThreshold.Init(base.mgr)
+
+	p.AdaptiveTopKWindow = ParamItem{
+		Key:          "queryNode.adaptiveTopK.window",
+		Version:      "2.3.4",
+		DefaultValue: "10",
+		Doc:          "seconds of the observation window, topK cap is adjusted at most once per window",
+	}
+	p.AdaptiveTopKWindow.Init(base.mgr)
+
+	p.AdaptiveTopKFloor = ParamItem{
+		Key:          "queryNode.adaptiveTopK.floor",
+		Version:      "2.3.4",
+		DefaultValue: "10",
+		Doc:          "effective topK will never be capped below this value",
+	}
+	p.AdaptiveTopKFloor.Init(base.mgr)
+
+	p.MaxReduceMemoryPerRequest = ParamItem{
+		Key:          "queryNode.reduce.maxMemoryPerRequest",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc:          "max memory in MB a single request could use while reducing results on shard leader, 0 means unlimited",
+	}
+	p.MaxReduceMemoryPerRequest.Init(base.mgr)
+
+	p.RerankerSoPath = ParamItem{
+		Key:          "queryNode.reranker.soPath",
+		Version:      "2.3.4",
+		DefaultValue: "",
+		Doc:          "path of the reranker plugin invoked after search results reduced, empty means disabled",
+	}
+	p.RerankerSoPath.Init(base.mgr)
+
+	p.RerankerConfig = ParamItem{
+		Key:          "queryNode.reranker.config",
+		Version:      "2.3.4",
+		DefaultValue: "",
+		Doc:          "config passed to the reranker plugin on init",
+	}
+	p.RerankerConfig.Init(base.mgr)
+
+	p.QueryHookRetryAttempts = ParamItem{
+		Key:          "queryNode.queryHook.retryAttempts",
+		Version:      "2.3.4",
+		DefaultValue: "3",
+		Doc:          "max attempts of query hook on transient errors, search params are not optimized after all attempts failed",
+	}
+	p.QueryHookRetryAttempts.Init(base.mgr)
+
+	p.QueryHookRetryInterval = ParamItem{
+		Key:          "queryNode.queryHook.retryInterval",
+		Version:      "2.3.4",
+		DefaultValue: "10",
+		Doc:          "initial backoff in milliseconds between query hook attempts, doubled after each attempt",
+	}
+	p.QueryHookRetryInterval.Init(base.mgr)
+
+	p.WorkerSearchTimeout = ParamItem{
+		Key:          "queryNode.delegator.workerSearchTimeout",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc:          "timeout in milliseconds of each worker the delegator fans search out to, results of timed out workers are skipped and the search is marked as partial, 0 means disabled",
+	}
+	p.WorkerSearchTimeout.Init(base.mgr)
+
+	p.EnableVectorDump = ParamItem{
+		Key:          "queryNode.debug.enableVectorDump",
+		Version:      "2.3.4",
+		DefaultValue: "false",
+		Doc:          "whether raw vectors of entities could be dumped for debugging, it exposes raw embeddings so keep it disabled unless investigating",
+	}
+	p.EnableVectorDump.Init(base.mgr)
+
+	p.MaxNQPerBatch = ParamItem{
+		Key:          "queryNode.search.maxNQPerBatch",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc:          "search with larger nq is split into batches of at most this nq on shard leader, results are concatenated in nq order, 0 means disabled",
+	}
+	p.MaxNQPerBatch.Init(base.mgr)
+
+	p.NQBatchConcurrency = ParamItem{
+		Key:          "queryNode.search.nqBatchConcurrency",
+		Version:      "2.3.4",
+		DefaultValue: "1",
+		Doc:          "max number of nq batches of a search executed concurrently, 1 means sequentially",
+	}
+	p.NQBatchConcurrency.Init(base.mgr)
+
+	p.StreamCompressionLevel = ParamItem{
+		Key:          "queryNode.stream.compressionLevel",
+		Version:      "2.3.4",
+		DefaultValue: "1",
+		Doc:          "zstd level to compress streamed query results if requested, 1 (fastest) to 4 (best), higher level costs more cpu",
+	}
+	p.StreamCompressionLevel.Init(base.mgr)
+
+	p.StreamCompressionMinSize = ParamItem{
+		Key:          "queryNode.stream.compressionMinSize",
+		Version:      "2.3.4",
+		DefaultValue: "4096",
+		Doc:          "streamed query results smaller than this size in bytes are sent uncompressed",
+	}
+	p.StreamCompressionMinSize.Init(base.mgr)
+
+	p.MaxQueryTimeout = ParamItem{
+		Key:          "queryNode.maxQueryTimeout",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc:          "max timeout in milliseconds of search and query on shard leader, the deadline of client is capped by it, 0 means not capped",
+	}
+	p.MaxQueryTimeout.Init(base.mgr)
+
+	p.LoadErrorHistorySize = ParamItem{
+		Key:          "queryNode.loadErrorHistorySize",
+		Version:      "2.3.4",
+		DefaultValue: "100",
+		Doc:          "number of recent segment load failures kept for inspection",
+	}
+	p.LoadErrorHistorySize.Init(base.mgr)
+
+	p.MaxSearchExcludedPKs = ParamItem{
+		Key:          "queryNode.maxSearchExcludedPKs",
+		Version:      "2.3.4",
+		DefaultValue: "16384",
+		Doc:          "max number of primary keys excluded from a search request",
+	}
+	p.MaxSearchExcludedPKs.Init(base.mgr)
+
+	p.EmptyGrowingBinlogPolicy = ParamItem{
+		Key:          "queryNode.emptyGrowingBinlogPolicy",
+		Version:      "2.3.4",
+		DefaultValue: "skip",
+		Doc: `behavior when the binlog of unflushed segment is empty while watching channel,
+skip: skip loading the segment with a warning, skipWithMetric: skip with a warning and count it by metric, error: fail the watch request`,
+	}
+	p.EmptyGrowingBinlogPolicy.Init(base.mgr)
+
+	p.DeleteApplyMode = ParamItem{
+		Key:          "queryNode.deleteApplyMode",
+		Version:      "2.3.4",
+		DefaultValue: "eager",
+		Doc: `how the delta logs of segment are applied, could be overridden per collection,
+eager: apply while loading the segment, lazy: keep them in memory and apply on the first search or query of segment,
+lazy mode shortens the segment loading but the first request on each segment pays the latency of applying deletes`,
+	}
+	p.DeleteApplyMode.Init(base.mgr)
+
+	p.VectorNormSampleSize = ParamItem{
+		Key:          "queryNode.vectorNormSampleSize",
+		Version:      "2.3.4",
+		DefaultValue: "1000",
+		Doc:          "max number of vectors sampled per segment to compute the vector norm statistics",
+	}
+	p.VectorNormSampleSize.Init(base.mgr)
+
+	p.FilterBruteForceThreshold = ParamItem{
+		Key:          "queryNode.filterBruteForceThreshold",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc: `filtered search brute forces over the matched rows instead of ANN search if the estimated number of rows
+matching the filter is not greater than the threshold, 0 to disable`,
+	}
+	p.FilterBruteForceThreshold.Init(base.mgr)
+
+	p.StreamMaxResultBytes = ParamItem{
+		Key:          "queryNode.stream.maxResultBytes",
+		Version:      "2.3.4",
+		DefaultValue: "1073741824",
+		Doc:          "cap of the max streamed result bytes specified by client, 0 means not capped",
+	}
+	p.StreamMaxResultBytes.Init(base.mgr)
+
+	p.ReduceAlgorithm = ParamItem{
+		Key:          "queryNode.reduce.algorithm",
+		Version:      "2.3.4",
+		DefaultValue: "auto",
+		Doc: `algorithm to select topK among results of segments during search reduce, heap, sort or auto.
+auto chooses sort if the ratio of topK to candidates reaches queryNode.reduce.sortRatioThreshold, heap otherwise`,
+	}
+	p.ReduceAlgorithm.Init(base.mgr)
+
+	p.ReduceSortRatioThreshold = ParamItem{
+		Key:          "queryNode.reduce.sortRatioThreshold",
+		Version:      "2.3.4",
+		DefaultValue: "0.5",
+		Doc:          "min ratio of topK to candidates of a query to reduce by full sort when the algorithm is auto",
+	}
+	p.ReduceSortRatioThreshold.Init(base.mgr)
+
+	p.ReduceSortMaxCandidates = ParamItem{
+		Key:          "queryNode.reduce.sortMaxCandidates",
+		Version:      "2.3.4",
+		DefaultValue: "65536",
+		Doc:          "max candidates of a query to reduce by full sort when the algorithm is auto, to bound the memory of sorting",
+	}
+	p.ReduceSortMaxCandidates.Init(base.mgr)
+
+	p.LatencyHistogramWindow = ParamItem{
+		Key:          "queryNode.latencyHistogram.window",
+		Version:      "2.3.4",
+		DefaultValue: "60",
+		Doc:          "seconds of the sliding window of search latency percentiles per collection",
+	}
+	p.LatencyHistogramWindow.Init(base.mgr)
+
+	p.RefineMaxFactor = ParamItem{
+		Key:          "queryNode.refine.maxFactor",
+		Version:      "2.3.4",
+		DefaultValue: "16",
+		Doc:          "max refine factor of search, candidates of topK * refine factor are reranked with raw vectors",
+	}
+	p.RefineMaxFactor.Init(base.mgr)
+
+	p.IndexSimulationMaxRows = ParamItem{
+		Key:          "queryNode.indexSimulation.maxRows",
+		Version:      "2.3.4",
+		DefaultValue: "10000",
+		Doc:          "max rows sampled to simulate searching with a proposed index config, which bounds the cost of brute force",
+	}
+	p.IndexSimulationMaxRows.Init(base.mgr)
+
+	p.ExactSearchMaxConcurrency = ParamItem{
+		Key:          "queryNode.exactSearch.maxConcurrency",
+		Version:      "2.3.4",
+		DefaultValue: "1",
+		Doc:          "max concurrent exact searches bypassing index on the node, which compute distances of all rows, 0 means exact search disabled",
+	}
+	p.ExactSearchMaxConcurrency.Init(base.mgr)
+
+	p.ExactSearchMaxRows = ParamItem{
+		Key:          "queryNode.exactSearch.maxRows",
+		Version:      "2.3.4",
+		DefaultValue: "100000",
+		Doc:          "max rows of channel an exact search computes distances of, as the vectors of all the rows are retrieved into memory",
+	}
+	p.ExactSearchMaxRows.Init(base.mgr)
+
+	p.FacetMaxBuckets = ParamItem{
+		Key:          "queryNode.facet.maxBuckets",
+		Version:      "2.3.4",
+		DefaultValue: "64",
+		Doc:          "max buckets of search facet, which is the number of boundaries plus one",
+	}
+	p.FacetMaxBuckets.Init(base.mgr)
+
+	p.FacetMaxCandidateFactor = ParamItem{
+		Key:          "queryNode.facet.maxCandidateFactor",
+		Version:      "2.3.4",
+		DefaultValue: "16",
+		Doc:          "max candidate factor of search facet, candidates of topK * candidate factor are bucketed",
+	}
+	p.FacetMaxCandidateFactor.Init(base.mgr)
+
+	p.SearchParamLogSize = ParamItem{
+		Key:          "queryNode.searchParamLog.sizePerCollection",
+		Version:      "2.3.4",
+		DefaultValue: "32",
+		Doc:          "number of latest searches of each collection whose search params applied by query hook are kept for inspection, 0 means disabled",
+	}
+	p.SearchParamLogSize.Init(base.mgr)
+
+	p.PerPartitionTopKMaxHits = ParamItem{
+		Key:          "queryNode.perPartitionTopK.maxHits",
+		Version:      "2.3.4",
+		DefaultValue: "16384",
+		Doc:          "max hits of each query searched with per partition topK, which is topK times the number of partitions",
+	}
+	p.PerPartitionTopKMaxHits.Init(base.mgr)
+
+	p.LoadDurationLogSize = ParamItem{
+		Key:          "queryNode.loadDurationLog.sizePerCollection",
+		Version:      "2.3.4",
+		DefaultValue: "64",
+		Doc:          "number of latest load operations of each collection whose durations are kept for capacity modeling, 0 means disabled",
+	}
+	p.LoadDurationLogSize.Init(base.mgr)
+
+	p.QueryTimeoutOverrideMax = ParamItem{
+		Key:          "queryNode.queryTimeoutOverride.max",
+		Version:      "2.3.4",
+		DefaultValue: "600000",
+		Doc:          "max timeout in milliseconds of search and query settable for a collection at runtime, overriding queryNode.maxQueryTimeout, 0 disables the overrides",
+	}
+	p.QueryTimeoutOverrideMax.Init(base.mgr)
+
+	p.FilteredSearchMinRecallRatio = ParamItem{
+		Key:          "queryNode.filteredSearch.minRecallRatio",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc: `filtered ANN search falls back to brute force the rows matching the filter if any query got fewer hits
+than the ratio of topK while more rows match the filter, 0 to disable`,
+	}
+	p.FilteredSearchMinRecallRatio.Init(base.mgr)
+
+	p.FilteredSearchFallbackMaxRows = ParamItem{
+		Key:          "queryNode.filteredSearch.fallbackMaxRows",
+		Version:      "2.3.4",
+		DefaultValue: "100000",
+		Doc:          "filtered search falls back to brute force only if the rows matching the filter are not more than it",
+	}
+	p.FilteredSearchFallbackMaxRows.Init(base.mgr)
+
+	p.StreamResumablePageSize = ParamItem{
+		Key:          "queryNode.stream.resumablePageSize",
+		Version:      "2.3.4",
+		DefaultValue: "1000",
+		Doc:          "max rows of each channel retrieved and streamed at a time by resumable stream",
+	}
+	p.StreamResumablePageSize.Init(base.mgr)
+
+	p.StreamCursorTTL = ParamItem{
+		Key:          "queryNode.stream.cursorTTL",
+		Version:      "2.3.4",
+		DefaultValue: "600",
+		Doc:          "seconds the cursor of resumable stream is kept after the stream ends or disconnects, expired if not resumed within it",
+	}
+	p.StreamCursorTTL.Init(base.mgr)
+
+	p.GrowingSegmentMaxPerChannel = ParamItem{
+		Key:          "queryNode.growingSegment.maxPerChannel",
+		Version:      "2.3.4",
+		DefaultValue: "0",
+		Doc: `max growing segments of a channel, loading more growing segments is rejected and the channels exceeding it are
+reported to be flushed early, 0 means no limit`,
+	}
+	p.GrowingSegmentMaxPerChannel.Init(base.mgr)
+
+	p.BatchLoadIndexMaxConcurrency = ParamItem{
+		Key:          "queryNode.batchLoadIndex.maxConcurrency",
+		Version:      "2.3.4",
+		DefaultValue: "4",
+		Doc:          "max segments loading index concurrently in a batch load index request, shared across collections",
+	}
+	p.BatchLoadIndexMaxConcurrency.Init(base.mgr)
+
+	p.GroundTruthCacheCapacity = ParamItem{
+		Key:          "queryNode.groundTruthCache.capacity",
+		Version:      "2.3.4",
+		DefaultValue: "64",
+		Doc:          "max query sets whose exact ground truth is cached per channel, the least recently computed is evicted, 0 disables the cache",
+	}
+	p.GroundTruthCacheCapacity.Init(base.mgr)
+
+	p.NonFiniteVectorPolicy = ParamItem{
+		Key:          "queryNode.nonFiniteVectorPolicy",
+		Version:      "2.3.4",
+		DefaultValue: "reject",
+		Doc: `policy of the query vectors containing NaN or Inf, reject: fail the search with the bad query indexes,
+drop: return empty result for the bad queries, zero: replace the NaN and Inf components with 0`,
+	}
+	p.NonFiniteVectorPolicy.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////